@@ -4,4 +4,8 @@ type ContextKey int
 
 const (
 	AuthUser ContextKey = iota
+	Request
+	// AuthClient holds the *models.Client authenticated with client
+	// credentials
+	AuthClient
 )
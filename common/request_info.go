@@ -0,0 +1,11 @@
+package common
+
+// RequestInfo identifies where a request comes from, for the audit trail. It
+// is stored in the context under Request.
+type RequestInfo struct {
+	IP        string
+	UserAgent string
+	RequestID string
+	// Origin is the one browsers send with cross-origin requests
+	Origin string
+}
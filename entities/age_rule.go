@@ -0,0 +1,25 @@
+package entities
+
+// AnyCountry is the country of age rules applying wherever no rule of its
+// own does
+const AnyCountry = "*"
+
+// AgeRule is the minimum age users of a country must be verified to have to
+// access a category of content, and the methods accepted to verify it
+type AgeRule struct {
+	Country    string   `json:"country" yaml:"country"`
+	Category   string   `json:"category" yaml:"category"`
+	MinimumAge int      `json:"minimum_age" yaml:"minimum_age"`
+	Methods    []string `json:"methods" yaml:"methods"`
+}
+
+// AllowsMethod checks if the rule accepts verifying through method
+func (rule *AgeRule) AllowsMethod(method string) bool {
+	for _, allowed := range rule.Methods {
+		if allowed == method {
+			return true
+		}
+	}
+
+	return false
+}
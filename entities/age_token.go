@@ -0,0 +1,22 @@
+package entities
+
+// AgeTokenRequest asks for a token proving the signed in user's age to the
+// relying party whose client_id is Audience
+type AgeTokenRequest struct {
+	Audience *string `json:"audience"`
+	// AgeOver defaults to the age the user was verified to be over, and can
+	// only be lowered
+	AgeOver *int `json:"age_over"`
+}
+
+// AgeToken is a signed JWT relying parties check against the JWKS
+type AgeToken struct {
+	Token     string `json:"token"`
+	ExpiresAt int64  `json:"expires_at"`
+}
+
+// AgeTokenVerification asks to check an age token issued for the client
+// asking
+type AgeTokenVerification struct {
+	Token *string `json:"token"`
+}
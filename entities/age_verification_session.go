@@ -0,0 +1,15 @@
+package entities
+
+// AgeVerificationSessionRequest starts an age verification session
+type AgeVerificationSessionRequest struct {
+	Method *string `json:"method"`
+	// Country and Category pick the age rule the session must satisfy. The
+	// rule for any country applies when Country is left out.
+	Country  *string `json:"country"`
+	Category *string `json:"category"`
+	// AgeOver defaults to the minimum age of the rule, and can only be raised
+	AgeOver *int `json:"age_over"`
+	// ReturnURL is where the provider sends the user back to once done. It
+	// must be one of the redirect URIs of the client the session is run for.
+	ReturnURL *string `json:"return_url"`
+}
@@ -0,0 +1,43 @@
+package entities
+
+import (
+	"time"
+
+	"verifymy-golang-test/models"
+)
+
+// AuditEventSortKey is the only ordering of audit events, newest first
+const AuditEventSortKey = "created_at:desc"
+
+// AuditEventFilters whitelists the fields audit events can be filtered by.
+// Zero values mean the filter is not applied.
+type AuditEventFilters struct {
+	ActorID     string
+	Action      string
+	TargetType  string
+	TargetID    string
+	IP          string
+	RequestID   string
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+}
+
+type AuditEventQuery struct {
+	Filters AuditEventFilters
+	Page    PageRequest
+}
+
+// CursorFor builds the cursor pointing at event
+func (q AuditEventQuery) CursorFor(event models.AuditEvent) *Cursor {
+	return &Cursor{
+		ID:    event.ID.String(),
+		Sort:  AuditEventSortKey,
+		Value: event.CreatedAt.UTC().Format(time.RFC3339Nano),
+	}
+}
+
+type AuditEventsPage struct {
+	Events []models.AuditEvent
+	Next   *Cursor
+	Total  *int64
+}
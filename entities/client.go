@@ -0,0 +1,10 @@
+package entities
+
+// ClientChanges are the fields of a client being set. Fields left out are
+// kept as they are.
+type ClientChanges struct {
+	Name           *string   `json:"name"`
+	RedirectURIs   *[]string `json:"redirect_uris"`
+	AllowedOrigins *[]string `json:"allowed_origins"`
+	Scopes         *[]string `json:"scopes"`
+}
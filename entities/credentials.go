@@ -3,4 +3,6 @@ package entities
 type Credentials struct {
 	AccessToken string `json:"access_token"`
 	ExpiresAt   int64  `json:"expires_at"`
+	// SudoExpiresAt is set on credentials issued by re-authenticating
+	SudoExpiresAt int64 `json:"sudo_expires_at,omitempty"`
 }
@@ -0,0 +1,61 @@
+package entities
+
+const (
+	EventUserRegistered     = "user.registered"
+	EventUserProfileUpdated = "user.profile_updated"
+	EventUserDeleted        = "user.deleted"
+)
+
+// DomainEvent is something that happened to an aggregate other services may
+// react to. Events are serialized as JSON into the outbox and webhook
+// deliveries, which outlive erasure, so they hold identifiers rather than
+// personal data. Consumers fetch the rest through the API.
+type DomainEvent interface {
+	EventType() string
+	AggregateID() string
+}
+
+// UserRegistered is published when a user signs up or is invited
+type UserRegistered struct {
+	UserID  string `json:"user_id"`
+	Role    string `json:"role"`
+	Invited bool   `json:"invited"`
+}
+
+func (e UserRegistered) EventType() string {
+	return EventUserRegistered
+}
+
+func (e UserRegistered) AggregateID() string {
+	return e.UserID
+}
+
+// UserProfileUpdated is published when the profile of a user changes. It
+// names the fields changed, not their values.
+type UserProfileUpdated struct {
+	UserID string   `json:"user_id"`
+	Fields []string `json:"fields"`
+}
+
+func (e UserProfileUpdated) EventType() string {
+	return EventUserProfileUpdated
+}
+
+func (e UserProfileUpdated) AggregateID() string {
+	return e.UserID
+}
+
+// UserDeleted is published when a user is deleted, and again when they are
+// erased
+type UserDeleted struct {
+	UserID string `json:"user_id"`
+	Erased bool   `json:"erased"`
+}
+
+func (e UserDeleted) EventType() string {
+	return EventUserDeleted
+}
+
+func (e UserDeleted) AggregateID() string {
+	return e.UserID
+}
@@ -50,6 +50,18 @@ func NewInvalidEmailAndOrPasswordError() error {
 	}
 }
 
+type InvalidPasswordError struct {
+	*baseErrors
+}
+
+func NewInvalidPasswordError() error {
+	return &InvalidPasswordError{
+		baseErrors: &baseErrors{
+			Message: "invalid password",
+		},
+	}
+}
+
 type InvalidTokenError struct {
 	*baseErrors
 }
@@ -74,3 +86,129 @@ func NewItemNotFoundError(itemType string, itemId string) error {
 		},
 	}
 }
+
+type InvalidParameterError struct {
+	*baseErrors
+}
+
+func NewInvalidParameterError(parameter string, reason string) error {
+	return &InvalidParameterError{
+		baseErrors: &baseErrors{
+			Message: fmt.Sprintf("invalid %s parameter", parameter),
+			Details: []string{reason},
+		},
+	}
+}
+
+type PreconditionFailedError struct {
+	*baseErrors
+}
+
+func NewPreconditionFailedError() error {
+	return &PreconditionFailedError{
+		baseErrors: &baseErrors{
+			Message: "precondition failed",
+			Details: []string{"resource was modified since it was fetched"},
+		},
+	}
+}
+
+type UnsupportedMediaTypeError struct {
+	*baseErrors
+}
+
+func NewUnsupportedMediaTypeError(mediaType string, supported []string) error {
+	return &UnsupportedMediaTypeError{
+		baseErrors: &baseErrors{
+			Message: fmt.Sprintf("unsupported media type %s", mediaType),
+			Details: supported,
+		},
+	}
+}
+
+type InvalidPatchError struct {
+	*baseErrors
+}
+
+func NewInvalidPatchError(reason string) error {
+	return &InvalidPatchError{
+		baseErrors: &baseErrors{
+			Message: "invalid patch",
+			Details: []string{reason},
+		},
+	}
+}
+
+type ValidationError struct {
+	*baseErrors
+}
+
+func NewValidationError(reason string) error {
+	return &ValidationError{
+		baseErrors: &baseErrors{
+			Message: "validation failed",
+			Details: []string{reason},
+		},
+	}
+}
+
+type InvalidClientError struct {
+	*baseErrors
+}
+
+func NewInvalidClientError() error {
+	return &InvalidClientError{
+		baseErrors: &baseErrors{
+			Message: "invalid client credentials",
+		},
+	}
+}
+
+type RateLimitExceededError struct {
+	*baseErrors
+}
+
+func NewRateLimitExceededError(reason string) error {
+	return &RateLimitExceededError{
+		baseErrors: &baseErrors{
+			Message: "rate limit exceeded",
+			Details: []string{reason},
+		},
+	}
+}
+
+// OAuth error codes, RFC 6749
+const (
+	OAuthErrorInvalidRequest          = "invalid_request"
+	OAuthErrorInvalidClient           = "invalid_client"
+	OAuthErrorInvalidGrant            = "invalid_grant"
+	OAuthErrorUnauthorizedClient      = "unauthorized_client"
+	OAuthErrorUnsupportedGrantType    = "unsupported_grant_type"
+	OAuthErrorUnsupportedResponseType = "unsupported_response_type"
+	OAuthErrorInvalidScope            = "invalid_scope"
+	OAuthErrorAccessDenied            = "access_denied"
+	// Errors of resources taking access tokens, RFC 6750
+	OAuthErrorInvalidToken      = "invalid_token"
+	OAuthErrorInsufficientScope = "insufficient_scope"
+)
+
+// OAuthError is an error of the OAuth endpoints, shaped as RFC 6749 defines
+// instead of like the other errors, since OAuth clients expect it
+type OAuthError struct {
+	Code        string `json:"error"`
+	Description string `json:"error_description,omitempty"`
+	// RedirectURI sends the user back to the client with the error, once the
+	// client and its redirect URI are known to be valid
+	RedirectURI string `json:"redirect_uri,omitempty"`
+}
+
+func (e *OAuthError) Error() string {
+	return e.Code + ": " + e.Description
+}
+
+func NewOAuthError(code string, description string) error {
+	return &OAuthError{
+		Code:        code,
+		Description: description,
+	}
+}
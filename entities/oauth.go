@@ -0,0 +1,80 @@
+package entities
+
+// OAuthAuthorizationRequest is a client asking to act on behalf of the signed
+// in user, as sent to /oauth/authorize. Only the authorization code flow with
+// PKCE is supported.
+type OAuthAuthorizationRequest struct {
+	ResponseType        string `json:"response_type"`
+	ClientID            string `json:"client_id"`
+	RedirectURI         string `json:"redirect_uri"`
+	Scope               string `json:"scope"`
+	State               string `json:"state"`
+	CodeChallenge       string `json:"code_challenge"`
+	CodeChallengeMethod string `json:"code_challenge_method"`
+	// Nonce is sent by OpenID Connect clients, to tie the ID token to the
+	// session of the user with them
+	Nonce string `json:"nonce"`
+}
+
+// OAuthAuthorizationDecision is the answer of the user on the consent screen
+type OAuthAuthorizationDecision struct {
+	OAuthAuthorizationRequest
+	Approved *bool `json:"approved"`
+}
+
+// OAuthAuthorizationPrompt is what the consent screen shows the user
+type OAuthAuthorizationPrompt struct {
+	ClientID   string   `json:"client_id"`
+	ClientName string   `json:"client_name"`
+	Scopes     []string `json:"scopes"`
+	// Consented tells the user already granted the client these scopes, so
+	// the consent screen can be skipped
+	Consented bool `json:"consented"`
+}
+
+// OAuthAuthorizationResult is where to send the user back to the client,
+// with either a code or an error
+type OAuthAuthorizationResult struct {
+	RedirectURI string `json:"redirect_uri"`
+}
+
+// OAuthClientCredentials authenticate a client on the OAuth endpoints,
+// through HTTP Basic or the request body
+type OAuthClientCredentials struct {
+	ClientID     string
+	ClientSecret string
+}
+
+// OAuthTokenRequest is the form sent to /oauth/token. Which fields are
+// required depends on the grant type.
+type OAuthTokenRequest struct {
+	GrantType    string
+	Code         string
+	RedirectURI  string
+	CodeVerifier string
+	RefreshToken string
+	Scope        string
+}
+
+type OAuthToken struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	Scope        string `json:"scope"`
+	// IDToken is issued along with tokens granted the openid scope
+	IDToken string `json:"id_token,omitempty"`
+}
+
+// OAuthTokenIntrospection tells a client whether one of its tokens is still
+// active, RFC 7662. Only Active is set for inactive tokens.
+type OAuthTokenIntrospection struct {
+	Active    bool   `json:"active"`
+	Scope     string `json:"scope,omitempty"`
+	ClientID  string `json:"client_id,omitempty"`
+	TokenType string `json:"token_type,omitempty"`
+	ExpiresAt int64  `json:"exp,omitempty"`
+	IssuedAt  int64  `json:"iat,omitempty"`
+	Subject   string `json:"sub,omitempty"`
+	Issuer    string `json:"iss,omitempty"`
+}
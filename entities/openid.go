@@ -0,0 +1,40 @@
+package entities
+
+// OpenIDConfiguration is the discovery document of OpenID Connect, which
+// client libraries configure themselves from
+type OpenIDConfiguration struct {
+	Issuer                            string   `json:"issuer"`
+	AuthorizationEndpoint             string   `json:"authorization_endpoint"`
+	TokenEndpoint                     string   `json:"token_endpoint"`
+	UserInfoEndpoint                  string   `json:"userinfo_endpoint"`
+	JWKSURI                           string   `json:"jwks_uri"`
+	RevocationEndpoint                string   `json:"revocation_endpoint"`
+	IntrospectionEndpoint             string   `json:"introspection_endpoint"`
+	EndSessionEndpoint                string   `json:"end_session_endpoint"`
+	ScopesSupported                   []string `json:"scopes_supported"`
+	ResponseTypesSupported            []string `json:"response_types_supported"`
+	GrantTypesSupported               []string `json:"grant_types_supported"`
+	SubjectTypesSupported             []string `json:"subject_types_supported"`
+	IDTokenSigningAlgValuesSupported  []string `json:"id_token_signing_alg_values_supported"`
+	TokenEndpointAuthMethodsSupported []string `json:"token_endpoint_auth_methods_supported"`
+	CodeChallengeMethodsSupported     []string `json:"code_challenge_methods_supported"`
+	ClaimsSupported                   []string `json:"claims_supported"`
+}
+
+// OpenIDUserInfo holds the claims about the user an access token was granted
+// the scopes of. Only Subject is always set.
+type OpenIDUserInfo struct {
+	Subject   string `json:"sub"`
+	Name      string `json:"name,omitempty"`
+	Birthdate string `json:"birthdate,omitempty"`
+	Email     string `json:"email,omitempty"`
+}
+
+// OpenIDLogoutRequest is a client signing the user out, as sent to
+// /oauth/logout
+type OpenIDLogoutRequest struct {
+	IDTokenHint           string
+	ClientID              string
+	PostLogoutRedirectURI string
+	State                 string
+}
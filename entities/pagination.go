@@ -0,0 +1,54 @@
+package entities
+
+import (
+	"encoding/base64"
+	"encoding/json"
+
+	"verifymy-golang-test/models"
+)
+
+const (
+	DefaultPageSize = 10
+	MaxPageSize     = 100
+)
+
+// Cursor points to the last row of a page. It is handed to clients as an
+// opaque string so the ordering key can change without breaking them.
+type Cursor struct {
+	ID       string `json:"id"`
+	Sort     string `json:"sort,omitempty"`
+	Value    string `json:"value,omitempty"`
+	Backward bool   `json:"backward,omitempty"`
+}
+
+func (c *Cursor) Encode() string {
+	payload, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(payload)
+}
+
+func DecodeCursor(value string) (*Cursor, error) {
+	payload, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, NewInvalidParameterError("cursor", "malformed cursor")
+	}
+
+	var cursor Cursor
+	if err := json.Unmarshal(payload, &cursor); err != nil || cursor.ID == "" {
+		return nil, NewInvalidParameterError("cursor", "malformed cursor")
+	}
+
+	return &cursor, nil
+}
+
+type PageRequest struct {
+	Limit     int
+	Cursor    *Cursor
+	WithTotal bool
+}
+
+type UsersPage struct {
+	Users    []models.User
+	Next     *Cursor
+	Previous *Cursor
+	Total    *int64
+}
@@ -0,0 +1,8 @@
+package entities
+
+// ParentalConsentDecision is the answer of a guardian to the consent request
+// they were sent
+type ParentalConsentDecision struct {
+	Token    string `json:"token"`
+	Approved *bool  `json:"approved"`
+}
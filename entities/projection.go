@@ -0,0 +1,22 @@
+package entities
+
+// UserFields whitelists the user fields clients can ask for through sparse
+// fieldsets.
+var UserFields = []interface{}{
+	"id",
+	"name",
+	"date_of_birth",
+	"email",
+	"address",
+	"role",
+	"status",
+	"created_at",
+	"updated_at",
+}
+
+// Projection describes how a response should be shaped: which fields to
+// keep (all of them when empty) and which related resources to embed.
+type Projection struct {
+	Fields   []string
+	Includes []string
+}
@@ -0,0 +1,75 @@
+package entities
+
+import (
+	"fmt"
+	"time"
+
+	"verifymy-golang-test/models"
+)
+
+type SortDirection string
+
+const (
+	SortAscending  SortDirection = "asc"
+	SortDescending SortDirection = "desc"
+
+	DefaultUserSortField = "id"
+)
+
+// UserSortFields whitelists the fields users can be sorted by.
+var UserSortFields = []interface{}{
+	"id",
+	"name",
+	"email",
+	"date_of_birth",
+	"created_at",
+}
+
+// UserFilters whitelists the fields users can be filtered by. Zero values
+// mean the filter is not applied. Deleted selects soft deleted users instead
+// of active ones.
+type UserFilters struct {
+	Deleted         bool
+	EmailPrefix     string
+	Name            string
+	DateOfBirthFrom *models.Date
+	DateOfBirthTo   *models.Date
+	CreatedFrom     *time.Time
+	CreatedTo       *time.Time
+}
+
+type UserQuery struct {
+	Fields        []string
+	Filters       UserFilters
+	SortField     string
+	SortDirection SortDirection
+	Page          PageRequest
+}
+
+// SortKey identifies the ordering a cursor was issued for, so a cursor
+// cannot be replayed against a different sort.
+func (q UserQuery) SortKey() string {
+	return fmt.Sprintf("%s:%s", q.SortField, q.SortDirection)
+}
+
+// CursorFor builds the cursor pointing at user under the query ordering.
+func (q UserQuery) CursorFor(user models.User, backward bool) *Cursor {
+	cursor := &Cursor{
+		ID:       user.ID.String(),
+		Sort:     q.SortKey(),
+		Backward: backward,
+	}
+
+	switch q.SortField {
+	case "name":
+		cursor.Value = user.Name
+	case "email":
+		cursor.Value = user.Email
+	case "date_of_birth":
+		cursor.Value = time.Time(user.DateOfBirth).Format(models.DateFormat)
+	case "created_at":
+		cursor.Value = user.CreatedAt.UTC().Format(time.RFC3339Nano)
+	}
+
+	return cursor
+}
@@ -0,0 +1,16 @@
+package entities
+
+// WebhookEventTypes are the domain events webhooks can subscribe to
+var WebhookEventTypes = []string{
+	EventUserRegistered,
+	EventUserProfileUpdated,
+	EventUserDeleted,
+}
+
+// WebhookSubscriptionChanges are the fields of a webhook subscription being
+// set. Fields left out are kept as they are.
+type WebhookSubscriptionChanges struct {
+	URL        *string   `json:"url"`
+	EventTypes *[]string `json:"event_types"`
+	Secret     *string   `json:"secret"`
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"verifymy-golang-test/entities"
+	"verifymy-golang-test/services"
+)
+
+type acceptInvitationHandler struct {
+	authService services.AuthService
+}
+
+func NewAcceptInvitationHandler(
+	authService services.AuthService,
+) Handler {
+	return &acceptInvitationHandler{
+		authService: authService,
+	}
+}
+
+func (h *acceptInvitationHandler) Method() []string {
+	return []string{http.MethodPost}
+}
+
+func (h *acceptInvitationHandler) Route() string {
+	return "/auth/invitations/accept"
+}
+
+func (h *acceptInvitationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var payload map[string]string
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		err = entities.NewError("Invalid JSON", []string{err.Error()})
+
+		jsonPayload, _ := json.Marshal(err)
+		w.Write(jsonPayload)
+		return
+	}
+
+	if payload["password"] == "" {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		err := entities.NewError("password is required", []string{})
+
+		jsonPayload, _ := json.Marshal(err)
+		w.Write(jsonPayload)
+		return
+	}
+
+	credentials, err := h.authService.AcceptInvitation(
+		r.Context(), payload["token"], payload["password"],
+	)
+	if err != nil {
+		if _, ok := err.(*entities.InvalidTokenError); ok {
+			w.WriteHeader(http.StatusBadRequest)
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+			err = entities.NewUnexpectedError(err)
+		}
+
+		jsonPayload, _ := json.Marshal(err)
+		w.Write(jsonPayload)
+		return
+	}
+
+	jsonPayload, _ := json.Marshal(credentials)
+	w.Write(jsonPayload)
+}
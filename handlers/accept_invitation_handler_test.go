@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/suite"
+
+	"verifymy-golang-test/entities"
+	mock_services "verifymy-golang-test/mocks/services"
+)
+
+type acceptInvitationHandlerTestSuite struct {
+	suite.Suite
+	ctrl            *gomock.Controller
+	authServiceMock *mock_services.MockAuthService
+	handler         Handler
+}
+
+func TestAcceptInvitationHandlerTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(acceptInvitationHandlerTestSuite))
+}
+
+func (s *acceptInvitationHandlerTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.authServiceMock = mock_services.NewMockAuthService(s.ctrl)
+	s.handler = NewAcceptInvitationHandler(s.authServiceMock)
+}
+
+func (s *acceptInvitationHandlerTestSuite) TestMethod() {
+	s.Equal([]string{"POST"}, s.handler.Method())
+}
+
+func (s *acceptInvitationHandlerTestSuite) TestRoute() {
+	s.Equal("/auth/invitations/accept", s.handler.Route())
+}
+
+func (s *acceptInvitationHandlerTestSuite) TestServeHTTP() {
+	tests := []struct {
+		description            string
+		payload                string
+		skipAcceptInvitation   bool
+		acceptInvitationResult *entities.Credentials
+		acceptInvitationError  error
+		expectedStatusCode     int
+		expectedPayload        map[string]interface{}
+	}{
+		{
+			description: "Success",
+			payload:     `{"token": "invitation-token", "password": "my-password"}`,
+			acceptInvitationResult: &entities.Credentials{
+				AccessToken: "access-token",
+				ExpiresAt:   1000,
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedPayload: map[string]interface{}{
+				"access_token": "access-token",
+				"expires_at":   float64(1000),
+			},
+		},
+		{
+			description:          "Invalid JSON",
+			payload:              `{"token": "invitation-token"`,
+			skipAcceptInvitation: true,
+			expectedStatusCode:   http.StatusUnprocessableEntity,
+			expectedPayload: map[string]interface{}{
+				"message": "Invalid JSON",
+				"details": []interface{}{"unexpected EOF"},
+			},
+		},
+		{
+			description:          "Missing password",
+			payload:              `{"token": "invitation-token"}`,
+			skipAcceptInvitation: true,
+			expectedStatusCode:   http.StatusUnprocessableEntity,
+			expectedPayload: map[string]interface{}{
+				"message": "password is required",
+				"details": []interface{}{},
+			},
+		},
+		{
+			description:           "Invalid token",
+			payload:               `{"token": "invitation-token", "password": "my-password"}`,
+			acceptInvitationError: entities.NewInvalidTokenError(),
+			expectedStatusCode:    http.StatusBadRequest,
+			expectedPayload: map[string]interface{}{
+				"message": "invalid token",
+				"details": nil,
+			},
+		},
+		{
+			description:           "Unexpected error",
+			payload:               `{"token": "invitation-token", "password": "my-password"}`,
+			acceptInvitationError: errors.New("error accepting invitation"),
+			expectedStatusCode:    http.StatusInternalServerError,
+			expectedPayload: map[string]interface{}{
+				"message": "unexpected error",
+				"details": []interface{}{"error accepting invitation"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			request := httptest.NewRequest(
+				http.MethodPost, "/auth/invitations/accept", strings.NewReader(test.payload),
+			)
+			response := httptest.NewRecorder()
+
+			if !test.skipAcceptInvitation {
+				s.authServiceMock.EXPECT().AcceptInvitation(
+					request.Context(), "invitation-token", "my-password",
+				).Return(test.acceptInvitationResult, test.acceptInvitationError)
+			}
+
+			s.handler.ServeHTTP(response, request)
+
+			var jsonPayload map[string]interface{}
+			_ = json.Unmarshal(response.Body.Bytes(), &jsonPayload)
+
+			s.Equal(test.expectedPayload, jsonPayload)
+			s.Equal(test.expectedStatusCode, response.Code)
+		})
+	}
+}
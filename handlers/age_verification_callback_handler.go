@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"verifymy-golang-test/entities"
+	"verifymy-golang-test/providers"
+	"verifymy-golang-test/services"
+)
+
+const maxAgeVerificationCallbackSize = 1 << 20
+
+type ageVerificationCallbackHandler struct {
+	ageVerificationSessionService services.AgeVerificationSessionService
+}
+
+func NewAgeVerificationCallbackHandler(
+	ageVerificationSessionService services.AgeVerificationSessionService,
+) Handler {
+	return &ageVerificationCallbackHandler{
+		ageVerificationSessionService: ageVerificationSessionService,
+	}
+}
+
+func (h *ageVerificationCallbackHandler) Method() []string {
+	return []string{http.MethodPost}
+}
+
+func (h *ageVerificationCallbackHandler) Route() string {
+	return "/age_verifications/callbacks/{provider}"
+}
+
+// ServeHTTP is called by providers rather than users, who are trusted by how
+// each provider authenticates its callbacks
+func (h *ageVerificationCallbackHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxAgeVerificationCallbackSize))
+	if err == nil {
+		_, err = h.ageVerificationSessionService.HandleCallback(
+			r.Context(), mux.Vars(r)["provider"], r.Header, body,
+		)
+	}
+	if err != nil {
+		if errors.Is(err, providers.ErrInvalidAgeVerificationCallback) {
+			w.WriteHeader(http.StatusUnauthorized)
+			err = entities.NewError(err.Error(), []string{})
+		} else if errors.Is(err, providers.ErrUnknownAgeVerificationSession) {
+			w.WriteHeader(http.StatusNotFound)
+			err = entities.NewError(err.Error(), []string{})
+		} else if _, ok := err.(*entities.ItemNotFoundError); ok {
+			w.WriteHeader(http.StatusNotFound)
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+			err = entities.NewUnexpectedError(err)
+		}
+
+		jsonPayload, _ := json.Marshal(err)
+		w.Write(jsonPayload)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/suite"
+
+	"verifymy-golang-test/entities"
+	mock_services "verifymy-golang-test/mocks/services"
+	"verifymy-golang-test/models"
+	"verifymy-golang-test/providers"
+)
+
+type ageVerificationCallbackHandlerTestSuite struct {
+	suite.Suite
+	ctrl                              *gomock.Controller
+	ageVerificationSessionServiceMock *mock_services.MockAgeVerificationSessionService
+	handler                           Handler
+}
+
+func TestAgeVerificationCallbackHandlerTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(ageVerificationCallbackHandlerTestSuite))
+}
+
+func (s *ageVerificationCallbackHandlerTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.ageVerificationSessionServiceMock = mock_services.NewMockAgeVerificationSessionService(s.ctrl)
+	s.handler = NewAgeVerificationCallbackHandler(s.ageVerificationSessionServiceMock)
+}
+
+func (s *ageVerificationCallbackHandlerTestSuite) TestMethod() {
+	s.Equal([]string{"POST"}, s.handler.Method())
+}
+
+func (s *ageVerificationCallbackHandlerTestSuite) TestRoute() {
+	s.Equal("/age_verifications/callbacks/{provider}", s.handler.Route())
+}
+
+func (s *ageVerificationCallbackHandlerTestSuite) TestServeHTTP() {
+	body := `{"reference":"sim_1"}`
+
+	tests := []struct {
+		description        string
+		callbackError      error
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			description:        "Success",
+			expectedStatusCode: http.StatusNoContent,
+			expectedBody:       "",
+		},
+		{
+			description:        "Invalid callback",
+			callbackError:      providers.ErrInvalidAgeVerificationCallback,
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedBody:       `{"message":"invalid age verification callback","details":[]}`,
+		},
+		{
+			description:        "Unknown session at the provider",
+			callbackError:      providers.ErrUnknownAgeVerificationSession,
+			expectedStatusCode: http.StatusNotFound,
+			expectedBody:       `{"message":"unknown age verification session","details":[]}`,
+		},
+		{
+			description:        "Unknown provider",
+			callbackError:      entities.NewItemNotFoundError("Age verification provider", "simulator"),
+			expectedStatusCode: http.StatusNotFound,
+			expectedBody:       `{"message":"Age verification provider not found","details":["simulator"]}`,
+		},
+		{
+			description:        "Unexpected error",
+			callbackError:      errors.New("database error"),
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedBody:       `{"message":"unexpected error","details":["database error"]}`,
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			request := httptest.NewRequest(
+				http.MethodPost, "/age_verifications/callbacks/simulator", strings.NewReader(body),
+			)
+			request.Header.Set("X-Simulator-Signature", "abc")
+			request = mux.SetURLVars(request, map[string]string{"provider": "simulator"})
+			response := httptest.NewRecorder()
+
+			s.ageVerificationSessionServiceMock.EXPECT().HandleCallback(
+				request.Context(), "simulator", request.Header, []byte(body),
+			).Return(&models.AgeVerificationSession{}, test.callbackError)
+
+			s.handler.ServeHTTP(response, request)
+
+			s.Equal(test.expectedStatusCode, response.Code)
+			s.Equal(test.expectedBody, response.Body.String())
+		})
+	}
+}
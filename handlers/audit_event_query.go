@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"verifymy-golang-test/entities"
+)
+
+func parseAuditEventQuery(r *http.Request) (entities.AuditEventQuery, error) {
+	query := r.URL.Query()
+	auditEventQuery := entities.AuditEventQuery{
+		Filters: entities.AuditEventFilters{
+			ActorID:    query.Get("actor_id"),
+			Action:     query.Get("action"),
+			TargetType: query.Get("target_type"),
+			TargetID:   query.Get("target_id"),
+			IP:         query.Get("ip"),
+			RequestID:  query.Get("request_id"),
+		},
+	}
+
+	timeFilters := []struct {
+		parameter string
+		target    **time.Time
+	}{
+		{"created_from", &auditEventQuery.Filters.CreatedFrom},
+		{"created_to", &auditEventQuery.Filters.CreatedTo},
+	}
+	for _, filter := range timeFilters {
+		parameter, target := filter.parameter, filter.target
+		if value := query.Get(parameter); value != "" {
+			createdAt, err := parseTimeOrDate(value, parameter == "created_to")
+			if err != nil {
+				return auditEventQuery, entities.NewInvalidParameterError(
+					parameter, "must be an RFC 3339 timestamp or a date formatted as YYYY-MM-DD",
+				)
+			}
+
+			*target = &createdAt
+		}
+	}
+
+	page, err := parsePageRequest(r)
+	if err != nil {
+		return auditEventQuery, err
+	}
+
+	if page.Cursor != nil &&
+		(page.Cursor.Sort != entities.AuditEventSortKey || page.Cursor.Backward) {
+		return auditEventQuery, entities.NewInvalidParameterError(
+			"cursor", "cursor does not match the requested sort",
+		)
+	}
+
+	auditEventQuery.Page = page
+	return auditEventQuery, nil
+}
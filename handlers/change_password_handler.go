@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"verifymy-golang-test/entities"
+	"verifymy-golang-test/services"
+)
+
+type changePasswordHandler struct {
+	authService services.AuthService
+}
+
+func NewChangePasswordHandler(
+	authService services.AuthService,
+) Handler {
+	return &changePasswordHandler{
+		authService: authService,
+	}
+}
+
+func (h *changePasswordHandler) Method() []string {
+	return []string{http.MethodPost}
+}
+
+func (h *changePasswordHandler) Route() string {
+	return "/profile/password"
+}
+
+func (h *changePasswordHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var payload map[string]string
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		err = entities.NewError("Invalid JSON", []string{err.Error()})
+
+		jsonPayload, _ := json.Marshal(err)
+		w.Write(jsonPayload)
+		return
+	}
+
+	for _, field := range []string{"current_password", "new_password"} {
+		if payload[field] == "" {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			err := entities.NewError(field+" is required", []string{})
+
+			jsonPayload, _ := json.Marshal(err)
+			w.Write(jsonPayload)
+			return
+		}
+	}
+
+	credentials, err := h.authService.ChangePassword(
+		r.Context(), payload["current_password"], payload["new_password"],
+	)
+	if err != nil {
+		if _, ok := err.(*entities.InvalidPasswordError); ok {
+			w.WriteHeader(http.StatusBadRequest)
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+			err = entities.NewUnexpectedError(err)
+		}
+
+		jsonPayload, _ := json.Marshal(err)
+		w.Write(jsonPayload)
+		return
+	}
+
+	jsonPayload, _ := json.Marshal(credentials)
+	w.Write(jsonPayload)
+}
@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/suite"
+
+	"verifymy-golang-test/entities"
+	mock_services "verifymy-golang-test/mocks/services"
+)
+
+type changePasswordHandlerTestSuite struct {
+	suite.Suite
+	ctrl            *gomock.Controller
+	authServiceMock *mock_services.MockAuthService
+	handler         Handler
+}
+
+func TestChangePasswordHandlerTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(changePasswordHandlerTestSuite))
+}
+
+func (s *changePasswordHandlerTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.authServiceMock = mock_services.NewMockAuthService(s.ctrl)
+	s.handler = NewChangePasswordHandler(s.authServiceMock)
+}
+
+func (s *changePasswordHandlerTestSuite) TestMethod() {
+	s.Equal([]string{"POST"}, s.handler.Method())
+}
+
+func (s *changePasswordHandlerTestSuite) TestRoute() {
+	s.Equal("/profile/password", s.handler.Route())
+}
+
+func (s *changePasswordHandlerTestSuite) TestServeHTTP() {
+	tests := []struct {
+		description          string
+		payload              string
+		skipChangePassword   bool
+		changePasswordResult *entities.Credentials
+		changePasswordError  error
+		expectedStatusCode   int
+		expectedPayload      map[string]interface{}
+	}{
+		{
+			description: "Success",
+			payload:     `{"current_password": "old-password", "new_password": "new-password"}`,
+			changePasswordResult: &entities.Credentials{
+				AccessToken: "access-token",
+				ExpiresAt:   1000,
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedPayload: map[string]interface{}{
+				"access_token": "access-token",
+				"expires_at":   float64(1000),
+			},
+		},
+		{
+			description:        "Invalid JSON",
+			payload:            `{"current_password": "old-password"`,
+			skipChangePassword: true,
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			expectedPayload: map[string]interface{}{
+				"message": "Invalid JSON",
+				"details": []interface{}{"unexpected EOF"},
+			},
+		},
+		{
+			description:        "Missing current password",
+			payload:            `{"new_password": "new-password"}`,
+			skipChangePassword: true,
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			expectedPayload: map[string]interface{}{
+				"message": "current_password is required",
+				"details": []interface{}{},
+			},
+		},
+		{
+			description:        "Missing new password",
+			payload:            `{"current_password": "old-password"}`,
+			skipChangePassword: true,
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			expectedPayload: map[string]interface{}{
+				"message": "new_password is required",
+				"details": []interface{}{},
+			},
+		},
+		{
+			description:         "Wrong current password",
+			payload:             `{"current_password": "old-password", "new_password": "new-password"}`,
+			changePasswordError: entities.NewInvalidPasswordError(),
+			expectedStatusCode:  http.StatusBadRequest,
+			expectedPayload: map[string]interface{}{
+				"message": "invalid password",
+				"details": nil,
+			},
+		},
+		{
+			description:         "Unexpected error",
+			payload:             `{"current_password": "old-password", "new_password": "new-password"}`,
+			changePasswordError: errors.New("error changing password"),
+			expectedStatusCode:  http.StatusInternalServerError,
+			expectedPayload: map[string]interface{}{
+				"message": "unexpected error",
+				"details": []interface{}{"error changing password"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			request := httptest.NewRequest(
+				http.MethodPost, "/profile/password", strings.NewReader(test.payload),
+			)
+			response := httptest.NewRecorder()
+
+			if !test.skipChangePassword {
+				s.authServiceMock.EXPECT().ChangePassword(
+					request.Context(), "old-password", "new-password",
+				).Return(test.changePasswordResult, test.changePasswordError)
+			}
+
+			s.handler.ServeHTTP(response, request)
+
+			var jsonPayload map[string]interface{}
+			_ = json.Unmarshal(response.Body.Bytes(), &jsonPayload)
+
+			s.Equal(test.expectedPayload, jsonPayload)
+			s.Equal(test.expectedStatusCode, response.Code)
+		})
+	}
+}
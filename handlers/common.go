@@ -1,9 +1,41 @@
 package handlers
 
-import "net/http"
+import (
+	"net/http"
+
+	"verifymy-golang-test/models"
+)
 
 type Handler interface {
 	http.Handler
 	Route() string
 	Method() []string
 }
+
+// AuthorizedHandler is a Handler restricted to users holding a permission
+type AuthorizedHandler interface {
+	Handler
+	Permission() models.Permission
+}
+
+// SudoHandler is a Handler for sensitive operations, which may require the
+// user to have re-authenticated recently, see services.AuthService.Sudo
+type SudoHandler interface {
+	Handler
+	RequiresSudo() bool
+}
+
+// ClientHandler is a Handler for relying parties, authenticated with their
+// client credentials and restricted to clients granted a scope
+type ClientHandler interface {
+	Handler
+	Scope() string
+}
+
+// PendingConsentHandler is a Handler users can still use while their
+// guardian has yet to consent to them signing up, see
+// services.ParentalConsentService
+type PendingConsentHandler interface {
+	Handler
+	AllowsPendingConsent() bool
+}
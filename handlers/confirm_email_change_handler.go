@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"verifymy-golang-test/entities"
+	"verifymy-golang-test/services"
+)
+
+type confirmEmailChangeHandler struct {
+	authService services.AuthService
+}
+
+func NewConfirmEmailChangeHandler(
+	authService services.AuthService,
+) Handler {
+	return &confirmEmailChangeHandler{
+		authService: authService,
+	}
+}
+
+func (h *confirmEmailChangeHandler) Method() []string {
+	return []string{http.MethodPost}
+}
+
+func (h *confirmEmailChangeHandler) Route() string {
+	return "/auth/email/confirm"
+}
+
+func (h *confirmEmailChangeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var payload map[string]string
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		err = entities.NewError("Invalid JSON", []string{err.Error()})
+
+		jsonPayload, _ := json.Marshal(err)
+		w.Write(jsonPayload)
+		return
+	}
+
+	credentials, err := h.authService.ConfirmEmailChange(r.Context(), payload["token"])
+	if err != nil {
+		if _, ok := err.(*entities.InvalidTokenError); ok {
+			w.WriteHeader(http.StatusBadRequest)
+		} else if _, ok := err.(*entities.EmailAlreadyInUseError); ok {
+			w.WriteHeader(http.StatusForbidden)
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+			err = entities.NewUnexpectedError(err)
+		}
+
+		jsonPayload, _ := json.Marshal(err)
+		w.Write(jsonPayload)
+		return
+	}
+
+	jsonPayload, _ := json.Marshal(credentials)
+	w.Write(jsonPayload)
+}
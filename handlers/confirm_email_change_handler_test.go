@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/suite"
+
+	"verifymy-golang-test/entities"
+	mock_services "verifymy-golang-test/mocks/services"
+)
+
+type confirmEmailChangeHandlerTestSuite struct {
+	suite.Suite
+	ctrl            *gomock.Controller
+	authServiceMock *mock_services.MockAuthService
+	handler         Handler
+}
+
+func TestConfirmEmailChangeHandlerTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(confirmEmailChangeHandlerTestSuite))
+}
+
+func (s *confirmEmailChangeHandlerTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.authServiceMock = mock_services.NewMockAuthService(s.ctrl)
+	s.handler = NewConfirmEmailChangeHandler(s.authServiceMock)
+}
+
+func (s *confirmEmailChangeHandlerTestSuite) TestMethod() {
+	s.Equal([]string{"POST"}, s.handler.Method())
+}
+
+func (s *confirmEmailChangeHandlerTestSuite) TestRoute() {
+	s.Equal("/auth/email/confirm", s.handler.Route())
+}
+
+func (s *confirmEmailChangeHandlerTestSuite) TestServeHTTP() {
+	tests := []struct {
+		description        string
+		payload            string
+		skipConfirm        bool
+		confirmResult      *entities.Credentials
+		confirmError       error
+		expectedStatusCode int
+		expectedPayload    map[string]interface{}
+	}{
+		{
+			description: "Success",
+			payload:     `{"token": "email-change-token"}`,
+			confirmResult: &entities.Credentials{
+				AccessToken: "access-token",
+				ExpiresAt:   1000,
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedPayload: map[string]interface{}{
+				"access_token": "access-token",
+				"expires_at":   float64(1000),
+			},
+		},
+		{
+			description:        "Invalid JSON",
+			payload:            `{"token": "email-change-token"`,
+			skipConfirm:        true,
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			expectedPayload: map[string]interface{}{
+				"message": "Invalid JSON",
+				"details": []interface{}{"unexpected EOF"},
+			},
+		},
+		{
+			description:        "Invalid token",
+			payload:            `{"token": "email-change-token"}`,
+			confirmError:       entities.NewInvalidTokenError(),
+			expectedStatusCode: http.StatusBadRequest,
+			expectedPayload: map[string]interface{}{
+				"message": "invalid token",
+				"details": nil,
+			},
+		},
+		{
+			description:        "Email already in use",
+			payload:            `{"token": "email-change-token"}`,
+			confirmError:       entities.NewEmailAlreadyInUseError("spidey@nyork.co"),
+			expectedStatusCode: http.StatusForbidden,
+			expectedPayload: map[string]interface{}{
+				"message": "e-mail is already in use",
+				"details": []interface{}{"spidey@nyork.co"},
+			},
+		},
+		{
+			description:        "Unexpected error",
+			payload:            `{"token": "email-change-token"}`,
+			confirmError:       errors.New("error confirming email change"),
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedPayload: map[string]interface{}{
+				"message": "unexpected error",
+				"details": []interface{}{"error confirming email change"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			request := httptest.NewRequest(
+				http.MethodPost, "/auth/email/confirm", strings.NewReader(test.payload),
+			)
+			response := httptest.NewRecorder()
+
+			if !test.skipConfirm {
+				s.authServiceMock.EXPECT().ConfirmEmailChange(
+					request.Context(), "email-change-token",
+				).Return(test.confirmResult, test.confirmError)
+			}
+
+			s.handler.ServeHTTP(response, request)
+
+			var jsonPayload map[string]interface{}
+			_ = json.Unmarshal(response.Body.Bytes(), &jsonPayload)
+
+			s.Equal(test.expectedPayload, jsonPayload)
+			s.Equal(test.expectedStatusCode, response.Code)
+		})
+	}
+}
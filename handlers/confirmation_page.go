@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"html/template"
+	"net/http"
+)
+
+// confirmationPage is what links e-mailed to users open: a page asking them
+// to confirm before the token of the link is used, since opening a link
+// should not act on its own. Their answer is posted as JSON to the path of
+// the page, where the handler taking the token is.
+type confirmationPage struct {
+	Title   string
+	Message string
+	// Done is shown once the answer is taken
+	Done string
+	// Password asks for a password along with the answer
+	Password bool
+	Actions  []confirmationAction
+}
+
+// confirmationAction is a button of a confirmationPage, posting the token
+// along with Fields
+type confirmationAction struct {
+	Label  string                 `json:"label"`
+	Fields map[string]interface{} `json:"fields"`
+}
+
+var confirmationPageTemplate = template.Must(template.New("confirmation").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+</head>
+<body>
+<main>
+<h1>{{.Title}}</h1>
+<p id="message">{{.Message}}</p>
+{{if .Actions}}<form id="answer">
+{{if .Password}}<p><label>Password <input type="password" name="password" autocomplete="new-password" required></label></p>
+{{end}}{{range $index, $action := .Actions}}<button type="submit" value="{{$index}}">{{$action.Label}}</button>
+{{end}}</form>
+<script>
+const token = {{.Token}};
+const actions = {{.Actions}};
+document.getElementById("answer").addEventListener("submit", async (event) => {
+	event.preventDefault();
+	const form = event.target;
+	const body = Object.assign({token: token}, actions[Number(event.submitter.value)].fields);
+	if (form.password) {
+		body.password = form.password.value;
+	}
+
+	const message = document.getElementById("message");
+	const response = await fetch(window.location.pathname, {
+		method: "POST",
+		headers: {"Content-Type": "application/json"},
+		body: JSON.stringify(body),
+	});
+	if (response.ok) {
+		form.remove();
+		message.textContent = {{.Done}};
+		return;
+	}
+
+	const error = await response.json().catch(() => ({}));
+	message.textContent = error.message || "Something went wrong, please try again.";
+});
+</script>
+{{end}}</main>
+</body>
+</html>
+`))
+
+// renderConfirmationPage renders page for the token in the query string of
+// the request. Links missing it get a page without actions.
+func renderConfirmationPage(w http.ResponseWriter, r *http.Request, page confirmationPage) {
+	token := r.URL.Query().Get("token")
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	// The token is in the URL of the page
+	w.Header().Set("Referrer-Policy", "no-referrer")
+	w.Header().Set("X-Frame-Options", "DENY")
+
+	status := http.StatusOK
+	if token == "" {
+		status = http.StatusBadRequest
+		page.Message = "This link is incomplete. Open the link you were sent again."
+		page.Actions = nil
+	}
+	w.WriteHeader(status)
+
+	confirmationPageTemplate.Execute(w, struct {
+		confirmationPage
+		Token string
+	}{page, token})
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"verifymy-golang-test/entities"
+	"verifymy-golang-test/models"
+	"verifymy-golang-test/services"
+)
+
+// clientWithSecret shows the secret of a client, which is only done once it
+// is generated
+type clientWithSecret struct {
+	*models.Client
+	ClientSecret string `json:"client_secret"`
+}
+
+type createClientHandler struct {
+	clientService services.ClientService
+}
+
+func NewCreateClientHandler(clientService services.ClientService) Handler {
+	return &createClientHandler{clientService: clientService}
+}
+
+func (h *createClientHandler) Method() []string {
+	return []string{http.MethodPost}
+}
+
+func (h *createClientHandler) Route() string {
+	return "/clients"
+}
+
+func (h *createClientHandler) Permission() models.Permission {
+	return models.PermissionManageClients
+}
+
+func (h *createClientHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var payload entities.ClientChanges
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		err = entities.NewError("Invalid JSON", []string{err.Error()})
+
+		jsonPayload, _ := json.Marshal(err)
+		w.Write(jsonPayload)
+		return
+	}
+
+	client, secret, err := h.clientService.Create(r.Context(), payload)
+	if err != nil {
+		if _, ok := err.(*entities.ValidationError); ok {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+			err = entities.NewUnexpectedError(err)
+		}
+
+		jsonPayload, _ := json.Marshal(err)
+		w.Write(jsonPayload)
+		return
+	}
+
+	w.Header().Set("Location", "/clients/"+client.ID.String())
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusCreated)
+
+	jsonPayload, _ := json.Marshal(clientWithSecret{Client: client, ClientSecret: secret})
+	w.Write(jsonPayload)
+}
@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	"verifymy-golang-test/entities"
+	mock_services "verifymy-golang-test/mocks/services"
+	"verifymy-golang-test/models"
+)
+
+type createClientHandlerTestSuite struct {
+	suite.Suite
+	ctrl              *gomock.Controller
+	clientServiceMock *mock_services.MockClientService
+	handler           Handler
+}
+
+func TestCreateClientHandlerTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(createClientHandlerTestSuite))
+}
+
+func (s *createClientHandlerTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.clientServiceMock = mock_services.NewMockClientService(s.ctrl)
+	s.handler = NewCreateClientHandler(s.clientServiceMock)
+}
+
+func (s *createClientHandlerTestSuite) TestMethod() {
+	s.Equal([]string{"POST"}, s.handler.Method())
+}
+
+func (s *createClientHandlerTestSuite) TestRoute() {
+	s.Equal("/clients", s.handler.Route())
+}
+
+func (s *createClientHandlerTestSuite) TestPermission() {
+	s.Equal(
+		models.PermissionManageClients,
+		s.handler.(AuthorizedHandler).Permission(),
+	)
+}
+
+func (s *createClientHandlerTestSuite) TestServeHTTP() {
+	clientId := uuid.New()
+	createdAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	client := &models.Client{
+		ID:             clientId,
+		Name:           "Shop",
+		RedirectURIs:   models.StringList{"https://shop.example.com/verified"},
+		AllowedOrigins: models.StringList{},
+		Scopes:         models.StringList{"age_verification"},
+		SecretHash:     "hashed-secret",
+		CreatedAt:      createdAt,
+		UpdatedAt:      createdAt,
+	}
+	name := "Shop"
+	redirectURIs := []string{"https://shop.example.com/verified"}
+	scopes := []string{"age_verification"}
+	body := `{"name":"Shop","redirect_uris":["https://shop.example.com/verified"],"scopes":["age_verification"]}`
+
+	tests := []struct {
+		description        string
+		body               string
+		skipCreate         bool
+		createError        error
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			description:        "Success",
+			body:               body,
+			expectedStatusCode: http.StatusCreated,
+			expectedBody: `{
+				"id":"` + clientId.String() + `",
+				"name":"Shop",
+				"redirect_uris":["https://shop.example.com/verified"],
+				"allowed_origins":[],
+				"scopes":["age_verification"],
+				"client_secret":"client-secret",
+				"created_at":"2024-05-01T10:00:00Z",
+				"updated_at":"2024-05-01T10:00:00Z"
+			}`,
+		},
+		{
+			description:        "Invalid JSON",
+			body:               `{`,
+			skipCreate:         true,
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			expectedBody:       `{"message":"Invalid JSON","details":["unexpected EOF"]}`,
+		},
+		{
+			description:        "Validation error",
+			body:               body,
+			createError:        entities.NewValidationError("scopes must not be empty"),
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			expectedBody:       `{"message":"validation failed","details":["scopes must not be empty"]}`,
+		},
+		{
+			description:        "Unexpected error",
+			body:               body,
+			createError:        errors.New("error creating client"),
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedBody:       `{"message":"unexpected error","details":["error creating client"]}`,
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			request := httptest.NewRequest(http.MethodPost, "/clients", strings.NewReader(test.body))
+			response := httptest.NewRecorder()
+
+			if !test.skipCreate {
+				var created *models.Client
+				var secret string
+				if test.createError == nil {
+					created, secret = client, "client-secret"
+				}
+				s.clientServiceMock.EXPECT().Create(
+					request.Context(),
+					entities.ClientChanges{Name: &name, RedirectURIs: &redirectURIs, Scopes: &scopes},
+				).Return(created, secret, test.createError)
+			}
+
+			s.handler.ServeHTTP(response, request)
+
+			s.Equal(test.expectedStatusCode, response.Code)
+			s.JSONEq(test.expectedBody, response.Body.String())
+			if test.expectedStatusCode == http.StatusCreated {
+				s.Equal("/clients/"+clientId.String(), response.Header().Get("Location"))
+				s.Equal("no-store", response.Header().Get("Cache-Control"))
+			}
+		})
+	}
+}
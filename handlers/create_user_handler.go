@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"verifymy-golang-test/entities"
+	"verifymy-golang-test/models"
+	"verifymy-golang-test/services"
+)
+
+type createUserHandler struct {
+	adminUserService services.AdminUserService
+}
+
+func NewCreateUserHandler(
+	adminUserService services.AdminUserService,
+) Handler {
+	return &createUserHandler{
+		adminUserService: adminUserService,
+	}
+}
+
+func (h *createUserHandler) Method() []string {
+	return []string{http.MethodPost}
+}
+
+func (h *createUserHandler) Route() string {
+	return "/users"
+}
+
+func (h *createUserHandler) Permission() models.Permission {
+	return models.PermissionManageUsers
+}
+
+func (h *createUserHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	payload, err := decodeEditableUser(r, adminEditableFields, nil)
+	if err != nil {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+
+		jsonPayload, _ := json.Marshal(err)
+		w.Write(jsonPayload)
+		return
+	}
+
+	if payload.Role == "" {
+		payload.Role = models.RoleUser
+	}
+
+	_, err = userChanges(
+		adminEditableFields, &models.User{}, userDocument(adminEditableFields, &payload),
+	)
+	if err != nil {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+
+		jsonPayload, _ := json.Marshal(err)
+		w.Write(jsonPayload)
+		return
+	}
+
+	user, err := h.adminUserService.Invite(r.Context(), payload)
+	if err != nil {
+		if _, ok := err.(*entities.EmailAlreadyInUseError); ok {
+			w.WriteHeader(http.StatusForbidden)
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+			err = entities.NewUnexpectedError(err)
+		}
+
+		jsonPayload, _ := json.Marshal(err)
+		w.Write(jsonPayload)
+		return
+	}
+
+	w.Header().Set("Location", "/users/"+user.ID.String())
+	w.Header().Set("ETag", versionETag(user.Version))
+	w.WriteHeader(http.StatusCreated)
+
+	jsonPayload, _ := json.Marshal(user)
+	w.Write(jsonPayload)
+}
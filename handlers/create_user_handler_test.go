@@ -0,0 +1,213 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	"verifymy-golang-test/entities"
+	mock_services "verifymy-golang-test/mocks/services"
+	"verifymy-golang-test/models"
+)
+
+type createUserHandlerTestSuite struct {
+	suite.Suite
+	ctrl                 *gomock.Controller
+	adminUserServiceMock *mock_services.MockAdminUserService
+	handler              Handler
+}
+
+func TestCreateUserHandlerTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(createUserHandlerTestSuite))
+}
+
+func (s *createUserHandlerTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.adminUserServiceMock = mock_services.NewMockAdminUserService(s.ctrl)
+	s.handler = NewCreateUserHandler(s.adminUserServiceMock)
+}
+
+func (s *createUserHandlerTestSuite) TestMethod() {
+	s.Equal([]string{"POST"}, s.handler.Method())
+}
+
+func (s *createUserHandlerTestSuite) TestRoute() {
+	s.Equal("/users", s.handler.Route())
+}
+
+func (s *createUserHandlerTestSuite) TestPermission() {
+	s.Equal(
+		models.PermissionManageUsers,
+		s.handler.(AuthorizedHandler).Permission(),
+	)
+}
+
+func (s *createUserHandlerTestSuite) TestServeHTTP() {
+	userId := uuid.New()
+	dateOfBirth := models.Date(time.Date(2001, 8, 10, 0, 0, 0, 0, time.UTC))
+	invitedUser := &models.User{
+		ID:          userId,
+		Name:        "Mary Jane",
+		DateOfBirth: dateOfBirth,
+		Email:       "mary.jane@nyork.co",
+		Role:        models.RoleUser,
+		Version:     1,
+	}
+
+	tests := []struct {
+		description        string
+		payload            string
+		expectedInvite     *models.User
+		inviteError        error
+		expectedStatusCode int
+		expectedPayload    map[string]interface{}
+	}{
+		{
+			description: "Success",
+			payload:     `{"name": "Mary Jane", "email": "mary.jane@nyork.co", "date_of_birth": "2001-08-10"}`,
+			expectedInvite: &models.User{
+				Name:        "Mary Jane",
+				DateOfBirth: dateOfBirth,
+				Email:       "mary.jane@nyork.co",
+				Role:        models.RoleUser,
+			},
+			expectedStatusCode: http.StatusCreated,
+			expectedPayload: map[string]interface{}{
+				"id":            userId.String(),
+				"name":          "Mary Jane",
+				"date_of_birth": "2001-08-10",
+				"email":         "mary.jane@nyork.co",
+				"password":      nil,
+				"address":       "",
+				"role":          "user",
+				"status":        "",
+				"created_at":    "0001-01-01T00:00:00Z",
+				"updated_at":    "0001-01-01T00:00:00Z",
+			},
+		},
+		{
+			description: "Non-editable fields are ignored",
+			payload: `{"name": "Mary Jane", "email": "mary.jane@nyork.co", "date_of_birth": "2001-08-10",
+				"password": "secret", "status": "active", "created_at": "2000-01-01T00:00:00Z"}`,
+			expectedInvite: &models.User{
+				Name:        "Mary Jane",
+				DateOfBirth: dateOfBirth,
+				Email:       "mary.jane@nyork.co",
+				Role:        models.RoleUser,
+			},
+			expectedStatusCode: http.StatusCreated,
+			expectedPayload: map[string]interface{}{
+				"id":            userId.String(),
+				"name":          "Mary Jane",
+				"date_of_birth": "2001-08-10",
+				"email":         "mary.jane@nyork.co",
+				"password":      nil,
+				"address":       "",
+				"role":          "user",
+				"status":        "",
+				"created_at":    "0001-01-01T00:00:00Z",
+				"updated_at":    "0001-01-01T00:00:00Z",
+			},
+		},
+		{
+			description:        "Invalid JSON",
+			payload:            `{"name": "Mary Jane"`,
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			expectedPayload: map[string]interface{}{
+				"message": "Invalid JSON",
+				"details": []interface{}{"unexpected EOF"},
+			},
+		},
+		{
+			description:        "Missing required field",
+			payload:            `{"name": "Mary Jane", "email": "mary.jane@nyork.co"}`,
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			expectedPayload: map[string]interface{}{
+				"message": "validation failed",
+				"details": []interface{}{"field date_of_birth is required"},
+			},
+		},
+		{
+			description:        "Unknown role",
+			payload:            `{"name": "Mary Jane", "email": "mary.jane@nyork.co", "date_of_birth": "2001-08-10", "role": "root"}`,
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			expectedPayload: map[string]interface{}{
+				"message": "validation failed",
+				"details": []interface{}{"field role must be one of [user admin]"},
+			},
+		},
+		{
+			description: "E-mail already in use",
+			payload:     `{"name": "Mary Jane", "email": "mary.jane@nyork.co", "date_of_birth": "2001-08-10", "role": "admin"}`,
+			expectedInvite: &models.User{
+				Name:        "Mary Jane",
+				DateOfBirth: dateOfBirth,
+				Email:       "mary.jane@nyork.co",
+				Role:        models.RoleAdmin,
+			},
+			inviteError:        entities.NewEmailAlreadyInUseError("mary.jane@nyork.co"),
+			expectedStatusCode: http.StatusForbidden,
+			expectedPayload: map[string]interface{}{
+				"message": "e-mail is already in use",
+				"details": []interface{}{"mary.jane@nyork.co"},
+			},
+		},
+		{
+			description: "Unexpected error",
+			payload:     `{"name": "Mary Jane", "email": "mary.jane@nyork.co", "date_of_birth": "2001-08-10"}`,
+			expectedInvite: &models.User{
+				Name:        "Mary Jane",
+				DateOfBirth: dateOfBirth,
+				Email:       "mary.jane@nyork.co",
+				Role:        models.RoleUser,
+			},
+			inviteError:        errors.New("error inviting user"),
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedPayload: map[string]interface{}{
+				"message": "unexpected error",
+				"details": []interface{}{"error inviting user"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			request := httptest.NewRequest(
+				http.MethodPost, "/users", strings.NewReader(test.payload),
+			)
+			response := httptest.NewRecorder()
+
+			if test.expectedInvite != nil {
+				inviteResponse := invitedUser
+				if test.inviteError != nil {
+					inviteResponse = nil
+				}
+
+				s.adminUserServiceMock.EXPECT().Invite(
+					request.Context(), *test.expectedInvite,
+				).Return(inviteResponse, test.inviteError)
+			}
+
+			s.handler.ServeHTTP(response, request)
+
+			var jsonPayload map[string]interface{}
+			_ = json.Unmarshal(response.Body.Bytes(), &jsonPayload)
+
+			s.Equal(test.expectedPayload, jsonPayload)
+			s.Equal(test.expectedStatusCode, response.Code)
+			if test.expectedStatusCode == http.StatusCreated {
+				s.Equal("/users/"+userId.String(), response.Header().Get("Location"))
+				s.Equal(`"1"`, response.Header().Get("ETag"))
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"verifymy-golang-test/entities"
+	"verifymy-golang-test/models"
+	"verifymy-golang-test/services"
+)
+
+// webhookSubscriptionWithSecret shows the secret of a subscription, which is
+// only done once it is created
+type webhookSubscriptionWithSecret struct {
+	*models.WebhookSubscription
+	Secret string `json:"secret"`
+}
+
+type createWebhookSubscriptionHandler struct {
+	webhookSubscriptionService services.WebhookSubscriptionService
+}
+
+func NewCreateWebhookSubscriptionHandler(
+	webhookSubscriptionService services.WebhookSubscriptionService,
+) Handler {
+	return &createWebhookSubscriptionHandler{
+		webhookSubscriptionService: webhookSubscriptionService,
+	}
+}
+
+func (h *createWebhookSubscriptionHandler) Method() []string {
+	return []string{http.MethodPost}
+}
+
+func (h *createWebhookSubscriptionHandler) Route() string {
+	return "/webhooks"
+}
+
+func (h *createWebhookSubscriptionHandler) Permission() models.Permission {
+	return models.PermissionManageWebhooks
+}
+
+func (h *createWebhookSubscriptionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var payload entities.WebhookSubscriptionChanges
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		err = entities.NewError("Invalid JSON", []string{err.Error()})
+
+		jsonPayload, _ := json.Marshal(err)
+		w.Write(jsonPayload)
+		return
+	}
+
+	subscription, err := h.webhookSubscriptionService.Create(r.Context(), payload)
+	if err != nil {
+		if _, ok := err.(*entities.ValidationError); ok {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+			err = entities.NewUnexpectedError(err)
+		}
+
+		jsonPayload, _ := json.Marshal(err)
+		w.Write(jsonPayload)
+		return
+	}
+
+	w.Header().Set("Location", "/webhooks/"+subscription.ID.String())
+	w.WriteHeader(http.StatusCreated)
+
+	jsonPayload, _ := json.Marshal(webhookSubscriptionWithSecret{
+		WebhookSubscription: subscription,
+		Secret:              string(subscription.Secret),
+	})
+	w.Write(jsonPayload)
+}
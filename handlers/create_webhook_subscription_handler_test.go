@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	"verifymy-golang-test/entities"
+	mock_services "verifymy-golang-test/mocks/services"
+	"verifymy-golang-test/models"
+)
+
+type createWebhookSubscriptionHandlerTestSuite struct {
+	suite.Suite
+	ctrl                           *gomock.Controller
+	webhookSubscriptionServiceMock *mock_services.MockWebhookSubscriptionService
+	handler                        Handler
+}
+
+func TestCreateWebhookSubscriptionHandlerTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(createWebhookSubscriptionHandlerTestSuite))
+}
+
+func (s *createWebhookSubscriptionHandlerTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.webhookSubscriptionServiceMock = mock_services.NewMockWebhookSubscriptionService(s.ctrl)
+	s.handler = NewCreateWebhookSubscriptionHandler(s.webhookSubscriptionServiceMock)
+}
+
+func (s *createWebhookSubscriptionHandlerTestSuite) TestMethod() {
+	s.Equal([]string{"POST"}, s.handler.Method())
+}
+
+func (s *createWebhookSubscriptionHandlerTestSuite) TestRoute() {
+	s.Equal("/webhooks", s.handler.Route())
+}
+
+func (s *createWebhookSubscriptionHandlerTestSuite) TestPermission() {
+	s.Equal(
+		models.PermissionManageWebhooks,
+		s.handler.(AuthorizedHandler).Permission(),
+	)
+}
+
+func (s *createWebhookSubscriptionHandlerTestSuite) TestServeHTTP() {
+	subscriptionId := uuid.New()
+	createdAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	subscription := &models.WebhookSubscription{
+		ID:         subscriptionId,
+		URL:        "https://partner.example.com/hooks",
+		EventTypes: models.StringList{"user.registered"},
+		Secret:     "a-very-long-secret",
+		CreatedAt:  createdAt,
+		UpdatedAt:  createdAt,
+	}
+	url := "https://partner.example.com/hooks"
+	eventTypes := []string{"user.registered"}
+
+	tests := []struct {
+		description        string
+		body               string
+		skipCreate         bool
+		createError        error
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			description:        "Success",
+			body:               `{"url":"https://partner.example.com/hooks","event_types":["user.registered"]}`,
+			expectedStatusCode: http.StatusCreated,
+			expectedBody: `{
+				"id":"` + subscriptionId.String() + `",
+				"url":"https://partner.example.com/hooks",
+				"event_types":["user.registered"],
+				"secret":"a-very-long-secret",
+				"created_at":"2024-05-01T10:00:00Z",
+				"updated_at":"2024-05-01T10:00:00Z"
+			}`,
+		},
+		{
+			description:        "Invalid JSON",
+			body:               `{`,
+			skipCreate:         true,
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			expectedBody:       `{"message":"Invalid JSON","details":["unexpected EOF"]}`,
+		},
+		{
+			description:        "Validation error",
+			body:               `{"url":"https://partner.example.com/hooks","event_types":["user.registered"]}`,
+			createError:        entities.NewValidationError("event_types must not be empty"),
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			expectedBody:       `{"message":"validation failed","details":["event_types must not be empty"]}`,
+		},
+		{
+			description:        "Unexpected error",
+			body:               `{"url":"https://partner.example.com/hooks","event_types":["user.registered"]}`,
+			createError:        errors.New("error creating webhook"),
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedBody:       `{"message":"unexpected error","details":["error creating webhook"]}`,
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			request := httptest.NewRequest(http.MethodPost, "/webhooks", strings.NewReader(test.body))
+			response := httptest.NewRecorder()
+
+			if !test.skipCreate {
+				var created *models.WebhookSubscription
+				if test.createError == nil {
+					created = subscription
+				}
+				s.webhookSubscriptionServiceMock.EXPECT().Create(
+					request.Context(),
+					entities.WebhookSubscriptionChanges{URL: &url, EventTypes: &eventTypes},
+				).Return(created, test.createError)
+			}
+
+			s.handler.ServeHTTP(response, request)
+
+			s.Equal(test.expectedStatusCode, response.Code)
+			s.JSONEq(test.expectedBody, response.Body.String())
+			if test.expectedStatusCode == http.StatusCreated {
+				s.Equal("/webhooks/"+subscriptionId.String(), response.Header().Get("Location"))
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"verifymy-golang-test/entities"
+	"verifymy-golang-test/services"
+)
+
+type decideOAuthAuthorizationHandler struct {
+	oauthService services.OAuthService
+}
+
+func NewDecideOAuthAuthorizationHandler(
+	oauthService services.OAuthService,
+) Handler {
+	return &decideOAuthAuthorizationHandler{
+		oauthService: oauthService,
+	}
+}
+
+func (h *decideOAuthAuthorizationHandler) Method() []string {
+	return []string{http.MethodPost}
+}
+
+func (h *decideOAuthAuthorizationHandler) Route() string {
+	return "/oauth/authorize"
+}
+
+// ServeHTTP takes the answer of the user on the consent screen, along with
+// the authorization request, and tells where to send them back to
+func (h *decideOAuthAuthorizationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var payload entities.OAuthAuthorizationDecision
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		err = entities.NewError("Invalid JSON", []string{err.Error()})
+
+		jsonPayload, _ := json.Marshal(err)
+		w.Write(jsonPayload)
+		return
+	}
+
+	result, err := h.oauthService.Decide(r.Context(), payload)
+	if err != nil {
+		writeOAuthError(w, err)
+		return
+	}
+
+	jsonPayload, _ := json.Marshal(result)
+	w.Write(jsonPayload)
+}
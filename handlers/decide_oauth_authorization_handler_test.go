@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/suite"
+
+	"verifymy-golang-test/entities"
+	mock_services "verifymy-golang-test/mocks/services"
+)
+
+type decideOAuthAuthorizationHandlerTestSuite struct {
+	suite.Suite
+	ctrl             *gomock.Controller
+	oauthServiceMock *mock_services.MockOAuthService
+	handler          Handler
+}
+
+func TestDecideOAuthAuthorizationHandlerTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(decideOAuthAuthorizationHandlerTestSuite))
+}
+
+func (s *decideOAuthAuthorizationHandlerTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.oauthServiceMock = mock_services.NewMockOAuthService(s.ctrl)
+	s.handler = NewDecideOAuthAuthorizationHandler(s.oauthServiceMock)
+}
+
+func (s *decideOAuthAuthorizationHandlerTestSuite) TestMethod() {
+	s.Equal([]string{"POST"}, s.handler.Method())
+}
+
+func (s *decideOAuthAuthorizationHandlerTestSuite) TestRoute() {
+	s.Equal("/oauth/authorize", s.handler.Route())
+}
+
+func (s *decideOAuthAuthorizationHandlerTestSuite) TestServeHTTP() {
+	body := `{
+		"response_type":"code",
+		"client_id":"7b1e2f5c-8d2a-4f7e-9a51-3c0d4e6b8f21",
+		"redirect_uri":"https://shop.example.com/callback",
+		"state":"xyz",
+		"code_challenge":"challenge",
+		"code_challenge_method":"S256",
+		"approved":true
+	}`
+	approved := true
+
+	tests := []struct {
+		description        string
+		body               string
+		skipDecide         bool
+		result             *entities.OAuthAuthorizationResult
+		decideError        error
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			description: "Success",
+			body:        body,
+			result: &entities.OAuthAuthorizationResult{
+				RedirectURI: "https://shop.example.com/callback?code=the-code&state=xyz",
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `{"redirect_uri":"https://shop.example.com/callback?code=the-code&state=xyz"}`,
+		},
+		{
+			description:        "Invalid JSON",
+			body:               `{`,
+			skipDecide:         true,
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			expectedBody:       `{"message":"Invalid JSON","details":["unexpected EOF"]}`,
+		},
+		{
+			description:        "Validation error",
+			body:               body,
+			decideError:        entities.NewValidationError("approved is required"),
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			expectedBody:       `{"message":"validation failed","details":["approved is required"]}`,
+		},
+		{
+			description:        "Unexpected error",
+			body:               body,
+			decideError:        errors.New("database unavailable"),
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedBody:       `{"message":"unexpected error","details":["database unavailable"]}`,
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			request := httptest.NewRequest(
+				http.MethodPost, "/oauth/authorize", strings.NewReader(test.body),
+			)
+			response := httptest.NewRecorder()
+
+			if !test.skipDecide {
+				s.oauthServiceMock.EXPECT().Decide(
+					request.Context(),
+					entities.OAuthAuthorizationDecision{
+						OAuthAuthorizationRequest: entities.OAuthAuthorizationRequest{
+							ResponseType:        "code",
+							ClientID:            "7b1e2f5c-8d2a-4f7e-9a51-3c0d4e6b8f21",
+							RedirectURI:         "https://shop.example.com/callback",
+							State:               "xyz",
+							CodeChallenge:       "challenge",
+							CodeChallengeMethod: "S256",
+						},
+						Approved: &approved,
+					},
+				).Return(test.result, test.decideError)
+			}
+
+			s.handler.ServeHTTP(response, request)
+
+			s.Equal(test.expectedStatusCode, response.Code)
+			s.JSONEq(test.expectedBody, response.Body.String())
+		})
+	}
+}
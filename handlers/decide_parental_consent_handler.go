@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"verifymy-golang-test/entities"
+	"verifymy-golang-test/services"
+)
+
+type decideParentalConsentHandler struct {
+	parentalConsentService services.ParentalConsentService
+}
+
+func NewDecideParentalConsentHandler(
+	parentalConsentService services.ParentalConsentService,
+) Handler {
+	return &decideParentalConsentHandler{
+		parentalConsentService: parentalConsentService,
+	}
+}
+
+func (h *decideParentalConsentHandler) Method() []string {
+	return []string{http.MethodPost}
+}
+
+func (h *decideParentalConsentHandler) Route() string {
+	return "/auth/parental_consent"
+}
+
+// ServeHTTP takes the decision of the guardian a consent request was sent
+// to, see services.ParentalConsentService.Request
+func (h *decideParentalConsentHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var payload entities.ParentalConsentDecision
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		err = entities.NewError("Invalid JSON", []string{err.Error()})
+
+		jsonPayload, _ := json.Marshal(err)
+		w.Write(jsonPayload)
+		return
+	}
+
+	if payload.Approved == nil {
+		w.Header().Set("Content-Type", "application/json")
+
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		jsonPayload, _ := json.Marshal(entities.NewValidationError("approved is required"))
+		w.Write(jsonPayload)
+		return
+	}
+
+	if err := h.parentalConsentService.Decide(
+		r.Context(), payload.Token, *payload.Approved,
+	); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+
+		if _, ok := err.(*entities.InvalidTokenError); ok {
+			w.WriteHeader(http.StatusBadRequest)
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+			err = entities.NewUnexpectedError(err)
+		}
+
+		jsonPayload, _ := json.Marshal(err)
+		w.Write(jsonPayload)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
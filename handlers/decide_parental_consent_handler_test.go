@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/suite"
+
+	"verifymy-golang-test/entities"
+	mock_services "verifymy-golang-test/mocks/services"
+)
+
+type decideParentalConsentHandlerTestSuite struct {
+	suite.Suite
+	ctrl                       *gomock.Controller
+	parentalConsentServiceMock *mock_services.MockParentalConsentService
+	handler                    Handler
+}
+
+func TestDecideParentalConsentHandlerTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(decideParentalConsentHandlerTestSuite))
+}
+
+func (s *decideParentalConsentHandlerTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.parentalConsentServiceMock = mock_services.NewMockParentalConsentService(s.ctrl)
+	s.handler = NewDecideParentalConsentHandler(s.parentalConsentServiceMock)
+}
+
+func (s *decideParentalConsentHandlerTestSuite) TestMethod() {
+	s.Equal([]string{"POST"}, s.handler.Method())
+}
+
+func (s *decideParentalConsentHandlerTestSuite) TestRoute() {
+	s.Equal("/auth/parental_consent", s.handler.Route())
+}
+
+func (s *decideParentalConsentHandlerTestSuite) TestServeHTTP() {
+	tests := []struct {
+		description        string
+		payload            string
+		skipDecide         bool
+		approved           bool
+		decideError        error
+		expectedStatusCode int
+		expectedPayload    map[string]interface{}
+	}{
+		{
+			description:        "Approved",
+			payload:            `{"token": "parental-consent-token", "approved": true}`,
+			approved:           true,
+			expectedStatusCode: http.StatusNoContent,
+		},
+		{
+			description:        "Rejected",
+			payload:            `{"token": "parental-consent-token", "approved": false}`,
+			expectedStatusCode: http.StatusNoContent,
+		},
+		{
+			description:        "Invalid JSON",
+			payload:            `{"token": "parental-consent-token"`,
+			skipDecide:         true,
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			expectedPayload: map[string]interface{}{
+				"message": "Invalid JSON",
+				"details": []interface{}{"unexpected EOF"},
+			},
+		},
+		{
+			description:        "Missing decision",
+			payload:            `{"token": "parental-consent-token"}`,
+			skipDecide:         true,
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			expectedPayload: map[string]interface{}{
+				"message": "validation failed",
+				"details": []interface{}{"approved is required"},
+			},
+		},
+		{
+			description:        "Invalid token",
+			payload:            `{"token": "parental-consent-token", "approved": true}`,
+			approved:           true,
+			decideError:        entities.NewInvalidTokenError(),
+			expectedStatusCode: http.StatusBadRequest,
+			expectedPayload: map[string]interface{}{
+				"message": "invalid token",
+				"details": nil,
+			},
+		},
+		{
+			description:        "Unexpected error",
+			payload:            `{"token": "parental-consent-token", "approved": true}`,
+			approved:           true,
+			decideError:        errors.New("database unavailable"),
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedPayload: map[string]interface{}{
+				"message": "unexpected error",
+				"details": []interface{}{"database unavailable"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			request := httptest.NewRequest(
+				http.MethodPost, "/auth/parental_consent", strings.NewReader(test.payload),
+			)
+			response := httptest.NewRecorder()
+
+			if !test.skipDecide {
+				s.parentalConsentServiceMock.EXPECT().Decide(
+					request.Context(), "parental-consent-token", test.approved,
+				).Return(test.decideError)
+			}
+
+			s.handler.ServeHTTP(response, request)
+
+			var jsonPayload map[string]interface{}
+			_ = json.Unmarshal(response.Body.Bytes(), &jsonPayload)
+
+			s.Equal(test.expectedPayload, jsonPayload)
+			s.Equal(test.expectedStatusCode, response.Code)
+		})
+	}
+}
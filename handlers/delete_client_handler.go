@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"verifymy-golang-test/entities"
+	"verifymy-golang-test/models"
+	"verifymy-golang-test/services"
+)
+
+type deleteClientHandler struct {
+	clientService services.ClientService
+}
+
+func NewDeleteClientHandler(clientService services.ClientService) Handler {
+	return &deleteClientHandler{clientService: clientService}
+}
+
+func (h *deleteClientHandler) Method() []string {
+	return []string{http.MethodDelete}
+}
+
+func (h *deleteClientHandler) Route() string {
+	return "/clients/{client_id}"
+}
+
+func (h *deleteClientHandler) Permission() models.Permission {
+	return models.PermissionManageClients
+}
+
+func (h *deleteClientHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	err := h.clientService.DeleteById(r.Context(), mux.Vars(r)["client_id"])
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+
+		if _, ok := err.(*entities.ItemNotFoundError); ok {
+			w.WriteHeader(http.StatusNotFound)
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+			err = entities.NewUnexpectedError(err)
+		}
+
+		jsonPayload, _ := json.Marshal(err)
+		w.Write(jsonPayload)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
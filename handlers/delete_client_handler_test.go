@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/suite"
+
+	"verifymy-golang-test/entities"
+	mock_services "verifymy-golang-test/mocks/services"
+	"verifymy-golang-test/models"
+)
+
+type deleteClientHandlerTestSuite struct {
+	suite.Suite
+	ctrl              *gomock.Controller
+	clientServiceMock *mock_services.MockClientService
+	handler           Handler
+}
+
+func TestDeleteClientHandlerTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(deleteClientHandlerTestSuite))
+}
+
+func (s *deleteClientHandlerTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.clientServiceMock = mock_services.NewMockClientService(s.ctrl)
+	s.handler = NewDeleteClientHandler(s.clientServiceMock)
+}
+
+func (s *deleteClientHandlerTestSuite) TestMethod() {
+	s.Equal([]string{"DELETE"}, s.handler.Method())
+}
+
+func (s *deleteClientHandlerTestSuite) TestRoute() {
+	s.Equal("/clients/{client_id}", s.handler.Route())
+}
+
+func (s *deleteClientHandlerTestSuite) TestPermission() {
+	s.Equal(
+		models.PermissionManageClients,
+		s.handler.(AuthorizedHandler).Permission(),
+	)
+}
+
+func (s *deleteClientHandlerTestSuite) TestServeHTTP() {
+	clientId := uuid.New()
+
+	tests := []struct {
+		description        string
+		deleteError        error
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			description:        "Success",
+			expectedStatusCode: http.StatusNoContent,
+		},
+		{
+			description:        "Client not found",
+			deleteError:        entities.NewItemNotFoundError("Client", clientId.String()),
+			expectedStatusCode: http.StatusNotFound,
+			expectedBody:       `{"message":"Client not found","details":["` + clientId.String() + `"]}`,
+		},
+		{
+			description:        "Unexpected error",
+			deleteError:        errors.New("error deleting client"),
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedBody:       `{"message":"unexpected error","details":["error deleting client"]}`,
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			request := httptest.NewRequest(http.MethodDelete, "/clients/"+clientId.String(), nil)
+			request = mux.SetURLVars(request, map[string]string{"client_id": clientId.String()})
+			response := httptest.NewRecorder()
+
+			s.clientServiceMock.EXPECT().DeleteById(
+				request.Context(), clientId.String(),
+			).Return(test.deleteError)
+
+			s.handler.ServeHTTP(response, request)
+
+			s.Equal(test.expectedStatusCode, response.Code)
+			s.Equal(test.expectedBody, response.Body.String())
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"verifymy-golang-test/common"
+	"verifymy-golang-test/entities"
+	"verifymy-golang-test/models"
+	"verifymy-golang-test/services"
+)
+
+type deleteProfileHandler struct {
+	userErasureService services.UserErasureService
+}
+
+func NewDeleteProfileHandler(
+	userErasureService services.UserErasureService,
+) Handler {
+	return &deleteProfileHandler{
+		userErasureService: userErasureService,
+	}
+}
+
+func (h *deleteProfileHandler) Method() []string {
+	return []string{http.MethodDelete}
+}
+
+func (h *deleteProfileHandler) Route() string {
+	return "/profile"
+}
+
+func (h *deleteProfileHandler) AllowsPendingConsent() bool {
+	return true
+}
+
+func (h *deleteProfileHandler) RequiresSudo() bool {
+	return true
+}
+
+// ServeHTTP erases the signed in user, see services.UserErasureService
+func (h *deleteProfileHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	user := r.Context().Value(common.AuthUser).(*models.User)
+
+	if err := h.userErasureService.Erase(r.Context(), user); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+
+		w.WriteHeader(http.StatusInternalServerError)
+		err = entities.NewUnexpectedError(err)
+
+		jsonPayload, _ := json.Marshal(err)
+		w.Write(jsonPayload)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
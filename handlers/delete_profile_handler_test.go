@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	"verifymy-golang-test/common"
+	mock_services "verifymy-golang-test/mocks/services"
+	"verifymy-golang-test/models"
+)
+
+type deleteProfileHandlerTestSuite struct {
+	suite.Suite
+	ctrl                   *gomock.Controller
+	userErasureServiceMock *mock_services.MockUserErasureService
+	handler                Handler
+}
+
+func TestDeleteProfileHandlerTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(deleteProfileHandlerTestSuite))
+}
+
+func (s *deleteProfileHandlerTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.userErasureServiceMock = mock_services.NewMockUserErasureService(s.ctrl)
+	s.handler = NewDeleteProfileHandler(s.userErasureServiceMock)
+}
+
+func (s *deleteProfileHandlerTestSuite) TestMethod() {
+	s.Equal([]string{"DELETE"}, s.handler.Method())
+}
+
+func (s *deleteProfileHandlerTestSuite) TestRoute() {
+	s.Equal("/profile", s.handler.Route())
+}
+
+func (s *deleteProfileHandlerTestSuite) TestAllowsPendingConsent() {
+	s.True(s.handler.(PendingConsentHandler).AllowsPendingConsent())
+}
+
+func (s *deleteProfileHandlerTestSuite) TestRequiresSudo() {
+	s.True(s.handler.(SudoHandler).RequiresSudo())
+}
+
+func (s *deleteProfileHandlerTestSuite) TestServeHTTP() {
+	user := &models.User{ID: uuid.New()}
+
+	tests := []struct {
+		description        string
+		eraseError         error
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			description:        "Success",
+			expectedStatusCode: http.StatusNoContent,
+		},
+		{
+			description:        "Unexpected error",
+			eraseError:         errors.New("erasure hook orders: error"),
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedBody:       `{"message":"unexpected error","details":["erasure hook orders: error"]}`,
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			request := httptest.NewRequest(http.MethodDelete, "/profile", nil)
+			request = request.WithContext(
+				context.WithValue(request.Context(), common.AuthUser, user),
+			)
+			response := httptest.NewRecorder()
+
+			s.userErasureServiceMock.EXPECT().Erase(
+				request.Context(), user,
+			).Return(test.eraseError)
+
+			s.handler.ServeHTTP(response, request)
+
+			s.Equal(test.expectedBody, response.Body.String())
+			s.Equal(test.expectedStatusCode, response.Code)
+		})
+	}
+}
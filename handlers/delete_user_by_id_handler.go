@@ -7,6 +7,7 @@ import (
 	"github.com/gorilla/mux"
 
 	"verifymy-golang-test/entities"
+	"verifymy-golang-test/models"
 	"verifymy-golang-test/services"
 )
 
@@ -30,6 +31,10 @@ func (h *deleteUserByIdHandler) Route() string {
 	return "/users/{user_id}"
 }
 
+func (h *deleteUserByIdHandler) Permission() models.Permission {
+	return models.PermissionManageUsers
+}
+
 func (h *deleteUserByIdHandler) ServeHTTP(
 	w http.ResponseWriter, r *http.Request,
 ) {
@@ -58,6 +63,7 @@ func (h *deleteUserByIdHandler) ServeHTTP(
 
 		jsonPayload, _ := json.Marshal(err)
 		w.Write(jsonPayload)
+		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -13,7 +14,9 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/suite"
 
+	"verifymy-golang-test/common"
 	"verifymy-golang-test/entities"
+	"verifymy-golang-test/middlewares"
 	mock_services "verifymy-golang-test/mocks/services"
 	"verifymy-golang-test/models"
 )
@@ -44,6 +47,29 @@ func (s *deleteUserByIdHandlerTestSuite) TestRoute() {
 	s.Equal("/users/{user_id}", s.handler.Route())
 }
 
+func (s *deleteUserByIdHandlerTestSuite) TestPermission() {
+	s.Equal(
+		models.PermissionManageUsers,
+		s.handler.(AuthorizedHandler).Permission(),
+	)
+}
+
+func (s *deleteUserByIdHandlerTestSuite) TestForbiddenWithoutPermission() {
+	userId := uuid.New().String()
+	request := httptest.NewRequest(http.MethodDelete, "/users/"+userId, nil)
+	request = mux.SetURLVars(request, map[string]string{"user_id": userId})
+	request = request.WithContext(context.WithValue(
+		request.Context(), common.AuthUser, &models.User{Role: models.RoleUser},
+	))
+	response := httptest.NewRecorder()
+
+	middlewares.PermissionMiddleware(
+		s.handler.(AuthorizedHandler).Permission(),
+	)(s.handler).ServeHTTP(response, request)
+
+	s.Equal(http.StatusForbidden, response.Code)
+}
+
 func (s *deleteUserByIdHandlerTestSuite) TestServeHTTP() {
 	userId := uuid.New()
 	user := &models.User{
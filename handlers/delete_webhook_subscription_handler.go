@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"verifymy-golang-test/entities"
+	"verifymy-golang-test/models"
+	"verifymy-golang-test/services"
+)
+
+type deleteWebhookSubscriptionHandler struct {
+	webhookSubscriptionService services.WebhookSubscriptionService
+}
+
+func NewDeleteWebhookSubscriptionHandler(
+	webhookSubscriptionService services.WebhookSubscriptionService,
+) Handler {
+	return &deleteWebhookSubscriptionHandler{
+		webhookSubscriptionService: webhookSubscriptionService,
+	}
+}
+
+func (h *deleteWebhookSubscriptionHandler) Method() []string {
+	return []string{http.MethodDelete}
+}
+
+func (h *deleteWebhookSubscriptionHandler) Route() string {
+	return "/webhooks/{webhook_id}"
+}
+
+func (h *deleteWebhookSubscriptionHandler) Permission() models.Permission {
+	return models.PermissionManageWebhooks
+}
+
+func (h *deleteWebhookSubscriptionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	err := h.webhookSubscriptionService.DeleteById(r.Context(), mux.Vars(r)["webhook_id"])
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+
+		if _, ok := err.(*entities.ItemNotFoundError); ok {
+			w.WriteHeader(http.StatusNotFound)
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+			err = entities.NewUnexpectedError(err)
+		}
+
+		jsonPayload, _ := json.Marshal(err)
+		w.Write(jsonPayload)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
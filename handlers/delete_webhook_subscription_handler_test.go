@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/suite"
+
+	"verifymy-golang-test/entities"
+	mock_services "verifymy-golang-test/mocks/services"
+	"verifymy-golang-test/models"
+)
+
+type deleteWebhookSubscriptionHandlerTestSuite struct {
+	suite.Suite
+	ctrl                           *gomock.Controller
+	webhookSubscriptionServiceMock *mock_services.MockWebhookSubscriptionService
+	handler                        Handler
+}
+
+func TestDeleteWebhookSubscriptionHandlerTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(deleteWebhookSubscriptionHandlerTestSuite))
+}
+
+func (s *deleteWebhookSubscriptionHandlerTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.webhookSubscriptionServiceMock = mock_services.NewMockWebhookSubscriptionService(s.ctrl)
+	s.handler = NewDeleteWebhookSubscriptionHandler(s.webhookSubscriptionServiceMock)
+}
+
+func (s *deleteWebhookSubscriptionHandlerTestSuite) TestMethod() {
+	s.Equal([]string{"DELETE"}, s.handler.Method())
+}
+
+func (s *deleteWebhookSubscriptionHandlerTestSuite) TestRoute() {
+	s.Equal("/webhooks/{webhook_id}", s.handler.Route())
+}
+
+func (s *deleteWebhookSubscriptionHandlerTestSuite) TestPermission() {
+	s.Equal(
+		models.PermissionManageWebhooks,
+		s.handler.(AuthorizedHandler).Permission(),
+	)
+}
+
+func (s *deleteWebhookSubscriptionHandlerTestSuite) TestServeHTTP() {
+	subscriptionId := uuid.New()
+
+	tests := []struct {
+		description        string
+		deleteError        error
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			description:        "Success",
+			expectedStatusCode: http.StatusNoContent,
+		},
+		{
+			description:        "Webhook not found",
+			deleteError:        entities.NewItemNotFoundError("Webhook", subscriptionId.String()),
+			expectedStatusCode: http.StatusNotFound,
+			expectedBody:       `{"message":"Webhook not found","details":["` + subscriptionId.String() + `"]}`,
+		},
+		{
+			description:        "Unexpected error",
+			deleteError:        errors.New("error deleting webhook"),
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedBody:       `{"message":"unexpected error","details":["error deleting webhook"]}`,
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			request := httptest.NewRequest(http.MethodDelete, "/webhooks/"+subscriptionId.String(), nil)
+			request = mux.SetURLVars(request, map[string]string{"webhook_id": subscriptionId.String()})
+			response := httptest.NewRecorder()
+
+			s.webhookSubscriptionServiceMock.EXPECT().DeleteById(
+				request.Context(), subscriptionId.String(),
+			).Return(test.deleteError)
+
+			s.handler.ServeHTTP(response, request)
+
+			s.Equal(test.expectedStatusCode, response.Code)
+			s.Equal(test.expectedBody, response.Body.String())
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+
+	"verifymy-golang-test/entities"
+	"verifymy-golang-test/services"
+)
+
+type downloadDataExportHandler struct {
+	dataExportService services.DataExportService
+}
+
+func NewDownloadDataExportHandler(
+	dataExportService services.DataExportService,
+) Handler {
+	return &downloadDataExportHandler{
+		dataExportService: dataExportService,
+	}
+}
+
+func (h *downloadDataExportHandler) Method() []string {
+	return []string{http.MethodGet}
+}
+
+func (h *downloadDataExportHandler) Route() string {
+	return "/exports/download"
+}
+
+// ServeHTTP is public, the signature of the link is what authorizes the
+// download, see services.DataExportService.DownloadURL
+func (h *downloadDataExportHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	exportId := query.Get("id")
+
+	expiresAt, err := strconv.ParseInt(query.Get("expires"), 10, 64)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+
+		w.WriteHeader(http.StatusBadRequest)
+		err = entities.NewInvalidTokenError()
+
+		jsonPayload, _ := json.Marshal(err)
+		w.Write(jsonPayload)
+		return
+	}
+
+	file, err := h.dataExportService.Open(
+		r.Context(), exportId, expiresAt, query.Get("signature"),
+	)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+
+		if _, ok := err.(*entities.InvalidTokenError); ok {
+			w.WriteHeader(http.StatusForbidden)
+		} else if _, ok := err.(*entities.ItemNotFoundError); ok {
+			w.WriteHeader(http.StatusNotFound)
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+			err = entities.NewUnexpectedError(err)
+		}
+
+		jsonPayload, _ := json.Marshal(err)
+		w.Write(jsonPayload)
+		return
+	}
+	defer file.Close()
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set(
+		"Content-Disposition", `attachment; filename="data-export-`+exportId+`.zip"`,
+	)
+	io.Copy(w, file)
+}
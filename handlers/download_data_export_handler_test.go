@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/suite"
+
+	"verifymy-golang-test/entities"
+	mock_services "verifymy-golang-test/mocks/services"
+)
+
+type downloadDataExportHandlerTestSuite struct {
+	suite.Suite
+	ctrl                  *gomock.Controller
+	dataExportServiceMock *mock_services.MockDataExportService
+	handler               Handler
+}
+
+func TestDownloadDataExportHandlerTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(downloadDataExportHandlerTestSuite))
+}
+
+func (s *downloadDataExportHandlerTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.dataExportServiceMock = mock_services.NewMockDataExportService(s.ctrl)
+	s.handler = NewDownloadDataExportHandler(s.dataExportServiceMock)
+}
+
+func (s *downloadDataExportHandlerTestSuite) TestMethod() {
+	s.Equal([]string{"GET"}, s.handler.Method())
+}
+
+func (s *downloadDataExportHandlerTestSuite) TestRoute() {
+	s.Equal("/exports/download", s.handler.Route())
+}
+
+func (s *downloadDataExportHandlerTestSuite) TestServeHTTP() {
+	tests := []struct {
+		description         string
+		query               string
+		file                io.ReadCloser
+		openError           error
+		expectedStatusCode  int
+		expectedContentType string
+		expectedBody        string
+	}{
+		{
+			description:         "Success",
+			query:               "id=export-id&expires=1688130000&signature=abc",
+			file:                io.NopCloser(strings.NewReader("zip content")),
+			expectedStatusCode:  http.StatusOK,
+			expectedContentType: "application/zip",
+			expectedBody:        "zip content",
+		},
+		{
+			description:         "Malformed expiration",
+			query:               "id=export-id&expires=tomorrow&signature=abc",
+			expectedStatusCode:  http.StatusBadRequest,
+			expectedContentType: "application/json",
+			expectedBody:        `{"message":"invalid token","details":null}`,
+		},
+		{
+			description:         "Invalid signature",
+			query:               "id=export-id&expires=1688130000&signature=abc",
+			openError:           entities.NewInvalidTokenError(),
+			expectedStatusCode:  http.StatusForbidden,
+			expectedContentType: "application/json",
+			expectedBody:        `{"message":"invalid token","details":null}`,
+		},
+		{
+			description:         "Not found",
+			query:               "id=export-id&expires=1688130000&signature=abc",
+			openError:           entities.NewItemNotFoundError("Data export", "export-id"),
+			expectedStatusCode:  http.StatusNotFound,
+			expectedContentType: "application/json",
+			expectedBody:        `{"message":"Data export not found","details":["export-id"]}`,
+		},
+		{
+			description:         "Unexpected error",
+			query:               "id=export-id&expires=1688130000&signature=abc",
+			openError:           errors.New("storage error"),
+			expectedStatusCode:  http.StatusInternalServerError,
+			expectedContentType: "application/json",
+			expectedBody:        `{"message":"unexpected error","details":["storage error"]}`,
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			request := httptest.NewRequest(
+				http.MethodGet, "/exports/download?"+test.query, nil,
+			)
+			response := httptest.NewRecorder()
+
+			if test.file != nil || test.openError != nil {
+				s.dataExportServiceMock.EXPECT().Open(
+					request.Context(),
+					"export-id", int64(1688130000), "abc",
+				).Return(test.file, test.openError)
+			}
+
+			s.handler.ServeHTTP(response, request)
+
+			s.Equal(test.expectedBody, response.Body.String())
+			s.Equal(test.expectedStatusCode, response.Code)
+			s.Equal(test.expectedContentType, response.Header().Get("Content-Type"))
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"net/http"
+
+	"verifymy-golang-test/entities"
+	"verifymy-golang-test/services"
+)
+
+type endOpenIDSessionHandler struct {
+	oauthService services.OAuthService
+}
+
+func NewEndOpenIDSessionHandler(
+	oauthService services.OAuthService,
+) Handler {
+	return &endOpenIDSessionHandler{
+		oauthService: oauthService,
+	}
+}
+
+func (h *endOpenIDSessionHandler) Method() []string {
+	return []string{http.MethodGet, http.MethodPost}
+}
+
+func (h *endOpenIDSessionHandler) Route() string {
+	return "/oauth/logout"
+}
+
+// ServeHTTP is where OpenID Connect clients send users to sign them out,
+// RP-initiated logout. Users are redirected back to the client when it
+// tells where to; errors are shown to them instead, since the redirect URI
+// can't be trusted then.
+func (h *endOpenIDSessionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if err := r.ParseForm(); err != nil {
+		writeOAuthError(w, entities.NewOAuthError(
+			entities.OAuthErrorInvalidRequest, "body must be form encoded",
+		))
+		return
+	}
+
+	redirectURI, err := h.oauthService.Logout(r.Context(), entities.OpenIDLogoutRequest{
+		IDTokenHint:           r.Form.Get("id_token_hint"),
+		ClientID:              r.Form.Get("client_id"),
+		PostLogoutRedirectURI: r.Form.Get("post_logout_redirect_uri"),
+		State:                 r.Form.Get("state"),
+	})
+	if err != nil {
+		writeOAuthError(w, err)
+		return
+	}
+
+	if redirectURI != "" {
+		http.Redirect(w, r, redirectURI, http.StatusFound)
+		return
+	}
+
+	w.Write([]byte(`{}`))
+}
@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/suite"
+
+	"verifymy-golang-test/entities"
+	mock_services "verifymy-golang-test/mocks/services"
+)
+
+type endOpenIDSessionHandlerTestSuite struct {
+	suite.Suite
+	ctrl             *gomock.Controller
+	oauthServiceMock *mock_services.MockOAuthService
+	handler          Handler
+}
+
+func TestEndOpenIDSessionHandlerTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(endOpenIDSessionHandlerTestSuite))
+}
+
+func (s *endOpenIDSessionHandlerTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.oauthServiceMock = mock_services.NewMockOAuthService(s.ctrl)
+	s.handler = NewEndOpenIDSessionHandler(s.oauthServiceMock)
+}
+
+func (s *endOpenIDSessionHandlerTestSuite) TestMethod() {
+	s.Equal([]string{"GET", "POST"}, s.handler.Method())
+}
+
+func (s *endOpenIDSessionHandlerTestSuite) TestRoute() {
+	s.Equal("/oauth/logout", s.handler.Route())
+}
+
+func (s *endOpenIDSessionHandlerTestSuite) TestServeHTTP() {
+	tests := []struct {
+		description        string
+		redirectURI        string
+		logoutError        error
+		expectedStatusCode int
+		expectedLocation   string
+		expectedBody       string
+	}{
+		{
+			description:        "Redirected back",
+			redirectURI:        "https://shop.example.com/callback?state=xyz",
+			expectedStatusCode: http.StatusFound,
+			expectedLocation:   "https://shop.example.com/callback?state=xyz",
+		},
+		{
+			description:        "Not redirected",
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `{}`,
+		},
+		{
+			description: "Invalid ID token",
+			logoutError: entities.NewInvalidParameterError(
+				"id_token_hint", "id_token_hint must be an ID token issued by us",
+			),
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       `{"message":"invalid id_token_hint parameter","details":["id_token_hint must be an ID token issued by us"]}`,
+		},
+		{
+			description:        "Unexpected error",
+			logoutError:        errors.New("database unavailable"),
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedBody:       `{"message":"unexpected error","details":["database unavailable"]}`,
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			request := httptest.NewRequest(
+				http.MethodGet,
+				"/oauth/logout?id_token_hint=id-token&client_id=client-id"+
+					"&post_logout_redirect_uri=https://shop.example.com/callback&state=xyz",
+				nil,
+			)
+			response := httptest.NewRecorder()
+
+			s.oauthServiceMock.EXPECT().Logout(request.Context(), entities.OpenIDLogoutRequest{
+				IDTokenHint:           "id-token",
+				ClientID:              "client-id",
+				PostLogoutRedirectURI: "https://shop.example.com/callback",
+				State:                 "xyz",
+			}).Return(test.redirectURI, test.logoutError)
+
+			s.handler.ServeHTTP(response, request)
+
+			s.Equal(test.expectedStatusCode, response.Code)
+			s.Equal(test.expectedLocation, response.Header().Get("Location"))
+			if test.expectedBody != "" {
+				s.JSONEq(test.expectedBody, response.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+func versionETag(version int64) string {
+	return fmt.Sprintf(`"%d"`, version)
+}
+
+// checkIfMatch evaluates the If-Match header against the current version of
+// a resource. On success it returns the version the update must be
+// conditioned on, or 0 when the client did not ask for a precondition.
+func checkIfMatch(r *http.Request, currentVersion int64) (int64, bool) {
+	header := strings.TrimSpace(r.Header.Get("If-Match"))
+	if header == "" || header == "*" {
+		return 0, true
+	}
+
+	currentETag := versionETag(currentVersion)
+	for _, etag := range strings.Split(header, ",") {
+		if strings.TrimSpace(etag) == currentETag {
+			return currentVersion, true
+		}
+	}
+
+	return 0, false
+}
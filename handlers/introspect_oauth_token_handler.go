@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"verifymy-golang-test/services"
+)
+
+type introspectOAuthTokenHandler struct {
+	oauthService services.OAuthService
+}
+
+func NewIntrospectOAuthTokenHandler(
+	oauthService services.OAuthService,
+) Handler {
+	return &introspectOAuthTokenHandler{
+		oauthService: oauthService,
+	}
+}
+
+func (h *introspectOAuthTokenHandler) Method() []string {
+	return []string{http.MethodPost}
+}
+
+func (h *introspectOAuthTokenHandler) Route() string {
+	return "/oauth/introspect"
+}
+
+func (h *introspectOAuthTokenHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+
+	form, credentials, err := oauthClientCredentials(r)
+	if err != nil {
+		writeOAuthError(w, err)
+		return
+	}
+
+	introspection, err := h.oauthService.Introspect(r.Context(), credentials, form.Get("token"))
+	if err != nil {
+		writeOAuthError(w, err)
+		return
+	}
+
+	jsonPayload, _ := json.Marshal(introspection)
+	w.Write(jsonPayload)
+}
@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/suite"
+
+	"verifymy-golang-test/entities"
+	mock_services "verifymy-golang-test/mocks/services"
+)
+
+type introspectOAuthTokenHandlerTestSuite struct {
+	suite.Suite
+	ctrl             *gomock.Controller
+	oauthServiceMock *mock_services.MockOAuthService
+	handler          Handler
+}
+
+func TestIntrospectOAuthTokenHandlerTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(introspectOAuthTokenHandlerTestSuite))
+}
+
+func (s *introspectOAuthTokenHandlerTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.oauthServiceMock = mock_services.NewMockOAuthService(s.ctrl)
+	s.handler = NewIntrospectOAuthTokenHandler(s.oauthServiceMock)
+}
+
+func (s *introspectOAuthTokenHandlerTestSuite) TestMethod() {
+	s.Equal([]string{"POST"}, s.handler.Method())
+}
+
+func (s *introspectOAuthTokenHandlerTestSuite) TestRoute() {
+	s.Equal("/oauth/introspect", s.handler.Route())
+}
+
+func (s *introspectOAuthTokenHandlerTestSuite) TestServeHTTP() {
+	tests := []struct {
+		description        string
+		introspection      *entities.OAuthTokenIntrospection
+		introspectError    error
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			description: "Active token",
+			introspection: &entities.OAuthTokenIntrospection{
+				Active:    true,
+				Scope:     "profile",
+				ClientID:  "client-id",
+				TokenType: "Bearer",
+				ExpiresAt: 1688130000,
+				IssuedAt:  1688126400,
+				Subject:   "user-id",
+				Issuer:    "http://localhost:8080",
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody: `{
+				"active":true,
+				"scope":"profile",
+				"client_id":"client-id",
+				"token_type":"Bearer",
+				"exp":1688130000,
+				"iat":1688126400,
+				"sub":"user-id",
+				"iss":"http://localhost:8080"
+			}`,
+		},
+		{
+			description:        "Inactive token",
+			introspection:      &entities.OAuthTokenIntrospection{},
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `{"active":false}`,
+		},
+		{
+			description: "Missing token",
+			introspectError: entities.NewOAuthError(
+				entities.OAuthErrorInvalidRequest, "token is required",
+			),
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       `{"error":"invalid_request","error_description":"token is required"}`,
+		},
+		{
+			description:        "Unexpected error",
+			introspectError:    errors.New("database unavailable"),
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedBody:       `{"message":"unexpected error","details":["database unavailable"]}`,
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			request := httptest.NewRequest(
+				http.MethodPost, "/oauth/introspect", strings.NewReader("token=access-token"),
+			)
+			request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			request.SetBasicAuth("client-id", "client-secret")
+			response := httptest.NewRecorder()
+
+			s.oauthServiceMock.EXPECT().Introspect(
+				request.Context(),
+				entities.OAuthClientCredentials{ClientID: "client-id", ClientSecret: "client-secret"},
+				"access-token",
+			).Return(test.introspection, test.introspectError)
+
+			s.handler.ServeHTTP(response, request)
+
+			s.Equal(test.expectedStatusCode, response.Code)
+			s.JSONEq(test.expectedBody, response.Body.String())
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"verifymy-golang-test/entities"
+	"verifymy-golang-test/services"
+)
+
+type issueAgeTokenHandler struct {
+	ageTokenService services.AgeTokenService
+}
+
+func NewIssueAgeTokenHandler(
+	ageTokenService services.AgeTokenService,
+) Handler {
+	return &issueAgeTokenHandler{
+		ageTokenService: ageTokenService,
+	}
+}
+
+func (h *issueAgeTokenHandler) Method() []string {
+	return []string{http.MethodPost}
+}
+
+func (h *issueAgeTokenHandler) Route() string {
+	return "/profile/age_tokens"
+}
+
+func (h *issueAgeTokenHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+
+	var payload entities.AgeTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		err = entities.NewError("Invalid JSON", []string{err.Error()})
+
+		jsonPayload, _ := json.Marshal(err)
+		w.Write(jsonPayload)
+		return
+	}
+
+	token, err := h.ageTokenService.Issue(r.Context(), payload)
+	if err != nil {
+		if _, ok := err.(*entities.ValidationError); ok {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+			err = entities.NewUnexpectedError(err)
+		}
+
+		jsonPayload, _ := json.Marshal(err)
+		w.Write(jsonPayload)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+
+	jsonPayload, _ := json.Marshal(token)
+	w.Write(jsonPayload)
+}
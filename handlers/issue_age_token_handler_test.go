@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/suite"
+
+	"verifymy-golang-test/entities"
+	mock_services "verifymy-golang-test/mocks/services"
+)
+
+type issueAgeTokenHandlerTestSuite struct {
+	suite.Suite
+	ctrl                *gomock.Controller
+	ageTokenServiceMock *mock_services.MockAgeTokenService
+	handler             Handler
+}
+
+func TestIssueAgeTokenHandlerTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(issueAgeTokenHandlerTestSuite))
+}
+
+func (s *issueAgeTokenHandlerTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.ageTokenServiceMock = mock_services.NewMockAgeTokenService(s.ctrl)
+	s.handler = NewIssueAgeTokenHandler(s.ageTokenServiceMock)
+}
+
+func (s *issueAgeTokenHandlerTestSuite) TestMethod() {
+	s.Equal([]string{"POST"}, s.handler.Method())
+}
+
+func (s *issueAgeTokenHandlerTestSuite) TestRoute() {
+	s.Equal("/profile/age_tokens", s.handler.Route())
+}
+
+func (s *issueAgeTokenHandlerTestSuite) TestServeHTTP() {
+	audience := "shop.example.com"
+	ageOver := 18
+
+	tests := []struct {
+		description        string
+		body               string
+		skipIssue          bool
+		token              *entities.AgeToken
+		issueError         error
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			description:        "Success",
+			body:               `{"audience":"shop.example.com","age_over":18}`,
+			token:              &entities.AgeToken{Token: "eyJ.age.token", ExpiresAt: 1688130000},
+			expectedStatusCode: http.StatusCreated,
+			expectedBody:       `{"token":"eyJ.age.token","expires_at":1688130000}`,
+		},
+		{
+			description:        "Invalid JSON",
+			body:               `{`,
+			skipIssue:          true,
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			expectedBody:       `{"message":"Invalid JSON","details":["unexpected EOF"]}`,
+		},
+		{
+			description:        "Not verified",
+			body:               `{"audience":"shop.example.com","age_over":18}`,
+			issueError:         entities.NewValidationError("age is not verified"),
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			expectedBody:       `{"message":"validation failed","details":["age is not verified"]}`,
+		},
+		{
+			description:        "Unexpected error",
+			body:               `{"audience":"shop.example.com","age_over":18}`,
+			issueError:         errors.New("database unavailable"),
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedBody:       `{"message":"unexpected error","details":["database unavailable"]}`,
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			request := httptest.NewRequest(
+				http.MethodPost, "/profile/age_tokens", strings.NewReader(test.body),
+			)
+			response := httptest.NewRecorder()
+
+			if !test.skipIssue {
+				s.ageTokenServiceMock.EXPECT().Issue(
+					request.Context(),
+					entities.AgeTokenRequest{Audience: &audience, AgeOver: &ageOver},
+				).Return(test.token, test.issueError)
+			}
+
+			s.handler.ServeHTTP(response, request)
+
+			s.Equal(test.expectedStatusCode, response.Code)
+			s.Equal("no-store", response.Header().Get("Cache-Control"))
+			s.JSONEq(test.expectedBody, response.Body.String())
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"verifymy-golang-test/entities"
+	"verifymy-golang-test/models"
+	"verifymy-golang-test/services"
+)
+
+type listAuditEventsHandler struct {
+	auditService services.AuditService
+}
+
+func NewListAuditEventsHandler(
+	auditService services.AuditService,
+) Handler {
+	return &listAuditEventsHandler{
+		auditService: auditService,
+	}
+}
+
+func (h *listAuditEventsHandler) Method() []string {
+	return []string{http.MethodGet}
+}
+
+func (h *listAuditEventsHandler) Route() string {
+	return "/audit_events"
+}
+
+func (h *listAuditEventsHandler) Permission() models.Permission {
+	return models.PermissionViewAuditLog
+}
+
+// ServeHTTP lists audit events newest first. Pages only go forward, since
+// events keep being appended.
+func (h *listAuditEventsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	query, err := parseAuditEventQuery(r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+
+		jsonPayload, _ := json.Marshal(err)
+		w.Write(jsonPayload)
+		return
+	}
+
+	page, err := h.auditService.FindAll(r.Context(), query)
+	if err != nil {
+		if _, ok := err.(*entities.InvalidParameterError); ok {
+			w.WriteHeader(http.StatusBadRequest)
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+			err = entities.NewUnexpectedError(err)
+		}
+
+		jsonPayload, _ := json.Marshal(err)
+		w.Write(jsonPayload)
+		return
+	}
+
+	setLinkHeader(w, r, query.Page.Limit, page.Next, nil)
+	if page.Total != nil {
+		w.Header().Set("X-Total-Count", strconv.FormatInt(*page.Total, 10))
+	}
+
+	events := page.Events
+	if events == nil {
+		events = []models.AuditEvent{}
+	}
+
+	jsonPayload, _ := json.Marshal(events)
+	w.Write(jsonPayload)
+}
@@ -0,0 +1,184 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	"verifymy-golang-test/entities"
+	mock_services "verifymy-golang-test/mocks/services"
+	"verifymy-golang-test/models"
+)
+
+type listAuditEventsHandlerTestSuite struct {
+	suite.Suite
+	ctrl             *gomock.Controller
+	auditServiceMock *mock_services.MockAuditService
+	handler          Handler
+}
+
+func TestListAuditEventsHandlerTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(listAuditEventsHandlerTestSuite))
+}
+
+func (s *listAuditEventsHandlerTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.auditServiceMock = mock_services.NewMockAuditService(s.ctrl)
+	s.handler = NewListAuditEventsHandler(s.auditServiceMock)
+}
+
+func (s *listAuditEventsHandlerTestSuite) TestMethod() {
+	s.Equal([]string{"GET"}, s.handler.Method())
+}
+
+func (s *listAuditEventsHandlerTestSuite) TestRoute() {
+	s.Equal("/audit_events", s.handler.Route())
+}
+
+func (s *listAuditEventsHandlerTestSuite) TestPermission() {
+	s.Equal(
+		models.PermissionViewAuditLog,
+		s.handler.(AuthorizedHandler).Permission(),
+	)
+}
+
+func (s *listAuditEventsHandlerTestSuite) TestServeHTTP() {
+	createdAt := time.Date(2023, 6, 30, 12, 0, 0, 0, time.UTC)
+	createdFrom := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	event := models.AuditEvent{
+		ID:         uuid.New(),
+		ActorID:    "actor-id",
+		Action:     "user.updated",
+		TargetType: "user",
+		TargetID:   "target-id",
+		IP:         "10.0.0.1",
+		UserAgent:  "curl/8.0",
+		RequestID:  "request-id",
+		Diff: models.JSONMap{
+			"password": map[string]interface{}{"from": "[REDACTED]", "to": "[REDACTED]"},
+		},
+		CreatedAt: createdAt,
+	}
+	next := &entities.Cursor{ID: event.ID.String(), Sort: entities.AuditEventSortKey, Value: "2023-06-30T12:00:00Z"}
+	total := int64(5)
+
+	tests := []struct {
+		description        string
+		queryString        string
+		expectedQuery      *entities.AuditEventQuery
+		findAllResponse    *entities.AuditEventsPage
+		findAllError       error
+		expectedResponse   interface{}
+		expectedLink       string
+		expectedTotal      string
+		expectedStatusCode int
+	}{
+		{
+			description: "Success",
+			queryString: "?action=user.updated&actor_id=actor-id&created_from=2023-06-01&limit=1&include_total=true",
+			expectedQuery: &entities.AuditEventQuery{
+				Filters: entities.AuditEventFilters{
+					ActorID:     "actor-id",
+					Action:      "user.updated",
+					CreatedFrom: &createdFrom,
+				},
+				Page: entities.PageRequest{Limit: 1, WithTotal: true},
+			},
+			findAllResponse: &entities.AuditEventsPage{
+				Events: []models.AuditEvent{event},
+				Next:   next,
+				Total:  &total,
+			},
+			expectedResponse: []interface{}{
+				map[string]interface{}{
+					"id":          event.ID.String(),
+					"actor_id":    "actor-id",
+					"action":      "user.updated",
+					"target_type": "user",
+					"target_id":   "target-id",
+					"ip":          "10.0.0.1",
+					"user_agent":  "curl/8.0",
+					"request_id":  "request-id",
+					"diff": map[string]interface{}{
+						"password": map[string]interface{}{"from": "[REDACTED]", "to": "[REDACTED]"},
+					},
+					"metadata":   nil,
+					"created_at": "2023-06-30T12:00:00Z",
+				},
+			},
+			expectedLink:       `</audit_events?action=user.updated&actor_id=actor-id&created_from=2023-06-01&cursor=` + next.Encode() + `&include_total=true&limit=1>; rel="next"`,
+			expectedTotal:      "5",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			description: "No events",
+			expectedQuery: &entities.AuditEventQuery{
+				Page: entities.PageRequest{Limit: entities.DefaultPageSize},
+			},
+			findAllResponse:    &entities.AuditEventsPage{},
+			expectedResponse:   []interface{}{},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			description: "Invalid creation time",
+			queryString: "?created_to=yesterday",
+			expectedResponse: map[string]interface{}{
+				"message": "invalid created_to parameter",
+				"details": []interface{}{"must be an RFC 3339 timestamp or a date formatted as YYYY-MM-DD"},
+			},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			description: "Cursor of another listing",
+			queryString: "?cursor=" + (&entities.Cursor{ID: "id", Sort: "id:asc"}).Encode(),
+			expectedResponse: map[string]interface{}{
+				"message": "invalid cursor parameter",
+				"details": []interface{}{"cursor does not match the requested sort"},
+			},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			description: "Unexpected error",
+			expectedQuery: &entities.AuditEventQuery{
+				Page: entities.PageRequest{Limit: entities.DefaultPageSize},
+			},
+			findAllError: errors.New("error fetching audit events"),
+			expectedResponse: map[string]interface{}{
+				"message": "unexpected error",
+				"details": []interface{}{"error fetching audit events"},
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			request := httptest.NewRequest(http.MethodGet, "/audit_events"+test.queryString, nil)
+			response := httptest.NewRecorder()
+
+			if test.expectedQuery != nil {
+				s.auditServiceMock.EXPECT().FindAll(
+					request.Context(), *test.expectedQuery,
+				).Return(test.findAllResponse, test.findAllError)
+			}
+
+			s.handler.ServeHTTP(response, request)
+
+			var payload interface{}
+			_ = json.Unmarshal(response.Body.Bytes(), &payload)
+
+			s.Equal(test.expectedResponse, payload)
+			s.Equal(test.expectedStatusCode, response.Code)
+			s.Equal(test.expectedLink, response.Header().Get("Link"))
+			s.Equal(test.expectedTotal, response.Header().Get("X-Total-Count"))
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"verifymy-golang-test/entities"
+	"verifymy-golang-test/models"
+	"verifymy-golang-test/services"
+)
+
+type listClientsHandler struct {
+	clientService services.ClientService
+}
+
+func NewListClientsHandler(clientService services.ClientService) Handler {
+	return &listClientsHandler{clientService: clientService}
+}
+
+func (h *listClientsHandler) Method() []string {
+	return []string{http.MethodGet}
+}
+
+func (h *listClientsHandler) Route() string {
+	return "/clients"
+}
+
+func (h *listClientsHandler) Permission() models.Permission {
+	return models.PermissionManageClients
+}
+
+func (h *listClientsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	clients, err := h.clientService.FindAll(r.Context())
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		err = entities.NewUnexpectedError(err)
+
+		jsonPayload, _ := json.Marshal(err)
+		w.Write(jsonPayload)
+		return
+	}
+
+	if clients == nil {
+		clients = []models.Client{}
+	}
+
+	jsonPayload, _ := json.Marshal(clients)
+	w.Write(jsonPayload)
+}
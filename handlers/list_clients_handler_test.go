@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	mock_services "verifymy-golang-test/mocks/services"
+	"verifymy-golang-test/models"
+)
+
+type listClientsHandlerTestSuite struct {
+	suite.Suite
+	ctrl              *gomock.Controller
+	clientServiceMock *mock_services.MockClientService
+	handler           Handler
+}
+
+func TestListClientsHandlerTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(listClientsHandlerTestSuite))
+}
+
+func (s *listClientsHandlerTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.clientServiceMock = mock_services.NewMockClientService(s.ctrl)
+	s.handler = NewListClientsHandler(s.clientServiceMock)
+}
+
+func (s *listClientsHandlerTestSuite) TestMethod() {
+	s.Equal([]string{"GET"}, s.handler.Method())
+}
+
+func (s *listClientsHandlerTestSuite) TestRoute() {
+	s.Equal("/clients", s.handler.Route())
+}
+
+func (s *listClientsHandlerTestSuite) TestPermission() {
+	s.Equal(
+		models.PermissionManageClients,
+		s.handler.(AuthorizedHandler).Permission(),
+	)
+}
+
+func (s *listClientsHandlerTestSuite) TestServeHTTP() {
+	clientId := uuid.New()
+	createdAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		description        string
+		clients            []models.Client
+		findAllError       error
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			description: "Success",
+			clients: []models.Client{{
+				ID:             clientId,
+				Name:           "Shop",
+				RedirectURIs:   models.StringList{},
+				AllowedOrigins: models.StringList{"https://shop.example.com"},
+				Scopes:         models.StringList{"age_tokens"},
+				SecretHash:     "hashed-secret",
+				CreatedAt:      createdAt,
+				UpdatedAt:      createdAt,
+			}},
+			expectedStatusCode: http.StatusOK,
+			expectedBody: `[{
+				"id":"` + clientId.String() + `",
+				"name":"Shop",
+				"redirect_uris":[],
+				"allowed_origins":["https://shop.example.com"],
+				"scopes":["age_tokens"],
+				"created_at":"2024-05-01T10:00:00Z",
+				"updated_at":"2024-05-01T10:00:00Z"
+			}]`,
+		},
+		{
+			description:        "No clients",
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `[]`,
+		},
+		{
+			description:        "Unexpected error",
+			findAllError:       errors.New("error finding clients"),
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedBody:       `{"message":"unexpected error","details":["error finding clients"]}`,
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			request := httptest.NewRequest(http.MethodGet, "/clients", nil)
+			response := httptest.NewRecorder()
+
+			s.clientServiceMock.EXPECT().FindAll(request.Context()).Return(
+				test.clients, test.findAllError,
+			)
+
+			s.handler.ServeHTTP(response, request)
+
+			s.Equal(test.expectedStatusCode, response.Code)
+			s.JSONEq(test.expectedBody, response.Body.String())
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"verifymy-golang-test/entities"
+	"verifymy-golang-test/models"
+	"verifymy-golang-test/services"
+)
+
+type listDeletedUsersHandler struct {
+	userService services.UserService
+}
+
+func NewListDeletedUsersHandler(
+	userService services.UserService,
+) Handler {
+	return &listDeletedUsersHandler{
+		userService: userService,
+	}
+}
+
+func (h *listDeletedUsersHandler) Method() []string {
+	return []string{http.MethodGet}
+}
+
+func (h *listDeletedUsersHandler) Route() string {
+	return "/deleted_users"
+}
+
+func (h *listDeletedUsersHandler) Permission() models.Permission {
+	return models.PermissionManageUsers
+}
+
+func (h *listDeletedUsersHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	query, err := parseUserQuery(r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+
+		jsonPayload, _ := json.Marshal(err)
+		w.Write(jsonPayload)
+		return
+	}
+
+	query.Filters.Deleted = true
+	page, err := h.userService.FindAll(r.Context(), query)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		err = entities.NewUnexpectedError(err)
+
+		jsonPayload, _ := json.Marshal(err)
+		w.Write(jsonPayload)
+		return
+	}
+
+	setLinkHeader(w, r, query.Page.Limit, page.Next, page.Previous)
+	if page.Total != nil {
+		w.Header().Set("X-Total-Count", strconv.FormatInt(*page.Total, 10))
+	}
+
+	users := make([]map[string]interface{}, 0, len(page.Users))
+	for i := range page.Users {
+		user := &page.Users[i]
+
+		shaped := shapeResource(user, entities.Projection{}, nil)
+		shaped["deleted_at"] = user.DeletedAt.Time
+		shaped["anonymized"] = user.AnonymizedAt.Valid
+		users = append(users, shaped)
+	}
+
+	jsonPayload, _ := json.Marshal(users)
+	w.Write(jsonPayload)
+}
@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+
+	"verifymy-golang-test/entities"
+	mock_services "verifymy-golang-test/mocks/services"
+	"verifymy-golang-test/models"
+)
+
+type listDeletedUsersHandlerTestSuite struct {
+	suite.Suite
+	ctrl            *gomock.Controller
+	userServiceMock *mock_services.MockUserService
+	handler         Handler
+}
+
+func TestListDeletedUsersHandlerTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(listDeletedUsersHandlerTestSuite))
+}
+
+func (s *listDeletedUsersHandlerTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.userServiceMock = mock_services.NewMockUserService(s.ctrl)
+	s.handler = NewListDeletedUsersHandler(s.userServiceMock)
+}
+
+func (s *listDeletedUsersHandlerTestSuite) TestMethod() {
+	s.Equal([]string{"GET"}, s.handler.Method())
+}
+
+func (s *listDeletedUsersHandlerTestSuite) TestRoute() {
+	s.Equal("/deleted_users", s.handler.Route())
+}
+
+func (s *listDeletedUsersHandlerTestSuite) TestPermission() {
+	s.Equal(
+		models.PermissionManageUsers,
+		s.handler.(AuthorizedHandler).Permission(),
+	)
+}
+
+func (s *listDeletedUsersHandlerTestSuite) TestServeHTTP() {
+	deletedAt := time.Date(2023, 6, 1, 10, 0, 0, 0, time.UTC)
+	users := []models.User{
+		{
+			ID:        uuid.New(),
+			Name:      "Peter Parker",
+			Password:  "hashedpass",
+			DeletedAt: gorm.DeletedAt{Time: deletedAt, Valid: true},
+		},
+		{
+			ID:           uuid.New(),
+			DeletedAt:    gorm.DeletedAt{Time: deletedAt, Valid: true},
+			AnonymizedAt: sql.NullTime{Time: deletedAt.Add(time.Hour), Valid: true},
+		},
+	}
+	expectedQuery := entities.UserQuery{
+		Filters:       entities.UserFilters{Deleted: true},
+		SortField:     entities.DefaultUserSortField,
+		SortDirection: entities.SortAscending,
+		Page:          entities.PageRequest{Limit: 10},
+	}
+	total := int64(2)
+
+	tests := []struct {
+		description        string
+		queryString        string
+		expectFindAll      bool
+		findAllResponse    *entities.UsersPage
+		findAllError       error
+		expectedResponse   interface{}
+		expectedTotal      string
+		expectedStatusCode int
+	}{
+		{
+			description:   "Success",
+			expectFindAll: true,
+			findAllResponse: &entities.UsersPage{
+				Users: users,
+				Total: &total,
+			},
+			expectedResponse: []interface{}{
+				map[string]interface{}{
+					"id":            users[0].ID.String(),
+					"name":          "Peter Parker",
+					"email":         "",
+					"password":      nil,
+					"date_of_birth": "0001-01-01",
+					"address":       "",
+					"role":          "",
+					"status":        "",
+					"created_at":    "0001-01-01T00:00:00Z",
+					"updated_at":    "0001-01-01T00:00:00Z",
+					"deleted_at":    "2023-06-01T10:00:00Z",
+					"anonymized":    false,
+				},
+				map[string]interface{}{
+					"id":            users[1].ID.String(),
+					"name":          "",
+					"email":         "",
+					"password":      nil,
+					"date_of_birth": "0001-01-01",
+					"address":       "",
+					"role":          "",
+					"status":        "",
+					"created_at":    "0001-01-01T00:00:00Z",
+					"updated_at":    "0001-01-01T00:00:00Z",
+					"deleted_at":    "2023-06-01T10:00:00Z",
+					"anonymized":    true,
+				},
+			},
+			expectedTotal:      "2",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			description: "Invalid sort",
+			queryString: "?sort=password",
+			expectedResponse: map[string]interface{}{
+				"message": "invalid sort parameter",
+				"details": []interface{}{"cannot sort by password"},
+			},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			description:   "Unexpected error",
+			expectFindAll: true,
+			findAllError:  errors.New("error fetching deleted users"),
+			expectedResponse: map[string]interface{}{
+				"message": "unexpected error",
+				"details": []interface{}{"error fetching deleted users"},
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			request := httptest.NewRequest(http.MethodGet, "/deleted_users"+test.queryString, nil)
+			response := httptest.NewRecorder()
+
+			if test.expectFindAll {
+				s.userServiceMock.EXPECT().FindAll(
+					request.Context(), expectedQuery,
+				).Return(test.findAllResponse, test.findAllError)
+			}
+
+			s.handler.ServeHTTP(response, request)
+
+			var payload interface{}
+			_ = json.Unmarshal(response.Body.Bytes(), &payload)
+
+			s.Equal(test.expectedResponse, payload)
+			s.Equal(test.expectedStatusCode, response.Code)
+			s.Equal(test.expectedTotal, response.Header().Get("X-Total-Count"))
+		})
+	}
+}
@@ -6,18 +6,22 @@ import (
 	"strconv"
 
 	"verifymy-golang-test/entities"
+	"verifymy-golang-test/models"
 	"verifymy-golang-test/services"
 )
 
 type listUsersHandler struct {
 	userService services.UserService
+	includes    services.UserIncludeRegistry
 }
 
 func NewListUsersHandler(
 	userService services.UserService,
+	includes services.UserIncludeRegistry,
 ) Handler {
 	return &listUsersHandler{
 		userService: userService,
+		includes:    includes,
 	}
 }
 
@@ -29,22 +33,52 @@ func (h *listUsersHandler) Route() string {
 	return "/users"
 }
 
+func (h *listUsersHandler) Permission() models.Permission {
+	return models.PermissionManageUsers
+}
+
 func (h *listUsersHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
-	limit := r.URL.Query().Get("limit")
-	if limit == "" {
-		limit = "10"
+	projection, err := parseProjection(r, entities.UserFields, h.includes.Names())
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+
+		jsonPayload, _ := json.Marshal(err)
+		w.Write(jsonPayload)
+		return
+	}
+
+	query, err := parseUserQuery(r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+
+		jsonPayload, _ := json.Marshal(err)
+		w.Write(jsonPayload)
+		return
+	}
+
+	query.Fields = projection.Fields
+	if len(query.Fields) > 0 {
+		// Included resources may be resolved from fields left out of the
+		// projection, which are loaded but not rendered
+		for _, field := range h.includes.Fields(projection.Includes) {
+			if !containsString(query.Fields, field) {
+				query.Fields = append(query.Fields, field)
+			}
+		}
 	}
-	intLimit, _ := strconv.Atoi(limit)
+	page, err := h.userService.FindAll(r.Context(), query)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		err = entities.NewUnexpectedError(err)
 
-	page := r.URL.Query().Get("page")
-	if page == "" {
-		page = "1"
+		jsonPayload, _ := json.Marshal(err)
+		w.Write(jsonPayload)
+		return
 	}
-	intPage, _ := strconv.Atoi(page)
 
-	users, count, err := h.userService.FindAll(r.Context(), intLimit, intPage)
+	included, err := h.includes.Resolve(r.Context(), projection.Includes, page.Users)
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		err = entities.NewUnexpectedError(err)
@@ -54,7 +88,17 @@ func (h *listUsersHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	setLinkHeader(w, r, query.Page.Limit, page.Next, page.Previous)
+	if page.Total != nil {
+		w.Header().Set("X-Total-Count", strconv.FormatInt(*page.Total, 10))
+	}
+
+	users := make([]map[string]interface{}, 0, len(page.Users))
+	for i := range page.Users {
+		user := &page.Users[i]
+		users = append(users, shapeResource(user, projection, included[user.ID.String()]))
+	}
+
 	jsonPayload, _ := json.Marshal(users)
-	w.Header().Set("X-Total-Count", strconv.FormatInt(count, 10))
 	w.Write(jsonPayload)
 }
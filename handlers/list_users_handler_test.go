@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -13,6 +14,9 @@ import (
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/suite"
 
+	"verifymy-golang-test/common"
+	"verifymy-golang-test/entities"
+	"verifymy-golang-test/middlewares"
 	mock_services "verifymy-golang-test/mocks/services"
 	"verifymy-golang-test/models"
 )
@@ -21,6 +25,7 @@ type listUsersHandlerTestSuite struct {
 	suite.Suite
 	ctrl            *gomock.Controller
 	userServiceMock *mock_services.MockUserService
+	includesMock    *mock_services.MockUserIncludeRegistry
 	handler         Handler
 }
 
@@ -32,7 +37,9 @@ func TestNewListUsersHandler(t *testing.T) {
 func (s *listUsersHandlerTestSuite) SetupTest() {
 	s.ctrl = gomock.NewController(s.T())
 	s.userServiceMock = mock_services.NewMockUserService(s.ctrl)
-	s.handler = NewListUsersHandler(s.userServiceMock)
+	s.includesMock = mock_services.NewMockUserIncludeRegistry(s.ctrl)
+	s.includesMock.EXPECT().Names().Return([]interface{}{"roles"}).AnyTimes()
+	s.handler = NewListUsersHandler(s.userServiceMock, s.includesMock)
 }
 
 func (s *listUsersHandlerTestSuite) TestMethod() {
@@ -43,68 +50,274 @@ func (s *listUsersHandlerTestSuite) TestRoute() {
 	s.Equal("/users", s.handler.Route())
 }
 
+func (s *listUsersHandlerTestSuite) TestPermission() {
+	s.Equal(
+		models.PermissionManageUsers,
+		s.handler.(AuthorizedHandler).Permission(),
+	)
+}
+
+func (s *listUsersHandlerTestSuite) TestForbiddenWithoutPermission() {
+	request := httptest.NewRequest(http.MethodGet, "/users", nil)
+	request = request.WithContext(context.WithValue(
+		request.Context(), common.AuthUser, &models.User{Role: models.RoleUser},
+	))
+	response := httptest.NewRecorder()
+
+	middlewares.PermissionMiddleware(
+		s.handler.(AuthorizedHandler).Permission(),
+	)(s.handler).ServeHTTP(response, request)
+
+	s.Equal(http.StatusForbidden, response.Code)
+}
+
 func (s *listUsersHandlerTestSuite) TestServeHTTP() {
 	users := []models.User{
 		{
-			ID: uuid.New(),
+			ID:       uuid.New(),
+			Password: "hashedpass",
 		},
 	}
+	expectedUsers := []interface{}{
+		map[string]interface{}{
+			"id":            users[0].ID.String(),
+			"name":          users[0].Name,
+			"email":         users[0].Email,
+			"password":      nil,
+			"date_of_birth": time.Time{}.Format(models.DateFormat),
+			"address":       users[0].Address,
+			"created_at":    "0001-01-01T00:00:00Z",
+			"updated_at":    "0001-01-01T00:00:00Z",
+			"role":          "",
+			"status":        "",
+		},
+	}
+	cursor := &entities.Cursor{ID: users[0].ID.String(), Sort: "id:asc"}
+	total := int64(1)
+	defaultQuery := func(page entities.PageRequest) *entities.UserQuery {
+		return &entities.UserQuery{
+			SortField: "id", SortDirection: entities.SortAscending, Page: page,
+		}
+	}
+	dateOfBirthFrom := models.Date(time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC))
+	createdFrom := time.Date(2023, 6, 1, 10, 0, 0, 0, time.UTC)
+	createdTo := time.Date(2023, 6, 30, 23, 59, 59, 999999999, time.UTC)
 
 	tests := []struct {
 		description        string
 		queryString        map[string]string
-		expectedLimit      int
-		expectedPage       int
-		findAllResponse    []models.User
-		findAllCount       int64
+		expectedQuery      *entities.UserQuery
+		findAllResponse    *entities.UsersPage
 		findAllError       error
+		includeFields      []string
+		expectedIncludes   []string
+		resolveResponse    map[string]map[string]interface{}
+		resolveError       error
 		expectedResponse   interface{}
 		expectedStatusCode int
+		expectedLink       string
+		expectedTotal      string
 	}{
 		{
-			description:     "Success with default query params",
-			expectedLimit:   10,
-			expectedPage:    1,
-			findAllResponse: users,
-			findAllCount:    int64(1),
-			expectedResponse: []interface{}{
-				map[string]interface{}{
-					"id":            users[0].ID.String(),
-					"name":          users[0].Name,
-					"email":         users[0].Email,
-					"password":      nil,
-					"date_of_birth": time.Time{}.Format(models.DateFormat),
-					"address":       users[0].Address,
-				},
+			description:        "Success with default query params",
+			expectedQuery:      defaultQuery(entities.PageRequest{Limit: 10}),
+			findAllResponse:    &entities.UsersPage{Users: users},
+			expectedResponse:   expectedUsers,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			description: "Success setting cursor, limit and total",
+			queryString: map[string]string{
+				"limit":         "3",
+				"cursor":        cursor.Encode(),
+				"include_total": "true",
 			},
+			expectedQuery: defaultQuery(entities.PageRequest{
+				Limit: 3, Cursor: cursor, WithTotal: true,
+			}),
+			findAllResponse: &entities.UsersPage{
+				Users:    users,
+				Next:     cursor,
+				Previous: &entities.Cursor{ID: cursor.ID, Sort: "id:asc", Backward: true},
+				Total:    &total,
+			},
+			expectedResponse:   expectedUsers,
 			expectedStatusCode: http.StatusOK,
+			expectedLink: fmt.Sprintf(
+				`</users?cursor=%s&include_total=true&limit=3>; rel="next", </users?cursor=%s&include_total=true&limit=3>; rel="prev"`,
+				cursor.Encode(),
+				(&entities.Cursor{ID: cursor.ID, Sort: "id:asc", Backward: true}).Encode(),
+			),
+			expectedTotal: "1",
 		},
 		{
-			description: "Success setting page and limit",
+			description: "Success filtering and sorting",
 			queryString: map[string]string{
-				"limit": "3",
-				"page":  "1",
+				"email_prefix":       "stephen",
+				"name":               "Curry",
+				"date_of_birth_from": "1990-01-01",
+				"created_from":       "2023-06-01T10:00:00Z",
+				"created_to":         "2023-06-30",
+				"sort":               "created_at",
+				"order":              "DESC",
+			},
+			expectedQuery: &entities.UserQuery{
+				Filters: entities.UserFilters{
+					EmailPrefix:     "stephen",
+					Name:            "Curry",
+					DateOfBirthFrom: &dateOfBirthFrom,
+					CreatedFrom:     &createdFrom,
+					CreatedTo:       &createdTo,
+				},
+				SortField:     "created_at",
+				SortDirection: entities.SortDescending,
+				Page:          entities.PageRequest{Limit: 10},
+			},
+			findAllResponse:    &entities.UsersPage{Users: users},
+			expectedResponse:   expectedUsers,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			description:   "Success with sparse fieldset and include",
+			queryString:   map[string]string{"fields": "id, name", "include": "roles"},
+			includeFields: []string{"role"},
+			expectedQuery: &entities.UserQuery{
+				Fields:        []string{"id", "name", "role"},
+				SortField:     "id",
+				SortDirection: entities.SortAscending,
+				Page:          entities.PageRequest{Limit: 10},
+			},
+			findAllResponse:  &entities.UsersPage{Users: users},
+			expectedIncludes: []string{"roles"},
+			resolveResponse: map[string]map[string]interface{}{
+				users[0].ID.String(): {"roles": []string{"admin"}},
 			},
-			expectedLimit:   3,
-			expectedPage:    1,
-			findAllResponse: users,
-			findAllCount:    int64(1),
 			expectedResponse: []interface{}{
 				map[string]interface{}{
-					"id":            users[0].ID.String(),
-					"name":          users[0].Name,
-					"email":         users[0].Email,
-					"password":      nil,
-					"date_of_birth": time.Time{}.Format(models.DateFormat),
-					"address":       users[0].Address,
+					"id":    users[0].ID.String(),
+					"name":  users[0].Name,
+					"roles": []interface{}{"admin"},
 				},
 			},
 			expectedStatusCode: http.StatusOK,
 		},
+		{
+			description: "Unknown field",
+			queryString: map[string]string{"fields": "id,password"},
+			expectedResponse: map[string]interface{}{
+				"message": "invalid fields parameter",
+				"details": []interface{}{"unknown field password"},
+			},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			description: "Unknown include",
+			queryString: map[string]string{"include": "friends"},
+			expectedResponse: map[string]interface{}{
+				"message": "invalid include parameter",
+				"details": []interface{}{"unknown include friends"},
+			},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			description:      "Error resolving includes",
+			queryString:      map[string]string{"include": "roles"},
+			expectedQuery:    defaultQuery(entities.PageRequest{Limit: 10}),
+			findAllResponse:  &entities.UsersPage{Users: users},
+			expectedIncludes: []string{"roles"},
+			resolveError:     errors.New("error resolving roles"),
+			expectedResponse: map[string]interface{}{
+				"message": "unexpected error",
+				"details": []interface{}{"error resolving roles"},
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+		{
+			description: "Sort field is not allowed",
+			queryString: map[string]string{"sort": "password"},
+			expectedResponse: map[string]interface{}{
+				"message": "invalid sort parameter",
+				"details": []interface{}{"cannot sort by password"},
+			},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			description: "Invalid order",
+			queryString: map[string]string{"order": "sideways"},
+			expectedResponse: map[string]interface{}{
+				"message": "invalid order parameter",
+				"details": []interface{}{"must be either asc or desc"},
+			},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			description: "Invalid date of birth filter",
+			queryString: map[string]string{"date_of_birth_to": "01/01/1990"},
+			expectedResponse: map[string]interface{}{
+				"message": "invalid date_of_birth_to parameter",
+				"details": []interface{}{"must be a date formatted as YYYY-MM-DD"},
+			},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			description: "Invalid created filter",
+			queryString: map[string]string{"created_from": "yesterday"},
+			expectedResponse: map[string]interface{}{
+				"message": "invalid created_from parameter",
+				"details": []interface{}{
+					"must be an RFC 3339 timestamp or a date formatted as YYYY-MM-DD",
+				},
+			},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			description: "Cursor issued for another sort",
+			queryString: map[string]string{"cursor": cursor.Encode(), "sort": "name"},
+			expectedResponse: map[string]interface{}{
+				"message": "invalid cursor parameter",
+				"details": []interface{}{"cursor does not match the requested sort"},
+			},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			description: "Invalid limit",
+			queryString: map[string]string{"limit": "abc"},
+			expectedResponse: map[string]interface{}{
+				"message": "invalid limit parameter",
+				"details": []interface{}{"must be an integer between 1 and 100"},
+			},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			description: "Limit above maximum page size",
+			queryString: map[string]string{"limit": "101"},
+			expectedResponse: map[string]interface{}{
+				"message": "invalid limit parameter",
+				"details": []interface{}{"must be an integer between 1 and 100"},
+			},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			description: "Malformed cursor",
+			queryString: map[string]string{"cursor": "not-a-cursor"},
+			expectedResponse: map[string]interface{}{
+				"message": "invalid cursor parameter",
+				"details": []interface{}{"malformed cursor"},
+			},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			description: "Invalid include_total",
+			queryString: map[string]string{"include_total": "maybe"},
+			expectedResponse: map[string]interface{}{
+				"message": "invalid include_total parameter",
+				"details": []interface{}{"must be a boolean"},
+			},
+			expectedStatusCode: http.StatusBadRequest,
+		},
 		{
 			description:   "Error fetching list of users",
-			expectedLimit: 10,
-			expectedPage:  1,
+			expectedQuery: defaultQuery(entities.PageRequest{Limit: 10}),
 			findAllError:  errors.New("error fetching list of users"),
 			expectedResponse: map[string]interface{}{
 				"message": "unexpected error",
@@ -125,11 +338,20 @@ func (s *listUsersHandlerTestSuite) TestServeHTTP() {
 
 			response := httptest.NewRecorder()
 
-			s.userServiceMock.EXPECT().FindAll(
-				request.Context(),
-				test.expectedLimit,
-				test.expectedPage,
-			).Return(test.findAllResponse, test.findAllCount, test.findAllError)
+			if test.includeFields != nil {
+				s.includesMock.EXPECT().Fields(test.expectedIncludes).Return(test.includeFields)
+			}
+			if test.expectedQuery != nil {
+				s.userServiceMock.EXPECT().FindAll(
+					request.Context(),
+					*test.expectedQuery,
+				).Return(test.findAllResponse, test.findAllError)
+			}
+			if test.findAllResponse != nil {
+				s.includesMock.EXPECT().Resolve(
+					request.Context(), test.expectedIncludes, test.findAllResponse.Users,
+				).Return(test.resolveResponse, test.resolveError)
+			}
 
 			s.handler.ServeHTTP(response, request)
 
@@ -138,11 +360,8 @@ func (s *listUsersHandlerTestSuite) TestServeHTTP() {
 
 			s.Equal(test.expectedResponse, payload)
 			s.Equal(test.expectedStatusCode, response.Code)
-			if test.findAllError == nil {
-				s.Equal(fmt.Sprintf("%d", test.findAllCount), response.Header().Get("X-Total-Count"))
-
-			}
+			s.Equal(test.expectedLink, response.Header().Get("Link"))
+			s.Equal(test.expectedTotal, response.Header().Get("X-Total-Count"))
 		})
 	}
-
 }
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"verifymy-golang-test/entities"
+	"verifymy-golang-test/models"
+	"verifymy-golang-test/services"
+)
+
+var webhookDeliveryStatuses = []string{
+	models.WebhookDeliveryStatusPending,
+	models.WebhookDeliveryStatusSucceeded,
+	models.WebhookDeliveryStatusDead,
+}
+
+type listWebhookDeliveriesHandler struct {
+	webhookDeliveryService services.WebhookDeliveryService
+}
+
+func NewListWebhookDeliveriesHandler(
+	webhookDeliveryService services.WebhookDeliveryService,
+) Handler {
+	return &listWebhookDeliveriesHandler{
+		webhookDeliveryService: webhookDeliveryService,
+	}
+}
+
+func (h *listWebhookDeliveriesHandler) Method() []string {
+	return []string{http.MethodGet}
+}
+
+func (h *listWebhookDeliveriesHandler) Route() string {
+	return "/webhooks/{webhook_id}/deliveries"
+}
+
+func (h *listWebhookDeliveriesHandler) Permission() models.Permission {
+	return models.PermissionManageWebhooks
+}
+
+// ServeHTTP lists the latest deliveries of a webhook, newest first, with
+// every attempt made. They can be narrowed down by status, like dead ones.
+func (h *listWebhookDeliveriesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	status := r.URL.Query().Get("status")
+	if status != "" && !models.StringList(webhookDeliveryStatuses).Includes(status) {
+		w.WriteHeader(http.StatusBadRequest)
+		err := entities.NewInvalidParameterError("status", "must be pending, succeeded or dead")
+
+		jsonPayload, _ := json.Marshal(err)
+		w.Write(jsonPayload)
+		return
+	}
+
+	deliveries, err := h.webhookDeliveryService.FindBySubscriptionId(
+		r.Context(), mux.Vars(r)["webhook_id"], status,
+	)
+	if err != nil {
+		if _, ok := err.(*entities.ItemNotFoundError); ok {
+			w.WriteHeader(http.StatusNotFound)
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+			err = entities.NewUnexpectedError(err)
+		}
+
+		jsonPayload, _ := json.Marshal(err)
+		w.Write(jsonPayload)
+		return
+	}
+
+	if deliveries == nil {
+		deliveries = []models.WebhookDelivery{}
+	}
+
+	jsonPayload, _ := json.Marshal(deliveries)
+	w.Write(jsonPayload)
+}
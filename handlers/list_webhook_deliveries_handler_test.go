@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/suite"
+
+	"verifymy-golang-test/entities"
+	mock_services "verifymy-golang-test/mocks/services"
+	"verifymy-golang-test/models"
+)
+
+type listWebhookDeliveriesHandlerTestSuite struct {
+	suite.Suite
+	ctrl                       *gomock.Controller
+	webhookDeliveryServiceMock *mock_services.MockWebhookDeliveryService
+	handler                    Handler
+}
+
+func TestListWebhookDeliveriesHandlerTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(listWebhookDeliveriesHandlerTestSuite))
+}
+
+func (s *listWebhookDeliveriesHandlerTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.webhookDeliveryServiceMock = mock_services.NewMockWebhookDeliveryService(s.ctrl)
+	s.handler = NewListWebhookDeliveriesHandler(s.webhookDeliveryServiceMock)
+}
+
+func (s *listWebhookDeliveriesHandlerTestSuite) TestMethod() {
+	s.Equal([]string{"GET"}, s.handler.Method())
+}
+
+func (s *listWebhookDeliveriesHandlerTestSuite) TestRoute() {
+	s.Equal("/webhooks/{webhook_id}/deliveries", s.handler.Route())
+}
+
+func (s *listWebhookDeliveriesHandlerTestSuite) TestPermission() {
+	s.Equal(
+		models.PermissionManageWebhooks,
+		s.handler.(AuthorizedHandler).Permission(),
+	)
+}
+
+func (s *listWebhookDeliveriesHandlerTestSuite) TestServeHTTP() {
+	subscriptionId := uuid.NewString()
+	deliveryId := uuid.New()
+	attemptId := uuid.New()
+	at := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		description        string
+		query              string
+		status             string
+		skipFind           bool
+		deliveries         []models.WebhookDelivery
+		findError          error
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			description: "Success",
+			query:       "?status=dead",
+			status:      models.WebhookDeliveryStatusDead,
+			deliveries: []models.WebhookDelivery{{
+				ID:             deliveryId,
+				SubscriptionID: subscriptionId,
+				EventID:        "event-id",
+				EventType:      "user.deleted",
+				Payload:        `{"type":"user.deleted"}`,
+				Status:         models.WebhookDeliveryStatusDead,
+				AttemptCount:   1,
+				NextAttemptAt:  at,
+				CreatedAt:      at,
+				UpdatedAt:      at,
+				Attempts: []models.WebhookDeliveryAttempt{{
+					ID:         attemptId,
+					DeliveryID: deliveryId.String(),
+					StatusCode: 500,
+					Error:      "webhook answered 500",
+					DurationMs: 12,
+					CreatedAt:  at,
+				}},
+			}},
+			expectedStatusCode: http.StatusOK,
+			expectedBody: `[{
+				"id":"` + deliveryId.String() + `",
+				"subscription_id":"` + subscriptionId + `",
+				"event_id":"event-id",
+				"event_type":"user.deleted",
+				"status":"dead",
+				"attempt_count":1,
+				"next_attempt_at":"2024-05-01T10:00:00Z",
+				"created_at":"2024-05-01T10:00:00Z",
+				"updated_at":"2024-05-01T10:00:00Z",
+				"attempts":[{
+					"id":"` + attemptId.String() + `",
+					"status_code":500,
+					"error":"webhook answered 500",
+					"duration_ms":12,
+					"created_at":"2024-05-01T10:00:00Z"
+				}]
+			}]`,
+		},
+		{
+			description:        "No deliveries",
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `[]`,
+		},
+		{
+			description:        "Invalid status",
+			query:              "?status=failed",
+			skipFind:           true,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       `{"message":"invalid status parameter","details":["must be pending, succeeded or dead"]}`,
+		},
+		{
+			description:        "Webhook not found",
+			findError:          entities.NewItemNotFoundError("Webhook", subscriptionId),
+			expectedStatusCode: http.StatusNotFound,
+			expectedBody:       `{"message":"Webhook not found","details":["` + subscriptionId + `"]}`,
+		},
+		{
+			description:        "Unexpected error",
+			findError:          errors.New("error finding deliveries"),
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedBody:       `{"message":"unexpected error","details":["error finding deliveries"]}`,
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			request := httptest.NewRequest(
+				http.MethodGet, "/webhooks/"+subscriptionId+"/deliveries"+test.query, nil,
+			)
+			request = mux.SetURLVars(request, map[string]string{"webhook_id": subscriptionId})
+			response := httptest.NewRecorder()
+
+			if !test.skipFind {
+				s.webhookDeliveryServiceMock.EXPECT().FindBySubscriptionId(
+					request.Context(), subscriptionId, test.status,
+				).Return(test.deliveries, test.findError)
+			}
+
+			s.handler.ServeHTTP(response, request)
+
+			s.Equal(test.expectedStatusCode, response.Code)
+			s.JSONEq(test.expectedBody, response.Body.String())
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"verifymy-golang-test/entities"
+	"verifymy-golang-test/models"
+	"verifymy-golang-test/services"
+)
+
+type listWebhookSubscriptionsHandler struct {
+	webhookSubscriptionService services.WebhookSubscriptionService
+}
+
+func NewListWebhookSubscriptionsHandler(
+	webhookSubscriptionService services.WebhookSubscriptionService,
+) Handler {
+	return &listWebhookSubscriptionsHandler{
+		webhookSubscriptionService: webhookSubscriptionService,
+	}
+}
+
+func (h *listWebhookSubscriptionsHandler) Method() []string {
+	return []string{http.MethodGet}
+}
+
+func (h *listWebhookSubscriptionsHandler) Route() string {
+	return "/webhooks"
+}
+
+func (h *listWebhookSubscriptionsHandler) Permission() models.Permission {
+	return models.PermissionManageWebhooks
+}
+
+func (h *listWebhookSubscriptionsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	subscriptions, err := h.webhookSubscriptionService.FindAll(r.Context())
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		err = entities.NewUnexpectedError(err)
+
+		jsonPayload, _ := json.Marshal(err)
+		w.Write(jsonPayload)
+		return
+	}
+
+	if subscriptions == nil {
+		subscriptions = []models.WebhookSubscription{}
+	}
+
+	jsonPayload, _ := json.Marshal(subscriptions)
+	w.Write(jsonPayload)
+}
@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	mock_services "verifymy-golang-test/mocks/services"
+	"verifymy-golang-test/models"
+)
+
+type listWebhookSubscriptionsHandlerTestSuite struct {
+	suite.Suite
+	ctrl                           *gomock.Controller
+	webhookSubscriptionServiceMock *mock_services.MockWebhookSubscriptionService
+	handler                        Handler
+}
+
+func TestListWebhookSubscriptionsHandlerTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(listWebhookSubscriptionsHandlerTestSuite))
+}
+
+func (s *listWebhookSubscriptionsHandlerTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.webhookSubscriptionServiceMock = mock_services.NewMockWebhookSubscriptionService(s.ctrl)
+	s.handler = NewListWebhookSubscriptionsHandler(s.webhookSubscriptionServiceMock)
+}
+
+func (s *listWebhookSubscriptionsHandlerTestSuite) TestMethod() {
+	s.Equal([]string{"GET"}, s.handler.Method())
+}
+
+func (s *listWebhookSubscriptionsHandlerTestSuite) TestRoute() {
+	s.Equal("/webhooks", s.handler.Route())
+}
+
+func (s *listWebhookSubscriptionsHandlerTestSuite) TestPermission() {
+	s.Equal(
+		models.PermissionManageWebhooks,
+		s.handler.(AuthorizedHandler).Permission(),
+	)
+}
+
+func (s *listWebhookSubscriptionsHandlerTestSuite) TestServeHTTP() {
+	subscriptionId := uuid.New()
+	createdAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		description        string
+		subscriptions      []models.WebhookSubscription
+		findAllError       error
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			description: "Success",
+			subscriptions: []models.WebhookSubscription{{
+				ID:         subscriptionId,
+				URL:        "https://partner.example.com/hooks",
+				EventTypes: models.StringList{"user.deleted"},
+				Secret:     "a-very-long-secret",
+				CreatedAt:  createdAt,
+				UpdatedAt:  createdAt,
+			}},
+			expectedStatusCode: http.StatusOK,
+			expectedBody: `[{
+				"id":"` + subscriptionId.String() + `",
+				"url":"https://partner.example.com/hooks",
+				"event_types":["user.deleted"],
+				"created_at":"2024-05-01T10:00:00Z",
+				"updated_at":"2024-05-01T10:00:00Z"
+			}]`,
+		},
+		{
+			description:        "No webhooks",
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `[]`,
+		},
+		{
+			description:        "Unexpected error",
+			findAllError:       errors.New("error finding webhooks"),
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedBody:       `{"message":"unexpected error","details":["error finding webhooks"]}`,
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			request := httptest.NewRequest(http.MethodGet, "/webhooks", nil)
+			response := httptest.NewRecorder()
+
+			s.webhookSubscriptionServiceMock.EXPECT().FindAll(request.Context()).Return(
+				test.subscriptions, test.findAllError,
+			)
+
+			s.handler.ServeHTTP(response, request)
+
+			s.Equal(test.expectedStatusCode, response.Code)
+			s.JSONEq(test.expectedBody, response.Body.String())
+		})
+	}
+}
@@ -7,6 +7,91 @@ import (
 )
 
 var Module = fx.Provide(
+	services.NewEventPublisher,
+	fx.Annotate(
+		services.NewEventRelayService,
+		fx.ParamTags(``, `group:"event_sinks"`),
+	),
+	fx.Annotate(
+		services.NewLogEventSink,
+		fx.ResultTags(`group:"event_sinks"`),
+	),
+	fx.Annotate(
+		services.NewWebhookEventSink,
+		fx.ResultTags(`group:"event_sinks"`),
+	),
+	fx.Annotate(
+		services.NewWebhookSubscriptionsEventSink,
+		fx.ResultTags(`group:"event_sinks"`),
+	),
+	services.NewWebhookSubscriptionService,
+	services.NewWebhookDeliveryService,
+	services.NewAgeVerificationService,
+	services.NewAgeVerificationSessionService,
+	services.NewAgeRuleEngine,
+	services.NewAgeTokenService,
+	services.NewClientService,
+	services.NewOAuthService,
+	fx.Annotate(
+		services.NewAgeVerificationProviderRegistry,
+		fx.ParamTags(`group:"age_verification_providers"`),
+	),
 	services.NewAuthService,
 	services.NewUserService,
+	services.NewParentalConsentService,
+	services.NewAuditService,
+	services.NewAdminUserService,
+	services.NewUserRetentionService,
+	fx.Annotate(
+		services.NewDataExportService,
+		fx.ParamTags(``, ``, ``, `group:"data_export_collectors"`),
+	),
+	fx.Annotate(
+		services.NewProfileExportCollector,
+		fx.ResultTags(`group:"data_export_collectors"`),
+	),
+	fx.Annotate(
+		services.NewAuditEventsExportCollector,
+		fx.ResultTags(`group:"data_export_collectors"`),
+	),
+	fx.Annotate(
+		services.NewSessionsExportCollector,
+		fx.ResultTags(`group:"data_export_collectors"`),
+	),
+	fx.Annotate(
+		services.NewAgeVerificationExportCollector,
+		fx.ResultTags(`group:"data_export_collectors"`),
+	),
+	fx.Annotate(
+		services.NewOAuthExportCollector,
+		fx.ResultTags(`group:"data_export_collectors"`),
+	),
+	fx.Annotate(
+		services.NewDataExportErasureHook,
+		fx.ResultTags(`group:"erasure_hooks"`),
+	),
+	fx.Annotate(
+		services.NewAgeVerificationErasureHook,
+		fx.ResultTags(`group:"erasure_hooks"`),
+	),
+	fx.Annotate(
+		services.NewOAuthErasureHook,
+		fx.ResultTags(`group:"erasure_hooks"`),
+	),
+	fx.Annotate(
+		services.NewUserErasureService,
+		fx.ParamTags(``, ``, ``, ``, `group:"erasure_hooks"`),
+	),
+	fx.Annotate(
+		services.NewRolesIncluder,
+		fx.ResultTags(`group:"user_includers"`),
+	),
+	fx.Annotate(
+		services.NewAgeVerificationIncluder,
+		fx.ResultTags(`group:"user_includers"`),
+	),
+	fx.Annotate(
+		services.NewUserIncludeRegistry,
+		fx.ParamTags(`group:"user_includers"`),
+	),
 )
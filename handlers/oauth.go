@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"verifymy-golang-test/entities"
+)
+
+// oauthAuthorizationRequest reads an authorization request from the query
+// of /oauth/authorize
+func oauthAuthorizationRequest(query url.Values) entities.OAuthAuthorizationRequest {
+	return entities.OAuthAuthorizationRequest{
+		ResponseType:        query.Get("response_type"),
+		ClientID:            query.Get("client_id"),
+		RedirectURI:         query.Get("redirect_uri"),
+		Scope:               query.Get("scope"),
+		State:               query.Get("state"),
+		CodeChallenge:       query.Get("code_challenge"),
+		CodeChallengeMethod: query.Get("code_challenge_method"),
+		Nonce:               query.Get("nonce"),
+	}
+}
+
+// oauthClientCredentials reads the form of an OAuth endpoint, along with the
+// client credentials sent through HTTP Basic authentication or, failing
+// that, the client_id and client_secret fields
+func oauthClientCredentials(
+	r *http.Request,
+) (url.Values, entities.OAuthClientCredentials, error) {
+	if err := r.ParseForm(); err != nil {
+		return nil, entities.OAuthClientCredentials{}, entities.NewOAuthError(
+			entities.OAuthErrorInvalidRequest, "body must be form encoded",
+		)
+	}
+
+	clientId, clientSecret, ok := r.BasicAuth()
+	if !ok {
+		clientId, clientSecret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+	}
+
+	return r.PostForm, entities.OAuthClientCredentials{
+		ClientID: clientId, ClientSecret: clientSecret,
+	}, nil
+}
+
+// writeOAuthError writes errors of the OAuth endpoints, shaped as RFC 6749
+// defines. Clients failing to authenticate get a 401, as do invalid access
+// tokens, access tokens lacking a scope a 403, RFC 6750, and every other
+// OAuth error a 400.
+func writeOAuthError(w http.ResponseWriter, err error) {
+	if oauthErr, ok := err.(*entities.OAuthError); ok {
+		bearerChallenge := fmt.Sprintf(
+			`Bearer realm="oauth", error=%q, error_description=%q`,
+			oauthErr.Code, oauthErr.Description,
+		)
+
+		switch oauthErr.Code {
+		case entities.OAuthErrorInvalidClient:
+			w.Header().Set("WWW-Authenticate", `Basic realm="oauth"`)
+			w.WriteHeader(http.StatusUnauthorized)
+		case entities.OAuthErrorInvalidToken:
+			w.Header().Set("WWW-Authenticate", bearerChallenge)
+			w.WriteHeader(http.StatusUnauthorized)
+		case entities.OAuthErrorInsufficientScope:
+			w.Header().Set("WWW-Authenticate", bearerChallenge)
+			w.WriteHeader(http.StatusForbidden)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	} else if _, ok := err.(*entities.InvalidParameterError); ok {
+		w.WriteHeader(http.StatusBadRequest)
+	} else if _, ok := err.(*entities.ValidationError); ok {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	} else {
+		w.WriteHeader(http.StatusInternalServerError)
+		err = entities.NewUnexpectedError(err)
+	}
+
+	jsonPayload, _ := json.Marshal(err)
+	w.Write(jsonPayload)
+}
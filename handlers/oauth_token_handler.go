@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"verifymy-golang-test/entities"
+	"verifymy-golang-test/services"
+)
+
+type oauthTokenHandler struct {
+	oauthService services.OAuthService
+}
+
+func NewOAuthTokenHandler(
+	oauthService services.OAuthService,
+) Handler {
+	return &oauthTokenHandler{
+		oauthService: oauthService,
+	}
+}
+
+func (h *oauthTokenHandler) Method() []string {
+	return []string{http.MethodPost}
+}
+
+func (h *oauthTokenHandler) Route() string {
+	return "/oauth/token"
+}
+
+func (h *oauthTokenHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Pragma", "no-cache")
+
+	form, credentials, err := oauthClientCredentials(r)
+	if err != nil {
+		writeOAuthError(w, err)
+		return
+	}
+
+	token, err := h.oauthService.Token(r.Context(), credentials, entities.OAuthTokenRequest{
+		GrantType:    form.Get("grant_type"),
+		Code:         form.Get("code"),
+		RedirectURI:  form.Get("redirect_uri"),
+		CodeVerifier: form.Get("code_verifier"),
+		RefreshToken: form.Get("refresh_token"),
+		Scope:        form.Get("scope"),
+	})
+	if err != nil {
+		writeOAuthError(w, err)
+		return
+	}
+
+	jsonPayload, _ := json.Marshal(token)
+	w.Write(jsonPayload)
+}
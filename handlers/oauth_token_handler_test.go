@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/suite"
+
+	"verifymy-golang-test/entities"
+	mock_services "verifymy-golang-test/mocks/services"
+)
+
+type oauthTokenHandlerTestSuite struct {
+	suite.Suite
+	ctrl             *gomock.Controller
+	oauthServiceMock *mock_services.MockOAuthService
+	handler          Handler
+}
+
+func TestOAuthTokenHandlerTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(oauthTokenHandlerTestSuite))
+}
+
+func (s *oauthTokenHandlerTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.oauthServiceMock = mock_services.NewMockOAuthService(s.ctrl)
+	s.handler = NewOAuthTokenHandler(s.oauthServiceMock)
+}
+
+func (s *oauthTokenHandlerTestSuite) TestMethod() {
+	s.Equal([]string{"POST"}, s.handler.Method())
+}
+
+func (s *oauthTokenHandlerTestSuite) TestRoute() {
+	s.Equal("/oauth/token", s.handler.Route())
+}
+
+func (s *oauthTokenHandlerTestSuite) TestServeHTTP() {
+	tests := []struct {
+		description         string
+		body                string
+		basicAuth           bool
+		expectedCredentials entities.OAuthClientCredentials
+		token               *entities.OAuthToken
+		tokenError          error
+		expectedStatusCode  int
+		expectedBody        string
+	}{
+		{
+			description: "Credentials through HTTP Basic",
+			body:        "grant_type=authorization_code&code=the-code&redirect_uri=https%3A%2F%2Fshop.example.com%2Fcallback&code_verifier=verifier",
+			basicAuth:   true,
+			expectedCredentials: entities.OAuthClientCredentials{
+				ClientID: "client-id", ClientSecret: "client-secret",
+			},
+			token: &entities.OAuthToken{
+				AccessToken:  "access-token",
+				TokenType:    "Bearer",
+				ExpiresIn:    3600,
+				RefreshToken: "refresh-token",
+				Scope:        "profile",
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody: `{
+				"access_token":"access-token",
+				"token_type":"Bearer",
+				"expires_in":3600,
+				"refresh_token":"refresh-token",
+				"scope":"profile"
+			}`,
+		},
+		{
+			description: "Credentials in the body",
+			body:        "grant_type=authorization_code&code=the-code&redirect_uri=https%3A%2F%2Fshop.example.com%2Fcallback&code_verifier=verifier&client_id=other-id&client_secret=other-secret",
+			expectedCredentials: entities.OAuthClientCredentials{
+				ClientID: "other-id", ClientSecret: "other-secret",
+			},
+			token: &entities.OAuthToken{
+				AccessToken: "access-token", TokenType: "Bearer", ExpiresIn: 3600, Scope: "profile",
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody: `{
+				"access_token":"access-token",
+				"token_type":"Bearer",
+				"expires_in":3600,
+				"scope":"profile"
+			}`,
+		},
+		{
+			description: "Invalid client",
+			body:        "grant_type=authorization_code&code=the-code&redirect_uri=https%3A%2F%2Fshop.example.com%2Fcallback&code_verifier=verifier",
+			basicAuth:   true,
+			expectedCredentials: entities.OAuthClientCredentials{
+				ClientID: "client-id", ClientSecret: "client-secret",
+			},
+			tokenError: entities.NewOAuthError(
+				entities.OAuthErrorInvalidClient, "client authentication failed",
+			),
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedBody:       `{"error":"invalid_client","error_description":"client authentication failed"}`,
+		},
+		{
+			description: "Invalid grant",
+			body:        "grant_type=authorization_code&code=the-code&redirect_uri=https%3A%2F%2Fshop.example.com%2Fcallback&code_verifier=verifier",
+			basicAuth:   true,
+			expectedCredentials: entities.OAuthClientCredentials{
+				ClientID: "client-id", ClientSecret: "client-secret",
+			},
+			tokenError: entities.NewOAuthError(
+				entities.OAuthErrorInvalidGrant, "code is invalid or expired",
+			),
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       `{"error":"invalid_grant","error_description":"code is invalid or expired"}`,
+		},
+		{
+			description: "Unexpected error",
+			body:        "grant_type=authorization_code&code=the-code&redirect_uri=https%3A%2F%2Fshop.example.com%2Fcallback&code_verifier=verifier",
+			basicAuth:   true,
+			expectedCredentials: entities.OAuthClientCredentials{
+				ClientID: "client-id", ClientSecret: "client-secret",
+			},
+			tokenError:         errors.New("database unavailable"),
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedBody:       `{"message":"unexpected error","details":["database unavailable"]}`,
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			request := httptest.NewRequest(
+				http.MethodPost, "/oauth/token", strings.NewReader(test.body),
+			)
+			request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			if test.basicAuth {
+				request.SetBasicAuth("client-id", "client-secret")
+			}
+			response := httptest.NewRecorder()
+
+			s.oauthServiceMock.EXPECT().Token(
+				request.Context(),
+				test.expectedCredentials,
+				entities.OAuthTokenRequest{
+					GrantType:    "authorization_code",
+					Code:         "the-code",
+					RedirectURI:  "https://shop.example.com/callback",
+					CodeVerifier: "verifier",
+				},
+			).Return(test.token, test.tokenError)
+
+			s.handler.ServeHTTP(response, request)
+
+			s.Equal(test.expectedStatusCode, response.Code)
+			s.JSONEq(test.expectedBody, response.Body.String())
+			s.Equal("no-store", response.Header().Get("Cache-Control"))
+			if test.expectedStatusCode == http.StatusUnauthorized {
+				s.Equal(`Basic realm="oauth"`, response.Header().Get("WWW-Authenticate"))
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"verifymy-golang-test/entities"
+)
+
+func parsePageRequest(r *http.Request) (entities.PageRequest, error) {
+	query := r.URL.Query()
+	page := entities.PageRequest{Limit: entities.DefaultPageSize}
+
+	if limit := query.Get("limit"); limit != "" {
+		intLimit, err := strconv.Atoi(limit)
+		if err != nil || intLimit < 1 || intLimit > entities.MaxPageSize {
+			return page, entities.NewInvalidParameterError(
+				"limit",
+				fmt.Sprintf("must be an integer between 1 and %d", entities.MaxPageSize),
+			)
+		}
+
+		page.Limit = intLimit
+	}
+
+	if cursor := query.Get("cursor"); cursor != "" {
+		decodedCursor, err := entities.DecodeCursor(cursor)
+		if err != nil {
+			return page, err
+		}
+
+		page.Cursor = decodedCursor
+	}
+
+	if withTotal := query.Get("include_total"); withTotal != "" {
+		boolWithTotal, err := strconv.ParseBool(withTotal)
+		if err != nil {
+			return page, entities.NewInvalidParameterError(
+				"include_total", "must be a boolean",
+			)
+		}
+
+		page.WithTotal = boolWithTotal
+	}
+
+	return page, nil
+}
+
+// setLinkHeader advertises the neighbour pages, keeping every other query
+// parameter the client sent so filters survive navigation.
+func setLinkHeader(
+	w http.ResponseWriter, r *http.Request, limit int, next *entities.Cursor, previous *entities.Cursor,
+) {
+	rels := []string{"next", "prev"}
+	cursors := []*entities.Cursor{next, previous}
+
+	var links []string
+	for i, cursor := range cursors {
+		if cursor == nil {
+			continue
+		}
+
+		query := r.URL.Query()
+		query.Set("cursor", cursor.Encode())
+		query.Set("limit", strconv.Itoa(limit))
+
+		links = append(
+			links, fmt.Sprintf(`<%s?%s>; rel="%s"`, r.URL.Path, query.Encode(), rels[i]),
+		)
+	}
+
+	if len(links) > 0 {
+		w.Header().Set("Link", strings.Join(links, ", "))
+	}
+}
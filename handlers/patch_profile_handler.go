@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"verifymy-golang-test/common"
+	"verifymy-golang-test/entities"
+	"verifymy-golang-test/models"
+	"verifymy-golang-test/services"
+)
+
+type patchProfileHandler struct {
+	userService services.UserService
+}
+
+func NewPatchProfileHandler(
+	userService services.UserService,
+) Handler {
+	return &patchProfileHandler{
+		userService: userService,
+	}
+}
+
+func (h *patchProfileHandler) Method() []string {
+	return []string{http.MethodPatch}
+}
+
+func (h *patchProfileHandler) Route() string {
+	return "/profile"
+}
+
+func (h *patchProfileHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	user := r.Context().Value(common.AuthUser).(*models.User)
+
+	mediaType, err := parsePatchMediaType(r)
+	if err != nil {
+		writeUnsupportedPatchMediaType(w, err)
+		return
+	}
+
+	expectedVersion, ok := checkIfMatch(r, user.Version)
+	if !ok {
+		w.Header().Set("Content-Type", "application/json")
+
+		w.WriteHeader(http.StatusPreconditionFailed)
+		jsonPayload, _ := json.Marshal(entities.NewPreconditionFailedError())
+		w.Write(jsonPayload)
+		return
+	}
+
+	changes, err := patchUserChanges(r, profileEditableFields, user, mediaType)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		jsonPayload, _ := json.Marshal(err)
+		w.Write(jsonPayload)
+		return
+	}
+
+	if len(changes) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	if err := h.userService.PatchProfile(r.Context(), changes, expectedVersion); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+
+		if _, ok := err.(*entities.PreconditionFailedError); ok {
+			w.WriteHeader(http.StatusPreconditionFailed)
+		} else if _, ok := err.(*entities.ValidationError); ok {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+			err = entities.NewUnexpectedError(err)
+		}
+
+		jsonPayload, _ := json.Marshal(err)
+		w.Write(jsonPayload)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,273 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/suite"
+
+	"verifymy-golang-test/common"
+	"verifymy-golang-test/entities"
+	mock_services "verifymy-golang-test/mocks/services"
+	"verifymy-golang-test/models"
+)
+
+type patchProfileHandlerTestSuite struct {
+	suite.Suite
+	ctrl            *gomock.Controller
+	userServiceMock *mock_services.MockUserService
+	handler         Handler
+}
+
+func TestPatchProfileHandlerTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(patchProfileHandlerTestSuite))
+}
+
+func (s *patchProfileHandlerTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.userServiceMock = mock_services.NewMockUserService(s.ctrl)
+	s.handler = NewPatchProfileHandler(s.userServiceMock)
+}
+
+func (s *patchProfileHandlerTestSuite) TestMethod() {
+	s.Equal([]string{"PATCH"}, s.handler.Method())
+}
+
+func (s *patchProfileHandlerTestSuite) TestRoute() {
+	s.Equal("/profile", s.handler.Route())
+}
+
+func (s *patchProfileHandlerTestSuite) TestServeHTTP() {
+	user := &models.User{
+		Name:        "Peter Parker",
+		DateOfBirth: models.Date(time.Date(2001, 8, 10, 0, 0, 0, 0, time.UTC)),
+		Email:       "peter.parker@nyork.co",
+		Address:     "20 Ingram Street",
+		Version:     3,
+	}
+
+	tests := []struct {
+		description        string
+		contentType        string
+		ifMatch            string
+		payload            string
+		expectedChanges    map[string]interface{}
+		expectedVersion    int64
+		patchProfileError  error
+		expectedStatusCode int
+		expectedPayload    map[string]interface{}
+	}{
+		{
+			description:        "Merge patch clears address",
+			contentType:        "application/merge-patch+json",
+			payload:            `{"address": null, "name": "Spider Man"}`,
+			expectedChanges:    map[string]interface{}{"address": "", "name": "Spider Man"},
+			expectedStatusCode: http.StatusNoContent,
+		},
+		{
+			description:        "Merge patch with charset and If-Match",
+			contentType:        "application/merge-patch+json; charset=utf-8",
+			ifMatch:            `"3"`,
+			payload:            `{"date_of_birth": "2001-08-11"}`,
+			expectedChanges:    map[string]interface{}{"date_of_birth": "2001-08-11"},
+			expectedVersion:    3,
+			expectedStatusCode: http.StatusNoContent,
+		},
+		{
+			description: "JSON patch",
+			contentType: "application/json-patch+json",
+			payload: `[
+				{"op": "test", "path": "/email", "value": "peter.parker@nyork.co"},
+				{"op": "replace", "path": "/email", "value": "spidey@nyork.co"},
+				{"op": "remove", "path": "/address"}
+			]`,
+			expectedChanges:    map[string]interface{}{"address": "", "email": "spidey@nyork.co"},
+			expectedStatusCode: http.StatusNoContent,
+		},
+		{
+			description:        "Nothing changes",
+			contentType:        "application/merge-patch+json",
+			payload:            `{"name": "Peter Parker"}`,
+			expectedStatusCode: http.StatusNoContent,
+		},
+		{
+			description:        "Unsupported media type",
+			contentType:        "application/json",
+			payload:            `{"address": null}`,
+			expectedStatusCode: http.StatusUnsupportedMediaType,
+			expectedPayload: map[string]interface{}{
+				"message": "unsupported media type application/json",
+				"details": []interface{}{
+					"application/merge-patch+json", "application/json-patch+json",
+				},
+			},
+		},
+		{
+			description:        "Stale If-Match",
+			contentType:        "application/merge-patch+json",
+			ifMatch:            `"2"`,
+			payload:            `{"address": null}`,
+			expectedStatusCode: http.StatusPreconditionFailed,
+			expectedPayload: map[string]interface{}{
+				"message": "precondition failed",
+				"details": []interface{}{"resource was modified since it was fetched"},
+			},
+		},
+		{
+			description:        "Invalid JSON",
+			contentType:        "application/merge-patch+json",
+			payload:            `{"address": null`,
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			expectedPayload: map[string]interface{}{
+				"message": "Invalid JSON",
+				"details": []interface{}{"unexpected EOF"},
+			},
+		},
+		{
+			description:        "Field not editable",
+			contentType:        "application/merge-patch+json",
+			payload:            `{"password": "123456"}`,
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			expectedPayload: map[string]interface{}{
+				"message": "validation failed",
+				"details": []interface{}{"field password is not editable"},
+			},
+		},
+		{
+			description:        "Required field cleared",
+			contentType:        "application/merge-patch+json",
+			payload:            `{"name": null}`,
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			expectedPayload: map[string]interface{}{
+				"message": "validation failed",
+				"details": []interface{}{"field name is required"},
+			},
+		},
+		{
+			description:        "Invalid field type",
+			contentType:        "application/merge-patch+json",
+			payload:            `{"address": 10}`,
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			expectedPayload: map[string]interface{}{
+				"message": "validation failed",
+				"details": []interface{}{"field address must be a string"},
+			},
+		},
+		{
+			description:        "Invalid email",
+			contentType:        "application/merge-patch+json",
+			payload:            `{"email": "spidey"}`,
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			expectedPayload: map[string]interface{}{
+				"message": "validation failed",
+				"details": []interface{}{"field email must be a valid email address"},
+			},
+		},
+		{
+			description:        "Invalid date of birth",
+			contentType:        "application/merge-patch+json",
+			payload:            `{"date_of_birth": "10/08/2001"}`,
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			expectedPayload: map[string]interface{}{
+				"message": "validation failed",
+				"details": []interface{}{"field date_of_birth must be a date formatted as YYYY-MM-DD"},
+			},
+		},
+		{
+			description:        "Patch replaces the whole document",
+			contentType:        "application/merge-patch+json",
+			payload:            `"Spider Man"`,
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			expectedPayload: map[string]interface{}{
+				"message": "validation failed",
+				"details": []interface{}{"user must be an object"},
+			},
+		},
+		{
+			description:        "JSON patch test fails",
+			contentType:        "application/json-patch+json",
+			payload:            `[{"op": "test", "path": "/name", "value": "Spider Man"}]`,
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			expectedPayload: map[string]interface{}{
+				"message": "invalid patch",
+				"details": []interface{}{`operation 0: test failed for path "/name"`},
+			},
+		},
+		{
+			description:        "Concurrent modification",
+			contentType:        "application/merge-patch+json",
+			ifMatch:            `"3"`,
+			payload:            `{"address": ""}`,
+			expectedChanges:    map[string]interface{}{"address": ""},
+			expectedVersion:    3,
+			patchProfileError:  entities.NewPreconditionFailedError(),
+			expectedStatusCode: http.StatusPreconditionFailed,
+			expectedPayload: map[string]interface{}{
+				"message": "precondition failed",
+				"details": []interface{}{"resource was modified since it was fetched"},
+			},
+		},
+		{
+			description:        "Email change",
+			contentType:        "application/merge-patch+json",
+			payload:            `{"email": "miles.morales@nyork.co"}`,
+			expectedChanges:    map[string]interface{}{"email": "miles.morales@nyork.co"},
+			patchProfileError:  entities.NewValidationError("email can only be changed by confirming the new address"),
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			expectedPayload: map[string]interface{}{
+				"message": "validation failed",
+				"details": []interface{}{"email can only be changed by confirming the new address"},
+			},
+		},
+		{
+			description:        "Unexpected error",
+			contentType:        "application/merge-patch+json",
+			payload:            `{"address": ""}`,
+			expectedChanges:    map[string]interface{}{"address": ""},
+			patchProfileError:  errors.New("error patching profile"),
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedPayload: map[string]interface{}{
+				"message": "unexpected error",
+				"details": []interface{}{"error patching profile"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			request := httptest.NewRequest(
+				http.MethodPatch, "/profile", strings.NewReader(test.payload),
+			)
+			request = request.WithContext(
+				context.WithValue(request.Context(), common.AuthUser, user),
+			)
+			request.Header.Set("Content-Type", test.contentType)
+			if test.ifMatch != "" {
+				request.Header.Set("If-Match", test.ifMatch)
+			}
+			response := httptest.NewRecorder()
+
+			if test.expectedChanges != nil {
+				s.userServiceMock.EXPECT().PatchProfile(
+					request.Context(), test.expectedChanges, test.expectedVersion,
+				).Return(test.patchProfileError)
+			}
+
+			s.handler.ServeHTTP(response, request)
+
+			var jsonPayload map[string]interface{}
+			_ = json.Unmarshal(response.Body.Bytes(), &jsonPayload)
+
+			s.Equal(test.expectedPayload, jsonPayload)
+			s.Equal(test.expectedStatusCode, response.Code)
+		})
+	}
+}
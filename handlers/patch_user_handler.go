@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"verifymy-golang-test/entities"
+	"verifymy-golang-test/models"
+	"verifymy-golang-test/services"
+)
+
+type patchUserHandler struct {
+	userService      services.UserService
+	adminUserService services.AdminUserService
+}
+
+func NewPatchUserHandler(
+	userService services.UserService,
+	adminUserService services.AdminUserService,
+) Handler {
+	return &patchUserHandler{
+		userService:      userService,
+		adminUserService: adminUserService,
+	}
+}
+
+func (h *patchUserHandler) Method() []string {
+	return []string{http.MethodPatch}
+}
+
+func (h *patchUserHandler) Route() string {
+	return "/users/{user_id}"
+}
+
+func (h *patchUserHandler) Permission() models.Permission {
+	return models.PermissionManageUsers
+}
+
+func (h *patchUserHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	mediaType, err := parsePatchMediaType(r)
+	if err != nil {
+		writeUnsupportedPatchMediaType(w, err)
+		return
+	}
+
+	user, err := h.userService.FindById(r.Context(), mux.Vars(r)["user_id"])
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+
+		if _, ok := err.(*entities.ItemNotFoundError); ok {
+			w.WriteHeader(http.StatusNotFound)
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+			err = entities.NewUnexpectedError(err)
+		}
+
+		jsonPayload, _ := json.Marshal(err)
+		w.Write(jsonPayload)
+		return
+	}
+
+	expectedVersion, ok := checkIfMatch(r, user.Version)
+	if !ok {
+		w.Header().Set("Content-Type", "application/json")
+
+		w.WriteHeader(http.StatusPreconditionFailed)
+		jsonPayload, _ := json.Marshal(entities.NewPreconditionFailedError())
+		w.Write(jsonPayload)
+		return
+	}
+
+	changes, err := patchUserChanges(r, adminEditableFields, user, mediaType)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		jsonPayload, _ := json.Marshal(err)
+		w.Write(jsonPayload)
+		return
+	}
+
+	if len(changes) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	err = h.adminUserService.Update(r.Context(), user, changes, expectedVersion)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+
+		if _, ok := err.(*entities.PreconditionFailedError); ok {
+			w.WriteHeader(http.StatusPreconditionFailed)
+		} else if _, ok := err.(*entities.EmailAlreadyInUseError); ok {
+			w.WriteHeader(http.StatusForbidden)
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+			err = entities.NewUnexpectedError(err)
+		}
+
+		jsonPayload, _ := json.Marshal(err)
+		w.Write(jsonPayload)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,205 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/suite"
+
+	"verifymy-golang-test/entities"
+	mock_services "verifymy-golang-test/mocks/services"
+	"verifymy-golang-test/models"
+)
+
+type patchUserHandlerTestSuite struct {
+	suite.Suite
+	ctrl                 *gomock.Controller
+	userServiceMock      *mock_services.MockUserService
+	adminUserServiceMock *mock_services.MockAdminUserService
+	handler              Handler
+}
+
+func TestPatchUserHandlerTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(patchUserHandlerTestSuite))
+}
+
+func (s *patchUserHandlerTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.userServiceMock = mock_services.NewMockUserService(s.ctrl)
+	s.adminUserServiceMock = mock_services.NewMockAdminUserService(s.ctrl)
+	s.handler = NewPatchUserHandler(s.userServiceMock, s.adminUserServiceMock)
+}
+
+func (s *patchUserHandlerTestSuite) TestMethod() {
+	s.Equal([]string{"PATCH"}, s.handler.Method())
+}
+
+func (s *patchUserHandlerTestSuite) TestRoute() {
+	s.Equal("/users/{user_id}", s.handler.Route())
+}
+
+func (s *patchUserHandlerTestSuite) TestPermission() {
+	s.Equal(
+		models.PermissionManageUsers,
+		s.handler.(AuthorizedHandler).Permission(),
+	)
+}
+
+func (s *patchUserHandlerTestSuite) TestServeHTTP() {
+	userId := uuid.New()
+	user := &models.User{
+		ID:          userId,
+		Name:        "Peter Parker",
+		DateOfBirth: models.Date(time.Date(2001, 8, 10, 0, 0, 0, 0, time.UTC)),
+		Email:       "peter.parker@nyork.co",
+		Address:     "20 Ingram Street",
+		Role:        models.RoleUser,
+		Version:     5,
+	}
+
+	tests := []struct {
+		description        string
+		contentType        string
+		payload            string
+		ifMatch            string
+		skipFindById       bool
+		findByIdError      error
+		expectedChanges    map[string]interface{}
+		expectedVersion    int64
+		updateError        error
+		expectedStatusCode int
+		expectedPayload    map[string]interface{}
+	}{
+		{
+			description:        "Merge patch",
+			contentType:        "application/merge-patch+json",
+			payload:            `{"role": "admin", "address": null}`,
+			ifMatch:            `"5"`,
+			expectedChanges:    map[string]interface{}{"address": "", "role": "admin"},
+			expectedVersion:    5,
+			expectedStatusCode: http.StatusNoContent,
+		},
+		{
+			description:        "JSON patch",
+			contentType:        "application/json-patch+json",
+			payload:            `[{"op": "replace", "path": "/role", "value": "admin"}]`,
+			expectedChanges:    map[string]interface{}{"role": "admin"},
+			expectedStatusCode: http.StatusNoContent,
+		},
+		{
+			description:        "Unsupported media type",
+			contentType:        "application/json",
+			payload:            `{"role": "admin"}`,
+			skipFindById:       true,
+			expectedStatusCode: http.StatusUnsupportedMediaType,
+			expectedPayload: map[string]interface{}{
+				"message": "unsupported media type application/json",
+				"details": []interface{}{
+					"application/merge-patch+json", "application/json-patch+json",
+				},
+			},
+		},
+		{
+			description:        "User not found",
+			contentType:        "application/merge-patch+json",
+			payload:            `{"role": "admin"}`,
+			findByIdError:      entities.NewItemNotFoundError("User", userId.String()),
+			expectedStatusCode: http.StatusNotFound,
+			expectedPayload: map[string]interface{}{
+				"message": "User not found",
+				"details": []interface{}{userId.String()},
+			},
+		},
+		{
+			description:        "Stale If-Match",
+			contentType:        "application/merge-patch+json",
+			payload:            `{"role": "admin"}`,
+			ifMatch:            `"4"`,
+			expectedStatusCode: http.StatusPreconditionFailed,
+			expectedPayload: map[string]interface{}{
+				"message": "precondition failed",
+				"details": []interface{}{"resource was modified since it was fetched"},
+			},
+		},
+		{
+			description:        "Unknown role",
+			contentType:        "application/merge-patch+json",
+			payload:            `{"role": "root"}`,
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			expectedPayload: map[string]interface{}{
+				"message": "validation failed",
+				"details": []interface{}{"field role must be one of [user admin]"},
+			},
+		},
+		{
+			description:        "Email already in use",
+			contentType:        "application/merge-patch+json",
+			payload:            `{"email": "miles.morales@nyork.co"}`,
+			expectedChanges:    map[string]interface{}{"email": "miles.morales@nyork.co"},
+			updateError:        entities.NewEmailAlreadyInUseError("miles.morales@nyork.co"),
+			expectedStatusCode: http.StatusForbidden,
+			expectedPayload: map[string]interface{}{
+				"message": "e-mail is already in use",
+				"details": []interface{}{"miles.morales@nyork.co"},
+			},
+		},
+		{
+			description:        "Unexpected error",
+			contentType:        "application/merge-patch+json",
+			payload:            `{"role": "admin"}`,
+			expectedChanges:    map[string]interface{}{"role": "admin"},
+			updateError:        errors.New("error updating user"),
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedPayload: map[string]interface{}{
+				"message": "unexpected error",
+				"details": []interface{}{"error updating user"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			request := httptest.NewRequest(
+				http.MethodPatch, "/users/"+userId.String(), strings.NewReader(test.payload),
+			)
+			request = mux.SetURLVars(request, map[string]string{"user_id": userId.String()})
+			request.Header.Set("Content-Type", test.contentType)
+			if test.ifMatch != "" {
+				request.Header.Set("If-Match", test.ifMatch)
+			}
+			response := httptest.NewRecorder()
+
+			if !test.skipFindById {
+				findByIdResponse := user
+				if test.findByIdError != nil {
+					findByIdResponse = nil
+				}
+				s.userServiceMock.EXPECT().FindById(
+					request.Context(), userId.String(),
+				).Return(findByIdResponse, test.findByIdError)
+			}
+			if test.expectedChanges != nil {
+				s.adminUserServiceMock.EXPECT().Update(
+					request.Context(), user, test.expectedChanges, test.expectedVersion,
+				).Return(test.updateError)
+			}
+
+			s.handler.ServeHTTP(response, request)
+
+			var jsonPayload map[string]interface{}
+			_ = json.Unmarshal(response.Body.Bytes(), &jsonPayload)
+
+			s.Equal(test.expectedPayload, jsonPayload)
+			s.Equal(test.expectedStatusCode, response.Code)
+		})
+	}
+}
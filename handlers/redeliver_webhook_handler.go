@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"verifymy-golang-test/entities"
+	"verifymy-golang-test/models"
+	"verifymy-golang-test/services"
+)
+
+type redeliverWebhookHandler struct {
+	webhookDeliveryService services.WebhookDeliveryService
+}
+
+func NewRedeliverWebhookHandler(
+	webhookDeliveryService services.WebhookDeliveryService,
+) Handler {
+	return &redeliverWebhookHandler{
+		webhookDeliveryService: webhookDeliveryService,
+	}
+}
+
+func (h *redeliverWebhookHandler) Method() []string {
+	return []string{http.MethodPost}
+}
+
+func (h *redeliverWebhookHandler) Route() string {
+	return "/webhooks/{webhook_id}/deliveries/{delivery_id}/redeliver"
+}
+
+func (h *redeliverWebhookHandler) Permission() models.Permission {
+	return models.PermissionManageWebhooks
+}
+
+// ServeHTTP queues the delivery again. It is pushed in the background, so
+// the outcome shows up in the deliveries of the webhook.
+func (h *redeliverWebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	err := h.webhookDeliveryService.Redeliver(
+		r.Context(), params["webhook_id"], params["delivery_id"],
+	)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+
+		if _, ok := err.(*entities.ItemNotFoundError); ok {
+			w.WriteHeader(http.StatusNotFound)
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+			err = entities.NewUnexpectedError(err)
+		}
+
+		jsonPayload, _ := json.Marshal(err)
+		w.Write(jsonPayload)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/suite"
+
+	"verifymy-golang-test/entities"
+	mock_services "verifymy-golang-test/mocks/services"
+	"verifymy-golang-test/models"
+)
+
+type redeliverWebhookHandlerTestSuite struct {
+	suite.Suite
+	ctrl                       *gomock.Controller
+	webhookDeliveryServiceMock *mock_services.MockWebhookDeliveryService
+	handler                    Handler
+}
+
+func TestRedeliverWebhookHandlerTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(redeliverWebhookHandlerTestSuite))
+}
+
+func (s *redeliverWebhookHandlerTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.webhookDeliveryServiceMock = mock_services.NewMockWebhookDeliveryService(s.ctrl)
+	s.handler = NewRedeliverWebhookHandler(s.webhookDeliveryServiceMock)
+}
+
+func (s *redeliverWebhookHandlerTestSuite) TestMethod() {
+	s.Equal([]string{"POST"}, s.handler.Method())
+}
+
+func (s *redeliverWebhookHandlerTestSuite) TestRoute() {
+	s.Equal("/webhooks/{webhook_id}/deliveries/{delivery_id}/redeliver", s.handler.Route())
+}
+
+func (s *redeliverWebhookHandlerTestSuite) TestPermission() {
+	s.Equal(
+		models.PermissionManageWebhooks,
+		s.handler.(AuthorizedHandler).Permission(),
+	)
+}
+
+func (s *redeliverWebhookHandlerTestSuite) TestServeHTTP() {
+	subscriptionId := uuid.NewString()
+	deliveryId := uuid.NewString()
+
+	tests := []struct {
+		description        string
+		redeliverError     error
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			description:        "Success",
+			expectedStatusCode: http.StatusAccepted,
+		},
+		{
+			description:        "Delivery not found",
+			redeliverError:     entities.NewItemNotFoundError("Webhook delivery", deliveryId),
+			expectedStatusCode: http.StatusNotFound,
+			expectedBody:       `{"message":"Webhook delivery not found","details":["` + deliveryId + `"]}`,
+		},
+		{
+			description:        "Unexpected error",
+			redeliverError:     errors.New("error redelivering webhook"),
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedBody:       `{"message":"unexpected error","details":["error redelivering webhook"]}`,
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			request := httptest.NewRequest(
+				http.MethodPost,
+				"/webhooks/"+subscriptionId+"/deliveries/"+deliveryId+"/redeliver",
+				nil,
+			)
+			request = mux.SetURLVars(request, map[string]string{
+				"webhook_id": subscriptionId, "delivery_id": deliveryId,
+			})
+			response := httptest.NewRecorder()
+
+			s.webhookDeliveryServiceMock.EXPECT().Redeliver(
+				request.Context(), subscriptionId, deliveryId,
+			).Return(test.redeliverError)
+
+			s.handler.ServeHTTP(response, request)
+
+			s.Equal(test.expectedStatusCode, response.Code)
+			s.Equal(test.expectedBody, response.Body.String())
+		})
+	}
+}
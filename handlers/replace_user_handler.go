@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"verifymy-golang-test/entities"
+	"verifymy-golang-test/models"
+	"verifymy-golang-test/services"
+)
+
+type replaceUserHandler struct {
+	userService      services.UserService
+	adminUserService services.AdminUserService
+}
+
+func NewReplaceUserHandler(
+	userService services.UserService,
+	adminUserService services.AdminUserService,
+) Handler {
+	return &replaceUserHandler{
+		userService:      userService,
+		adminUserService: adminUserService,
+	}
+}
+
+func (h *replaceUserHandler) Method() []string {
+	return []string{http.MethodPut}
+}
+
+func (h *replaceUserHandler) Route() string {
+	return "/users/{user_id}"
+}
+
+func (h *replaceUserHandler) Permission() models.Permission {
+	return models.PermissionManageUsers
+}
+
+// ServeHTTP replaces every editable field of the user, so fields missing
+// from the payload are cleared or, when required, rejected.
+func (h *replaceUserHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	user, err := h.userService.FindById(r.Context(), mux.Vars(r)["user_id"])
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+
+		if _, ok := err.(*entities.ItemNotFoundError); ok {
+			w.WriteHeader(http.StatusNotFound)
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+			err = entities.NewUnexpectedError(err)
+		}
+
+		jsonPayload, _ := json.Marshal(err)
+		w.Write(jsonPayload)
+		return
+	}
+
+	expectedVersion, ok := checkIfMatch(r, user.Version)
+	if !ok {
+		w.Header().Set("Content-Type", "application/json")
+
+		w.WriteHeader(http.StatusPreconditionFailed)
+		jsonPayload, _ := json.Marshal(entities.NewPreconditionFailedError())
+		w.Write(jsonPayload)
+		return
+	}
+
+	var payload interface{}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		err = entities.NewError("Invalid JSON", []string{err.Error()})
+
+		jsonPayload, _ := json.Marshal(err)
+		w.Write(jsonPayload)
+		return
+	}
+
+	changes, err := userChanges(adminEditableFields, user, payload)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		jsonPayload, _ := json.Marshal(err)
+		w.Write(jsonPayload)
+		return
+	}
+
+	if len(changes) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	err = h.adminUserService.Update(r.Context(), user, changes, expectedVersion)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+
+		if _, ok := err.(*entities.PreconditionFailedError); ok {
+			w.WriteHeader(http.StatusPreconditionFailed)
+		} else if _, ok := err.(*entities.EmailAlreadyInUseError); ok {
+			w.WriteHeader(http.StatusForbidden)
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+			err = entities.NewUnexpectedError(err)
+		}
+
+		jsonPayload, _ := json.Marshal(err)
+		w.Write(jsonPayload)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
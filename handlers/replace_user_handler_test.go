@@ -0,0 +1,212 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/suite"
+
+	"verifymy-golang-test/entities"
+	mock_services "verifymy-golang-test/mocks/services"
+	"verifymy-golang-test/models"
+)
+
+type replaceUserHandlerTestSuite struct {
+	suite.Suite
+	ctrl                 *gomock.Controller
+	userServiceMock      *mock_services.MockUserService
+	adminUserServiceMock *mock_services.MockAdminUserService
+	handler              Handler
+}
+
+func TestReplaceUserHandlerTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(replaceUserHandlerTestSuite))
+}
+
+func (s *replaceUserHandlerTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.userServiceMock = mock_services.NewMockUserService(s.ctrl)
+	s.adminUserServiceMock = mock_services.NewMockAdminUserService(s.ctrl)
+	s.handler = NewReplaceUserHandler(s.userServiceMock, s.adminUserServiceMock)
+}
+
+func (s *replaceUserHandlerTestSuite) TestMethod() {
+	s.Equal([]string{"PUT"}, s.handler.Method())
+}
+
+func (s *replaceUserHandlerTestSuite) TestRoute() {
+	s.Equal("/users/{user_id}", s.handler.Route())
+}
+
+func (s *replaceUserHandlerTestSuite) TestPermission() {
+	s.Equal(
+		models.PermissionManageUsers,
+		s.handler.(AuthorizedHandler).Permission(),
+	)
+}
+
+func (s *replaceUserHandlerTestSuite) TestServeHTTP() {
+	userId := uuid.New()
+	user := &models.User{
+		ID:          userId,
+		Name:        "Peter Parker",
+		DateOfBirth: models.Date(time.Date(2001, 8, 10, 0, 0, 0, 0, time.UTC)),
+		Email:       "peter.parker@nyork.co",
+		Address:     "20 Ingram Street",
+		Role:        models.RoleUser,
+		Version:     5,
+	}
+	replacement := `{"name": "Peter Parker", "email": "peter.parker@nyork.co", "date_of_birth": "2001-08-10", "role": "admin"}`
+
+	tests := []struct {
+		description        string
+		payload            string
+		ifMatch            string
+		findByIdError      error
+		expectedChanges    map[string]interface{}
+		expectedVersion    int64
+		updateError        error
+		expectedStatusCode int
+		expectedPayload    map[string]interface{}
+	}{
+		{
+			description:        "Success",
+			payload:            replacement,
+			ifMatch:            `"5"`,
+			expectedChanges:    map[string]interface{}{"address": "", "role": "admin"},
+			expectedVersion:    5,
+			expectedStatusCode: http.StatusNoContent,
+		},
+		{
+			description:        "Nothing changes",
+			payload:            `{"name": "Peter Parker", "email": "peter.parker@nyork.co", "date_of_birth": "2001-08-10", "address": "20 Ingram Street", "role": "user"}`,
+			expectedStatusCode: http.StatusNoContent,
+		},
+		{
+			description:        "User not found",
+			payload:            replacement,
+			findByIdError:      entities.NewItemNotFoundError("User", userId.String()),
+			expectedStatusCode: http.StatusNotFound,
+			expectedPayload: map[string]interface{}{
+				"message": "User not found",
+				"details": []interface{}{userId.String()},
+			},
+		},
+		{
+			description:        "Error finding user",
+			payload:            replacement,
+			findByIdError:      errors.New("error finding user"),
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedPayload: map[string]interface{}{
+				"message": "unexpected error",
+				"details": []interface{}{"error finding user"},
+			},
+		},
+		{
+			description:        "Stale If-Match",
+			payload:            replacement,
+			ifMatch:            `"4"`,
+			expectedStatusCode: http.StatusPreconditionFailed,
+			expectedPayload: map[string]interface{}{
+				"message": "precondition failed",
+				"details": []interface{}{"resource was modified since it was fetched"},
+			},
+		},
+		{
+			description:        "Invalid JSON",
+			payload:            `{"name": "Peter Parker"`,
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			expectedPayload: map[string]interface{}{
+				"message": "Invalid JSON",
+				"details": []interface{}{"unexpected EOF"},
+			},
+		},
+		{
+			description:        "Missing required field",
+			payload:            `{"name": "Peter Parker", "email": "peter.parker@nyork.co", "date_of_birth": "2001-08-10"}`,
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			expectedPayload: map[string]interface{}{
+				"message": "validation failed",
+				"details": []interface{}{"field role is required"},
+			},
+		},
+		{
+			description:        "Concurrent modification",
+			payload:            replacement,
+			ifMatch:            `"5"`,
+			expectedChanges:    map[string]interface{}{"address": "", "role": "admin"},
+			expectedVersion:    5,
+			updateError:        entities.NewPreconditionFailedError(),
+			expectedStatusCode: http.StatusPreconditionFailed,
+			expectedPayload: map[string]interface{}{
+				"message": "precondition failed",
+				"details": []interface{}{"resource was modified since it was fetched"},
+			},
+		},
+		{
+			description:        "Email already in use",
+			payload:            `{"name": "Peter Parker", "email": "miles.morales@nyork.co", "date_of_birth": "2001-08-10", "address": "20 Ingram Street", "role": "user"}`,
+			expectedChanges:    map[string]interface{}{"email": "miles.morales@nyork.co"},
+			updateError:        entities.NewEmailAlreadyInUseError("miles.morales@nyork.co"),
+			expectedStatusCode: http.StatusForbidden,
+			expectedPayload: map[string]interface{}{
+				"message": "e-mail is already in use",
+				"details": []interface{}{"miles.morales@nyork.co"},
+			},
+		},
+		{
+			description:        "Unexpected error",
+			payload:            replacement,
+			expectedChanges:    map[string]interface{}{"address": "", "role": "admin"},
+			updateError:        errors.New("error updating user"),
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedPayload: map[string]interface{}{
+				"message": "unexpected error",
+				"details": []interface{}{"error updating user"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			request := httptest.NewRequest(
+				http.MethodPut, "/users/"+userId.String(), strings.NewReader(test.payload),
+			)
+			request = mux.SetURLVars(request, map[string]string{"user_id": userId.String()})
+			if test.ifMatch != "" {
+				request.Header.Set("If-Match", test.ifMatch)
+			}
+			response := httptest.NewRecorder()
+
+			findByIdResponse := user
+			if test.findByIdError != nil {
+				findByIdResponse = nil
+			}
+			s.userServiceMock.EXPECT().FindById(
+				request.Context(), userId.String(),
+			).Return(findByIdResponse, test.findByIdError)
+			if test.expectedChanges != nil {
+				s.adminUserServiceMock.EXPECT().Update(
+					request.Context(), user, test.expectedChanges, test.expectedVersion,
+				).Return(test.updateError)
+			}
+
+			s.handler.ServeHTTP(response, request)
+
+			var jsonPayload map[string]interface{}
+			_ = json.Unmarshal(response.Body.Bytes(), &jsonPayload)
+
+			s.Equal(test.expectedPayload, jsonPayload)
+			s.Equal(test.expectedStatusCode, response.Code)
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"verifymy-golang-test/entities"
+	"verifymy-golang-test/services"
+)
+
+type requestDataExportHandler struct {
+	dataExportService services.DataExportService
+}
+
+func NewRequestDataExportHandler(
+	dataExportService services.DataExportService,
+) Handler {
+	return &requestDataExportHandler{
+		dataExportService: dataExportService,
+	}
+}
+
+func (h *requestDataExportHandler) Method() []string {
+	return []string{http.MethodPost}
+}
+
+func (h *requestDataExportHandler) Route() string {
+	return "/profile/exports"
+}
+
+// ServeHTTP queues the export, whose status can be followed through
+// showDataExportHandler until it is ready to download.
+func (h *requestDataExportHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	export, err := h.dataExportService.Request(r.Context())
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		err = entities.NewUnexpectedError(err)
+
+		jsonPayload, _ := json.Marshal(err)
+		w.Write(jsonPayload)
+		return
+	}
+
+	w.Header().Set("Location", "/profile/exports/"+export.ID.String())
+	w.WriteHeader(http.StatusAccepted)
+
+	jsonPayload, _ := json.Marshal(export)
+	w.Write(jsonPayload)
+}
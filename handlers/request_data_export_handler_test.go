@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	mock_services "verifymy-golang-test/mocks/services"
+	"verifymy-golang-test/models"
+)
+
+type requestDataExportHandlerTestSuite struct {
+	suite.Suite
+	ctrl                  *gomock.Controller
+	dataExportServiceMock *mock_services.MockDataExportService
+	handler               Handler
+}
+
+func TestRequestDataExportHandlerTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(requestDataExportHandlerTestSuite))
+}
+
+func (s *requestDataExportHandlerTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.dataExportServiceMock = mock_services.NewMockDataExportService(s.ctrl)
+	s.handler = NewRequestDataExportHandler(s.dataExportServiceMock)
+}
+
+func (s *requestDataExportHandlerTestSuite) TestMethod() {
+	s.Equal([]string{"POST"}, s.handler.Method())
+}
+
+func (s *requestDataExportHandlerTestSuite) TestRoute() {
+	s.Equal("/profile/exports", s.handler.Route())
+}
+
+func (s *requestDataExportHandlerTestSuite) TestServeHTTP() {
+	exportId := uuid.MustParse("0b7c5a41-5bd6-4a7b-9d4e-8f2b4c1e7a10")
+	createdAt := time.Date(2023, 6, 30, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		description        string
+		export             *models.DataExport
+		requestError       error
+		expectedStatusCode int
+		expectedLocation   string
+		expectedBody       string
+	}{
+		{
+			description: "Success",
+			export: &models.DataExport{
+				ID:        exportId,
+				Status:    models.DataExportStatusPending,
+				CreatedAt: createdAt,
+			},
+			expectedStatusCode: http.StatusAccepted,
+			expectedLocation:   "/profile/exports/" + exportId.String(),
+			expectedBody:       `{"id":"` + exportId.String() + `","status":"pending","created_at":"2023-06-30T12:00:00Z"}`,
+		},
+		{
+			description:        "Unexpected error",
+			requestError:       errors.New("database error"),
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedBody:       `{"message":"unexpected error","details":["database error"]}`,
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			request := httptest.NewRequest(http.MethodPost, "/profile/exports", nil)
+			response := httptest.NewRecorder()
+
+			s.dataExportServiceMock.EXPECT().Request(
+				request.Context(),
+			).Return(test.export, test.requestError)
+
+			s.handler.ServeHTTP(response, request)
+
+			s.Equal(test.expectedBody, response.Body.String())
+			s.Equal(test.expectedStatusCode, response.Code)
+			s.Equal(test.expectedLocation, response.Header().Get("Location"))
+		})
+	}
+}
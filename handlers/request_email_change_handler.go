@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"verifymy-golang-test/entities"
+	"verifymy-golang-test/services"
+)
+
+type requestEmailChangeHandler struct {
+	userService services.UserService
+}
+
+func NewRequestEmailChangeHandler(
+	userService services.UserService,
+) Handler {
+	return &requestEmailChangeHandler{
+		userService: userService,
+	}
+}
+
+func (h *requestEmailChangeHandler) Method() []string {
+	return []string{http.MethodPost}
+}
+
+func (h *requestEmailChangeHandler) Route() string {
+	return "/profile/email"
+}
+
+func (h *requestEmailChangeHandler) RequiresSudo() bool {
+	return true
+}
+
+// ServeHTTP only requests the change, which is applied once the new address
+// is confirmed, see confirmEmailChangeHandler.
+func (h *requestEmailChangeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var payload map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		err = entities.NewError("Invalid JSON", []string{err.Error()})
+
+		jsonPayload, _ := json.Marshal(err)
+		w.Write(jsonPayload)
+		return
+	}
+
+	email, err := emailField.parse(payload["email"])
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		err = entities.NewValidationError(fmt.Sprintf("field email %s", err.Error()))
+
+		jsonPayload, _ := json.Marshal(err)
+		w.Write(jsonPayload)
+		return
+	}
+
+	if err := h.userService.RequestEmailChange(r.Context(), email.(string)); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+
+		if _, ok := err.(*entities.EmailAlreadyInUseError); ok {
+			w.WriteHeader(http.StatusForbidden)
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+			err = entities.NewUnexpectedError(err)
+		}
+
+		jsonPayload, _ := json.Marshal(err)
+		w.Write(jsonPayload)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
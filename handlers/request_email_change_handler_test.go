@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/suite"
+
+	"verifymy-golang-test/common"
+	"verifymy-golang-test/entities"
+	mock_services "verifymy-golang-test/mocks/services"
+	"verifymy-golang-test/models"
+)
+
+type requestEmailChangeHandlerTestSuite struct {
+	suite.Suite
+	ctrl            *gomock.Controller
+	userServiceMock *mock_services.MockUserService
+	handler         Handler
+}
+
+func TestRequestEmailChangeHandlerTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(requestEmailChangeHandlerTestSuite))
+}
+
+func (s *requestEmailChangeHandlerTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.userServiceMock = mock_services.NewMockUserService(s.ctrl)
+	s.handler = NewRequestEmailChangeHandler(s.userServiceMock)
+}
+
+func (s *requestEmailChangeHandlerTestSuite) TestMethod() {
+	s.Equal([]string{"POST"}, s.handler.Method())
+}
+
+func (s *requestEmailChangeHandlerTestSuite) TestRoute() {
+	s.Equal("/profile/email", s.handler.Route())
+}
+
+func (s *requestEmailChangeHandlerTestSuite) TestRequiresSudo() {
+	s.True(s.handler.(SudoHandler).RequiresSudo())
+}
+
+func (s *requestEmailChangeHandlerTestSuite) TestServeHTTP() {
+	tests := []struct {
+		description        string
+		payload            string
+		skipRequest        bool
+		requestError       error
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			description:        "Success",
+			payload:            `{"email": "spidey@nyork.co"}`,
+			expectedStatusCode: http.StatusAccepted,
+		},
+		{
+			description:        "Invalid JSON",
+			payload:            `{"email": "spidey@nyork.co"`,
+			skipRequest:        true,
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			expectedBody:       `{"message":"Invalid JSON","details":["unexpected EOF"]}`,
+		},
+		{
+			description:        "Invalid email",
+			payload:            `{"email": "spidey"}`,
+			skipRequest:        true,
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			expectedBody:       `{"message":"validation failed","details":["field email must be a valid email address"]}`,
+		},
+		{
+			description:        "Missing email",
+			payload:            `{}`,
+			skipRequest:        true,
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			expectedBody:       `{"message":"validation failed","details":["field email must be a string"]}`,
+		},
+		{
+			description:        "Email already in use",
+			payload:            `{"email": "spidey@nyork.co"}`,
+			requestError:       entities.NewEmailAlreadyInUseError("spidey@nyork.co"),
+			expectedStatusCode: http.StatusForbidden,
+			expectedBody:       `{"message":"e-mail is already in use","details":["spidey@nyork.co"]}`,
+		},
+		{
+			description:        "Unexpected error",
+			payload:            `{"email": "spidey@nyork.co"}`,
+			requestError:       errors.New("error sending email"),
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedBody:       `{"message":"unexpected error","details":["error sending email"]}`,
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			request := httptest.NewRequest(
+				http.MethodPost, "/profile/email", strings.NewReader(test.payload),
+			)
+			request = request.WithContext(context.WithValue(
+				request.Context(), common.AuthUser, &models.User{Email: "peter.parker@nyork.co"},
+			))
+			response := httptest.NewRecorder()
+
+			if !test.skipRequest {
+				s.userServiceMock.EXPECT().RequestEmailChange(
+					request.Context(), "spidey@nyork.co",
+				).Return(test.requestError)
+			}
+
+			s.handler.ServeHTTP(response, request)
+
+			s.Equal(test.expectedBody, response.Body.String())
+			s.Equal(test.expectedStatusCode, response.Code)
+		})
+	}
+}
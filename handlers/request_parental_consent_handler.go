@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"verifymy-golang-test/entities"
+	"verifymy-golang-test/services"
+)
+
+type requestParentalConsentHandler struct {
+	parentalConsentService services.ParentalConsentService
+}
+
+func NewRequestParentalConsentHandler(
+	parentalConsentService services.ParentalConsentService,
+) Handler {
+	return &requestParentalConsentHandler{
+		parentalConsentService: parentalConsentService,
+	}
+}
+
+func (h *requestParentalConsentHandler) Method() []string {
+	return []string{http.MethodPost}
+}
+
+func (h *requestParentalConsentHandler) Route() string {
+	return "/profile/parental_consent"
+}
+
+func (h *requestParentalConsentHandler) AllowsPendingConsent() bool {
+	return true
+}
+
+// ServeHTTP sends the consent request of the signed in user again, to the
+// guardian in the payload, see services.ParentalConsentService.Resend
+func (h *requestParentalConsentHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var payload map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		err = entities.NewError("Invalid JSON", []string{err.Error()})
+
+		jsonPayload, _ := json.Marshal(err)
+		w.Write(jsonPayload)
+		return
+	}
+
+	guardianEmail, err := emailField.parse(payload["guardian_email"])
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		err = entities.NewValidationError(fmt.Sprintf("field guardian_email %s", err.Error()))
+
+		jsonPayload, _ := json.Marshal(err)
+		w.Write(jsonPayload)
+		return
+	}
+
+	if err := h.parentalConsentService.Resend(r.Context(), guardianEmail.(string)); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+
+		if _, ok := err.(*entities.ValidationError); ok {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+		} else if _, ok := err.(*entities.PreconditionFailedError); ok {
+			w.WriteHeader(http.StatusPreconditionFailed)
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+			err = entities.NewUnexpectedError(err)
+		}
+
+		jsonPayload, _ := json.Marshal(err)
+		w.Write(jsonPayload)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/suite"
+
+	"verifymy-golang-test/common"
+	"verifymy-golang-test/entities"
+	mock_services "verifymy-golang-test/mocks/services"
+	"verifymy-golang-test/models"
+)
+
+type requestParentalConsentHandlerTestSuite struct {
+	suite.Suite
+	ctrl                       *gomock.Controller
+	parentalConsentServiceMock *mock_services.MockParentalConsentService
+	handler                    Handler
+}
+
+func TestRequestParentalConsentHandlerTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(requestParentalConsentHandlerTestSuite))
+}
+
+func (s *requestParentalConsentHandlerTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.parentalConsentServiceMock = mock_services.NewMockParentalConsentService(s.ctrl)
+	s.handler = NewRequestParentalConsentHandler(s.parentalConsentServiceMock)
+}
+
+func (s *requestParentalConsentHandlerTestSuite) TestMethod() {
+	s.Equal([]string{"POST"}, s.handler.Method())
+}
+
+func (s *requestParentalConsentHandlerTestSuite) TestRoute() {
+	s.Equal("/profile/parental_consent", s.handler.Route())
+}
+
+func (s *requestParentalConsentHandlerTestSuite) TestAllowsPendingConsent() {
+	s.True(s.handler.(PendingConsentHandler).AllowsPendingConsent())
+}
+
+func (s *requestParentalConsentHandlerTestSuite) TestServeHTTP() {
+	tests := []struct {
+		description        string
+		payload            string
+		skipResend         bool
+		resendError        error
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			description:        "Success",
+			payload:            `{"guardian_email": "rio.morales@nyork.co"}`,
+			expectedStatusCode: http.StatusAccepted,
+		},
+		{
+			description:        "Invalid JSON",
+			payload:            `{"guardian_email": "rio.morales@nyork.co"`,
+			skipResend:         true,
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			expectedBody:       `{"message":"Invalid JSON","details":["unexpected EOF"]}`,
+		},
+		{
+			description:        "Invalid email",
+			payload:            `{"guardian_email": "rio"}`,
+			skipResend:         true,
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			expectedBody:       `{"message":"validation failed","details":["field guardian_email must be a valid email address"]}`,
+		},
+		{
+			description:        "Not pending",
+			payload:            `{"guardian_email": "rio.morales@nyork.co"}`,
+			resendError:        entities.NewValidationError("parental consent is not pending"),
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			expectedBody:       `{"message":"validation failed","details":["parental consent is not pending"]}`,
+		},
+		{
+			description:        "User changed meanwhile",
+			payload:            `{"guardian_email": "rio.morales@nyork.co"}`,
+			resendError:        entities.NewPreconditionFailedError(),
+			expectedStatusCode: http.StatusPreconditionFailed,
+			expectedBody:       `{"message":"precondition failed","details":["resource was modified since it was fetched"]}`,
+		},
+		{
+			description:        "Unexpected error",
+			payload:            `{"guardian_email": "rio.morales@nyork.co"}`,
+			resendError:        errors.New("error sending email"),
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedBody:       `{"message":"unexpected error","details":["error sending email"]}`,
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			request := httptest.NewRequest(
+				http.MethodPost, "/profile/parental_consent", strings.NewReader(test.payload),
+			)
+			request = request.WithContext(context.WithValue(
+				request.Context(), common.AuthUser, &models.User{Email: "miles.morales@nyork.co"},
+			))
+			response := httptest.NewRecorder()
+
+			if !test.skipResend {
+				s.parentalConsentServiceMock.EXPECT().Resend(
+					request.Context(), "rio.morales@nyork.co",
+				).Return(test.resendError)
+			}
+
+			s.handler.ServeHTTP(response, request)
+
+			s.Equal(test.expectedBody, response.Body.String())
+			s.Equal(test.expectedStatusCode, response.Code)
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"verifymy-golang-test/entities"
+	"verifymy-golang-test/utils"
+)
+
+func parseProjection(
+	r *http.Request, allowedFields []interface{}, allowedIncludes []interface{},
+) (entities.Projection, error) {
+	var projection entities.Projection
+
+	for _, field := range splitList(r.URL.Query().Get("fields")) {
+		if !utils.SliceContains(allowedFields, field) {
+			return projection, entities.NewInvalidParameterError(
+				"fields", "unknown field "+field,
+			)
+		}
+
+		projection.Fields = append(projection.Fields, field)
+	}
+
+	for _, include := range splitList(r.URL.Query().Get("include")) {
+		if !utils.SliceContains(allowedIncludes, include) {
+			return projection, entities.NewInvalidParameterError(
+				"include", "unknown include "+include,
+			)
+		}
+
+		projection.Includes = append(projection.Includes, include)
+	}
+
+	return projection, nil
+}
+
+// shapeResource renders resource as a JSON object holding only the
+// projected fields, with the included related resources embedded. Pass
+// models by pointer so their pointer receiver marshalers, like the one
+// hiding SecretValue, are used.
+func shapeResource(
+	resource interface{}, projection entities.Projection, included map[string]interface{},
+) map[string]interface{} {
+	jsonPayload, _ := json.Marshal(resource)
+
+	var shaped map[string]interface{}
+	_ = json.Unmarshal(jsonPayload, &shaped)
+
+	if len(projection.Fields) > 0 {
+		for key := range shaped {
+			if !containsString(projection.Fields, key) {
+				delete(shaped, key)
+			}
+		}
+	}
+
+	for name, value := range included {
+		shaped[name] = value
+	}
+
+	return shaped
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+func containsString(slice []string, element string) bool {
+	for _, item := range slice {
+		if item == element {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"verifymy-golang-test/entities"
+	"verifymy-golang-test/models"
+	"verifymy-golang-test/services"
+)
+
+type restoreUserHandler struct {
+	adminUserService services.AdminUserService
+}
+
+func NewRestoreUserHandler(
+	adminUserService services.AdminUserService,
+) Handler {
+	return &restoreUserHandler{
+		adminUserService: adminUserService,
+	}
+}
+
+func (h *restoreUserHandler) Method() []string {
+	return []string{http.MethodPost}
+}
+
+func (h *restoreUserHandler) Route() string {
+	return "/users/{user_id}/restore"
+}
+
+func (h *restoreUserHandler) Permission() models.Permission {
+	return models.PermissionManageUsers
+}
+
+func (h *restoreUserHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := h.adminUserService.Restore(r.Context(), mux.Vars(r)["user_id"]); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+
+		if _, ok := err.(*entities.ItemNotFoundError); ok {
+			w.WriteHeader(http.StatusNotFound)
+		} else if _, ok := err.(*entities.EmailAlreadyInUseError); ok {
+			w.WriteHeader(http.StatusForbidden)
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+			err = entities.NewUnexpectedError(err)
+		}
+
+		jsonPayload, _ := json.Marshal(err)
+		w.Write(jsonPayload)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/suite"
+
+	"verifymy-golang-test/entities"
+	mock_services "verifymy-golang-test/mocks/services"
+	"verifymy-golang-test/models"
+)
+
+type restoreUserHandlerTestSuite struct {
+	suite.Suite
+	ctrl                 *gomock.Controller
+	adminUserServiceMock *mock_services.MockAdminUserService
+	handler              Handler
+}
+
+func TestRestoreUserHandlerTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(restoreUserHandlerTestSuite))
+}
+
+func (s *restoreUserHandlerTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.adminUserServiceMock = mock_services.NewMockAdminUserService(s.ctrl)
+	s.handler = NewRestoreUserHandler(s.adminUserServiceMock)
+}
+
+func (s *restoreUserHandlerTestSuite) TestMethod() {
+	s.Equal([]string{"POST"}, s.handler.Method())
+}
+
+func (s *restoreUserHandlerTestSuite) TestRoute() {
+	s.Equal("/users/{user_id}/restore", s.handler.Route())
+}
+
+func (s *restoreUserHandlerTestSuite) TestPermission() {
+	s.Equal(
+		models.PermissionManageUsers,
+		s.handler.(AuthorizedHandler).Permission(),
+	)
+}
+
+func (s *restoreUserHandlerTestSuite) TestServeHTTP() {
+	userId := uuid.New()
+
+	tests := []struct {
+		description        string
+		restoreError       error
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			description:        "Success",
+			expectedStatusCode: http.StatusNoContent,
+		},
+		{
+			description:        "Deleted user not found",
+			restoreError:       entities.NewItemNotFoundError("Deleted user", userId.String()),
+			expectedStatusCode: http.StatusNotFound,
+			expectedBody: fmt.Sprintf(
+				`{"message":"Deleted user not found","details":["%s"]}`, userId.String(),
+			),
+		},
+		{
+			description:        "Email registered again",
+			restoreError:       entities.NewEmailAlreadyInUseError("peter.parker@nyork.co"),
+			expectedStatusCode: http.StatusForbidden,
+			expectedBody:       `{"message":"e-mail is already in use","details":["peter.parker@nyork.co"]}`,
+		},
+		{
+			description:        "Unexpected error",
+			restoreError:       errors.New("error restoring user"),
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedBody:       `{"message":"unexpected error","details":["error restoring user"]}`,
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			request := httptest.NewRequest(
+				http.MethodPost, "/users/"+userId.String()+"/restore", nil,
+			)
+			request = mux.SetURLVars(request, map[string]string{"user_id": userId.String()})
+			response := httptest.NewRecorder()
+
+			s.adminUserServiceMock.EXPECT().Restore(
+				request.Context(), userId.String(),
+			).Return(test.restoreError)
+
+			s.handler.ServeHTTP(response, request)
+
+			s.Equal(test.expectedStatusCode, response.Code)
+			s.Equal(test.expectedBody, response.Body.String())
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"net/http"
+
+	"verifymy-golang-test/services"
+)
+
+type revokeOAuthTokenHandler struct {
+	oauthService services.OAuthService
+}
+
+func NewRevokeOAuthTokenHandler(
+	oauthService services.OAuthService,
+) Handler {
+	return &revokeOAuthTokenHandler{
+		oauthService: oauthService,
+	}
+}
+
+func (h *revokeOAuthTokenHandler) Method() []string {
+	return []string{http.MethodPost}
+}
+
+func (h *revokeOAuthTokenHandler) Route() string {
+	return "/oauth/revoke"
+}
+
+// ServeHTTP answers 200 whether or not the token was known, RFC 7009
+func (h *revokeOAuthTokenHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	form, credentials, err := oauthClientCredentials(r)
+	if err != nil {
+		writeOAuthError(w, err)
+		return
+	}
+
+	if err := h.oauthService.Revoke(r.Context(), credentials, form.Get("token")); err != nil {
+		writeOAuthError(w, err)
+		return
+	}
+
+	w.Write([]byte(`{}`))
+}
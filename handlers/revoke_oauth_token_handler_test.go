@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/suite"
+
+	"verifymy-golang-test/entities"
+	mock_services "verifymy-golang-test/mocks/services"
+)
+
+type revokeOAuthTokenHandlerTestSuite struct {
+	suite.Suite
+	ctrl             *gomock.Controller
+	oauthServiceMock *mock_services.MockOAuthService
+	handler          Handler
+}
+
+func TestRevokeOAuthTokenHandlerTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(revokeOAuthTokenHandlerTestSuite))
+}
+
+func (s *revokeOAuthTokenHandlerTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.oauthServiceMock = mock_services.NewMockOAuthService(s.ctrl)
+	s.handler = NewRevokeOAuthTokenHandler(s.oauthServiceMock)
+}
+
+func (s *revokeOAuthTokenHandlerTestSuite) TestMethod() {
+	s.Equal([]string{"POST"}, s.handler.Method())
+}
+
+func (s *revokeOAuthTokenHandlerTestSuite) TestRoute() {
+	s.Equal("/oauth/revoke", s.handler.Route())
+}
+
+func (s *revokeOAuthTokenHandlerTestSuite) TestServeHTTP() {
+	tests := []struct {
+		description        string
+		revokeError        error
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			description:        "Revoked",
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `{}`,
+		},
+		{
+			description: "Invalid client",
+			revokeError: entities.NewOAuthError(
+				entities.OAuthErrorInvalidClient, "client authentication failed",
+			),
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedBody:       `{"error":"invalid_client","error_description":"client authentication failed"}`,
+		},
+		{
+			description:        "Unexpected error",
+			revokeError:        errors.New("database unavailable"),
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedBody:       `{"message":"unexpected error","details":["database unavailable"]}`,
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			request := httptest.NewRequest(
+				http.MethodPost, "/oauth/revoke", strings.NewReader("token=refresh-token"),
+			)
+			request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			request.SetBasicAuth("client-id", "client-secret")
+			response := httptest.NewRecorder()
+
+			s.oauthServiceMock.EXPECT().Revoke(
+				request.Context(),
+				entities.OAuthClientCredentials{ClientID: "client-id", ClientSecret: "client-secret"},
+				"refresh-token",
+			).Return(test.revokeError)
+
+			s.handler.ServeHTTP(response, request)
+
+			s.Equal(test.expectedStatusCode, response.Code)
+			s.JSONEq(test.expectedBody, response.Body.String())
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"verifymy-golang-test/entities"
+	"verifymy-golang-test/models"
+	"verifymy-golang-test/services"
+)
+
+type rotateClientSecretHandler struct {
+	clientService services.ClientService
+}
+
+func NewRotateClientSecretHandler(clientService services.ClientService) Handler {
+	return &rotateClientSecretHandler{clientService: clientService}
+}
+
+func (h *rotateClientSecretHandler) Method() []string {
+	return []string{http.MethodPost}
+}
+
+func (h *rotateClientSecretHandler) Route() string {
+	return "/clients/{client_id}/secret"
+}
+
+func (h *rotateClientSecretHandler) Permission() models.Permission {
+	return models.PermissionManageClients
+}
+
+// ServeHTTP replaces the secret of the client, the previous one stops working
+// right away
+func (h *rotateClientSecretHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	secret, err := h.clientService.RotateSecret(r.Context(), mux.Vars(r)["client_id"])
+	if err != nil {
+		if _, ok := err.(*entities.ItemNotFoundError); ok {
+			w.WriteHeader(http.StatusNotFound)
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+			err = entities.NewUnexpectedError(err)
+		}
+
+		jsonPayload, _ := json.Marshal(err)
+		w.Write(jsonPayload)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+
+	jsonPayload, _ := json.Marshal(map[string]string{"client_secret": secret})
+	w.Write(jsonPayload)
+}
@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/suite"
+
+	"verifymy-golang-test/entities"
+	mock_services "verifymy-golang-test/mocks/services"
+	"verifymy-golang-test/models"
+)
+
+type rotateClientSecretHandlerTestSuite struct {
+	suite.Suite
+	ctrl              *gomock.Controller
+	clientServiceMock *mock_services.MockClientService
+	handler           Handler
+}
+
+func TestRotateClientSecretHandlerTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(rotateClientSecretHandlerTestSuite))
+}
+
+func (s *rotateClientSecretHandlerTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.clientServiceMock = mock_services.NewMockClientService(s.ctrl)
+	s.handler = NewRotateClientSecretHandler(s.clientServiceMock)
+}
+
+func (s *rotateClientSecretHandlerTestSuite) TestMethod() {
+	s.Equal([]string{"POST"}, s.handler.Method())
+}
+
+func (s *rotateClientSecretHandlerTestSuite) TestRoute() {
+	s.Equal("/clients/{client_id}/secret", s.handler.Route())
+}
+
+func (s *rotateClientSecretHandlerTestSuite) TestPermission() {
+	s.Equal(
+		models.PermissionManageClients,
+		s.handler.(AuthorizedHandler).Permission(),
+	)
+}
+
+func (s *rotateClientSecretHandlerTestSuite) TestServeHTTP() {
+	clientId := uuid.New()
+
+	tests := []struct {
+		description        string
+		secret             string
+		rotateError        error
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			description:        "Success",
+			secret:             "new-client-secret",
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `{"client_secret":"new-client-secret"}`,
+		},
+		{
+			description:        "Client not found",
+			rotateError:        entities.NewItemNotFoundError("Client", clientId.String()),
+			expectedStatusCode: http.StatusNotFound,
+			expectedBody:       `{"message":"Client not found","details":["` + clientId.String() + `"]}`,
+		},
+		{
+			description:        "Unexpected error",
+			rotateError:        errors.New("error rotating secret"),
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedBody:       `{"message":"unexpected error","details":["error rotating secret"]}`,
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			request := httptest.NewRequest(
+				http.MethodPost, "/clients/"+clientId.String()+"/secret", nil,
+			)
+			request = mux.SetURLVars(request, map[string]string{"client_id": clientId.String()})
+			response := httptest.NewRecorder()
+
+			s.clientServiceMock.EXPECT().RotateSecret(
+				request.Context(), clientId.String(),
+			).Return(test.secret, test.rotateError)
+
+			s.handler.ServeHTTP(response, request)
+
+			s.Equal(test.expectedStatusCode, response.Code)
+			s.JSONEq(test.expectedBody, response.Body.String())
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"verifymy-golang-test/entities"
+	"verifymy-golang-test/services"
+)
+
+type showAgeRuleHandler struct {
+	ageRuleEngine services.AgeRuleEngine
+}
+
+func NewShowAgeRuleHandler(
+	ageRuleEngine services.AgeRuleEngine,
+) Handler {
+	return &showAgeRuleHandler{
+		ageRuleEngine: ageRuleEngine,
+	}
+}
+
+func (h *showAgeRuleHandler) Method() []string {
+	return []string{http.MethodGet}
+}
+
+func (h *showAgeRuleHandler) Route() string {
+	return "/age_rules"
+}
+
+// ServeHTTP resolves the rule applying to the country and category in the
+// query. It is public, so clients can tell which verification their users
+// need before signing them in.
+func (h *showAgeRuleHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	category := r.URL.Query().Get("category")
+	if category == "" {
+		w.WriteHeader(http.StatusBadRequest)
+
+		jsonPayload, _ := json.Marshal(
+			entities.NewInvalidParameterError("category", "is required"),
+		)
+		w.Write(jsonPayload)
+		return
+	}
+
+	rule, err := h.ageRuleEngine.Resolve(r.URL.Query().Get("country"), category)
+	if err != nil {
+		if _, ok := err.(*entities.InvalidParameterError); ok {
+			w.WriteHeader(http.StatusBadRequest)
+		} else if _, ok := err.(*entities.ItemNotFoundError); ok {
+			w.WriteHeader(http.StatusNotFound)
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+			err = entities.NewUnexpectedError(err)
+		}
+
+		jsonPayload, _ := json.Marshal(err)
+		w.Write(jsonPayload)
+		return
+	}
+
+	jsonPayload, _ := json.Marshal(rule)
+	w.Write(jsonPayload)
+}
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/suite"
+
+	"verifymy-golang-test/entities"
+	mock_services "verifymy-golang-test/mocks/services"
+)
+
+type showAgeRuleHandlerTestSuite struct {
+	suite.Suite
+	ctrl              *gomock.Controller
+	ageRuleEngineMock *mock_services.MockAgeRuleEngine
+	handler           Handler
+}
+
+func TestShowAgeRuleHandlerTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(showAgeRuleHandlerTestSuite))
+}
+
+func (s *showAgeRuleHandlerTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.ageRuleEngineMock = mock_services.NewMockAgeRuleEngine(s.ctrl)
+	s.handler = NewShowAgeRuleHandler(s.ageRuleEngineMock)
+}
+
+func (s *showAgeRuleHandlerTestSuite) TestMethod() {
+	s.Equal([]string{"GET"}, s.handler.Method())
+}
+
+func (s *showAgeRuleHandlerTestSuite) TestRoute() {
+	s.Equal("/age_rules", s.handler.Route())
+}
+
+func (s *showAgeRuleHandlerTestSuite) TestServeHTTP() {
+	tests := []struct {
+		description        string
+		query              string
+		skipResolve        bool
+		rule               *entities.AgeRule
+		resolveError       error
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			description: "Success",
+			query:       "?country=US&category=alcohol",
+			rule: &entities.AgeRule{
+				Country: "US", Category: "alcohol", MinimumAge: 21, Methods: []string{"document"},
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `{"country":"US","category":"alcohol","minimum_age":21,"methods":["document"]}`,
+		},
+		{
+			description:        "Missing category",
+			query:              "?country=US",
+			skipResolve:        true,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       `{"message":"invalid category parameter","details":["is required"]}`,
+		},
+		{
+			description: "Invalid country",
+			query:       "?country=USA&category=alcohol",
+			resolveError: entities.NewInvalidParameterError(
+				"country", "must be an ISO 3166-1 alpha-2 code",
+			),
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       `{"message":"invalid country parameter","details":["must be an ISO 3166-1 alpha-2 code"]}`,
+		},
+		{
+			description:        "Unknown category",
+			query:              "?country=US&category=alcohol",
+			resolveError:       entities.NewItemNotFoundError("Age rule", "alcohol"),
+			expectedStatusCode: http.StatusNotFound,
+			expectedBody:       `{"message":"Age rule not found","details":["alcohol"]}`,
+		},
+		{
+			description:        "Unexpected error",
+			query:              "?country=US&category=alcohol",
+			resolveError:       errors.New("rules unavailable"),
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedBody:       `{"message":"unexpected error","details":["rules unavailable"]}`,
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			request := httptest.NewRequest(http.MethodGet, "/age_rules"+test.query, nil)
+			response := httptest.NewRecorder()
+
+			if !test.skipResolve {
+				s.ageRuleEngineMock.EXPECT().Resolve(
+					request.URL.Query().Get("country"), "alcohol",
+				).Return(test.rule, test.resolveError)
+			}
+
+			s.handler.ServeHTTP(response, request)
+
+			s.Equal(test.expectedStatusCode, response.Code)
+			s.JSONEq(test.expectedBody, response.Body.String())
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"verifymy-golang-test/common"
+	"verifymy-golang-test/entities"
+	"verifymy-golang-test/models"
+	"verifymy-golang-test/services"
+)
+
+type showAgeVerificationHandler struct {
+	ageVerificationService services.AgeVerificationService
+}
+
+func NewShowAgeVerificationHandler(
+	ageVerificationService services.AgeVerificationService,
+) Handler {
+	return &showAgeVerificationHandler{
+		ageVerificationService: ageVerificationService,
+	}
+}
+
+func (h *showAgeVerificationHandler) Method() []string {
+	return []string{http.MethodGet}
+}
+
+func (h *showAgeVerificationHandler) Route() string {
+	return "/profile/age_verification"
+}
+
+func (h *showAgeVerificationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	user := r.Context().Value(common.AuthUser).(*models.User)
+
+	verification, err := h.ageVerificationService.FindByUserId(r.Context(), user.ID.String())
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		err = entities.NewUnexpectedError(err)
+
+		jsonPayload, _ := json.Marshal(err)
+		w.Write(jsonPayload)
+		return
+	}
+
+	jsonPayload, _ := json.Marshal(verification)
+	w.Write(jsonPayload)
+}
@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	"verifymy-golang-test/common"
+	mock_services "verifymy-golang-test/mocks/services"
+	"verifymy-golang-test/models"
+)
+
+type showAgeVerificationHandlerTestSuite struct {
+	suite.Suite
+	ctrl                       *gomock.Controller
+	ageVerificationServiceMock *mock_services.MockAgeVerificationService
+	handler                    Handler
+}
+
+func TestShowAgeVerificationHandlerTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(showAgeVerificationHandlerTestSuite))
+}
+
+func (s *showAgeVerificationHandlerTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.ageVerificationServiceMock = mock_services.NewMockAgeVerificationService(s.ctrl)
+	s.handler = NewShowAgeVerificationHandler(s.ageVerificationServiceMock)
+}
+
+func (s *showAgeVerificationHandlerTestSuite) TestMethod() {
+	s.Equal([]string{"GET"}, s.handler.Method())
+}
+
+func (s *showAgeVerificationHandlerTestSuite) TestRoute() {
+	s.Equal("/profile/age_verification", s.handler.Route())
+}
+
+func (s *showAgeVerificationHandlerTestSuite) TestServeHTTP() {
+	user := &models.User{ID: uuid.MustParse("6f1c2d3e-4b5a-4c7d-8e9f-0a1b2c3d4e5f")}
+	verifiedAt := time.Date(2023, 6, 30, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		description        string
+		verification       *models.AgeVerification
+		findError          error
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			description: "Unverified",
+			verification: &models.AgeVerification{
+				UserID: user.ID.String(),
+				Status: models.AgeVerificationStatusUnverified,
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `{"age_over":null,"assurance_level":null,"expires_at":null,"method":null,"status":"unverified","verified_at":null}`,
+		},
+		{
+			description: "Verified",
+			verification: &models.AgeVerification{
+				UserID:         user.ID.String(),
+				Status:         models.AgeVerificationStatusVerified,
+				Method:         "document",
+				AssuranceLevel: models.AssuranceLevelHigh,
+				AgeOver:        18,
+				VerifiedAt:     sql.NullTime{Time: verifiedAt, Valid: true},
+				ExpiresAt:      sql.NullTime{Time: verifiedAt.AddDate(1, 0, 0), Valid: true},
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `{"age_over":18,"assurance_level":"high","expires_at":"2024-06-30T12:00:00Z","method":"document","status":"verified","verified_at":"2023-06-30T12:00:00Z"}`,
+		},
+		{
+			description:        "Unexpected error",
+			findError:          errors.New("database error"),
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedBody:       `{"message":"unexpected error","details":["database error"]}`,
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			request := httptest.NewRequest(http.MethodGet, "/profile/age_verification", nil)
+			request = request.WithContext(
+				context.WithValue(request.Context(), common.AuthUser, user),
+			)
+			response := httptest.NewRecorder()
+
+			s.ageVerificationServiceMock.EXPECT().FindByUserId(
+				request.Context(), user.ID.String(),
+			).Return(test.verification, test.findError)
+
+			s.handler.ServeHTTP(response, request)
+
+			s.Equal(test.expectedBody, response.Body.String())
+			s.Equal(test.expectedStatusCode, response.Code)
+		})
+	}
+}
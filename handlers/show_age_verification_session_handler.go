@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"verifymy-golang-test/entities"
+	"verifymy-golang-test/services"
+)
+
+type showAgeVerificationSessionHandler struct {
+	ageVerificationSessionService services.AgeVerificationSessionService
+}
+
+func NewShowAgeVerificationSessionHandler(
+	ageVerificationSessionService services.AgeVerificationSessionService,
+) Handler {
+	return &showAgeVerificationSessionHandler{
+		ageVerificationSessionService: ageVerificationSessionService,
+	}
+}
+
+func (h *showAgeVerificationSessionHandler) Method() []string {
+	return []string{http.MethodGet}
+}
+
+func (h *showAgeVerificationSessionHandler) Route() string {
+	return "/age_verifications/{age_verification_id}"
+}
+
+func (h *showAgeVerificationSessionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	session, err := h.ageVerificationSessionService.FindById(
+		r.Context(), mux.Vars(r)["age_verification_id"],
+	)
+	if err != nil {
+		if _, ok := err.(*entities.ItemNotFoundError); ok {
+			w.WriteHeader(http.StatusNotFound)
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+			err = entities.NewUnexpectedError(err)
+		}
+
+		jsonPayload, _ := json.Marshal(err)
+		w.Write(jsonPayload)
+		return
+	}
+
+	jsonPayload, _ := json.Marshal(session)
+	w.Write(jsonPayload)
+}
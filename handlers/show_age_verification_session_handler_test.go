@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/suite"
+
+	"verifymy-golang-test/entities"
+	mock_services "verifymy-golang-test/mocks/services"
+	"verifymy-golang-test/models"
+)
+
+type showAgeVerificationSessionHandlerTestSuite struct {
+	suite.Suite
+	ctrl                              *gomock.Controller
+	ageVerificationSessionServiceMock *mock_services.MockAgeVerificationSessionService
+	handler                           Handler
+}
+
+func TestShowAgeVerificationSessionHandlerTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(showAgeVerificationSessionHandlerTestSuite))
+}
+
+func (s *showAgeVerificationSessionHandlerTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.ageVerificationSessionServiceMock = mock_services.NewMockAgeVerificationSessionService(s.ctrl)
+	s.handler = NewShowAgeVerificationSessionHandler(s.ageVerificationSessionServiceMock)
+}
+
+func (s *showAgeVerificationSessionHandlerTestSuite) TestMethod() {
+	s.Equal([]string{"GET"}, s.handler.Method())
+}
+
+func (s *showAgeVerificationSessionHandlerTestSuite) TestRoute() {
+	s.Equal("/age_verifications/{age_verification_id}", s.handler.Route())
+}
+
+func (s *showAgeVerificationSessionHandlerTestSuite) TestServeHTTP() {
+	sessionId := uuid.New()
+	clientId := uuid.New()
+	createdAt := time.Date(2023, 6, 30, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		description        string
+		session            *models.AgeVerificationSession
+		findError          error
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			description: "Failed",
+			session: &models.AgeVerificationSession{
+				ID:            sessionId,
+				ClientID:      clientId.String(),
+				Method:        "document",
+				Country:       "*",
+				Category:      "adult_content",
+				AgeOver:       18,
+				Status:        models.AgeVerificationSessionStatusFailed,
+				RedirectURL:   "https://app.example.com/verified?reference=sim_1",
+				FailureReason: "user is under 18",
+				ExpiresAt:     createdAt.Add(30 * time.Minute),
+				CompletedAt:   sql.NullTime{Time: createdAt.Add(time.Minute), Valid: true},
+				CreatedAt:     createdAt,
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody: `{
+				"id":"` + sessionId.String() + `",
+				"client_id":"` + clientId.String() + `",
+				"method":"document",
+				"country":"*",
+				"category":"adult_content",
+				"age_over":18,
+				"status":"failed",
+				"redirect_url":null,
+				"failure_reason":"user is under 18",
+				"expires_at":"2023-06-30T12:30:00Z",
+				"completed_at":"2023-06-30T12:01:00Z",
+				"created_at":"2023-06-30T12:00:00Z"
+			}`,
+		},
+		{
+			description:        "Not found",
+			findError:          entities.NewItemNotFoundError("Age verification", sessionId.String()),
+			expectedStatusCode: http.StatusNotFound,
+			expectedBody:       `{"message":"Age verification not found","details":["` + sessionId.String() + `"]}`,
+		},
+		{
+			description:        "Unexpected error",
+			findError:          errors.New("database error"),
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedBody:       `{"message":"unexpected error","details":["database error"]}`,
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			request := httptest.NewRequest(
+				http.MethodGet, "/age_verifications/"+sessionId.String(), nil,
+			)
+			request = mux.SetURLVars(
+				request, map[string]string{"age_verification_id": sessionId.String()},
+			)
+			response := httptest.NewRecorder()
+
+			s.ageVerificationSessionServiceMock.EXPECT().FindById(
+				request.Context(), sessionId.String(),
+			).Return(test.session, test.findError)
+
+			s.handler.ServeHTTP(response, request)
+
+			s.Equal(test.expectedStatusCode, response.Code)
+			s.JSONEq(test.expectedBody, response.Body.String())
+		})
+	}
+}
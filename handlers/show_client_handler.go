@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"verifymy-golang-test/entities"
+	"verifymy-golang-test/models"
+	"verifymy-golang-test/services"
+)
+
+type showClientHandler struct {
+	clientService services.ClientService
+}
+
+func NewShowClientHandler(clientService services.ClientService) Handler {
+	return &showClientHandler{clientService: clientService}
+}
+
+func (h *showClientHandler) Method() []string {
+	return []string{http.MethodGet}
+}
+
+func (h *showClientHandler) Route() string {
+	return "/clients/{client_id}"
+}
+
+func (h *showClientHandler) Permission() models.Permission {
+	return models.PermissionManageClients
+}
+
+func (h *showClientHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	client, err := h.clientService.FindById(r.Context(), mux.Vars(r)["client_id"])
+	if err != nil {
+		if _, ok := err.(*entities.ItemNotFoundError); ok {
+			w.WriteHeader(http.StatusNotFound)
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+			err = entities.NewUnexpectedError(err)
+		}
+
+		jsonPayload, _ := json.Marshal(err)
+		w.Write(jsonPayload)
+		return
+	}
+
+	jsonPayload, _ := json.Marshal(client)
+	w.Write(jsonPayload)
+}
@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/suite"
+
+	"verifymy-golang-test/entities"
+	mock_services "verifymy-golang-test/mocks/services"
+	"verifymy-golang-test/models"
+)
+
+type showClientHandlerTestSuite struct {
+	suite.Suite
+	ctrl              *gomock.Controller
+	clientServiceMock *mock_services.MockClientService
+	handler           Handler
+}
+
+func TestShowClientHandlerTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(showClientHandlerTestSuite))
+}
+
+func (s *showClientHandlerTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.clientServiceMock = mock_services.NewMockClientService(s.ctrl)
+	s.handler = NewShowClientHandler(s.clientServiceMock)
+}
+
+func (s *showClientHandlerTestSuite) TestMethod() {
+	s.Equal([]string{"GET"}, s.handler.Method())
+}
+
+func (s *showClientHandlerTestSuite) TestRoute() {
+	s.Equal("/clients/{client_id}", s.handler.Route())
+}
+
+func (s *showClientHandlerTestSuite) TestPermission() {
+	s.Equal(
+		models.PermissionManageClients,
+		s.handler.(AuthorizedHandler).Permission(),
+	)
+}
+
+func (s *showClientHandlerTestSuite) TestServeHTTP() {
+	clientId := uuid.New()
+	createdAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	client := &models.Client{
+		ID:             clientId,
+		Name:           "Shop",
+		RedirectURIs:   models.StringList{},
+		AllowedOrigins: models.StringList{},
+		Scopes:         models.StringList{"age_tokens"},
+		SecretHash:     "hashed-secret",
+		CreatedAt:      createdAt,
+		UpdatedAt:      createdAt,
+	}
+
+	tests := []struct {
+		description        string
+		client             *models.Client
+		findByIdError      error
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			description:        "Success",
+			client:             client,
+			expectedStatusCode: http.StatusOK,
+			expectedBody: `{
+				"id":"` + clientId.String() + `",
+				"name":"Shop",
+				"redirect_uris":[],
+				"allowed_origins":[],
+				"scopes":["age_tokens"],
+				"created_at":"2024-05-01T10:00:00Z",
+				"updated_at":"2024-05-01T10:00:00Z"
+			}`,
+		},
+		{
+			description:        "Client not found",
+			findByIdError:      entities.NewItemNotFoundError("Client", clientId.String()),
+			expectedStatusCode: http.StatusNotFound,
+			expectedBody:       `{"message":"Client not found","details":["` + clientId.String() + `"]}`,
+		},
+		{
+			description:        "Unexpected error",
+			findByIdError:      errors.New("error finding client"),
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedBody:       `{"message":"unexpected error","details":["error finding client"]}`,
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			request := httptest.NewRequest(http.MethodGet, "/clients/"+clientId.String(), nil)
+			request = mux.SetURLVars(request, map[string]string{"client_id": clientId.String()})
+			response := httptest.NewRecorder()
+
+			s.clientServiceMock.EXPECT().FindById(
+				request.Context(), clientId.String(),
+			).Return(test.client, test.findByIdError)
+
+			s.handler.ServeHTTP(response, request)
+
+			s.Equal(test.expectedStatusCode, response.Code)
+			s.JSONEq(test.expectedBody, response.Body.String())
+		})
+	}
+}
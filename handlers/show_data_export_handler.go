@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"verifymy-golang-test/entities"
+	"verifymy-golang-test/models"
+	"verifymy-golang-test/services"
+)
+
+type showDataExportHandler struct {
+	dataExportService services.DataExportService
+}
+
+func NewShowDataExportHandler(
+	dataExportService services.DataExportService,
+) Handler {
+	return &showDataExportHandler{
+		dataExportService: dataExportService,
+	}
+}
+
+func (h *showDataExportHandler) Method() []string {
+	return []string{http.MethodGet}
+}
+
+func (h *showDataExportHandler) Route() string {
+	return "/profile/exports/{export_id}"
+}
+
+// ServeHTTP includes a signed download link once the export is ready, a new
+// one on every request since they expire.
+func (h *showDataExportHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	export, err := h.dataExportService.FindById(r.Context(), mux.Vars(r)["export_id"])
+	if err != nil {
+		if _, ok := err.(*entities.ItemNotFoundError); ok {
+			w.WriteHeader(http.StatusNotFound)
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+			err = entities.NewUnexpectedError(err)
+		}
+
+		jsonPayload, _ := json.Marshal(err)
+		w.Write(jsonPayload)
+		return
+	}
+
+	body := map[string]interface{}{
+		"id":         export.ID,
+		"status":     export.Status,
+		"created_at": export.CreatedAt,
+	}
+	if export.CompletedAt.Valid {
+		body["completed_at"] = export.CompletedAt.Time
+	}
+	if export.ExpiresAt.Valid {
+		body["expires_at"] = export.ExpiresAt.Time
+	}
+	if export.Status == models.DataExportStatusReady {
+		body["download_url"], body["download_expires_at"] = h.dataExportService.DownloadURL(export)
+	}
+
+	jsonPayload, _ := json.Marshal(body)
+	w.Write(jsonPayload)
+}
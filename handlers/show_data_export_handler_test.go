@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/suite"
+
+	"verifymy-golang-test/entities"
+	mock_services "verifymy-golang-test/mocks/services"
+	"verifymy-golang-test/models"
+)
+
+type showDataExportHandlerTestSuite struct {
+	suite.Suite
+	ctrl                  *gomock.Controller
+	dataExportServiceMock *mock_services.MockDataExportService
+	handler               Handler
+}
+
+func TestShowDataExportHandlerTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(showDataExportHandlerTestSuite))
+}
+
+func (s *showDataExportHandlerTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.dataExportServiceMock = mock_services.NewMockDataExportService(s.ctrl)
+	s.handler = NewShowDataExportHandler(s.dataExportServiceMock)
+}
+
+func (s *showDataExportHandlerTestSuite) TestMethod() {
+	s.Equal([]string{"GET"}, s.handler.Method())
+}
+
+func (s *showDataExportHandlerTestSuite) TestRoute() {
+	s.Equal("/profile/exports/{export_id}", s.handler.Route())
+}
+
+func (s *showDataExportHandlerTestSuite) TestServeHTTP() {
+	exportId := uuid.MustParse("0b7c5a41-5bd6-4a7b-9d4e-8f2b4c1e7a10")
+	createdAt := time.Date(2023, 6, 30, 12, 0, 0, 0, time.UTC)
+	completedAt := createdAt.Add(time.Minute)
+
+	tests := []struct {
+		description        string
+		export             *models.DataExport
+		findError          error
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			description: "Pending",
+			export: &models.DataExport{
+				ID:        exportId,
+				Status:    models.DataExportStatusPending,
+				CreatedAt: createdAt,
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `{"created_at":"2023-06-30T12:00:00Z","id":"` + exportId.String() + `","status":"pending"}`,
+		},
+		{
+			description: "Ready",
+			export: &models.DataExport{
+				ID:          exportId,
+				Status:      models.DataExportStatusReady,
+				CreatedAt:   createdAt,
+				CompletedAt: sql.NullTime{Time: completedAt, Valid: true},
+				ExpiresAt:   sql.NullTime{Time: completedAt.Add(7 * 24 * time.Hour), Valid: true},
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `{"completed_at":"2023-06-30T12:01:00Z","created_at":"2023-06-30T12:00:00Z","download_expires_at":1688130000,"download_url":"http://localhost:8080/exports/download?signature=abc","expires_at":"2023-07-07T12:01:00Z","id":"` + exportId.String() + `","status":"ready"}`,
+		},
+		{
+			description:        "Not found",
+			findError:          entities.NewItemNotFoundError("Data export", exportId.String()),
+			expectedStatusCode: http.StatusNotFound,
+			expectedBody:       `{"message":"Data export not found","details":["` + exportId.String() + `"]}`,
+		},
+		{
+			description:        "Unexpected error",
+			findError:          errors.New("database error"),
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedBody:       `{"message":"unexpected error","details":["database error"]}`,
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			request := httptest.NewRequest(
+				http.MethodGet, "/profile/exports/"+exportId.String(), nil,
+			)
+			request = mux.SetURLVars(request, map[string]string{"export_id": exportId.String()})
+			response := httptest.NewRecorder()
+
+			s.dataExportServiceMock.EXPECT().FindById(
+				request.Context(), exportId.String(),
+			).Return(test.export, test.findError)
+			if test.export != nil && test.export.Status == models.DataExportStatusReady {
+				s.dataExportServiceMock.EXPECT().DownloadURL(test.export).Return(
+					"http://localhost:8080/exports/download?signature=abc", int64(1688130000),
+				)
+			}
+
+			s.handler.ServeHTTP(response, request)
+
+			s.Equal(test.expectedBody, response.Body.String())
+			s.Equal(test.expectedStatusCode, response.Code)
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"net/http"
+)
+
+type showEmailChangeConfirmationHandler struct{}
+
+func NewShowEmailChangeConfirmationHandler() Handler {
+	return &showEmailChangeConfirmationHandler{}
+}
+
+func (h *showEmailChangeConfirmationHandler) Method() []string {
+	return []string{http.MethodGet}
+}
+
+func (h *showEmailChangeConfirmationHandler) Route() string {
+	return "/auth/email/confirm"
+}
+
+// ServeHTTP is where the link sent to the new address of a user leads,
+// asking them to confirm the change, see
+// services.UserService.RequestEmailChange
+func (h *showEmailChangeConfirmationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	renderConfirmationPage(w, r, confirmationPage{
+		Title: "Confirm your new e-mail address",
+		Message: "Confirm this is the new e-mail address of your account. " +
+			"You will have to sign in again once it is changed.",
+		Done: "Your e-mail address was changed.",
+		Actions: []confirmationAction{
+			{Label: "Confirm", Fields: map[string]interface{}{}},
+		},
+	})
+}
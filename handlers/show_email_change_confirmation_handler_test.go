@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type showEmailChangeConfirmationHandlerTestSuite struct {
+	suite.Suite
+	handler Handler
+}
+
+func TestShowEmailChangeConfirmationHandlerTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(showEmailChangeConfirmationHandlerTestSuite))
+}
+
+func (s *showEmailChangeConfirmationHandlerTestSuite) SetupTest() {
+	s.handler = NewShowEmailChangeConfirmationHandler()
+}
+
+func (s *showEmailChangeConfirmationHandlerTestSuite) TestMethod() {
+	s.Equal([]string{"GET"}, s.handler.Method())
+}
+
+func (s *showEmailChangeConfirmationHandlerTestSuite) TestRoute() {
+	s.Equal("/auth/email/confirm", s.handler.Route())
+}
+
+func (s *showEmailChangeConfirmationHandlerTestSuite) TestServeHTTP() {
+	request := httptest.NewRequest(
+		http.MethodGet, "/auth/email/confirm?token=email-change-token", nil,
+	)
+	response := httptest.NewRecorder()
+
+	s.handler.ServeHTTP(response, request)
+
+	s.Equal(http.StatusOK, response.Code)
+	s.Equal("text/html; charset=utf-8", response.Header().Get("Content-Type"))
+	s.Contains(response.Body.String(), `const token = "email-change-token";`)
+	s.Contains(response.Body.String(), `[{"label":"Confirm","fields":{}}]`)
+}
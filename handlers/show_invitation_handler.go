@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"net/http"
+)
+
+type showInvitationHandler struct{}
+
+func NewShowInvitationHandler() Handler {
+	return &showInvitationHandler{}
+}
+
+func (h *showInvitationHandler) Method() []string {
+	return []string{http.MethodGet}
+}
+
+func (h *showInvitationHandler) Route() string {
+	return "/auth/invitations/accept"
+}
+
+// ServeHTTP is where the link sent to invited users leads, asking them to
+// choose their password, see services.AdminUserService.Invite
+func (h *showInvitationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	renderConfirmationPage(w, r, confirmationPage{
+		Title:    "Accept your invitation",
+		Message:  "Choose the password of your account to accept the invitation.",
+		Done:     "Your invitation was accepted, you can now sign in.",
+		Password: true,
+		Actions: []confirmationAction{
+			{Label: "Accept invitation", Fields: map[string]interface{}{}},
+		},
+	})
+}
@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type showInvitationHandlerTestSuite struct {
+	suite.Suite
+	handler Handler
+}
+
+func TestShowInvitationHandlerTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(showInvitationHandlerTestSuite))
+}
+
+func (s *showInvitationHandlerTestSuite) SetupTest() {
+	s.handler = NewShowInvitationHandler()
+}
+
+func (s *showInvitationHandlerTestSuite) TestMethod() {
+	s.Equal([]string{"GET"}, s.handler.Method())
+}
+
+func (s *showInvitationHandlerTestSuite) TestRoute() {
+	s.Equal("/auth/invitations/accept", s.handler.Route())
+}
+
+func (s *showInvitationHandlerTestSuite) TestServeHTTP() {
+	request := httptest.NewRequest(
+		http.MethodGet, "/auth/invitations/accept?token=invitation-token", nil,
+	)
+	response := httptest.NewRecorder()
+
+	s.handler.ServeHTTP(response, request)
+
+	s.Equal(http.StatusOK, response.Code)
+	s.Equal("text/html; charset=utf-8", response.Header().Get("Content-Type"))
+	s.Contains(response.Body.String(), `const token = "invitation-token";`)
+	s.Contains(response.Body.String(), `type="password"`)
+}
@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"verifymy-golang-test/providers"
+)
+
+// jwksMaxAge is how long relying parties may cache the signing keys, and
+// the OpenID configuration pointing to them
+const jwksMaxAge = "3600"
+
+type showJWKSHandler struct {
+	signingKeys providers.SigningKeys
+}
+
+func NewShowJWKSHandler(
+	signingKeys providers.SigningKeys,
+) Handler {
+	return &showJWKSHandler{
+		signingKeys: signingKeys,
+	}
+}
+
+func (h *showJWKSHandler) Method() []string {
+	return []string{http.MethodGet}
+}
+
+func (h *showJWKSHandler) Route() string {
+	return "/.well-known/jwks.json"
+}
+
+// ServeHTTP publishes the public keys tokens signed for third parties are
+// checked against
+func (h *showJWKSHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age="+jwksMaxAge)
+
+	jsonPayload, _ := json.Marshal(h.signingKeys.PublicKeys())
+	w.Write(jsonPayload)
+}
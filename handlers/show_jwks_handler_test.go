@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/suite"
+
+	mock_providers "verifymy-golang-test/mocks/providers"
+	"verifymy-golang-test/providers"
+)
+
+type showJWKSHandlerTestSuite struct {
+	suite.Suite
+	ctrl            *gomock.Controller
+	signingKeysMock *mock_providers.MockSigningKeys
+	handler         Handler
+}
+
+func TestShowJWKSHandlerTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(showJWKSHandlerTestSuite))
+}
+
+func (s *showJWKSHandlerTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.signingKeysMock = mock_providers.NewMockSigningKeys(s.ctrl)
+	s.handler = NewShowJWKSHandler(s.signingKeysMock)
+}
+
+func (s *showJWKSHandlerTestSuite) TestMethod() {
+	s.Equal([]string{"GET"}, s.handler.Method())
+}
+
+func (s *showJWKSHandlerTestSuite) TestRoute() {
+	s.Equal("/.well-known/jwks.json", s.handler.Route())
+}
+
+func (s *showJWKSHandlerTestSuite) TestServeHTTP() {
+	s.signingKeysMock.EXPECT().PublicKeys().Return(providers.JSONWebKeySet{
+		Keys: []providers.JSONWebKey{{
+			KeyType:   "RSA",
+			Use:       "sig",
+			Algorithm: "RS256",
+			KeyID:     "kid-1",
+			Modulus:   "0vx7agoebGcQSuu",
+			Exponent:  "AQAB",
+		}},
+	})
+
+	request := httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
+	response := httptest.NewRecorder()
+
+	s.handler.ServeHTTP(response, request)
+
+	s.Equal(http.StatusOK, response.Code)
+	s.Equal("public, max-age=3600", response.Header().Get("Cache-Control"))
+	s.JSONEq(
+		`{"keys":[{"kty":"RSA","use":"sig","alg":"RS256","kid":"kid-1","n":"0vx7agoebGcQSuu","e":"AQAB"}]}`,
+		response.Body.String(),
+	)
+}
@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"verifymy-golang-test/services"
+)
+
+type showOAuthAuthorizationHandler struct {
+	oauthService services.OAuthService
+}
+
+func NewShowOAuthAuthorizationHandler(
+	oauthService services.OAuthService,
+) Handler {
+	return &showOAuthAuthorizationHandler{
+		oauthService: oauthService,
+	}
+}
+
+func (h *showOAuthAuthorizationHandler) Method() []string {
+	return []string{http.MethodGet}
+}
+
+func (h *showOAuthAuthorizationHandler) Route() string {
+	return "/oauth/authorize"
+}
+
+// ServeHTTP backs the consent screen: the frontend forwards the query the
+// client sent the user with, and shows what the client asks for. Errors
+// holding a redirect_uri are meant to be sent back to the client.
+func (h *showOAuthAuthorizationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	prompt, err := h.oauthService.Prompt(r.Context(), oauthAuthorizationRequest(r.URL.Query()))
+	if err != nil {
+		writeOAuthError(w, err)
+		return
+	}
+
+	jsonPayload, _ := json.Marshal(prompt)
+	w.Write(jsonPayload)
+}
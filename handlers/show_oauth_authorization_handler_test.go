@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/suite"
+
+	"verifymy-golang-test/entities"
+	mock_services "verifymy-golang-test/mocks/services"
+)
+
+type showOAuthAuthorizationHandlerTestSuite struct {
+	suite.Suite
+	ctrl             *gomock.Controller
+	oauthServiceMock *mock_services.MockOAuthService
+	handler          Handler
+}
+
+func TestShowOAuthAuthorizationHandlerTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(showOAuthAuthorizationHandlerTestSuite))
+}
+
+func (s *showOAuthAuthorizationHandlerTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.oauthServiceMock = mock_services.NewMockOAuthService(s.ctrl)
+	s.handler = NewShowOAuthAuthorizationHandler(s.oauthServiceMock)
+}
+
+func (s *showOAuthAuthorizationHandlerTestSuite) TestMethod() {
+	s.Equal([]string{"GET"}, s.handler.Method())
+}
+
+func (s *showOAuthAuthorizationHandlerTestSuite) TestRoute() {
+	s.Equal("/oauth/authorize", s.handler.Route())
+}
+
+func (s *showOAuthAuthorizationHandlerTestSuite) TestServeHTTP() {
+	clientId := "7b1e2f5c-8d2a-4f7e-9a51-3c0d4e6b8f21"
+
+	tests := []struct {
+		description        string
+		prompt             *entities.OAuthAuthorizationPrompt
+		promptError        error
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			description: "Success",
+			prompt: &entities.OAuthAuthorizationPrompt{
+				ClientID: clientId, ClientName: "Shop", Scopes: []string{"profile"},
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody: `{
+				"client_id":"` + clientId + `",
+				"client_name":"Shop",
+				"scopes":["profile"],
+				"consented":false
+			}`,
+		},
+		{
+			description: "Unknown client",
+			promptError: entities.NewInvalidParameterError(
+				"client_id", "client_id must be a registered client",
+			),
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody: `{
+				"message":"invalid client_id parameter",
+				"details":["client_id must be a registered client"]
+			}`,
+		},
+		{
+			description: "Error sent back to the client",
+			promptError: &entities.OAuthError{
+				Code:        entities.OAuthErrorInvalidScope,
+				Description: "scope must only hold scopes of the client users can grant",
+				RedirectURI: "https://shop.example.com/callback?error=invalid_scope",
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody: `{
+				"error":"invalid_scope",
+				"error_description":"scope must only hold scopes of the client users can grant",
+				"redirect_uri":"https://shop.example.com/callback?error=invalid_scope"
+			}`,
+		},
+		{
+			description:        "Unexpected error",
+			promptError:        errors.New("database unavailable"),
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedBody:       `{"message":"unexpected error","details":["database unavailable"]}`,
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			request := httptest.NewRequest(
+				http.MethodGet,
+				"/oauth/authorize?response_type=code&client_id="+clientId+
+					"&redirect_uri=https%3A%2F%2Fshop.example.com%2Fcallback&scope=profile"+
+					"&state=xyz&code_challenge=challenge&code_challenge_method=S256",
+				nil,
+			)
+			response := httptest.NewRecorder()
+
+			s.oauthServiceMock.EXPECT().Prompt(
+				request.Context(),
+				entities.OAuthAuthorizationRequest{
+					ResponseType:        "code",
+					ClientID:            clientId,
+					RedirectURI:         "https://shop.example.com/callback",
+					Scope:               "profile",
+					State:               "xyz",
+					CodeChallenge:       "challenge",
+					CodeChallengeMethod: "S256",
+				},
+			).Return(test.prompt, test.promptError)
+
+			s.handler.ServeHTTP(response, request)
+
+			s.Equal(test.expectedStatusCode, response.Code)
+			s.JSONEq(test.expectedBody, response.Body.String())
+		})
+	}
+}
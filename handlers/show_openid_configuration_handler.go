@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"verifymy-golang-test/services"
+)
+
+type showOpenIDConfigurationHandler struct {
+	oauthService services.OAuthService
+}
+
+func NewShowOpenIDConfigurationHandler(
+	oauthService services.OAuthService,
+) Handler {
+	return &showOpenIDConfigurationHandler{
+		oauthService: oauthService,
+	}
+}
+
+func (h *showOpenIDConfigurationHandler) Method() []string {
+	return []string{http.MethodGet}
+}
+
+func (h *showOpenIDConfigurationHandler) Route() string {
+	return "/.well-known/openid-configuration"
+}
+
+// ServeHTTP publishes the OpenID Connect discovery document, which client
+// libraries configure themselves from
+func (h *showOpenIDConfigurationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age="+jwksMaxAge)
+
+	jsonPayload, _ := json.Marshal(h.oauthService.Configuration())
+	w.Write(jsonPayload)
+}
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/suite"
+
+	"verifymy-golang-test/entities"
+	mock_services "verifymy-golang-test/mocks/services"
+)
+
+type showOpenIDConfigurationHandlerTestSuite struct {
+	suite.Suite
+	ctrl             *gomock.Controller
+	oauthServiceMock *mock_services.MockOAuthService
+	handler          Handler
+}
+
+func TestShowOpenIDConfigurationHandlerTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(showOpenIDConfigurationHandlerTestSuite))
+}
+
+func (s *showOpenIDConfigurationHandlerTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.oauthServiceMock = mock_services.NewMockOAuthService(s.ctrl)
+	s.handler = NewShowOpenIDConfigurationHandler(s.oauthServiceMock)
+}
+
+func (s *showOpenIDConfigurationHandlerTestSuite) TestMethod() {
+	s.Equal([]string{"GET"}, s.handler.Method())
+}
+
+func (s *showOpenIDConfigurationHandlerTestSuite) TestRoute() {
+	s.Equal("/.well-known/openid-configuration", s.handler.Route())
+}
+
+func (s *showOpenIDConfigurationHandlerTestSuite) TestServeHTTP() {
+	s.oauthServiceMock.EXPECT().Configuration().Return(entities.OpenIDConfiguration{
+		Issuer:                 "https://verify.example.com",
+		AuthorizationEndpoint:  "https://verify.example.com/oauth/authorize",
+		JWKSURI:                "https://verify.example.com/.well-known/jwks.json",
+		ResponseTypesSupported: []string{"code"},
+	})
+
+	request := httptest.NewRequest(http.MethodGet, "/.well-known/openid-configuration", nil)
+	response := httptest.NewRecorder()
+
+	s.handler.ServeHTTP(response, request)
+
+	s.Equal(http.StatusOK, response.Code)
+	s.Equal("public, max-age=3600", response.Header().Get("Cache-Control"))
+	s.JSONEq(`{
+		"issuer": "https://verify.example.com",
+		"authorization_endpoint": "https://verify.example.com/oauth/authorize",
+		"token_endpoint": "",
+		"userinfo_endpoint": "",
+		"jwks_uri": "https://verify.example.com/.well-known/jwks.json",
+		"revocation_endpoint": "",
+		"introspection_endpoint": "",
+		"end_session_endpoint": "",
+		"scopes_supported": null,
+		"response_types_supported": ["code"],
+		"grant_types_supported": null,
+		"subject_types_supported": null,
+		"id_token_signing_alg_values_supported": null,
+		"token_endpoint_auth_methods_supported": null,
+		"code_challenge_methods_supported": null,
+		"claims_supported": null
+	}`, response.Body.String())
+}
@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"net/http"
+)
+
+type showParentalConsentHandler struct{}
+
+func NewShowParentalConsentHandler() Handler {
+	return &showParentalConsentHandler{}
+}
+
+func (h *showParentalConsentHandler) Method() []string {
+	return []string{http.MethodGet}
+}
+
+func (h *showParentalConsentHandler) Route() string {
+	return "/auth/parental_consent"
+}
+
+// ServeHTTP is where the link sent to guardians leads, asking them to
+// consent or not, see services.ParentalConsentService.Request
+func (h *showParentalConsentHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	renderConfirmationPage(w, r, confirmationPage{
+		Title: "Parental consent",
+		Message: "A child gave your e-mail address as that of their parent or guardian " +
+			"while signing up. Their account stays restricted until you consent.",
+		Done: "Thank you, your answer was recorded.",
+		Actions: []confirmationAction{
+			{Label: "I consent", Fields: map[string]interface{}{"approved": true}},
+			{Label: "I do not consent", Fields: map[string]interface{}{"approved": false}},
+		},
+	})
+}
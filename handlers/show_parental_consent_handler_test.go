@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type showParentalConsentHandlerTestSuite struct {
+	suite.Suite
+	handler Handler
+}
+
+func TestShowParentalConsentHandlerTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(showParentalConsentHandlerTestSuite))
+}
+
+func (s *showParentalConsentHandlerTestSuite) SetupTest() {
+	s.handler = NewShowParentalConsentHandler()
+}
+
+func (s *showParentalConsentHandlerTestSuite) TestMethod() {
+	s.Equal([]string{"GET"}, s.handler.Method())
+}
+
+func (s *showParentalConsentHandlerTestSuite) TestRoute() {
+	s.Equal("/auth/parental_consent", s.handler.Route())
+}
+
+func (s *showParentalConsentHandlerTestSuite) TestServeHTTP() {
+	s.Run("Asks the guardian to decide", func() {
+		request := httptest.NewRequest(
+			http.MethodGet, "/auth/parental_consent?token=parental-consent-token", nil,
+		)
+		response := httptest.NewRecorder()
+
+		s.handler.ServeHTTP(response, request)
+
+		s.Equal(http.StatusOK, response.Code)
+		s.Equal("text/html; charset=utf-8", response.Header().Get("Content-Type"))
+		s.Equal("no-referrer", response.Header().Get("Referrer-Policy"))
+		s.Contains(response.Body.String(), `const token = "parental-consent-token";`)
+		s.Contains(response.Body.String(), `"fields":{"approved":true}`)
+		s.Contains(response.Body.String(), `"fields":{"approved":false}`)
+		s.NotContains(response.Body.String(), `type="password"`)
+	})
+
+	s.Run("Escapes the token", func() {
+		request := httptest.NewRequest(
+			http.MethodGet, "/auth/parental_consent?token=%22%3C%2Fscript%3E", nil,
+		)
+		response := httptest.NewRecorder()
+
+		s.handler.ServeHTTP(response, request)
+
+		s.Equal(http.StatusOK, response.Code)
+		s.NotContains(response.Body.String(), `"</script>`)
+	})
+
+	s.Run("Missing token", func() {
+		request := httptest.NewRequest(http.MethodGet, "/auth/parental_consent", nil)
+		response := httptest.NewRecorder()
+
+		s.handler.ServeHTTP(response, request)
+
+		s.Equal(http.StatusBadRequest, response.Code)
+		s.Contains(response.Body.String(), "This link is incomplete")
+		s.NotContains(response.Body.String(), "<form")
+	})
+}
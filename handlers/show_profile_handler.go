@@ -3,15 +3,21 @@ package handlers
 import (
 	"encoding/json"
 	"net/http"
+
 	"verifymy-golang-test/common"
+	"verifymy-golang-test/entities"
 	"verifymy-golang-test/models"
+	"verifymy-golang-test/services"
 )
 
 type showProfileHandler struct {
+	includes services.UserIncludeRegistry
 }
 
-func NewShowProfileHandler() Handler {
-	return &showProfileHandler{}
+func NewShowProfileHandler(includes services.UserIncludeRegistry) Handler {
+	return &showProfileHandler{
+		includes: includes,
+	}
 }
 
 func (h *showProfileHandler) Method() []string {
@@ -22,11 +28,40 @@ func (h *showProfileHandler) Route() string {
 	return "/profile"
 }
 
+func (h *showProfileHandler) AllowsPendingConsent() bool {
+	return true
+}
+
 func (h *showProfileHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
 	user := r.Context().Value(common.AuthUser).(*models.User)
 
-	jsonPayload, _ := json.Marshal(user)
+	projection, err := parseProjection(r, entities.UserFields, h.includes.Names())
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+
+		jsonPayload, _ := json.Marshal(err)
+		w.Write(jsonPayload)
+		return
+	}
+
+	// The profile was already loaded while authenticating, so fields are
+	// projected on the loaded user instead of querying it again.
+	included, err := h.includes.Resolve(r.Context(), projection.Includes, []models.User{*user})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		err = entities.NewUnexpectedError(err)
+
+		jsonPayload, _ := json.Marshal(err)
+		w.Write(jsonPayload)
+		return
+	}
+
+	w.Header().Set("ETag", versionETag(user.Version))
+
+	jsonPayload, _ := json.Marshal(
+		shapeResource(user, projection, included[user.ID.String()]),
+	)
 	w.Write(jsonPayload)
 }
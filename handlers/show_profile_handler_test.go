@@ -3,6 +3,7 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -13,13 +14,15 @@ import (
 	"github.com/stretchr/testify/suite"
 
 	"verifymy-golang-test/common"
+	mock_services "verifymy-golang-test/mocks/services"
 	"verifymy-golang-test/models"
 )
 
 type showProfileHandlerTestSuite struct {
 	suite.Suite
-	ctrl    *gomock.Controller
-	handler Handler
+	ctrl         *gomock.Controller
+	includesMock *mock_services.MockUserIncludeRegistry
+	handler      Handler
 }
 
 func TestShowProfileHandlerTestSuite(t *testing.T) {
@@ -29,7 +32,9 @@ func TestShowProfileHandlerTestSuite(t *testing.T) {
 
 func (s *showProfileHandlerTestSuite) SetupTest() {
 	s.ctrl = gomock.NewController(s.T())
-	s.handler = NewShowProfileHandler()
+	s.includesMock = mock_services.NewMockUserIncludeRegistry(s.ctrl)
+	s.includesMock.EXPECT().Names().Return([]interface{}{"roles"}).AnyTimes()
+	s.handler = NewShowProfileHandler(s.includesMock)
 }
 
 func (s *showProfileHandlerTestSuite) TestMethod() {
@@ -40,6 +45,10 @@ func (s *showProfileHandlerTestSuite) TestRoute() {
 	s.Equal("/profile", s.handler.Route())
 }
 
+func (s *showProfileHandlerTestSuite) TestAllowsPendingConsent() {
+	s.True(s.handler.(PendingConsentHandler).AllowsPendingConsent())
+}
+
 func (s *showProfileHandlerTestSuite) TestServeHTTP() {
 	userId := uuid.New()
 	user := &models.User{
@@ -51,32 +60,97 @@ func (s *showProfileHandlerTestSuite) TestServeHTTP() {
 		Email:    "peter.parker@nyork.co",
 		Password: "sp00der",
 		Address:  "20 Ingram Street",
+		Version:  4,
 	}
 
-	request := httptest.NewRequest("GET", "/profile", nil)
-	response := httptest.NewRecorder()
-
-	s.handler.ServeHTTP(
-		response,
-		request.WithContext(
-			context.WithValue(request.Context(), common.AuthUser, user),
-		),
-	)
-
-	var jsonPayload map[string]interface{}
-	_ = json.Unmarshal(response.Body.Bytes(), &jsonPayload)
-
-	s.Equal(
-		map[string]interface{}{
-			"id":            userId.String(),
-			"name":          "Peter Parker",
-			"date_of_birth": time.Now().UTC().AddDate(-20, 0, 0).Format("2006-01-02"),
-			"email":         "peter.parker@nyork.co",
-			"password":      nil,
-			"address":       "20 Ingram Street",
+	tests := []struct {
+		description        string
+		queryString        string
+		expectedIncludes   []string
+		resolveResponse    map[string]map[string]interface{}
+		resolveError       error
+		skipResolve        bool
+		expectedPayload    map[string]interface{}
+		expectedStatusCode int
+	}{
+		{
+			description: "Success",
+			expectedPayload: map[string]interface{}{
+				"id":            userId.String(),
+				"name":          "Peter Parker",
+				"date_of_birth": time.Now().UTC().AddDate(-20, 0, 0).Format("2006-01-02"),
+				"email":         "peter.parker@nyork.co",
+				"password":      nil,
+				"address":       "20 Ingram Street",
+				"created_at":    "0001-01-01T00:00:00Z",
+				"updated_at":    "0001-01-01T00:00:00Z",
+				"role":          "",
+				"status":        "",
+			},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			description:      "Success with sparse fieldset and include",
+			queryString:      "?fields=name,email&include=roles",
+			expectedIncludes: []string{"roles"},
+			resolveResponse: map[string]map[string]interface{}{
+				userId.String(): {"roles": nil},
+			},
+			expectedPayload: map[string]interface{}{
+				"name":  "Peter Parker",
+				"email": "peter.parker@nyork.co",
+				"roles": nil,
+			},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			description: "Unknown field",
+			queryString: "?fields=password",
+			skipResolve: true,
+			expectedPayload: map[string]interface{}{
+				"message": "invalid fields parameter",
+				"details": []interface{}{"unknown field password"},
+			},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			description:      "Error resolving includes",
+			queryString:      "?include=roles",
+			expectedIncludes: []string{"roles"},
+			resolveError:     errors.New("error resolving roles"),
+			expectedPayload: map[string]interface{}{
+				"message": "unexpected error",
+				"details": []interface{}{"error resolving roles"},
+			},
+			expectedStatusCode: http.StatusInternalServerError,
 		},
-		jsonPayload,
-	)
-	s.Equal(http.StatusOK, response.Code)
-	s.Equal("application/json", response.Header().Get("Content-Type"))
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			request := httptest.NewRequest("GET", "/profile"+test.queryString, nil)
+			request = request.WithContext(
+				context.WithValue(request.Context(), common.AuthUser, user),
+			)
+			response := httptest.NewRecorder()
+
+			if !test.skipResolve {
+				s.includesMock.EXPECT().Resolve(
+					request.Context(), test.expectedIncludes, []models.User{*user},
+				).Return(test.resolveResponse, test.resolveError)
+			}
+
+			s.handler.ServeHTTP(response, request)
+
+			var jsonPayload map[string]interface{}
+			_ = json.Unmarshal(response.Body.Bytes(), &jsonPayload)
+
+			s.Equal(test.expectedPayload, jsonPayload)
+			s.Equal(test.expectedStatusCode, response.Code)
+			s.Equal("application/json", response.Header().Get("Content-Type"))
+			if test.expectedStatusCode == http.StatusOK {
+				s.Equal(`"4"`, response.Header().Get("ETag"))
+			}
+		})
+	}
 }
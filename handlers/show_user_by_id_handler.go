@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"verifymy-golang-test/entities"
+	"verifymy-golang-test/models"
+	"verifymy-golang-test/services"
+)
+
+type showUserByIdHandler struct {
+	adminUserService services.AdminUserService
+	includes         services.UserIncludeRegistry
+}
+
+func NewShowUserByIdHandler(
+	adminUserService services.AdminUserService,
+	includes services.UserIncludeRegistry,
+) Handler {
+	return &showUserByIdHandler{
+		adminUserService: adminUserService,
+		includes:         includes,
+	}
+}
+
+func (h *showUserByIdHandler) Method() []string {
+	return []string{http.MethodGet}
+}
+
+func (h *showUserByIdHandler) Route() string {
+	return "/users/{user_id}"
+}
+
+func (h *showUserByIdHandler) Permission() models.Permission {
+	return models.PermissionManageUsers
+}
+
+func (h *showUserByIdHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	projection, err := parseProjection(r, entities.UserFields, h.includes.Names())
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+
+		jsonPayload, _ := json.Marshal(err)
+		w.Write(jsonPayload)
+		return
+	}
+
+	user, err := h.adminUserService.FindById(r.Context(), mux.Vars(r)["user_id"])
+	if err != nil {
+		if _, ok := err.(*entities.ItemNotFoundError); ok {
+			w.WriteHeader(http.StatusNotFound)
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+			err = entities.NewUnexpectedError(err)
+		}
+
+		jsonPayload, _ := json.Marshal(err)
+		w.Write(jsonPayload)
+		return
+	}
+
+	included, err := h.includes.Resolve(r.Context(), projection.Includes, []models.User{*user})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		err = entities.NewUnexpectedError(err)
+
+		jsonPayload, _ := json.Marshal(err)
+		w.Write(jsonPayload)
+		return
+	}
+
+	w.Header().Set("ETag", versionETag(user.Version))
+
+	shaped := shapeResource(user, projection, included[user.ID.String()])
+	if user.DeletedAt.Valid {
+		shaped["deleted_at"] = user.DeletedAt.Time
+	}
+
+	jsonPayload, _ := json.Marshal(shaped)
+	w.Write(jsonPayload)
+}
@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+
+	"verifymy-golang-test/entities"
+	mock_services "verifymy-golang-test/mocks/services"
+	"verifymy-golang-test/models"
+)
+
+type showUserByIdHandlerTestSuite struct {
+	suite.Suite
+	ctrl                 *gomock.Controller
+	adminUserServiceMock *mock_services.MockAdminUserService
+	includesMock         *mock_services.MockUserIncludeRegistry
+	handler              Handler
+}
+
+func TestShowUserByIdHandlerTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(showUserByIdHandlerTestSuite))
+}
+
+func (s *showUserByIdHandlerTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.adminUserServiceMock = mock_services.NewMockAdminUserService(s.ctrl)
+	s.includesMock = mock_services.NewMockUserIncludeRegistry(s.ctrl)
+	s.includesMock.EXPECT().Names().Return([]interface{}{"roles"}).AnyTimes()
+	s.handler = NewShowUserByIdHandler(s.adminUserServiceMock, s.includesMock)
+}
+
+func (s *showUserByIdHandlerTestSuite) TestMethod() {
+	s.Equal([]string{"GET"}, s.handler.Method())
+}
+
+func (s *showUserByIdHandlerTestSuite) TestRoute() {
+	s.Equal("/users/{user_id}", s.handler.Route())
+}
+
+func (s *showUserByIdHandlerTestSuite) TestPermission() {
+	s.Equal(
+		models.PermissionManageUsers,
+		s.handler.(AuthorizedHandler).Permission(),
+	)
+}
+
+func (s *showUserByIdHandlerTestSuite) TestServeHTTP() {
+	userId := uuid.New()
+	user := &models.User{
+		ID:       userId,
+		Name:     "Peter Parker",
+		Email:    "peter.parker@nyork.co",
+		Password: "sp00der",
+		Version:  7,
+	}
+	deletedUser := *user
+	deletedUser.DeletedAt = gorm.DeletedAt{
+		Time: time.Date(2023, 6, 1, 10, 0, 0, 0, time.UTC), Valid: true,
+	}
+
+	tests := []struct {
+		description        string
+		queryString        string
+		findByIdResponse   *models.User
+		findByIdError      error
+		skipFindById       bool
+		expectedIncludes   []string
+		resolveResponse    map[string]map[string]interface{}
+		skipResolve        bool
+		expectedPayload    map[string]interface{}
+		expectedETag       string
+		expectedStatusCode int
+	}{
+		{
+			description:      "Success",
+			queryString:      "?fields=name,email&include=roles",
+			findByIdResponse: user,
+			expectedIncludes: []string{"roles"},
+			resolveResponse: map[string]map[string]interface{}{
+				userId.String(): {"roles": nil},
+			},
+			expectedPayload: map[string]interface{}{
+				"name":  "Peter Parker",
+				"email": "peter.parker@nyork.co",
+				"roles": nil,
+			},
+			expectedETag:       `"7"`,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			description:      "Deleted user",
+			queryString:      "?fields=name",
+			findByIdResponse: &deletedUser,
+			resolveResponse:  map[string]map[string]interface{}{},
+			expectedPayload: map[string]interface{}{
+				"name":       "Peter Parker",
+				"deleted_at": "2023-06-01T10:00:00Z",
+			},
+			expectedETag:       `"7"`,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			description:  "Unknown field",
+			queryString:  "?fields=password",
+			skipFindById: true,
+			skipResolve:  true,
+			expectedPayload: map[string]interface{}{
+				"message": "invalid fields parameter",
+				"details": []interface{}{"unknown field password"},
+			},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			description:   "User not found",
+			findByIdError: entities.NewItemNotFoundError("User", userId.String()),
+			skipResolve:   true,
+			expectedPayload: map[string]interface{}{
+				"message": "User not found",
+				"details": []interface{}{userId.String()},
+			},
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			description:   "Unexpected error",
+			findByIdError: errors.New("error finding user"),
+			skipResolve:   true,
+			expectedPayload: map[string]interface{}{
+				"message": "unexpected error",
+				"details": []interface{}{"error finding user"},
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			request := httptest.NewRequest(
+				"GET", "/users/"+userId.String()+test.queryString, nil,
+			)
+			request = mux.SetURLVars(request, map[string]string{"user_id": userId.String()})
+			response := httptest.NewRecorder()
+
+			if !test.skipFindById {
+				s.adminUserServiceMock.EXPECT().FindById(
+					request.Context(), userId.String(),
+				).Return(test.findByIdResponse, test.findByIdError)
+			}
+			if !test.skipResolve {
+				s.includesMock.EXPECT().Resolve(
+					request.Context(), test.expectedIncludes, []models.User{*test.findByIdResponse},
+				).Return(test.resolveResponse, nil)
+			}
+
+			s.handler.ServeHTTP(response, request)
+
+			var jsonPayload map[string]interface{}
+			_ = json.Unmarshal(response.Body.Bytes(), &jsonPayload)
+
+			s.Equal(test.expectedPayload, jsonPayload)
+			s.Equal(test.expectedStatusCode, response.Code)
+			s.Equal(test.expectedETag, response.Header().Get("ETag"))
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"verifymy-golang-test/services"
+)
+
+type showUserInfoHandler struct {
+	oauthService services.OAuthService
+}
+
+func NewShowUserInfoHandler(
+	oauthService services.OAuthService,
+) Handler {
+	return &showUserInfoHandler{
+		oauthService: oauthService,
+	}
+}
+
+func (h *showUserInfoHandler) Method() []string {
+	return []string{http.MethodGet, http.MethodPost}
+}
+
+func (h *showUserInfoHandler) Route() string {
+	return "/userinfo"
+}
+
+// ServeHTTP answers OpenID Connect clients with the claims about the user
+// their access token, sent as a Bearer token, was granted
+func (h *showUserInfoHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+
+	accessToken := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	userInfo, err := h.oauthService.UserInfo(r.Context(), accessToken)
+	if err != nil {
+		writeOAuthError(w, err)
+		return
+	}
+
+	jsonPayload, _ := json.Marshal(userInfo)
+	w.Write(jsonPayload)
+}
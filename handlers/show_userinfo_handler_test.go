@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/suite"
+
+	"verifymy-golang-test/entities"
+	mock_services "verifymy-golang-test/mocks/services"
+)
+
+type showUserInfoHandlerTestSuite struct {
+	suite.Suite
+	ctrl             *gomock.Controller
+	oauthServiceMock *mock_services.MockOAuthService
+	handler          Handler
+}
+
+func TestShowUserInfoHandlerTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(showUserInfoHandlerTestSuite))
+}
+
+func (s *showUserInfoHandlerTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.oauthServiceMock = mock_services.NewMockOAuthService(s.ctrl)
+	s.handler = NewShowUserInfoHandler(s.oauthServiceMock)
+}
+
+func (s *showUserInfoHandlerTestSuite) TestMethod() {
+	s.Equal([]string{"GET", "POST"}, s.handler.Method())
+}
+
+func (s *showUserInfoHandlerTestSuite) TestRoute() {
+	s.Equal("/userinfo", s.handler.Route())
+}
+
+func (s *showUserInfoHandlerTestSuite) TestServeHTTP() {
+	tests := []struct {
+		description             string
+		userInfo                *entities.OpenIDUserInfo
+		userInfoError           error
+		expectedStatusCode      int
+		expectedWWWAuthenticate string
+		expectedBody            string
+	}{
+		{
+			description: "User info",
+			userInfo: &entities.OpenIDUserInfo{
+				Subject: "user-id", Email: "john@doe.com",
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `{"sub":"user-id","email":"john@doe.com"}`,
+		},
+		{
+			description: "Invalid token",
+			userInfoError: entities.NewOAuthError(
+				entities.OAuthErrorInvalidToken, "access token is invalid or expired",
+			),
+			expectedStatusCode:      http.StatusUnauthorized,
+			expectedWWWAuthenticate: `Bearer realm="oauth", error="invalid_token", error_description="access token is invalid or expired"`,
+			expectedBody:            `{"error":"invalid_token","error_description":"access token is invalid or expired"}`,
+		},
+		{
+			description: "Insufficient scope",
+			userInfoError: entities.NewOAuthError(
+				entities.OAuthErrorInsufficientScope, "access token must be granted the openid scope",
+			),
+			expectedStatusCode:      http.StatusForbidden,
+			expectedWWWAuthenticate: `Bearer realm="oauth", error="insufficient_scope", error_description="access token must be granted the openid scope"`,
+			expectedBody:            `{"error":"insufficient_scope","error_description":"access token must be granted the openid scope"}`,
+		},
+		{
+			description:        "Unexpected error",
+			userInfoError:      errors.New("database unavailable"),
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedBody:       `{"message":"unexpected error","details":["database unavailable"]}`,
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			request := httptest.NewRequest(http.MethodGet, "/userinfo", nil)
+			request.Header.Set("Authorization", "Bearer access-token")
+			response := httptest.NewRecorder()
+
+			s.oauthServiceMock.EXPECT().UserInfo(
+				request.Context(), "access-token",
+			).Return(test.userInfo, test.userInfoError)
+
+			s.handler.ServeHTTP(response, request)
+
+			s.Equal(test.expectedStatusCode, response.Code)
+			s.Equal("no-store", response.Header().Get("Cache-Control"))
+			s.Equal(test.expectedWWWAuthenticate, response.Header().Get("WWW-Authenticate"))
+			s.JSONEq(test.expectedBody, response.Body.String())
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"verifymy-golang-test/entities"
+	"verifymy-golang-test/models"
+	"verifymy-golang-test/services"
+)
+
+type showWebhookSubscriptionHandler struct {
+	webhookSubscriptionService services.WebhookSubscriptionService
+}
+
+func NewShowWebhookSubscriptionHandler(
+	webhookSubscriptionService services.WebhookSubscriptionService,
+) Handler {
+	return &showWebhookSubscriptionHandler{
+		webhookSubscriptionService: webhookSubscriptionService,
+	}
+}
+
+func (h *showWebhookSubscriptionHandler) Method() []string {
+	return []string{http.MethodGet}
+}
+
+func (h *showWebhookSubscriptionHandler) Route() string {
+	return "/webhooks/{webhook_id}"
+}
+
+func (h *showWebhookSubscriptionHandler) Permission() models.Permission {
+	return models.PermissionManageWebhooks
+}
+
+func (h *showWebhookSubscriptionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	subscription, err := h.webhookSubscriptionService.FindById(
+		r.Context(), mux.Vars(r)["webhook_id"],
+	)
+	if err != nil {
+		if _, ok := err.(*entities.ItemNotFoundError); ok {
+			w.WriteHeader(http.StatusNotFound)
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+			err = entities.NewUnexpectedError(err)
+		}
+
+		jsonPayload, _ := json.Marshal(err)
+		w.Write(jsonPayload)
+		return
+	}
+
+	jsonPayload, _ := json.Marshal(subscription)
+	w.Write(jsonPayload)
+}
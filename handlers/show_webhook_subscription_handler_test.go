@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/suite"
+
+	"verifymy-golang-test/entities"
+	mock_services "verifymy-golang-test/mocks/services"
+	"verifymy-golang-test/models"
+)
+
+type showWebhookSubscriptionHandlerTestSuite struct {
+	suite.Suite
+	ctrl                           *gomock.Controller
+	webhookSubscriptionServiceMock *mock_services.MockWebhookSubscriptionService
+	handler                        Handler
+}
+
+func TestShowWebhookSubscriptionHandlerTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(showWebhookSubscriptionHandlerTestSuite))
+}
+
+func (s *showWebhookSubscriptionHandlerTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.webhookSubscriptionServiceMock = mock_services.NewMockWebhookSubscriptionService(s.ctrl)
+	s.handler = NewShowWebhookSubscriptionHandler(s.webhookSubscriptionServiceMock)
+}
+
+func (s *showWebhookSubscriptionHandlerTestSuite) TestMethod() {
+	s.Equal([]string{"GET"}, s.handler.Method())
+}
+
+func (s *showWebhookSubscriptionHandlerTestSuite) TestRoute() {
+	s.Equal("/webhooks/{webhook_id}", s.handler.Route())
+}
+
+func (s *showWebhookSubscriptionHandlerTestSuite) TestPermission() {
+	s.Equal(
+		models.PermissionManageWebhooks,
+		s.handler.(AuthorizedHandler).Permission(),
+	)
+}
+
+func (s *showWebhookSubscriptionHandlerTestSuite) TestServeHTTP() {
+	subscriptionId := uuid.New()
+	createdAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	subscription := &models.WebhookSubscription{
+		ID:         subscriptionId,
+		URL:        "https://partner.example.com/hooks",
+		EventTypes: models.StringList{"user.deleted"},
+		Secret:     "a-very-long-secret",
+		CreatedAt:  createdAt,
+		UpdatedAt:  createdAt,
+	}
+
+	tests := []struct {
+		description        string
+		subscription       *models.WebhookSubscription
+		findByIdError      error
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			description:        "Success",
+			subscription:       subscription,
+			expectedStatusCode: http.StatusOK,
+			expectedBody: `{
+				"id":"` + subscriptionId.String() + `",
+				"url":"https://partner.example.com/hooks",
+				"event_types":["user.deleted"],
+				"created_at":"2024-05-01T10:00:00Z",
+				"updated_at":"2024-05-01T10:00:00Z"
+			}`,
+		},
+		{
+			description:        "Webhook not found",
+			findByIdError:      entities.NewItemNotFoundError("Webhook", subscriptionId.String()),
+			expectedStatusCode: http.StatusNotFound,
+			expectedBody:       `{"message":"Webhook not found","details":["` + subscriptionId.String() + `"]}`,
+		},
+		{
+			description:        "Unexpected error",
+			findByIdError:      errors.New("error finding webhook"),
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedBody:       `{"message":"unexpected error","details":["error finding webhook"]}`,
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			request := httptest.NewRequest(http.MethodGet, "/webhooks/"+subscriptionId.String(), nil)
+			request = mux.SetURLVars(request, map[string]string{"webhook_id": subscriptionId.String()})
+			response := httptest.NewRecorder()
+
+			s.webhookSubscriptionServiceMock.EXPECT().FindById(
+				request.Context(), subscriptionId.String(),
+			).Return(test.subscription, test.findByIdError)
+
+			s.handler.ServeHTTP(response, request)
+
+			s.Equal(test.expectedStatusCode, response.Code)
+			s.JSONEq(test.expectedBody, response.Body.String())
+		})
+	}
+}
@@ -5,7 +5,6 @@ import (
 	"net/http"
 
 	"verifymy-golang-test/entities"
-	"verifymy-golang-test/models"
 	"verifymy-golang-test/services"
 )
 
@@ -32,11 +31,10 @@ func (h *signUpHandler) Route() string {
 func (h *signUpHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
-	var payload models.User
-	err := json.NewDecoder(r.Body).Decode(&payload)
+	// Members like created_at are ignored, so users can't date their account
+	payload, err := decodeUserMembers(r, signUpMembers, nil)
 	if err != nil {
 		w.WriteHeader(http.StatusUnprocessableEntity)
-		err = entities.NewError("Invalid JSON", []string{err.Error()})
 
 		jsonPayload, _ := json.Marshal(err)
 		w.Write(jsonPayload)
@@ -47,6 +45,8 @@ func (h *signUpHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		if _, ok := err.(*entities.EmailAlreadyInUseError); ok {
 			w.WriteHeader(http.StatusForbidden)
+		} else if _, ok := err.(*entities.ValidationError); ok {
+			w.WriteHeader(http.StatusUnprocessableEntity)
 		} else {
 			w.WriteHeader(http.StatusInternalServerError)
 			err = entities.NewUnexpectedError(err)
@@ -72,6 +72,29 @@ func (s *signUpHandlerTestSuite) TestServeHTTP() {
 			},
 			expectedStatusCode: http.StatusOK,
 		},
+		{
+			description: "Non-editable fields are ignored",
+			payload: `{
+				"name": "Bruce Wayne",
+				"email": "bruce.wayne@jleague.io",
+				"date_of_birth": "1939-05-01",
+				"password": "lov3u4lfr3d",
+				"address": "Gotham City",
+				"role": "admin",
+				"status": "active",
+				"created_at": "2000-01-01T00:00:00Z",
+				"updated_at": "2000-01-01T00:00:00Z"
+			}`,
+			signUpResponse: &entities.Credentials{
+				AccessToken: "ACCESS_TOKEN",
+				ExpiresAt:   1,
+			},
+			expectedResponse: map[string]interface{}{
+				"access_token": "ACCESS_TOKEN",
+				"expires_at":   float64(1),
+			},
+			expectedStatusCode: http.StatusOK,
+		},
 		{
 			description:         "Invalid JSON",
 			payload:             `{"name": "Bruce Wayne"`,
@@ -98,6 +121,26 @@ func (s *signUpHandlerTestSuite) TestServeHTTP() {
 			},
 			expectedStatusCode: http.StatusForbidden,
 		},
+		{
+			description: "Guardian required",
+			payload: `{
+				"name": "Bruce Wayne",
+				"email": "bruce.wayne@jleague.io",
+				"date_of_birth": "1939-05-01",
+				"password": "lov3u4lfr3d",
+				"address": "Gotham City"
+			}`,
+			signUpError: entities.NewValidationError(
+				"guardian_email is required for users under the age of consent",
+			),
+			expectedResponse: map[string]interface{}{
+				"message": "validation failed",
+				"details": []interface{}{
+					"guardian_email is required for users under the age of consent",
+				},
+			},
+			expectedStatusCode: http.StatusUnprocessableEntity,
+		},
 		{
 			description: "Unexpected error",
 			payload: `{
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"verifymy-golang-test/entities"
+	"verifymy-golang-test/models"
+	"verifymy-golang-test/services"
+)
+
+type startAgeVerificationHandler struct {
+	ageVerificationSessionService services.AgeVerificationSessionService
+}
+
+func NewStartAgeVerificationHandler(
+	ageVerificationSessionService services.AgeVerificationSessionService,
+) Handler {
+	return &startAgeVerificationHandler{
+		ageVerificationSessionService: ageVerificationSessionService,
+	}
+}
+
+func (h *startAgeVerificationHandler) Method() []string {
+	return []string{http.MethodPost}
+}
+
+func (h *startAgeVerificationHandler) Route() string {
+	return "/age_verifications"
+}
+
+// Scope has sessions started by clients on behalf of the signed in user, so
+// they are attributed to and limited per client
+func (h *startAgeVerificationHandler) Scope() string {
+	return models.ClientScopeAgeVerification
+}
+
+func (h *startAgeVerificationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var payload entities.AgeVerificationSessionRequest
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		err = entities.NewError("Invalid JSON", []string{err.Error()})
+
+		jsonPayload, _ := json.Marshal(err)
+		w.Write(jsonPayload)
+		return
+	}
+
+	session, err := h.ageVerificationSessionService.Start(r.Context(), payload)
+	if err != nil {
+		if _, ok := err.(*entities.ValidationError); ok {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+		} else if _, ok := err.(*entities.RateLimitExceededError); ok {
+			w.WriteHeader(http.StatusTooManyRequests)
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+			err = entities.NewUnexpectedError(err)
+		}
+
+		jsonPayload, _ := json.Marshal(err)
+		w.Write(jsonPayload)
+		return
+	}
+
+	w.Header().Set("Location", "/age_verifications/"+session.ID.String())
+	w.WriteHeader(http.StatusCreated)
+
+	jsonPayload, _ := json.Marshal(session)
+	w.Write(jsonPayload)
+}
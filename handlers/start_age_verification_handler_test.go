@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	"verifymy-golang-test/entities"
+	mock_services "verifymy-golang-test/mocks/services"
+	"verifymy-golang-test/models"
+)
+
+type startAgeVerificationHandlerTestSuite struct {
+	suite.Suite
+	ctrl                              *gomock.Controller
+	ageVerificationSessionServiceMock *mock_services.MockAgeVerificationSessionService
+	handler                           Handler
+}
+
+func TestStartAgeVerificationHandlerTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(startAgeVerificationHandlerTestSuite))
+}
+
+func (s *startAgeVerificationHandlerTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.ageVerificationSessionServiceMock = mock_services.NewMockAgeVerificationSessionService(s.ctrl)
+	s.handler = NewStartAgeVerificationHandler(s.ageVerificationSessionServiceMock)
+}
+
+func (s *startAgeVerificationHandlerTestSuite) TestMethod() {
+	s.Equal([]string{"POST"}, s.handler.Method())
+}
+
+func (s *startAgeVerificationHandlerTestSuite) TestRoute() {
+	s.Equal("/age_verifications", s.handler.Route())
+}
+
+func (s *startAgeVerificationHandlerTestSuite) TestScope() {
+	s.Equal(models.ClientScopeAgeVerification, s.handler.(ClientHandler).Scope())
+}
+
+func (s *startAgeVerificationHandlerTestSuite) TestServeHTTP() {
+	sessionId := uuid.New()
+	createdAt := time.Date(2023, 6, 30, 12, 0, 0, 0, time.UTC)
+	session := &models.AgeVerificationSession{
+		ID:          sessionId,
+		Method:      "document",
+		Country:     "US",
+		Category:    "alcohol",
+		AgeOver:     21,
+		Status:      models.AgeVerificationSessionStatusPending,
+		RedirectURL: "https://app.example.com/verified?reference=sim_1",
+		ExpiresAt:   createdAt.Add(30 * time.Minute),
+		CreatedAt:   createdAt,
+	}
+	method := "document"
+	country := "US"
+	category := "alcohol"
+
+	tests := []struct {
+		description        string
+		body               string
+		skipStart          bool
+		startError         error
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			description:        "Success",
+			body:               `{"method":"document","country":"US","category":"alcohol"}`,
+			expectedStatusCode: http.StatusCreated,
+			expectedBody: `{
+				"id":"` + sessionId.String() + `",
+				"client_id":null,
+				"method":"document",
+				"country":"US",
+				"category":"alcohol",
+				"age_over":21,
+				"status":"pending",
+				"redirect_url":"https://app.example.com/verified?reference=sim_1",
+				"failure_reason":null,
+				"expires_at":"2023-06-30T12:30:00Z",
+				"completed_at":null,
+				"created_at":"2023-06-30T12:00:00Z"
+			}`,
+		},
+		{
+			description:        "Invalid JSON",
+			body:               `{`,
+			skipStart:          true,
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			expectedBody:       `{"message":"Invalid JSON","details":["unexpected EOF"]}`,
+		},
+		{
+			description:        "Validation error",
+			body:               `{"method":"document","country":"US","category":"alcohol"}`,
+			startError:         entities.NewValidationError("age_over must be between 1 and 100"),
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			expectedBody:       `{"message":"validation failed","details":["age_over must be between 1 and 100"]}`,
+		},
+		{
+			description:        "Rate limit exceeded",
+			body:               `{"method":"document","country":"US","category":"alcohol"}`,
+			startError:         entities.NewRateLimitExceededError("clients can start 1000 age verifications an hour"),
+			expectedStatusCode: http.StatusTooManyRequests,
+			expectedBody:       `{"message":"rate limit exceeded","details":["clients can start 1000 age verifications an hour"]}`,
+		},
+		{
+			description:        "Unexpected error",
+			body:               `{"method":"document","country":"US","category":"alcohol"}`,
+			startError:         errors.New("provider unavailable"),
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedBody:       `{"message":"unexpected error","details":["provider unavailable"]}`,
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			request := httptest.NewRequest(
+				http.MethodPost, "/age_verifications", strings.NewReader(test.body),
+			)
+			response := httptest.NewRecorder()
+
+			if !test.skipStart {
+				var started *models.AgeVerificationSession
+				if test.startError == nil {
+					started = session
+				}
+				s.ageVerificationSessionServiceMock.EXPECT().Start(
+					request.Context(),
+					entities.AgeVerificationSessionRequest{
+						Method: &method, Country: &country, Category: &category,
+					},
+				).Return(started, test.startError)
+			}
+
+			s.handler.ServeHTTP(response, request)
+
+			s.Equal(test.expectedStatusCode, response.Code)
+			s.JSONEq(test.expectedBody, response.Body.String())
+			if test.expectedStatusCode == http.StatusCreated {
+				s.Equal("/age_verifications/"+sessionId.String(), response.Header().Get("Location"))
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"verifymy-golang-test/entities"
+	"verifymy-golang-test/services"
+)
+
+type sudoHandler struct {
+	authService services.AuthService
+}
+
+func NewSudoHandler(
+	authService services.AuthService,
+) Handler {
+	return &sudoHandler{
+		authService: authService,
+	}
+}
+
+func (h *sudoHandler) Method() []string {
+	return []string{http.MethodPost}
+}
+
+func (h *sudoHandler) Route() string {
+	return "/auth/sudo"
+}
+
+func (h *sudoHandler) AllowsPendingConsent() bool {
+	return true
+}
+
+func (h *sudoHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var payload map[string]string
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		err = entities.NewError("Invalid JSON", []string{err.Error()})
+
+		jsonPayload, _ := json.Marshal(err)
+		w.Write(jsonPayload)
+		return
+	}
+
+	credentials, err := h.authService.Sudo(r.Context(), payload["password"])
+	if err != nil {
+		if _, ok := err.(*entities.InvalidPasswordError); ok {
+			w.WriteHeader(http.StatusBadRequest)
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+			err = entities.NewUnexpectedError(err)
+		}
+
+		jsonPayload, _ := json.Marshal(err)
+		w.Write(jsonPayload)
+		return
+	}
+
+	jsonPayload, _ := json.Marshal(credentials)
+	w.Write(jsonPayload)
+}
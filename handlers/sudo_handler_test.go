@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/suite"
+
+	"verifymy-golang-test/entities"
+	mock_services "verifymy-golang-test/mocks/services"
+)
+
+type sudoHandlerTestSuite struct {
+	suite.Suite
+	ctrl            *gomock.Controller
+	authServiceMock *mock_services.MockAuthService
+	handler         Handler
+}
+
+func TestSudoHandlerTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(sudoHandlerTestSuite))
+}
+
+func (s *sudoHandlerTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.authServiceMock = mock_services.NewMockAuthService(s.ctrl)
+	s.handler = NewSudoHandler(s.authServiceMock)
+}
+
+func (s *sudoHandlerTestSuite) TestMethod() {
+	s.Equal([]string{"POST"}, s.handler.Method())
+}
+
+func (s *sudoHandlerTestSuite) TestRoute() {
+	s.Equal("/auth/sudo", s.handler.Route())
+}
+
+func (s *sudoHandlerTestSuite) TestAllowsPendingConsent() {
+	s.True(s.handler.(PendingConsentHandler).AllowsPendingConsent())
+}
+
+func (s *sudoHandlerTestSuite) TestServeHTTP() {
+	tests := []struct {
+		description        string
+		payload            string
+		skipSudo           bool
+		sudoResult         *entities.Credentials
+		sudoError          error
+		expectedStatusCode int
+		expectedPayload    map[string]interface{}
+	}{
+		{
+			description: "Success",
+			payload:     `{"password": "my-password"}`,
+			sudoResult: &entities.Credentials{
+				AccessToken:   "access-token",
+				ExpiresAt:     1000,
+				SudoExpiresAt: 100,
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedPayload: map[string]interface{}{
+				"access_token":    "access-token",
+				"expires_at":      float64(1000),
+				"sudo_expires_at": float64(100),
+			},
+		},
+		{
+			description:        "Invalid JSON",
+			payload:            `{"password": "my-password"`,
+			skipSudo:           true,
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			expectedPayload: map[string]interface{}{
+				"message": "Invalid JSON",
+				"details": []interface{}{"unexpected EOF"},
+			},
+		},
+		{
+			description:        "Wrong password",
+			payload:            `{"password": "my-password"}`,
+			sudoError:          entities.NewInvalidPasswordError(),
+			expectedStatusCode: http.StatusBadRequest,
+			expectedPayload: map[string]interface{}{
+				"message": "invalid password",
+				"details": nil,
+			},
+		},
+		{
+			description:        "Unexpected error",
+			payload:            `{"password": "my-password"}`,
+			sudoError:          errors.New("error signing token"),
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedPayload: map[string]interface{}{
+				"message": "unexpected error",
+				"details": []interface{}{"error signing token"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			request := httptest.NewRequest(
+				http.MethodPost, "/auth/sudo", strings.NewReader(test.payload),
+			)
+			response := httptest.NewRecorder()
+
+			if !test.skipSudo {
+				s.authServiceMock.EXPECT().Sudo(
+					request.Context(), "my-password",
+				).Return(test.sudoResult, test.sudoError)
+			}
+
+			s.handler.ServeHTTP(response, request)
+
+			var jsonPayload map[string]interface{}
+			_ = json.Unmarshal(response.Body.Bytes(), &jsonPayload)
+
+			s.Equal(test.expectedPayload, jsonPayload)
+			s.Equal(test.expectedStatusCode, response.Code)
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"verifymy-golang-test/entities"
+	"verifymy-golang-test/models"
+	"verifymy-golang-test/services"
+)
+
+type updateClientHandler struct {
+	clientService services.ClientService
+}
+
+func NewUpdateClientHandler(clientService services.ClientService) Handler {
+	return &updateClientHandler{clientService: clientService}
+}
+
+func (h *updateClientHandler) Method() []string {
+	return []string{http.MethodPatch}
+}
+
+func (h *updateClientHandler) Route() string {
+	return "/clients/{client_id}"
+}
+
+func (h *updateClientHandler) Permission() models.Permission {
+	return models.PermissionManageClients
+}
+
+// ServeHTTP changes the fields given and keeps the others. The secret is
+// rotated apart, see rotateClientSecretHandler.
+func (h *updateClientHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var payload entities.ClientChanges
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		err = entities.NewError("Invalid JSON", []string{err.Error()})
+
+		jsonPayload, _ := json.Marshal(err)
+		w.Write(jsonPayload)
+		return
+	}
+
+	client, err := h.clientService.Update(r.Context(), mux.Vars(r)["client_id"], payload)
+	if err != nil {
+		if _, ok := err.(*entities.ItemNotFoundError); ok {
+			w.WriteHeader(http.StatusNotFound)
+		} else if _, ok := err.(*entities.ValidationError); ok {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+			err = entities.NewUnexpectedError(err)
+		}
+
+		jsonPayload, _ := json.Marshal(err)
+		w.Write(jsonPayload)
+		return
+	}
+
+	jsonPayload, _ := json.Marshal(client)
+	w.Write(jsonPayload)
+}
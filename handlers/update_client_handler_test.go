@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/suite"
+
+	"verifymy-golang-test/entities"
+	mock_services "verifymy-golang-test/mocks/services"
+	"verifymy-golang-test/models"
+)
+
+type updateClientHandlerTestSuite struct {
+	suite.Suite
+	ctrl              *gomock.Controller
+	clientServiceMock *mock_services.MockClientService
+	handler           Handler
+}
+
+func TestUpdateClientHandlerTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(updateClientHandlerTestSuite))
+}
+
+func (s *updateClientHandlerTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.clientServiceMock = mock_services.NewMockClientService(s.ctrl)
+	s.handler = NewUpdateClientHandler(s.clientServiceMock)
+}
+
+func (s *updateClientHandlerTestSuite) TestMethod() {
+	s.Equal([]string{"PATCH"}, s.handler.Method())
+}
+
+func (s *updateClientHandlerTestSuite) TestRoute() {
+	s.Equal("/clients/{client_id}", s.handler.Route())
+}
+
+func (s *updateClientHandlerTestSuite) TestPermission() {
+	s.Equal(
+		models.PermissionManageClients,
+		s.handler.(AuthorizedHandler).Permission(),
+	)
+}
+
+func (s *updateClientHandlerTestSuite) TestServeHTTP() {
+	clientId := uuid.New()
+	createdAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	client := &models.Client{
+		ID:             clientId,
+		Name:           "Shop",
+		RedirectURIs:   models.StringList{},
+		AllowedOrigins: models.StringList{"https://shop.example.com"},
+		Scopes:         models.StringList{"age_tokens"},
+		SecretHash:     "hashed-secret",
+		CreatedAt:      createdAt,
+		UpdatedAt:      createdAt,
+	}
+	allowedOrigins := []string{"https://shop.example.com"}
+	body := `{"allowed_origins":["https://shop.example.com"]}`
+
+	tests := []struct {
+		description        string
+		body               string
+		skipUpdate         bool
+		updateError        error
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			description:        "Success",
+			body:               body,
+			expectedStatusCode: http.StatusOK,
+			expectedBody: `{
+				"id":"` + clientId.String() + `",
+				"name":"Shop",
+				"redirect_uris":[],
+				"allowed_origins":["https://shop.example.com"],
+				"scopes":["age_tokens"],
+				"created_at":"2024-05-01T10:00:00Z",
+				"updated_at":"2024-05-01T10:00:00Z"
+			}`,
+		},
+		{
+			description:        "Invalid JSON",
+			body:               `{`,
+			skipUpdate:         true,
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			expectedBody:       `{"message":"Invalid JSON","details":["unexpected EOF"]}`,
+		},
+		{
+			description:        "Client not found",
+			body:               body,
+			updateError:        entities.NewItemNotFoundError("Client", clientId.String()),
+			expectedStatusCode: http.StatusNotFound,
+			expectedBody:       `{"message":"Client not found","details":["` + clientId.String() + `"]}`,
+		},
+		{
+			description: "Validation error",
+			body:        body,
+			updateError: entities.NewValidationError(
+				"allowed_origins must only hold origins, like https://example.com",
+			),
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			expectedBody: `{
+				"message":"validation failed",
+				"details":["allowed_origins must only hold origins, like https://example.com"]
+			}`,
+		},
+		{
+			description:        "Unexpected error",
+			body:               body,
+			updateError:        errors.New("error updating client"),
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedBody:       `{"message":"unexpected error","details":["error updating client"]}`,
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			request := httptest.NewRequest(
+				http.MethodPatch, "/clients/"+clientId.String(), strings.NewReader(test.body),
+			)
+			request = mux.SetURLVars(request, map[string]string{"client_id": clientId.String()})
+			response := httptest.NewRecorder()
+
+			if !test.skipUpdate {
+				var updated *models.Client
+				if test.updateError == nil {
+					updated = client
+				}
+				s.clientServiceMock.EXPECT().Update(
+					request.Context(),
+					clientId.String(),
+					entities.ClientChanges{AllowedOrigins: &allowedOrigins},
+				).Return(updated, test.updateError)
+			}
+
+			s.handler.ServeHTTP(response, request)
+
+			s.Equal(test.expectedStatusCode, response.Code)
+			s.JSONEq(test.expectedBody, response.Body.String())
+		})
+	}
+}
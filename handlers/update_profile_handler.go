@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"net/http"
 
+	"verifymy-golang-test/common"
 	"verifymy-golang-test/entities"
 	"verifymy-golang-test/models"
 	"verifymy-golang-test/services"
@@ -30,23 +31,41 @@ func (h *updateProfileHandler) Route() string {
 }
 
 func (h *updateProfileHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	var payload models.User
-	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+	user := r.Context().Value(common.AuthUser).(*models.User)
+
+	expectedVersion, ok := checkIfMatch(r, user.Version)
+	if !ok {
 		w.Header().Set("Content-Type", "application/json")
 
-		w.WriteHeader(http.StatusUnprocessableEntity)
-		err = entities.NewError("Invalid JSON", []string{err.Error()})
+		w.WriteHeader(http.StatusPreconditionFailed)
+		jsonPayload, _ := json.Marshal(entities.NewPreconditionFailedError())
+		w.Write(jsonPayload)
+		return
+	}
+
+	// The password is rejected rather than ignored, since it would look like
+	// it was changed. It is only changed through POST /profile/password.
+	payload, err := decodeEditableUser(r, profileEditableFields, profileForbiddenMembers)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
 
+		w.WriteHeader(http.StatusUnprocessableEntity)
 		jsonPayload, _ := json.Marshal(err)
 		w.Write(jsonPayload)
 		return
 	}
 
-	if err := h.userService.UpdateProfile(r.Context(), payload); err != nil {
+	if err := h.userService.UpdateProfile(r.Context(), payload, expectedVersion); err != nil {
 		w.Header().Set("Content-Type", "application/json")
 
-		w.WriteHeader(http.StatusInternalServerError)
-		err = entities.NewUnexpectedError(err)
+		if _, ok := err.(*entities.PreconditionFailedError); ok {
+			w.WriteHeader(http.StatusPreconditionFailed)
+		} else if _, ok := err.(*entities.ValidationError); ok {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+			err = entities.NewUnexpectedError(err)
+		}
 
 		jsonPayload, _ := json.Marshal(err)
 		w.Write(jsonPayload)
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
@@ -11,6 +12,8 @@ import (
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/suite"
 
+	"verifymy-golang-test/common"
+	"verifymy-golang-test/entities"
 	mock_services "verifymy-golang-test/mocks/services"
 	"verifymy-golang-test/models"
 )
@@ -43,12 +46,17 @@ func (s *updateProfileHandlerTestSuite) TestRoute() {
 
 func (s *updateProfileHandlerTestSuite) TestServeHTTP() {
 	tests := []struct {
-		description        string
-		payload            string
-		expectedPayload    models.User
-		updateProfileError error
-		expectedStatusCode int
-		invalidJsonError   bool
+		description             string
+		payload                 string
+		user                    *models.User
+		ifMatch                 string
+		expectedPayload         models.User
+		expectedVersion         int64
+		updateProfileError      error
+		expectedStatusCode      int
+		expectedBody            string
+		skipUpdateProfile       bool
+		unexpectedErrorResponse bool
 	}{
 		{
 			description:        "Success",
@@ -56,18 +64,94 @@ func (s *updateProfileHandlerTestSuite) TestServeHTTP() {
 			expectedPayload:    models.User{Name: "new name"},
 			expectedStatusCode: http.StatusNoContent,
 		},
+		{
+			description: "Non-editable fields are ignored",
+			payload: `{"name": "new name", "role": "admin", "status": "active",
+				"created_at": "2000-01-01T00:00:00Z", "updated_at": "2000-01-01T00:00:00Z"}`,
+			expectedPayload:    models.User{Name: "new name"},
+			expectedStatusCode: http.StatusNoContent,
+		},
+		{
+			description: "Pending user can't skip parental consent",
+			payload: `{"name": "new name", "status": "active",
+				"guardian_email": "miles.morales@nyork.co"}`,
+			user: &models.User{
+				Version:       3,
+				Status:        models.UserStatusPendingParentalConsent,
+				GuardianEmail: "rio.morales@nyork.co",
+			},
+			expectedPayload:    models.User{Name: "new name"},
+			expectedStatusCode: http.StatusNoContent,
+		},
+		{
+			description:        "Success with matching If-Match",
+			payload:            `{"name": "new name"}`,
+			ifMatch:            `"3"`,
+			expectedPayload:    models.User{Name: "new name"},
+			expectedVersion:    3,
+			expectedStatusCode: http.StatusNoContent,
+		},
+		{
+			description:        "Success with wildcard If-Match",
+			payload:            `{"name": "new name"}`,
+			ifMatch:            "*",
+			expectedPayload:    models.User{Name: "new name"},
+			expectedStatusCode: http.StatusNoContent,
+		},
+		{
+			description:        "Stale If-Match",
+			payload:            `{"name": "new name"}`,
+			ifMatch:            `"2"`,
+			expectedStatusCode: http.StatusPreconditionFailed,
+			expectedBody:       `{"message":"precondition failed","details":["resource was modified since it was fetched"]}`,
+			skipUpdateProfile:  true,
+		},
 		{
 			description:        "Invalid JSON",
 			payload:            `{"name": "new name"`,
 			expectedStatusCode: http.StatusUnprocessableEntity,
-			invalidJsonError:   true,
+			expectedBody:       `{"message":"Invalid JSON","details":["unexpected EOF"]}`,
+			skipUpdateProfile:  true,
+		},
+		{
+			description:        "Password change",
+			payload:            `{"name": "new name", "password": "n3wp4ss"}`,
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			expectedBody:       `{"message":"validation failed","details":["password can only be changed by confirming the current one"]}`,
+			skipUpdateProfile:  true,
 		},
 		{
-			description:        "Unexpected error",
+			description:        "Invalid editable field",
+			payload:            `{"name": 42}`,
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			expectedBody:       `{"message":"Invalid JSON","details":["json: cannot unmarshal number into Go struct field User.name of type string"]}`,
+			skipUpdateProfile:  true,
+		},
+		{
+			description:        "Concurrent modification",
 			payload:            `{"name": "new name"}`,
+			ifMatch:            `"3"`,
 			expectedPayload:    models.User{Name: "new name"},
-			updateProfileError: errors.New("unexpected error"),
-			expectedStatusCode: http.StatusInternalServerError,
+			expectedVersion:    3,
+			updateProfileError: entities.NewPreconditionFailedError(),
+			expectedStatusCode: http.StatusPreconditionFailed,
+			expectedBody:       `{"message":"precondition failed","details":["resource was modified since it was fetched"]}`,
+		},
+		{
+			description:        "Email change",
+			payload:            `{"email": "miles.morales@nyork.co"}`,
+			expectedPayload:    models.User{Email: "miles.morales@nyork.co"},
+			updateProfileError: entities.NewValidationError("email can only be changed by confirming the new address"),
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			expectedBody:       `{"message":"validation failed","details":["email can only be changed by confirming the new address"]}`,
+		},
+		{
+			description:             "Unexpected error",
+			payload:                 `{"name": "new name"}`,
+			expectedPayload:         models.User{Name: "new name"},
+			updateProfileError:      errors.New("unexpected error"),
+			expectedStatusCode:      http.StatusInternalServerError,
+			unexpectedErrorResponse: true,
 		},
 	}
 
@@ -76,21 +160,26 @@ func (s *updateProfileHandlerTestSuite) TestServeHTTP() {
 			request := httptest.NewRequest(
 				http.MethodPut, "/profile", bytes.NewReader([]byte(test.payload)),
 			)
+			user := test.user
+			if user == nil {
+				user = &models.User{Version: 3}
+			}
+			request = request.WithContext(context.WithValue(
+				request.Context(), common.AuthUser, user,
+			))
+			if test.ifMatch != "" {
+				request.Header.Set("If-Match", test.ifMatch)
+			}
 			response := httptest.NewRecorder()
 
-			if !test.invalidJsonError {
+			if !test.skipUpdateProfile {
 				s.userServiceMock.EXPECT().UpdateProfile(
-					request.Context(), test.expectedPayload,
+					request.Context(), test.expectedPayload, test.expectedVersion,
 				).Return(test.updateProfileError)
 			}
 
 			s.handler.ServeHTTP(response, request)
-			if test.invalidJsonError {
-				s.Equal(
-					response.Body.String(),
-					`{"message":"Invalid JSON","details":["unexpected EOF"]}`,
-				)
-			} else if test.updateProfileError != nil {
+			if test.unexpectedErrorResponse {
 				s.Equal(
 					response.Body.String(),
 					fmt.Sprintf(
@@ -98,6 +187,8 @@ func (s *updateProfileHandlerTestSuite) TestServeHTTP() {
 						test.updateProfileError.Error(),
 					),
 				)
+			} else if test.expectedBody != "" {
+				s.Equal(test.expectedBody, response.Body.String())
 			}
 			s.Equal(test.expectedStatusCode, response.Code)
 		})
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"verifymy-golang-test/entities"
+	"verifymy-golang-test/models"
+	"verifymy-golang-test/services"
+)
+
+type updateWebhookSubscriptionHandler struct {
+	webhookSubscriptionService services.WebhookSubscriptionService
+}
+
+func NewUpdateWebhookSubscriptionHandler(
+	webhookSubscriptionService services.WebhookSubscriptionService,
+) Handler {
+	return &updateWebhookSubscriptionHandler{
+		webhookSubscriptionService: webhookSubscriptionService,
+	}
+}
+
+func (h *updateWebhookSubscriptionHandler) Method() []string {
+	return []string{http.MethodPatch}
+}
+
+func (h *updateWebhookSubscriptionHandler) Route() string {
+	return "/webhooks/{webhook_id}"
+}
+
+func (h *updateWebhookSubscriptionHandler) Permission() models.Permission {
+	return models.PermissionManageWebhooks
+}
+
+// ServeHTTP changes the fields given and keeps the others. Setting the
+// secret rotates it, deliveries are signed with the new one right away.
+func (h *updateWebhookSubscriptionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var payload entities.WebhookSubscriptionChanges
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		err = entities.NewError("Invalid JSON", []string{err.Error()})
+
+		jsonPayload, _ := json.Marshal(err)
+		w.Write(jsonPayload)
+		return
+	}
+
+	subscription, err := h.webhookSubscriptionService.Update(
+		r.Context(), mux.Vars(r)["webhook_id"], payload,
+	)
+	if err != nil {
+		if _, ok := err.(*entities.ItemNotFoundError); ok {
+			w.WriteHeader(http.StatusNotFound)
+		} else if _, ok := err.(*entities.ValidationError); ok {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+			err = entities.NewUnexpectedError(err)
+		}
+
+		jsonPayload, _ := json.Marshal(err)
+		w.Write(jsonPayload)
+		return
+	}
+
+	jsonPayload, _ := json.Marshal(subscription)
+	w.Write(jsonPayload)
+}
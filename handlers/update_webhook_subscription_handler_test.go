@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/suite"
+
+	"verifymy-golang-test/entities"
+	mock_services "verifymy-golang-test/mocks/services"
+	"verifymy-golang-test/models"
+)
+
+type updateWebhookSubscriptionHandlerTestSuite struct {
+	suite.Suite
+	ctrl                           *gomock.Controller
+	webhookSubscriptionServiceMock *mock_services.MockWebhookSubscriptionService
+	handler                        Handler
+}
+
+func TestUpdateWebhookSubscriptionHandlerTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(updateWebhookSubscriptionHandlerTestSuite))
+}
+
+func (s *updateWebhookSubscriptionHandlerTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.webhookSubscriptionServiceMock = mock_services.NewMockWebhookSubscriptionService(s.ctrl)
+	s.handler = NewUpdateWebhookSubscriptionHandler(s.webhookSubscriptionServiceMock)
+}
+
+func (s *updateWebhookSubscriptionHandlerTestSuite) TestMethod() {
+	s.Equal([]string{"PATCH"}, s.handler.Method())
+}
+
+func (s *updateWebhookSubscriptionHandlerTestSuite) TestRoute() {
+	s.Equal("/webhooks/{webhook_id}", s.handler.Route())
+}
+
+func (s *updateWebhookSubscriptionHandlerTestSuite) TestPermission() {
+	s.Equal(
+		models.PermissionManageWebhooks,
+		s.handler.(AuthorizedHandler).Permission(),
+	)
+}
+
+func (s *updateWebhookSubscriptionHandlerTestSuite) TestServeHTTP() {
+	subscriptionId := uuid.New()
+	createdAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	subscription := &models.WebhookSubscription{
+		ID:         subscriptionId,
+		URL:        "https://partner.example.com/hooks",
+		EventTypes: models.StringList{"user.registered", "user.deleted"},
+		Secret:     "another-long-secret",
+		CreatedAt:  createdAt,
+		UpdatedAt:  createdAt,
+	}
+	eventTypes := []string{"user.registered", "user.deleted"}
+	secret := "another-long-secret"
+
+	tests := []struct {
+		description        string
+		body               string
+		skipUpdate         bool
+		updateError        error
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			description:        "Success",
+			body:               `{"event_types":["user.registered","user.deleted"],"secret":"another-long-secret"}`,
+			expectedStatusCode: http.StatusOK,
+			expectedBody: `{
+				"id":"` + subscriptionId.String() + `",
+				"url":"https://partner.example.com/hooks",
+				"event_types":["user.registered","user.deleted"],
+				"created_at":"2024-05-01T10:00:00Z",
+				"updated_at":"2024-05-01T10:00:00Z"
+			}`,
+		},
+		{
+			description:        "Invalid JSON",
+			body:               `[]`,
+			skipUpdate:         true,
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			expectedBody: `{"message":"Invalid JSON","details":[` +
+				`"json: cannot unmarshal array into Go value of type entities.WebhookSubscriptionChanges"]}`,
+		},
+		{
+			description:        "Webhook not found",
+			body:               `{"event_types":["user.registered","user.deleted"],"secret":"another-long-secret"}`,
+			updateError:        entities.NewItemNotFoundError("Webhook", subscriptionId.String()),
+			expectedStatusCode: http.StatusNotFound,
+			expectedBody:       `{"message":"Webhook not found","details":["` + subscriptionId.String() + `"]}`,
+		},
+		{
+			description:        "Validation error",
+			body:               `{"event_types":["user.registered","user.deleted"],"secret":"another-long-secret"}`,
+			updateError:        entities.NewValidationError("url must be an absolute http or https URL"),
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			expectedBody:       `{"message":"validation failed","details":["url must be an absolute http or https URL"]}`,
+		},
+		{
+			description:        "Unexpected error",
+			body:               `{"event_types":["user.registered","user.deleted"],"secret":"another-long-secret"}`,
+			updateError:        errors.New("error updating webhook"),
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedBody:       `{"message":"unexpected error","details":["error updating webhook"]}`,
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			request := httptest.NewRequest(
+				http.MethodPatch, "/webhooks/"+subscriptionId.String(), strings.NewReader(test.body),
+			)
+			request = mux.SetURLVars(request, map[string]string{"webhook_id": subscriptionId.String()})
+			response := httptest.NewRecorder()
+
+			if !test.skipUpdate {
+				var updated *models.WebhookSubscription
+				if test.updateError == nil {
+					updated = subscription
+				}
+				s.webhookSubscriptionServiceMock.EXPECT().Update(
+					request.Context(),
+					subscriptionId.String(),
+					entities.WebhookSubscriptionChanges{EventTypes: &eventTypes, Secret: &secret},
+				).Return(updated, test.updateError)
+			}
+
+			s.handler.ServeHTTP(response, request)
+
+			s.Equal(test.expectedStatusCode, response.Code)
+			s.JSONEq(test.expectedBody, response.Body.String())
+		})
+	}
+}
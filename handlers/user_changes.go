@@ -0,0 +1,305 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/mail"
+	"sort"
+	"time"
+
+	"verifymy-golang-test/entities"
+	"verifymy-golang-test/models"
+	"verifymy-golang-test/utils"
+)
+
+const (
+	mergePatchMediaType = "application/merge-patch+json"
+	jsonPatchMediaType  = "application/json-patch+json"
+)
+
+// userField describes a user field that can be edited through the API.
+// current returns its JSON value, nil when unset, and parse validates a JSON
+// value and returns what is stored in the column.
+type userField struct {
+	name     string
+	required bool
+	current  func(user *models.User) interface{}
+	parse    func(value interface{}) (interface{}, error)
+}
+
+var nameField = userField{
+	name:     "name",
+	required: true,
+	current:  func(user *models.User) interface{} { return user.Name },
+	parse:    parseUserString,
+}
+
+var emailField = userField{
+	name:     "email",
+	required: true,
+	current:  func(user *models.User) interface{} { return user.Email },
+	parse: func(value interface{}) (interface{}, error) {
+		email, err := parseUserString(value)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := mail.ParseAddress(email.(string)); err != nil {
+			return nil, fmt.Errorf("must be a valid email address")
+		}
+
+		return email, nil
+	},
+}
+
+var dateOfBirthField = userField{
+	name:     "date_of_birth",
+	required: true,
+	current: func(user *models.User) interface{} {
+		if time.Time(user.DateOfBirth).IsZero() {
+			return nil
+		}
+
+		return time.Time(user.DateOfBirth).Format(models.DateFormat)
+	},
+	parse: func(value interface{}) (interface{}, error) {
+		dateOfBirth, err := parseUserString(value)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := time.Parse(models.DateFormat, dateOfBirth.(string)); err != nil {
+			return nil, fmt.Errorf("must be a date formatted as YYYY-MM-DD")
+		}
+
+		return dateOfBirth, nil
+	},
+}
+
+var addressField = userField{
+	name:    "address",
+	current: func(user *models.User) interface{} { return user.Address },
+	parse:   parseUserString,
+}
+
+var roleField = userField{
+	name:     "role",
+	required: true,
+	current:  func(user *models.User) interface{} { return user.Role },
+	parse: func(value interface{}) (interface{}, error) {
+		if !utils.SliceContains(models.Roles, value) {
+			return nil, fmt.Errorf("must be one of %v", models.Roles)
+		}
+
+		return value, nil
+	},
+}
+
+// profileEditableFields are the fields users can edit on their own profile
+var profileEditableFields = []userField{
+	nameField, emailField, dateOfBirthField, addressField,
+}
+
+// adminEditableFields are the fields administrators can edit on any user
+var adminEditableFields = []userField{
+	nameField, emailField, dateOfBirthField, addressField, roleField,
+}
+
+func parseUserString(value interface{}) (interface{}, error) {
+	stringValue, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("must be a string")
+	}
+
+	return stringValue, nil
+}
+
+// profileForbiddenMembers are the members users can only change on their
+// profile through a dedicated endpoint, along with why they are rejected
+var profileForbiddenMembers = map[string]string{
+	"password": "password can only be changed by confirming the current one",
+}
+
+// signUpMembers are the members of the body read when users sign up
+var signUpMembers = []string{
+	"name", "email", "date_of_birth", "address", "password", "guardian_email",
+}
+
+// decodeEditableUser decodes the request body into the editable fields of a
+// user. Forbidden members fail with a validation error, given by the map,
+// instead of being silently dropped. Other members are ignored, so a PUT
+// can't write columns such as the status or timestamps of the user.
+func decodeEditableUser(
+	r *http.Request, fields []userField, forbidden map[string]string,
+) (models.User, error) {
+	members := make([]string, 0, len(fields))
+	for _, field := range fields {
+		members = append(members, field.name)
+	}
+
+	return decodeUserMembers(r, members, forbidden)
+}
+
+// decodeUserMembers decodes only the given members of the request body into
+// a user, rejecting the forbidden ones and ignoring the others
+func decodeUserMembers(
+	r *http.Request, members []string, forbidden map[string]string,
+) (models.User, error) {
+	var object map[string]json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&object); err != nil {
+		return models.User{}, entities.NewError("Invalid JSON", []string{err.Error()})
+	}
+
+	rejected := make([]string, 0, len(forbidden))
+	for member := range forbidden {
+		if _, ok := object[member]; ok {
+			rejected = append(rejected, member)
+		}
+	}
+	if len(rejected) > 0 {
+		sort.Strings(rejected)
+
+		return models.User{}, entities.NewValidationError(forbidden[rejected[0]])
+	}
+
+	editable := make(map[string]json.RawMessage, len(members))
+	for _, member := range members {
+		if value, ok := object[member]; ok {
+			editable[member] = value
+		}
+	}
+
+	document, err := json.Marshal(editable)
+	if err != nil {
+		return models.User{}, err
+	}
+
+	var user models.User
+	if err := json.Unmarshal(document, &user); err != nil {
+		return models.User{}, entities.NewError("Invalid JSON", []string{err.Error()})
+	}
+
+	return user, nil
+}
+
+// userDocument returns the JSON document patches are applied to
+func userDocument(fields []userField, user *models.User) map[string]interface{} {
+	document := map[string]interface{}{}
+	for _, field := range fields {
+		if value := field.current(user); value != nil {
+			document[field.name] = value
+		}
+	}
+
+	return document
+}
+
+// parsePatchMediaType returns the media type of a PATCH request, which must
+// be either a JSON Merge Patch or a JSON Patch.
+func parsePatchMediaType(r *http.Request) (string, error) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != mergePatchMediaType && mediaType != jsonPatchMediaType {
+		return "", entities.NewUnsupportedMediaTypeError(
+			mediaType, []string{mergePatchMediaType, jsonPatchMediaType},
+		)
+	}
+
+	return mediaType, nil
+}
+
+func writeUnsupportedPatchMediaType(w http.ResponseWriter, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Accept-Patch", mergePatchMediaType+", "+jsonPatchMediaType)
+
+	w.WriteHeader(http.StatusUnsupportedMediaType)
+	jsonPayload, _ := json.Marshal(err)
+	w.Write(jsonPayload)
+}
+
+// patchUserChanges applies the patch in the request body to the editable
+// fields of user and returns the columns whose value changed.
+func patchUserChanges(
+	r *http.Request, fields []userField, user *models.User, mediaType string,
+) (map[string]interface{}, error) {
+	document := userDocument(fields, user)
+
+	if mediaType == mergePatchMediaType {
+		var patch interface{}
+		if err := json.NewDecoder(r.Body).Decode(&patch); err != nil {
+			return nil, entities.NewError("Invalid JSON", []string{err.Error()})
+		}
+
+		return userChanges(fields, user, utils.MergePatch(document, patch))
+	}
+
+	var operations []utils.JSONPatchOperation
+	if err := json.NewDecoder(r.Body).Decode(&operations); err != nil {
+		return nil, entities.NewError("Invalid JSON", []string{err.Error()})
+	}
+
+	patched, err := utils.ApplyJSONPatch(document, operations)
+	if err != nil {
+		return nil, entities.NewInvalidPatchError(err.Error())
+	}
+
+	return userChanges(fields, user, patched)
+}
+
+// userChanges validates document as the new state of the editable fields
+// of user and returns the columns whose value changed.
+func userChanges(
+	fields []userField, user *models.User, document interface{},
+) (map[string]interface{}, error) {
+	object, ok := document.(map[string]interface{})
+	if !ok {
+		return nil, entities.NewValidationError("user must be an object")
+	}
+
+	members := make([]string, 0, len(object))
+	for member := range object {
+		members = append(members, member)
+	}
+	sort.Strings(members)
+
+	for _, member := range members {
+		editable := false
+		for _, field := range fields {
+			editable = editable || field.name == member
+		}
+
+		if !editable {
+			return nil, entities.NewValidationError(
+				fmt.Sprintf("field %s is not editable", member),
+			)
+		}
+	}
+
+	changes := map[string]interface{}{}
+	for _, field := range fields {
+		value := object[field.name]
+		if value == nil || value == "" {
+			if field.required {
+				return nil, entities.NewValidationError(
+					fmt.Sprintf("field %s is required", field.name),
+				)
+			}
+
+			value = ""
+		}
+
+		parsed, err := field.parse(value)
+		if err != nil {
+			return nil, entities.NewValidationError(
+				fmt.Sprintf("field %s %s", field.name, err.Error()),
+			)
+		}
+
+		if parsed != field.current(user) {
+			changes[field.name] = parsed
+		}
+	}
+
+	return changes, nil
+}
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"verifymy-golang-test/entities"
+	"verifymy-golang-test/models"
+	"verifymy-golang-test/utils"
+)
+
+func parseUserQuery(r *http.Request) (entities.UserQuery, error) {
+	query := r.URL.Query()
+	userQuery := entities.UserQuery{
+		SortField:     entities.DefaultUserSortField,
+		SortDirection: entities.SortAscending,
+		Filters: entities.UserFilters{
+			EmailPrefix: query.Get("email_prefix"),
+			Name:        query.Get("name"),
+		},
+	}
+
+	if sort := query.Get("sort"); sort != "" {
+		if !utils.SliceContains(entities.UserSortFields, sort) {
+			return userQuery, entities.NewInvalidParameterError(
+				"sort", "cannot sort by "+sort,
+			)
+		}
+
+		userQuery.SortField = sort
+	}
+
+	if order := strings.ToLower(query.Get("order")); order != "" {
+		if order != string(entities.SortAscending) && order != string(entities.SortDescending) {
+			return userQuery, entities.NewInvalidParameterError(
+				"order", "must be either asc or desc",
+			)
+		}
+
+		userQuery.SortDirection = entities.SortDirection(order)
+	}
+
+	dateFilters := []struct {
+		parameter string
+		target    **models.Date
+	}{
+		{"date_of_birth_from", &userQuery.Filters.DateOfBirthFrom},
+		{"date_of_birth_to", &userQuery.Filters.DateOfBirthTo},
+	}
+	for _, filter := range dateFilters {
+		parameter, target := filter.parameter, filter.target
+		if value := query.Get(parameter); value != "" {
+			date, err := time.Parse(models.DateFormat, value)
+			if err != nil {
+				return userQuery, entities.NewInvalidParameterError(
+					parameter, "must be a date formatted as YYYY-MM-DD",
+				)
+			}
+
+			modelDate := models.Date(date)
+			*target = &modelDate
+		}
+	}
+
+	timeFilters := []struct {
+		parameter string
+		target    **time.Time
+	}{
+		{"created_from", &userQuery.Filters.CreatedFrom},
+		{"created_to", &userQuery.Filters.CreatedTo},
+	}
+	for _, filter := range timeFilters {
+		parameter, target := filter.parameter, filter.target
+		if value := query.Get(parameter); value != "" {
+			createdAt, err := parseTimeOrDate(value, parameter == "created_to")
+			if err != nil {
+				return userQuery, entities.NewInvalidParameterError(
+					parameter, "must be an RFC 3339 timestamp or a date formatted as YYYY-MM-DD",
+				)
+			}
+
+			*target = &createdAt
+		}
+	}
+
+	page, err := parsePageRequest(r)
+	if err != nil {
+		return userQuery, err
+	}
+
+	if page.Cursor != nil && page.Cursor.Sort != userQuery.SortKey() {
+		return userQuery, entities.NewInvalidParameterError(
+			"cursor", "cursor does not match the requested sort",
+		)
+	}
+
+	userQuery.Page = page
+	return userQuery, nil
+}
+
+// parseTimeOrDate accepts both timestamps and plain dates. A plain date used
+// as an upper bound covers the whole day.
+func parseTimeOrDate(value string, endOfDay bool) (time.Time, error) {
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return parsed, nil
+	}
+
+	parsed, err := time.Parse(models.DateFormat, value)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if endOfDay {
+		parsed = parsed.Add(24*time.Hour - time.Nanosecond)
+	}
+
+	return parsed, nil
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"verifymy-golang-test/entities"
+	"verifymy-golang-test/models"
+	"verifymy-golang-test/services"
+)
+
+type verifyAgeTokenHandler struct {
+	ageTokenService services.AgeTokenService
+}
+
+func NewVerifyAgeTokenHandler(
+	ageTokenService services.AgeTokenService,
+) Handler {
+	return &verifyAgeTokenHandler{
+		ageTokenService: ageTokenService,
+	}
+}
+
+func (h *verifyAgeTokenHandler) Method() []string {
+	return []string{http.MethodPost}
+}
+
+func (h *verifyAgeTokenHandler) Route() string {
+	return "/age_tokens/verify"
+}
+
+func (h *verifyAgeTokenHandler) Scope() string {
+	return models.ClientScopeAgeTokens
+}
+
+// ServeHTTP is for relying parties that would rather not check tokens
+// against the JWKS themselves. Tokens only verify for the client they were
+// issued to.
+func (h *verifyAgeTokenHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var payload entities.AgeTokenVerification
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		err = entities.NewError("Invalid JSON", []string{err.Error()})
+
+		jsonPayload, _ := json.Marshal(err)
+		w.Write(jsonPayload)
+		return
+	}
+
+	claims, err := h.ageTokenService.Verify(r.Context(), payload)
+	if err != nil {
+		if _, ok := err.(*entities.InvalidTokenError); ok {
+			w.WriteHeader(http.StatusBadRequest)
+		} else if _, ok := err.(*entities.ValidationError); ok {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+		} else if _, ok := err.(*entities.InvalidClientError); ok {
+			w.WriteHeader(http.StatusUnauthorized)
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+			err = entities.NewUnexpectedError(err)
+		}
+
+		jsonPayload, _ := json.Marshal(err)
+		w.Write(jsonPayload)
+		return
+	}
+
+	jsonPayload, _ := json.Marshal(claims)
+	w.Write(jsonPayload)
+}
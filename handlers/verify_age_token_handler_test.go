@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/suite"
+
+	"verifymy-golang-test/entities"
+	mock_services "verifymy-golang-test/mocks/services"
+	"verifymy-golang-test/models"
+)
+
+type verifyAgeTokenHandlerTestSuite struct {
+	suite.Suite
+	ctrl                *gomock.Controller
+	ageTokenServiceMock *mock_services.MockAgeTokenService
+	handler             Handler
+}
+
+func TestVerifyAgeTokenHandlerTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(verifyAgeTokenHandlerTestSuite))
+}
+
+func (s *verifyAgeTokenHandlerTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.ageTokenServiceMock = mock_services.NewMockAgeTokenService(s.ctrl)
+	s.handler = NewVerifyAgeTokenHandler(s.ageTokenServiceMock)
+}
+
+func (s *verifyAgeTokenHandlerTestSuite) TestMethod() {
+	s.Equal([]string{"POST"}, s.handler.Method())
+}
+
+func (s *verifyAgeTokenHandlerTestSuite) TestRoute() {
+	s.Equal("/age_tokens/verify", s.handler.Route())
+}
+
+func (s *verifyAgeTokenHandlerTestSuite) TestScope() {
+	s.Equal(models.ClientScopeAgeTokens, s.handler.(ClientHandler).Scope())
+}
+
+func (s *verifyAgeTokenHandlerTestSuite) TestServeHTTP() {
+	token := "eyJ.age.token"
+
+	tests := []struct {
+		description        string
+		body               string
+		skipVerify         bool
+		claims             map[string]interface{}
+		verifyError        error
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			description: "Valid",
+			body:        `{"token":"eyJ.age.token"}`,
+			claims: map[string]interface{}{
+				"aud": "7b1e2f5c-8d2a-4f7e-9a51-3c0d4e6b8f21", "exp": 1688130000, "age_over_18": true,
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `{"aud":"7b1e2f5c-8d2a-4f7e-9a51-3c0d4e6b8f21","exp":1688130000,"age_over_18":true}`,
+		},
+		{
+			description:        "Invalid JSON",
+			body:               `{`,
+			skipVerify:         true,
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			expectedBody:       `{"message":"Invalid JSON","details":["unexpected EOF"]}`,
+		},
+		{
+			description:        "Invalid token",
+			body:               `{"token":"eyJ.age.token"}`,
+			verifyError:        entities.NewInvalidTokenError(),
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       `{"message":"invalid token","details":null}`,
+		},
+		{
+			description:        "Validation error",
+			body:               `{"token":"eyJ.age.token"}`,
+			verifyError:        entities.NewValidationError("token is required"),
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			expectedBody:       `{"message":"validation failed","details":["token is required"]}`,
+		},
+		{
+			description:        "Invalid client",
+			body:               `{"token":"eyJ.age.token"}`,
+			verifyError:        entities.NewInvalidClientError(),
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedBody:       `{"message":"invalid client credentials","details":null}`,
+		},
+		{
+			description:        "Unexpected error",
+			body:               `{"token":"eyJ.age.token"}`,
+			verifyError:        errors.New("keys unavailable"),
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedBody:       `{"message":"unexpected error","details":["keys unavailable"]}`,
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			request := httptest.NewRequest(
+				http.MethodPost, "/age_tokens/verify", strings.NewReader(test.body),
+			)
+			response := httptest.NewRecorder()
+
+			if !test.skipVerify {
+				s.ageTokenServiceMock.EXPECT().Verify(
+					request.Context(),
+					entities.AgeTokenVerification{Token: &token},
+				).Return(test.claims, test.verifyError)
+			}
+
+			s.handler.ServeHTTP(response, request)
+
+			s.Equal(test.expectedStatusCode, response.Code)
+			s.JSONEq(test.expectedBody, response.Body.String())
+		})
+	}
+}
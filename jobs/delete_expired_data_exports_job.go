@@ -0,0 +1,53 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"verifymy-golang-test/services"
+)
+
+const defaultDeleteExpiredDataExportsInterval = time.Hour
+
+type deleteExpiredDataExportsJob struct {
+	log               *zap.Logger
+	dataExportService services.DataExportService
+	interval          time.Duration
+}
+
+// NewDeleteExpiredDataExportsJob runs every DATA_EXPORTS_EXPIRY_INTERVAL, an
+// hour by default.
+func NewDeleteExpiredDataExportsJob(
+	log *zap.Logger,
+	dataExportService services.DataExportService,
+) (Job, error) {
+	interval, err := intervalFromEnv("DATA_EXPORTS_EXPIRY_INTERVAL", defaultDeleteExpiredDataExportsInterval)
+	if err != nil {
+		return nil, err
+	}
+
+	return &deleteExpiredDataExportsJob{
+		log:               log,
+		dataExportService: dataExportService,
+		interval:          interval,
+	}, nil
+}
+
+func (j *deleteExpiredDataExportsJob) Name() string {
+	return "delete_expired_data_exports"
+}
+
+func (j *deleteExpiredDataExportsJob) Interval() time.Duration {
+	return j.interval
+}
+
+func (j *deleteExpiredDataExportsJob) Run(ctx context.Context) error {
+	deleted, err := j.dataExportService.DeleteExpired(ctx)
+	if deleted > 0 {
+		j.log.Info("Deleted expired data exports", zap.Int64("count", deleted))
+	}
+
+	return err
+}
@@ -0,0 +1,53 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"verifymy-golang-test/services"
+)
+
+const defaultDeliverWebhooksInterval = time.Second * 5
+
+type deliverWebhooksJob struct {
+	log                    *zap.Logger
+	webhookDeliveryService services.WebhookDeliveryService
+	interval               time.Duration
+}
+
+// NewDeliverWebhooksJob runs every WEBHOOK_DELIVERIES_INTERVAL, five seconds
+// by default.
+func NewDeliverWebhooksJob(
+	log *zap.Logger,
+	webhookDeliveryService services.WebhookDeliveryService,
+) (Job, error) {
+	interval, err := intervalFromEnv("WEBHOOK_DELIVERIES_INTERVAL", defaultDeliverWebhooksInterval)
+	if err != nil {
+		return nil, err
+	}
+
+	return &deliverWebhooksJob{
+		log:                    log,
+		webhookDeliveryService: webhookDeliveryService,
+		interval:               interval,
+	}, nil
+}
+
+func (j *deliverWebhooksJob) Name() string {
+	return "deliver_webhooks"
+}
+
+func (j *deliverWebhooksJob) Interval() time.Duration {
+	return j.interval
+}
+
+func (j *deliverWebhooksJob) Run(ctx context.Context) error {
+	delivered, err := j.webhookDeliveryService.DeliverDue(ctx)
+	if delivered > 0 {
+		j.log.Info("Delivered webhooks", zap.Int64("count", delivered))
+	}
+
+	return err
+}
@@ -0,0 +1,57 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"verifymy-golang-test/services"
+)
+
+const defaultDisposeDeletedUsersInterval = time.Hour
+
+type disposeDeletedUsersJob struct {
+	log                  *zap.Logger
+	userRetentionService services.UserRetentionService
+	interval             time.Duration
+}
+
+// NewDisposeDeletedUsersJob runs every DELETED_USERS_DISPOSAL_INTERVAL, an
+// hour by default.
+func NewDisposeDeletedUsersJob(
+	log *zap.Logger,
+	userRetentionService services.UserRetentionService,
+) (Job, error) {
+	interval, err := intervalFromEnv("DELETED_USERS_DISPOSAL_INTERVAL", defaultDisposeDeletedUsersInterval)
+	if err != nil {
+		return nil, err
+	}
+
+	return &disposeDeletedUsersJob{
+		log:                  log,
+		userRetentionService: userRetentionService,
+		interval:             interval,
+	}, nil
+}
+
+func (j *disposeDeletedUsersJob) Name() string {
+	return "dispose_deleted_users"
+}
+
+func (j *disposeDeletedUsersJob) Interval() time.Duration {
+	return j.interval
+}
+
+func (j *disposeDeletedUsersJob) Run(ctx context.Context) error {
+	disposed, err := j.userRetentionService.DisposeDeletedUsers(ctx)
+	if err != nil {
+		return err
+	}
+
+	if disposed > 0 {
+		j.log.Info("Disposed of deleted users", zap.Int64("count", disposed))
+	}
+
+	return nil
+}
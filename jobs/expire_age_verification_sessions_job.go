@@ -0,0 +1,53 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"verifymy-golang-test/services"
+)
+
+const defaultExpireAgeVerificationSessionsInterval = time.Minute
+
+type expireAgeVerificationSessionsJob struct {
+	log                           *zap.Logger
+	ageVerificationSessionService services.AgeVerificationSessionService
+	interval                      time.Duration
+}
+
+// NewExpireAgeVerificationSessionsJob runs every
+// AGE_VERIFICATION_SESSIONS_INTERVAL, one minute by default.
+func NewExpireAgeVerificationSessionsJob(
+	log *zap.Logger,
+	ageVerificationSessionService services.AgeVerificationSessionService,
+) (Job, error) {
+	interval, err := intervalFromEnv("AGE_VERIFICATION_SESSIONS_INTERVAL", defaultExpireAgeVerificationSessionsInterval)
+	if err != nil {
+		return nil, err
+	}
+
+	return &expireAgeVerificationSessionsJob{
+		log:                           log,
+		ageVerificationSessionService: ageVerificationSessionService,
+		interval:                      interval,
+	}, nil
+}
+
+func (j *expireAgeVerificationSessionsJob) Name() string {
+	return "expire_age_verification_sessions"
+}
+
+func (j *expireAgeVerificationSessionsJob) Interval() time.Duration {
+	return j.interval
+}
+
+func (j *expireAgeVerificationSessionsJob) Run(ctx context.Context) error {
+	expired, err := j.ageVerificationSessionService.ExpirePending(ctx)
+	if expired > 0 {
+		j.log.Info("Expired age verification sessions", zap.Int64("count", expired))
+	}
+
+	return err
+}
@@ -0,0 +1,23 @@
+package jobs
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// intervalFromEnv reads the interval of a job from the environment variable
+// name, falling back to fallback when it is not set.
+func intervalFromEnv(name string, fallback time.Duration) (time.Duration, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return fallback, nil
+	}
+
+	interval, err := time.ParseDuration(value)
+	if err != nil || interval <= 0 {
+		return 0, fmt.Errorf("invalid %s %q", name, value)
+	}
+
+	return interval, nil
+}
@@ -0,0 +1,189 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+
+	mock_services "verifymy-golang-test/mocks/services"
+)
+
+type jobsTestSuite struct {
+	suite.Suite
+	ctrl                              *gomock.Controller
+	userRetentionServiceMock          *mock_services.MockUserRetentionService
+	dataExportServiceMock             *mock_services.MockDataExportService
+	eventRelayServiceMock             *mock_services.MockEventRelayService
+	webhookDeliveryServiceMock        *mock_services.MockWebhookDeliveryService
+	ageVerificationSessionServiceMock *mock_services.MockAgeVerificationSessionService
+}
+
+// The interval of jobs is read from the environment, so these tests can't
+// run in parallel.
+func TestJobsTestSuite(t *testing.T) {
+	suite.Run(t, new(jobsTestSuite))
+}
+
+func (s *jobsTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.userRetentionServiceMock = mock_services.NewMockUserRetentionService(s.ctrl)
+	s.dataExportServiceMock = mock_services.NewMockDataExportService(s.ctrl)
+	s.eventRelayServiceMock = mock_services.NewMockEventRelayService(s.ctrl)
+	s.webhookDeliveryServiceMock = mock_services.NewMockWebhookDeliveryService(s.ctrl)
+	s.ageVerificationSessionServiceMock = mock_services.NewMockAgeVerificationSessionService(s.ctrl)
+}
+
+type jobTest struct {
+	name            string
+	env             string
+	defaultInterval time.Duration
+	newJob          func() (Job, error)
+	// expectRun expects the job to call its service, which fails with err
+	expectRun func(ctx context.Context, err error)
+}
+
+func (s *jobsTestSuite) jobTests() []jobTest {
+	log := zap.NewNop()
+
+	return []jobTest{
+		{
+			name:            "dispose_deleted_users",
+			env:             "DELETED_USERS_DISPOSAL_INTERVAL",
+			defaultInterval: time.Hour,
+			newJob: func() (Job, error) {
+				return NewDisposeDeletedUsersJob(log, s.userRetentionServiceMock)
+			},
+			expectRun: func(ctx context.Context, err error) {
+				s.userRetentionServiceMock.EXPECT().DisposeDeletedUsers(ctx).Return(int64(0), err)
+			},
+		},
+		{
+			name:            "process_data_exports",
+			env:             "DATA_EXPORTS_INTERVAL",
+			defaultInterval: time.Minute,
+			newJob: func() (Job, error) {
+				return NewProcessDataExportsJob(log, s.dataExportServiceMock)
+			},
+			expectRun: func(ctx context.Context, err error) {
+				s.dataExportServiceMock.EXPECT().ProcessPending(ctx).Return(int64(0), err)
+			},
+		},
+		{
+			name:            "delete_expired_data_exports",
+			env:             "DATA_EXPORTS_EXPIRY_INTERVAL",
+			defaultInterval: time.Hour,
+			newJob: func() (Job, error) {
+				return NewDeleteExpiredDataExportsJob(log, s.dataExportServiceMock)
+			},
+			expectRun: func(ctx context.Context, err error) {
+				s.dataExportServiceMock.EXPECT().DeleteExpired(ctx).Return(int64(0), err)
+			},
+		},
+		{
+			name:            "relay_outbox_events",
+			env:             "OUTBOX_RELAY_INTERVAL",
+			defaultInterval: time.Second * 5,
+			newJob: func() (Job, error) {
+				return NewRelayOutboxEventsJob(log, s.eventRelayServiceMock)
+			},
+			expectRun: func(ctx context.Context, err error) {
+				s.eventRelayServiceMock.EXPECT().RelayPending(ctx).Return(int64(0), err)
+			},
+		},
+		{
+			name:            "deliver_webhooks",
+			env:             "WEBHOOK_DELIVERIES_INTERVAL",
+			defaultInterval: time.Second * 5,
+			newJob: func() (Job, error) {
+				return NewDeliverWebhooksJob(log, s.webhookDeliveryServiceMock)
+			},
+			expectRun: func(ctx context.Context, err error) {
+				s.webhookDeliveryServiceMock.EXPECT().DeliverDue(ctx).Return(int64(0), err)
+			},
+		},
+		{
+			name:            "expire_age_verification_sessions",
+			env:             "AGE_VERIFICATION_SESSIONS_INTERVAL",
+			defaultInterval: time.Minute,
+			newJob: func() (Job, error) {
+				return NewExpireAgeVerificationSessionsJob(log, s.ageVerificationSessionServiceMock)
+			},
+			expectRun: func(ctx context.Context, err error) {
+				s.ageVerificationSessionServiceMock.EXPECT().ExpirePending(ctx).Return(int64(0), err)
+			},
+		},
+	}
+}
+
+func (s *jobsTestSuite) TestInterval() {
+	tests := []struct {
+		description      string
+		value            string
+		expectedInterval time.Duration
+		expectedError    bool
+	}{
+		{
+			description: "Default",
+		},
+		{
+			description:      "From the environment",
+			value:            "90s",
+			expectedInterval: time.Second * 90,
+		},
+		{
+			description:   "Invalid",
+			value:         "soon",
+			expectedError: true,
+		},
+		{
+			description:   "Not positive",
+			value:         "-1m",
+			expectedError: true,
+		},
+	}
+
+	for _, job := range s.jobTests() {
+		for _, test := range tests {
+			s.Run(job.name+"/"+test.description, func() {
+				s.T().Setenv(job.env, test.value)
+
+				created, err := job.newJob()
+				if test.expectedError {
+					s.EqualError(err, "invalid "+job.env+` "`+test.value+`"`)
+					s.Nil(created)
+					return
+				}
+
+				expectedInterval := test.expectedInterval
+				if expectedInterval == 0 {
+					expectedInterval = job.defaultInterval
+				}
+
+				s.Require().NoError(err)
+				s.Equal(job.name, created.Name())
+				s.Equal(expectedInterval, created.Interval())
+			})
+		}
+	}
+}
+
+func (s *jobsTestSuite) TestRun() {
+	ctx := context.Background()
+
+	for _, job := range s.jobTests() {
+		s.Run(job.name, func() {
+			created, err := job.newJob()
+			s.Require().NoError(err)
+
+			runError := errors.New("error running " + job.name)
+			job.expectRun(ctx, runError)
+
+			s.Equal(runError, created.Run(ctx))
+		})
+	}
+}
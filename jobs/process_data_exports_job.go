@@ -0,0 +1,53 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"verifymy-golang-test/services"
+)
+
+const defaultProcessDataExportsInterval = time.Minute
+
+type processDataExportsJob struct {
+	log               *zap.Logger
+	dataExportService services.DataExportService
+	interval          time.Duration
+}
+
+// NewProcessDataExportsJob runs every DATA_EXPORTS_INTERVAL, a minute by
+// default.
+func NewProcessDataExportsJob(
+	log *zap.Logger,
+	dataExportService services.DataExportService,
+) (Job, error) {
+	interval, err := intervalFromEnv("DATA_EXPORTS_INTERVAL", defaultProcessDataExportsInterval)
+	if err != nil {
+		return nil, err
+	}
+
+	return &processDataExportsJob{
+		log:               log,
+		dataExportService: dataExportService,
+		interval:          interval,
+	}, nil
+}
+
+func (j *processDataExportsJob) Name() string {
+	return "process_data_exports"
+}
+
+func (j *processDataExportsJob) Interval() time.Duration {
+	return j.interval
+}
+
+func (j *processDataExportsJob) Run(ctx context.Context) error {
+	processed, err := j.dataExportService.ProcessPending(ctx)
+	if processed > 0 {
+		j.log.Info("Packaged data exports", zap.Int64("count", processed))
+	}
+
+	return err
+}
@@ -0,0 +1,53 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"verifymy-golang-test/services"
+)
+
+const defaultRelayOutboxEventsInterval = time.Second * 5
+
+type relayOutboxEventsJob struct {
+	log               *zap.Logger
+	eventRelayService services.EventRelayService
+	interval          time.Duration
+}
+
+// NewRelayOutboxEventsJob runs every OUTBOX_RELAY_INTERVAL, five seconds by
+// default.
+func NewRelayOutboxEventsJob(
+	log *zap.Logger,
+	eventRelayService services.EventRelayService,
+) (Job, error) {
+	interval, err := intervalFromEnv("OUTBOX_RELAY_INTERVAL", defaultRelayOutboxEventsInterval)
+	if err != nil {
+		return nil, err
+	}
+
+	return &relayOutboxEventsJob{
+		log:               log,
+		eventRelayService: eventRelayService,
+		interval:          interval,
+	}, nil
+}
+
+func (j *relayOutboxEventsJob) Name() string {
+	return "relay_outbox_events"
+}
+
+func (j *relayOutboxEventsJob) Interval() time.Duration {
+	return j.interval
+}
+
+func (j *relayOutboxEventsJob) Run(ctx context.Context) error {
+	relayed, err := j.eventRelayService.RelayPending(ctx)
+	if relayed > 0 {
+		j.log.Info("Relayed outbox events", zap.Int64("count", relayed))
+	}
+
+	return err
+}
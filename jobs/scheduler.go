@@ -0,0 +1,81 @@
+package jobs
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+// Job is a task the scheduler runs in the background every Interval.
+type Job interface {
+	Name() string
+	Interval() time.Duration
+	Run(ctx context.Context) error
+}
+
+type Scheduler struct {
+	log    *zap.Logger
+	jobs   []Job
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewScheduler(lc fx.Lifecycle, log *zap.Logger, jobs []Job) *Scheduler {
+	scheduler := &Scheduler{log: log, jobs: jobs}
+	lc.Append(
+		fx.Hook{
+			OnStart: func(context.Context) error {
+				scheduler.Start()
+				return nil
+			},
+			OnStop: func(context.Context) error {
+				scheduler.Stop()
+				return nil
+			},
+		},
+	)
+
+	return scheduler
+}
+
+// Start runs every job on its own ticker until Stop is called.
+func (s *Scheduler) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	for _, job := range s.jobs {
+		s.wg.Add(1)
+		go s.loop(ctx, job)
+	}
+}
+
+// Stop cancels the running jobs and waits for them to return.
+func (s *Scheduler) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+
+	s.wg.Wait()
+}
+
+func (s *Scheduler) loop(ctx context.Context, job Job) {
+	defer s.wg.Done()
+
+	s.log.Info("Scheduling job", zap.String("job", job.Name()), zap.Duration("interval", job.Interval()))
+	ticker := time.NewTicker(job.Interval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := job.Run(ctx); err != nil {
+				s.log.Error("Job failed", zap.String("job", job.Name()), zap.Error(err))
+			}
+		}
+	}
+}
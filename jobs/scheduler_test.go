@@ -0,0 +1,57 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/fx/fxtest"
+	"go.uber.org/zap"
+)
+
+type countingJob struct {
+	runs int32
+	err  error
+}
+
+func (j *countingJob) Name() string {
+	return "counting"
+}
+
+func (j *countingJob) Interval() time.Duration {
+	return time.Millisecond
+}
+
+func (j *countingJob) Run(ctx context.Context) error {
+	atomic.AddInt32(&j.runs, 1)
+	return j.err
+}
+
+type schedulerTestSuite struct {
+	suite.Suite
+}
+
+func TestSchedulerTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(schedulerTestSuite))
+}
+
+func (s *schedulerTestSuite) TestRunsJobsUntilStopped() {
+	job := &countingJob{}
+	failingJob := &countingJob{err: errors.New("error running job")}
+	lc := fxtest.NewLifecycle(s.T())
+	NewScheduler(lc, zap.NewNop(), []Job{job, failingJob})
+
+	lc.RequireStart()
+	s.Eventually(func() bool {
+		return atomic.LoadInt32(&job.runs) > 1 && atomic.LoadInt32(&failingJob.runs) > 1
+	}, time.Second, time.Millisecond)
+	lc.RequireStop()
+
+	runs := atomic.LoadInt32(&job.runs)
+	time.Sleep(10 * time.Millisecond)
+	s.Equal(runs, atomic.LoadInt32(&job.runs))
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"net"
 	"net/http"
+	"os"
 
 	gorillaHandlers "github.com/gorilla/handlers"
 	"github.com/gorilla/mux"
@@ -14,7 +15,9 @@ import (
 	"go.uber.org/zap"
 
 	"verifymy-golang-test/handlers"
+	"verifymy-golang-test/jobs"
 	"verifymy-golang-test/middlewares"
+	"verifymy-golang-test/providers"
 	"verifymy-golang-test/repositories"
 	"verifymy-golang-test/services"
 )
@@ -33,26 +36,89 @@ func main() {
 			NewHTTPServer,
 			fx.Annotate(
 				NewServeMux,
-				fx.ParamTags(`*zap.Logger`, `group:"routes"`),
+				fx.ParamTags(`*zap.Logger`, ``, ``, `group:"routes"`),
 			),
+			fx.Annotate(
+				jobs.NewScheduler,
+				fx.ParamTags(``, ``, `group:"jobs"`),
+			),
+
+			AsJob(jobs.NewDisposeDeletedUsersJob),
+			AsJob(jobs.NewProcessDataExportsJob),
+			AsJob(jobs.NewDeleteExpiredDataExportsJob),
+			AsJob(jobs.NewRelayOutboxEventsJob),
+			AsJob(jobs.NewDeliverWebhooksJob),
+			AsJob(jobs.NewExpireAgeVerificationSessionsJob),
 
 			AsRoute(handlers.NewHealthCheckHandler),
 			AsRoute(handlers.NewSignUpHandler),
 			AsRoute(handlers.NewSignInHandler),
+			AsRoute(handlers.NewShowInvitationHandler),
+			AsRoute(handlers.NewAcceptInvitationHandler),
 			AsRoute(handlers.NewShowProfileHandler),
 			AsRoute(handlers.NewUpdateProfileHandler),
+			AsRoute(handlers.NewPatchProfileHandler),
+			AsRoute(handlers.NewDeleteProfileHandler),
+			AsRoute(handlers.NewRequestEmailChangeHandler),
+			AsRoute(handlers.NewShowEmailChangeConfirmationHandler),
+			AsRoute(handlers.NewConfirmEmailChangeHandler),
+			AsRoute(handlers.NewRequestParentalConsentHandler),
+			AsRoute(handlers.NewShowParentalConsentHandler),
+			AsRoute(handlers.NewDecideParentalConsentHandler),
+			AsRoute(handlers.NewChangePasswordHandler),
+			AsRoute(handlers.NewSudoHandler),
+			AsRoute(handlers.NewShowAgeVerificationHandler),
+			AsRoute(handlers.NewStartAgeVerificationHandler),
+			AsRoute(handlers.NewShowAgeVerificationSessionHandler),
+			AsRoute(handlers.NewAgeVerificationCallbackHandler),
+			AsRoute(handlers.NewShowAgeRuleHandler),
+			AsRoute(handlers.NewIssueAgeTokenHandler),
+			AsRoute(handlers.NewVerifyAgeTokenHandler),
+			AsRoute(handlers.NewShowJWKSHandler),
+			AsRoute(handlers.NewRequestDataExportHandler),
+			AsRoute(handlers.NewShowDataExportHandler),
+			AsRoute(handlers.NewDownloadDataExportHandler),
 			AsRoute(handlers.NewListUsersHandler),
+			AsRoute(handlers.NewShowUserByIdHandler),
+			AsRoute(handlers.NewCreateUserHandler),
+			AsRoute(handlers.NewReplaceUserHandler),
+			AsRoute(handlers.NewPatchUserHandler),
+			AsRoute(handlers.NewRestoreUserHandler),
+			AsRoute(handlers.NewListDeletedUsersHandler),
 			AsRoute(handlers.NewDeleteUserByIdHandler),
+			AsRoute(handlers.NewListAuditEventsHandler),
+			AsRoute(handlers.NewCreateWebhookSubscriptionHandler),
+			AsRoute(handlers.NewListWebhookSubscriptionsHandler),
+			AsRoute(handlers.NewShowWebhookSubscriptionHandler),
+			AsRoute(handlers.NewUpdateWebhookSubscriptionHandler),
+			AsRoute(handlers.NewDeleteWebhookSubscriptionHandler),
+			AsRoute(handlers.NewListWebhookDeliveriesHandler),
+			AsRoute(handlers.NewRedeliverWebhookHandler),
+			AsRoute(handlers.NewCreateClientHandler),
+			AsRoute(handlers.NewListClientsHandler),
+			AsRoute(handlers.NewShowClientHandler),
+			AsRoute(handlers.NewUpdateClientHandler),
+			AsRoute(handlers.NewDeleteClientHandler),
+			AsRoute(handlers.NewRotateClientSecretHandler),
+			AsRoute(handlers.NewShowOAuthAuthorizationHandler),
+			AsRoute(handlers.NewDecideOAuthAuthorizationHandler),
+			AsRoute(handlers.NewOAuthTokenHandler),
+			AsRoute(handlers.NewRevokeOAuthTokenHandler),
+			AsRoute(handlers.NewIntrospectOAuthTokenHandler),
+			AsRoute(handlers.NewShowOpenIDConfigurationHandler),
+			AsRoute(handlers.NewShowUserInfoHandler),
+			AsRoute(handlers.NewEndOpenIDSessionHandler),
 		),
 		fx.WithLogger(
 			func(log *zap.Logger) fxevent.Logger {
 				return &fxevent.ZapLogger{Logger: log}
 			},
 		),
-		fx.Invoke(func(*http.Server) {}),
+		fx.Invoke(func(*http.Server, *jobs.Scheduler) {}),
 		repositories.Module,
 		services.Module,
 		handlers.Module,
+		ageVerificationSimulator(),
 	).Run()
 }
 
@@ -63,6 +129,7 @@ func NewHTTPServer(lc fx.Lifecycle, mux *mux.Router, log *zap.Logger) *http.Serv
 			[]string{
 				http.MethodGet,
 				http.MethodPost,
+				http.MethodPut,
 				http.MethodPatch,
 				http.MethodDelete,
 				http.MethodOptions,
@@ -73,6 +140,19 @@ func NewHTTPServer(lc fx.Lifecycle, mux *mux.Router, log *zap.Logger) *http.Serv
 				"Content-Type",
 				"Origin",
 				"Sec-fetch-site",
+				"If-Match",
+				"X-Client-Authorization",
+				"X-Request-ID",
+			},
+		),
+		gorillaHandlers.ExposedHeaders(
+			[]string{
+				"Accept-Patch",
+				"ETag",
+				"Link",
+				"Location",
+				"X-Request-ID",
+				"X-Total-Count",
 			},
 		),
 	)
@@ -100,9 +180,12 @@ func NewHTTPServer(lc fx.Lifecycle, mux *mux.Router, log *zap.Logger) *http.Serv
 
 func NewServeMux(
 	authService services.AuthService,
-	handlers []handlers.Handler,
+	clientService services.ClientService,
+	oauthService services.OAuthService,
+	routes []handlers.Handler,
 ) *mux.Router {
 	mux := mux.NewRouter()
+	mux.Use(middlewares.RequestInfoMiddleware(os.Getenv("TRUST_PROXY_HEADERS") == "true"))
 	mux.Use(middlewares.AuthMiddleware(authService))
 
 	mux.PathPrefix("/static/").Handler(
@@ -118,8 +201,24 @@ func NewServeMux(
 		),
 	).Methods(http.MethodGet)
 
-	for _, h := range handlers {
-		mux.Handle(h.Route(), h).Methods(h.Method()...)
+	for _, h := range routes {
+		var handler http.Handler = h
+		if sudo, ok := h.(handlers.SudoHandler); ok && sudo.RequiresSudo() {
+			handler = middlewares.SudoMiddleware(authService)(handler)
+		}
+		if authorized, ok := h.(handlers.AuthorizedHandler); ok {
+			handler = middlewares.PermissionMiddleware(authorized.Permission())(handler)
+		}
+		if client, ok := h.(handlers.ClientHandler); ok {
+			handler = middlewares.ClientAuthMiddleware(
+				clientService, oauthService, client.Scope(),
+			)(handler)
+		}
+		if pending, ok := h.(handlers.PendingConsentHandler); !ok || !pending.AllowsPendingConsent() {
+			handler = middlewares.ParentalConsentMiddleware(handler)
+		}
+
+		mux.Handle(h.Route(), handler).Methods(h.Method()...)
 	}
 
 	return mux
@@ -132,3 +231,29 @@ func AsRoute(f interface{}) interface{} {
 		fx.ResultTags(`group:"routes"`),
 	)
 }
+
+func AsJob(f interface{}) interface{} {
+	return fx.Annotate(
+		f,
+		fx.As(new(jobs.Job)),
+		fx.ResultTags(`group:"jobs"`),
+	)
+}
+
+// ageVerificationSimulator provides the age verification simulator where it
+// is enabled, see providers.AgeVerificationSimulatorEnabled
+func ageVerificationSimulator() fx.Option {
+	if !providers.AgeVerificationSimulatorEnabled() {
+		return fx.Options()
+	}
+
+	return fx.Provide(AsAgeVerificationProvider(providers.NewAgeVerificationSimulator))
+}
+
+func AsAgeVerificationProvider(f interface{}) interface{} {
+	return fx.Annotate(
+		f,
+		fx.As(new(providers.AgeVerificationProvider)),
+		fx.ResultTags(`group:"age_verification_providers"`),
+	)
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	"verifymy-golang-test/common"
+	"verifymy-golang-test/entities"
+	"verifymy-golang-test/handlers"
+	mock_services "verifymy-golang-test/mocks/services"
+	"verifymy-golang-test/models"
+)
+
+type serveMuxTestSuite struct {
+	suite.Suite
+	ctrl                              *gomock.Controller
+	authServiceMock                   *mock_services.MockAuthService
+	clientServiceMock                 *mock_services.MockClientService
+	oauthServiceMock                  *mock_services.MockOAuthService
+	ageVerificationSessionServiceMock *mock_services.MockAgeVerificationSessionService
+	user                              *models.User
+	client                            *models.Client
+	handler                           http.Handler
+}
+
+func TestServeMuxTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(serveMuxTestSuite))
+}
+
+func (s *serveMuxTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.authServiceMock = mock_services.NewMockAuthService(s.ctrl)
+	s.clientServiceMock = mock_services.NewMockClientService(s.ctrl)
+	s.oauthServiceMock = mock_services.NewMockOAuthService(s.ctrl)
+	s.ageVerificationSessionServiceMock = mock_services.NewMockAgeVerificationSessionService(s.ctrl)
+	s.user = &models.User{ID: uuid.New(), Status: models.UserStatusActive}
+	s.client = &models.Client{
+		ID: uuid.New(), Scopes: models.StringList{models.ClientScopeAgeVerification},
+	}
+
+	s.handler = NewServeMux(
+		s.authServiceMock,
+		s.clientServiceMock,
+		s.oauthServiceMock,
+		[]handlers.Handler{
+			handlers.NewStartAgeVerificationHandler(s.ageVerificationSessionServiceMock),
+		},
+	)
+}
+
+func (s *serveMuxTestSuite) TestStartAgeVerificationAuthenticatesClient() {
+	tests := []struct {
+		description        string
+		clientCredentials  bool
+		expectedStatusCode int
+	}{
+		{
+			description:        "With client credentials",
+			clientCredentials:  true,
+			expectedStatusCode: http.StatusCreated,
+		},
+		{
+			description:        "Without client credentials",
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			request := httptest.NewRequest(
+				http.MethodPost,
+				"/age_verifications",
+				strings.NewReader(`{"method":"document","category":"alcohol"}`),
+			)
+			request.Header.Set("Authorization", "Bearer user-token")
+			s.authServiceMock.EXPECT().GetUserFromToken(
+				gomock.Any(), "user-token",
+			).Return(s.user, nil)
+
+			if test.clientCredentials {
+				credentials := httptest.NewRequest(http.MethodPost, "/", nil)
+				credentials.SetBasicAuth(s.client.ID.String(), "client-secret")
+				request.Header.Set(
+					"X-Client-Authorization", credentials.Header.Get("Authorization"),
+				)
+				s.clientServiceMock.EXPECT().Authenticate(
+					gomock.Any(), s.client.ID.String(), "client-secret",
+				).Return(s.client, nil)
+				s.ageVerificationSessionServiceMock.EXPECT().Start(
+					gomock.Any(), gomock.Any(),
+				).DoAndReturn(func(
+					ctx context.Context, request entities.AgeVerificationSessionRequest,
+				) (*models.AgeVerificationSession, error) {
+					s.Equal(s.user, ctx.Value(common.AuthUser))
+					s.Equal(s.client, ctx.Value(common.AuthClient))
+
+					return &models.AgeVerificationSession{
+						ID:        uuid.New(),
+						ClientID:  s.client.ID.String(),
+						ExpiresAt: time.Now(),
+					}, nil
+				})
+			}
+			response := httptest.NewRecorder()
+
+			s.handler.ServeHTTP(response, request)
+
+			s.Equal(test.expectedStatusCode, response.Code)
+		})
+	}
+}
@@ -14,6 +14,22 @@ var ALLOWED_PATHS = []interface{}{
 	"/",
 	"/auth/sign_in",
 	"/auth/sign_up",
+	"/auth/invitations/accept",
+	"/auth/email/confirm",
+	"/auth/parental_consent",
+	"/exports/download",
+	"/age_rules",
+	// Authenticated with client credentials instead, see ClientAuthMiddleware
+	"/age_tokens/verify",
+	// OAuth clients authenticate on these themselves, see OAuthService
+	"/oauth/token",
+	"/oauth/revoke",
+	"/oauth/introspect",
+	// OpenID Connect clients send OAuth access tokens or ID tokens instead
+	"/userinfo",
+	"/oauth/logout",
+	"/.well-known/openid-configuration",
+	"/.well-known/jwks.json",
 	"/static/doc.json",
 	"/swagger/",
 	"/swagger/index.html",
@@ -22,12 +38,31 @@ var ALLOWED_PATHS = []interface{}{
 	"/swagger/swagger-ui-standalone-preset.js",
 }
 
+// ALLOWED_PATH_PREFIXES are public along with every path under them
+var ALLOWED_PATH_PREFIXES = []string{
+	"/age_verifications/callbacks/",
+}
+
+func isPublicPath(path string) bool {
+	if utils.SliceContains(ALLOWED_PATHS, path) {
+		return true
+	}
+
+	for _, prefix := range ALLOWED_PATH_PREFIXES {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
 func AuthMiddleware(
 	authService services.AuthService,
 ) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			if !utils.SliceContains(ALLOWED_PATHS, r.URL.Path) {
+			if !isPublicPath(r.URL.Path) {
 				authorizationHeader := strings.Split(r.Header.Get("Authorization"), " ")
 				if len(authorizationHeader) != 2 {
 					w.Header().Set("Content-Type", "application/json")
@@ -86,6 +86,10 @@ func (s *authMiddlewareTestSuite) TestAuthMiddleware() {
 				"email":         "king.james@nba.com",
 				"password":      nil,
 				"address":       "1111 S Figueroa St, Los Angeles",
+				"created_at":    "0001-01-01T00:00:00Z",
+				"updated_at":    "0001-01-01T00:00:00Z",
+				"role":          "",
+				"status":        "",
 			},
 		},
 		{
@@ -95,6 +99,13 @@ func (s *authMiddlewareTestSuite) TestAuthMiddleware() {
 			expectedResponse:   nil,
 			isPublicURL:        true,
 		},
+		{
+			description:        "Success with a public URL prefix",
+			route:              "/age_verifications/callbacks/simulator",
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   nil,
+			isPublicURL:        true,
+		},
 		{
 			description:         "Malformed authorization header",
 			route:               "/me",
@@ -0,0 +1,119 @@
+package middlewares
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"verifymy-golang-test/common"
+	"verifymy-golang-test/entities"
+	"verifymy-golang-test/models"
+	"verifymy-golang-test/services"
+)
+
+// ClientAuthorizationHeader carries the credentials of the client on
+// requests it sends on behalf of a signed in user, whose access token takes
+// the Authorization header
+const ClientAuthorizationHeader = "X-Client-Authorization"
+
+// ClientAuthMiddleware authenticates relying parties with their client
+// credentials, sent through HTTP Basic authentication, or with an access
+// token issued to them through the OAuth client credentials grant, and only
+// lets through clients granted scope. Their paths must be allowed by
+// AuthMiddleware, unless they are called on behalf of a signed in user,
+// in which case the client authenticates through ClientAuthorizationHeader.
+func ClientAuthMiddleware(
+	clientService services.ClientService,
+	oauthService services.OAuthService,
+	scope string,
+) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			client, scopes, err := authenticateClient(r, clientService, oauthService)
+			if err != nil {
+				w.Header().Set("Content-Type", "application/json")
+				if _, ok := err.(*entities.InvalidClientError); ok {
+					w.Header().Set("WWW-Authenticate", `Basic realm="clients"`)
+					w.WriteHeader(http.StatusUnauthorized)
+					w.Write([]byte(`{"message": "Invalid client credentials"}`))
+				} else if _, ok := err.(*entities.InvalidTokenError); ok {
+					w.Header().Set("WWW-Authenticate", `Bearer realm="clients", error="invalid_token"`)
+					w.WriteHeader(http.StatusUnauthorized)
+					w.Write([]byte(`{"message": "Invalid token"}`))
+				} else {
+					w.WriteHeader(http.StatusInternalServerError)
+					jsonPayload, _ := json.Marshal(entities.NewUnexpectedError(err))
+					w.Write(jsonPayload)
+				}
+				return
+			}
+
+			if !client.HasScope(scope) || !scopes.Includes(scope) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusForbidden)
+				w.Write([]byte(`{"message": "Insufficient scope"}`))
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), common.AuthClient, client)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// authenticateClient returns the client of the request along with the scopes
+// it is granted: those of the access token it sent, or all of its scopes when
+// it sent its credentials. Access tokens issued on behalf of users don't
+// authenticate the client itself.
+func authenticateClient(
+	r *http.Request,
+	clientService services.ClientService,
+	oauthService services.OAuthService,
+) (*models.Client, models.StringList, error) {
+	header := "Authorization"
+	if _, ok := r.Context().Value(common.AuthUser).(*models.User); ok {
+		header = ClientAuthorizationHeader
+	}
+
+	authorization := r.Header.Get(header)
+	if strings.HasPrefix(authorization, "Bearer ") {
+		grant, scopes, err := oauthService.AuthenticateAccessToken(
+			r.Context(), strings.TrimPrefix(authorization, "Bearer "),
+		)
+		if err != nil {
+			return nil, nil, err
+		} else if grant.UserID != "" {
+			return nil, nil, entities.NewInvalidTokenError()
+		}
+
+		client, err := clientService.FindById(r.Context(), grant.ClientID)
+		if _, ok := err.(*entities.ItemNotFoundError); ok {
+			return nil, nil, entities.NewInvalidTokenError()
+		} else if err != nil {
+			return nil, nil, err
+		}
+
+		return client, scopes, nil
+	}
+
+	clientId, clientSecret, ok := parseBasicAuth(authorization)
+	if !ok {
+		return nil, nil, entities.NewInvalidClientError()
+	}
+
+	client, err := clientService.Authenticate(r.Context(), clientId, clientSecret)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return client, client.Scopes, nil
+}
+
+// parseBasicAuth parses the credentials of HTTP Basic authentication out of
+// authorization, like http.Request.BasicAuth does for the Authorization
+// header
+func parseBasicAuth(authorization string) (string, string, bool) {
+	request := http.Request{Header: http.Header{"Authorization": {authorization}}}
+	return request.BasicAuth()
+}
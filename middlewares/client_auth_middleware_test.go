@@ -0,0 +1,264 @@
+package middlewares
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	"verifymy-golang-test/common"
+	"verifymy-golang-test/entities"
+	mock_services "verifymy-golang-test/mocks/services"
+	"verifymy-golang-test/models"
+)
+
+type clientAuthMiddlewareTestSuite struct {
+	suite.Suite
+	ctrl          *gomock.Controller
+	clientService *mock_services.MockClientService
+	oauthService  *mock_services.MockOAuthService
+	middleware    func(http.Handler) http.Handler
+	nextHandler   http.Handler
+}
+
+func TestClientAuthMiddlewareTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(clientAuthMiddlewareTestSuite))
+}
+
+func (s *clientAuthMiddlewareTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.clientService = mock_services.NewMockClientService(s.ctrl)
+	s.oauthService = mock_services.NewMockOAuthService(s.ctrl)
+	s.middleware = ClientAuthMiddleware(
+		s.clientService, s.oauthService, models.ClientScopeAgeTokens,
+	)
+	s.nextHandler = http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			client := r.Context().Value(common.AuthClient).(*models.Client)
+			w.Header().Set("X-Client-Id", client.ID.String())
+			w.WriteHeader(http.StatusNoContent)
+		},
+	)
+}
+
+func (s *clientAuthMiddlewareTestSuite) TestClientAuthMiddleware() {
+	client := &models.Client{
+		ID: uuid.New(), Scopes: models.StringList{models.ClientScopeAgeTokens},
+	}
+
+	tests := []struct {
+		description        string
+		skipBasicAuth      bool
+		client             *models.Client
+		authenticateError  error
+		expectedStatusCode int
+		expectedResponse   map[string]interface{}
+	}{
+		{
+			description:        "Valid credentials",
+			client:             client,
+			expectedStatusCode: http.StatusNoContent,
+		},
+		{
+			description:        "Missing credentials",
+			skipBasicAuth:      true,
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedResponse: map[string]interface{}{
+				"message": "Invalid client credentials",
+			},
+		},
+		{
+			description:        "Invalid credentials",
+			authenticateError:  entities.NewInvalidClientError(),
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedResponse: map[string]interface{}{
+				"message": "Invalid client credentials",
+			},
+		},
+		{
+			description: "Client without scope",
+			client: &models.Client{
+				ID: client.ID, Scopes: models.StringList{models.ClientScopeAgeVerification},
+			},
+			expectedStatusCode: http.StatusForbidden,
+			expectedResponse: map[string]interface{}{
+				"message": "Insufficient scope",
+			},
+		},
+		{
+			description:        "Unexpected error",
+			authenticateError:  errors.New("database unavailable"),
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedResponse: map[string]interface{}{
+				"message": "unexpected error",
+				"details": []interface{}{"database unavailable"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			request := httptest.NewRequest("POST", "/age_tokens/verify", nil)
+			if !test.skipBasicAuth {
+				request.SetBasicAuth(client.ID.String(), "client-secret")
+				s.clientService.EXPECT().Authenticate(
+					request.Context(), client.ID.String(), "client-secret",
+				).Return(test.client, test.authenticateError)
+			}
+			response := httptest.NewRecorder()
+
+			s.middleware(s.nextHandler).ServeHTTP(response, request)
+
+			var jsonPayload map[string]interface{}
+			_ = json.Unmarshal(response.Body.Bytes(), &jsonPayload)
+
+			s.Equal(test.expectedStatusCode, response.Code)
+			s.Equal(test.expectedResponse, jsonPayload)
+			if test.expectedStatusCode == http.StatusNoContent {
+				s.Equal(client.ID.String(), response.Header().Get("X-Client-Id"))
+			} else if test.expectedStatusCode == http.StatusUnauthorized {
+				s.Equal(`Basic realm="clients"`, response.Header().Get("WWW-Authenticate"))
+			}
+		})
+	}
+}
+
+func (s *clientAuthMiddlewareTestSuite) TestClientAuthMiddlewareWithAccessToken() {
+	client := &models.Client{
+		ID: uuid.New(), Scopes: models.StringList{models.ClientScopeAgeTokens},
+	}
+	clientGrant := &models.OAuthGrant{ID: uuid.New(), ClientID: client.ID.String()}
+	userGrant := &models.OAuthGrant{
+		ID: uuid.New(), ClientID: client.ID.String(), UserID: uuid.NewString(),
+	}
+
+	tests := []struct {
+		description        string
+		grant              *models.OAuthGrant
+		scopes             models.StringList
+		authenticateError  error
+		client             *models.Client
+		findError          error
+		expectedStatusCode int
+		expectedResponse   map[string]interface{}
+	}{
+		{
+			description:        "Valid access token",
+			grant:              clientGrant,
+			scopes:             models.StringList{models.ClientScopeAgeTokens},
+			client:             client,
+			expectedStatusCode: http.StatusNoContent,
+		},
+		{
+			description:        "Invalid access token",
+			authenticateError:  entities.NewInvalidTokenError(),
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedResponse:   map[string]interface{}{"message": "Invalid token"},
+		},
+		{
+			description:        "Access token of a user",
+			grant:              userGrant,
+			scopes:             models.StringList{models.ClientScopeProfile},
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedResponse:   map[string]interface{}{"message": "Invalid token"},
+		},
+		{
+			description:        "Deleted client",
+			grant:              clientGrant,
+			scopes:             models.StringList{models.ClientScopeAgeTokens},
+			findError:          entities.NewItemNotFoundError("Client", client.ID.String()),
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedResponse:   map[string]interface{}{"message": "Invalid token"},
+		},
+		{
+			description:        "Access token without scope",
+			grant:              clientGrant,
+			scopes:             models.StringList{models.ClientScopeAgeVerification},
+			client:             client,
+			expectedStatusCode: http.StatusForbidden,
+			expectedResponse:   map[string]interface{}{"message": "Insufficient scope"},
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			request := httptest.NewRequest("POST", "/age_tokens/verify", nil)
+			request.Header.Set("Authorization", "Bearer access-token")
+			s.oauthService.EXPECT().AuthenticateAccessToken(
+				request.Context(), "access-token",
+			).Return(test.grant, test.scopes, test.authenticateError)
+			if test.client != nil || test.findError != nil {
+				s.clientService.EXPECT().FindById(
+					request.Context(), client.ID.String(),
+				).Return(test.client, test.findError)
+			}
+			response := httptest.NewRecorder()
+
+			s.middleware(s.nextHandler).ServeHTTP(response, request)
+
+			var jsonPayload map[string]interface{}
+			_ = json.Unmarshal(response.Body.Bytes(), &jsonPayload)
+
+			s.Equal(test.expectedStatusCode, response.Code)
+			s.Equal(test.expectedResponse, jsonPayload)
+			if test.expectedStatusCode == http.StatusUnauthorized {
+				s.Equal(
+					`Bearer realm="clients", error="invalid_token"`,
+					response.Header().Get("WWW-Authenticate"),
+				)
+			}
+		})
+	}
+}
+
+func (s *clientAuthMiddlewareTestSuite) TestClientAuthMiddlewareOnBehalfOfUser() {
+	client := &models.Client{
+		ID: uuid.New(), Scopes: models.StringList{models.ClientScopeAgeTokens},
+	}
+	credentials := httptest.NewRequest("POST", "/", nil)
+	credentials.SetBasicAuth(client.ID.String(), "client-secret")
+
+	tests := []struct {
+		description        string
+		header             string
+		expectedStatusCode int
+	}{
+		{
+			description:        "Credentials in client authorization header",
+			header:             ClientAuthorizationHeader,
+			expectedStatusCode: http.StatusNoContent,
+		},
+		{
+			description:        "Credentials in authorization header",
+			header:             "Authorization",
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			request := httptest.NewRequest("POST", "/age_verifications", nil)
+			request = request.WithContext(context.WithValue(
+				request.Context(), common.AuthUser, &models.User{ID: uuid.New()},
+			))
+			request.Header.Set(test.header, credentials.Header.Get("Authorization"))
+			if test.expectedStatusCode == http.StatusNoContent {
+				s.clientService.EXPECT().Authenticate(
+					request.Context(), client.ID.String(), "client-secret",
+				).Return(client, nil)
+			}
+			response := httptest.NewRecorder()
+
+			s.middleware(s.nextHandler).ServeHTTP(response, request)
+
+			s.Equal(test.expectedStatusCode, response.Code)
+		})
+	}
+}
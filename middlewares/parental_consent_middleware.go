@@ -0,0 +1,37 @@
+package middlewares
+
+import (
+	"net/http"
+
+	"verifymy-golang-test/common"
+	"verifymy-golang-test/models"
+)
+
+// ParentalConsentMiddleware turns away users whose guardian has yet to
+// consent to them signing up, or rejected it. It must run after
+// AuthMiddleware, and lets through requests to public paths, which carry no
+// user.
+func ParentalConsentMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, ok := r.Context().Value(common.AuthUser).(*models.User)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		switch user.Status {
+		case models.UserStatusPendingParentalConsent:
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"message": "Parental consent required"}`))
+			return
+		case models.UserStatusParentalConsentRejected:
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"message": "Parental consent rejected"}`))
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,87 @@
+package middlewares
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"verifymy-golang-test/common"
+	"verifymy-golang-test/models"
+)
+
+type parentalConsentMiddlewareTestSuite struct {
+	suite.Suite
+	nextHandler http.Handler
+}
+
+func TestParentalConsentMiddlewareTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(parentalConsentMiddlewareTestSuite))
+}
+
+func (s *parentalConsentMiddlewareTestSuite) SetupTest() {
+	s.nextHandler = http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		},
+	)
+}
+
+func (s *parentalConsentMiddlewareTestSuite) TestParentalConsentMiddleware() {
+	tests := []struct {
+		description        string
+		user               *models.User
+		expectedStatusCode int
+		expectedResponse   map[string]interface{}
+	}{
+		{
+			description:        "Active user",
+			user:               &models.User{Status: models.UserStatusActive},
+			expectedStatusCode: http.StatusNoContent,
+		},
+		{
+			description:        "User pending parental consent",
+			user:               &models.User{Status: models.UserStatusPendingParentalConsent},
+			expectedStatusCode: http.StatusForbidden,
+			expectedResponse: map[string]interface{}{
+				"message": "Parental consent required",
+			},
+		},
+		{
+			description:        "User whose parental consent was rejected",
+			user:               &models.User{Status: models.UserStatusParentalConsentRejected},
+			expectedStatusCode: http.StatusForbidden,
+			expectedResponse: map[string]interface{}{
+				"message": "Parental consent rejected",
+			},
+		},
+		{
+			description:        "No authenticated user",
+			expectedStatusCode: http.StatusNoContent,
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			request := httptest.NewRequest("GET", "/age_verifications", nil)
+			if test.user != nil {
+				request = request.WithContext(
+					context.WithValue(request.Context(), common.AuthUser, test.user),
+				)
+			}
+			response := httptest.NewRecorder()
+
+			ParentalConsentMiddleware(s.nextHandler).ServeHTTP(response, request)
+
+			var jsonPayload map[string]interface{}
+			_ = json.Unmarshal(response.Body.Bytes(), &jsonPayload)
+
+			s.Equal(test.expectedStatusCode, response.Code)
+			s.Equal(test.expectedResponse, jsonPayload)
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package middlewares
+
+import (
+	"net/http"
+
+	"verifymy-golang-test/common"
+	"verifymy-golang-test/models"
+)
+
+// PermissionMiddleware only lets through requests whose authenticated user
+// holds the permission. It must run after AuthMiddleware.
+func PermissionMiddleware(
+	permission models.Permission,
+) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user, ok := r.Context().Value(common.AuthUser).(*models.User)
+			if !ok || !user.Can(permission) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusForbidden)
+				w.Write([]byte(`{"message": "Insufficient permissions"}`))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package middlewares
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"verifymy-golang-test/common"
+	"verifymy-golang-test/models"
+)
+
+type permissionMiddlewareTestSuite struct {
+	suite.Suite
+	middleware  func(http.Handler) http.Handler
+	nextHandler http.Handler
+}
+
+func TestPermissionMiddlewareTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(permissionMiddlewareTestSuite))
+}
+
+func (s *permissionMiddlewareTestSuite) SetupTest() {
+	s.middleware = PermissionMiddleware(models.PermissionManageUsers)
+	s.nextHandler = http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		},
+	)
+}
+
+func (s *permissionMiddlewareTestSuite) TestPermissionMiddleware() {
+	tests := []struct {
+		description        string
+		user               *models.User
+		expectedStatusCode int
+		expectedResponse   map[string]interface{}
+	}{
+		{
+			description:        "Admin",
+			user:               &models.User{Role: models.RoleAdmin},
+			expectedStatusCode: http.StatusNoContent,
+		},
+		{
+			description:        "User without permission",
+			user:               &models.User{Role: models.RoleUser},
+			expectedStatusCode: http.StatusForbidden,
+			expectedResponse: map[string]interface{}{
+				"message": "Insufficient permissions",
+			},
+		},
+		{
+			description:        "No authenticated user",
+			expectedStatusCode: http.StatusForbidden,
+			expectedResponse: map[string]interface{}{
+				"message": "Insufficient permissions",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			request := httptest.NewRequest("GET", "/users/1", nil)
+			if test.user != nil {
+				request = request.WithContext(
+					context.WithValue(request.Context(), common.AuthUser, test.user),
+				)
+			}
+			response := httptest.NewRecorder()
+
+			s.middleware(s.nextHandler).ServeHTTP(response, request)
+
+			var jsonPayload map[string]interface{}
+			_ = json.Unmarshal(response.Body.Bytes(), &jsonPayload)
+
+			s.Equal(test.expectedStatusCode, response.Code)
+			s.Equal(test.expectedResponse, jsonPayload)
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package middlewares
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"verifymy-golang-test/common"
+)
+
+var requestIdPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// RequestInfoMiddleware stores common.RequestInfo in the request context. The
+// X-Request-ID sent by the client, or one generated otherwise, is echoed in
+// the response. X-Forwarded-For is only trusted when trustProxyHeaders is
+// set, since clients can send it too.
+func RequestInfoMiddleware(trustProxyHeaders bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requestId := r.Header.Get("X-Request-ID")
+			if !requestIdPattern.MatchString(requestId) {
+				requestId = uuid.NewString()
+			}
+			w.Header().Set("X-Request-ID", requestId)
+
+			ip, _, err := net.SplitHostPort(r.RemoteAddr)
+			if err != nil {
+				ip = r.RemoteAddr
+			}
+			if forwardedFor := r.Header.Get("X-Forwarded-For"); trustProxyHeaders && forwardedFor != "" {
+				ip = strings.TrimSpace(strings.Split(forwardedFor, ",")[0])
+			}
+
+			info := &common.RequestInfo{
+				IP:        ip,
+				UserAgent: r.UserAgent(),
+				RequestID: requestId,
+				Origin:    r.Header.Get("Origin"),
+			}
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), common.Request, info)))
+		})
+	}
+}
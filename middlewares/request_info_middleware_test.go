@@ -0,0 +1,97 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	"verifymy-golang-test/common"
+)
+
+type requestInfoMiddlewareTestSuite struct {
+	suite.Suite
+}
+
+func TestRequestInfoMiddlewareTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(requestInfoMiddlewareTestSuite))
+}
+
+func (s *requestInfoMiddlewareTestSuite) TestRequestInfoMiddleware() {
+	tests := []struct {
+		description       string
+		trustProxyHeaders bool
+		headers           map[string]string
+		expectedIP        string
+		expectedRequestID string
+	}{
+		{
+			description: "Generated request id",
+			headers:     map[string]string{"User-Agent": "curl/8.0"},
+			expectedIP:  "192.0.2.1",
+		},
+		{
+			description: "Cross-origin request",
+			headers:     map[string]string{"Origin": "https://shop.example.com"},
+			expectedIP:  "192.0.2.1",
+		},
+		{
+			description:       "Request id sent by the client",
+			headers:           map[string]string{"X-Request-ID": "abc-123"},
+			expectedIP:        "192.0.2.1",
+			expectedRequestID: "abc-123",
+		},
+		{
+			description: "Malformed request id",
+			headers:     map[string]string{"X-Request-ID": "abc\n123"},
+			expectedIP:  "192.0.2.1",
+		},
+		{
+			description: "Untrusted proxy headers",
+			headers:     map[string]string{"X-Forwarded-For": "203.0.113.7"},
+			expectedIP:  "192.0.2.1",
+		},
+		{
+			description:       "Trusted proxy headers",
+			trustProxyHeaders: true,
+			headers:           map[string]string{"X-Forwarded-For": "203.0.113.7, 10.0.0.1"},
+			expectedIP:        "203.0.113.7",
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			var info *common.RequestInfo
+			handler := RequestInfoMiddleware(test.trustProxyHeaders)(http.HandlerFunc(
+				func(w http.ResponseWriter, r *http.Request) {
+					info, _ = r.Context().Value(common.Request).(*common.RequestInfo)
+				},
+			))
+
+			request := httptest.NewRequest(http.MethodGet, "/", nil)
+			for name, value := range test.headers {
+				request.Header.Set(name, value)
+			}
+			response := httptest.NewRecorder()
+
+			handler.ServeHTTP(response, request)
+
+			s.Require().NotNil(info)
+			s.Equal(test.expectedIP, info.IP)
+			s.Equal(test.headers["User-Agent"], info.UserAgent)
+			s.Equal(test.headers["Origin"], info.Origin)
+			s.Equal(info.RequestID, response.Header().Get("X-Request-ID"))
+			if test.expectedRequestID != "" {
+				s.Equal(test.expectedRequestID, info.RequestID)
+			} else {
+				_, err := uuid.Parse(info.RequestID)
+				s.NoError(err)
+				s.False(strings.Contains(info.RequestID, "\n"))
+			}
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package middlewares
+
+import (
+	"net/http"
+	"strings"
+
+	"verifymy-golang-test/services"
+)
+
+// SudoMiddleware only lets through requests whose access token is in sudo
+// mode, see AuthService.Sudo. It must run after AuthMiddleware.
+func SudoMiddleware(
+	authService services.AuthService,
+) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			authorizationHeader := strings.Split(r.Header.Get("Authorization"), " ")
+			if len(authorizationHeader) != 2 || !authService.InSudoMode(authorizationHeader[1]) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusForbidden)
+				w.Write([]byte(`{"message": "Re-authentication required"}`))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
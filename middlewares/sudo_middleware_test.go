@@ -0,0 +1,91 @@
+package middlewares
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/suite"
+
+	mock_services "verifymy-golang-test/mocks/services"
+)
+
+type sudoMiddlewareTestSuite struct {
+	suite.Suite
+	ctrl        *gomock.Controller
+	authService *mock_services.MockAuthService
+	middleware  func(http.Handler) http.Handler
+	nextHandler http.Handler
+}
+
+func TestSudoMiddlewareTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(sudoMiddlewareTestSuite))
+}
+
+func (s *sudoMiddlewareTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.authService = mock_services.NewMockAuthService(s.ctrl)
+	s.middleware = SudoMiddleware(s.authService)
+	s.nextHandler = http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		},
+	)
+}
+
+func (s *sudoMiddlewareTestSuite) TestSudoMiddleware() {
+	tests := []struct {
+		description        string
+		authorization      string
+		skipInSudoMode     bool
+		inSudoMode         bool
+		expectedStatusCode int
+		expectedResponse   map[string]interface{}
+	}{
+		{
+			description:        "In sudo mode",
+			authorization:      "Bearer access-token",
+			inSudoMode:         true,
+			expectedStatusCode: http.StatusNoContent,
+		},
+		{
+			description:        "Not in sudo mode",
+			authorization:      "Bearer access-token",
+			expectedStatusCode: http.StatusForbidden,
+			expectedResponse: map[string]interface{}{
+				"message": "Re-authentication required",
+			},
+		},
+		{
+			description:        "No access token",
+			skipInSudoMode:     true,
+			expectedStatusCode: http.StatusForbidden,
+			expectedResponse: map[string]interface{}{
+				"message": "Re-authentication required",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			request := httptest.NewRequest("POST", "/profile/email", nil)
+			request.Header.Set("Authorization", test.authorization)
+			response := httptest.NewRecorder()
+
+			if !test.skipInSudoMode {
+				s.authService.EXPECT().InSudoMode("access-token").Return(test.inSudoMode)
+			}
+
+			s.middleware(s.nextHandler).ServeHTTP(response, request)
+
+			var jsonPayload map[string]interface{}
+			_ = json.Unmarshal(response.Body.Bytes(), &jsonPayload)
+
+			s.Equal(test.expectedStatusCode, response.Code)
+			s.Equal(test.expectedResponse, jsonPayload)
+		})
+	}
+}
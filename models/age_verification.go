@@ -0,0 +1,81 @@
+package models
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	AgeVerificationStatusUnverified = "unverified"
+	AgeVerificationStatusPending    = "pending"
+	AgeVerificationStatusVerified   = "verified"
+	AgeVerificationStatusFailed     = "failed"
+
+	AssuranceLevelLow         = "low"
+	AssuranceLevelSubstantial = "substantial"
+	AssuranceLevelHigh        = "high"
+)
+
+// AgeVerification is the outcome of checking the age of a user. It is kept
+// apart from the date of birth the user declared, which is never trusted to
+// prove their age.
+type AgeVerification struct {
+	ID             uuid.UUID `gorm:"primarykey;type:varchar(36)"`
+	UserID         string    `gorm:"type:varchar(36);uniqueIndex"`
+	Status         string    `gorm:"type:varchar(20)"`
+	Method         string    `gorm:"type:varchar(50)"`
+	AssuranceLevel string    `gorm:"type:varchar(20)"`
+	// AgeOver is the age the user was verified to be at least
+	AgeOver    int
+	VerifiedAt sql.NullTime `gorm:"null"`
+	ExpiresAt  sql.NullTime `gorm:"null"`
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+func (verification *AgeVerification) BeforeCreate(tx *gorm.DB) error {
+	verification.ID = uuid.New()
+	if verification.Status == "" {
+		verification.Status = AgeVerificationStatusUnverified
+	}
+
+	return nil
+}
+
+// Expired checks if the verification no longer proves the age of the user
+func (verification *AgeVerification) Expired(now time.Time) bool {
+	return verification.ExpiresAt.Valid && !now.Before(verification.ExpiresAt.Time)
+}
+
+// MarshalJSON renders what is still unknown about the verification as null
+func (verification AgeVerification) MarshalJSON() ([]byte, error) {
+	body := map[string]interface{}{
+		"status":          verification.Status,
+		"method":          nil,
+		"assurance_level": nil,
+		"age_over":        nil,
+		"verified_at":     nil,
+		"expires_at":      nil,
+	}
+	if verification.Method != "" {
+		body["method"] = verification.Method
+	}
+	if verification.AssuranceLevel != "" {
+		body["assurance_level"] = verification.AssuranceLevel
+	}
+	if verification.AgeOver > 0 {
+		body["age_over"] = verification.AgeOver
+	}
+	if verification.VerifiedAt.Valid {
+		body["verified_at"] = verification.VerifiedAt.Time
+	}
+	if verification.ExpiresAt.Valid {
+		body["expires_at"] = verification.ExpiresAt.Time
+	}
+
+	return json.Marshal(body)
+}
@@ -0,0 +1,111 @@
+package models
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	AgeVerificationSessionStatusPending  = "pending"
+	AgeVerificationSessionStatusVerified = "verified"
+	AgeVerificationSessionStatusFailed   = "failed"
+	AgeVerificationSessionStatusExpired  = "expired"
+)
+
+// ageVerificationSessionTransitions lists the statuses a session can move to
+// from each status. Only pending sessions move; the others are final.
+var ageVerificationSessionTransitions = map[string][]string{
+	AgeVerificationSessionStatusPending: {
+		AgeVerificationSessionStatusVerified,
+		AgeVerificationSessionStatusFailed,
+		AgeVerificationSessionStatusExpired,
+	},
+}
+
+// AgeVerificationSession is an attempt of a user to verify their age with a
+// provider, on behalf of a client when ClientID is set
+type AgeVerificationSession struct {
+	ID       uuid.UUID `gorm:"primarykey;type:varchar(36)"`
+	UserID   string    `gorm:"type:varchar(36);index"`
+	ClientID string    `gorm:"type:varchar(36);index"`
+	Provider string    `gorm:"type:varchar(50);uniqueIndex:idx_age_verification_sessions_reference"`
+	// Reference identifies the session at the provider
+	Reference string `gorm:"type:varchar(255);uniqueIndex:idx_age_verification_sessions_reference"`
+	Method    string `gorm:"type:varchar(50)"`
+	// Country and Category are those of the age rule the session satisfies
+	Country        string `gorm:"type:varchar(2)"`
+	Category       string `gorm:"type:varchar(50)"`
+	AgeOver        int
+	Status         string       `gorm:"type:varchar(20);index"`
+	RedirectURL    string       `gorm:"type:text"`
+	AssuranceLevel string       `gorm:"type:varchar(20)"`
+	FailureReason  string       `gorm:"type:varchar(255)"`
+	ExpiresAt      time.Time    `gorm:"index"`
+	CompletedAt    sql.NullTime `gorm:"null"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+func (session *AgeVerificationSession) BeforeCreate(tx *gorm.DB) error {
+	session.ID = uuid.New()
+	if session.Status == "" {
+		session.Status = AgeVerificationSessionStatusPending
+	}
+
+	return nil
+}
+
+// CanTransitionTo checks if the session can move to status from the one it
+// is in
+func (session *AgeVerificationSession) CanTransitionTo(status string) bool {
+	for _, allowed := range ageVerificationSessionTransitions[session.Status] {
+		if allowed == status {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Expired checks if a pending session ran out of time to be completed
+func (session *AgeVerificationSession) Expired(now time.Time) bool {
+	return session.Status == AgeVerificationSessionStatusPending &&
+		!now.Before(session.ExpiresAt)
+}
+
+// MarshalJSON only includes where to send the user while the session is
+// pending, and why it failed once it did
+func (session AgeVerificationSession) MarshalJSON() ([]byte, error) {
+	body := map[string]interface{}{
+		"id":             session.ID,
+		"client_id":      nil,
+		"method":         session.Method,
+		"country":        session.Country,
+		"category":       session.Category,
+		"age_over":       session.AgeOver,
+		"status":         session.Status,
+		"redirect_url":   nil,
+		"failure_reason": nil,
+		"expires_at":     session.ExpiresAt,
+		"completed_at":   nil,
+		"created_at":     session.CreatedAt,
+	}
+	if session.ClientID != "" {
+		body["client_id"] = session.ClientID
+	}
+	if session.Status == AgeVerificationSessionStatusPending {
+		body["redirect_url"] = session.RedirectURL
+	}
+	if session.FailureReason != "" {
+		body["failure_reason"] = session.FailureReason
+	}
+	if session.CompletedAt.Valid {
+		body["completed_at"] = session.CompletedAt.Time
+	}
+
+	return json.Marshal(body)
+}
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var ErrAuditEventsAppendOnly = errors.New("audit events are append-only")
+
+// AuditEvent records a security-relevant action. Diff holds, keyed by
+// column, the previous and new value of each field the action changed.
+type AuditEvent struct {
+	ID         uuid.UUID `json:"id" gorm:"primarykey;type:varchar(36)"`
+	ActorID    string    `json:"actor_id" gorm:"type:varchar(36);index"`
+	Action     string    `json:"action" gorm:"type:varchar(100);index"`
+	TargetType string    `json:"target_type" gorm:"type:varchar(50)"`
+	TargetID   string    `json:"target_id" gorm:"type:varchar(36);index"`
+	IP         string    `json:"ip" gorm:"type:varchar(45)"`
+	UserAgent  string    `json:"user_agent" gorm:"type:varchar(255)"`
+	RequestID  string    `json:"request_id" gorm:"type:varchar(64);index"`
+	Diff       JSONMap   `json:"diff,omitempty"`
+	Metadata   JSONMap   `json:"metadata"`
+	CreatedAt  time.Time `json:"created_at" gorm:"index"`
+}
+
+func (event *AuditEvent) BeforeCreate(tx *gorm.DB) error {
+	event.ID = uuid.New()
+
+	return nil
+}
+
+func (event *AuditEvent) BeforeUpdate(tx *gorm.DB) error {
+	return ErrAuditEventsAppendOnly
+}
+
+func (event *AuditEvent) BeforeDelete(tx *gorm.DB) error {
+	return ErrAuditEventsAppendOnly
+}
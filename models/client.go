@@ -0,0 +1,60 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	// ClientScopeAgeVerification lets a client send its users through age
+	// verification sessions
+	ClientScopeAgeVerification = "age_verification"
+	// ClientScopeAgeTokens lets a client receive age tokens and verify them
+	ClientScopeAgeTokens = "age_tokens"
+	// ClientScopeOpenID lets a client sign users in through OpenID Connect
+	ClientScopeOpenID = "openid"
+	// ClientScopeProfile and ClientScopeEmail let a client ask users, through
+	// OAuth, for their profile and e-mail address
+	ClientScopeProfile = "profile"
+	ClientScopeEmail   = "email"
+)
+
+// ClientScopes lists the scopes a client can be granted
+var ClientScopes = []string{
+	ClientScopeAgeVerification,
+	ClientScopeAgeTokens,
+	ClientScopeOpenID,
+	ClientScopeProfile,
+	ClientScopeEmail,
+}
+
+// ClientUserScopes are the scopes users grant clients through the OAuth
+// authorization code flow. The others are granted to clients themselves,
+// through the client credentials grant.
+var ClientUserScopes = StringList{ClientScopeOpenID, ClientScopeProfile, ClientScopeEmail}
+
+// Client is a relying party calling the API for age checks. Its ID is the
+// client_id it authenticates with, along with a secret of which only the
+// hash is kept.
+type Client struct {
+	ID             uuid.UUID   `json:"id" gorm:"primarykey;type:varchar(36)"`
+	Name           string      `json:"name" gorm:"type:varchar(255)"`
+	RedirectURIs   StringList  `json:"redirect_uris" gorm:"column:redirect_uris"`
+	AllowedOrigins StringList  `json:"allowed_origins"`
+	Scopes         StringList  `json:"scopes"`
+	SecretHash     SecretValue `json:"-" gorm:"type:varchar(255)"`
+	CreatedAt      time.Time   `json:"created_at"`
+	UpdatedAt      time.Time   `json:"updated_at"`
+}
+
+func (client *Client) BeforeCreate(tx *gorm.DB) error {
+	client.ID = uuid.New()
+	return nil
+}
+
+// HasScope checks if the client was granted scope
+func (client *Client) HasScope(scope string) bool {
+	return client.Scopes.Includes(scope)
+}
@@ -2,6 +2,7 @@ package models
 
 import (
 	"database/sql/driver"
+	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
@@ -24,8 +25,8 @@ func (d *Date) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
-func (d *Date) MarshalJSON() ([]byte, error) {
-	timeObj := time.Time(*d)
+func (d Date) MarshalJSON() ([]byte, error) {
+	timeObj := time.Time(d)
 	return []byte(fmt.Sprintf(`"%s"`, timeObj.Format(DateFormat))), nil
 }
 
@@ -62,6 +63,18 @@ func (Date) GormDataType() string {
 	return "DATE"
 }
 
+// AgeOn returns how many full years old someone born on d is on day
+func (d Date) AgeOn(day time.Time) int {
+	dateOfBirth := time.Time(d)
+	age := day.Year() - dateOfBirth.Year()
+	if day.Month() < dateOfBirth.Month() ||
+		(day.Month() == dateOfBirth.Month() && day.Day() < dateOfBirth.Day()) {
+		age--
+	}
+
+	return age
+}
+
 type SecretValue string
 
 func (sv *SecretValue) MarshalJSON() ([]byte, error) {
@@ -94,3 +107,82 @@ func (sv *SecretValue) UnmarshalText(value string) error {
 func (*SecretValue) GormDataType() string {
 	return "VARCHAR"
 }
+
+// JSONMap is stored as a JSON encoded text column
+type JSONMap map[string]interface{}
+
+func (m JSONMap) Value() (driver.Value, error) {
+	if m == nil {
+		return nil, nil
+	}
+
+	bytes, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+
+	return driver.Value(string(bytes)), nil
+}
+
+func (m *JSONMap) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case []byte:
+		return json.Unmarshal(v, m)
+	case string:
+		return json.Unmarshal([]byte(v), m)
+	case nil:
+		*m = nil
+	default:
+		return fmt.Errorf("cannot sql.Scan() JSONMap from: %#v", v)
+	}
+	return nil
+}
+
+func (JSONMap) GormDataType() string {
+	return "TEXT"
+}
+
+// StringList is stored as a JSON encoded text column
+type StringList []string
+
+func (l StringList) Value() (driver.Value, error) {
+	if l == nil {
+		return nil, nil
+	}
+
+	bytes, err := json.Marshal(l)
+	if err != nil {
+		return nil, err
+	}
+
+	return driver.Value(string(bytes)), nil
+}
+
+func (l *StringList) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case []byte:
+		return json.Unmarshal(v, l)
+	case string:
+		return json.Unmarshal([]byte(v), l)
+	case nil:
+		*l = nil
+	default:
+		return fmt.Errorf("cannot sql.Scan() StringList from: %#v", v)
+	}
+	return nil
+}
+
+func (StringList) GormDataType() string {
+	return "TEXT"
+}
+
+// Includes checks if value is in the list
+func (l StringList) Includes(value string) bool {
+	for _, item := range l {
+		if item == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	DataExportStatusPending = "pending"
+	DataExportStatusReady   = "ready"
+	DataExportStatusFailed  = "failed"
+)
+
+// DataExport is a request of a user for a copy of their data, which is
+// packaged in the background. It is deleted, along with its file, once it
+// expires.
+type DataExport struct {
+	ID          uuid.UUID    `json:"id" gorm:"primarykey;type:varchar(36)"`
+	UserID      string       `json:"-" gorm:"type:varchar(36);index"`
+	Status      string       `json:"status" gorm:"type:varchar(20);index"`
+	File        string       `json:"-" gorm:"type:varchar(255)"`
+	CreatedAt   time.Time    `json:"created_at"`
+	CompletedAt sql.NullTime `json:"-" gorm:"null"`
+	ExpiresAt   sql.NullTime `json:"-" gorm:"null;index"`
+}
+
+func (export *DataExport) BeforeCreate(tx *gorm.DB) error {
+	export.ID = uuid.New()
+	if export.Status == "" {
+		export.Status = DataExportStatusPending
+	}
+
+	return nil
+}
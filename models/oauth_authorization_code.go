@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// OAuthAuthorizationCode is handed to a client once a user authorizes it,
+// to be exchanged for tokens along with the PKCE code verifier matching
+// CodeChallenge
+type OAuthAuthorizationCode struct {
+	// CodeHash is the SHA-256 of the code, which is only known by the client
+	CodeHash    string `gorm:"primarykey;type:varchar(64)"`
+	ClientID    string `gorm:"type:varchar(36)"`
+	UserID      string `gorm:"type:varchar(36);index"`
+	RedirectURI string `gorm:"type:text"`
+	Scopes      StringList
+	// CodeChallenge is the S256 challenge, the only method supported
+	CodeChallenge string `gorm:"type:varchar(128)"`
+	// Nonce is sent by OpenID Connect clients, and copied into the ID token
+	Nonce     string `gorm:"type:varchar(255)"`
+	ExpiresAt time.Time
+	CreatedAt time.Time
+}
+
+func (OAuthAuthorizationCode) TableName() string {
+	return "oauth_authorization_codes"
+}
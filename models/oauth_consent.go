@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// OAuthConsent keeps the scopes a user granted a client, so the consent
+// screen can be skipped when the client asks for them again
+type OAuthConsent struct {
+	ID        uuid.UUID `gorm:"primarykey;type:varchar(36)"`
+	UserID    string    `gorm:"type:varchar(36);uniqueIndex:idx_oauth_consents_user_client"`
+	ClientID  string    `gorm:"type:varchar(36);uniqueIndex:idx_oauth_consents_user_client"`
+	Scopes    StringList
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (OAuthConsent) TableName() string {
+	return "oauth_consents"
+}
+
+func (consent *OAuthConsent) BeforeCreate(tx *gorm.DB) error {
+	consent.ID = uuid.New()
+	return nil
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// OAuthGrant is an authorization a client holds through OAuth, given by a
+// user or, with the client credentials grant, to the client itself. The
+// access tokens issued from it stop working once it is revoked.
+type OAuthGrant struct {
+	ID       uuid.UUID `gorm:"primarykey;type:varchar(36)"`
+	ClientID string    `gorm:"type:varchar(36);index"`
+	// UserID is empty for grants of the client itself
+	UserID string `gorm:"type:varchar(36);index"`
+	Scopes StringList
+	// RefreshTokenHash is the SHA-256 of the refresh token, replaced every
+	// time it is used. Grants of the client itself have none.
+	RefreshTokenHash      string       `gorm:"type:varchar(64);index"`
+	RefreshTokenExpiresAt sql.NullTime `gorm:"null"`
+	RevokedAt             sql.NullTime `gorm:"null"`
+	CreatedAt             time.Time
+	UpdatedAt             time.Time
+}
+
+func (OAuthGrant) TableName() string {
+	return "oauth_grants"
+}
+
+func (grant *OAuthGrant) BeforeCreate(tx *gorm.DB) error {
+	grant.ID = uuid.New()
+	return nil
+}
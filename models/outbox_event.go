@@ -0,0 +1,29 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// OutboxEvent is a domain event stored in the transaction of the write it
+// describes, until it is relayed to the event sinks. DeliveredSinks names the
+// sinks it reached while others failed it, so it is only retried on those.
+type OutboxEvent struct {
+	ID             uuid.UUID    `json:"id" gorm:"primarykey;type:varchar(36)"`
+	Type           string       `json:"type" gorm:"type:varchar(100)"`
+	AggregateID    string       `json:"aggregate_id" gorm:"type:varchar(36);index"`
+	Payload        JSONMap      `json:"data"`
+	Attempts       int          `json:"-"`
+	LastError      string       `json:"-" gorm:"type:text"`
+	DeliveredSinks StringList   `json:"-"`
+	CreatedAt      time.Time    `json:"occurred_at" gorm:"index"`
+	DeliveredAt    sql.NullTime `json:"-" gorm:"null;index"`
+}
+
+func (event *OutboxEvent) BeforeCreate(tx *gorm.DB) error {
+	event.ID = uuid.New()
+	return nil
+}
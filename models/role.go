@@ -0,0 +1,43 @@
+package models
+
+type Permission string
+
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+
+	PermissionManageUsers    Permission = "users:manage"
+	PermissionViewAuditLog   Permission = "audit:view"
+	PermissionManageWebhooks Permission = "webhooks:manage"
+	PermissionManageClients  Permission = "clients:manage"
+)
+
+// Roles lists the roles a user can be assigned
+var Roles = []interface{}{RoleUser, RoleAdmin}
+
+var rolePermissions = map[string][]Permission{
+	RoleAdmin: {
+		PermissionManageUsers,
+		PermissionViewAuditLog,
+		PermissionManageWebhooks,
+		PermissionManageClients,
+	},
+}
+
+// RolePermissions returns the permissions granted by a role
+func RolePermissions(role string) []Permission {
+	permissions := []Permission{}
+
+	return append(permissions, rolePermissions[role]...)
+}
+
+// Can checks if the role of the user grants the permission
+func (user *User) Can(permission Permission) bool {
+	for _, granted := range rolePermissions[user.Role] {
+		if granted == permission {
+			return true
+		}
+	}
+
+	return false
+}
@@ -2,23 +2,54 @@ package models
 
 import (
 	"database/sql"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
+const (
+	UserStatusActive = "active"
+	// UserStatusPendingParentalConsent restricts users until their guardian
+	// consents to them signing up
+	UserStatusPendingParentalConsent  = "pending_parental_consent"
+	UserStatusParentalConsentRejected = "parental_consent_rejected"
+)
+
 type User struct {
-	ID          uuid.UUID    `json:"id" gorm:"primarykey;type:varchar(36)"`
-	Name        string       `json:"name" gorm:"type:varchar(255)"`
-	DateOfBirth Date         `json:"date_of_birth" gorm:"type:date"`
-	Email       string       `json:"email" gorm:"type:varchar(255)"`
-	Password    SecretValue  `json:"password" gorm:"type:varchar(255)"`
-	Address     string       `json:"address" gorm:"type:varchar(255)"`
-	DeletedAt   sql.NullTime `json:"-" gorm:"null;index"`
+	ID           uuid.UUID      `json:"id" gorm:"primarykey;type:varchar(36)"`
+	Name         string         `json:"name" gorm:"type:varchar(255)"`
+	DateOfBirth  Date           `json:"date_of_birth" gorm:"type:date"`
+	Email        string         `json:"email" gorm:"type:varchar(255)"`
+	Password     SecretValue    `json:"password" gorm:"type:varchar(255)"`
+	Address      string         `json:"address" gorm:"type:varchar(255)"`
+	Role         string         `json:"role" gorm:"type:varchar(20);not null;default:user"`
+	Status       string         `json:"status" gorm:"type:varchar(30);not null;default:active"`
+	CreatedAt    time.Time      `json:"created_at" gorm:"index"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	Version      int64          `json:"-" gorm:"not null;default:1"`
+	DeletedAt    gorm.DeletedAt `json:"-" gorm:"index"`
+	AnonymizedAt sql.NullTime   `json:"-" gorm:"null"`
+	// SessionsRevokedAt invalidates the access tokens issued before it
+	SessionsRevokedAt sql.NullTime `json:"-" gorm:"null"`
+	// GuardianEmail is where the parental consent of users signing up under
+	// the age of consent is requested, see UserStatusPendingParentalConsent
+	GuardianEmail string `json:"guardian_email,omitempty" gorm:"type:varchar(255)"`
+	// ActiveEmail is a generated column backing the unique email index, see
+	// providers.Migrate. It is NULL for soft deleted users, so their email can
+	// be registered again.
+	ActiveEmail *string `json:"-" gorm:"->;-:migration"`
 }
 
 func (user *User) BeforeCreate(tx *gorm.DB) error {
 	user.ID = uuid.New()
+	user.Version = 1
+	if user.Role == "" {
+		user.Role = RoleUser
+	}
+	if user.Status == "" {
+		user.Status = UserStatusActive
+	}
 
 	return nil
 }
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// UserTombstone is the record kept of an erased user, as evidence of the
+// erasure. It holds no personal data.
+type UserTombstone struct {
+	ID          uuid.UUID `json:"id" gorm:"primarykey;type:varchar(36)"`
+	UserID      string    `json:"user_id" gorm:"type:varchar(36);index"`
+	RequestedBy string    `json:"requested_by" gorm:"type:varchar(36)"`
+	// Steps lists, comma separated, the erasure steps that were carried out
+	Steps    string    `json:"steps" gorm:"type:varchar(1000)"`
+	ErasedAt time.Time `json:"erased_at"`
+}
+
+func (tombstone *UserTombstone) BeforeCreate(tx *gorm.DB) error {
+	tombstone.ID = uuid.New()
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	WebhookDeliveryStatusPending   = "pending"
+	WebhookDeliveryStatusSucceeded = "succeeded"
+	WebhookDeliveryStatusDead      = "dead"
+)
+
+// WebhookDelivery is a domain event to be pushed to a subscription. Failed
+// deliveries are retried until they run out of attempts and are dead, which
+// takes a redelivery to retry them again.
+type WebhookDelivery struct {
+	ID             uuid.UUID `json:"id" gorm:"primarykey;type:varchar(36)"`
+	SubscriptionID string    `json:"subscription_id" gorm:"type:varchar(36);uniqueIndex:idx_webhook_deliveries_event"`
+	EventID        string    `json:"event_id" gorm:"type:varchar(36);uniqueIndex:idx_webhook_deliveries_event"`
+	EventType      string    `json:"event_type" gorm:"type:varchar(100)"`
+	// Payload is the body sent, so redeliveries send the same one
+	Payload       string                   `json:"-" gorm:"type:text"`
+	Status        string                   `json:"status" gorm:"type:varchar(20);index"`
+	AttemptCount  int                      `json:"attempt_count"`
+	NextAttemptAt time.Time                `json:"next_attempt_at" gorm:"index"`
+	DeliveredAt   sql.NullTime             `json:"-" gorm:"null"`
+	CreatedAt     time.Time                `json:"created_at" gorm:"index"`
+	UpdatedAt     time.Time                `json:"updated_at"`
+	Attempts      []WebhookDeliveryAttempt `json:"attempts" gorm:"foreignKey:DeliveryID"`
+}
+
+func (delivery *WebhookDelivery) BeforeCreate(tx *gorm.DB) error {
+	delivery.ID = uuid.New()
+	if delivery.Status == "" {
+		delivery.Status = WebhookDeliveryStatusPending
+	}
+
+	return nil
+}
+
+// WebhookDeliveryAttempt logs an attempt to deliver a webhook, with the
+// status answered or the error that kept it from answering
+type WebhookDeliveryAttempt struct {
+	ID         uuid.UUID `json:"id" gorm:"primarykey;type:varchar(36)"`
+	DeliveryID string    `json:"-" gorm:"type:varchar(36);index"`
+	StatusCode int       `json:"status_code"`
+	Error      string    `json:"error" gorm:"type:text"`
+	DurationMs int64     `json:"duration_ms"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func (attempt *WebhookDeliveryAttempt) BeforeCreate(tx *gorm.DB) error {
+	attempt.ID = uuid.New()
+	return nil
+}
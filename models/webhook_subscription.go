@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// WebhookSubscription pushes the domain events of the listed types to URL.
+// Deliveries are signed with Secret, which is only shown when it is set.
+type WebhookSubscription struct {
+	ID         uuid.UUID   `json:"id" gorm:"primarykey;type:varchar(36)"`
+	URL        string      `json:"url" gorm:"type:varchar(2048)"`
+	EventTypes StringList  `json:"event_types"`
+	Secret     SecretValue `json:"-" gorm:"type:varchar(255)"`
+	CreatedAt  time.Time   `json:"created_at"`
+	UpdatedAt  time.Time   `json:"updated_at"`
+}
+
+func (subscription *WebhookSubscription) BeforeCreate(tx *gorm.DB) error {
+	subscription.ID = uuid.New()
+	return nil
+}
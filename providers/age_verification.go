@@ -0,0 +1,78 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+const (
+	AgeVerificationMethodDocument        = "document"
+	AgeVerificationMethodCreditCard      = "credit_card"
+	AgeVerificationMethodEmailEstimation = "email_estimation"
+	AgeVerificationMethodMobileOperator  = "mobile_operator"
+)
+
+// AgeVerificationMethods lists the methods providers can verify ages through
+var AgeVerificationMethods = []string{
+	AgeVerificationMethodDocument,
+	AgeVerificationMethodCreditCard,
+	AgeVerificationMethodEmailEstimation,
+	AgeVerificationMethodMobileOperator,
+}
+
+// ErrInvalidAgeVerificationCallback is returned for callbacks that can't be
+// trusted to come from the provider, like those with a wrong signature.
+var ErrInvalidAgeVerificationCallback = errors.New("invalid age verification callback")
+
+// AgeVerificationRequest asks a provider to check that a user is at least
+// AgeOver years old
+type AgeVerificationRequest struct {
+	UserID string
+	// DateOfBirth is the self-declared one, which providers may use to prefill
+	// their forms but never as proof
+	DateOfBirth time.Time
+	AgeOver     int
+	// ReturnURL is where the user is sent back to once done with the provider
+	ReturnURL string
+	// CallbackURL is where the provider notifies the outcome, when it does
+	CallbackURL string
+}
+
+// AgeVerificationSession is a verification started with a provider, which the
+// user completes at RedirectURL
+type AgeVerificationSession struct {
+	Reference   string
+	RedirectURL string
+	ExpiresAt   time.Time
+}
+
+// AgeVerificationResult is how a session with a provider stands. Status is
+// one of the models.AgeVerificationStatus values other than unverified.
+type AgeVerificationResult struct {
+	Reference      string
+	Status         string
+	AssuranceLevel string
+	AgeOver        int
+	VerifiedAt     time.Time
+	ExpiresAt      time.Time
+	// Reason tells why the verification failed
+	Reason string
+}
+
+// AgeVerificationProvider verifies the age of users through a single
+// method. Outcomes are either polled or pushed by the provider to the
+// callback, whichever it supports; providers that never call back don't
+// need to handle callbacks.
+type AgeVerificationProvider interface {
+	Name() string
+	Method() string
+	StartSession(
+		ctx context.Context, request AgeVerificationRequest,
+	) (*AgeVerificationSession, error)
+	Poll(ctx context.Context, reference string) (*AgeVerificationResult, error)
+	HandleCallback(
+		ctx context.Context, header http.Header, body []byte,
+	) (*AgeVerificationResult, error)
+}
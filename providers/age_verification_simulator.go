@@ -0,0 +1,222 @@
+package providers
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	"verifymy-golang-test/models"
+)
+
+const (
+	ageVerificationSimulatorSessionLifetime = 30 * time.Minute
+	ageVerificationSimulatorValidity        = 365 * 24 * time.Hour
+
+	AgeVerificationSimulatorSignatureHeader = "X-Simulator-Signature"
+)
+
+// ErrUnknownAgeVerificationSession is returned for references a provider
+// never issued
+var ErrUnknownAgeVerificationSession = errors.New("unknown age verification session")
+
+var simulatedAssuranceLevels = map[string]string{
+	AgeVerificationMethodDocument:        models.AssuranceLevelHigh,
+	AgeVerificationMethodCreditCard:      models.AssuranceLevelSubstantial,
+	AgeVerificationMethodEmailEstimation: models.AssuranceLevelLow,
+	AgeVerificationMethodMobileOperator:  models.AssuranceLevelSubstantial,
+}
+
+type simulatedSession struct {
+	result  AgeVerificationResult
+	readyAt time.Time
+}
+
+// ageVerificationSimulator verifies ages offline, trusting the declared date
+// of birth: users old enough are verified and everyone else fails. Sessions
+// are kept in memory and stay pending for AGE_VERIFICATION_SIMULATOR_DELAY,
+// unless a callback completes them first.
+type ageVerificationSimulator struct {
+	method   string
+	delay    time.Duration
+	secret   []byte
+	sessions map[string]*simulatedSession
+	mutex    sync.Mutex
+	now      func() time.Time
+}
+
+// AgeVerificationSimulatorEnabled tells whether the simulator is available.
+// Since it trusts the declared date of birth, it is only available in the test
+// and dev environments, unless AGE_VERIFICATION_SIMULATOR_ENABLED is true.
+func AgeVerificationSimulatorEnabled() bool {
+	return ageVerificationSimulatorEnabled(
+		os.Getenv("ENV"), os.Getenv("AGE_VERIFICATION_SIMULATOR_ENABLED"),
+	)
+}
+
+func ageVerificationSimulatorEnabled(env string, enabled string) bool {
+	return env == "test" || env == "dev" || enabled == "true"
+}
+
+// NewAgeVerificationSimulator simulates the method in
+// AGE_VERIFICATION_SIMULATOR_METHOD, document by default. Callbacks are
+// signed with AGE_VERIFICATION_SIMULATOR_SECRET, which is required.
+func NewAgeVerificationSimulator() (AgeVerificationProvider, error) {
+	method := os.Getenv("AGE_VERIFICATION_SIMULATOR_METHOD")
+	if method == "" {
+		method = AgeVerificationMethodDocument
+	} else if _, ok := simulatedAssuranceLevels[method]; !ok {
+		return nil, fmt.Errorf("unknown method %q in AGE_VERIFICATION_SIMULATOR_METHOD", method)
+	}
+
+	var delay time.Duration
+	if value := os.Getenv("AGE_VERIFICATION_SIMULATOR_DELAY"); value != "" {
+		var err error
+		if delay, err = time.ParseDuration(value); err != nil {
+			return nil, fmt.Errorf("invalid AGE_VERIFICATION_SIMULATOR_DELAY: %w", err)
+		}
+	}
+
+	secret := os.Getenv("AGE_VERIFICATION_SIMULATOR_SECRET")
+	if secret == "" {
+		return nil, errors.New("AGE_VERIFICATION_SIMULATOR_SECRET is required")
+	}
+
+	return &ageVerificationSimulator{
+		method:   method,
+		delay:    delay,
+		secret:   []byte(secret),
+		sessions: map[string]*simulatedSession{},
+		now:      time.Now,
+	}, nil
+}
+
+func (p *ageVerificationSimulator) Name() string {
+	return "simulator"
+}
+
+func (p *ageVerificationSimulator) Method() string {
+	return p.method
+}
+
+// StartSession settles the outcome right away. The user is sent straight
+// back to the return URL, since there is nothing for them to do.
+func (p *ageVerificationSimulator) StartSession(
+	ctx context.Context, request AgeVerificationRequest,
+) (*AgeVerificationSession, error) {
+	reference, err := simulatedReference()
+	if err != nil {
+		return nil, err
+	}
+
+	now := p.now()
+	result := AgeVerificationResult{Reference: reference, AgeOver: request.AgeOver}
+	if request.DateOfBirth.IsZero() {
+		result.Status = models.AgeVerificationStatusFailed
+		result.Reason = "date of birth is unknown"
+	} else if models.Date(request.DateOfBirth).AgeOn(now) < request.AgeOver {
+		result.Status = models.AgeVerificationStatusFailed
+		result.Reason = fmt.Sprintf("user is under %d", request.AgeOver)
+	} else {
+		result.Status = models.AgeVerificationStatusVerified
+		result.AssuranceLevel = simulatedAssuranceLevels[p.method]
+		result.VerifiedAt = now
+		result.ExpiresAt = now.Add(ageVerificationSimulatorValidity)
+	}
+
+	p.mutex.Lock()
+	p.sessions[reference] = &simulatedSession{result: result, readyAt: now.Add(p.delay)}
+	p.mutex.Unlock()
+
+	redirectURL := request.ReturnURL
+	if parsed, err := url.Parse(request.ReturnURL); err == nil && request.ReturnURL != "" {
+		query := parsed.Query()
+		query.Set("reference", reference)
+		parsed.RawQuery = query.Encode()
+		redirectURL = parsed.String()
+	}
+
+	return &AgeVerificationSession{
+		Reference:   reference,
+		RedirectURL: redirectURL,
+		ExpiresAt:   now.Add(ageVerificationSimulatorSessionLifetime),
+	}, nil
+}
+
+func (p *ageVerificationSimulator) Poll(
+	ctx context.Context, reference string,
+) (*AgeVerificationResult, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	session, ok := p.sessions[reference]
+	if !ok {
+		return nil, ErrUnknownAgeVerificationSession
+	}
+
+	if p.now().Before(session.readyAt) {
+		return &AgeVerificationResult{
+			Reference: reference,
+			Status:    models.AgeVerificationStatusPending,
+			AgeOver:   session.result.AgeOver,
+		}, nil
+	}
+
+	result := session.result
+	return &result, nil
+}
+
+// HandleCallback takes {"reference": "..."} signed in X-Simulator-Signature
+// with the hex HMAC-SHA256 of the body, and completes the session.
+func (p *ageVerificationSimulator) HandleCallback(
+	ctx context.Context, header http.Header, body []byte,
+) (*AgeVerificationResult, error) {
+	signature, err := hex.DecodeString(header.Get(AgeVerificationSimulatorSignatureHeader))
+	if err != nil || !hmac.Equal(signature, p.sign(body)) {
+		return nil, ErrInvalidAgeVerificationCallback
+	}
+
+	var payload struct {
+		Reference string `json:"reference"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, ErrInvalidAgeVerificationCallback
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	session, ok := p.sessions[payload.Reference]
+	if !ok {
+		return nil, ErrUnknownAgeVerificationSession
+	}
+
+	session.readyAt = p.now()
+	result := session.result
+	return &result, nil
+}
+
+func (p *ageVerificationSimulator) sign(body []byte) []byte {
+	mac := hmac.New(sha256.New, p.secret)
+	mac.Write(body)
+
+	return mac.Sum(nil)
+}
+
+func simulatedReference() (string, error) {
+	bytes := make([]byte, 16)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+
+	return "sim_" + hex.EncodeToString(bytes), nil
+}
@@ -0,0 +1,205 @@
+package providers
+
+import (
+	"context"
+	"encoding/hex"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"verifymy-golang-test/models"
+)
+
+type ageVerificationSimulatorTestSuite struct {
+	suite.Suite
+	ctx       context.Context
+	now       time.Time
+	simulator *ageVerificationSimulator
+}
+
+func TestAgeVerificationSimulatorTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(ageVerificationSimulatorTestSuite))
+}
+
+func (s *ageVerificationSimulatorTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.now = time.Date(2023, 6, 30, 12, 0, 0, 0, time.UTC)
+	s.simulator = &ageVerificationSimulator{
+		method:   AgeVerificationMethodCreditCard,
+		delay:    time.Minute,
+		secret:   []byte("simulator-secret"),
+		sessions: map[string]*simulatedSession{},
+		now:      func() time.Time { return s.now },
+	}
+}
+
+func (s *ageVerificationSimulatorTestSuite) TestAgeVerificationSimulatorEnabled() {
+	tests := []struct {
+		description string
+		env         string
+		enabled     string
+		expected    bool
+	}{
+		{description: "Test environment", env: "test", expected: true},
+		{description: "Dev environment", env: "dev", expected: true},
+		{description: "Production", env: "production"},
+		{description: "No environment"},
+		{description: "Enabled explicitly", env: "production", enabled: "true", expected: true},
+		{description: "Disabled explicitly", env: "production", enabled: "false"},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			s.Equal(test.expected, ageVerificationSimulatorEnabled(test.env, test.enabled))
+		})
+	}
+}
+
+func (s *ageVerificationSimulatorTestSuite) start(dateOfBirth time.Time) *AgeVerificationSession {
+	session, err := s.simulator.StartSession(s.ctx, AgeVerificationRequest{
+		UserID:      "6f1c2d3e-4b5a-4c7d-8e9f-0a1b2c3d4e5f",
+		DateOfBirth: dateOfBirth,
+		AgeOver:     18,
+		ReturnURL:   "https://app.example.com/verified?from=simulator",
+	})
+	s.Require().NoError(err)
+
+	return session
+}
+
+func (s *ageVerificationSimulatorTestSuite) TestStartSession() {
+	session := s.start(time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	s.Regexp("^sim_[0-9a-f]{32}$", session.Reference)
+	s.Equal(
+		"https://app.example.com/verified?from=simulator&reference="+session.Reference,
+		session.RedirectURL,
+	)
+	s.Equal(s.now.Add(30*time.Minute), session.ExpiresAt)
+}
+
+func (s *ageVerificationSimulatorTestSuite) TestPoll() {
+	tests := []struct {
+		description    string
+		dateOfBirth    time.Time
+		expectedResult AgeVerificationResult
+	}{
+		{
+			description: "Old enough",
+			dateOfBirth: time.Date(2005, 6, 30, 0, 0, 0, 0, time.UTC),
+			expectedResult: AgeVerificationResult{
+				Status:         models.AgeVerificationStatusVerified,
+				AssuranceLevel: models.AssuranceLevelSubstantial,
+				AgeOver:        18,
+				VerifiedAt:     time.Date(2023, 6, 30, 12, 0, 0, 0, time.UTC),
+				ExpiresAt:      time.Date(2024, 6, 29, 12, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			description: "Underage",
+			dateOfBirth: time.Date(2005, 7, 1, 0, 0, 0, 0, time.UTC),
+			expectedResult: AgeVerificationResult{
+				Status:  models.AgeVerificationStatusFailed,
+				AgeOver: 18,
+				Reason:  "user is under 18",
+			},
+		},
+		{
+			description: "Unknown date of birth",
+			expectedResult: AgeVerificationResult{
+				Status:  models.AgeVerificationStatusFailed,
+				AgeOver: 18,
+				Reason:  "date of birth is unknown",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			startedAt := s.now
+			session := s.start(test.dateOfBirth)
+
+			result, err := s.simulator.Poll(s.ctx, session.Reference)
+			s.NoError(err)
+			s.Equal(&AgeVerificationResult{
+				Reference: session.Reference,
+				Status:    models.AgeVerificationStatusPending,
+				AgeOver:   18,
+			}, result)
+
+			s.now = startedAt.Add(time.Minute)
+			defer func() { s.now = startedAt }()
+
+			result, err = s.simulator.Poll(s.ctx, session.Reference)
+			test.expectedResult.Reference = session.Reference
+			s.NoError(err)
+			s.Equal(&test.expectedResult, result)
+		})
+	}
+}
+
+func (s *ageVerificationSimulatorTestSuite) TestPollUnknownSession() {
+	result, err := s.simulator.Poll(s.ctx, "sim_unknown")
+	s.Nil(result)
+	s.Equal(ErrUnknownAgeVerificationSession, err)
+}
+
+func (s *ageVerificationSimulatorTestSuite) TestHandleCallback() {
+	session := s.start(time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC))
+	body := []byte(`{"reference":"` + session.Reference + `"}`)
+	unknownBody := []byte(`{"reference":"sim_unknown"}`)
+
+	tests := []struct {
+		description    string
+		body           []byte
+		signature      string
+		expectedStatus string
+		expectedError  error
+	}{
+		{
+			description:   "Missing signature",
+			body:          body,
+			expectedError: ErrInvalidAgeVerificationCallback,
+		},
+		{
+			description:   "Wrong signature",
+			body:          body,
+			signature:     hex.EncodeToString(s.simulator.sign(unknownBody)),
+			expectedError: ErrInvalidAgeVerificationCallback,
+		},
+		{
+			description:   "Unknown session",
+			body:          unknownBody,
+			signature:     hex.EncodeToString(s.simulator.sign(unknownBody)),
+			expectedError: ErrUnknownAgeVerificationSession,
+		},
+		{
+			description:    "Success",
+			body:           body,
+			signature:      hex.EncodeToString(s.simulator.sign(body)),
+			expectedStatus: models.AgeVerificationStatusVerified,
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			header := http.Header{}
+			header.Set(AgeVerificationSimulatorSignatureHeader, test.signature)
+
+			result, err := s.simulator.HandleCallback(s.ctx, header, test.body)
+
+			s.Equal(test.expectedError, err)
+			if test.expectedError == nil {
+				s.Equal(test.expectedStatus, result.Status)
+			}
+		})
+	}
+
+	// The callback completes the session without waiting for the delay
+	result, err := s.simulator.Poll(s.ctx, session.Reference)
+	s.NoError(err)
+	s.Equal(models.AgeVerificationStatusVerified, result.Status)
+}
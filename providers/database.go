@@ -22,12 +22,13 @@ func NewDBConnection(dialector gorm.Dialector) (*gorm.DB, error) {
 		db, err := gorm.Open(dialector, &gorm.Config{
 			Logger:                                   logger.Default.LogMode(logger.Silent),
 			DisableForeignKeyConstraintWhenMigrating: true,
+			TranslateError:                           true,
 		})
 		if err != nil {
 			return nil, err
 		}
 
-		if err := db.AutoMigrate(&models.User{}); err != nil {
+		if err := Migrate(db); err != nil {
 			return nil, err
 		}
 
@@ -37,6 +38,76 @@ func NewDBConnection(dialector gorm.Dialector) (*gorm.DB, error) {
 	return dbConn, nil
 }
 
+const userActiveEmailIndex = "idx_users_active_email"
+
+// Migrate brings the schema up to date. The generated active_email column and
+// its unique index are created by hand, since GORM can neither tell that a
+// generated column is up to date nor add a unique column to an existing
+// SQLite table.
+func Migrate(db *gorm.DB) error {
+	if err := db.AutoMigrate(
+		&models.User{}, &models.AuditEvent{}, &models.UserTombstone{}, &models.DataExport{},
+		&models.OutboxEvent{}, &models.WebhookSubscription{}, &models.WebhookDelivery{},
+		&models.WebhookDeliveryAttempt{}, &models.AgeVerification{}, &models.AgeVerificationSession{},
+		&models.Client{}, &models.OAuthGrant{}, &models.OAuthAuthorizationCode{}, &models.OAuthConsent{},
+	); err != nil {
+		return err
+	}
+
+	migrator := db.Migrator()
+	if !migrator.HasColumn(&models.User{}, "active_email") {
+		err := db.Exec(
+			"ALTER TABLE users ADD COLUMN active_email VARCHAR(255) " +
+				"GENERATED ALWAYS AS (CASE WHEN deleted_at IS NULL THEN LOWER(email) END) VIRTUAL",
+		).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	if migrator.HasIndex(&models.User{}, userActiveEmailIndex) {
+		return nil
+	}
+
+	if err := deduplicateUserEmails(db); err != nil {
+		return err
+	}
+
+	return db.Exec(
+		"CREATE UNIQUE INDEX " + userActiveEmailIndex + " ON users (active_email)",
+	).Error
+}
+
+// deduplicateUserEmails prepares users stored before emails were unique for
+// the unique email index. Emails are trimmed and lowercased, as they are
+// stored now, and when several active users share an email all but the
+// oldest are soft deleted, so an administrator can restore them once the
+// conflict is resolved.
+func deduplicateUserEmails(db *gorm.DB) error {
+	if err := db.Exec(
+		"UPDATE users SET email = LOWER(TRIM(email)) WHERE email IS NOT NULL",
+	).Error; err != nil {
+		return err
+	}
+
+	var duplicateIds []string
+	if err := db.Raw(
+		"SELECT DISTINCT newer.id FROM users AS newer " +
+			"JOIN users AS older ON older.email = newer.email AND older.deleted_at IS NULL " +
+			"AND (older.created_at < newer.created_at " +
+			"OR (older.created_at = newer.created_at AND older.id < newer.id)) " +
+			"WHERE newer.deleted_at IS NULL",
+	).Scan(&duplicateIds).Error; err != nil {
+		return err
+	}
+
+	if len(duplicateIds) == 0 {
+		return nil
+	}
+
+	return db.Where("id IN ?", duplicateIds).Delete(&models.User{}).Error
+}
+
 func NewDBDialector() gorm.Dialector {
 	driver, connString := getDriverAndConnString()
 
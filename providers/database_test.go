@@ -0,0 +1,71 @@
+package providers
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"verifymy-golang-test/models"
+)
+
+type databaseTestSuite struct {
+	suite.Suite
+	db *gorm.DB
+}
+
+func TestDatabaseTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(databaseTestSuite))
+}
+
+func (s *databaseTestSuite) SetupTest() {
+	db, err := gorm.Open(
+		sqlite.Open(filepath.Join(s.T().TempDir(), "db.sqlite3")),
+		&gorm.Config{Logger: logger.Default.LogMode(logger.Silent), TranslateError: true},
+	)
+	s.Require().NoError(err)
+
+	s.db = db
+}
+
+func (s *databaseTestSuite) TestMigrateDeduplicatesUserEmails() {
+	// Users stored before emails were normalized and unique
+	s.Require().NoError(s.db.AutoMigrate(&models.User{}))
+	createdAt := time.Date(2023, 6, 1, 10, 0, 0, 0, time.UTC)
+	users := []models.User{
+		{Name: "Peter Parker", Email: "Peter.Parker@NYork.co", CreatedAt: createdAt},
+		{Name: "Spider-Man", Email: " peter.parker@nyork.co", CreatedAt: createdAt.Add(time.Hour)},
+		{Name: "Miles Morales", Email: "Miles.Morales@nyork.co", CreatedAt: createdAt},
+	}
+	for i := range users {
+		s.Require().NoError(s.db.Create(&users[i]).Error)
+	}
+
+	s.Require().NoError(Migrate(s.db))
+
+	s.True(s.db.Migrator().HasIndex(&models.User{}, userActiveEmailIndex))
+
+	var active []models.User
+	s.Require().NoError(s.db.Order("name").Find(&active).Error)
+	s.Len(active, 2)
+	s.Equal("miles.morales@nyork.co", active[0].Email)
+	s.Equal("peter.parker@nyork.co", active[1].Email)
+	s.Equal(users[0].ID, active[1].ID)
+
+	var duplicate models.User
+	s.Require().NoError(s.db.Unscoped().First(&duplicate, "id", users[1].ID).Error)
+	s.True(duplicate.DeletedAt.Valid)
+	s.Equal("peter.parker@nyork.co", duplicate.Email)
+
+	err := s.db.Create(&models.User{Email: "miles.morales@nyork.co"}).Error
+	s.True(errors.Is(err, gorm.ErrDuplicatedKey))
+
+	// Migrating again leaves the data alone
+	s.Require().NoError(Migrate(s.db))
+}
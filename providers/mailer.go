@@ -0,0 +1,72 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"net/smtp"
+	"os"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+type Email struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+type Mailer interface {
+	Send(ctx context.Context, email Email) error
+}
+
+// NewMailer sends emails through the SMTP server at SMTP_ADDR. When it is
+// not set, emails are only logged, which is enough for local development.
+func NewMailer(log *zap.Logger) Mailer {
+	addr := os.Getenv("SMTP_ADDR")
+	if addr == "" {
+		return &logMailer{log: log}
+	}
+
+	var auth smtp.Auth
+	if username := os.Getenv("SMTP_USERNAME"); username != "" {
+		host := strings.Split(addr, ":")[0]
+		auth = smtp.PlainAuth("", username, os.Getenv("SMTP_PASSWORD"), host)
+	}
+
+	return &smtpMailer{
+		addr: addr,
+		auth: auth,
+		from: os.Getenv("SMTP_FROM"),
+	}
+}
+
+type logMailer struct {
+	log *zap.Logger
+}
+
+func (m *logMailer) Send(ctx context.Context, email Email) error {
+	m.log.Info(
+		"Sending email",
+		zap.String("to", email.To),
+		zap.String("subject", email.Subject),
+		zap.String("body", email.Body),
+	)
+
+	return nil
+}
+
+type smtpMailer struct {
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+func (m *smtpMailer) Send(ctx context.Context, email Email) error {
+	message := fmt.Sprintf(
+		"From: %s\r\nTo: %s\r\nSubject: %s\r\n\r\n%s\r\n",
+		m.from, email.To, email.Subject, email.Body,
+	)
+
+	return smtp.SendMail(m.addr, m.auth, m.from, []string{email.To}, []byte(message))
+}
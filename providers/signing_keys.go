@@ -0,0 +1,162 @@
+package providers
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const generatedSigningKeyBits = 2048
+
+// JSONWebKey is the public part of a signing key, as published in a JWKS
+// (RFC 7517)
+type JSONWebKey struct {
+	KeyType   string `json:"kty"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+	Modulus   string `json:"n"`
+	Exponent  string `json:"e"`
+}
+
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+// SigningKeys signs the tokens third parties check offline, against the
+// public keys published as a JWKS. Unlike the access tokens signed with
+// SECRET_KEY, they don't require sharing any secret.
+type SigningKeys interface {
+	Sign(claims jwt.Claims, tokenType string) (string, error)
+	Parse(
+		token string, tokenType string, claims jwt.Claims, options ...jwt.ParserOption,
+	) (*jwt.Token, error)
+	PublicKeys() JSONWebKeySet
+}
+
+// NewSigningKeys signs with the PEM encoded RSA private key at
+// SIGNING_KEY_FILE. When it is not set, a key is generated on start, which
+// is enough for local development but invalidates every token on restart.
+func NewSigningKeys() (SigningKeys, error) {
+	path := os.Getenv("SIGNING_KEY_FILE")
+	if path == "" {
+		privateKey, err := rsa.GenerateKey(rand.Reader, generatedSigningKeyBits)
+		if err != nil {
+			return nil, err
+		}
+
+		return newSigningKeys(privateKey), nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading SIGNING_KEY_FILE: %w", err)
+	}
+
+	privateKey, err := parseRSAPrivateKey(content)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return newSigningKeys(privateKey), nil
+}
+
+type rsaSigningKeys struct {
+	privateKey *rsa.PrivateKey
+	keyID      string
+}
+
+func newSigningKeys(privateKey *rsa.PrivateKey) SigningKeys {
+	return &rsaSigningKeys{
+		privateKey: privateKey,
+		keyID:      rsaThumbprint(&privateKey.PublicKey),
+	}
+}
+
+// Sign signs claims with RS256, identifying the key in the kid header. The
+// typ header tells tokens of different uses apart, so one can't be passed
+// off as another.
+func (k *rsaSigningKeys) Sign(claims jwt.Claims, tokenType string) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["typ"] = tokenType
+	token.Header["kid"] = k.keyID
+
+	return token.SignedString(k.privateKey)
+}
+
+// Parse only accepts RS256 tokens of tokenType signed with a published key
+func (k *rsaSigningKeys) Parse(
+	token string, tokenType string, claims jwt.Claims, options ...jwt.ParserOption,
+) (*jwt.Token, error) {
+	options = append(options, jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg()}))
+
+	return jwt.ParseWithClaims(token, claims, func(token *jwt.Token) (interface{}, error) {
+		if keyID, _ := token.Header["kid"].(string); keyID != k.keyID {
+			return nil, errors.New("unknown signing key")
+		} else if token.Header["typ"] != tokenType {
+			return nil, errors.New("unexpected token type")
+		}
+
+		return &k.privateKey.PublicKey, nil
+	}, options...)
+}
+
+func (k *rsaSigningKeys) PublicKeys() JSONWebKeySet {
+	publicKey := k.privateKey.PublicKey
+
+	return JSONWebKeySet{Keys: []JSONWebKey{{
+		KeyType:   "RSA",
+		Use:       "sig",
+		Algorithm: jwt.SigningMethodRS256.Alg(),
+		KeyID:     k.keyID,
+		Modulus:   base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes()),
+		Exponent: base64.RawURLEncoding.EncodeToString(
+			big.NewInt(int64(publicKey.E)).Bytes(),
+		),
+	}}}
+}
+
+func parseRSAPrivateKey(content []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(content)
+	if block == nil {
+		return nil, errors.New("no PEM encoded key found")
+	}
+
+	if privateKey, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return privateKey, nil
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	privateKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("key is not an RSA private key")
+	}
+
+	return privateKey, nil
+}
+
+// rsaThumbprint identifies a key by its JWK thumbprint (RFC 7638), so the
+// same key always gets the same kid
+func rsaThumbprint(publicKey *rsa.PublicKey) string {
+	members := fmt.Sprintf(
+		`{"e":"%s","kty":"RSA","n":"%s"}`,
+		base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes()),
+		base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes()),
+	)
+	sum := sha256.Sum256([]byte(members))
+
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,140 @@
+package providers
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/suite"
+)
+
+type signingKeysTestSuite struct {
+	suite.Suite
+	privateKey  *rsa.PrivateKey
+	signingKeys SigningKeys
+}
+
+func TestSigningKeysTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(signingKeysTestSuite))
+}
+
+func (s *signingKeysTestSuite) SetupSuite() {
+	privateKey, err := rsa.GenerateKey(rand.Reader, generatedSigningKeyBits)
+	s.Require().NoError(err)
+
+	s.privateKey = privateKey
+	s.signingKeys = newSigningKeys(privateKey)
+}
+
+func (s *signingKeysTestSuite) TestSignAndParse() {
+	token, err := s.signingKeys.Sign(jwt.MapClaims{
+		"aud": "shop.example.com",
+		"exp": time.Now().Add(time.Minute).Unix(),
+	}, "age+jwt")
+	s.Require().NoError(err)
+
+	otherKeys := newSigningKeys(s.otherPrivateKey())
+	otherToken, err := otherKeys.Sign(jwt.MapClaims{"aud": "shop.example.com"}, "age+jwt")
+	s.Require().NoError(err)
+
+	hmacToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"aud": "shop.example.com",
+	}).SignedString([]byte("secret"))
+	s.Require().NoError(err)
+
+	tests := []struct {
+		description string
+		token       string
+		tokenType   string
+		expectedOk  bool
+	}{
+		{description: "Valid", token: token, tokenType: "age+jwt", expectedOk: true},
+		{description: "Other token type", token: token, tokenType: "JWT"},
+		{description: "Unknown key", token: otherToken, tokenType: "age+jwt"},
+		{description: "Other algorithm", token: hmacToken, tokenType: "JWT"},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			claims := jwt.MapClaims{}
+			_, err := s.signingKeys.Parse(
+				test.token, test.tokenType, claims, jwt.WithAudience("shop.example.com"),
+			)
+
+			s.Equal(test.expectedOk, err == nil)
+			if test.expectedOk {
+				s.Equal("shop.example.com", claims["aud"])
+			}
+		})
+	}
+}
+
+func (s *signingKeysTestSuite) TestPublicKeys() {
+	keys := s.signingKeys.PublicKeys().Keys
+
+	s.Require().Len(keys, 1)
+	s.Equal("RSA", keys[0].KeyType)
+	s.Equal("sig", keys[0].Use)
+	s.Equal("RS256", keys[0].Algorithm)
+	s.Equal(rsaThumbprint(&s.privateKey.PublicKey), keys[0].KeyID)
+
+	modulus, err := base64.RawURLEncoding.DecodeString(keys[0].Modulus)
+	s.Require().NoError(err)
+	exponent, err := base64.RawURLEncoding.DecodeString(keys[0].Exponent)
+	s.Require().NoError(err)
+	s.Equal(s.privateKey.N, new(big.Int).SetBytes(modulus))
+	s.Equal(int64(s.privateKey.E), new(big.Int).SetBytes(exponent).Int64())
+}
+
+func (s *signingKeysTestSuite) TestParseRSAPrivateKey() {
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(s.privateKey)
+	s.Require().NoError(err)
+
+	tests := []struct {
+		description   string
+		content       []byte
+		expectedError error
+	}{
+		{
+			description: "PKCS #1",
+			content: pem.EncodeToMemory(&pem.Block{
+				Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(s.privateKey),
+			}),
+		},
+		{
+			description: "PKCS #8",
+			content:     pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8}),
+		},
+		{
+			description:   "Not PEM",
+			content:       []byte("secret"),
+			expectedError: errors.New("no PEM encoded key found"),
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			privateKey, err := parseRSAPrivateKey(test.content)
+
+			s.Equal(test.expectedError, err)
+			if test.expectedError == nil {
+				s.True(s.privateKey.Equal(privateKey))
+			}
+		})
+	}
+}
+
+func (s *signingKeysTestSuite) otherPrivateKey() *rsa.PrivateKey {
+	privateKey, err := rsa.GenerateKey(rand.Reader, generatedSigningKeyBits)
+	s.Require().NoError(err)
+
+	return privateKey
+}
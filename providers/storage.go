@@ -0,0 +1,56 @@
+package providers
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// Storage keeps files the application generates, like data exports
+type Storage interface {
+	Write(ctx context.Context, name string, content []byte) error
+	Open(ctx context.Context, name string) (io.ReadCloser, error)
+	Delete(ctx context.Context, name string) error
+}
+
+// NewStorage keeps files on the local disk, under STORAGE_DIR or storage by
+// default.
+func NewStorage() Storage {
+	dir := os.Getenv("STORAGE_DIR")
+	if dir == "" {
+		dir = "storage"
+	}
+
+	return &localStorage{dir: dir}
+}
+
+type localStorage struct {
+	dir string
+}
+
+func (s *localStorage) path(name string) string {
+	return filepath.Join(s.dir, filepath.Clean("/"+name))
+}
+
+func (s *localStorage) Write(ctx context.Context, name string, content []byte) error {
+	path := s.path(name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, content, 0o640)
+}
+
+func (s *localStorage) Open(ctx context.Context, name string) (io.ReadCloser, error) {
+	return os.Open(s.path(name))
+}
+
+// Delete doesn't fail when the file doesn't exist
+func (s *localStorage) Delete(ctx context.Context, name string) error {
+	if err := os.Remove(s.path(name)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
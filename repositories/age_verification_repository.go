@@ -0,0 +1,76 @@
+package repositories
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"verifymy-golang-test/models"
+)
+
+type AgeVerificationRepository interface {
+	FindByUserId(ctx context.Context, userId string) (*models.AgeVerification, error)
+	FindByUserIds(ctx context.Context, userIds []string) ([]models.AgeVerification, error)
+	Save(
+		ctx context.Context, verification models.AgeVerification,
+	) (*models.AgeVerification, error)
+	DeleteByUserId(ctx context.Context, userId string) error
+}
+
+func NewAgeVerificationRepository(db *gorm.DB) AgeVerificationRepository {
+	return &ageVerificationRepository{
+		db: db,
+	}
+}
+
+type ageVerificationRepository struct {
+	db *gorm.DB
+}
+
+func (repo *ageVerificationRepository) FindByUserId(
+	ctx context.Context, userId string,
+) (*models.AgeVerification, error) {
+	var verification models.AgeVerification
+	err := conn(ctx, repo.db).Where("user_id", userId).First(&verification).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &verification, nil
+}
+
+func (repo *ageVerificationRepository) FindByUserIds(
+	ctx context.Context, userIds []string,
+) ([]models.AgeVerification, error) {
+	var verifications []models.AgeVerification
+	err := conn(ctx, repo.db).Where("user_id IN ?", userIds).Find(&verifications).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return verifications, nil
+}
+
+// Save creates the verification of a user when it has no ID yet and
+// replaces it otherwise
+func (repo *ageVerificationRepository) Save(
+	ctx context.Context, verification models.AgeVerification,
+) (*models.AgeVerification, error) {
+	if err := conn(ctx, repo.db).Save(&verification).Error; err != nil {
+		return nil, err
+	}
+
+	return &verification, nil
+}
+
+func (repo *ageVerificationRepository) DeleteByUserId(
+	ctx context.Context, userId string,
+) error {
+	return conn(ctx, repo.db).
+		Where("user_id", userId).
+		Delete(&models.AgeVerification{}).Error
+}
@@ -0,0 +1,109 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"verifymy-golang-test/models"
+	"verifymy-golang-test/providers"
+)
+
+type ageVerificationRepositoryTestSuite struct {
+	suite.Suite
+	ctx                       context.Context
+	ageVerificationRepository AgeVerificationRepository
+}
+
+func TestAgeVerificationRepositoryTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(ageVerificationRepositoryTestSuite))
+}
+
+func (s *ageVerificationRepositoryTestSuite) SetupTest() {
+	s.ctx = context.Background()
+
+	dbconn, err := gorm.Open(
+		sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", uuid.NewString())),
+		&gorm.Config{Logger: logger.Default.LogMode(logger.Silent), TranslateError: true},
+	)
+	s.Require().NoError(err)
+	s.Require().NoError(providers.Migrate(dbconn))
+
+	s.ageVerificationRepository = NewAgeVerificationRepository(dbconn)
+}
+
+func (s *ageVerificationRepositoryTestSuite) TestFindByUserIdWithoutVerification() {
+	verification, err := s.ageVerificationRepository.FindByUserId(s.ctx, uuid.NewString())
+	s.NoError(err)
+	s.Nil(verification)
+}
+
+func (s *ageVerificationRepositoryTestSuite) TestFindByUserIds() {
+	verified, err := s.ageVerificationRepository.Save(s.ctx, models.AgeVerification{
+		UserID: uuid.NewString(),
+		Status: models.AgeVerificationStatusVerified,
+	})
+	s.Require().NoError(err)
+	_, err = s.ageVerificationRepository.Save(s.ctx, models.AgeVerification{
+		UserID: uuid.NewString(),
+		Status: models.AgeVerificationStatusFailed,
+	})
+	s.Require().NoError(err)
+
+	verifications, err := s.ageVerificationRepository.FindByUserIds(
+		s.ctx, []string{verified.UserID, uuid.NewString()},
+	)
+	s.NoError(err)
+	s.Len(verifications, 1)
+	s.Equal(verified.ID, verifications[0].ID)
+}
+
+func (s *ageVerificationRepositoryTestSuite) TestSave() {
+	userId := uuid.NewString()
+
+	created, err := s.ageVerificationRepository.Save(s.ctx, models.AgeVerification{
+		UserID: userId,
+		Status: models.AgeVerificationStatusPending,
+		Method: "document",
+	})
+	s.Require().NoError(err)
+	s.NotEqual(uuid.Nil, created.ID)
+
+	verifiedAt := time.Now().UTC().Truncate(time.Second)
+	created.Status = models.AgeVerificationStatusVerified
+	created.AssuranceLevel = models.AssuranceLevelHigh
+	created.AgeOver = 18
+	created.VerifiedAt = sql.NullTime{Time: verifiedAt, Valid: true}
+	_, err = s.ageVerificationRepository.Save(s.ctx, *created)
+	s.Require().NoError(err)
+
+	found, err := s.ageVerificationRepository.FindByUserId(s.ctx, userId)
+	s.NoError(err)
+	s.Equal(created.ID, found.ID)
+	s.Equal(models.AgeVerificationStatusVerified, found.Status)
+	s.Equal(models.AssuranceLevelHigh, found.AssuranceLevel)
+	s.Equal(18, found.AgeOver)
+	s.True(found.VerifiedAt.Time.Equal(verifiedAt))
+	s.False(found.ExpiresAt.Valid)
+}
+
+func (s *ageVerificationRepositoryTestSuite) TestDeleteByUserId() {
+	userId := uuid.NewString()
+	_, err := s.ageVerificationRepository.Save(s.ctx, models.AgeVerification{UserID: userId})
+	s.Require().NoError(err)
+
+	s.NoError(s.ageVerificationRepository.DeleteByUserId(s.ctx, userId))
+
+	found, err := s.ageVerificationRepository.FindByUserId(s.ctx, userId)
+	s.NoError(err)
+	s.Nil(found)
+}
@@ -0,0 +1,142 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"verifymy-golang-test/models"
+)
+
+type AgeVerificationSessionRepository interface {
+	Create(
+		ctx context.Context, session models.AgeVerificationSession,
+	) (*models.AgeVerificationSession, error)
+	FindById(ctx context.Context, id string) (*models.AgeVerificationSession, error)
+	FindByReference(
+		ctx context.Context, provider string, reference string,
+	) (*models.AgeVerificationSession, error)
+	FindExpired(
+		ctx context.Context, now time.Time, limit int,
+	) ([]models.AgeVerificationSession, error)
+	CountByClientIdSince(ctx context.Context, clientId string, since time.Time) (int64, error)
+	UpdateColumnsById(
+		ctx context.Context, id string, columns map[string]interface{}, expectedStatus string,
+	) (bool, error)
+	DeleteByUserId(ctx context.Context, userId string) error
+}
+
+func NewAgeVerificationSessionRepository(db *gorm.DB) AgeVerificationSessionRepository {
+	return &ageVerificationSessionRepository{
+		db: db,
+	}
+}
+
+type ageVerificationSessionRepository struct {
+	db *gorm.DB
+}
+
+func (repo *ageVerificationSessionRepository) Create(
+	ctx context.Context, session models.AgeVerificationSession,
+) (*models.AgeVerificationSession, error) {
+	if err := conn(ctx, repo.db).Create(&session).Error; err != nil {
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+func (repo *ageVerificationSessionRepository) FindById(
+	ctx context.Context, id string,
+) (*models.AgeVerificationSession, error) {
+	var session models.AgeVerificationSession
+	err := conn(ctx, repo.db).Where("id", id).First(&session).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+func (repo *ageVerificationSessionRepository) FindByReference(
+	ctx context.Context, provider string, reference string,
+) (*models.AgeVerificationSession, error) {
+	var session models.AgeVerificationSession
+	err := conn(ctx, repo.db).
+		Where("provider", provider).
+		Where("reference", reference).
+		First(&session).
+		Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+// FindExpired returns the oldest sessions still pending past their expiry
+func (repo *ageVerificationSessionRepository) FindExpired(
+	ctx context.Context, now time.Time, limit int,
+) ([]models.AgeVerificationSession, error) {
+	var sessions []models.AgeVerificationSession
+	err := conn(ctx, repo.db).
+		Where("status", models.AgeVerificationSessionStatusPending).
+		Where("expires_at <= ?", now).
+		Order("expires_at").
+		Limit(limit).
+		Find(&sessions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+// CountByClientIdSince counts the sessions started for a client since then
+func (repo *ageVerificationSessionRepository) CountByClientIdSince(
+	ctx context.Context, clientId string, since time.Time,
+) (int64, error) {
+	var count int64
+	err := conn(ctx, repo.db).
+		Model(&models.AgeVerificationSession{}).
+		Where("client_id", clientId).
+		Where("created_at >= ?", since).
+		Count(&count).Error
+
+	return count, err
+}
+
+// UpdateColumnsById only updates the session while it is still in
+// expectedStatus, so two updates racing to move it from the same status
+// can't both win. It reports whether the session was updated.
+func (repo *ageVerificationSessionRepository) UpdateColumnsById(
+	ctx context.Context, id string, columns map[string]interface{}, expectedStatus string,
+) (bool, error) {
+	result := conn(ctx, repo.db).
+		Model(&models.AgeVerificationSession{}).
+		Where("id", id).
+		Where("status", expectedStatus).
+		Updates(columns)
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+func (repo *ageVerificationSessionRepository) DeleteByUserId(
+	ctx context.Context, userId string,
+) error {
+	return conn(ctx, repo.db).
+		Where("user_id", userId).
+		Delete(&models.AgeVerificationSession{}).Error
+}
@@ -0,0 +1,180 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"verifymy-golang-test/models"
+	"verifymy-golang-test/providers"
+)
+
+type ageVerificationSessionRepositoryTestSuite struct {
+	suite.Suite
+	ctx                              context.Context
+	now                              time.Time
+	ageVerificationSessionRepository AgeVerificationSessionRepository
+}
+
+func TestAgeVerificationSessionRepositoryTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(ageVerificationSessionRepositoryTestSuite))
+}
+
+func (s *ageVerificationSessionRepositoryTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.now = time.Date(2023, 6, 30, 12, 0, 0, 0, time.UTC)
+
+	dbconn, err := gorm.Open(
+		sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", uuid.NewString())),
+		&gorm.Config{Logger: logger.Default.LogMode(logger.Silent), TranslateError: true},
+	)
+	s.Require().NoError(err)
+	s.Require().NoError(providers.Migrate(dbconn))
+
+	s.ageVerificationSessionRepository = NewAgeVerificationSessionRepository(dbconn)
+}
+
+func (s *ageVerificationSessionRepositoryTestSuite) create(
+	userId string, reference string, expiresAt time.Time,
+) *models.AgeVerificationSession {
+	session, err := s.ageVerificationSessionRepository.Create(s.ctx, models.AgeVerificationSession{
+		UserID:    userId,
+		Provider:  "simulator",
+		Reference: reference,
+		Method:    "document",
+		AgeOver:   18,
+		ExpiresAt: expiresAt,
+	})
+	s.Require().NoError(err)
+
+	return session
+}
+
+func (s *ageVerificationSessionRepositoryTestSuite) TestFindById() {
+	session := s.create(uuid.NewString(), "sim_1", s.now)
+
+	found, err := s.ageVerificationSessionRepository.FindById(s.ctx, session.ID.String())
+	s.NoError(err)
+	s.Equal(models.AgeVerificationSessionStatusPending, found.Status)
+	s.Equal("sim_1", found.Reference)
+
+	found, err = s.ageVerificationSessionRepository.FindById(s.ctx, uuid.NewString())
+	s.NoError(err)
+	s.Nil(found)
+}
+
+func (s *ageVerificationSessionRepositoryTestSuite) TestFindByReference() {
+	session := s.create(uuid.NewString(), "sim_1", s.now)
+
+	found, err := s.ageVerificationSessionRepository.FindByReference(s.ctx, "simulator", "sim_1")
+	s.NoError(err)
+	s.Equal(session.ID, found.ID)
+
+	found, err = s.ageVerificationSessionRepository.FindByReference(s.ctx, "mobile", "sim_1")
+	s.NoError(err)
+	s.Nil(found)
+}
+
+func (s *ageVerificationSessionRepositoryTestSuite) TestFindExpired() {
+	userId := uuid.NewString()
+	later := s.create(userId, "sim_1", s.now.Add(-time.Minute))
+	earlier := s.create(userId, "sim_2", s.now.Add(-time.Hour))
+	s.create(userId, "sim_3", s.now.Add(time.Minute))
+	completed := s.create(userId, "sim_4", s.now.Add(-time.Hour))
+	_, err := s.ageVerificationSessionRepository.UpdateColumnsById(
+		s.ctx,
+		completed.ID.String(),
+		map[string]interface{}{"status": models.AgeVerificationSessionStatusVerified},
+		models.AgeVerificationSessionStatusPending,
+	)
+	s.Require().NoError(err)
+
+	sessions, err := s.ageVerificationSessionRepository.FindExpired(s.ctx, s.now, 10)
+	s.NoError(err)
+	s.Len(sessions, 2)
+	s.Equal(earlier.ID, sessions[0].ID)
+	s.Equal(later.ID, sessions[1].ID)
+}
+
+func (s *ageVerificationSessionRepositoryTestSuite) TestCountByClientIdSince() {
+	clientId := uuid.NewString()
+	for i, createdAt := range []time.Time{
+		s.now.Add(-time.Minute), s.now.Add(-time.Minute), s.now.Add(-2 * time.Hour),
+	} {
+		_, err := s.ageVerificationSessionRepository.Create(s.ctx, models.AgeVerificationSession{
+			UserID:    uuid.NewString(),
+			ClientID:  clientId,
+			Provider:  "simulator",
+			Reference: fmt.Sprintf("sim_%d", i),
+			ExpiresAt: s.now,
+			CreatedAt: createdAt,
+		})
+		s.Require().NoError(err)
+	}
+	s.create(uuid.NewString(), "sim_other", s.now)
+
+	count, err := s.ageVerificationSessionRepository.CountByClientIdSince(
+		s.ctx, clientId, s.now.Add(-time.Hour),
+	)
+	s.NoError(err)
+	s.Equal(int64(2), count)
+
+	count, err = s.ageVerificationSessionRepository.CountByClientIdSince(
+		s.ctx, uuid.NewString(), s.now.Add(-time.Hour),
+	)
+	s.NoError(err)
+	s.Zero(count)
+}
+
+func (s *ageVerificationSessionRepositoryTestSuite) TestUpdateColumnsById() {
+	session := s.create(uuid.NewString(), "sim_1", s.now)
+	columns := map[string]interface{}{
+		"status":          models.AgeVerificationSessionStatusVerified,
+		"assurance_level": models.AssuranceLevelHigh,
+	}
+
+	updated, err := s.ageVerificationSessionRepository.UpdateColumnsById(
+		s.ctx, session.ID.String(), columns, models.AgeVerificationSessionStatusPending,
+	)
+	s.NoError(err)
+	s.True(updated)
+
+	// The session is not pending anymore, so it can't be moved from it again
+	updated, err = s.ageVerificationSessionRepository.UpdateColumnsById(
+		s.ctx,
+		session.ID.String(),
+		map[string]interface{}{"status": models.AgeVerificationSessionStatusFailed},
+		models.AgeVerificationSessionStatusPending,
+	)
+	s.NoError(err)
+	s.False(updated)
+
+	found, err := s.ageVerificationSessionRepository.FindById(s.ctx, session.ID.String())
+	s.NoError(err)
+	s.Equal(models.AgeVerificationSessionStatusVerified, found.Status)
+	s.Equal(models.AssuranceLevelHigh, found.AssuranceLevel)
+}
+
+func (s *ageVerificationSessionRepositoryTestSuite) TestDeleteByUserId() {
+	userId := uuid.NewString()
+	session := s.create(userId, "sim_1", s.now)
+	other := s.create(uuid.NewString(), "sim_2", s.now)
+
+	s.NoError(s.ageVerificationSessionRepository.DeleteByUserId(s.ctx, userId))
+
+	found, err := s.ageVerificationSessionRepository.FindById(s.ctx, session.ID.String())
+	s.NoError(err)
+	s.Nil(found)
+
+	found, err = s.ageVerificationSessionRepository.FindById(s.ctx, other.ID.String())
+	s.NoError(err)
+	s.NotNil(found)
+}
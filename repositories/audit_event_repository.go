@@ -0,0 +1,133 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"verifymy-golang-test/entities"
+	"verifymy-golang-test/models"
+)
+
+// AuditEventRepository is append-only, audit events can't be changed once
+// recorded
+type AuditEventRepository interface {
+	Create(ctx context.Context, event models.AuditEvent) error
+	FindByUserId(ctx context.Context, userId string) ([]models.AuditEvent, error)
+	FindAll(ctx context.Context, query entities.AuditEventQuery) ([]models.AuditEvent, error)
+	Count(ctx context.Context, filters entities.AuditEventFilters) (int64, error)
+}
+
+func NewAuditEventRepository(db *gorm.DB) AuditEventRepository {
+	return &auditEventRepository{
+		db: db,
+	}
+}
+
+type auditEventRepository struct {
+	db *gorm.DB
+}
+
+// Create joins the transaction of the context it is given, see Transactor,
+// so events are recorded along with the write they describe
+func (repo *auditEventRepository) Create(
+	ctx context.Context, event models.AuditEvent,
+) error {
+	return conn(ctx, repo.db).Create(&event).Error
+}
+
+// FindByUserId returns the events the user carried out or was the target of,
+// oldest first
+func (repo *auditEventRepository) FindByUserId(
+	ctx context.Context, userId string,
+) ([]models.AuditEvent, error) {
+	var events []models.AuditEvent
+	err := repo.db.WithContext(ctx).
+		Where("actor_id = ? OR (target_type = ? AND target_id = ?)", userId, "user", userId).
+		Order("created_at").
+		Find(&events).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// FindAll returns the events matching the query, newest first
+func (repo *auditEventRepository) FindAll(
+	ctx context.Context, query entities.AuditEventQuery,
+) ([]models.AuditEvent, error) {
+	db := repo.filtered(ctx, query.Filters)
+	if cursor := query.Page.Cursor; cursor != nil {
+		createdAt, err := time.Parse(time.RFC3339Nano, cursor.Value)
+		if err != nil {
+			return nil, entities.NewInvalidParameterError("cursor", "malformed cursor")
+		}
+
+		db = db.Where(
+			"(created_at < ? OR (created_at = ? AND id < ?))",
+			createdAt, createdAt, cursor.ID,
+		)
+	}
+
+	var events []models.AuditEvent
+	err := db.
+		Order("created_at DESC").
+		Order("id DESC").
+		Limit(query.Page.Limit).
+		Find(&events).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+func (repo *auditEventRepository) Count(
+	ctx context.Context, filters entities.AuditEventFilters,
+) (int64, error) {
+	var totalResults int64
+	err := repo.filtered(ctx, filters).
+		Model(&models.AuditEvent{}).
+		Count(&totalResults).
+		Error
+	if err != nil {
+		return 0, err
+	}
+
+	return totalResults, nil
+}
+
+func (repo *auditEventRepository) filtered(
+	ctx context.Context, filters entities.AuditEventFilters,
+) *gorm.DB {
+	db := repo.db.WithContext(ctx)
+
+	columns := []struct {
+		name  string
+		value string
+	}{
+		{"actor_id", filters.ActorID},
+		{"action", filters.Action},
+		{"target_type", filters.TargetType},
+		{"target_id", filters.TargetID},
+		{"ip", filters.IP},
+		{"request_id", filters.RequestID},
+	}
+	for _, column := range columns {
+		if column.value != "" {
+			db = db.Where(column.name, column.value)
+		}
+	}
+
+	if filters.CreatedFrom != nil {
+		db = db.Where("created_at >= ?", *filters.CreatedFrom)
+	}
+	if filters.CreatedTo != nil {
+		db = db.Where("created_at <= ?", *filters.CreatedTo)
+	}
+
+	return db
+}
@@ -0,0 +1,154 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"verifymy-golang-test/entities"
+	"verifymy-golang-test/models"
+	"verifymy-golang-test/providers"
+)
+
+// auditEventRepositoryQueryTestSuite runs against an in-memory SQLite
+// database, to check which events the filters and cursors select.
+type auditEventRepositoryQueryTestSuite struct {
+	suite.Suite
+	ctx                  context.Context
+	db                   *gorm.DB
+	auditEventRepository AuditEventRepository
+	now                  time.Time
+}
+
+func TestAuditEventRepositoryQueryTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(auditEventRepositoryQueryTestSuite))
+}
+
+func (s *auditEventRepositoryQueryTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.now = time.Date(2023, 6, 30, 12, 0, 0, 0, time.UTC)
+
+	dbconn, err := gorm.Open(
+		sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", uuid.NewString())),
+		&gorm.Config{Logger: logger.Default.LogMode(logger.Silent), TranslateError: true},
+	)
+	s.Require().NoError(err)
+	s.Require().NoError(providers.Migrate(dbconn))
+
+	s.db = dbconn
+	s.auditEventRepository = NewAuditEventRepository(dbconn)
+
+	events := []models.AuditEvent{
+		{ActorID: "admin", Action: "user.updated", TargetType: "user", TargetID: "peter", RequestID: "r1"},
+		{ActorID: "peter", Action: "user.signed_in", TargetType: "user", TargetID: "peter", IP: "10.0.0.1"},
+		{ActorID: "admin", Action: "user.viewed", TargetType: "user", TargetID: "mary"},
+		{ActorID: "mary", Action: "user.signed_in", TargetType: "user", TargetID: "mary", IP: "10.0.0.2"},
+	}
+	for i, event := range events {
+		event.CreatedAt = s.now.Add(time.Duration(i) * time.Minute)
+		s.Require().NoError(s.auditEventRepository.Create(s.ctx, event))
+	}
+}
+
+func (s *auditEventRepositoryQueryTestSuite) actions(events []models.AuditEvent) []string {
+	actions := make([]string, 0, len(events))
+	for _, event := range events {
+		actions = append(actions, event.ActorID+" "+event.Action)
+	}
+
+	return actions
+}
+
+func (s *auditEventRepositoryQueryTestSuite) TestFindAll() {
+	createdFrom := s.now.Add(time.Minute)
+	createdTo := s.now.Add(2 * time.Minute)
+
+	tests := []struct {
+		description     string
+		filters         entities.AuditEventFilters
+		expectedActions []string
+	}{
+		{
+			description: "Newest first",
+			expectedActions: []string{
+				"mary user.signed_in", "admin user.viewed", "peter user.signed_in", "admin user.updated",
+			},
+		},
+		{
+			description:     "By actor",
+			filters:         entities.AuditEventFilters{ActorID: "admin"},
+			expectedActions: []string{"admin user.viewed", "admin user.updated"},
+		},
+		{
+			description:     "By action and target",
+			filters:         entities.AuditEventFilters{Action: "user.signed_in", TargetID: "peter"},
+			expectedActions: []string{"peter user.signed_in"},
+		},
+		{
+			description:     "By IP",
+			filters:         entities.AuditEventFilters{IP: "10.0.0.2"},
+			expectedActions: []string{"mary user.signed_in"},
+		},
+		{
+			description:     "By request",
+			filters:         entities.AuditEventFilters{RequestID: "r1"},
+			expectedActions: []string{"admin user.updated"},
+		},
+		{
+			description:     "By creation time",
+			filters:         entities.AuditEventFilters{CreatedFrom: &createdFrom, CreatedTo: &createdTo},
+			expectedActions: []string{"admin user.viewed", "peter user.signed_in"},
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			query := entities.AuditEventQuery{
+				Filters: test.filters,
+				Page:    entities.PageRequest{Limit: 10},
+			}
+
+			events, err := s.auditEventRepository.FindAll(s.ctx, query)
+			s.NoError(err)
+			s.Equal(test.expectedActions, s.actions(events))
+
+			total, err := s.auditEventRepository.Count(s.ctx, test.filters)
+			s.NoError(err)
+			s.Equal(int64(len(test.expectedActions)), total)
+		})
+	}
+}
+
+func (s *auditEventRepositoryQueryTestSuite) TestFindAllWithCursor() {
+	query := entities.AuditEventQuery{Page: entities.PageRequest{Limit: 3}}
+
+	events, err := s.auditEventRepository.FindAll(s.ctx, query)
+	s.Require().NoError(err)
+	s.Len(events, 3)
+
+	query.Page.Cursor = query.CursorFor(events[2])
+	events, err = s.auditEventRepository.FindAll(s.ctx, query)
+	s.NoError(err)
+	s.Equal([]string{"admin user.updated"}, s.actions(events))
+
+	query.Page.Cursor = &entities.Cursor{ID: "id", Value: "yesterday"}
+	_, err = s.auditEventRepository.FindAll(s.ctx, query)
+	s.Equal(entities.NewInvalidParameterError("cursor", "malformed cursor"), err)
+}
+
+func (s *auditEventRepositoryQueryTestSuite) TestAppendOnly() {
+	var event models.AuditEvent
+	s.Require().NoError(s.db.First(&event).Error)
+
+	event.Action = "user.deleted"
+	s.ErrorIs(s.db.Save(&event).Error, models.ErrAuditEventsAppendOnly)
+	s.ErrorIs(s.db.Delete(&event).Error, models.ErrAuditEventsAppendOnly)
+}
@@ -0,0 +1,111 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+
+	"verifymy-golang-test/models"
+)
+
+type auditEventRepositoryTestSuite struct {
+	suite.Suite
+	ctx                  context.Context
+	dbmock               sqlmock.Sqlmock
+	auditEventRepository AuditEventRepository
+}
+
+func TestAuditEventRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(auditEventRepositoryTestSuite))
+}
+
+func (s *auditEventRepositoryTestSuite) SetupTest() {
+	s.ctx = context.Background()
+
+	conn, dbmock, _ := sqlmock.New()
+	dialector := mysql.Dialector{
+		Config: &mysql.Config{
+			DSN:                       "sqlmock_db_0",
+			Conn:                      conn,
+			SkipInitializeWithVersion: true,
+		},
+	}
+
+	dbconn, err := gorm.Open(dialector, &gorm.Config{})
+	if err != nil {
+		s.FailNow(err.Error())
+	}
+
+	s.dbmock = dbmock
+	s.auditEventRepository = NewAuditEventRepository(dbconn)
+}
+
+func (s *auditEventRepositoryTestSuite) TestCreate() {
+	tests := []struct {
+		description  string
+		errorInQuery error
+	}{
+		{
+			description: "Success",
+		},
+		{
+			description:  "Error in query",
+			errorInQuery: errors.New("error executing query"),
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			s.SetupTest()
+
+			s.dbmock.ExpectBegin()
+			expectedQuery := s.dbmock.ExpectExec(
+				regexp.QuoteMeta("INSERT INTO `audit_events`"),
+			).WithArgs(
+				sqlmock.AnyArg(),
+				"actor-id",
+				"user.updated",
+				"user",
+				"target-id",
+				"127.0.0.1",
+				"curl/8.0",
+				"request-id",
+				`{"name":{"from":"Peter","to":"Peter Parker"}}`,
+				nil,
+				sqlmock.AnyArg(),
+			)
+			if test.errorInQuery != nil {
+				expectedQuery.WillReturnError(test.errorInQuery)
+				s.dbmock.ExpectRollback()
+			} else {
+				expectedQuery.WillReturnResult(sqlmock.NewResult(1, 1))
+				s.dbmock.ExpectCommit()
+			}
+
+			err := s.auditEventRepository.Create(s.ctx, models.AuditEvent{
+				ActorID:    "actor-id",
+				Action:     "user.updated",
+				TargetType: "user",
+				TargetID:   "target-id",
+				IP:         "127.0.0.1",
+				UserAgent:  "curl/8.0",
+				RequestID:  "request-id",
+				Diff: models.JSONMap{
+					"name": map[string]interface{}{"from": "Peter", "to": "Peter Parker"},
+				},
+			})
+			if test.errorInQuery != nil {
+				s.ErrorContains(err, test.errorInQuery.Error())
+			} else {
+				s.NoError(err)
+			}
+			s.NoError(s.dbmock.ExpectationsWereMet())
+		})
+	}
+}
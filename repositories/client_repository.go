@@ -0,0 +1,79 @@
+package repositories
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"verifymy-golang-test/models"
+)
+
+type ClientRepository interface {
+	Create(ctx context.Context, client models.Client) (*models.Client, error)
+	FindAll(ctx context.Context) ([]models.Client, error)
+	FindById(ctx context.Context, id string) (*models.Client, error)
+	Update(ctx context.Context, client models.Client) error
+	DeleteById(ctx context.Context, id string) (bool, error)
+}
+
+func NewClientRepository(db *gorm.DB) ClientRepository {
+	return &clientRepository{
+		db: db,
+	}
+}
+
+type clientRepository struct {
+	db *gorm.DB
+}
+
+func (repo *clientRepository) Create(
+	ctx context.Context, client models.Client,
+) (*models.Client, error) {
+	if err := conn(ctx, repo.db).Create(&client).Error; err != nil {
+		return nil, err
+	}
+
+	return &client, nil
+}
+
+func (repo *clientRepository) FindAll(
+	ctx context.Context,
+) ([]models.Client, error) {
+	var clients []models.Client
+	err := conn(ctx, repo.db).Order("created_at, id").Find(&clients).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return clients, nil
+}
+
+func (repo *clientRepository) FindById(
+	ctx context.Context, id string,
+) (*models.Client, error) {
+	var client models.Client
+	err := conn(ctx, repo.db).Where("id", id).First(&client).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &client, nil
+}
+
+func (repo *clientRepository) Update(
+	ctx context.Context, client models.Client,
+) error {
+	return conn(ctx, repo.db).Save(&client).Error
+}
+
+func (repo *clientRepository) DeleteById(
+	ctx context.Context, id string,
+) (bool, error) {
+	result := conn(ctx, repo.db).Where("id", id).Delete(&models.Client{})
+
+	return result.RowsAffected > 0, result.Error
+}
@@ -0,0 +1,103 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"verifymy-golang-test/models"
+	"verifymy-golang-test/providers"
+)
+
+type clientRepositoryTestSuite struct {
+	suite.Suite
+	ctx              context.Context
+	clientRepository ClientRepository
+}
+
+func TestClientRepositoryTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(clientRepositoryTestSuite))
+}
+
+func (s *clientRepositoryTestSuite) SetupTest() {
+	s.ctx = context.Background()
+
+	dbconn, err := gorm.Open(
+		sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", uuid.NewString())),
+		&gorm.Config{Logger: logger.Default.LogMode(logger.Silent), TranslateError: true},
+	)
+	s.Require().NoError(err)
+	s.Require().NoError(providers.Migrate(dbconn))
+
+	s.clientRepository = NewClientRepository(dbconn)
+}
+
+func (s *clientRepositoryTestSuite) create(name string) *models.Client {
+	client, err := s.clientRepository.Create(s.ctx, models.Client{
+		Name:           name,
+		RedirectURIs:   models.StringList{"https://shop.example.com/verified"},
+		AllowedOrigins: models.StringList{"https://shop.example.com"},
+		Scopes:         models.StringList{models.ClientScopeAgeVerification},
+		SecretHash:     "hashed-secret",
+	})
+	s.Require().NoError(err)
+
+	return client
+}
+
+func (s *clientRepositoryTestSuite) TestFindById() {
+	client := s.create("Shop")
+
+	found, err := s.clientRepository.FindById(s.ctx, client.ID.String())
+	s.NoError(err)
+	s.Equal("Shop", found.Name)
+	s.Equal(models.StringList{"https://shop.example.com/verified"}, found.RedirectURIs)
+	s.Equal(models.StringList{"https://shop.example.com"}, found.AllowedOrigins)
+	s.Equal(models.StringList{"age_verification"}, found.Scopes)
+	s.Equal(models.SecretValue("hashed-secret"), found.SecretHash)
+
+	found, err = s.clientRepository.FindById(s.ctx, uuid.NewString())
+	s.NoError(err)
+	s.Nil(found)
+}
+
+func (s *clientRepositoryTestSuite) TestFindAll() {
+	s.create("Shop")
+	s.create("Forum")
+
+	clients, err := s.clientRepository.FindAll(s.ctx)
+	s.NoError(err)
+	s.Len(clients, 2)
+}
+
+func (s *clientRepositoryTestSuite) TestUpdate() {
+	client := s.create("Shop")
+	client.Name = "Shop & Co"
+	client.SecretHash = "another-hashed-secret"
+
+	s.NoError(s.clientRepository.Update(s.ctx, *client))
+
+	found, err := s.clientRepository.FindById(s.ctx, client.ID.String())
+	s.NoError(err)
+	s.Equal("Shop & Co", found.Name)
+	s.Equal(models.SecretValue("another-hashed-secret"), found.SecretHash)
+}
+
+func (s *clientRepositoryTestSuite) TestDeleteById() {
+	client := s.create("Shop")
+
+	deleted, err := s.clientRepository.DeleteById(s.ctx, client.ID.String())
+	s.NoError(err)
+	s.True(deleted)
+
+	deleted, err = s.clientRepository.DeleteById(s.ctx, client.ID.String())
+	s.NoError(err)
+	s.False(deleted)
+}
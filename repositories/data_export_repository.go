@@ -0,0 +1,121 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"verifymy-golang-test/models"
+)
+
+type DataExportRepository interface {
+	Create(ctx context.Context, export models.DataExport) (*models.DataExport, error)
+	FindById(ctx context.Context, id string) (*models.DataExport, error)
+	FindPending(ctx context.Context, limit int) ([]models.DataExport, error)
+	FindByUserId(ctx context.Context, userId string) ([]models.DataExport, error)
+	FindExpired(ctx context.Context, now time.Time, limit int) ([]models.DataExport, error)
+	Update(ctx context.Context, export models.DataExport) error
+	DeleteById(ctx context.Context, id string) error
+	DeleteByUserId(ctx context.Context, userId string) error
+}
+
+func NewDataExportRepository(db *gorm.DB) DataExportRepository {
+	return &dataExportRepository{
+		db: db,
+	}
+}
+
+type dataExportRepository struct {
+	db *gorm.DB
+}
+
+func (repo *dataExportRepository) Create(
+	ctx context.Context, export models.DataExport,
+) (*models.DataExport, error) {
+	if err := repo.db.WithContext(ctx).Create(&export).Error; err != nil {
+		return nil, err
+	}
+
+	return &export, nil
+}
+
+func (repo *dataExportRepository) FindById(
+	ctx context.Context, id string,
+) (*models.DataExport, error) {
+	var export models.DataExport
+	err := repo.db.WithContext(ctx).Where("id", id).First(&export).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &export, nil
+}
+
+// FindPending returns the oldest exports still to be packaged
+func (repo *dataExportRepository) FindPending(
+	ctx context.Context, limit int,
+) ([]models.DataExport, error) {
+	var exports []models.DataExport
+	err := repo.db.WithContext(ctx).
+		Where("status", models.DataExportStatusPending).
+		Order("created_at").
+		Limit(limit).
+		Find(&exports).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return exports, nil
+}
+
+func (repo *dataExportRepository) FindByUserId(
+	ctx context.Context, userId string,
+) ([]models.DataExport, error) {
+	var exports []models.DataExport
+	err := repo.db.WithContext(ctx).Where("user_id", userId).Find(&exports).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return exports, nil
+}
+
+// FindExpired returns the exports which expired the earliest
+func (repo *dataExportRepository) FindExpired(
+	ctx context.Context, now time.Time, limit int,
+) ([]models.DataExport, error) {
+	var exports []models.DataExport
+	err := repo.db.WithContext(ctx).
+		Where("expires_at <= ?", now).
+		Order("expires_at").
+		Limit(limit).
+		Find(&exports).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return exports, nil
+}
+
+func (repo *dataExportRepository) Update(
+	ctx context.Context, export models.DataExport,
+) error {
+	return repo.db.WithContext(ctx).Save(&export).Error
+}
+
+func (repo *dataExportRepository) DeleteById(ctx context.Context, id string) error {
+	return repo.db.WithContext(ctx).
+		Where("id", id).
+		Delete(&models.DataExport{}).Error
+}
+
+func (repo *dataExportRepository) DeleteByUserId(ctx context.Context, userId string) error {
+	return repo.db.WithContext(ctx).
+		Where("user_id", userId).
+		Delete(&models.DataExport{}).Error
+}
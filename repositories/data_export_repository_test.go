@@ -0,0 +1,143 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"verifymy-golang-test/models"
+	"verifymy-golang-test/providers"
+)
+
+type dataExportRepositoryTestSuite struct {
+	suite.Suite
+	ctx                  context.Context
+	dataExportRepository DataExportRepository
+}
+
+func TestDataExportRepositoryTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(dataExportRepositoryTestSuite))
+}
+
+func (s *dataExportRepositoryTestSuite) SetupTest() {
+	s.ctx = context.Background()
+
+	dbconn, err := gorm.Open(
+		sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", uuid.NewString())),
+		&gorm.Config{Logger: logger.Default.LogMode(logger.Silent), TranslateError: true},
+	)
+	s.Require().NoError(err)
+	s.Require().NoError(providers.Migrate(dbconn))
+
+	s.dataExportRepository = NewDataExportRepository(dbconn)
+}
+
+func (s *dataExportRepositoryTestSuite) create(userId string, createdAt time.Time) *models.DataExport {
+	export, err := s.dataExportRepository.Create(s.ctx, models.DataExport{
+		UserID:    userId,
+		CreatedAt: createdAt,
+	})
+	s.Require().NoError(err)
+
+	return export
+}
+
+func (s *dataExportRepositoryTestSuite) TestCreate() {
+	export := s.create("user-id", time.Now())
+
+	s.NotEqual(uuid.Nil, export.ID)
+	s.Equal(models.DataExportStatusPending, export.Status)
+}
+
+func (s *dataExportRepositoryTestSuite) TestFindById() {
+	export := s.create("user-id", time.Now())
+
+	found, err := s.dataExportRepository.FindById(s.ctx, export.ID.String())
+	s.NoError(err)
+	s.Equal(export.ID, found.ID)
+
+	found, err = s.dataExportRepository.FindById(s.ctx, uuid.NewString())
+	s.NoError(err)
+	s.Nil(found)
+}
+
+func (s *dataExportRepositoryTestSuite) TestFindPending() {
+	now := time.Date(2023, 6, 30, 12, 0, 0, 0, time.UTC)
+	newer := s.create("user-id", now)
+	older := s.create("user-id", now.Add(-time.Hour))
+	ready := s.create("user-id", now.Add(-2*time.Hour))
+	ready.Status = models.DataExportStatusReady
+	s.Require().NoError(s.dataExportRepository.Update(s.ctx, *ready))
+
+	exports, err := s.dataExportRepository.FindPending(s.ctx, 10)
+	s.NoError(err)
+	s.Len(exports, 2)
+	s.Equal(older.ID, exports[0].ID)
+	s.Equal(newer.ID, exports[1].ID)
+
+	exports, err = s.dataExportRepository.FindPending(s.ctx, 1)
+	s.NoError(err)
+	s.Len(exports, 1)
+}
+
+func (s *dataExportRepositoryTestSuite) TestFindExpired() {
+	now := time.Date(2023, 6, 30, 12, 0, 0, 0, time.UTC)
+	s.create("user-id", now)
+	expireAt := func(export *models.DataExport, expiresAt time.Time) {
+		export.ExpiresAt = sql.NullTime{Time: expiresAt, Valid: true}
+		s.Require().NoError(s.dataExportRepository.Update(s.ctx, *export))
+	}
+	expireAt(s.create("user-id", now), now.Add(time.Minute))
+	newer := s.create("user-id", now)
+	expireAt(newer, now)
+	older := s.create("user-id", now)
+	expireAt(older, now.Add(-time.Hour))
+
+	exports, err := s.dataExportRepository.FindExpired(s.ctx, now, 10)
+	s.NoError(err)
+	s.Require().Len(exports, 2)
+	s.Equal(older.ID, exports[0].ID)
+	s.Equal(newer.ID, exports[1].ID)
+
+	exports, err = s.dataExportRepository.FindExpired(s.ctx, now, 1)
+	s.NoError(err)
+	s.Len(exports, 1)
+}
+
+func (s *dataExportRepositoryTestSuite) TestDeleteById() {
+	export := s.create("user-id", time.Now())
+	other := s.create("user-id", time.Now())
+
+	s.NoError(s.dataExportRepository.DeleteById(s.ctx, export.ID.String()))
+
+	exports, err := s.dataExportRepository.FindByUserId(s.ctx, "user-id")
+	s.NoError(err)
+	s.Len(exports, 1)
+	s.Equal(other.ID, exports[0].ID)
+}
+
+func (s *dataExportRepositoryTestSuite) TestDeleteByUserId() {
+	s.create("user-id", time.Now())
+	s.create("user-id", time.Now())
+	other := s.create("other-user-id", time.Now())
+
+	s.NoError(s.dataExportRepository.DeleteByUserId(s.ctx, "user-id"))
+
+	exports, err := s.dataExportRepository.FindByUserId(s.ctx, "user-id")
+	s.NoError(err)
+	s.Empty(exports)
+
+	exports, err = s.dataExportRepository.FindByUserId(s.ctx, "other-user-id")
+	s.NoError(err)
+	s.Len(exports, 1)
+	s.Equal(other.ID, exports[0].ID)
+}
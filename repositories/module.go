@@ -9,4 +9,7 @@ import (
 var Module = fx.Provide(
 	providers.NewDBDialector,
 	providers.NewDBConnection,
+	providers.NewMailer,
+	providers.NewStorage,
+	providers.NewSigningKeys,
 )
@@ -0,0 +1,77 @@
+package repositories
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"verifymy-golang-test/models"
+)
+
+type OAuthAuthorizationCodeRepository interface {
+	Create(ctx context.Context, code models.OAuthAuthorizationCode) error
+	FindByCodeHash(ctx context.Context, hash string) (*models.OAuthAuthorizationCode, error)
+	DeleteByCodeHash(ctx context.Context, hash string) (bool, error)
+	DeleteByUserId(ctx context.Context, userId string) error
+	DeleteByClientId(ctx context.Context, clientId string) error
+}
+
+func NewOAuthAuthorizationCodeRepository(db *gorm.DB) OAuthAuthorizationCodeRepository {
+	return &oauthAuthorizationCodeRepository{
+		db: db,
+	}
+}
+
+type oauthAuthorizationCodeRepository struct {
+	db *gorm.DB
+}
+
+func (repo *oauthAuthorizationCodeRepository) Create(
+	ctx context.Context, code models.OAuthAuthorizationCode,
+) error {
+	return conn(ctx, repo.db).Create(&code).Error
+}
+
+func (repo *oauthAuthorizationCodeRepository) FindByCodeHash(
+	ctx context.Context, hash string,
+) (*models.OAuthAuthorizationCode, error) {
+	var code models.OAuthAuthorizationCode
+	err := conn(ctx, repo.db).Where("code_hash", hash).First(&code).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &code, nil
+}
+
+// DeleteByCodeHash returns false when the code was already deleted, that is,
+// exchanged by someone else
+func (repo *oauthAuthorizationCodeRepository) DeleteByCodeHash(
+	ctx context.Context, hash string,
+) (bool, error) {
+	result := conn(ctx, repo.db).
+		Where("code_hash", hash).
+		Delete(&models.OAuthAuthorizationCode{})
+
+	return result.RowsAffected > 0, result.Error
+}
+
+func (repo *oauthAuthorizationCodeRepository) DeleteByUserId(
+	ctx context.Context, userId string,
+) error {
+	return conn(ctx, repo.db).
+		Where("user_id", userId).
+		Delete(&models.OAuthAuthorizationCode{}).Error
+}
+
+func (repo *oauthAuthorizationCodeRepository) DeleteByClientId(
+	ctx context.Context, clientId string,
+) error {
+	return conn(ctx, repo.db).
+		Where("client_id", clientId).
+		Delete(&models.OAuthAuthorizationCode{}).Error
+}
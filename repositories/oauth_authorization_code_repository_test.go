@@ -0,0 +1,116 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"verifymy-golang-test/models"
+	"verifymy-golang-test/providers"
+)
+
+type oauthAuthorizationCodeRepositoryTestSuite struct {
+	suite.Suite
+	ctx                              context.Context
+	oauthAuthorizationCodeRepository OAuthAuthorizationCodeRepository
+}
+
+func TestOAuthAuthorizationCodeRepositoryTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(oauthAuthorizationCodeRepositoryTestSuite))
+}
+
+func (s *oauthAuthorizationCodeRepositoryTestSuite) SetupTest() {
+	s.ctx = context.Background()
+
+	dbconn, err := gorm.Open(
+		sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", uuid.NewString())),
+		&gorm.Config{Logger: logger.Default.LogMode(logger.Silent), TranslateError: true},
+	)
+	s.Require().NoError(err)
+	s.Require().NoError(providers.Migrate(dbconn))
+
+	s.oauthAuthorizationCodeRepository = NewOAuthAuthorizationCodeRepository(dbconn)
+}
+
+func (s *oauthAuthorizationCodeRepositoryTestSuite) create(
+	hash string, userId string,
+) models.OAuthAuthorizationCode {
+	code := models.OAuthAuthorizationCode{
+		CodeHash:      hash,
+		ClientID:      uuid.NewString(),
+		UserID:        userId,
+		RedirectURI:   "https://shop.example.com/callback",
+		Scopes:        models.StringList{models.ClientScopeProfile, models.ClientScopeEmail},
+		CodeChallenge: "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM",
+		ExpiresAt:     time.Now().UTC().Add(time.Minute).Truncate(time.Second),
+	}
+	s.Require().NoError(s.oauthAuthorizationCodeRepository.Create(s.ctx, code))
+
+	return code
+}
+
+func (s *oauthAuthorizationCodeRepositoryTestSuite) TestFindByCodeHash() {
+	code := s.create("code-hash", uuid.NewString())
+
+	found, err := s.oauthAuthorizationCodeRepository.FindByCodeHash(s.ctx, "code-hash")
+	s.NoError(err)
+	s.Equal(code.UserID, found.UserID)
+	s.Equal(code.RedirectURI, found.RedirectURI)
+	s.Equal(code.Scopes, found.Scopes)
+	s.Equal(code.CodeChallenge, found.CodeChallenge)
+	s.True(code.ExpiresAt.Equal(found.ExpiresAt))
+
+	found, err = s.oauthAuthorizationCodeRepository.FindByCodeHash(s.ctx, "other-code-hash")
+	s.NoError(err)
+	s.Nil(found)
+}
+
+func (s *oauthAuthorizationCodeRepositoryTestSuite) TestDeleteByCodeHash() {
+	s.create("code-hash", uuid.NewString())
+
+	deleted, err := s.oauthAuthorizationCodeRepository.DeleteByCodeHash(s.ctx, "code-hash")
+	s.NoError(err)
+	s.True(deleted)
+
+	deleted, err = s.oauthAuthorizationCodeRepository.DeleteByCodeHash(s.ctx, "code-hash")
+	s.NoError(err)
+	s.False(deleted)
+}
+
+func (s *oauthAuthorizationCodeRepositoryTestSuite) TestDeleteByUserId() {
+	code := s.create("code-hash", uuid.NewString())
+	s.create("other-code-hash", uuid.NewString())
+
+	s.NoError(s.oauthAuthorizationCodeRepository.DeleteByUserId(s.ctx, code.UserID))
+
+	found, err := s.oauthAuthorizationCodeRepository.FindByCodeHash(s.ctx, "code-hash")
+	s.NoError(err)
+	s.Nil(found)
+
+	found, err = s.oauthAuthorizationCodeRepository.FindByCodeHash(s.ctx, "other-code-hash")
+	s.NoError(err)
+	s.NotNil(found)
+}
+
+func (s *oauthAuthorizationCodeRepositoryTestSuite) TestDeleteByClientId() {
+	code := s.create("code-hash", uuid.NewString())
+	s.create("other-code-hash", code.UserID)
+
+	s.NoError(s.oauthAuthorizationCodeRepository.DeleteByClientId(s.ctx, code.ClientID))
+
+	found, err := s.oauthAuthorizationCodeRepository.FindByCodeHash(s.ctx, "code-hash")
+	s.NoError(err)
+	s.Nil(found)
+
+	found, err = s.oauthAuthorizationCodeRepository.FindByCodeHash(s.ctx, "other-code-hash")
+	s.NoError(err)
+	s.NotNil(found)
+}
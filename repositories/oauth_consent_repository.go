@@ -0,0 +1,78 @@
+package repositories
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"verifymy-golang-test/models"
+)
+
+type OAuthConsentRepository interface {
+	FindByUserIdAndClientId(
+		ctx context.Context, userId string, clientId string,
+	) (*models.OAuthConsent, error)
+	FindByUserId(ctx context.Context, userId string) ([]models.OAuthConsent, error)
+	Save(ctx context.Context, consent models.OAuthConsent) (*models.OAuthConsent, error)
+	DeleteByUserId(ctx context.Context, userId string) error
+}
+
+func NewOAuthConsentRepository(db *gorm.DB) OAuthConsentRepository {
+	return &oauthConsentRepository{
+		db: db,
+	}
+}
+
+type oauthConsentRepository struct {
+	db *gorm.DB
+}
+
+func (repo *oauthConsentRepository) FindByUserIdAndClientId(
+	ctx context.Context, userId string, clientId string,
+) (*models.OAuthConsent, error) {
+	var consent models.OAuthConsent
+	err := conn(ctx, repo.db).
+		Where("user_id = ? AND client_id = ?", userId, clientId).
+		First(&consent).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &consent, nil
+}
+
+// FindByUserId returns the consents the user gave, oldest first
+func (repo *oauthConsentRepository) FindByUserId(
+	ctx context.Context, userId string,
+) ([]models.OAuthConsent, error) {
+	var consents []models.OAuthConsent
+	err := conn(ctx, repo.db).Where("user_id", userId).Order("created_at").Find(&consents).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return consents, nil
+}
+
+// Save creates the consent when it has no ID yet and replaces it otherwise
+func (repo *oauthConsentRepository) Save(
+	ctx context.Context, consent models.OAuthConsent,
+) (*models.OAuthConsent, error) {
+	if err := conn(ctx, repo.db).Save(&consent).Error; err != nil {
+		return nil, err
+	}
+
+	return &consent, nil
+}
+
+func (repo *oauthConsentRepository) DeleteByUserId(
+	ctx context.Context, userId string,
+) error {
+	return conn(ctx, repo.db).
+		Where("user_id", userId).
+		Delete(&models.OAuthConsent{}).Error
+}
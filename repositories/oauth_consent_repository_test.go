@@ -0,0 +1,102 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"verifymy-golang-test/models"
+	"verifymy-golang-test/providers"
+)
+
+type oauthConsentRepositoryTestSuite struct {
+	suite.Suite
+	ctx                    context.Context
+	oauthConsentRepository OAuthConsentRepository
+}
+
+func TestOAuthConsentRepositoryTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(oauthConsentRepositoryTestSuite))
+}
+
+func (s *oauthConsentRepositoryTestSuite) SetupTest() {
+	s.ctx = context.Background()
+
+	dbconn, err := gorm.Open(
+		sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", uuid.NewString())),
+		&gorm.Config{Logger: logger.Default.LogMode(logger.Silent), TranslateError: true},
+	)
+	s.Require().NoError(err)
+	s.Require().NoError(providers.Migrate(dbconn))
+
+	s.oauthConsentRepository = NewOAuthConsentRepository(dbconn)
+}
+
+func (s *oauthConsentRepositoryTestSuite) TestSave() {
+	userId := uuid.NewString()
+	clientId := uuid.NewString()
+
+	consent, err := s.oauthConsentRepository.Save(s.ctx, models.OAuthConsent{
+		UserID: userId, ClientID: clientId, Scopes: models.StringList{models.ClientScopeProfile},
+	})
+	s.Require().NoError(err)
+	s.NotEqual(uuid.Nil, consent.ID)
+
+	consent.Scopes = models.StringList{models.ClientScopeProfile, models.ClientScopeEmail}
+	_, err = s.oauthConsentRepository.Save(s.ctx, *consent)
+	s.Require().NoError(err)
+
+	found, err := s.oauthConsentRepository.FindByUserIdAndClientId(s.ctx, userId, clientId)
+	s.NoError(err)
+	s.Equal(consent.ID, found.ID)
+	s.Equal(models.StringList{"profile", "email"}, found.Scopes)
+
+	found, err = s.oauthConsentRepository.FindByUserIdAndClientId(s.ctx, userId, uuid.NewString())
+	s.NoError(err)
+	s.Nil(found)
+}
+
+func (s *oauthConsentRepositoryTestSuite) TestFindByUserId() {
+	userId := uuid.NewString()
+	clientId := uuid.NewString()
+	_, err := s.oauthConsentRepository.Save(s.ctx, models.OAuthConsent{
+		UserID: userId, ClientID: clientId, Scopes: models.StringList{models.ClientScopeProfile},
+	})
+	s.Require().NoError(err)
+	_, err = s.oauthConsentRepository.Save(s.ctx, models.OAuthConsent{
+		UserID: uuid.NewString(), ClientID: clientId, Scopes: models.StringList{models.ClientScopeProfile},
+	})
+	s.Require().NoError(err)
+
+	found, err := s.oauthConsentRepository.FindByUserId(s.ctx, userId)
+	s.NoError(err)
+	s.Len(found, 1)
+	s.Equal(clientId, found[0].ClientID)
+	s.Equal(models.StringList{"profile"}, found[0].Scopes)
+
+	found, err = s.oauthConsentRepository.FindByUserId(s.ctx, uuid.NewString())
+	s.NoError(err)
+	s.Empty(found)
+}
+
+func (s *oauthConsentRepositoryTestSuite) TestDeleteByUserId() {
+	userId := uuid.NewString()
+	clientId := uuid.NewString()
+	_, err := s.oauthConsentRepository.Save(s.ctx, models.OAuthConsent{
+		UserID: userId, ClientID: clientId, Scopes: models.StringList{models.ClientScopeProfile},
+	})
+	s.Require().NoError(err)
+
+	s.NoError(s.oauthConsentRepository.DeleteByUserId(s.ctx, userId))
+
+	found, err := s.oauthConsentRepository.FindByUserIdAndClientId(s.ctx, userId, clientId)
+	s.NoError(err)
+	s.Nil(found)
+}
@@ -0,0 +1,145 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"gorm.io/gorm"
+
+	"verifymy-golang-test/models"
+)
+
+type OAuthGrantRepository interface {
+	Create(ctx context.Context, grant models.OAuthGrant) (*models.OAuthGrant, error)
+	FindById(ctx context.Context, id string) (*models.OAuthGrant, error)
+	FindByRefreshTokenHash(ctx context.Context, hash string) (*models.OAuthGrant, error)
+	FindByUserId(ctx context.Context, userId string) ([]models.OAuthGrant, error)
+	UpdateRefreshToken(
+		ctx context.Context, id string, currentHash string, hash string, expiresAt time.Time,
+	) (bool, error)
+	RevokeById(ctx context.Context, id string, revokedAt time.Time) error
+	RevokeByUserIdAndClientId(
+		ctx context.Context, userId string, clientId string, revokedAt time.Time,
+	) error
+	RevokeByClientId(ctx context.Context, clientId string, revokedAt time.Time) error
+	DeleteByUserId(ctx context.Context, userId string) error
+}
+
+func NewOAuthGrantRepository(db *gorm.DB) OAuthGrantRepository {
+	return &oauthGrantRepository{
+		db: db,
+	}
+}
+
+type oauthGrantRepository struct {
+	db *gorm.DB
+}
+
+func (repo *oauthGrantRepository) Create(
+	ctx context.Context, grant models.OAuthGrant,
+) (*models.OAuthGrant, error) {
+	if err := conn(ctx, repo.db).Create(&grant).Error; err != nil {
+		return nil, err
+	}
+
+	return &grant, nil
+}
+
+func (repo *oauthGrantRepository) FindById(
+	ctx context.Context, id string,
+) (*models.OAuthGrant, error) {
+	return repo.findBy(ctx, "id", id)
+}
+
+func (repo *oauthGrantRepository) FindByRefreshTokenHash(
+	ctx context.Context, hash string,
+) (*models.OAuthGrant, error) {
+	if hash == "" {
+		return nil, nil
+	}
+
+	return repo.findBy(ctx, "refresh_token_hash", hash)
+}
+
+// FindByUserId returns the grants given by the user, revoked ones included,
+// oldest first
+func (repo *oauthGrantRepository) FindByUserId(
+	ctx context.Context, userId string,
+) ([]models.OAuthGrant, error) {
+	var grants []models.OAuthGrant
+	err := conn(ctx, repo.db).Where("user_id", userId).Order("created_at").Find(&grants).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return grants, nil
+}
+
+func (repo *oauthGrantRepository) findBy(
+	ctx context.Context, column string, value string,
+) (*models.OAuthGrant, error) {
+	var grant models.OAuthGrant
+	err := conn(ctx, repo.db).Where(column, value).First(&grant).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &grant, nil
+}
+
+// UpdateRefreshToken replaces the refresh token of a grant only if it still
+// is currentHash and the grant is not revoked, so a refresh token can't be
+// used twice. It returns false otherwise.
+func (repo *oauthGrantRepository) UpdateRefreshToken(
+	ctx context.Context, id string, currentHash string, hash string, expiresAt time.Time,
+) (bool, error) {
+	result := conn(ctx, repo.db).
+		Model(&models.OAuthGrant{}).
+		Where("id = ? AND refresh_token_hash = ? AND revoked_at IS NULL", id, currentHash).
+		Updates(map[string]interface{}{
+			"refresh_token_hash":       hash,
+			"refresh_token_expires_at": sql.NullTime{Time: expiresAt, Valid: true},
+		})
+
+	return result.RowsAffected > 0, result.Error
+}
+
+func (repo *oauthGrantRepository) RevokeById(
+	ctx context.Context, id string, revokedAt time.Time,
+) error {
+	return conn(ctx, repo.db).
+		Model(&models.OAuthGrant{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", sql.NullTime{Time: revokedAt, Valid: true}).Error
+}
+
+func (repo *oauthGrantRepository) RevokeByUserIdAndClientId(
+	ctx context.Context, userId string, clientId string, revokedAt time.Time,
+) error {
+	return conn(ctx, repo.db).
+		Model(&models.OAuthGrant{}).
+		Where("user_id = ? AND client_id = ? AND revoked_at IS NULL", userId, clientId).
+		Update("revoked_at", sql.NullTime{Time: revokedAt, Valid: true}).Error
+}
+
+func (repo *oauthGrantRepository) RevokeByClientId(
+	ctx context.Context, clientId string, revokedAt time.Time,
+) error {
+	return conn(ctx, repo.db).
+		Model(&models.OAuthGrant{}).
+		Where("client_id = ? AND revoked_at IS NULL", clientId).
+		Update("revoked_at", sql.NullTime{Time: revokedAt, Valid: true}).Error
+}
+
+func (repo *oauthGrantRepository) DeleteByUserId(
+	ctx context.Context, userId string,
+) error {
+	return conn(ctx, repo.db).
+		Where("user_id", userId).
+		Delete(&models.OAuthGrant{}).Error
+}
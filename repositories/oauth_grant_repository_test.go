@@ -0,0 +1,189 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"verifymy-golang-test/models"
+	"verifymy-golang-test/providers"
+)
+
+type oauthGrantRepositoryTestSuite struct {
+	suite.Suite
+	ctx                  context.Context
+	oauthGrantRepository OAuthGrantRepository
+}
+
+func TestOAuthGrantRepositoryTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(oauthGrantRepositoryTestSuite))
+}
+
+func (s *oauthGrantRepositoryTestSuite) SetupTest() {
+	s.ctx = context.Background()
+
+	dbconn, err := gorm.Open(
+		sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", uuid.NewString())),
+		&gorm.Config{Logger: logger.Default.LogMode(logger.Silent), TranslateError: true},
+	)
+	s.Require().NoError(err)
+	s.Require().NoError(providers.Migrate(dbconn))
+
+	s.oauthGrantRepository = NewOAuthGrantRepository(dbconn)
+}
+
+func (s *oauthGrantRepositoryTestSuite) create(userId string, refreshTokenHash string) *models.OAuthGrant {
+	grant, err := s.oauthGrantRepository.Create(s.ctx, models.OAuthGrant{
+		ClientID:         uuid.NewString(),
+		UserID:           userId,
+		Scopes:           models.StringList{models.ClientScopeProfile},
+		RefreshTokenHash: refreshTokenHash,
+	})
+	s.Require().NoError(err)
+
+	return grant
+}
+
+func (s *oauthGrantRepositoryTestSuite) TestFind() {
+	grant := s.create(uuid.NewString(), "refresh-token-hash")
+
+	found, err := s.oauthGrantRepository.FindById(s.ctx, grant.ID.String())
+	s.NoError(err)
+	s.Equal(grant.UserID, found.UserID)
+	s.Equal(models.StringList{"profile"}, found.Scopes)
+
+	found, err = s.oauthGrantRepository.FindByRefreshTokenHash(s.ctx, "refresh-token-hash")
+	s.NoError(err)
+	s.Equal(grant.ID, found.ID)
+
+	found, err = s.oauthGrantRepository.FindById(s.ctx, uuid.NewString())
+	s.NoError(err)
+	s.Nil(found)
+}
+
+func (s *oauthGrantRepositoryTestSuite) TestFindByRefreshTokenHashWithoutRefreshToken() {
+	s.create("", "")
+
+	found, err := s.oauthGrantRepository.FindByRefreshTokenHash(s.ctx, "")
+	s.NoError(err)
+	s.Nil(found)
+}
+
+func (s *oauthGrantRepositoryTestSuite) TestUpdateRefreshToken() {
+	grant := s.create(uuid.NewString(), "refresh-token-hash")
+	expiresAt := time.Now().UTC().Add(time.Hour).Truncate(time.Second)
+
+	updated, err := s.oauthGrantRepository.UpdateRefreshToken(
+		s.ctx, grant.ID.String(), "refresh-token-hash", "next-refresh-token-hash", expiresAt,
+	)
+	s.NoError(err)
+	s.True(updated)
+
+	updated, err = s.oauthGrantRepository.UpdateRefreshToken(
+		s.ctx, grant.ID.String(), "refresh-token-hash", "other-refresh-token-hash", expiresAt,
+	)
+	s.NoError(err)
+	s.False(updated)
+
+	found, err := s.oauthGrantRepository.FindById(s.ctx, grant.ID.String())
+	s.NoError(err)
+	s.Equal("next-refresh-token-hash", found.RefreshTokenHash)
+	s.True(expiresAt.Equal(found.RefreshTokenExpiresAt.Time))
+}
+
+func (s *oauthGrantRepositoryTestSuite) TestRevokeById() {
+	grant := s.create(uuid.NewString(), "refresh-token-hash")
+	revokedAt := time.Now().UTC().Truncate(time.Second)
+
+	s.NoError(s.oauthGrantRepository.RevokeById(s.ctx, grant.ID.String(), revokedAt))
+	s.NoError(s.oauthGrantRepository.RevokeById(s.ctx, grant.ID.String(), revokedAt.Add(time.Hour)))
+
+	found, err := s.oauthGrantRepository.FindById(s.ctx, grant.ID.String())
+	s.NoError(err)
+	s.True(revokedAt.Equal(found.RevokedAt.Time))
+
+	updated, err := s.oauthGrantRepository.UpdateRefreshToken(
+		s.ctx, grant.ID.String(), "refresh-token-hash", "next-refresh-token-hash", revokedAt,
+	)
+	s.NoError(err)
+	s.False(updated)
+}
+
+func (s *oauthGrantRepositoryTestSuite) TestRevokeByUserIdAndClientId() {
+	grant := s.create(uuid.NewString(), "refresh-token-hash")
+	otherClient := s.create(grant.UserID, "other-client-refresh-token-hash")
+	otherUser := s.create(uuid.NewString(), "other-user-refresh-token-hash")
+	revokedAt := time.Now().UTC().Truncate(time.Second)
+
+	s.NoError(s.oauthGrantRepository.RevokeByUserIdAndClientId(
+		s.ctx, grant.UserID, grant.ClientID, revokedAt,
+	))
+
+	found, err := s.oauthGrantRepository.FindById(s.ctx, grant.ID.String())
+	s.NoError(err)
+	s.True(revokedAt.Equal(found.RevokedAt.Time))
+
+	for _, other := range []*models.OAuthGrant{otherClient, otherUser} {
+		found, err = s.oauthGrantRepository.FindById(s.ctx, other.ID.String())
+		s.NoError(err)
+		s.False(found.RevokedAt.Valid)
+	}
+}
+
+func (s *oauthGrantRepositoryTestSuite) TestRevokeByClientId() {
+	grant := s.create(uuid.NewString(), "refresh-token-hash")
+	other := s.create(uuid.NewString(), "other-refresh-token-hash")
+	revokedAt := time.Now().UTC().Truncate(time.Second)
+
+	s.NoError(s.oauthGrantRepository.RevokeByClientId(s.ctx, grant.ClientID, revokedAt))
+
+	found, err := s.oauthGrantRepository.FindById(s.ctx, grant.ID.String())
+	s.NoError(err)
+	s.True(revokedAt.Equal(found.RevokedAt.Time))
+
+	found, err = s.oauthGrantRepository.FindById(s.ctx, other.ID.String())
+	s.NoError(err)
+	s.False(found.RevokedAt.Valid)
+}
+
+func (s *oauthGrantRepositoryTestSuite) TestFindByUserId() {
+	userId := uuid.NewString()
+	grant := s.create(userId, "refresh-token-hash")
+	s.Require().NoError(s.oauthGrantRepository.RevokeById(s.ctx, grant.ID.String(), time.Now()))
+	other := s.create(userId, "other-refresh-token-hash")
+	s.create(uuid.NewString(), "another-refresh-token-hash")
+
+	found, err := s.oauthGrantRepository.FindByUserId(s.ctx, userId)
+	s.NoError(err)
+	s.Len(found, 2)
+	s.Equal(grant.ID, found[0].ID)
+	s.True(found[0].RevokedAt.Valid)
+	s.Equal(other.ID, found[1].ID)
+
+	found, err = s.oauthGrantRepository.FindByUserId(s.ctx, uuid.NewString())
+	s.NoError(err)
+	s.Empty(found)
+}
+
+func (s *oauthGrantRepositoryTestSuite) TestDeleteByUserId() {
+	grant := s.create(uuid.NewString(), "refresh-token-hash")
+	other := s.create(uuid.NewString(), "other-refresh-token-hash")
+
+	s.NoError(s.oauthGrantRepository.DeleteByUserId(s.ctx, grant.UserID))
+
+	found, err := s.oauthGrantRepository.FindById(s.ctx, grant.ID.String())
+	s.NoError(err)
+	s.Nil(found)
+
+	found, err = s.oauthGrantRepository.FindById(s.ctx, other.ID.String())
+	s.NoError(err)
+	s.NotNil(found)
+}
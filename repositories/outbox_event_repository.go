@@ -0,0 +1,93 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"verifymy-golang-test/models"
+)
+
+type OutboxEventRepository interface {
+	Create(ctx context.Context, events []models.OutboxEvent) error
+	FindUndeliveredTo(ctx context.Context, sink string, limit int) ([]models.OutboxEvent, error)
+	MarkDelivered(ctx context.Context, id string, deliveredAt time.Time) error
+	MarkDeliveredTo(ctx context.Context, id string, sinks models.StringList) error
+	RecordFailure(ctx context.Context, id string, reason string) error
+}
+
+// NewOutboxEventRepository joins the transaction of the context it is given,
+// see Transactor, so events are stored along with the write they describe.
+func NewOutboxEventRepository(db *gorm.DB) OutboxEventRepository {
+	return &outboxEventRepository{
+		db: db,
+	}
+}
+
+type outboxEventRepository struct {
+	db *gorm.DB
+}
+
+func (repo *outboxEventRepository) Create(
+	ctx context.Context, events []models.OutboxEvent,
+) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	return conn(ctx, repo.db).Create(&events).Error
+}
+
+// FindUndeliveredTo returns the oldest events still to be relayed to sink,
+// in the order they happened. Events it already got while other sinks failed
+// them are left out, so each sink moves on at its own pace.
+func (repo *outboxEventRepository) FindUndeliveredTo(
+	ctx context.Context, sink string, limit int,
+) ([]models.OutboxEvent, error) {
+	// DeliveredSinks is JSON encoded, so sink shows up quoted in it
+	var events []models.OutboxEvent
+	err := conn(ctx, repo.db).
+		Where("delivered_at IS NULL").
+		Where("delivered_sinks IS NULL OR delivered_sinks NOT LIKE ?", `%"`+sink+`"%`).
+		Order("created_at, id").
+		Limit(limit).
+		Find(&events).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+func (repo *outboxEventRepository) MarkDelivered(
+	ctx context.Context, id string, deliveredAt time.Time,
+) error {
+	return conn(ctx, repo.db).
+		Model(&models.OutboxEvent{}).
+		Where("id", id).
+		Update("delivered_at", deliveredAt).Error
+}
+
+// MarkDeliveredTo records the sinks an event still to be relayed was
+// delivered to
+func (repo *outboxEventRepository) MarkDeliveredTo(
+	ctx context.Context, id string, sinks models.StringList,
+) error {
+	return conn(ctx, repo.db).
+		Model(&models.OutboxEvent{}).
+		Where("id", id).
+		Update("delivered_sinks", sinks).Error
+}
+
+func (repo *outboxEventRepository) RecordFailure(
+	ctx context.Context, id string, reason string,
+) error {
+	return conn(ctx, repo.db).
+		Model(&models.OutboxEvent{}).
+		Where("id", id).
+		Updates(map[string]interface{}{
+			"attempts":   gorm.Expr("attempts + 1"),
+			"last_error": reason,
+		}).Error
+}
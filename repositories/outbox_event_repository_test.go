@@ -0,0 +1,161 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"verifymy-golang-test/models"
+	"verifymy-golang-test/providers"
+)
+
+type outboxEventRepositoryTestSuite struct {
+	suite.Suite
+	ctx                   context.Context
+	db                    *gorm.DB
+	transactor            Transactor
+	userRepository        UserRepository
+	outboxEventRepository OutboxEventRepository
+}
+
+func TestOutboxEventRepositoryTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(outboxEventRepositoryTestSuite))
+}
+
+func (s *outboxEventRepositoryTestSuite) SetupTest() {
+	s.ctx = context.Background()
+
+	dbconn, err := gorm.Open(
+		sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", uuid.NewString())),
+		&gorm.Config{Logger: logger.Default.LogMode(logger.Silent), TranslateError: true},
+	)
+	s.Require().NoError(err)
+	s.Require().NoError(providers.Migrate(dbconn))
+
+	s.db = dbconn
+	s.transactor = NewTransactor(dbconn)
+	s.userRepository = NewUserRepository(dbconn)
+	s.outboxEventRepository = NewOutboxEventRepository(dbconn)
+}
+
+func (s *outboxEventRepositoryTestSuite) create(eventType string, createdAt time.Time) models.OutboxEvent {
+	events := []models.OutboxEvent{{
+		Type:        eventType,
+		AggregateID: uuid.NewString(),
+		Payload:     models.JSONMap{"key": "value"},
+		CreatedAt:   createdAt,
+	}}
+	s.Require().NoError(s.outboxEventRepository.Create(s.ctx, events))
+
+	return events[0]
+}
+
+func (s *outboxEventRepositoryTestSuite) TestFindUndeliveredTo() {
+	now := time.Now().UTC()
+	newest := s.create("newest", now)
+	oldest := s.create("oldest", now.Add(-time.Hour))
+	delivered := s.create("delivered", now.Add(-2*time.Hour))
+	s.Require().NoError(
+		s.outboxEventRepository.MarkDelivered(s.ctx, delivered.ID.String(), now),
+	)
+	deliveredToLog := s.create("delivered_to_log", now.Add(-3*time.Hour))
+	s.Require().NoError(s.outboxEventRepository.MarkDeliveredTo(
+		s.ctx, deliveredToLog.ID.String(), models.StringList{"log"},
+	))
+
+	events, err := s.outboxEventRepository.FindUndeliveredTo(s.ctx, "log", 10)
+	s.NoError(err)
+	s.Len(events, 2)
+	s.Equal(oldest.ID, events[0].ID)
+	s.Equal(models.JSONMap{"key": "value"}, events[0].Payload)
+	s.Equal(newest.ID, events[1].ID)
+
+	events, err = s.outboxEventRepository.FindUndeliveredTo(s.ctx, "webhook", 10)
+	s.NoError(err)
+	s.Len(events, 3)
+	s.Equal(deliveredToLog.ID, events[0].ID)
+
+	events, err = s.outboxEventRepository.FindUndeliveredTo(s.ctx, "log", 1)
+	s.NoError(err)
+	s.Len(events, 1)
+}
+
+func (s *outboxEventRepositoryTestSuite) TestMarkDeliveredTo() {
+	event := s.create("event", time.Now())
+
+	s.NoError(s.outboxEventRepository.MarkDeliveredTo(
+		s.ctx, event.ID.String(), models.StringList{"log"},
+	))
+
+	events, err := s.outboxEventRepository.FindUndeliveredTo(s.ctx, "webhook", 10)
+	s.NoError(err)
+	s.Len(events, 1)
+	s.Equal(models.StringList{"log"}, events[0].DeliveredSinks)
+}
+
+func (s *outboxEventRepositoryTestSuite) TestRecordFailure() {
+	event := s.create("event", time.Now())
+
+	s.NoError(s.outboxEventRepository.RecordFailure(s.ctx, event.ID.String(), "first"))
+	s.NoError(s.outboxEventRepository.RecordFailure(s.ctx, event.ID.String(), "second"))
+
+	events, err := s.outboxEventRepository.FindUndeliveredTo(s.ctx, "webhook", 10)
+	s.NoError(err)
+	s.Len(events, 1)
+	s.Equal(2, events[0].Attempts)
+	s.Equal("second", events[0].LastError)
+}
+
+func (s *outboxEventRepositoryTestSuite) TestWithinTransaction() {
+	write := func(email string, fail error) error {
+		return s.transactor.WithinTransaction(s.ctx, func(ctx context.Context) error {
+			user, err := s.userRepository.Create(ctx, models.User{Name: "Name", Email: email})
+			if err != nil {
+				return err
+			}
+
+			if err := s.outboxEventRepository.Create(ctx, []models.OutboxEvent{{
+				Type: "user.registered", AggregateID: user.ID.String(),
+			}}); err != nil {
+				return err
+			}
+
+			return fail
+		})
+	}
+
+	s.Run("Commits the write and its events", func() {
+		s.NoError(write("committed@email.com", nil))
+
+		user, err := s.userRepository.FindByEmail(s.ctx, "committed@email.com")
+		s.NoError(err)
+		s.NotNil(user)
+
+		events, err := s.outboxEventRepository.FindUndeliveredTo(s.ctx, "webhook", 10)
+		s.NoError(err)
+		s.Len(events, 1)
+		s.Equal(user.ID.String(), events[0].AggregateID)
+	})
+
+	s.Run("Rolls back the write and its events", func() {
+		fail := errors.New("error")
+		s.ErrorIs(write("rolled-back@email.com", fail), fail)
+
+		user, err := s.userRepository.FindByEmail(s.ctx, "rolled-back@email.com")
+		s.NoError(err)
+		s.Nil(user)
+
+		var count int64
+		s.NoError(s.db.Model(&models.OutboxEvent{}).Count(&count).Error)
+		s.Equal(int64(1), count)
+	})
+}
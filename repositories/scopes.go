@@ -0,0 +1,25 @@
+package repositories
+
+import "gorm.io/gorm"
+
+// Scope widens or narrows the rows a query sees, see gorm.DB.Scopes. Soft
+// deleted users are hidden by default.
+type Scope func(db *gorm.DB) *gorm.DB
+
+// WithDeleted also returns soft deleted users, for administrators.
+func WithDeleted(db *gorm.DB) *gorm.DB {
+	return db.Unscoped()
+}
+
+// OnlyDeleted returns soft deleted users only.
+func OnlyDeleted(db *gorm.DB) *gorm.DB {
+	return db.Unscoped().Where("deleted_at IS NOT NULL")
+}
+
+func scoped(db *gorm.DB, scopes []Scope) *gorm.DB {
+	for _, scope := range scopes {
+		db = scope(db)
+	}
+
+	return db
+}
@@ -0,0 +1,48 @@
+package repositories
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+type transactionKey struct{}
+
+// Transactor runs writes of several repositories in a single transaction.
+// Repositories join the transaction of the context they are given.
+type Transactor interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+func NewTransactor(db *gorm.DB) Transactor {
+	return &transactor{
+		db: db,
+	}
+}
+
+type transactor struct {
+	db *gorm.DB
+}
+
+// WithinTransaction commits when fn succeeds and rolls back otherwise. Nested
+// calls join the outer transaction.
+func (t *transactor) WithinTransaction(
+	ctx context.Context, fn func(ctx context.Context) error,
+) error {
+	if _, ok := ctx.Value(transactionKey{}).(*gorm.DB); ok {
+		return fn(ctx)
+	}
+
+	return t.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, transactionKey{}, tx))
+	})
+}
+
+// conn is the transaction of ctx, if any, or db
+func conn(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(transactionKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+
+	return db.WithContext(ctx)
+}
@@ -2,35 +2,98 @@ package repositories
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"time"
 
 	"gorm.io/gorm"
 
+	"verifymy-golang-test/entities"
 	"verifymy-golang-test/models"
+	"verifymy-golang-test/utils"
 )
 
 type UserRepository interface {
 	Create(context.Context, models.User) (*models.User, error)
 	FindByEmail(ctx context.Context, email string) (*models.User, error)
-	FindById(ctx context.Context, id string) (*models.User, error)
-	FindAll(ctx context.Context, limit int, offset int) ([]models.User, int64, error)
-	UpdateAttributesByUserId(ctx context.Context, userId string, data models.User) error
+	FindById(ctx context.Context, id string, scopes ...Scope) (*models.User, error)
+	FindAll(ctx context.Context, query entities.UserQuery) ([]models.User, error)
+	Count(ctx context.Context, filters entities.UserFilters) (int64, error)
+	UpdateAttributesByUserId(
+		ctx context.Context, userId string, data models.User, expectedVersion int64,
+	) error
+	UpdateColumnsByUserId(
+		ctx context.Context, userId string, columns map[string]interface{}, expectedVersion int64,
+	) error
+	DeleteById(ctx context.Context, userId string) (bool, error)
+	EraseById(ctx context.Context, userId string) (bool, error)
+	Restore(ctx context.Context, userId string) (bool, error)
+	FindDeletedBefore(
+		ctx context.Context, cutoff time.Time, afterId string, limit int,
+	) ([]models.User, error)
+	PurgeDeletedById(ctx context.Context, userId string) (bool, error)
+	AnonymizeDeletedById(ctx context.Context, userId string) (bool, error)
+	PurgeDeletedBefore(ctx context.Context, cutoff time.Time) (int64, error)
 }
 
+// userSortColumns maps sortable fields to the columns they order by. Only
+// columns listed here are ever interpolated into SQL.
+var userSortColumns = map[string]string{
+	"id":            "id",
+	"name":          "name",
+	"email":         "email",
+	"date_of_birth": "date_of_birth",
+	"created_at":    "created_at",
+}
+
+// userSelectableColumns maps the fields of sparse fieldsets to the columns
+// selected for them.
+var userSelectableColumns = map[string]string{
+	"id":            "id",
+	"name":          "name",
+	"date_of_birth": "date_of_birth",
+	"email":         "email",
+	"address":       "address",
+	"role":          "role",
+	"status":        "status",
+	"created_at":    "created_at",
+	"updated_at":    "updated_at",
+}
+
+var likeEscaper = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+
+// NewUserRepository normalizes every email it stores or looks up, see
+// utils.NormalizeEmail. Plus tags are stripped when EMAIL_STRIP_PLUS_TAG is
+// true.
 func NewUserRepository(db *gorm.DB) UserRepository {
 	return &userRepository{
-		db: db,
+		db:           db,
+		stripPlusTag: os.Getenv("EMAIL_STRIP_PLUS_TAG") == "true",
 	}
 }
 
 type userRepository struct {
-	db *gorm.DB
+	db           *gorm.DB
+	stripPlusTag bool
+}
+
+func (repo *userRepository) normalizeEmail(email string) string {
+	return utils.NormalizeEmail(email, repo.stripPlusTag)
 }
 
 func (repo *userRepository) Create(
 	ctx context.Context, user models.User,
 ) (*models.User, error) {
-	err := repo.db.WithContext(ctx).Create(&user).Error
+	user.Email = repo.normalizeEmail(user.Email)
+	err := conn(ctx, repo.db).Create(&user).Error
 	if err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, entities.NewEmailAlreadyInUseError(user.Email)
+		}
+
 		return nil, err
 	}
 
@@ -39,9 +102,8 @@ func (repo *userRepository) Create(
 
 func (repo *userRepository) FindByEmail(ctx context.Context, email string) (*models.User, error) {
 	var user models.User
-	err := repo.db.WithContext(ctx).
-		Where("email", email).
-		Where("deleted_at IS NULL").
+	err := conn(ctx, repo.db).
+		Where("email", repo.normalizeEmail(email)).
 		First(&user).
 		Error
 	if err != nil {
@@ -55,11 +117,14 @@ func (repo *userRepository) FindByEmail(ctx context.Context, email string) (*mod
 	return &user, nil
 }
 
-func (repo *userRepository) FindById(ctx context.Context, id string) (*models.User, error) {
+// FindById hides soft deleted users unless a scope like WithDeleted says
+// otherwise.
+func (repo *userRepository) FindById(
+	ctx context.Context, id string, scopes ...Scope,
+) (*models.User, error) {
 	var user models.User
-	err := repo.db.WithContext(ctx).
+	err := scoped(conn(ctx, repo.db), scopes).
 		Where("id", id).
-		Where("deleted_at IS NULL").
 		First(&user).
 		Error
 	if err != nil {
@@ -73,44 +138,372 @@ func (repo *userRepository) FindById(ctx context.Context, id string) (*models.Us
 	return &user, nil
 }
 
+// FindAll returns users matching the query filters after the cursor, in
+// the requested order with id as tiebreaker. When the cursor points
+// backward the order is reversed so the closest users come first.
 func (repo *userRepository) FindAll(
-	ctx context.Context, limit int, offset int,
-) ([]models.User, int64, error) {
+	ctx context.Context, query entities.UserQuery,
+) ([]models.User, error) {
+	column, ok := userSortColumns[query.SortField]
+	if !ok {
+		return nil, entities.NewInvalidParameterError(
+			"sort", fmt.Sprintf("cannot sort by %s", query.SortField),
+		)
+	}
+
+	descending := query.SortDirection == entities.SortDescending
+	cursor := query.Page.Cursor
+	if cursor != nil && cursor.Backward {
+		descending = !descending
+	}
+
+	comparison, direction := ">", "ASC"
+	if descending {
+		comparison, direction = "<", "DESC"
+	}
+
+	db := repo.filtered(ctx, query.Filters)
+	if cursor != nil && column == "id" {
+		db = db.Where(fmt.Sprintf("id %s ?", comparison), cursor.ID)
+	} else if cursor != nil {
+		value, err := cursorValue(column, cursor.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		db = db.Where(
+			fmt.Sprintf("(%[1]s %[2]s ? OR (%[1]s = ? AND id %[2]s ?))", column, comparison),
+			value, value, cursor.ID,
+		)
+	}
+
+	if len(query.Fields) > 0 {
+		columns := []string{"id"}
+		if column != "id" {
+			columns = append(columns, column)
+		}
+
+		for _, field := range query.Fields {
+			selectable, ok := userSelectableColumns[field]
+			if !ok {
+				return nil, entities.NewInvalidParameterError(
+					"fields", fmt.Sprintf("unknown field %s", field),
+				)
+			}
+
+			if selectable != "id" && selectable != column {
+				columns = append(columns, selectable)
+			}
+		}
+
+		db = db.Select(columns)
+	}
+
+	db = db.Order(fmt.Sprintf("%s %s", column, direction))
+	if column != "id" {
+		db = db.Order(fmt.Sprintf("id %s", direction))
+	}
+
 	var users []models.User
-	err := repo.db.WithContext(ctx).
-		Where("deleted_at IS NULL").
-		Limit(limit).
-		Offset(offset).
+	err := db.
+		Limit(query.Page.Limit).
 		Find(&users).
 		Error
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 
+	return users, nil
+}
+
+func (repo *userRepository) Count(
+	ctx context.Context, filters entities.UserFilters,
+) (int64, error) {
 	var totalResults int64
-	err = repo.db.WithContext(ctx).
-		Where("deleted_at IS NULL").
+	err := repo.filtered(ctx, filters).
 		Model(&models.User{}).
 		Count(&totalResults).
 		Error
 	if err != nil {
-		return nil, 0, err
+		return 0, err
+	}
+
+	return totalResults, nil
+}
+
+func (repo *userRepository) filtered(
+	ctx context.Context, filters entities.UserFilters,
+) *gorm.DB {
+	db := conn(ctx, repo.db)
+	if filters.Deleted {
+		db = db.Scopes(OnlyDeleted)
+	}
+
+	if filters.EmailPrefix != "" {
+		db = db.Where("email LIKE ? ESCAPE '!'", likeEscaper.Replace(filters.EmailPrefix)+"%")
+	}
+	if filters.Name != "" {
+		db = db.Where("name LIKE ? ESCAPE '!'", "%"+likeEscaper.Replace(filters.Name)+"%")
+	}
+	if filters.DateOfBirthFrom != nil {
+		db = db.Where("date_of_birth >= ?", *filters.DateOfBirthFrom)
+	}
+	if filters.DateOfBirthTo != nil {
+		db = db.Where("date_of_birth <= ?", *filters.DateOfBirthTo)
+	}
+	if filters.CreatedFrom != nil {
+		db = db.Where("created_at >= ?", *filters.CreatedFrom)
+	}
+	if filters.CreatedTo != nil {
+		db = db.Where("created_at <= ?", *filters.CreatedTo)
 	}
 
-	return users, totalResults, nil
+	return db
 }
 
+func cursorValue(column string, value string) (interface{}, error) {
+	if column != "created_at" {
+		return value, nil
+	}
+
+	createdAt, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return nil, entities.NewInvalidParameterError("cursor", "malformed cursor")
+	}
+
+	return createdAt, nil
+}
+
+// UpdateAttributesByUserId updates the non-zero attributes of data, the same
+// way GORM does when updating from a struct.
 func (repo *userRepository) UpdateAttributesByUserId(
-	ctx context.Context, userId string, data models.User,
+	ctx context.Context, userId string, data models.User, expectedVersion int64,
 ) error {
-	err := repo.db.WithContext(ctx).
-		Model(&models.User{}).
-		Where("id", userId).
-		Updates(data).
-		Error
+	attributes, err := repo.nonZeroAttributes(ctx, data)
 	if err != nil {
 		return err
 	}
 
+	return repo.UpdateColumnsByUserId(ctx, userId, attributes, expectedVersion)
+}
+
+// UpdateColumnsByUserId writes columns as given, zero values included, and
+// bumps the user version. When expectedVersion is set the update only happens
+// if the stored version still matches it.
+func (repo *userRepository) UpdateColumnsByUserId(
+	ctx context.Context, userId string, columns map[string]interface{}, expectedVersion int64,
+) error {
+	attributes := make(map[string]interface{}, len(columns)+1)
+	for column, value := range columns {
+		attributes[column] = value
+	}
+	attributes["version"] = gorm.Expr("version + 1")
+
+	email, hasEmail := attributes["email"].(string)
+	if hasEmail {
+		email = repo.normalizeEmail(email)
+		attributes["email"] = email
+	}
+
+	query := conn(ctx, repo.db).
+		Model(&models.User{}).
+		Where("id", userId)
+	if expectedVersion > 0 {
+		query = query.Where("version", expectedVersion)
+	}
+
+	result := query.Updates(attributes)
+	if result.Error != nil {
+		if hasEmail && errors.Is(result.Error, gorm.ErrDuplicatedKey) {
+			return entities.NewEmailAlreadyInUseError(email)
+		}
+
+		return result.Error
+	}
+
+	if expectedVersion > 0 && result.RowsAffected == 0 {
+		return entities.NewPreconditionFailedError()
+	}
+
 	return nil
 }
+
+// DeleteById soft deletes a user. It reports false when there is no active
+// user with the given id.
+func (repo *userRepository) DeleteById(ctx context.Context, userId string) (bool, error) {
+	result := conn(ctx, repo.db).
+		Where("id", userId).
+		Delete(&models.User{})
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// EraseById erases the personal data of an active user, revokes their
+// sessions and soft deletes them, all at once. Unlike DeleteById, the user
+// can't be restored afterwards. It reports false when there is no active
+// user with the given id.
+func (repo *userRepository) EraseById(ctx context.Context, userId string) (bool, error) {
+	now := time.Now().UTC()
+	result := conn(ctx, repo.db).
+		Model(&models.User{}).
+		Where("id", userId).
+		Updates(map[string]interface{}{
+			"name":                "",
+			"date_of_birth":       nil,
+			"email":               "",
+			"password":            "",
+			"address":             "",
+			"guardian_email":      "",
+			"anonymized_at":       now,
+			"sessions_revoked_at": now,
+			"deleted_at":          now,
+			"version":             gorm.Expr("version + 1"),
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// Restore undoes the soft delete of a user. It reports false when there is no
+// deleted user with the given id, when its data was already anonymized, or
+// when it was deleted because its guardian rejected it signing up.
+func (repo *userRepository) Restore(ctx context.Context, userId string) (bool, error) {
+	result := conn(ctx, repo.db).
+		Scopes(OnlyDeleted).
+		Model(&models.User{}).
+		Where("id", userId).
+		Where("anonymized_at IS NULL").
+		Where("status <> ?", models.UserStatusParentalConsentRejected).
+		Updates(map[string]interface{}{
+			"deleted_at": nil,
+			"version":    gorm.Expr("version + 1"),
+		})
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
+			// The email was registered again after the user was deleted
+			user, err := repo.FindById(ctx, userId, WithDeleted)
+			if err != nil {
+				return false, err
+			}
+
+			return false, entities.NewEmailAlreadyInUseError(user.Email)
+		}
+
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// FindDeletedBefore returns, ordered by id, the users deleted before cutoff
+// whose personal data was not erased yet. Pass the id of the last user of a
+// page as afterId to get the next one.
+func (repo *userRepository) FindDeletedBefore(
+	ctx context.Context, cutoff time.Time, afterId string, limit int,
+) ([]models.User, error) {
+	var users []models.User
+	err := conn(ctx, repo.db).
+		Unscoped().
+		Where("deleted_at < ?", cutoff).
+		Where("anonymized_at IS NULL").
+		Where("id > ?", afterId).
+		Order("id").
+		Limit(limit).
+		Find(&users).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+// PurgeDeletedById permanently removes a deleted user. It reports false when
+// there is no deleted user with the given id.
+func (repo *userRepository) PurgeDeletedById(ctx context.Context, userId string) (bool, error) {
+	result := conn(ctx, repo.db).
+		Unscoped().
+		Where("id", userId).
+		Where("deleted_at IS NOT NULL").
+		Delete(&models.User{})
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// AnonymizeDeletedById erases the personal data of a deleted user, keeping
+// its row so references to it stay valid. It reports false when there is no
+// deleted user with the given id whose data is still there.
+func (repo *userRepository) AnonymizeDeletedById(
+	ctx context.Context, userId string,
+) (bool, error) {
+	result := conn(ctx, repo.db).
+		Unscoped().
+		Model(&models.User{}).
+		Where("id", userId).
+		Where("deleted_at IS NOT NULL").
+		Where("anonymized_at IS NULL").
+		Updates(map[string]interface{}{
+			"name":           "",
+			"date_of_birth":  nil,
+			"email":          "",
+			"password":       "",
+			"address":        "",
+			"guardian_email": "",
+			"anonymized_at":  time.Now().UTC(),
+			"version":        gorm.Expr("version + 1"),
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// PurgeDeletedBefore permanently removes the users deleted before cutoff
+// whose personal data was already erased, and returns how many were removed.
+func (repo *userRepository) PurgeDeletedBefore(
+	ctx context.Context, cutoff time.Time,
+) (int64, error) {
+	result := conn(ctx, repo.db).
+		Unscoped().
+		Where("deleted_at < ?", cutoff).
+		Where("anonymized_at IS NOT NULL").
+		Delete(&models.User{})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return result.RowsAffected, nil
+}
+
+// nonZeroAttributes mirrors what GORM updates from a struct, as a map so
+// expressions like the version bump can be added to the same statement.
+func (repo *userRepository) nonZeroAttributes(
+	ctx context.Context, data models.User,
+) (map[string]interface{}, error) {
+	statement := &gorm.Statement{DB: repo.db}
+	if err := statement.Parse(&models.User{}); err != nil {
+		return nil, err
+	}
+
+	attributes := map[string]interface{}{}
+	value := reflect.ValueOf(data)
+	for _, field := range statement.Schema.Fields {
+		if field.DBName == "" || !field.Updatable || field.PrimaryKey {
+			continue
+		}
+
+		if fieldValue, isZero := field.ValueOf(ctx, value); !isZero {
+			attributes[field.DBName] = fieldValue
+		}
+	}
+
+	return attributes, nil
+}
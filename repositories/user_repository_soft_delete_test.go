@@ -0,0 +1,322 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"verifymy-golang-test/entities"
+	"verifymy-golang-test/models"
+	"verifymy-golang-test/providers"
+)
+
+// userRepositorySoftDeleteTestSuite runs against an in-memory SQLite database,
+// since what matters here is which rows GORM hides and which ones the unique
+// email index counts, not the SQL it writes.
+type userRepositorySoftDeleteTestSuite struct {
+	suite.Suite
+	ctx            context.Context
+	userRepository UserRepository
+	user           *models.User
+}
+
+func TestUserRepositorySoftDeleteTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(userRepositorySoftDeleteTestSuite))
+}
+
+func (s *userRepositorySoftDeleteTestSuite) SetupTest() {
+	s.ctx = context.Background()
+
+	dbconn, err := gorm.Open(
+		sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", uuid.NewString())),
+		&gorm.Config{Logger: logger.Default.LogMode(logger.Silent), TranslateError: true},
+	)
+	s.Require().NoError(err)
+	s.Require().NoError(providers.Migrate(dbconn))
+
+	s.userRepository = NewUserRepository(dbconn)
+	s.user, err = s.userRepository.Create(s.ctx, models.User{
+		Name:        "Peter Parker",
+		DateOfBirth: models.Date(time.Date(2001, 8, 10, 0, 0, 0, 0, time.UTC)),
+		Email:       "peter.parker@nyork.co",
+		Password:    "hashedpass",
+	})
+	s.Require().NoError(err)
+}
+
+func (s *userRepositorySoftDeleteTestSuite) delete() {
+	deleted, err := s.userRepository.DeleteById(s.ctx, s.user.ID.String())
+	s.Require().NoError(err)
+	s.Require().True(deleted)
+}
+
+func (s *userRepositorySoftDeleteTestSuite) TestDeleteById() {
+	s.delete()
+
+	deleted, err := s.userRepository.DeleteById(s.ctx, s.user.ID.String())
+	s.NoError(err)
+	s.False(deleted)
+
+	user, err := s.userRepository.FindById(s.ctx, s.user.ID.String(), WithDeleted)
+	s.NoError(err)
+	s.True(user.DeletedAt.Valid)
+	s.WithinDuration(time.Now(), user.DeletedAt.Time, time.Minute)
+}
+
+func (s *userRepositorySoftDeleteTestSuite) TestDeletedUserIsHidden() {
+	s.delete()
+
+	user, err := s.userRepository.FindById(s.ctx, s.user.ID.String())
+	s.NoError(err)
+	s.Nil(user)
+
+	user, err = s.userRepository.FindByEmail(s.ctx, s.user.Email)
+	s.NoError(err)
+	s.Nil(user)
+
+	query := entities.UserQuery{
+		SortField:     entities.DefaultUserSortField,
+		SortDirection: entities.SortAscending,
+		Page:          entities.PageRequest{Limit: 10},
+	}
+	users, err := s.userRepository.FindAll(s.ctx, query)
+	s.NoError(err)
+	s.Empty(users)
+
+	total, err := s.userRepository.Count(s.ctx, query.Filters)
+	s.NoError(err)
+	s.Equal(int64(0), total)
+
+	err = s.userRepository.UpdateColumnsByUserId(
+		s.ctx, s.user.ID.String(), map[string]interface{}{"name": "Spider Man"}, s.user.Version,
+	)
+	s.Equal(entities.NewPreconditionFailedError(), err)
+}
+
+func (s *userRepositorySoftDeleteTestSuite) TestDeletedUsersFilter() {
+	query := entities.UserQuery{
+		Filters:       entities.UserFilters{Deleted: true},
+		SortField:     entities.DefaultUserSortField,
+		SortDirection: entities.SortAscending,
+		Page:          entities.PageRequest{Limit: 10},
+	}
+
+	users, err := s.userRepository.FindAll(s.ctx, query)
+	s.NoError(err)
+	s.Empty(users)
+
+	s.delete()
+
+	users, err = s.userRepository.FindAll(s.ctx, query)
+	s.NoError(err)
+	s.Len(users, 1)
+	s.Equal(s.user.ID, users[0].ID)
+
+	total, err := s.userRepository.Count(s.ctx, query.Filters)
+	s.NoError(err)
+	s.Equal(int64(1), total)
+}
+
+func (s *userRepositorySoftDeleteTestSuite) TestRestore() {
+	restored, err := s.userRepository.Restore(s.ctx, s.user.ID.String())
+	s.NoError(err)
+	s.False(restored)
+
+	s.delete()
+
+	restored, err = s.userRepository.Restore(s.ctx, s.user.ID.String())
+	s.NoError(err)
+	s.True(restored)
+
+	user, err := s.userRepository.FindById(s.ctx, s.user.ID.String())
+	s.NoError(err)
+	s.Equal(s.user.Email, user.Email)
+}
+
+func (s *userRepositorySoftDeleteTestSuite) TestEraseById() {
+	erased, err := s.userRepository.EraseById(s.ctx, s.user.ID.String())
+	s.Require().NoError(err)
+	s.True(erased)
+
+	erased, err = s.userRepository.EraseById(s.ctx, s.user.ID.String())
+	s.NoError(err)
+	s.False(erased)
+
+	user, err := s.userRepository.FindById(s.ctx, s.user.ID.String())
+	s.NoError(err)
+	s.Nil(user)
+
+	user, err = s.userRepository.FindById(s.ctx, s.user.ID.String(), WithDeleted)
+	s.Require().NoError(err)
+	s.Empty(user.Name)
+	s.Empty(user.Email)
+	s.Empty(user.Password)
+	s.Empty(user.Address)
+	s.True(time.Time(user.DateOfBirth).IsZero())
+	s.True(user.AnonymizedAt.Valid)
+	s.True(user.SessionsRevokedAt.Valid)
+	s.True(user.DeletedAt.Valid)
+
+	restored, err := s.userRepository.Restore(s.ctx, s.user.ID.String())
+	s.NoError(err)
+	s.False(restored)
+
+	_, err = s.userRepository.Create(s.ctx, models.User{Email: "peter.parker@nyork.co"})
+	s.NoError(err)
+}
+
+func (s *userRepositorySoftDeleteTestSuite) TestFindDeletedBefore() {
+	users, err := s.userRepository.FindDeletedBefore(s.ctx, time.Now().Add(time.Hour), "", 10)
+	s.NoError(err)
+	s.Empty(users)
+
+	s.delete()
+	erased, err := s.userRepository.Create(s.ctx, models.User{Email: "miles.morales@nyork.co"})
+	s.Require().NoError(err)
+	_, err = s.userRepository.EraseById(s.ctx, erased.ID.String())
+	s.Require().NoError(err)
+
+	users, err = s.userRepository.FindDeletedBefore(s.ctx, time.Now().Add(-time.Hour), "", 10)
+	s.NoError(err)
+	s.Empty(users)
+
+	users, err = s.userRepository.FindDeletedBefore(s.ctx, time.Now().Add(time.Hour), "", 10)
+	s.NoError(err)
+	s.Require().Len(users, 1)
+	s.Equal(s.user.ID, users[0].ID)
+	s.Equal("peter.parker@nyork.co", users[0].Email)
+
+	users, err = s.userRepository.FindDeletedBefore(
+		s.ctx, time.Now().Add(time.Hour), s.user.ID.String(), 10,
+	)
+	s.NoError(err)
+	s.Empty(users)
+}
+
+func (s *userRepositorySoftDeleteTestSuite) TestAnonymizeDeletedById() {
+	anonymized, err := s.userRepository.AnonymizeDeletedById(s.ctx, s.user.ID.String())
+	s.NoError(err)
+	s.False(anonymized)
+
+	s.delete()
+
+	anonymized, err = s.userRepository.AnonymizeDeletedById(s.ctx, s.user.ID.String())
+	s.NoError(err)
+	s.True(anonymized)
+
+	user, err := s.userRepository.FindById(s.ctx, s.user.ID.String(), WithDeleted)
+	s.NoError(err)
+	s.Empty(user.Email)
+	s.True(user.AnonymizedAt.Valid)
+
+	restored, err := s.userRepository.Restore(s.ctx, s.user.ID.String())
+	s.NoError(err)
+	s.False(restored)
+
+	anonymized, err = s.userRepository.AnonymizeDeletedById(s.ctx, s.user.ID.String())
+	s.NoError(err)
+	s.False(anonymized)
+}
+
+func (s *userRepositorySoftDeleteTestSuite) TestPurgeDeletedById() {
+	purged, err := s.userRepository.PurgeDeletedById(s.ctx, s.user.ID.String())
+	s.NoError(err)
+	s.False(purged)
+
+	s.delete()
+
+	purged, err = s.userRepository.PurgeDeletedById(s.ctx, s.user.ID.String())
+	s.NoError(err)
+	s.True(purged)
+
+	user, err := s.userRepository.FindById(s.ctx, s.user.ID.String(), WithDeleted)
+	s.NoError(err)
+	s.Nil(user)
+}
+
+func (s *userRepositorySoftDeleteTestSuite) TestPurgeDeletedBefore() {
+	s.delete()
+
+	// The personal data of the user is still there, to be erased first
+	purged, err := s.userRepository.PurgeDeletedBefore(s.ctx, time.Now().Add(time.Hour))
+	s.NoError(err)
+	s.Equal(int64(0), purged)
+
+	_, err = s.userRepository.AnonymizeDeletedById(s.ctx, s.user.ID.String())
+	s.Require().NoError(err)
+
+	purged, err = s.userRepository.PurgeDeletedBefore(s.ctx, time.Now().Add(-time.Hour))
+	s.NoError(err)
+	s.Equal(int64(0), purged)
+
+	purged, err = s.userRepository.PurgeDeletedBefore(s.ctx, time.Now().Add(time.Hour))
+	s.NoError(err)
+	s.Equal(int64(1), purged)
+
+	user, err := s.userRepository.FindById(s.ctx, s.user.ID.String(), WithDeleted)
+	s.NoError(err)
+	s.Nil(user)
+}
+
+func (s *userRepositorySoftDeleteTestSuite) TestDuplicateEmail() {
+	_, err := s.userRepository.Create(s.ctx, models.User{
+		Name:  "Miles Morales",
+		Email: " Peter.Parker@NYork.co",
+	})
+	s.Equal(entities.NewEmailAlreadyInUseError("peter.parker@nyork.co"), err)
+
+	user, err := s.userRepository.FindByEmail(s.ctx, "PETER.PARKER@nyork.co ")
+	s.NoError(err)
+	s.Equal(s.user.ID, user.ID)
+
+	miles, err := s.userRepository.Create(s.ctx, models.User{
+		Name:  "Miles Morales",
+		Email: "miles.morales@nyork.co",
+	})
+	s.Require().NoError(err)
+
+	err = s.userRepository.UpdateColumnsByUserId(
+		s.ctx, miles.ID.String(), map[string]interface{}{"email": "Peter.Parker@nyork.co"}, 0,
+	)
+	s.Equal(entities.NewEmailAlreadyInUseError("peter.parker@nyork.co"), err)
+}
+
+func (s *userRepositorySoftDeleteTestSuite) TestEmailIsReleasedByDelete() {
+	s.delete()
+
+	user, err := s.userRepository.Create(s.ctx, models.User{
+		Name:  "Peter Parker",
+		Email: s.user.Email,
+	})
+	s.Require().NoError(err)
+
+	restored, err := s.userRepository.Restore(s.ctx, s.user.ID.String())
+	s.Equal(entities.NewEmailAlreadyInUseError(s.user.Email), err)
+	s.False(restored)
+
+	foundUser, err := s.userRepository.FindByEmail(s.ctx, s.user.Email)
+	s.NoError(err)
+	s.Equal(user.ID, foundUser.ID)
+}
+
+func (s *userRepositorySoftDeleteTestSuite) TestStripPlusTag() {
+	repository := &userRepository{db: s.userRepository.(*userRepository).db, stripPlusTag: true}
+
+	_, err := repository.Create(s.ctx, models.User{
+		Name:  "Peter Parker",
+		Email: "peter.parker+spidey@nyork.co",
+	})
+	s.Equal(entities.NewEmailAlreadyInUseError("peter.parker@nyork.co"), err)
+
+	user, err := repository.FindByEmail(s.ctx, "Peter.Parker+news@nyork.co")
+	s.NoError(err)
+	s.Equal(s.user.ID, user.ID)
+}
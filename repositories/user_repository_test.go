@@ -7,9 +7,11 @@ import (
 	"regexp"
 	"testing"
 	"time"
+	"verifymy-golang-test/entities"
 	"verifymy-golang-test/models"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	mysqlDriver "github.com/go-sql-driver/mysql"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/suite"
 	"gorm.io/driver/mysql"
@@ -40,7 +42,7 @@ func (s *userRepositoryTestSuite) SetupTest() {
 		},
 	}
 
-	dbconn, err := gorm.Open(dialector, &gorm.Config{})
+	dbconn, err := gorm.Open(dialector, &gorm.Config{TranslateError: true})
 	if err != nil {
 		s.FailNow(err.Error())
 	}
@@ -71,7 +73,15 @@ func (s *userRepositoryTestSuite) TestCreate() {
 			"email",
 			"hashedpass",
 			"Av. Paulista, 1000. São Paulo - SP",
+			"user",
+			"active",
+			sqlmock.AnyArg(),
+			sqlmock.AnyArg(),
+			int64(1),
+			nil,
+			nil,
 			nil,
+			"",
 		).WillReturnResult(sqlmock.NewResult(1, 1))
 		s.dbmock.ExpectCommit()
 
@@ -91,6 +101,46 @@ func (s *userRepositoryTestSuite) TestCreate() {
 		s.NoError(s.dbmock.ExpectationsWereMet())
 	})
 
+	s.Run("Email already in use", func() {
+		s.SetupTest()
+
+		s.dbmock.ExpectBegin()
+		s.dbmock.ExpectExec(
+			regexp.QuoteMeta("INSERT INTO `users`"),
+		).WithArgs(
+			sqlmock.AnyArg(),
+			"name",
+			eighteenYearsAgo,
+			"peter.parker@nyork.co",
+			"hashedpass",
+			"",
+			"user",
+			"active",
+			sqlmock.AnyArg(),
+			sqlmock.AnyArg(),
+			int64(1),
+			nil,
+			nil,
+			nil,
+			"",
+		).WillReturnError(&mysqlDriver.MySQLError{Number: 1062, Message: "Duplicate entry"})
+		s.dbmock.ExpectRollback()
+
+		user, err := s.userRepository.Create(
+			s.ctx,
+			models.User{
+				Name:        "name",
+				DateOfBirth: eighteenYearsAgo,
+				Email:       "Peter.Parker@NYork.co ",
+				Password:    "hashedpass",
+			},
+		)
+
+		s.Equal(entities.NewEmailAlreadyInUseError("peter.parker@nyork.co"), err)
+		s.Nil(user)
+		s.NoError(s.dbmock.ExpectationsWereMet())
+	})
+
 	s.Run("Error in query", func() {
 		s.SetupTest()
 
@@ -104,7 +154,15 @@ func (s *userRepositoryTestSuite) TestCreate() {
 			"email",
 			"hashedpass",
 			"Av. Paulista, 1000. São Paulo - SP",
+			"user",
+			"active",
+			sqlmock.AnyArg(),
+			sqlmock.AnyArg(),
+			int64(1),
+			nil,
 			nil,
+			nil,
+			"",
 		).WillReturnError(errors.New("error executing query"))
 		s.dbmock.ExpectRollback()
 
@@ -136,6 +194,10 @@ func (s *userRepositoryTestSuite) TestFindByEmail() {
 			description: "Success",
 			email:       "email@email.com",
 		},
+		{
+			description: "Success with unnormalized email",
+			email:       " Email@Email.com",
+		},
 		{
 			description:         "No results found",
 			email:               "email@email.com",
@@ -155,9 +217,9 @@ func (s *userRepositoryTestSuite) TestFindByEmail() {
 			s.SetupTest()
 
 			expectedQuery := s.dbmock.ExpectQuery(
-				regexp.QuoteMeta("SELECT * FROM `users` WHERE `email` = ? AND deleted_at IS NULL ORDER BY `users`.`id` LIMIT 1"),
+				regexp.QuoteMeta("SELECT * FROM `users` WHERE `email` = ? AND `users`.`deleted_at` IS NULL ORDER BY `users`.`id` LIMIT 1"),
 			).WithArgs(
-				test.email,
+				"email@email.com",
 			)
 
 			if test.noResultsFoundError {
@@ -198,69 +260,231 @@ func (s *userRepositoryTestSuite) TestFindByEmail() {
 
 func (s *userRepositoryTestSuite) TestFindAll() {
 	userId := uuid.New()
+	cursorId := uuid.New().String()
+	createdAt := time.Date(2023, 6, 1, 10, 0, 0, 0, time.UTC)
+	dateOfBirth := models.Date(time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC))
 
+	tests := []struct {
+		description   string
+		query         entities.UserQuery
+		expectedQuery string
+		expectedArgs  []driver.Value
+		getUsersError bool
+		expectedError string
+	}{
+		{
+			description: "Success without cursor",
+			query: entities.UserQuery{
+				SortField: "id", SortDirection: entities.SortAscending,
+				Page: entities.PageRequest{Limit: 11},
+			},
+			expectedQuery: "SELECT * FROM `users` WHERE `users`.`deleted_at` IS NULL ORDER BY id ASC LIMIT 11",
+		},
+		{
+			description: "Success with forward cursor",
+			query: entities.UserQuery{
+				SortField: "id", SortDirection: entities.SortAscending,
+				Page: entities.PageRequest{Limit: 11, Cursor: &entities.Cursor{ID: cursorId}},
+			},
+			expectedQuery: "SELECT * FROM `users` WHERE id > ? AND `users`.`deleted_at` IS NULL ORDER BY id ASC LIMIT 11",
+			expectedArgs:  []driver.Value{cursorId},
+		},
+		{
+			description: "Success with backward cursor",
+			query: entities.UserQuery{
+				SortField: "id", SortDirection: entities.SortAscending,
+				Page: entities.PageRequest{
+					Limit: 11, Cursor: &entities.Cursor{ID: cursorId, Backward: true},
+				},
+			},
+			expectedQuery: "SELECT * FROM `users` WHERE id < ? AND `users`.`deleted_at` IS NULL ORDER BY id DESC LIMIT 11",
+			expectedArgs:  []driver.Value{cursorId},
+		},
+		{
+			description: "Success sorting by name descending with cursor",
+			query: entities.UserQuery{
+				SortField: "name", SortDirection: entities.SortDescending,
+				Page: entities.PageRequest{
+					Limit: 11, Cursor: &entities.Cursor{ID: cursorId, Value: "Stephen"},
+				},
+			},
+			expectedQuery: "SELECT * FROM `users` WHERE ((name < ? OR (name = ? AND id < ?))) AND `users`.`deleted_at` IS NULL ORDER BY name DESC,id DESC LIMIT 11",
+			expectedArgs:  []driver.Value{"Stephen", "Stephen", cursorId},
+		},
+		{
+			description: "Success sorting by created_at with cursor",
+			query: entities.UserQuery{
+				SortField: "created_at", SortDirection: entities.SortAscending,
+				Page: entities.PageRequest{
+					Limit: 11,
+					Cursor: &entities.Cursor{
+						ID: cursorId, Value: createdAt.Format(time.RFC3339Nano),
+					},
+				},
+			},
+			expectedQuery: "SELECT * FROM `users` WHERE ((created_at > ? OR (created_at = ? AND id > ?))) AND `users`.`deleted_at` IS NULL ORDER BY created_at ASC,id ASC LIMIT 11",
+			expectedArgs:  []driver.Value{createdAt, createdAt, cursorId},
+		},
+		{
+			description: "Success with filters",
+			query: entities.UserQuery{
+				SortField: "id", SortDirection: entities.SortAscending,
+				Filters: entities.UserFilters{
+					EmailPrefix:     "stephen_",
+					Name:            "100%",
+					DateOfBirthFrom: &dateOfBirth,
+					DateOfBirthTo:   &dateOfBirth,
+					CreatedFrom:     &createdAt,
+					CreatedTo:       &createdAt,
+				},
+				Page: entities.PageRequest{Limit: 11},
+			},
+			expectedQuery: "SELECT * FROM `users` WHERE email LIKE ? ESCAPE '!' AND name LIKE ? ESCAPE '!' AND date_of_birth >= ? AND date_of_birth <= ? AND created_at >= ? AND created_at <= ? AND `users`.`deleted_at` IS NULL ORDER BY id ASC LIMIT 11",
+			expectedArgs: []driver.Value{
+				"stephen!_%", "%100!%%", "1990-01-01", "1990-01-01", createdAt, createdAt,
+			},
+		},
+		{
+			description: "Success selecting sparse fieldset",
+			query: entities.UserQuery{
+				Fields:    []string{"email", "id"},
+				SortField: "name", SortDirection: entities.SortAscending,
+				Page: entities.PageRequest{Limit: 11},
+			},
+			expectedQuery: "SELECT `id`,`name`,`email` FROM `users` WHERE `users`.`deleted_at` IS NULL ORDER BY name ASC,id ASC LIMIT 11",
+		},
+		{
+			description: "Field is not selectable",
+			query: entities.UserQuery{
+				Fields:    []string{"password"},
+				SortField: "id", SortDirection: entities.SortAscending,
+				Page: entities.PageRequest{Limit: 11},
+			},
+			expectedError: "invalid fields parameter",
+		},
+		{
+			description: "Malformed created_at cursor",
+			query: entities.UserQuery{
+				SortField: "created_at", SortDirection: entities.SortAscending,
+				Page: entities.PageRequest{
+					Limit: 11, Cursor: &entities.Cursor{ID: cursorId, Value: "yesterday"},
+				},
+			},
+			expectedError: "invalid cursor parameter",
+		},
+		{
+			description: "Sort field is not whitelisted",
+			query: entities.UserQuery{
+				SortField: "password", SortDirection: entities.SortAscending,
+				Page: entities.PageRequest{Limit: 11},
+			},
+			expectedError: "invalid sort parameter",
+		},
+		{
+			description: "Error getting users",
+			query: entities.UserQuery{
+				SortField: "id", SortDirection: entities.SortAscending,
+				Page: entities.PageRequest{Limit: 11},
+			},
+			expectedQuery: "SELECT * FROM `users` WHERE `users`.`deleted_at` IS NULL ORDER BY id ASC LIMIT 11",
+			getUsersError: true,
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			s.SetupTest()
+
+			if test.expectedQuery != "" {
+				expectedGetUsersQuery := s.dbmock.ExpectQuery(
+					regexp.QuoteMeta(test.expectedQuery),
+				).WithArgs(test.expectedArgs...)
+				if test.getUsersError {
+					expectedGetUsersQuery.WillReturnError(errors.New("error executing query"))
+				} else {
+					expectedGetUsersQuery.WillReturnRows(
+						sqlmock.NewRows(
+							[]string{"id", "name", "date_of_birth", "email", "password", "address"},
+						).AddRow(
+							userId,
+							"Stephen Curry",
+							time.Date(1988, 3, 14, 0, 0, 0, 0, time.UTC),
+							"stephen.curry@nba.com",
+							"hashedpass",
+							"Av. Paulista, 1000. São Paulo - SP",
+						),
+					)
+				}
+			}
+
+			result, err := s.userRepository.FindAll(s.ctx, test.query)
+			if test.expectedError != "" {
+				s.ErrorContains(err, test.expectedError)
+				s.Nil(result)
+			} else if test.getUsersError {
+				s.Error(err)
+				s.Nil(result)
+			} else {
+				s.NoError(err)
+				s.Equal(result[0].Name, "Stephen Curry")
+			}
+			s.NoError(s.dbmock.ExpectationsWereMet())
+		})
+	}
+}
+
+func (s *userRepositoryTestSuite) TestCount() {
 	tests := []struct {
 		description     string
-		getUsersError   bool
+		filters         entities.UserFilters
+		expectedQuery   string
+		expectedArgs    []driver.Value
 		countUsersError bool
 	}{
 		{
-			description: "Success",
+			description:   "Success",
+			expectedQuery: "SELECT count(*) FROM `users` WHERE `users`.`deleted_at` IS NULL",
 		},
 		{
-			description:   "Error getting users",
-			getUsersError: true,
+			description:   "Success with filters",
+			filters:       entities.UserFilters{Name: "Curry"},
+			expectedQuery: "SELECT count(*) FROM `users` WHERE name LIKE ? ESCAPE '!' AND `users`.`deleted_at` IS NULL",
+			expectedArgs:  []driver.Value{"%Curry%"},
+		},
+		{
+			description:   "Success with deleted users",
+			filters:       entities.UserFilters{Deleted: true},
+			expectedQuery: "SELECT count(*) FROM `users` WHERE deleted_at IS NOT NULL",
 		},
 		{
 			description:     "Error counting users",
+			expectedQuery:   "SELECT count(*) FROM `users` WHERE `users`.`deleted_at` IS NULL",
 			countUsersError: true,
 		},
 	}
 
 	for _, test := range tests {
 		s.Run(test.description, func() {
-			expectedGetUsersQuery := s.dbmock.ExpectQuery(
-				regexp.QuoteMeta("SELECT * FROM `users` WHERE deleted_at IS NULL LIMIT 10"),
-			)
-			if test.getUsersError {
-				expectedGetUsersQuery.WillReturnError(errors.New("error executing query"))
-			} else {
-				expectedGetUsersQuery.WillReturnRows(
-					sqlmock.NewRows(
-						[]string{"id", "name", "date_of_birth", "email", "password", "address"},
-					).AddRow(
-						userId,
-						"Stephen Curry",
-						time.Date(1988, 3, 14, 0, 0, 0, 0, time.UTC),
-						"stephen.curry@nba.com",
-						"hashedpass",
-						"Av. Paulista, 1000. São Paulo - SP",
-					),
-				)
-			}
+			s.SetupTest()
 
-			if !test.getUsersError {
-				expectedCountUsersQuery := s.dbmock.ExpectQuery(
-					regexp.QuoteMeta("SELECT count(*) FROM `users` WHERE deleted_at IS NULL"),
+			expectedCountUsersQuery := s.dbmock.ExpectQuery(
+				regexp.QuoteMeta(test.expectedQuery),
+			).WithArgs(test.expectedArgs...)
+			if test.countUsersError {
+				expectedCountUsersQuery.WillReturnError(errors.New("error executing count query"))
+			} else {
+				expectedCountUsersQuery.WillReturnRows(
+					sqlmock.NewRows([]string{"count"}).AddRow(1),
 				)
-				if test.countUsersError {
-					expectedCountUsersQuery.WillReturnError(errors.New("error executing count query"))
-				} else {
-					expectedCountUsersQuery.WillReturnRows(
-						sqlmock.NewRows([]string{"count"}).AddRow(1),
-					)
-				}
 			}
 
-			result, totalResults, err := s.userRepository.FindAll(s.ctx, 10, 0)
-			if test.getUsersError || test.countUsersError {
+			totalResults, err := s.userRepository.Count(s.ctx, test.filters)
+			if test.countUsersError {
 				s.Error(err)
-				s.Nil(result)
 				s.Equal(int64(0), totalResults)
 			} else {
 				s.NoError(err)
 				s.Equal(int64(1), totalResults)
-				s.Equal(result[0].Name, "Stephen Curry")
 			}
 			s.NoError(s.dbmock.ExpectationsWereMet())
 		})
@@ -297,7 +521,7 @@ func (s *userRepositoryTestSuite) TestFindById() {
 			s.SetupTest()
 
 			expectedQuery := s.dbmock.ExpectQuery(
-				regexp.QuoteMeta("SELECT * FROM `users` WHERE `id` = ? AND deleted_at IS NULL ORDER BY `users`.`id` LIMIT 1"),
+				regexp.QuoteMeta("SELECT * FROM `users` WHERE `id` = ? AND `users`.`deleted_at` IS NULL ORDER BY `users`.`id` LIMIT 1"),
 			).WithArgs(
 				test.userId,
 			)
@@ -342,39 +566,63 @@ func (s *userRepositoryTestSuite) TestUpdateAttributesByUserId() {
 	userId := uuid.New()
 
 	tests := []struct {
-		description   string
-		attributes    models.User
-		expectedQuery string
-		expectedArgs  []driver.Value
-		errorInQuery  error
+		description     string
+		attributes      models.User
+		expectedVersion int64
+		expectedQuery   string
+		expectedArgs    []driver.Value
+		rowsAffected    int64
+		errorInQuery    error
+		expectedError   string
 	}{
 		{
 			description:   "Success only name",
 			attributes:    models.User{Name: "name"},
-			expectedQuery: "UPDATE `users` SET `name`=? WHERE `id` = ?",
-			expectedArgs:  []driver.Value{"name", userId.String()},
+			expectedQuery: "UPDATE `users` SET `name`=?,`version`=version + 1,`updated_at`=? WHERE `id` = ?",
+			expectedArgs:  []driver.Value{"name", sqlmock.AnyArg(), userId.String()},
+			rowsAffected:  1,
 		},
 		{
 			description: "Success multiple attributes",
 			attributes: models.User{
 				Name: "name", Email: "hello@world.com",
 			},
-			expectedQuery: "UPDATE `users` SET `name`=?,`email`=? WHERE `id` = ?",
+			expectedQuery: "UPDATE `users` SET `email`=?,`name`=?,`version`=version + 1,`updated_at`=? WHERE `id` = ?",
 			expectedArgs: []driver.Value{
-				"name", "hello@world.com", userId.String(),
+				"hello@world.com", "name", sqlmock.AnyArg(), userId.String(),
 			},
+			rowsAffected: 1,
+		},
+		{
+			description:     "Success with expected version",
+			attributes:      models.User{Name: "name"},
+			expectedVersion: 3,
+			expectedQuery:   "UPDATE `users` SET `name`=?,`version`=version + 1,`updated_at`=? WHERE `id` = ? AND `version` = ?",
+			expectedArgs:    []driver.Value{"name", sqlmock.AnyArg(), userId.String(), 3},
+			rowsAffected:    1,
+		},
+		{
+			description:     "Version does not match",
+			attributes:      models.User{Name: "name"},
+			expectedVersion: 3,
+			expectedQuery:   "UPDATE `users` SET `name`=?,`version`=version + 1,`updated_at`=? WHERE `id` = ? AND `version` = ?",
+			expectedArgs:    []driver.Value{"name", sqlmock.AnyArg(), userId.String(), 3},
+			expectedError:   "precondition failed",
 		},
 		{
 			description:   "Error in query",
 			attributes:    models.User{Name: "name"},
-			expectedQuery: "UPDATE `users` SET `name`=? WHERE `id` = ?",
-			expectedArgs:  []driver.Value{"name", userId.String()},
+			expectedQuery: "UPDATE `users` SET `name`=?,`version`=version + 1,`updated_at`=? WHERE `id` = ?",
+			expectedArgs:  []driver.Value{"name", sqlmock.AnyArg(), userId.String()},
 			errorInQuery:  errors.New("error executing query"),
+			expectedError: "error executing query",
 		},
 	}
 
 	for _, test := range tests {
 		s.Run(test.description, func() {
+			s.SetupTest()
+
 			s.dbmock.ExpectBegin()
 
 			expectedQuery := s.dbmock.ExpectExec(
@@ -384,24 +632,198 @@ func (s *userRepositoryTestSuite) TestUpdateAttributesByUserId() {
 			)
 			if test.errorInQuery != nil {
 				expectedQuery.WillReturnError(test.errorInQuery)
+				s.dbmock.ExpectRollback()
 			} else {
-				expectedQuery.WillReturnResult(sqlmock.NewResult(1, 1))
+				expectedQuery.WillReturnResult(sqlmock.NewResult(0, test.rowsAffected))
+				s.dbmock.ExpectCommit()
 			}
 
+			err := s.userRepository.UpdateAttributesByUserId(
+				s.ctx, userId.String(), test.attributes, test.expectedVersion,
+			)
+			if test.expectedError != "" {
+				s.ErrorContains(err, test.expectedError)
+			} else {
+				s.NoError(err)
+			}
+			s.NoError(s.dbmock.ExpectationsWereMet())
+		})
+	}
+}
+
+func (s *userRepositoryTestSuite) TestUpdateColumnsByUserId() {
+	userId := uuid.New()
+
+	tests := []struct {
+		description     string
+		columns         map[string]interface{}
+		expectedVersion int64
+		expectedQuery   string
+		expectedArgs    []driver.Value
+		rowsAffected    int64
+		errorInQuery    error
+		expectedError   string
+	}{
+		{
+			description:   "Clears column",
+			columns:       map[string]interface{}{"address": "", "name": "name"},
+			expectedQuery: "UPDATE `users` SET `address`=?,`name`=?,`version`=version + 1,`updated_at`=? WHERE `id` = ?",
+			expectedArgs:  []driver.Value{"", "name", sqlmock.AnyArg(), userId.String()},
+			rowsAffected:  1,
+		},
+		{
+			description:   "Normalizes email",
+			columns:       map[string]interface{}{"email": " Peter.Parker@NYork.co"},
+			expectedQuery: "UPDATE `users` SET `email`=?,`version`=version + 1,`updated_at`=? WHERE `id` = ?",
+			expectedArgs:  []driver.Value{"peter.parker@nyork.co", sqlmock.AnyArg(), userId.String()},
+			rowsAffected:  1,
+		},
+		{
+			description:   "Email already in use",
+			columns:       map[string]interface{}{"email": "peter.parker@nyork.co"},
+			expectedQuery: "UPDATE `users` SET `email`=?,`version`=version + 1,`updated_at`=? WHERE `id` = ?",
+			expectedArgs:  []driver.Value{"peter.parker@nyork.co", sqlmock.AnyArg(), userId.String()},
+			errorInQuery:  &mysqlDriver.MySQLError{Number: 1062, Message: "Duplicate entry"},
+			expectedError: "e-mail is already in use",
+		},
+		{
+			description:     "Version does not match",
+			columns:         map[string]interface{}{"address": ""},
+			expectedVersion: 2,
+			expectedQuery:   "UPDATE `users` SET `address`=?,`version`=version + 1,`updated_at`=? WHERE `id` = ? AND `version` = ?",
+			expectedArgs:    []driver.Value{"", sqlmock.AnyArg(), userId.String(), 2},
+			expectedError:   "precondition failed",
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			s.SetupTest()
+
+			s.dbmock.ExpectBegin()
+			expectedQuery := s.dbmock.ExpectExec(
+				regexp.QuoteMeta(test.expectedQuery),
+			).WithArgs(
+				test.expectedArgs...,
+			)
 			if test.errorInQuery != nil {
+				expectedQuery.WillReturnError(test.errorInQuery)
 				s.dbmock.ExpectRollback()
 			} else {
+				expectedQuery.WillReturnResult(sqlmock.NewResult(0, test.rowsAffected))
 				s.dbmock.ExpectCommit()
 			}
 
-			err := s.userRepository.UpdateAttributesByUserId(
-				s.ctx, userId.String(), test.attributes,
+			err := s.userRepository.UpdateColumnsByUserId(
+				s.ctx, userId.String(), test.columns, test.expectedVersion,
+			)
+			if test.expectedError != "" {
+				s.ErrorContains(err, test.expectedError)
+			} else {
+				s.NoError(err)
+			}
+			s.NoError(s.dbmock.ExpectationsWereMet())
+		})
+	}
+}
+
+func (s *userRepositoryTestSuite) TestRestore() {
+	userId := uuid.New()
+
+	tests := []struct {
+		description      string
+		rowsAffected     int64
+		errorInQuery     error
+		expectedRestored bool
+	}{
+		{
+			description:      "Success",
+			rowsAffected:     1,
+			expectedRestored: true,
+		},
+		{
+			description: "No deleted user found",
+		},
+		{
+			description:  "Error in query",
+			errorInQuery: errors.New("error executing query"),
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			s.SetupTest()
+
+			s.dbmock.ExpectBegin()
+			expectedQuery := s.dbmock.ExpectExec(
+				regexp.QuoteMeta("UPDATE `users` SET `deleted_at`=?,`version`=version + 1,`updated_at`=? WHERE `id` = ? AND anonymized_at IS NULL AND status <> ? AND deleted_at IS NOT NULL"),
+			).WithArgs(
+				nil, sqlmock.AnyArg(), userId.String(), models.UserStatusParentalConsentRejected,
 			)
+			if test.errorInQuery != nil {
+				expectedQuery.WillReturnError(test.errorInQuery)
+				s.dbmock.ExpectRollback()
+			} else {
+				expectedQuery.WillReturnResult(sqlmock.NewResult(0, test.rowsAffected))
+				s.dbmock.ExpectCommit()
+			}
+
+			restored, err := s.userRepository.Restore(s.ctx, userId.String())
 			if test.errorInQuery != nil {
 				s.ErrorContains(err, test.errorInQuery.Error())
 			} else {
 				s.NoError(err)
 			}
+			s.Equal(test.expectedRestored, restored)
+			s.NoError(s.dbmock.ExpectationsWereMet())
+		})
+	}
+}
+
+func (s *userRepositoryTestSuite) TestPurgeDeletedBefore() {
+	cutoff := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		description    string
+		rowsAffected   int64
+		errorInQuery   error
+		expectedPurged int64
+	}{
+		{
+			description:    "Success",
+			rowsAffected:   2,
+			expectedPurged: 2,
+		},
+		{
+			description:  "Error in query",
+			errorInQuery: errors.New("error executing query"),
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			s.SetupTest()
+
+			s.dbmock.ExpectBegin()
+			expectedQuery := s.dbmock.ExpectExec(
+				regexp.QuoteMeta("DELETE FROM `users` WHERE deleted_at < ? AND anonymized_at IS NOT NULL"),
+			).WithArgs(cutoff)
+			if test.errorInQuery != nil {
+				expectedQuery.WillReturnError(test.errorInQuery)
+				s.dbmock.ExpectRollback()
+			} else {
+				expectedQuery.WillReturnResult(sqlmock.NewResult(0, test.rowsAffected))
+				s.dbmock.ExpectCommit()
+			}
+
+			purged, err := s.userRepository.PurgeDeletedBefore(s.ctx, cutoff)
+			if test.errorInQuery != nil {
+				s.ErrorContains(err, test.errorInQuery.Error())
+			} else {
+				s.NoError(err)
+			}
+			s.Equal(test.expectedPurged, purged)
+			s.NoError(s.dbmock.ExpectationsWereMet())
 		})
 	}
 }
@@ -0,0 +1,31 @@
+package repositories
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"verifymy-golang-test/models"
+)
+
+type UserTombstoneRepository interface {
+	Create(ctx context.Context, tombstone models.UserTombstone) error
+}
+
+func NewUserTombstoneRepository(db *gorm.DB) UserTombstoneRepository {
+	return &userTombstoneRepository{
+		db: db,
+	}
+}
+
+type userTombstoneRepository struct {
+	db *gorm.DB
+}
+
+// Create joins the transaction of the context it is given, see Transactor,
+// so the tombstone is stored along with the erasure it records
+func (repo *userTombstoneRepository) Create(
+	ctx context.Context, tombstone models.UserTombstone,
+) error {
+	return conn(ctx, repo.db).Create(&tombstone).Error
+}
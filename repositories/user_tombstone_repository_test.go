@@ -0,0 +1,102 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+
+	"verifymy-golang-test/models"
+)
+
+type userTombstoneRepositoryTestSuite struct {
+	suite.Suite
+	ctx                     context.Context
+	dbmock                  sqlmock.Sqlmock
+	userTombstoneRepository UserTombstoneRepository
+}
+
+func TestUserTombstoneRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(userTombstoneRepositoryTestSuite))
+}
+
+func (s *userTombstoneRepositoryTestSuite) SetupTest() {
+	s.ctx = context.Background()
+
+	conn, dbmock, _ := sqlmock.New()
+	dialector := mysql.Dialector{
+		Config: &mysql.Config{
+			DSN:                       "sqlmock_db_0",
+			Conn:                      conn,
+			SkipInitializeWithVersion: true,
+		},
+	}
+
+	dbconn, err := gorm.Open(dialector, &gorm.Config{})
+	if err != nil {
+		s.FailNow(err.Error())
+	}
+
+	s.dbmock = dbmock
+	s.userTombstoneRepository = NewUserTombstoneRepository(dbconn)
+}
+
+func (s *userTombstoneRepositoryTestSuite) TestCreate() {
+	erasedAt := time.Date(2023, 6, 30, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		description  string
+		errorInQuery error
+	}{
+		{
+			description: "Success",
+		},
+		{
+			description:  "Error in query",
+			errorInQuery: errors.New("error executing query"),
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			s.SetupTest()
+
+			s.dbmock.ExpectBegin()
+			expectedQuery := s.dbmock.ExpectExec(
+				regexp.QuoteMeta("INSERT INTO `user_tombstones`"),
+			).WithArgs(
+				sqlmock.AnyArg(),
+				"user-id",
+				"user-id",
+				"profile,sessions",
+				erasedAt,
+			)
+			if test.errorInQuery != nil {
+				expectedQuery.WillReturnError(test.errorInQuery)
+				s.dbmock.ExpectRollback()
+			} else {
+				expectedQuery.WillReturnResult(sqlmock.NewResult(1, 1))
+				s.dbmock.ExpectCommit()
+			}
+
+			err := s.userTombstoneRepository.Create(s.ctx, models.UserTombstone{
+				UserID:      "user-id",
+				RequestedBy: "user-id",
+				Steps:       "profile,sessions",
+				ErasedAt:    erasedAt,
+			})
+			if test.errorInQuery != nil {
+				s.ErrorContains(err, test.errorInQuery.Error())
+			} else {
+				s.NoError(err)
+			}
+			s.NoError(s.dbmock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"verifymy-golang-test/models"
+)
+
+type WebhookDeliveryRepository interface {
+	Create(ctx context.Context, deliveries []models.WebhookDelivery) error
+	FindById(ctx context.Context, id string) (*models.WebhookDelivery, error)
+	FindDue(ctx context.Context, now time.Time, limit int) ([]models.WebhookDelivery, error)
+	FindBySubscriptionId(
+		ctx context.Context, subscriptionId string, status string, limit int,
+	) ([]models.WebhookDelivery, error)
+	Update(ctx context.Context, delivery models.WebhookDelivery) error
+	CreateAttempt(ctx context.Context, attempt models.WebhookDeliveryAttempt) error
+	DeleteBySubscriptionId(ctx context.Context, subscriptionId string) error
+}
+
+func NewWebhookDeliveryRepository(db *gorm.DB) WebhookDeliveryRepository {
+	return &webhookDeliveryRepository{
+		db: db,
+	}
+}
+
+type webhookDeliveryRepository struct {
+	db *gorm.DB
+}
+
+// Create skips deliveries of an event already queued for a subscription, so
+// an event relayed twice is only delivered once
+func (repo *webhookDeliveryRepository) Create(
+	ctx context.Context, deliveries []models.WebhookDelivery,
+) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	return conn(ctx, repo.db).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&deliveries).Error
+}
+
+func (repo *webhookDeliveryRepository) FindById(
+	ctx context.Context, id string,
+) (*models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+	err := conn(ctx, repo.db).Where("id", id).First(&delivery).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &delivery, nil
+}
+
+// FindDue returns the pending deliveries whose next attempt is due, oldest
+// first
+func (repo *webhookDeliveryRepository) FindDue(
+	ctx context.Context, now time.Time, limit int,
+) ([]models.WebhookDelivery, error) {
+	var deliveries []models.WebhookDelivery
+	err := conn(ctx, repo.db).
+		Where("status", models.WebhookDeliveryStatusPending).
+		Where("next_attempt_at <= ?", now).
+		Order("next_attempt_at, created_at").
+		Limit(limit).
+		Find(&deliveries).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// FindBySubscriptionId returns the newest deliveries of a subscription, of
+// any status when status is empty, along with their attempts
+func (repo *webhookDeliveryRepository) FindBySubscriptionId(
+	ctx context.Context, subscriptionId string, status string, limit int,
+) ([]models.WebhookDelivery, error) {
+	query := conn(ctx, repo.db).
+		Preload("Attempts", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at")
+		}).
+		Where("subscription_id", subscriptionId)
+	if status != "" {
+		query = query.Where("status", status)
+	}
+
+	var deliveries []models.WebhookDelivery
+	err := query.Order("created_at DESC, id DESC").Limit(limit).Find(&deliveries).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+func (repo *webhookDeliveryRepository) Update(
+	ctx context.Context, delivery models.WebhookDelivery,
+) error {
+	return conn(ctx, repo.db).Omit(clause.Associations).Save(&delivery).Error
+}
+
+func (repo *webhookDeliveryRepository) CreateAttempt(
+	ctx context.Context, attempt models.WebhookDeliveryAttempt,
+) error {
+	return conn(ctx, repo.db).Create(&attempt).Error
+}
+
+// DeleteBySubscriptionId deletes the deliveries of a subscription and their
+// attempts
+func (repo *webhookDeliveryRepository) DeleteBySubscriptionId(
+	ctx context.Context, subscriptionId string,
+) error {
+	db := conn(ctx, repo.db)
+	err := db.
+		Where(
+			"delivery_id IN (?)",
+			db.Model(&models.WebhookDelivery{}).Select("id").Where("subscription_id", subscriptionId),
+		).
+		Delete(&models.WebhookDeliveryAttempt{}).Error
+	if err != nil {
+		return err
+	}
+
+	return db.Where("subscription_id", subscriptionId).Delete(&models.WebhookDelivery{}).Error
+}
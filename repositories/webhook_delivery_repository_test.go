@@ -0,0 +1,153 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"verifymy-golang-test/models"
+	"verifymy-golang-test/providers"
+)
+
+type webhookDeliveryRepositoryTestSuite struct {
+	suite.Suite
+	ctx                       context.Context
+	db                        *gorm.DB
+	webhookDeliveryRepository WebhookDeliveryRepository
+}
+
+func TestWebhookDeliveryRepositoryTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(webhookDeliveryRepositoryTestSuite))
+}
+
+func (s *webhookDeliveryRepositoryTestSuite) SetupTest() {
+	s.ctx = context.Background()
+
+	dbconn, err := gorm.Open(
+		sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", uuid.NewString())),
+		&gorm.Config{Logger: logger.Default.LogMode(logger.Silent), TranslateError: true},
+	)
+	s.Require().NoError(err)
+	s.Require().NoError(providers.Migrate(dbconn))
+
+	s.db = dbconn
+	s.webhookDeliveryRepository = NewWebhookDeliveryRepository(dbconn)
+}
+
+func (s *webhookDeliveryRepositoryTestSuite) create(
+	subscriptionId string, eventId string, nextAttemptAt time.Time,
+) models.WebhookDelivery {
+	deliveries := []models.WebhookDelivery{{
+		SubscriptionID: subscriptionId,
+		EventID:        eventId,
+		EventType:      "user.registered",
+		Payload:        `{"type":"user.registered"}`,
+		NextAttemptAt:  nextAttemptAt,
+		CreatedAt:      nextAttemptAt,
+	}}
+	s.Require().NoError(s.webhookDeliveryRepository.Create(s.ctx, deliveries))
+
+	return deliveries[0]
+}
+
+func (s *webhookDeliveryRepositoryTestSuite) TestCreate() {
+	subscriptionId := uuid.NewString()
+	eventId := uuid.NewString()
+	delivery := s.create(subscriptionId, eventId, time.Now())
+
+	s.Equal(models.WebhookDeliveryStatusPending, delivery.Status)
+
+	s.Run("Skips events already queued for the subscription", func() {
+		s.create(subscriptionId, eventId, time.Now())
+
+		deliveries, err := s.webhookDeliveryRepository.FindBySubscriptionId(
+			s.ctx, subscriptionId, "", 10,
+		)
+		s.NoError(err)
+		s.Len(deliveries, 1)
+		s.Equal(delivery.ID, deliveries[0].ID)
+	})
+}
+
+func (s *webhookDeliveryRepositoryTestSuite) TestFindDue() {
+	now := time.Now().UTC()
+	subscriptionId := uuid.NewString()
+	later := s.create(subscriptionId, uuid.NewString(), now.Add(-time.Minute))
+	sooner := s.create(subscriptionId, uuid.NewString(), now.Add(-time.Hour))
+	s.create(subscriptionId, uuid.NewString(), now.Add(time.Hour))
+	dead := s.create(subscriptionId, uuid.NewString(), now.Add(-time.Hour))
+	dead.Status = models.WebhookDeliveryStatusDead
+	s.Require().NoError(s.webhookDeliveryRepository.Update(s.ctx, dead))
+
+	deliveries, err := s.webhookDeliveryRepository.FindDue(s.ctx, now, 10)
+
+	s.NoError(err)
+	s.Len(deliveries, 2)
+	s.Equal(sooner.ID, deliveries[0].ID)
+	s.Equal(later.ID, deliveries[1].ID)
+}
+
+func (s *webhookDeliveryRepositoryTestSuite) TestFindBySubscriptionId() {
+	now := time.Now().UTC()
+	subscriptionId := uuid.NewString()
+	older := s.create(subscriptionId, uuid.NewString(), now.Add(-time.Hour))
+	newer := s.create(subscriptionId, uuid.NewString(), now)
+	s.create(uuid.NewString(), uuid.NewString(), now)
+	newer.Status = models.WebhookDeliveryStatusDead
+	s.Require().NoError(s.webhookDeliveryRepository.Update(s.ctx, newer))
+	for _, statusCode := range []int{500, 503} {
+		s.Require().NoError(s.webhookDeliveryRepository.CreateAttempt(s.ctx, models.WebhookDeliveryAttempt{
+			DeliveryID: newer.ID.String(),
+			StatusCode: statusCode,
+			CreatedAt:  now.Add(time.Duration(statusCode) * time.Second),
+		}))
+	}
+
+	deliveries, err := s.webhookDeliveryRepository.FindBySubscriptionId(
+		s.ctx, subscriptionId, "", 10,
+	)
+	s.NoError(err)
+	s.Len(deliveries, 2)
+	s.Equal(newer.ID, deliveries[0].ID)
+	s.Len(deliveries[0].Attempts, 2)
+	s.Equal(500, deliveries[0].Attempts[0].StatusCode)
+	s.Equal(older.ID, deliveries[1].ID)
+	s.Empty(deliveries[1].Attempts)
+
+	deliveries, err = s.webhookDeliveryRepository.FindBySubscriptionId(
+		s.ctx, subscriptionId, models.WebhookDeliveryStatusPending, 10,
+	)
+	s.NoError(err)
+	s.Len(deliveries, 1)
+	s.Equal(older.ID, deliveries[0].ID)
+}
+
+func (s *webhookDeliveryRepositoryTestSuite) TestDeleteBySubscriptionId() {
+	subscriptionId := uuid.NewString()
+	delivery := s.create(subscriptionId, uuid.NewString(), time.Now())
+	kept := s.create(uuid.NewString(), uuid.NewString(), time.Now())
+	for _, deliveryId := range []string{delivery.ID.String(), kept.ID.String()} {
+		s.Require().NoError(s.webhookDeliveryRepository.CreateAttempt(
+			s.ctx, models.WebhookDeliveryAttempt{DeliveryID: deliveryId, StatusCode: 500},
+		))
+	}
+
+	s.NoError(s.webhookDeliveryRepository.DeleteBySubscriptionId(s.ctx, subscriptionId))
+
+	found, err := s.webhookDeliveryRepository.FindById(s.ctx, delivery.ID.String())
+	s.NoError(err)
+	s.Nil(found)
+
+	var attempts []models.WebhookDeliveryAttempt
+	s.NoError(s.db.Find(&attempts).Error)
+	s.Len(attempts, 1)
+	s.Equal(kept.ID.String(), attempts[0].DeliveryID)
+}
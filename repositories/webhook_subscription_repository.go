@@ -0,0 +1,102 @@
+package repositories
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"verifymy-golang-test/models"
+)
+
+type WebhookSubscriptionRepository interface {
+	Create(
+		ctx context.Context, subscription models.WebhookSubscription,
+	) (*models.WebhookSubscription, error)
+	FindAll(ctx context.Context) ([]models.WebhookSubscription, error)
+	FindById(ctx context.Context, id string) (*models.WebhookSubscription, error)
+	FindByEventType(ctx context.Context, eventType string) ([]models.WebhookSubscription, error)
+	Update(ctx context.Context, subscription models.WebhookSubscription) error
+	DeleteById(ctx context.Context, id string) (bool, error)
+}
+
+func NewWebhookSubscriptionRepository(db *gorm.DB) WebhookSubscriptionRepository {
+	return &webhookSubscriptionRepository{
+		db: db,
+	}
+}
+
+type webhookSubscriptionRepository struct {
+	db *gorm.DB
+}
+
+func (repo *webhookSubscriptionRepository) Create(
+	ctx context.Context, subscription models.WebhookSubscription,
+) (*models.WebhookSubscription, error) {
+	if err := conn(ctx, repo.db).Create(&subscription).Error; err != nil {
+		return nil, err
+	}
+
+	return &subscription, nil
+}
+
+func (repo *webhookSubscriptionRepository) FindAll(
+	ctx context.Context,
+) ([]models.WebhookSubscription, error) {
+	var subscriptions []models.WebhookSubscription
+	err := conn(ctx, repo.db).Order("created_at, id").Find(&subscriptions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return subscriptions, nil
+}
+
+func (repo *webhookSubscriptionRepository) FindById(
+	ctx context.Context, id string,
+) (*models.WebhookSubscription, error) {
+	var subscription models.WebhookSubscription
+	err := conn(ctx, repo.db).Where("id", id).First(&subscription).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &subscription, nil
+}
+
+// FindByEventType filters subscriptions once loaded, since event types are
+// stored as a JSON list. There are only ever a handful of subscriptions.
+func (repo *webhookSubscriptionRepository) FindByEventType(
+	ctx context.Context, eventType string,
+) ([]models.WebhookSubscription, error) {
+	subscriptions, err := repo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	subscribed := []models.WebhookSubscription{}
+	for _, subscription := range subscriptions {
+		if subscription.EventTypes.Includes(eventType) {
+			subscribed = append(subscribed, subscription)
+		}
+	}
+
+	return subscribed, nil
+}
+
+func (repo *webhookSubscriptionRepository) Update(
+	ctx context.Context, subscription models.WebhookSubscription,
+) error {
+	return conn(ctx, repo.db).Save(&subscription).Error
+}
+
+func (repo *webhookSubscriptionRepository) DeleteById(
+	ctx context.Context, id string,
+) (bool, error) {
+	result := conn(ctx, repo.db).Where("id", id).Delete(&models.WebhookSubscription{})
+
+	return result.RowsAffected > 0, result.Error
+}
@@ -0,0 +1,112 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"verifymy-golang-test/models"
+	"verifymy-golang-test/providers"
+)
+
+type webhookSubscriptionRepositoryTestSuite struct {
+	suite.Suite
+	ctx                           context.Context
+	webhookSubscriptionRepository WebhookSubscriptionRepository
+}
+
+func TestWebhookSubscriptionRepositoryTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(webhookSubscriptionRepositoryTestSuite))
+}
+
+func (s *webhookSubscriptionRepositoryTestSuite) SetupTest() {
+	s.ctx = context.Background()
+
+	dbconn, err := gorm.Open(
+		sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", uuid.NewString())),
+		&gorm.Config{Logger: logger.Default.LogMode(logger.Silent), TranslateError: true},
+	)
+	s.Require().NoError(err)
+	s.Require().NoError(providers.Migrate(dbconn))
+
+	s.webhookSubscriptionRepository = NewWebhookSubscriptionRepository(dbconn)
+}
+
+func (s *webhookSubscriptionRepositoryTestSuite) create(eventTypes ...string) *models.WebhookSubscription {
+	subscription, err := s.webhookSubscriptionRepository.Create(s.ctx, models.WebhookSubscription{
+		URL:        "https://partner.example.com/hooks",
+		EventTypes: eventTypes,
+		Secret:     "a-very-long-secret",
+	})
+	s.Require().NoError(err)
+
+	return subscription
+}
+
+func (s *webhookSubscriptionRepositoryTestSuite) TestFindById() {
+	subscription := s.create("user.registered", "user.deleted")
+
+	found, err := s.webhookSubscriptionRepository.FindById(s.ctx, subscription.ID.String())
+	s.NoError(err)
+	s.Equal(models.StringList{"user.registered", "user.deleted"}, found.EventTypes)
+	s.Equal(models.SecretValue("a-very-long-secret"), found.Secret)
+
+	found, err = s.webhookSubscriptionRepository.FindById(s.ctx, uuid.NewString())
+	s.NoError(err)
+	s.Nil(found)
+}
+
+func (s *webhookSubscriptionRepositoryTestSuite) TestFindByEventType() {
+	registered := s.create("user.registered")
+	both := s.create("user.registered", "user.deleted")
+
+	subscriptions, err := s.webhookSubscriptionRepository.FindByEventType(s.ctx, "user.registered")
+	s.NoError(err)
+	s.Len(subscriptions, 2)
+
+	subscriptions, err = s.webhookSubscriptionRepository.FindByEventType(s.ctx, "user.deleted")
+	s.NoError(err)
+	s.Len(subscriptions, 1)
+	s.Equal(both.ID, subscriptions[0].ID)
+
+	subscriptions, err = s.webhookSubscriptionRepository.FindByEventType(s.ctx, "user.profile_updated")
+	s.NoError(err)
+	s.Empty(subscriptions)
+
+	all, err := s.webhookSubscriptionRepository.FindAll(s.ctx)
+	s.NoError(err)
+	s.Len(all, 2)
+	s.Contains([]uuid.UUID{all[0].ID, all[1].ID}, registered.ID)
+}
+
+func (s *webhookSubscriptionRepositoryTestSuite) TestUpdate() {
+	subscription := s.create("user.registered")
+	subscription.URL = "https://partner.example.com/v2/hooks"
+	subscription.Secret = "another-long-secret"
+
+	s.NoError(s.webhookSubscriptionRepository.Update(s.ctx, *subscription))
+
+	found, err := s.webhookSubscriptionRepository.FindById(s.ctx, subscription.ID.String())
+	s.NoError(err)
+	s.Equal("https://partner.example.com/v2/hooks", found.URL)
+	s.Equal(models.SecretValue("another-long-secret"), found.Secret)
+}
+
+func (s *webhookSubscriptionRepositoryTestSuite) TestDeleteById() {
+	subscription := s.create("user.registered")
+
+	deleted, err := s.webhookSubscriptionRepository.DeleteById(s.ctx, subscription.ID.String())
+	s.NoError(err)
+	s.True(deleted)
+
+	deleted, err = s.webhookSubscriptionRepository.DeleteById(s.ctx, subscription.ID.String())
+	s.NoError(err)
+	s.False(deleted)
+}
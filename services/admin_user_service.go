@@ -0,0 +1,156 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"verifymy-golang-test/entities"
+	"verifymy-golang-test/models"
+	"verifymy-golang-test/providers"
+	"verifymy-golang-test/repositories"
+)
+
+// AdminUserService manages users on behalf of administrators. Every
+// operation leaves an audit record.
+type AdminUserService interface {
+	FindById(ctx context.Context, userId string) (*models.User, error)
+	Invite(ctx context.Context, user models.User) (*models.User, error)
+	Update(
+		ctx context.Context, user *models.User, changes map[string]interface{}, expectedVersion int64,
+	) error
+	Restore(ctx context.Context, userId string) error
+}
+
+func NewAdminUserService(
+	userRepository repositories.UserRepository,
+	eventPublisher EventPublisher,
+	auditService AuditService,
+	mailer providers.Mailer,
+) AdminUserService {
+	return &adminUserService{
+		userRepository: userRepository,
+		eventPublisher: eventPublisher,
+		auditService:   auditService,
+		mailer:         mailer,
+	}
+}
+
+type adminUserService struct {
+	userRepository repositories.UserRepository
+	eventPublisher EventPublisher
+	auditService   AuditService
+	mailer         providers.Mailer
+}
+
+// FindById also finds soft deleted users
+func (s *adminUserService) FindById(
+	ctx context.Context, userId string,
+) (*models.User, error) {
+	user, err := s.userRepository.FindById(ctx, userId, repositories.WithDeleted)
+	if err != nil {
+		return nil, err
+	} else if user == nil {
+		return nil, entities.NewItemNotFoundError("User", userId)
+	}
+
+	if err := s.auditService.Record(
+		ctx, AuditActionUserViewed, auditTargetUser, userId, nil,
+	); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// Invite creates a user without a password and emails them a link to choose
+// one, see AuthService.AcceptInvitation.
+func (s *adminUserService) Invite(
+	ctx context.Context, user models.User,
+) (*models.User, error) {
+	foundUser, err := s.userRepository.FindByEmail(ctx, user.Email)
+	if err != nil {
+		return nil, err
+	} else if foundUser != nil {
+		return nil, entities.NewEmailAlreadyInUseError(user.Email)
+	}
+
+	user.Password = ""
+	var invitedUser *models.User
+	if err := s.eventPublisher.Publish(
+		ctx, func(ctx context.Context) ([]entities.DomainEvent, error) {
+			invitedUser, err = s.userRepository.Create(ctx, user)
+			if err != nil {
+				return nil, err
+			}
+
+			return []entities.DomainEvent{registeredEvent(invitedUser, true)}, nil
+		},
+	); err != nil {
+		return nil, err
+	}
+
+	token, err := newInvitationToken(invitedUser)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.mailer.Send(ctx, providers.Email{
+		To:      invitedUser.Email,
+		Subject: "You have been invited",
+		Body: fmt.Sprintf(
+			"Hello %s,\n\nAn account was created for you. Choose your password at %s\n",
+			invitedUser.Name, invitationURL(token),
+		),
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := s.auditService.Record(
+		ctx,
+		AuditActionUserInvited,
+		auditTargetUser,
+		invitedUser.ID.String(),
+		map[string]interface{}{"role": invitedUser.Role},
+	); err != nil {
+		return nil, err
+	}
+
+	return invitedUser, nil
+}
+
+// Update writes changes, keyed by column, to the user. The audit record
+// holds the diff of the changes, with secrets redacted.
+func (s *adminUserService) Update(
+	ctx context.Context, user *models.User, changes map[string]interface{}, expectedVersion int64,
+) error {
+	if err := s.eventPublisher.Publish(
+		ctx, func(ctx context.Context) ([]entities.DomainEvent, error) {
+			if err := s.userRepository.UpdateColumnsByUserId(
+				ctx, user.ID.String(), changes, expectedVersion,
+			); err != nil {
+				return nil, err
+			}
+
+			return profileUpdatedEvents(user.ID.String(), changes), nil
+		},
+	); err != nil {
+		return err
+	}
+
+	return s.auditService.RecordChanges(
+		ctx, AuditActionUserUpdated, auditTargetUser, user.ID.String(), user, changes,
+	)
+}
+
+func (s *adminUserService) Restore(ctx context.Context, userId string) error {
+	restored, err := s.userRepository.Restore(ctx, userId)
+	if err != nil {
+		return err
+	} else if !restored {
+		return entities.NewItemNotFoundError("Deleted user", userId)
+	}
+
+	return s.auditService.Record(
+		ctx, AuditActionUserRestored, auditTargetUser, userId, nil,
+	)
+}
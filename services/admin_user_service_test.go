@@ -0,0 +1,321 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	"verifymy-golang-test/entities"
+	mock_providers "verifymy-golang-test/mocks/providers"
+	mock_repositories "verifymy-golang-test/mocks/repositories"
+	mock_services "verifymy-golang-test/mocks/services"
+	"verifymy-golang-test/models"
+	"verifymy-golang-test/providers"
+)
+
+type adminUserServiceTestSuite struct {
+	suite.Suite
+	ctrl                      *gomock.Controller
+	ctx                       context.Context
+	userRepositoryMock        *mock_repositories.MockUserRepository
+	outboxEventRepositoryMock *mock_repositories.MockOutboxEventRepository
+	auditServiceMock          *mock_services.MockAuditService
+	mailerMock                *mock_providers.MockMailer
+	service                   AdminUserService
+}
+
+func TestAdminUserServiceTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(adminUserServiceTestSuite))
+}
+
+func (s *adminUserServiceTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.ctx = context.Background()
+	s.userRepositoryMock = mock_repositories.NewMockUserRepository(s.ctrl)
+	s.outboxEventRepositoryMock = mock_repositories.NewMockOutboxEventRepository(s.ctrl)
+	s.auditServiceMock = mock_services.NewMockAuditService(s.ctrl)
+	s.mailerMock = mock_providers.NewMockMailer(s.ctrl)
+
+	transactorMock := mock_repositories.NewMockTransactor(s.ctrl)
+	transactorMock.EXPECT().WithinTransaction(
+		gomock.Any(), gomock.Any(),
+	).DoAndReturn(runWithinTransaction).AnyTimes()
+
+	s.service = NewAdminUserService(
+		s.userRepositoryMock,
+		NewEventPublisher(transactorMock, s.outboxEventRepositoryMock),
+		s.auditServiceMock,
+		s.mailerMock,
+	)
+}
+
+func (s *adminUserServiceTestSuite) TestFindById() {
+	userId := uuid.New()
+	user := &models.User{ID: userId}
+
+	tests := []struct {
+		description      string
+		findByIdResponse *models.User
+		findByIdError    error
+		recordError      error
+		expectedError    string
+	}{
+		{
+			description:      "Success",
+			findByIdResponse: user,
+		},
+		{
+			description:   "User not found",
+			expectedError: "User not found",
+		},
+		{
+			description:   "Error finding user",
+			findByIdError: errors.New("error finding user"),
+			expectedError: "error finding user",
+		},
+		{
+			description:      "Error recording audit event",
+			findByIdResponse: user,
+			recordError:      errors.New("error recording audit event"),
+			expectedError:    "error recording audit event",
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			s.SetupTest()
+
+			s.userRepositoryMock.EXPECT().FindById(s.ctx, userId.String(), gomock.Any()).Return(
+				test.findByIdResponse, test.findByIdError,
+			)
+			if test.findByIdResponse != nil {
+				s.auditServiceMock.EXPECT().Record(
+					s.ctx, AuditActionUserViewed, "user", userId.String(), nil,
+				).Return(test.recordError)
+			}
+
+			foundUser, err := s.service.FindById(s.ctx, userId.String())
+			if test.expectedError != "" {
+				s.ErrorContains(err, test.expectedError)
+				s.Nil(foundUser)
+			} else {
+				s.NoError(err)
+				s.Equal(user, foundUser)
+			}
+		})
+	}
+}
+
+func (s *adminUserServiceTestSuite) TestInvite() {
+	userId := uuid.New()
+	payload := models.User{
+		Name:     "Mary Jane",
+		Email:    "mary.jane@nyork.co",
+		Password: "chosen-by-admin",
+		Role:     models.RoleAdmin,
+	}
+	createdUser := &models.User{
+		ID:      userId,
+		Name:    "Mary Jane",
+		Email:   "mary.jane@nyork.co",
+		Role:    models.RoleAdmin,
+		Version: 1,
+	}
+
+	tests := []struct {
+		description         string
+		findByEmailResponse *models.User
+		createError         error
+		sendError           error
+		recordError         error
+		expectedError       string
+	}{
+		{
+			description: "Success",
+		},
+		{
+			description:         "E-mail already in use",
+			findByEmailResponse: &models.User{},
+			expectedError:       "e-mail is already in use",
+		},
+		{
+			description:   "Error creating user",
+			createError:   errors.New("error creating user"),
+			expectedError: "error creating user",
+		},
+		{
+			description:   "Error sending invitation",
+			sendError:     errors.New("error sending invitation"),
+			expectedError: "error sending invitation",
+		},
+		{
+			description:   "Error recording audit event",
+			recordError:   errors.New("error recording audit event"),
+			expectedError: "error recording audit event",
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			s.SetupTest()
+
+			s.userRepositoryMock.EXPECT().FindByEmail(s.ctx, payload.Email).Return(
+				test.findByEmailResponse, nil,
+			)
+			if test.findByEmailResponse == nil {
+				invitedUser := payload
+				invitedUser.Password = ""
+
+				createResponse := createdUser
+				if test.createError != nil {
+					createResponse = nil
+				}
+				s.userRepositoryMock.EXPECT().Create(s.ctx, invitedUser).Return(
+					createResponse, test.createError,
+				)
+			}
+			if test.findByEmailResponse == nil && test.createError == nil {
+				s.outboxEventRepositoryMock.EXPECT().Create(s.ctx, outboxEventsOf(
+					entities.UserRegistered{
+						UserID:  userId.String(),
+						Role:    models.RoleAdmin,
+						Invited: true,
+					},
+				)).Return(nil)
+				s.mailerMock.EXPECT().Send(s.ctx, gomock.Any()).DoAndReturn(
+					func(ctx context.Context, email providers.Email) error {
+						s.Equal("mary.jane@nyork.co", email.To)
+						s.True(strings.Contains(email.Body, "?token="))
+
+						return test.sendError
+					},
+				)
+			}
+			if test.findByEmailResponse == nil && test.createError == nil && test.sendError == nil {
+				s.auditServiceMock.EXPECT().Record(
+					s.ctx,
+					AuditActionUserInvited,
+					"user",
+					userId.String(),
+					map[string]interface{}{"role": models.RoleAdmin},
+				).Return(test.recordError)
+			}
+
+			user, err := s.service.Invite(s.ctx, payload)
+			if test.expectedError != "" {
+				s.ErrorContains(err, test.expectedError)
+				s.Nil(user)
+			} else {
+				s.NoError(err)
+				s.Equal(createdUser, user)
+			}
+		})
+	}
+}
+
+func (s *adminUserServiceTestSuite) TestUpdate() {
+	userId := uuid.New()
+	user := &models.User{ID: userId, Role: models.RoleUser}
+	changes := map[string]interface{}{"role": models.RoleAdmin, "address": ""}
+
+	tests := []struct {
+		description   string
+		updateError   error
+		recordError   error
+		expectedError string
+	}{
+		{
+			description: "Success",
+		},
+		{
+			description:   "Error updating user",
+			updateError:   entities.NewPreconditionFailedError(),
+			expectedError: "precondition failed",
+		},
+		{
+			description:   "Error recording audit event",
+			recordError:   errors.New("error recording audit event"),
+			expectedError: "error recording audit event",
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			s.SetupTest()
+
+			s.userRepositoryMock.EXPECT().UpdateColumnsByUserId(
+				s.ctx, userId.String(), changes, int64(2),
+			).Return(test.updateError)
+			if test.updateError == nil {
+				s.outboxEventRepositoryMock.EXPECT().Create(s.ctx, outboxEventsOf(
+					entities.UserProfileUpdated{
+						UserID: userId.String(), Fields: []string{"address", "role"},
+					},
+				)).Return(nil)
+				s.auditServiceMock.EXPECT().RecordChanges(
+					s.ctx, AuditActionUserUpdated, "user", userId.String(), user, changes,
+				).Return(test.recordError)
+			}
+
+			err := s.service.Update(s.ctx, user, changes, 2)
+			if test.expectedError != "" {
+				s.ErrorContains(err, test.expectedError)
+			} else {
+				s.NoError(err)
+			}
+		})
+	}
+}
+
+func (s *adminUserServiceTestSuite) TestRestore() {
+	userId := uuid.New()
+
+	tests := []struct {
+		description   string
+		restored      bool
+		restoreError  error
+		expectedError string
+	}{
+		{
+			description: "Success",
+			restored:    true,
+		},
+		{
+			description:   "Deleted user not found",
+			expectedError: "Deleted user not found",
+		},
+		{
+			description:   "Error restoring user",
+			restoreError:  errors.New("error restoring user"),
+			expectedError: "error restoring user",
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			s.SetupTest()
+
+			s.userRepositoryMock.EXPECT().Restore(s.ctx, userId.String()).Return(
+				test.restored, test.restoreError,
+			)
+			if test.restored {
+				s.auditServiceMock.EXPECT().Record(
+					s.ctx, AuditActionUserRestored, "user", userId.String(), nil,
+				).Return(nil)
+			}
+
+			err := s.service.Restore(s.ctx, userId.String())
+			if test.expectedError != "" {
+				s.ErrorContains(err, test.expectedError)
+			} else {
+				s.NoError(err)
+			}
+		})
+	}
+}
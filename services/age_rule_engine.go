@@ -0,0 +1,137 @@
+package services
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"verifymy-golang-test/entities"
+	"verifymy-golang-test/models"
+	"verifymy-golang-test/providers"
+)
+
+const defaultAgeRulesFile = "config/age_rules.yaml"
+
+var countryCodePattern = regexp.MustCompile(`^[A-Z]{2}$`)
+
+// AgeRuleEngine resolves the minimum age and verification methods that apply
+// to a country and category of content
+type AgeRuleEngine interface {
+	Resolve(country string, category string) (*entities.AgeRule, error)
+}
+
+// NewAgeRuleEngine loads the rules from the YAML file at AGE_RULES_FILE,
+// config/age_rules.yaml by default. Invalid rules keep the application from
+// starting.
+func NewAgeRuleEngine() (AgeRuleEngine, error) {
+	path := os.Getenv("AGE_RULES_FILE")
+	if path == "" {
+		path = defaultAgeRulesFile
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading age rules: %w", err)
+	}
+
+	rules, err := parseAgeRules(content)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return newAgeRuleEngine(rules), nil
+}
+
+type ageRuleEngine struct {
+	// rules are keyed by category and then by country
+	rules map[string]map[string]entities.AgeRule
+}
+
+func newAgeRuleEngine(rules []entities.AgeRule) AgeRuleEngine {
+	engine := &ageRuleEngine{rules: map[string]map[string]entities.AgeRule{}}
+	for _, rule := range rules {
+		if engine.rules[rule.Category] == nil {
+			engine.rules[rule.Category] = map[string]entities.AgeRule{}
+		}
+
+		engine.rules[rule.Category][rule.Country] = rule
+	}
+
+	return engine
+}
+
+// Resolve picks the rule of the country for the category, falling back to
+// the rule for any country. Countries are matched regardless of case, and
+// leaving it empty resolves the rule for any country.
+func (e *ageRuleEngine) Resolve(country string, category string) (*entities.AgeRule, error) {
+	country = strings.ToUpper(strings.TrimSpace(country))
+	if country == "" {
+		country = entities.AnyCountry
+	} else if country != entities.AnyCountry && !countryCodePattern.MatchString(country) {
+		return nil, entities.NewInvalidParameterError(
+			"country", "must be an ISO 3166-1 alpha-2 code",
+		)
+	}
+
+	rules, ok := e.rules[category]
+	if !ok {
+		return nil, entities.NewItemNotFoundError("Age rule", category)
+	}
+
+	rule, ok := rules[country]
+	if !ok {
+		if rule, ok = rules[entities.AnyCountry]; !ok {
+			return nil, entities.NewItemNotFoundError("Age rule", country+"/"+category)
+		}
+	}
+
+	return &rule, nil
+}
+
+// parseAgeRules reads rules from YAML, checking that each is complete, only
+// accepts known methods and is the only one for its country and category
+func parseAgeRules(content []byte) ([]entities.AgeRule, error) {
+	var config struct {
+		Rules []entities.AgeRule `yaml:"rules"`
+	}
+	if err := yaml.Unmarshal(content, &config); err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{}
+	for i, rule := range config.Rules {
+		rule.Country = strings.ToUpper(rule.Country)
+		config.Rules[i].Country = rule.Country
+
+		if rule.Country != entities.AnyCountry && !countryCodePattern.MatchString(rule.Country) {
+			return nil, fmt.Errorf("rule %d: invalid country %q", i+1, rule.Country)
+		} else if rule.Category == "" {
+			return nil, fmt.Errorf("rule %d: category is required", i+1)
+		} else if rule.MinimumAge < minAgeVerificationAgeOver ||
+			rule.MinimumAge > maxAgeVerificationAgeOver {
+			return nil, fmt.Errorf(
+				"rule %d: minimum_age must be between %d and %d",
+				i+1, minAgeVerificationAgeOver, maxAgeVerificationAgeOver,
+			)
+		} else if len(rule.Methods) == 0 {
+			return nil, fmt.Errorf("rule %d: methods are required", i+1)
+		}
+
+		for _, method := range rule.Methods {
+			if !models.StringList(providers.AgeVerificationMethods).Includes(method) {
+				return nil, fmt.Errorf("rule %d: unknown method %q", i+1, method)
+			}
+		}
+
+		key := rule.Country + "/" + rule.Category
+		if seen[key] {
+			return nil, fmt.Errorf("rule %d: duplicate rule for %s", i+1, key)
+		}
+		seen[key] = true
+	}
+
+	return config.Rules, nil
+}
@@ -0,0 +1,194 @@
+package services
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"verifymy-golang-test/entities"
+)
+
+type ageRuleEngineTestSuite struct {
+	suite.Suite
+	engine AgeRuleEngine
+}
+
+func TestAgeRuleEngineTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(ageRuleEngineTestSuite))
+}
+
+func (s *ageRuleEngineTestSuite) SetupTest() {
+	s.engine = newAgeRuleEngine([]entities.AgeRule{
+		{
+			Country:    entities.AnyCountry,
+			Category:   "digital_consent",
+			MinimumAge: 16,
+			Methods:    []string{"document"},
+		},
+		{Country: "US", Category: "digital_consent", MinimumAge: 13, Methods: []string{"document"}},
+		{Country: "DE", Category: "adult_content", MinimumAge: 18, Methods: []string{"document"}},
+	})
+}
+
+func (s *ageRuleEngineTestSuite) TestResolve() {
+	tests := []struct {
+		description   string
+		country       string
+		category      string
+		expectedRule  *entities.AgeRule
+		expectedError error
+	}{
+		{
+			description: "Country rule",
+			country:     "us",
+			category:    "digital_consent",
+			expectedRule: &entities.AgeRule{
+				Country: "US", Category: "digital_consent", MinimumAge: 13, Methods: []string{"document"},
+			},
+		},
+		{
+			description: "Fallback to any country",
+			country:     "FR",
+			category:    "digital_consent",
+			expectedRule: &entities.AgeRule{
+				Country: "*", Category: "digital_consent", MinimumAge: 16, Methods: []string{"document"},
+			},
+		},
+		{
+			description: "No country",
+			category:    "digital_consent",
+			expectedRule: &entities.AgeRule{
+				Country: "*", Category: "digital_consent", MinimumAge: 16, Methods: []string{"document"},
+			},
+		},
+		{
+			description: "Invalid country",
+			country:     "USA",
+			category:    "digital_consent",
+			expectedError: entities.NewInvalidParameterError(
+				"country", "must be an ISO 3166-1 alpha-2 code",
+			),
+		},
+		{
+			description:   "Unknown category",
+			country:       "US",
+			category:      "gambling",
+			expectedError: entities.NewItemNotFoundError("Age rule", "gambling"),
+		},
+		{
+			description:   "No rule for country",
+			country:       "FR",
+			category:      "adult_content",
+			expectedError: entities.NewItemNotFoundError("Age rule", "FR/adult_content"),
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			rule, err := s.engine.Resolve(test.country, test.category)
+
+			s.Equal(test.expectedRule, rule)
+			s.Equal(test.expectedError, err)
+		})
+	}
+}
+
+func (s *ageRuleEngineTestSuite) TestParseAgeRules() {
+	tests := []struct {
+		description   string
+		content       string
+		expectedRules []entities.AgeRule
+		expectedError error
+	}{
+		{
+			description: "Valid",
+			content: `
+rules:
+  - country: gb
+    category: alcohol
+    minimum_age: 18
+    methods: [document, credit_card]
+`,
+			expectedRules: []entities.AgeRule{
+				{
+					Country:    "GB",
+					Category:   "alcohol",
+					MinimumAge: 18,
+					Methods:    []string{"document", "credit_card"},
+				},
+			},
+		},
+		{
+			description: "Invalid country",
+			content: `
+rules:
+  - {country: GBR, category: alcohol, minimum_age: 18, methods: [document]}
+`,
+			expectedError: errors.New(`rule 1: invalid country "GBR"`),
+		},
+		{
+			description: "Missing category",
+			content: `
+rules:
+  - {country: GB, minimum_age: 18, methods: [document]}
+`,
+			expectedError: errors.New("rule 1: category is required"),
+		},
+		{
+			description: "Minimum age out of range",
+			content: `
+rules:
+  - {country: GB, category: alcohol, minimum_age: 0, methods: [document]}
+`,
+			expectedError: errors.New("rule 1: minimum_age must be between 1 and 100"),
+		},
+		{
+			description: "Missing methods",
+			content: `
+rules:
+  - {country: GB, category: alcohol, minimum_age: 18}
+`,
+			expectedError: errors.New("rule 1: methods are required"),
+		},
+		{
+			description: "Unknown method",
+			content: `
+rules:
+  - {country: GB, category: alcohol, minimum_age: 18, methods: [palm_reading]}
+`,
+			expectedError: errors.New(`rule 1: unknown method "palm_reading"`),
+		},
+		{
+			description: "Duplicate rule",
+			content: `
+rules:
+  - {country: GB, category: alcohol, minimum_age: 18, methods: [document]}
+  - {country: gb, category: alcohol, minimum_age: 21, methods: [document]}
+`,
+			expectedError: errors.New("rule 2: duplicate rule for GB/alcohol"),
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			rules, err := parseAgeRules([]byte(test.content))
+
+			s.Equal(test.expectedError, err)
+			if test.expectedError == nil {
+				s.Equal(test.expectedRules, rules)
+			}
+		})
+	}
+}
+
+func (s *ageRuleEngineTestSuite) TestDefaultRulesAreValid() {
+	content, err := os.ReadFile("../" + defaultAgeRulesFile)
+	s.Require().NoError(err)
+
+	_, err = parseAgeRules(content)
+
+	s.NoError(err)
+}
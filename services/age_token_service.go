@@ -0,0 +1,184 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+
+	"verifymy-golang-test/common"
+	"verifymy-golang-test/entities"
+	"verifymy-golang-test/models"
+	"verifymy-golang-test/providers"
+	"verifymy-golang-test/repositories"
+)
+
+const (
+	// AgeTokenType is the typ header of age tokens
+	AgeTokenType = "age+jwt"
+
+	ageTokenClaimPrefix     = "age_over_"
+	defaultAgeTokenLifetime = time.Hour
+	defaultIssuerURL        = "http://localhost:8080"
+)
+
+// AgeTokenService issues minimal-disclosure age tokens: JWTs telling a
+// relying party the user is over an age, like {"age_over_18": true}, without
+// identifying them. Tokens are issued to registered clients, which check them
+// offline against the JWKS, or through Verify.
+type AgeTokenService interface {
+	Issue(ctx context.Context, request entities.AgeTokenRequest) (*entities.AgeToken, error)
+	Verify(
+		ctx context.Context, verification entities.AgeTokenVerification,
+	) (map[string]interface{}, error)
+}
+
+// NewAgeTokenService issues tokens from ISSUER_URL lasting
+// AGE_TOKEN_LIFETIME, one hour by default
+func NewAgeTokenService(
+	ageVerificationService AgeVerificationService,
+	signingKeys providers.SigningKeys,
+	clientRepository repositories.ClientRepository,
+) (AgeTokenService, error) {
+	lifetime := defaultAgeTokenLifetime
+	if value := os.Getenv("AGE_TOKEN_LIFETIME"); value != "" {
+		var err error
+		if lifetime, err = time.ParseDuration(value); err != nil {
+			return nil, fmt.Errorf("invalid AGE_TOKEN_LIFETIME: %w", err)
+		}
+	}
+
+	return &ageTokenService{
+		ageVerificationService: ageVerificationService,
+		signingKeys:            signingKeys,
+		clientRepository:       clientRepository,
+		issuer:                 issuerURL(),
+		lifetime:               lifetime,
+		now:                    time.Now,
+	}, nil
+}
+
+type ageTokenService struct {
+	ageVerificationService AgeVerificationService
+	signingKeys            providers.SigningKeys
+	clientRepository       repositories.ClientRepository
+	issuer                 string
+	lifetime               time.Duration
+	now                    func() time.Time
+}
+
+// issuerURL identifies this server in the tokens it signs for third parties
+func issuerURL() string {
+	issuer := os.Getenv("ISSUER_URL")
+	if issuer == "" {
+		issuer = defaultIssuerURL
+	}
+
+	return strings.TrimSuffix(issuer, "/")
+}
+
+// Issue requires the age of the signed in user to be verified, and the
+// audience to be a client granted age tokens. Tokens never outlive the
+// verification they are issued from.
+func (s *ageTokenService) Issue(
+	ctx context.Context, request entities.AgeTokenRequest,
+) (*entities.AgeToken, error) {
+	user := ctx.Value(common.AuthUser).(*models.User)
+
+	if request.Audience == nil || strings.TrimSpace(*request.Audience) == "" {
+		return nil, entities.NewValidationError("audience is required")
+	}
+
+	client, err := s.clientRepository.FindById(ctx, *request.Audience)
+	if err != nil {
+		return nil, err
+	} else if client == nil || !client.HasScope(models.ClientScopeAgeTokens) {
+		return nil, entities.NewValidationError(
+			"audience must be a client allowed to receive age tokens",
+		)
+	}
+
+	verification, err := s.ageVerificationService.FindByUserId(ctx, user.ID.String())
+	if err != nil {
+		return nil, err
+	} else if verification.Status != models.AgeVerificationStatusVerified {
+		return nil, entities.NewValidationError("age is not verified")
+	}
+
+	ageOver := verification.AgeOver
+	if request.AgeOver != nil {
+		if *request.AgeOver < minAgeVerificationAgeOver {
+			return nil, entities.NewValidationError(fmt.Sprintf(
+				"age_over must be at least %d", minAgeVerificationAgeOver,
+			))
+		} else if *request.AgeOver > verification.AgeOver {
+			return nil, entities.NewValidationError(fmt.Sprintf(
+				"age is only verified over %d", verification.AgeOver,
+			))
+		}
+
+		ageOver = *request.AgeOver
+	}
+
+	now := s.now().UTC()
+	expiresAt := now.Add(s.lifetime)
+	if verification.ExpiresAt.Valid && verification.ExpiresAt.Time.Before(expiresAt) {
+		expiresAt = verification.ExpiresAt.Time.UTC()
+	}
+
+	token, err := s.signingKeys.Sign(jwt.MapClaims{
+		"iss": s.issuer,
+		"aud": client.ID.String(),
+		"iat": now.Unix(),
+		"exp": expiresAt.Unix(),
+		"jti": uuid.New().String(),
+		fmt.Sprintf("%s%d", ageTokenClaimPrefix, ageOver): true,
+		"assurance_level": verification.AssuranceLevel,
+	}, AgeTokenType)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entities.AgeToken{Token: token, ExpiresAt: expiresAt.Unix()}, nil
+}
+
+// Verify checks the token is an age token signed by us for the
+// authenticated client and is not expired, returning its claims
+func (s *ageTokenService) Verify(
+	ctx context.Context, verification entities.AgeTokenVerification,
+) (map[string]interface{}, error) {
+	client, ok := ctx.Value(common.AuthClient).(*models.Client)
+	if !ok {
+		return nil, entities.NewInvalidClientError()
+	}
+
+	if verification.Token == nil || *verification.Token == "" {
+		return nil, entities.NewValidationError("token is required")
+	}
+
+	claims := jwt.MapClaims{}
+	if _, err := s.signingKeys.Parse(
+		*verification.Token,
+		AgeTokenType,
+		claims,
+		jwt.WithIssuer(s.issuer),
+		jwt.WithAudience(client.ID.String()),
+		jwt.WithTimeFunc(s.now),
+	); err != nil {
+		return nil, entities.NewInvalidTokenError()
+	} else if expiresAt, _ := claims.GetExpirationTime(); expiresAt == nil {
+		return nil, entities.NewInvalidTokenError()
+	}
+
+	for claim, value := range claims {
+		if strings.HasPrefix(claim, ageTokenClaimPrefix) && value == true {
+			return claims, nil
+		}
+	}
+
+	return nil, entities.NewInvalidTokenError()
+}
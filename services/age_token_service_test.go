@@ -0,0 +1,353 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	"verifymy-golang-test/common"
+	"verifymy-golang-test/entities"
+	mock_repositories "verifymy-golang-test/mocks/repositories"
+	mock_services "verifymy-golang-test/mocks/services"
+	"verifymy-golang-test/models"
+	"verifymy-golang-test/providers"
+)
+
+type ageTokenServiceTestSuite struct {
+	suite.Suite
+	ctrl                       *gomock.Controller
+	ctx                        context.Context
+	user                       *models.User
+	client                     *models.Client
+	now                        time.Time
+	signingKeys                providers.SigningKeys
+	ageVerificationServiceMock *mock_services.MockAgeVerificationService
+	clientRepositoryMock       *mock_repositories.MockClientRepository
+	service                    AgeTokenService
+}
+
+func TestAgeTokenServiceTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(ageTokenServiceTestSuite))
+}
+
+func (s *ageTokenServiceTestSuite) SetupSuite() {
+	signingKeys, err := providers.NewSigningKeys()
+	s.Require().NoError(err)
+
+	s.signingKeys = signingKeys
+}
+
+func (s *ageTokenServiceTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.user = &models.User{ID: uuid.New(), Email: "john@doe.com"}
+	s.ctx = context.WithValue(context.Background(), common.AuthUser, s.user)
+	s.now = time.Now().UTC().Truncate(time.Second)
+	s.client = &models.Client{
+		ID: uuid.New(), Scopes: models.StringList{models.ClientScopeAgeTokens},
+	}
+	s.ageVerificationServiceMock = mock_services.NewMockAgeVerificationService(s.ctrl)
+	s.clientRepositoryMock = mock_repositories.NewMockClientRepository(s.ctrl)
+
+	s.service = &ageTokenService{
+		ageVerificationService: s.ageVerificationServiceMock,
+		signingKeys:            s.signingKeys,
+		clientRepository:       s.clientRepositoryMock,
+		issuer:                 "https://verify.example.com",
+		lifetime:               time.Hour,
+		now:                    func() time.Time { return s.now },
+	}
+}
+
+func (s *ageTokenServiceTestSuite) verification(expiresAt time.Time) *models.AgeVerification {
+	return &models.AgeVerification{
+		UserID:         s.user.ID.String(),
+		Status:         models.AgeVerificationStatusVerified,
+		AssuranceLevel: models.AssuranceLevelHigh,
+		AgeOver:        21,
+		ExpiresAt:      sql.NullTime{Time: expiresAt, Valid: true},
+	}
+}
+
+func (s *ageTokenServiceTestSuite) TestIssueValidation() {
+	audience := stringPointer(s.client.ID.String())
+
+	tests := []struct {
+		description   string
+		request       entities.AgeTokenRequest
+		client        *models.Client
+		verification  *models.AgeVerification
+		expectedError error
+	}{
+		{
+			description:   "Missing audience",
+			request:       entities.AgeTokenRequest{},
+			expectedError: entities.NewValidationError("audience is required"),
+		},
+		{
+			description: "Unknown client",
+			request:     entities.AgeTokenRequest{Audience: audience},
+			expectedError: entities.NewValidationError(
+				"audience must be a client allowed to receive age tokens",
+			),
+		},
+		{
+			description: "Client without scope",
+			request:     entities.AgeTokenRequest{Audience: audience},
+			client:      &models.Client{ID: s.client.ID, Scopes: models.StringList{}},
+			expectedError: entities.NewValidationError(
+				"audience must be a client allowed to receive age tokens",
+			),
+		},
+		{
+			description: "Unverified",
+			request:     entities.AgeTokenRequest{Audience: audience},
+			client:      s.client,
+			verification: &models.AgeVerification{
+				Status: models.AgeVerificationStatusUnverified,
+			},
+			expectedError: entities.NewValidationError("age is not verified"),
+		},
+		{
+			description: "Age over verified one",
+			request: entities.AgeTokenRequest{
+				Audience: audience, AgeOver: intPointer(25),
+			},
+			client:        s.client,
+			verification:  s.verification(s.now.AddDate(1, 0, 0)),
+			expectedError: entities.NewValidationError("age is only verified over 21"),
+		},
+		{
+			description: "Age out of range",
+			request: entities.AgeTokenRequest{
+				Audience: audience, AgeOver: intPointer(0),
+			},
+			client:        s.client,
+			verification:  s.verification(s.now.AddDate(1, 0, 0)),
+			expectedError: entities.NewValidationError("age_over must be at least 1"),
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			if test.request.Audience != nil {
+				s.clientRepositoryMock.EXPECT().FindById(
+					s.ctx, s.client.ID.String(),
+				).Return(test.client, nil)
+			}
+			if test.verification != nil {
+				s.ageVerificationServiceMock.EXPECT().FindByUserId(
+					s.ctx, s.user.ID.String(),
+				).Return(test.verification, nil)
+			}
+
+			token, err := s.service.Issue(s.ctx, test.request)
+
+			s.Nil(token)
+			s.Equal(test.expectedError, err)
+		})
+	}
+}
+
+func (s *ageTokenServiceTestSuite) TestIssue() {
+	tests := []struct {
+		description       string
+		ageOver           *int
+		expiresAt         time.Time
+		expectedClaim     string
+		expectedExpiresAt time.Time
+	}{
+		{
+			description:       "Verified age",
+			expiresAt:         s.now.AddDate(1, 0, 0),
+			expectedClaim:     "age_over_21",
+			expectedExpiresAt: s.now.Add(time.Hour),
+		},
+		{
+			description:       "Lower age",
+			ageOver:           intPointer(18),
+			expiresAt:         s.now.AddDate(1, 0, 0),
+			expectedClaim:     "age_over_18",
+			expectedExpiresAt: s.now.Add(time.Hour),
+		},
+		{
+			description:       "Verification expiring first",
+			expiresAt:         s.now.Add(time.Minute),
+			expectedClaim:     "age_over_21",
+			expectedExpiresAt: s.now.Add(time.Minute),
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			s.clientRepositoryMock.EXPECT().FindById(
+				s.ctx, s.client.ID.String(),
+			).Return(s.client, nil)
+			s.ageVerificationServiceMock.EXPECT().FindByUserId(
+				s.ctx, s.user.ID.String(),
+			).Return(s.verification(test.expiresAt), nil)
+
+			token, err := s.service.Issue(s.ctx, entities.AgeTokenRequest{
+				Audience: stringPointer(s.client.ID.String()), AgeOver: test.ageOver,
+			})
+			s.Require().NoError(err)
+			s.Equal(test.expectedExpiresAt.Unix(), token.ExpiresAt)
+
+			claims := jwt.MapClaims{}
+			_, err = s.signingKeys.Parse(token.Token, AgeTokenType, claims)
+			s.Require().NoError(err)
+
+			s.Equal(true, claims[test.expectedClaim])
+			s.Equal("https://verify.example.com", claims["iss"])
+			s.Equal(s.client.ID.String(), claims["aud"])
+			s.Equal("high", claims["assurance_level"])
+			s.NotEmpty(claims["jti"])
+			for _, value := range claims {
+				s.NotEqual(s.user.ID.String(), value)
+				s.NotEqual(s.user.Email, value)
+			}
+		})
+	}
+}
+
+func (s *ageTokenServiceTestSuite) TestIssueError() {
+	s.clientRepositoryMock.EXPECT().FindById(s.ctx, s.client.ID.String()).Return(s.client, nil)
+	s.ageVerificationServiceMock.EXPECT().FindByUserId(
+		s.ctx, s.user.ID.String(),
+	).Return(nil, errors.New("database unavailable"))
+
+	token, err := s.service.Issue(s.ctx, entities.AgeTokenRequest{
+		Audience: stringPointer(s.client.ID.String()),
+	})
+
+	s.Nil(token)
+	s.Equal(errors.New("database unavailable"), err)
+}
+
+func (s *ageTokenServiceTestSuite) TestVerify() {
+	sign := func(claims jwt.MapClaims, tokenType string) *string {
+		token, err := s.signingKeys.Sign(claims, tokenType)
+		s.Require().NoError(err)
+
+		return &token
+	}
+	claims := jwt.MapClaims{
+		"iss":         "https://verify.example.com",
+		"aud":         s.client.ID.String(),
+		"exp":         s.now.Add(time.Minute).Unix(),
+		"age_over_18": true,
+	}
+	withClaims := func(changes jwt.MapClaims) jwt.MapClaims {
+		changed := jwt.MapClaims{}
+		for claim, value := range claims {
+			changed[claim] = value
+		}
+		for claim, value := range changes {
+			if value == nil {
+				delete(changed, claim)
+			} else {
+				changed[claim] = value
+			}
+		}
+
+		return changed
+	}
+
+	tests := []struct {
+		description   string
+		verification  entities.AgeTokenVerification
+		expectedError error
+	}{
+		{
+			description: "Valid",
+			verification: entities.AgeTokenVerification{
+				Token: sign(claims, AgeTokenType),
+			},
+		},
+		{
+			description:   "Missing token",
+			verification:  entities.AgeTokenVerification{},
+			expectedError: entities.NewValidationError("token is required"),
+		},
+		{
+			description: "Other audience",
+			verification: entities.AgeTokenVerification{
+				Token: sign(withClaims(jwt.MapClaims{"aud": uuid.NewString()}), AgeTokenType),
+			},
+			expectedError: entities.NewInvalidTokenError(),
+		},
+		{
+			description: "Other issuer",
+			verification: entities.AgeTokenVerification{
+				Token: sign(withClaims(jwt.MapClaims{"iss": "https://evil.example.com"}), AgeTokenType),
+			},
+			expectedError: entities.NewInvalidTokenError(),
+		},
+		{
+			description: "Expired",
+			verification: entities.AgeTokenVerification{
+				Token: sign(withClaims(jwt.MapClaims{"exp": s.now.Add(-time.Minute).Unix()}), AgeTokenType),
+			},
+			expectedError: entities.NewInvalidTokenError(),
+		},
+		{
+			description: "Without expiry",
+			verification: entities.AgeTokenVerification{
+				Token: sign(withClaims(jwt.MapClaims{"exp": nil}), AgeTokenType),
+			},
+			expectedError: entities.NewInvalidTokenError(),
+		},
+		{
+			description: "Without age claim",
+			verification: entities.AgeTokenVerification{
+				Token: sign(withClaims(jwt.MapClaims{"age_over_18": nil}), AgeTokenType),
+			},
+			expectedError: entities.NewInvalidTokenError(),
+		},
+		{
+			description: "Other token type",
+			verification: entities.AgeTokenVerification{
+				Token: sign(claims, "JWT"),
+			},
+			expectedError: entities.NewInvalidTokenError(),
+		},
+	}
+
+	ctx := context.WithValue(context.Background(), common.AuthClient, s.client)
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			verified, err := s.service.Verify(ctx, test.verification)
+
+			s.Equal(test.expectedError, err)
+			if test.expectedError == nil {
+				s.Equal(true, verified["age_over_18"])
+			} else {
+				s.Nil(verified)
+			}
+		})
+	}
+}
+
+func (s *ageTokenServiceTestSuite) TestVerifyWithoutClient() {
+	token, err := s.signingKeys.Sign(jwt.MapClaims{
+		"iss":         "https://verify.example.com",
+		"aud":         s.client.ID.String(),
+		"exp":         s.now.Add(time.Minute).Unix(),
+		"age_over_18": true,
+	}, AgeTokenType)
+	s.Require().NoError(err)
+
+	verified, err := s.service.Verify(
+		context.Background(), entities.AgeTokenVerification{Token: &token},
+	)
+
+	s.Nil(verified)
+	s.Equal(entities.NewInvalidClientError(), err)
+}
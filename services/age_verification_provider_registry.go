@@ -0,0 +1,103 @@
+package services
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"verifymy-golang-test/providers"
+)
+
+// AgeVerificationProviderRegistry holds the providers users can verify their
+// age with. When several providers verify through the same method, the first
+// one enabled is used for it.
+type AgeVerificationProviderRegistry interface {
+	Names() []interface{}
+	Methods() []interface{}
+	Find(name string) (providers.AgeVerificationProvider, bool)
+	FindByMethod(method string) (providers.AgeVerificationProvider, bool)
+}
+
+type ageVerificationProviderRegistry struct {
+	providers map[string]providers.AgeVerificationProvider
+	names     []interface{}
+	methods   map[string]providers.AgeVerificationProvider
+	// methodNames keeps methods in the order providers were enabled
+	methodNames []interface{}
+}
+
+// NewAgeVerificationProviderRegistry enables the providers listed, comma
+// separated, in AGE_VERIFICATION_PROVIDERS. None is enabled when it is not
+// set, so ages can't be verified by a provider nobody chose.
+func NewAgeVerificationProviderRegistry(
+	ageVerificationProviders []providers.AgeVerificationProvider,
+) (AgeVerificationProviderRegistry, error) {
+	return newAgeVerificationProviderRegistry(
+		ageVerificationProviders, os.Getenv("AGE_VERIFICATION_PROVIDERS"),
+	)
+}
+
+func newAgeVerificationProviderRegistry(
+	ageVerificationProviders []providers.AgeVerificationProvider, names string,
+) (AgeVerificationProviderRegistry, error) {
+	providersByName := map[string]providers.AgeVerificationProvider{}
+	for _, provider := range ageVerificationProviders {
+		providersByName[provider.Name()] = provider
+	}
+
+	registry := &ageVerificationProviderRegistry{
+		providers:   map[string]providers.AgeVerificationProvider{},
+		names:       []interface{}{},
+		methods:     map[string]providers.AgeVerificationProvider{},
+		methodNames: []interface{}{},
+	}
+	if names == "" {
+		return registry, nil
+	}
+
+	for _, name := range strings.Split(names, ",") {
+		name = strings.TrimSpace(name)
+		provider, ok := providersByName[name]
+		if !ok {
+			return nil, fmt.Errorf(
+				"unknown age verification provider %q in AGE_VERIFICATION_PROVIDERS", name,
+			)
+		}
+
+		registry.enable(provider)
+	}
+
+	return registry, nil
+}
+
+func (r *ageVerificationProviderRegistry) enable(provider providers.AgeVerificationProvider) {
+	r.providers[provider.Name()] = provider
+	r.names = append(r.names, provider.Name())
+
+	if _, ok := r.methods[provider.Method()]; !ok {
+		r.methods[provider.Method()] = provider
+		r.methodNames = append(r.methodNames, provider.Method())
+	}
+}
+
+func (r *ageVerificationProviderRegistry) Names() []interface{} {
+	return r.names
+}
+
+func (r *ageVerificationProviderRegistry) Methods() []interface{} {
+	return r.methodNames
+}
+
+func (r *ageVerificationProviderRegistry) Find(
+	name string,
+) (providers.AgeVerificationProvider, bool) {
+	provider, ok := r.providers[name]
+	return provider, ok
+}
+
+func (r *ageVerificationProviderRegistry) FindByMethod(
+	method string,
+) (providers.AgeVerificationProvider, bool) {
+	provider, ok := r.methods[method]
+	return provider, ok
+}
@@ -0,0 +1,113 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/suite"
+
+	mock_providers "verifymy-golang-test/mocks/providers"
+	"verifymy-golang-test/providers"
+)
+
+type ageVerificationProviderRegistryTestSuite struct {
+	suite.Suite
+	ctrl      *gomock.Controller
+	simulator *mock_providers.MockAgeVerificationProvider
+	document  *mock_providers.MockAgeVerificationProvider
+}
+
+func TestAgeVerificationProviderRegistryTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(ageVerificationProviderRegistryTestSuite))
+}
+
+func (s *ageVerificationProviderRegistryTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.simulator = mock_providers.NewMockAgeVerificationProvider(s.ctrl)
+	s.simulator.EXPECT().Name().Return("simulator").AnyTimes()
+	s.simulator.EXPECT().Method().Return(providers.AgeVerificationMethodDocument).AnyTimes()
+	s.document = mock_providers.NewMockAgeVerificationProvider(s.ctrl)
+	s.document.EXPECT().Name().Return("document_check").AnyTimes()
+	s.document.EXPECT().Method().Return(providers.AgeVerificationMethodDocument).AnyTimes()
+}
+
+func (s *ageVerificationProviderRegistryTestSuite) TestNewAgeVerificationProviderRegistry() {
+	tests := []struct {
+		description     string
+		names           string
+		expectedNames   []interface{}
+		expectedMethods []interface{}
+		expectedError   error
+	}{
+		{
+			description:     "No provider unless listed",
+			expectedNames:   []interface{}{},
+			expectedMethods: []interface{}{},
+		},
+		{
+			description:     "First listed provider of a method",
+			names:           "simulator,document_check",
+			expectedNames:   []interface{}{"simulator", "document_check"},
+			expectedMethods: []interface{}{"document"},
+		},
+		{
+			description:     "Listed providers",
+			names:           "document_check",
+			expectedNames:   []interface{}{"document_check"},
+			expectedMethods: []interface{}{"document"},
+		},
+		{
+			description: "Unknown provider",
+			names:       "simulator, mobile_lookup",
+			expectedError: errors.New(
+				`unknown age verification provider "mobile_lookup" in AGE_VERIFICATION_PROVIDERS`,
+			),
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			registry, err := newAgeVerificationProviderRegistry(
+				[]providers.AgeVerificationProvider{s.simulator, s.document}, test.names,
+			)
+
+			s.Equal(test.expectedError, err)
+			if test.expectedError == nil {
+				s.Equal(test.expectedNames, registry.Names())
+				s.Equal(test.expectedMethods, registry.Methods())
+			}
+		})
+	}
+}
+
+func (s *ageVerificationProviderRegistryTestSuite) TestFind() {
+	registry, err := newAgeVerificationProviderRegistry(
+		[]providers.AgeVerificationProvider{s.simulator, s.document}, "simulator",
+	)
+	s.Require().NoError(err)
+
+	provider, ok := registry.Find("simulator")
+	s.True(ok)
+	s.Equal(s.simulator, provider)
+
+	provider, ok = registry.Find("document_check")
+	s.False(ok)
+	s.Nil(provider)
+}
+
+func (s *ageVerificationProviderRegistryTestSuite) TestFindByMethod() {
+	registry, err := newAgeVerificationProviderRegistry(
+		[]providers.AgeVerificationProvider{s.simulator, s.document}, "document_check,simulator",
+	)
+	s.Require().NoError(err)
+
+	provider, ok := registry.FindByMethod(providers.AgeVerificationMethodDocument)
+	s.True(ok)
+	s.Equal(s.document, provider)
+
+	provider, ok = registry.FindByMethod(providers.AgeVerificationMethodCreditCard)
+	s.False(ok)
+	s.Nil(provider)
+}
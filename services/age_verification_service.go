@@ -0,0 +1,187 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"verifymy-golang-test/models"
+	"verifymy-golang-test/repositories"
+)
+
+// AgeVerificationService tells how far the age of a user has been verified,
+// regardless of the date of birth they declared.
+type AgeVerificationService interface {
+	FindByUserId(ctx context.Context, userId string) (*models.AgeVerification, error)
+	FindByUserIds(
+		ctx context.Context, userIds []string,
+	) (map[string]*models.AgeVerification, error)
+}
+
+func NewAgeVerificationService(
+	ageVerificationRepository repositories.AgeVerificationRepository,
+) AgeVerificationService {
+	return &ageVerificationService{
+		ageVerificationRepository: ageVerificationRepository,
+		now:                       time.Now,
+	}
+}
+
+type ageVerificationService struct {
+	ageVerificationRepository repositories.AgeVerificationRepository
+	now                       func() time.Time
+}
+
+// FindByUserId returns an unverified status for users never verified. An
+// expired verification is reported as unverified too, keeping when it was
+// verified and expired.
+func (s *ageVerificationService) FindByUserId(
+	ctx context.Context, userId string,
+) (*models.AgeVerification, error) {
+	verification, err := s.ageVerificationRepository.FindByUserId(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.current(userId, verification), nil
+}
+
+// FindByUserIds is FindByUserId for several users at once, keyed by user id
+func (s *ageVerificationService) FindByUserIds(
+	ctx context.Context, userIds []string,
+) (map[string]*models.AgeVerification, error) {
+	verifications, err := s.ageVerificationRepository.FindByUserIds(ctx, userIds)
+	if err != nil {
+		return nil, err
+	}
+
+	found := make(map[string]*models.AgeVerification, len(verifications))
+	for i := range verifications {
+		found[verifications[i].UserID] = &verifications[i]
+	}
+
+	current := make(map[string]*models.AgeVerification, len(userIds))
+	for _, userId := range userIds {
+		current[userId] = s.current(userId, found[userId])
+	}
+
+	return current, nil
+}
+
+// current tells the status of the stored verification of a user, which is
+// nil for users never verified
+func (s *ageVerificationService) current(
+	userId string, verification *models.AgeVerification,
+) *models.AgeVerification {
+	if verification == nil {
+		return &models.AgeVerification{
+			UserID: userId,
+			Status: models.AgeVerificationStatusUnverified,
+		}
+	}
+
+	if verification.Status == models.AgeVerificationStatusVerified &&
+		verification.Expired(s.now()) {
+		verification.Status = models.AgeVerificationStatusUnverified
+	}
+
+	return verification
+}
+
+// ageVerificationIncluder embeds the age verification status of users
+type ageVerificationIncluder struct {
+	ageVerificationService AgeVerificationService
+}
+
+func NewAgeVerificationIncluder(
+	ageVerificationService AgeVerificationService,
+) UserIncluder {
+	return &ageVerificationIncluder{
+		ageVerificationService: ageVerificationService,
+	}
+}
+
+func (i *ageVerificationIncluder) Name() string {
+	return "age_verification"
+}
+
+func (i *ageVerificationIncluder) Fields() []string {
+	return nil
+}
+
+func (i *ageVerificationIncluder) Include(
+	ctx context.Context, users []models.User,
+) (map[string]interface{}, error) {
+	userIds := make([]string, 0, len(users))
+	for _, user := range users {
+		userIds = append(userIds, user.ID.String())
+	}
+
+	verifications, err := i.ageVerificationService.FindByUserIds(ctx, userIds)
+	if err != nil {
+		return nil, err
+	}
+
+	included := make(map[string]interface{}, len(verifications))
+	for userId, verification := range verifications {
+		included[userId] = verification
+	}
+
+	return included, nil
+}
+
+type ageVerificationExportCollector struct {
+	ageVerificationService AgeVerificationService
+}
+
+func NewAgeVerificationExportCollector(
+	ageVerificationService AgeVerificationService,
+) DataExportCollector {
+	return &ageVerificationExportCollector{
+		ageVerificationService: ageVerificationService,
+	}
+}
+
+func (c *ageVerificationExportCollector) Name() string {
+	return "age_verification"
+}
+
+func (c *ageVerificationExportCollector) Collect(
+	ctx context.Context, user *models.User,
+) (interface{}, error) {
+	verification, err := c.ageVerificationService.FindByUserId(ctx, user.ID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	return verification, nil
+}
+
+// ageVerificationErasureHook removes the verification of an erased user
+// along with their sessions
+type ageVerificationErasureHook struct {
+	ageVerificationRepository        repositories.AgeVerificationRepository
+	ageVerificationSessionRepository repositories.AgeVerificationSessionRepository
+}
+
+func NewAgeVerificationErasureHook(
+	ageVerificationRepository repositories.AgeVerificationRepository,
+	ageVerificationSessionRepository repositories.AgeVerificationSessionRepository,
+) ErasureHook {
+	return &ageVerificationErasureHook{
+		ageVerificationRepository:        ageVerificationRepository,
+		ageVerificationSessionRepository: ageVerificationSessionRepository,
+	}
+}
+
+func (h *ageVerificationErasureHook) Name() string {
+	return "age_verification"
+}
+
+func (h *ageVerificationErasureHook) Erase(ctx context.Context, user *models.User) error {
+	err := h.ageVerificationSessionRepository.DeleteByUserId(ctx, user.ID.String())
+	if err != nil {
+		return err
+	}
+
+	return h.ageVerificationRepository.DeleteByUserId(ctx, user.ID.String())
+}
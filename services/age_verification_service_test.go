@@ -0,0 +1,204 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	mock_repositories "verifymy-golang-test/mocks/repositories"
+	"verifymy-golang-test/models"
+)
+
+type ageVerificationServiceTestSuite struct {
+	suite.Suite
+	ctrl                          *gomock.Controller
+	ageVerificationRepositoryMock *mock_repositories.MockAgeVerificationRepository
+	sessionRepositoryMock         *mock_repositories.MockAgeVerificationSessionRepository
+	service                       AgeVerificationService
+	ctx                           context.Context
+	user                          *models.User
+	now                           time.Time
+}
+
+func TestAgeVerificationServiceTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(ageVerificationServiceTestSuite))
+}
+
+func (s *ageVerificationServiceTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.ageVerificationRepositoryMock = mock_repositories.NewMockAgeVerificationRepository(s.ctrl)
+	s.sessionRepositoryMock = mock_repositories.NewMockAgeVerificationSessionRepository(s.ctrl)
+	s.ctx = context.Background()
+	s.user = &models.User{ID: uuid.New()}
+	s.now = time.Date(2023, 6, 30, 12, 0, 0, 0, time.UTC)
+
+	service := NewAgeVerificationService(s.ageVerificationRepositoryMock).(*ageVerificationService)
+	service.now = func() time.Time { return s.now }
+	s.service = service
+}
+
+func (s *ageVerificationServiceTestSuite) TestFindByUserId() {
+	userId := s.user.ID.String()
+	verifiedAt := sql.NullTime{Time: s.now.AddDate(-1, 0, 0), Valid: true}
+
+	tests := []struct {
+		description          string
+		verification         *models.AgeVerification
+		findError            error
+		expectedVerification *models.AgeVerification
+		expectedError        error
+	}{
+		{
+			description: "Never verified",
+			expectedVerification: &models.AgeVerification{
+				UserID: userId,
+				Status: models.AgeVerificationStatusUnverified,
+			},
+		},
+		{
+			description: "Verified",
+			verification: &models.AgeVerification{
+				UserID:     userId,
+				Status:     models.AgeVerificationStatusVerified,
+				VerifiedAt: verifiedAt,
+				ExpiresAt:  sql.NullTime{Time: s.now.Add(time.Hour), Valid: true},
+			},
+			expectedVerification: &models.AgeVerification{
+				UserID:     userId,
+				Status:     models.AgeVerificationStatusVerified,
+				VerifiedAt: verifiedAt,
+				ExpiresAt:  sql.NullTime{Time: s.now.Add(time.Hour), Valid: true},
+			},
+		},
+		{
+			description: "Expired",
+			verification: &models.AgeVerification{
+				UserID:     userId,
+				Status:     models.AgeVerificationStatusVerified,
+				VerifiedAt: verifiedAt,
+				ExpiresAt:  sql.NullTime{Time: s.now, Valid: true},
+			},
+			expectedVerification: &models.AgeVerification{
+				UserID:     userId,
+				Status:     models.AgeVerificationStatusUnverified,
+				VerifiedAt: verifiedAt,
+				ExpiresAt:  sql.NullTime{Time: s.now, Valid: true},
+			},
+		},
+		{
+			description: "Failed",
+			verification: &models.AgeVerification{
+				UserID: userId,
+				Status: models.AgeVerificationStatusFailed,
+			},
+			expectedVerification: &models.AgeVerification{
+				UserID: userId,
+				Status: models.AgeVerificationStatusFailed,
+			},
+		},
+		{
+			description:   "Unexpected error",
+			findError:     errors.New("database error"),
+			expectedError: errors.New("database error"),
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			s.ageVerificationRepositoryMock.EXPECT().FindByUserId(
+				s.ctx, userId,
+			).Return(test.verification, test.findError)
+
+			verification, err := s.service.FindByUserId(s.ctx, userId)
+
+			s.Equal(test.expectedVerification, verification)
+			s.Equal(test.expectedError, err)
+		})
+	}
+}
+
+func (s *ageVerificationServiceTestSuite) TestAgeVerificationIncluder() {
+	includer := NewAgeVerificationIncluder(s.service)
+	verified := models.User{ID: uuid.New()}
+	expired := models.User{ID: uuid.New()}
+	expiresAt := sql.NullTime{Time: s.now, Valid: true}
+
+	s.ageVerificationRepositoryMock.EXPECT().FindByUserIds(
+		s.ctx, []string{verified.ID.String(), expired.ID.String(), s.user.ID.String()},
+	).Return([]models.AgeVerification{
+		{UserID: verified.ID.String(), Status: models.AgeVerificationStatusVerified},
+		{
+			UserID:    expired.ID.String(),
+			Status:    models.AgeVerificationStatusVerified,
+			ExpiresAt: expiresAt,
+		},
+	}, nil)
+
+	included, err := includer.Include(s.ctx, []models.User{verified, expired, *s.user})
+
+	s.Equal("age_verification", includer.Name())
+	s.Nil(includer.Fields())
+	s.NoError(err)
+	s.Equal(map[string]interface{}{
+		verified.ID.String(): &models.AgeVerification{
+			UserID: verified.ID.String(),
+			Status: models.AgeVerificationStatusVerified,
+		},
+		expired.ID.String(): &models.AgeVerification{
+			UserID:    expired.ID.String(),
+			Status:    models.AgeVerificationStatusUnverified,
+			ExpiresAt: expiresAt,
+		},
+		s.user.ID.String(): &models.AgeVerification{
+			UserID: s.user.ID.String(),
+			Status: models.AgeVerificationStatusUnverified,
+		},
+	}, included)
+
+	s.ageVerificationRepositoryMock.EXPECT().FindByUserIds(
+		s.ctx, []string{s.user.ID.String()},
+	).Return(nil, errors.New("database error"))
+
+	included, err = includer.Include(s.ctx, []models.User{*s.user})
+
+	s.Nil(included)
+	s.EqualError(err, "database error")
+}
+
+func (s *ageVerificationServiceTestSuite) TestAgeVerificationExportCollector() {
+	collector := NewAgeVerificationExportCollector(s.service)
+	s.Equal("age_verification", collector.Name())
+
+	s.ageVerificationRepositoryMock.EXPECT().FindByUserId(
+		s.ctx, s.user.ID.String(),
+	).Return(nil, nil)
+
+	data, err := collector.Collect(s.ctx, s.user)
+
+	s.NoError(err)
+	s.Equal(&models.AgeVerification{
+		UserID: s.user.ID.String(),
+		Status: models.AgeVerificationStatusUnverified,
+	}, data)
+}
+
+func (s *ageVerificationServiceTestSuite) TestAgeVerificationErasureHook() {
+	hook := NewAgeVerificationErasureHook(s.ageVerificationRepositoryMock, s.sessionRepositoryMock)
+	s.Equal("age_verification", hook.Name())
+
+	gomock.InOrder(
+		s.sessionRepositoryMock.EXPECT().DeleteByUserId(s.ctx, s.user.ID.String()).Return(nil),
+		s.ageVerificationRepositoryMock.EXPECT().DeleteByUserId(
+			s.ctx, s.user.ID.String(),
+		).Return(nil),
+	)
+
+	s.NoError(hook.Erase(s.ctx, s.user))
+}
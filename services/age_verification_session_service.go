@@ -0,0 +1,499 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"verifymy-golang-test/common"
+	"verifymy-golang-test/entities"
+	"verifymy-golang-test/models"
+	"verifymy-golang-test/providers"
+	"verifymy-golang-test/repositories"
+)
+
+const (
+	minAgeVerificationAgeOver               = 1
+	maxAgeVerificationAgeOver               = 100
+	defaultAgeVerificationSessionLifetime   = 30 * time.Minute
+	ageVerificationSessionsExpiryBatchSize  = 100
+	defaultAgeVerificationCallbackURL       = "http://localhost:8080/age_verifications/callbacks"
+	defaultAgeVerificationClientHourlyLimit = 1000
+)
+
+// AgeVerificationSessionService runs the sessions users verify their age
+// through. Sessions start pending and end verified, failed or expired, which
+// is then reflected on the age verification of the user.
+type AgeVerificationSessionService interface {
+	Start(
+		ctx context.Context, request entities.AgeVerificationSessionRequest,
+	) (*models.AgeVerificationSession, error)
+	FindById(ctx context.Context, id string) (*models.AgeVerificationSession, error)
+	HandleCallback(
+		ctx context.Context, providerName string, header http.Header, body []byte,
+	) (*models.AgeVerificationSession, error)
+	ExpirePending(ctx context.Context) (int64, error)
+}
+
+// NewAgeVerificationSessionService has providers call back to
+// AGE_VERIFICATION_CALLBACK_URL followed by their name. Clients can start
+// AGE_VERIFICATION_CLIENT_HOURLY_LIMIT sessions an hour, 1000 by default.
+func NewAgeVerificationSessionService(
+	transactor repositories.Transactor,
+	ageVerificationSessionRepository repositories.AgeVerificationSessionRepository,
+	ageVerificationRepository repositories.AgeVerificationRepository,
+	providerRegistry AgeVerificationProviderRegistry,
+	ageRuleEngine AgeRuleEngine,
+) (AgeVerificationSessionService, error) {
+	callbackURL := os.Getenv("AGE_VERIFICATION_CALLBACK_URL")
+	if callbackURL == "" {
+		callbackURL = defaultAgeVerificationCallbackURL
+	}
+
+	clientHourlyLimit := defaultAgeVerificationClientHourlyLimit
+	if value := os.Getenv("AGE_VERIFICATION_CLIENT_HOURLY_LIMIT"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 {
+			return nil, fmt.Errorf("invalid AGE_VERIFICATION_CLIENT_HOURLY_LIMIT %q", value)
+		}
+
+		clientHourlyLimit = parsed
+	}
+
+	return &ageVerificationSessionService{
+		transactor:                       transactor,
+		ageVerificationSessionRepository: ageVerificationSessionRepository,
+		ageVerificationRepository:        ageVerificationRepository,
+		providerRegistry:                 providerRegistry,
+		ageRuleEngine:                    ageRuleEngine,
+		callbackURL:                      strings.TrimSuffix(callbackURL, "/"),
+		clientHourlyLimit:                clientHourlyLimit,
+		now:                              time.Now,
+	}, nil
+}
+
+type ageVerificationSessionService struct {
+	transactor                       repositories.Transactor
+	ageVerificationSessionRepository repositories.AgeVerificationSessionRepository
+	ageVerificationRepository        repositories.AgeVerificationRepository
+	providerRegistry                 AgeVerificationProviderRegistry
+	ageRuleEngine                    AgeRuleEngine
+	callbackURL                      string
+	clientHourlyLimit                int
+	now                              func() time.Time
+}
+
+// Start opens a session for the signed in user with the provider of the
+// chosen method, enforcing the age rule of the country and category of
+// content. Sessions are run for the client the request is authenticated as,
+// see ClientAuthMiddleware, so they are only started from its origins and
+// return to its redirect URIs. Their age verification becomes pending,
+// unless it is already verified.
+func (s *ageVerificationSessionService) Start(
+	ctx context.Context, request entities.AgeVerificationSessionRequest,
+) (*models.AgeVerificationSession, error) {
+	user := ctx.Value(common.AuthUser).(*models.User)
+
+	provider, rule, ageOver, err := s.validate(request)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := s.validateClient(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	started, err := provider.StartSession(ctx, providers.AgeVerificationRequest{
+		UserID:      user.ID.String(),
+		DateOfBirth: time.Time(user.DateOfBirth),
+		AgeOver:     ageOver,
+		ReturnURL:   *request.ReturnURL,
+		CallbackURL: s.callbackURL + "/" + provider.Name(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := started.ExpiresAt
+	if expiresAt.IsZero() {
+		expiresAt = s.now().Add(defaultAgeVerificationSessionLifetime)
+	}
+
+	var session *models.AgeVerificationSession
+	if err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		session, err = s.ageVerificationSessionRepository.Create(ctx, models.AgeVerificationSession{
+			UserID:      user.ID.String(),
+			ClientID:    client.ID.String(),
+			Provider:    provider.Name(),
+			Reference:   started.Reference,
+			Method:      provider.Method(),
+			Country:     rule.Country,
+			Category:    rule.Category,
+			AgeOver:     ageOver,
+			RedirectURL: started.RedirectURL,
+			ExpiresAt:   expiresAt.UTC(),
+		})
+		if err != nil {
+			return err
+		}
+
+		return s.markPending(ctx, user.ID.String(), provider.Method())
+	}); err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// FindById only finds sessions of the signed in user. Pending sessions are
+// polled from their provider first; when that fails they are returned as
+// stored, for the provider may still call back.
+func (s *ageVerificationSessionService) FindById(
+	ctx context.Context, id string,
+) (*models.AgeVerificationSession, error) {
+	user := ctx.Value(common.AuthUser).(*models.User)
+
+	session, err := s.ageVerificationSessionRepository.FindById(ctx, id)
+	if err != nil {
+		return nil, err
+	} else if session == nil || session.UserID != user.ID.String() {
+		return nil, entities.NewItemNotFoundError("Age verification", id)
+	}
+
+	if session.Status != models.AgeVerificationSessionStatusPending {
+		return session, nil
+	} else if session.Expired(s.now()) {
+		return s.transition(ctx, session, providers.AgeVerificationResult{
+			Status: models.AgeVerificationSessionStatusExpired,
+		})
+	}
+
+	provider, ok := s.providerRegistry.Find(session.Provider)
+	if !ok {
+		return session, nil
+	}
+
+	result, err := provider.Poll(ctx, session.Reference)
+	if err != nil {
+		return session, nil
+	}
+
+	return s.transition(ctx, session, *result)
+}
+
+// HandleCallback completes the session a provider calls back about.
+// Callbacks are idempotent: once a session is completed, callbacks about it
+// leave it as it is. Outcomes arriving after the session expired are
+// ignored.
+func (s *ageVerificationSessionService) HandleCallback(
+	ctx context.Context, providerName string, header http.Header, body []byte,
+) (*models.AgeVerificationSession, error) {
+	provider, ok := s.providerRegistry.Find(providerName)
+	if !ok {
+		return nil, entities.NewItemNotFoundError("Age verification provider", providerName)
+	}
+
+	result, err := provider.HandleCallback(ctx, header, body)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := s.ageVerificationSessionRepository.FindByReference(
+		ctx, providerName, result.Reference,
+	)
+	if err != nil {
+		return nil, err
+	} else if session == nil {
+		return nil, entities.NewItemNotFoundError("Age verification", result.Reference)
+	}
+
+	if session.Expired(s.now()) {
+		result = &providers.AgeVerificationResult{
+			Status: models.AgeVerificationSessionStatusExpired,
+		}
+	}
+
+	return s.transition(ctx, session, *result)
+}
+
+// ExpirePending expires the sessions left pending past their expiry and
+// returns how many were. The first error is returned once the others are
+// expired.
+func (s *ageVerificationSessionService) ExpirePending(ctx context.Context) (int64, error) {
+	sessions, err := s.ageVerificationSessionRepository.FindExpired(
+		ctx, s.now(), ageVerificationSessionsExpiryBatchSize,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	var expired int64
+	var firstErr error
+	for _, session := range sessions {
+		updated, err := s.transition(ctx, &session, providers.AgeVerificationResult{
+			Status: models.AgeVerificationSessionStatusExpired,
+		})
+		if err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("age verification session %s: %w", session.ID, err)
+			}
+		} else if updated.Status == models.AgeVerificationSessionStatusExpired {
+			expired++
+		}
+	}
+
+	return expired, firstErr
+}
+
+// transition moves the session to the status of the result when its state
+// allows, and records the outcome on the age verification of the user.
+// Sessions that can't move, including those another request just moved, are
+// returned as they now are.
+func (s *ageVerificationSessionService) transition(
+	ctx context.Context,
+	session *models.AgeVerificationSession,
+	result providers.AgeVerificationResult,
+) (*models.AgeVerificationSession, error) {
+	if !session.CanTransitionTo(result.Status) {
+		return session, nil
+	}
+
+	updated := *session
+	updated.Status = result.Status
+	updated.CompletedAt = sql.NullTime{Time: s.now().UTC(), Valid: true}
+	columns := map[string]interface{}{
+		"status":       updated.Status,
+		"completed_at": updated.CompletedAt,
+	}
+	switch result.Status {
+	case models.AgeVerificationSessionStatusVerified:
+		updated.AssuranceLevel = result.AssuranceLevel
+		columns["assurance_level"] = result.AssuranceLevel
+	case models.AgeVerificationSessionStatusFailed:
+		updated.FailureReason = result.Reason
+		columns["failure_reason"] = result.Reason
+	}
+
+	if err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		moved, err := s.ageVerificationSessionRepository.UpdateColumnsById(
+			ctx, session.ID.String(), columns, session.Status,
+		)
+		if err != nil {
+			return err
+		} else if !moved {
+			current, err := s.ageVerificationSessionRepository.FindById(ctx, session.ID.String())
+			if err != nil {
+				return err
+			} else if current != nil {
+				updated = *current
+			}
+
+			return nil
+		}
+
+		return s.recordOutcome(ctx, &updated, result)
+	}); err != nil {
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+// markPending flags the age verification of the user as pending while a
+// session runs, unless it is verified and still valid
+func (s *ageVerificationSessionService) markPending(
+	ctx context.Context, userId string, method string,
+) error {
+	verification, err := s.ageVerificationRepository.FindByUserId(ctx, userId)
+	if err != nil {
+		return err
+	} else if verification == nil {
+		verification = &models.AgeVerification{UserID: userId}
+	} else if verification.Status == models.AgeVerificationStatusVerified &&
+		!verification.Expired(s.now()) {
+		return nil
+	}
+
+	verification.Status = models.AgeVerificationStatusPending
+	verification.Method = method
+	verification.AssuranceLevel = ""
+	verification.AgeOver = 0
+	verification.VerifiedAt = sql.NullTime{}
+	verification.ExpiresAt = sql.NullTime{}
+
+	_, err = s.ageVerificationRepository.Save(ctx, *verification)
+	return err
+}
+
+// recordOutcome reflects a completed session on the age verification of the
+// user. A verified session replaces whatever was verified before, while
+// failed and expired sessions only settle verifications still pending.
+func (s *ageVerificationSessionService) recordOutcome(
+	ctx context.Context,
+	session *models.AgeVerificationSession,
+	result providers.AgeVerificationResult,
+) error {
+	verification, err := s.ageVerificationRepository.FindByUserId(ctx, session.UserID)
+	if err != nil {
+		return err
+	}
+
+	if session.Status != models.AgeVerificationSessionStatusVerified &&
+		(verification == nil || verification.Status != models.AgeVerificationStatusPending) {
+		return nil
+	} else if verification == nil {
+		verification = &models.AgeVerification{UserID: session.UserID}
+	}
+
+	verification.Method = session.Method
+	switch session.Status {
+	case models.AgeVerificationSessionStatusVerified:
+		verifiedAt := result.VerifiedAt
+		if verifiedAt.IsZero() {
+			verifiedAt = session.CompletedAt.Time
+		}
+
+		verification.Status = models.AgeVerificationStatusVerified
+		verification.AssuranceLevel = result.AssuranceLevel
+		verification.AgeOver = session.AgeOver
+		verification.VerifiedAt = sql.NullTime{Time: verifiedAt.UTC(), Valid: true}
+		verification.ExpiresAt = sql.NullTime{
+			Time: result.ExpiresAt.UTC(), Valid: !result.ExpiresAt.IsZero(),
+		}
+	case models.AgeVerificationSessionStatusFailed:
+		verification.Status = models.AgeVerificationStatusFailed
+	default:
+		verification.Status = models.AgeVerificationStatusUnverified
+	}
+
+	_, err = s.ageVerificationRepository.Save(ctx, *verification)
+	return err
+}
+
+// validate resolves the age rule for the requested country and category,
+// then checks the request against it. It returns the provider of the
+// requested method and the minimum age to verify, which is the one of the
+// rule unless a higher one is requested.
+func (s *ageVerificationSessionService) validate(
+	request entities.AgeVerificationSessionRequest,
+) (providers.AgeVerificationProvider, *entities.AgeRule, int, error) {
+	if request.Method == nil {
+		return nil, nil, 0, entities.NewValidationError("method is required")
+	} else if request.Category == nil {
+		return nil, nil, 0, entities.NewValidationError("category is required")
+	}
+
+	country := ""
+	if request.Country != nil {
+		country = *request.Country
+	}
+
+	rule, err := s.ageRuleEngine.Resolve(country, *request.Category)
+	if err != nil {
+		if _, ok := err.(*entities.InvalidParameterError); ok {
+			return nil, nil, 0, entities.NewValidationError(
+				"country must be an ISO 3166-1 alpha-2 code",
+			)
+		} else if _, ok := err.(*entities.ItemNotFoundError); ok {
+			return nil, nil, 0, entities.NewValidationError(
+				"no age rule applies to category " + *request.Category,
+			)
+		}
+
+		return nil, nil, 0, err
+	}
+
+	ageOver := rule.MinimumAge
+	if request.AgeOver != nil {
+		if *request.AgeOver < minAgeVerificationAgeOver || *request.AgeOver > maxAgeVerificationAgeOver {
+			return nil, nil, 0, entities.NewValidationError(fmt.Sprintf(
+				"age_over must be between %d and %d",
+				minAgeVerificationAgeOver, maxAgeVerificationAgeOver,
+			))
+		} else if *request.AgeOver < rule.MinimumAge {
+			return nil, nil, 0, entities.NewValidationError(fmt.Sprintf(
+				"age_over must be at least %d for %s", rule.MinimumAge, rule.Category,
+			))
+		}
+
+		ageOver = *request.AgeOver
+	}
+
+	// Only methods both allowed by the rule and with an enabled provider
+	// can be chosen
+	methods := []string{}
+	for _, method := range rule.Methods {
+		if _, ok := s.providerRegistry.FindByMethod(method); ok {
+			methods = append(methods, method)
+		}
+	}
+
+	provider, ok := s.providerRegistry.FindByMethod(*request.Method)
+	if !ok || !rule.AllowsMethod(*request.Method) {
+		if len(methods) == 0 {
+			return nil, nil, 0, entities.NewValidationError(
+				"no method enabled is allowed for " + rule.Category,
+			)
+		}
+
+		return nil, nil, 0, entities.NewValidationError(
+			"method must be one of " + strings.Join(methods, ", "),
+		)
+	}
+
+	if request.ReturnURL != nil {
+		parsed, err := url.Parse(*request.ReturnURL)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return nil, nil, 0, entities.NewValidationError(
+				"return_url must be an absolute http or https URL",
+			)
+		}
+	}
+
+	return provider, rule, ageOver, nil
+}
+
+// validateClient checks the session can be run for the client the request
+// is authenticated as, which hasn't run out of sessions for the hour, and
+// returns it
+func (s *ageVerificationSessionService) validateClient(
+	ctx context.Context, request entities.AgeVerificationSessionRequest,
+) (*models.Client, error) {
+	client, ok := ctx.Value(common.AuthClient).(*models.Client)
+	if !ok {
+		return nil, entities.NewInvalidClientError()
+	}
+
+	if request.ReturnURL == nil {
+		return nil, entities.NewValidationError("return_url is required")
+	} else if !client.RedirectURIs.Includes(*request.ReturnURL) {
+		return nil, entities.NewValidationError(
+			"return_url must be one of the redirect URIs of the client",
+		)
+	}
+
+	info, ok := ctx.Value(common.Request).(*common.RequestInfo)
+	if ok && info.Origin != "" && !client.AllowedOrigins.Includes(info.Origin) {
+		return nil, entities.NewValidationError("origin is not allowed for the client")
+	}
+
+	started, err := s.ageVerificationSessionRepository.CountByClientIdSince(
+		ctx, client.ID.String(), s.now().Add(-time.Hour),
+	)
+	if err != nil {
+		return nil, err
+	} else if started >= int64(s.clientHourlyLimit) {
+		return nil, entities.NewRateLimitExceededError(fmt.Sprintf(
+			"clients can start %d age verifications an hour", s.clientHourlyLimit,
+		))
+	}
+
+	return client, nil
+}
@@ -0,0 +1,765 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	"verifymy-golang-test/common"
+	"verifymy-golang-test/entities"
+	mock_providers "verifymy-golang-test/mocks/providers"
+	mock_repositories "verifymy-golang-test/mocks/repositories"
+	"verifymy-golang-test/models"
+	"verifymy-golang-test/providers"
+)
+
+type ageVerificationSessionServiceTestSuite struct {
+	suite.Suite
+	ctrl                          *gomock.Controller
+	ctx                           context.Context
+	user                          *models.User
+	client                        *models.Client
+	now                           time.Time
+	sessionRepositoryMock         *mock_repositories.MockAgeVerificationSessionRepository
+	ageVerificationRepositoryMock *mock_repositories.MockAgeVerificationRepository
+	providerMock                  *mock_providers.MockAgeVerificationProvider
+	service                       AgeVerificationSessionService
+}
+
+func TestAgeVerificationSessionServiceTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(ageVerificationSessionServiceTestSuite))
+}
+
+func (s *ageVerificationSessionServiceTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.user = &models.User{
+		ID:          uuid.New(),
+		DateOfBirth: models.Date(time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC)),
+	}
+	s.client = &models.Client{
+		ID:             uuid.New(),
+		RedirectURIs:   models.StringList{"https://app.example.com/verified"},
+		AllowedOrigins: models.StringList{"https://app.example.com"},
+		Scopes:         models.StringList{models.ClientScopeAgeVerification},
+	}
+	s.ctx = context.WithValue(context.Background(), common.AuthUser, s.user)
+	s.ctx = context.WithValue(s.ctx, common.AuthClient, s.client)
+	s.now = time.Date(2023, 6, 30, 12, 0, 0, 0, time.UTC)
+	s.sessionRepositoryMock = mock_repositories.NewMockAgeVerificationSessionRepository(s.ctrl)
+	s.ageVerificationRepositoryMock = mock_repositories.NewMockAgeVerificationRepository(s.ctrl)
+
+	s.providerMock = mock_providers.NewMockAgeVerificationProvider(s.ctrl)
+	s.providerMock.EXPECT().Name().Return("simulator").AnyTimes()
+	s.providerMock.EXPECT().Method().Return(providers.AgeVerificationMethodDocument).AnyTimes()
+	registry, err := newAgeVerificationProviderRegistry(
+		[]providers.AgeVerificationProvider{s.providerMock}, "simulator",
+	)
+	s.Require().NoError(err)
+
+	transactorMock := mock_repositories.NewMockTransactor(s.ctrl)
+	transactorMock.EXPECT().WithinTransaction(
+		gomock.Any(), gomock.Any(),
+	).DoAndReturn(runWithinTransaction).AnyTimes()
+
+	ruleEngine := newAgeRuleEngine([]entities.AgeRule{
+		{
+			Country:    entities.AnyCountry,
+			Category:   "alcohol",
+			MinimumAge: 18,
+			Methods:    []string{"document", "credit_card"},
+		},
+		{Country: "US", Category: "alcohol", MinimumAge: 21, Methods: []string{"document"}},
+		{Country: "GB", Category: "alcohol", MinimumAge: 18, Methods: []string{"credit_card"}},
+	})
+
+	service, err := NewAgeVerificationSessionService(
+		transactorMock, s.sessionRepositoryMock, s.ageVerificationRepositoryMock, registry,
+		ruleEngine,
+	)
+	s.Require().NoError(err)
+	service.(*ageVerificationSessionService).now = func() time.Time { return s.now }
+	s.service = service
+}
+
+func intPointer(value int) *int {
+	return &value
+}
+
+func (s *ageVerificationSessionServiceTestSuite) pendingSession() *models.AgeVerificationSession {
+	return &models.AgeVerificationSession{
+		ID:        uuid.New(),
+		UserID:    s.user.ID.String(),
+		Provider:  "simulator",
+		Reference: "sim_1",
+		Method:    providers.AgeVerificationMethodDocument,
+		AgeOver:   18,
+		Status:    models.AgeVerificationSessionStatusPending,
+		ExpiresAt: s.now.Add(time.Minute),
+	}
+}
+
+func (s *ageVerificationSessionServiceTestSuite) TestStartValidation() {
+	tests := []struct {
+		description   string
+		request       entities.AgeVerificationSessionRequest
+		expectedError error
+	}{
+		{
+			description:   "Missing method",
+			request:       entities.AgeVerificationSessionRequest{Category: stringPointer("alcohol")},
+			expectedError: entities.NewValidationError("method is required"),
+		},
+		{
+			description: "Missing category",
+			request: entities.AgeVerificationSessionRequest{
+				Method: stringPointer("document"),
+			},
+			expectedError: entities.NewValidationError("category is required"),
+		},
+		{
+			description: "Invalid country",
+			request: entities.AgeVerificationSessionRequest{
+				Method:   stringPointer("document"),
+				Country:  stringPointer("USA"),
+				Category: stringPointer("alcohol"),
+			},
+			expectedError: entities.NewValidationError("country must be an ISO 3166-1 alpha-2 code"),
+		},
+		{
+			description: "Unknown category",
+			request: entities.AgeVerificationSessionRequest{
+				Method: stringPointer("document"), Category: stringPointer("gambling"),
+			},
+			expectedError: entities.NewValidationError("no age rule applies to category gambling"),
+		},
+		{
+			description: "Method without provider",
+			request: entities.AgeVerificationSessionRequest{
+				Method: stringPointer("credit_card"), Category: stringPointer("alcohol"),
+			},
+			expectedError: entities.NewValidationError("method must be one of document"),
+		},
+		{
+			description: "Method not allowed by rule",
+			request: entities.AgeVerificationSessionRequest{
+				Method:   stringPointer("email_estimation"),
+				Category: stringPointer("alcohol"),
+			},
+			expectedError: entities.NewValidationError("method must be one of document"),
+		},
+		{
+			description: "No enabled method allowed by rule",
+			request: entities.AgeVerificationSessionRequest{
+				Method:   stringPointer("document"),
+				Country:  stringPointer("GB"),
+				Category: stringPointer("alcohol"),
+			},
+			expectedError: entities.NewValidationError("no method enabled is allowed for alcohol"),
+		},
+		{
+			description: "Minimum age out of range",
+			request: entities.AgeVerificationSessionRequest{
+				Method:   stringPointer("document"),
+				Category: stringPointer("alcohol"),
+				AgeOver:  intPointer(101),
+			},
+			expectedError: entities.NewValidationError("age_over must be between 1 and 100"),
+		},
+		{
+			description: "Minimum age below rule",
+			request: entities.AgeVerificationSessionRequest{
+				Method:   stringPointer("document"),
+				Country:  stringPointer("us"),
+				Category: stringPointer("alcohol"),
+				AgeOver:  intPointer(18),
+			},
+			expectedError: entities.NewValidationError("age_over must be at least 21 for alcohol"),
+		},
+		{
+			description: "Invalid return URL",
+			request: entities.AgeVerificationSessionRequest{
+				Method:    stringPointer("document"),
+				Category:  stringPointer("alcohol"),
+				ReturnURL: stringPointer("/verified"),
+			},
+			expectedError: entities.NewValidationError(
+				"return_url must be an absolute http or https URL",
+			),
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			session, err := s.service.Start(s.ctx, test.request)
+
+			s.Nil(session)
+			s.Equal(test.expectedError, err)
+		})
+	}
+}
+
+func (s *ageVerificationSessionServiceTestSuite) TestStart() {
+	request := entities.AgeVerificationSessionRequest{
+		Method:    stringPointer("document"),
+		Category:  stringPointer("alcohol"),
+		ReturnURL: stringPointer("https://app.example.com/verified"),
+	}
+	started := &providers.AgeVerificationSession{
+		Reference:   "sim_1",
+		RedirectURL: "https://app.example.com/verified?reference=sim_1",
+		ExpiresAt:   s.now.Add(30 * time.Minute),
+	}
+	expectedSession := models.AgeVerificationSession{
+		UserID:      s.user.ID.String(),
+		ClientID:    s.client.ID.String(),
+		Provider:    "simulator",
+		Reference:   "sim_1",
+		Method:      "document",
+		Country:     entities.AnyCountry,
+		Category:    "alcohol",
+		AgeOver:     18,
+		RedirectURL: "https://app.example.com/verified?reference=sim_1",
+		ExpiresAt:   s.now.Add(30 * time.Minute),
+	}
+	verifiedAt := sql.NullTime{Time: s.now.AddDate(0, -1, 0), Valid: true}
+
+	tests := []struct {
+		description          string
+		startError           error
+		verification         *models.AgeVerification
+		expectedVerification *models.AgeVerification
+		expectedError        error
+	}{
+		{
+			description: "Never verified",
+			expectedVerification: &models.AgeVerification{
+				UserID: s.user.ID.String(),
+				Status: models.AgeVerificationStatusPending,
+				Method: "document",
+			},
+		},
+		{
+			description: "Previously failed",
+			verification: &models.AgeVerification{
+				UserID: s.user.ID.String(),
+				Status: models.AgeVerificationStatusFailed,
+				Method: "credit_card",
+			},
+			expectedVerification: &models.AgeVerification{
+				UserID: s.user.ID.String(),
+				Status: models.AgeVerificationStatusPending,
+				Method: "document",
+			},
+		},
+		{
+			description: "Already verified",
+			verification: &models.AgeVerification{
+				UserID:     s.user.ID.String(),
+				Status:     models.AgeVerificationStatusVerified,
+				VerifiedAt: verifiedAt,
+			},
+		},
+		{
+			description:   "Provider error",
+			startError:    errors.New("provider unavailable"),
+			expectedError: errors.New("provider unavailable"),
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			s.sessionRepositoryMock.EXPECT().CountByClientIdSince(
+				s.ctx, s.client.ID.String(), s.now.Add(-time.Hour),
+			).Return(int64(0), nil)
+			s.providerMock.EXPECT().StartSession(s.ctx, providers.AgeVerificationRequest{
+				UserID:      s.user.ID.String(),
+				DateOfBirth: time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC),
+				AgeOver:     18,
+				ReturnURL:   "https://app.example.com/verified",
+				CallbackURL: "http://localhost:8080/age_verifications/callbacks/simulator",
+			}).Return(started, test.startError)
+
+			if test.startError == nil {
+				created := expectedSession
+				created.ID = uuid.New()
+				s.sessionRepositoryMock.EXPECT().Create(s.ctx, expectedSession).Return(&created, nil)
+				s.ageVerificationRepositoryMock.EXPECT().FindByUserId(
+					s.ctx, s.user.ID.String(),
+				).Return(test.verification, nil)
+			}
+			if test.expectedVerification != nil {
+				s.ageVerificationRepositoryMock.EXPECT().Save(
+					s.ctx, *test.expectedVerification,
+				).Return(test.expectedVerification, nil)
+			}
+
+			session, err := s.service.Start(s.ctx, request)
+
+			s.Equal(test.expectedError, err)
+			if test.expectedError == nil {
+				s.Equal("sim_1", session.Reference)
+			}
+		})
+	}
+}
+
+func (s *ageVerificationSessionServiceTestSuite) TestStartForClient() {
+	request := entities.AgeVerificationSessionRequest{
+		Method:    stringPointer("document"),
+		Category:  stringPointer("alcohol"),
+		ReturnURL: stringPointer("https://app.example.com/verified"),
+	}
+	withOrigin := func(origin string) context.Context {
+		return context.WithValue(s.ctx, common.Request, &common.RequestInfo{Origin: origin})
+	}
+	withoutClient := context.WithValue(
+		context.Background(), common.AuthUser, s.user,
+	)
+
+	tests := []struct {
+		description   string
+		ctx           context.Context
+		returnURL     *string
+		started       int64
+		skipCount     bool
+		expectedError error
+	}{
+		{
+			description: "Allowed origin",
+			ctx:         withOrigin("https://app.example.com"),
+			returnURL:   request.ReturnURL,
+		},
+		{
+			description: "Without origin",
+			ctx:         s.ctx,
+			returnURL:   request.ReturnURL,
+			started:     999,
+		},
+		{
+			description:   "Not authenticated as a client",
+			ctx:           withoutClient,
+			returnURL:     request.ReturnURL,
+			skipCount:     true,
+			expectedError: entities.NewInvalidClientError(),
+		},
+		{
+			description:   "Missing return URL",
+			ctx:           s.ctx,
+			skipCount:     true,
+			expectedError: entities.NewValidationError("return_url is required"),
+		},
+		{
+			description: "Unregistered return URL",
+			ctx:         s.ctx,
+			returnURL:   stringPointer("https://evil.example.com/verified"),
+			skipCount:   true,
+			expectedError: entities.NewValidationError(
+				"return_url must be one of the redirect URIs of the client",
+			),
+		},
+		{
+			description:   "Other origin",
+			ctx:           withOrigin("https://evil.example.com"),
+			returnURL:     request.ReturnURL,
+			skipCount:     true,
+			expectedError: entities.NewValidationError("origin is not allowed for the client"),
+		},
+		{
+			description: "Hourly limit reached",
+			ctx:         s.ctx,
+			returnURL:   request.ReturnURL,
+			started:     1000,
+			expectedError: entities.NewRateLimitExceededError(
+				"clients can start 1000 age verifications an hour",
+			),
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			if !test.skipCount {
+				s.sessionRepositoryMock.EXPECT().CountByClientIdSince(
+					test.ctx, s.client.ID.String(), s.now.Add(-time.Hour),
+				).Return(test.started, nil)
+			}
+			if test.expectedError == nil {
+				s.providerMock.EXPECT().StartSession(test.ctx, gomock.Any()).Return(
+					&providers.AgeVerificationSession{
+						Reference: "sim_1", ExpiresAt: s.now.Add(30 * time.Minute),
+					}, nil,
+				)
+				s.sessionRepositoryMock.EXPECT().Create(test.ctx, gomock.Any()).DoAndReturn(func(
+					ctx context.Context, session models.AgeVerificationSession,
+				) (*models.AgeVerificationSession, error) {
+					return &session, nil
+				})
+				s.ageVerificationRepositoryMock.EXPECT().FindByUserId(
+					test.ctx, s.user.ID.String(),
+				).Return(&models.AgeVerification{Status: models.AgeVerificationStatusVerified}, nil)
+			}
+
+			clientRequest := request
+			clientRequest.ReturnURL = test.returnURL
+			session, err := s.service.Start(test.ctx, clientRequest)
+
+			s.Equal(test.expectedError, err)
+			if test.expectedError == nil {
+				s.Equal(s.client.ID.String(), session.ClientID)
+			} else {
+				s.Nil(session)
+			}
+		})
+	}
+}
+
+func (s *ageVerificationSessionServiceTestSuite) TestStartEnforcesCountryRule() {
+	s.sessionRepositoryMock.EXPECT().CountByClientIdSince(
+		s.ctx, s.client.ID.String(), s.now.Add(-time.Hour),
+	).Return(int64(0), nil)
+	s.providerMock.EXPECT().StartSession(s.ctx, providers.AgeVerificationRequest{
+		UserID:      s.user.ID.String(),
+		DateOfBirth: time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC),
+		AgeOver:     21,
+		ReturnURL:   "https://app.example.com/verified",
+		CallbackURL: "http://localhost:8080/age_verifications/callbacks/simulator",
+	}).Return(&providers.AgeVerificationSession{
+		Reference: "sim_1", ExpiresAt: s.now.Add(30 * time.Minute),
+	}, nil)
+	s.sessionRepositoryMock.EXPECT().Create(s.ctx, models.AgeVerificationSession{
+		UserID:    s.user.ID.String(),
+		ClientID:  s.client.ID.String(),
+		Provider:  "simulator",
+		Reference: "sim_1",
+		Method:    "document",
+		Country:   "US",
+		Category:  "alcohol",
+		AgeOver:   21,
+		ExpiresAt: s.now.Add(30 * time.Minute),
+	}).DoAndReturn(func(
+		ctx context.Context, session models.AgeVerificationSession,
+	) (*models.AgeVerificationSession, error) {
+		return &session, nil
+	})
+	s.ageVerificationRepositoryMock.EXPECT().FindByUserId(
+		s.ctx, s.user.ID.String(),
+	).Return(&models.AgeVerification{Status: models.AgeVerificationStatusVerified}, nil)
+
+	session, err := s.service.Start(s.ctx, entities.AgeVerificationSessionRequest{
+		Method:    stringPointer("document"),
+		Country:   stringPointer("us"),
+		Category:  stringPointer("alcohol"),
+		ReturnURL: stringPointer("https://app.example.com/verified"),
+	})
+
+	s.NoError(err)
+	s.Equal(21, session.AgeOver)
+}
+
+func (s *ageVerificationSessionServiceTestSuite) TestFindById() {
+	session := s.pendingSession()
+	sessionId := session.ID.String()
+
+	s.Run("Not found", func() {
+		s.sessionRepositoryMock.EXPECT().FindById(s.ctx, sessionId).Return(nil, nil)
+
+		found, err := s.service.FindById(s.ctx, sessionId)
+
+		s.Nil(found)
+		s.Equal(entities.NewItemNotFoundError("Age verification", sessionId), err)
+	})
+
+	s.Run("Session of another user", func() {
+		other := *session
+		other.UserID = uuid.NewString()
+		s.sessionRepositoryMock.EXPECT().FindById(s.ctx, sessionId).Return(&other, nil)
+
+		found, err := s.service.FindById(s.ctx, sessionId)
+
+		s.Nil(found)
+		s.Equal(entities.NewItemNotFoundError("Age verification", sessionId), err)
+	})
+
+	s.Run("Completed", func() {
+		completed := *session
+		completed.Status = models.AgeVerificationSessionStatusFailed
+		s.sessionRepositoryMock.EXPECT().FindById(s.ctx, sessionId).Return(&completed, nil)
+
+		found, err := s.service.FindById(s.ctx, sessionId)
+
+		s.NoError(err)
+		s.Equal(&completed, found)
+	})
+
+	s.Run("Still pending at the provider", func() {
+		s.sessionRepositoryMock.EXPECT().FindById(s.ctx, sessionId).Return(session, nil)
+		s.providerMock.EXPECT().Poll(s.ctx, "sim_1").Return(&providers.AgeVerificationResult{
+			Reference: "sim_1", Status: models.AgeVerificationSessionStatusPending,
+		}, nil)
+
+		found, err := s.service.FindById(s.ctx, sessionId)
+
+		s.NoError(err)
+		s.Equal(session, found)
+	})
+
+	s.Run("Provider error", func() {
+		s.sessionRepositoryMock.EXPECT().FindById(s.ctx, sessionId).Return(session, nil)
+		s.providerMock.EXPECT().Poll(s.ctx, "sim_1").Return(nil, errors.New("timeout"))
+
+		found, err := s.service.FindById(s.ctx, sessionId)
+
+		s.NoError(err)
+		s.Equal(session, found)
+	})
+
+	s.Run("Verified at the provider", func() {
+		s.sessionRepositoryMock.EXPECT().FindById(s.ctx, sessionId).Return(session, nil)
+		s.providerMock.EXPECT().Poll(s.ctx, "sim_1").Return(&providers.AgeVerificationResult{
+			Reference:      "sim_1",
+			Status:         models.AgeVerificationSessionStatusVerified,
+			AssuranceLevel: models.AssuranceLevelHigh,
+			VerifiedAt:     s.now,
+			ExpiresAt:      s.now.AddDate(1, 0, 0),
+		}, nil)
+		s.sessionRepositoryMock.EXPECT().UpdateColumnsById(s.ctx, sessionId, map[string]interface{}{
+			"status":          models.AgeVerificationSessionStatusVerified,
+			"completed_at":    sql.NullTime{Time: s.now, Valid: true},
+			"assurance_level": models.AssuranceLevelHigh,
+		}, models.AgeVerificationSessionStatusPending).Return(true, nil)
+
+		pending := &models.AgeVerification{
+			ID:     uuid.New(),
+			UserID: s.user.ID.String(),
+			Status: models.AgeVerificationStatusPending,
+			Method: "document",
+		}
+		s.ageVerificationRepositoryMock.EXPECT().FindByUserId(
+			s.ctx, s.user.ID.String(),
+		).Return(pending, nil)
+		s.ageVerificationRepositoryMock.EXPECT().Save(s.ctx, models.AgeVerification{
+			ID:             pending.ID,
+			UserID:         s.user.ID.String(),
+			Status:         models.AgeVerificationStatusVerified,
+			Method:         "document",
+			AssuranceLevel: models.AssuranceLevelHigh,
+			AgeOver:        18,
+			VerifiedAt:     sql.NullTime{Time: s.now, Valid: true},
+			ExpiresAt:      sql.NullTime{Time: s.now.AddDate(1, 0, 0), Valid: true},
+		}).Return(pending, nil)
+
+		found, err := s.service.FindById(s.ctx, sessionId)
+
+		s.NoError(err)
+		s.Equal(models.AgeVerificationSessionStatusVerified, found.Status)
+		s.Equal(models.AssuranceLevelHigh, found.AssuranceLevel)
+		s.Equal(sql.NullTime{Time: s.now, Valid: true}, found.CompletedAt)
+	})
+
+	s.Run("Expired", func() {
+		expired := *session
+		expired.ExpiresAt = s.now
+		s.sessionRepositoryMock.EXPECT().FindById(s.ctx, sessionId).Return(&expired, nil)
+		s.sessionRepositoryMock.EXPECT().UpdateColumnsById(s.ctx, sessionId, map[string]interface{}{
+			"status":       models.AgeVerificationSessionStatusExpired,
+			"completed_at": sql.NullTime{Time: s.now, Valid: true},
+		}, models.AgeVerificationSessionStatusPending).Return(true, nil)
+		s.ageVerificationRepositoryMock.EXPECT().FindByUserId(
+			s.ctx, s.user.ID.String(),
+		).Return(&models.AgeVerification{
+			UserID: s.user.ID.String(),
+			Status: models.AgeVerificationStatusPending,
+			Method: "document",
+		}, nil)
+		s.ageVerificationRepositoryMock.EXPECT().Save(s.ctx, models.AgeVerification{
+			UserID: s.user.ID.String(),
+			Status: models.AgeVerificationStatusUnverified,
+			Method: "document",
+		}).Return(nil, nil)
+
+		found, err := s.service.FindById(s.ctx, sessionId)
+
+		s.NoError(err)
+		s.Equal(models.AgeVerificationSessionStatusExpired, found.Status)
+	})
+}
+
+func (s *ageVerificationSessionServiceTestSuite) TestHandleCallback() {
+	header := http.Header{"X-Simulator-Signature": []string{"abc"}}
+	body := []byte(`{"reference":"sim_1"}`)
+	failed := &providers.AgeVerificationResult{
+		Reference: "sim_1",
+		Status:    models.AgeVerificationSessionStatusFailed,
+		Reason:    "user is under 18",
+	}
+
+	s.Run("Unknown provider", func() {
+		session, err := s.service.HandleCallback(s.ctx, "mobile", header, body)
+
+		s.Nil(session)
+		s.Equal(entities.NewItemNotFoundError("Age verification provider", "mobile"), err)
+	})
+
+	s.Run("Invalid callback", func() {
+		s.providerMock.EXPECT().HandleCallback(s.ctx, header, body).Return(
+			nil, providers.ErrInvalidAgeVerificationCallback,
+		)
+
+		session, err := s.service.HandleCallback(s.ctx, "simulator", header, body)
+
+		s.Nil(session)
+		s.Equal(providers.ErrInvalidAgeVerificationCallback, err)
+	})
+
+	s.Run("Unknown session", func() {
+		s.providerMock.EXPECT().HandleCallback(s.ctx, header, body).Return(failed, nil)
+		s.sessionRepositoryMock.EXPECT().FindByReference(s.ctx, "simulator", "sim_1").Return(nil, nil)
+
+		session, err := s.service.HandleCallback(s.ctx, "simulator", header, body)
+
+		s.Nil(session)
+		s.Equal(entities.NewItemNotFoundError("Age verification", "sim_1"), err)
+	})
+
+	s.Run("Failed", func() {
+		pending := s.pendingSession()
+		s.providerMock.EXPECT().HandleCallback(s.ctx, header, body).Return(failed, nil)
+		s.sessionRepositoryMock.EXPECT().FindByReference(
+			s.ctx, "simulator", "sim_1",
+		).Return(pending, nil)
+		s.sessionRepositoryMock.EXPECT().UpdateColumnsById(
+			s.ctx, pending.ID.String(), map[string]interface{}{
+				"status":         models.AgeVerificationSessionStatusFailed,
+				"completed_at":   sql.NullTime{Time: s.now, Valid: true},
+				"failure_reason": "user is under 18",
+			}, models.AgeVerificationSessionStatusPending,
+		).Return(true, nil)
+		s.ageVerificationRepositoryMock.EXPECT().FindByUserId(
+			s.ctx, s.user.ID.String(),
+		).Return(&models.AgeVerification{
+			UserID: s.user.ID.String(),
+			Status: models.AgeVerificationStatusPending,
+		}, nil)
+		s.ageVerificationRepositoryMock.EXPECT().Save(s.ctx, models.AgeVerification{
+			UserID: s.user.ID.String(),
+			Status: models.AgeVerificationStatusFailed,
+			Method: "document",
+		}).Return(nil, nil)
+
+		session, err := s.service.HandleCallback(s.ctx, "simulator", header, body)
+
+		s.NoError(err)
+		s.Equal(models.AgeVerificationSessionStatusFailed, session.Status)
+		s.Equal("user is under 18", session.FailureReason)
+	})
+
+	s.Run("Failed while already verified", func() {
+		pending := s.pendingSession()
+		s.providerMock.EXPECT().HandleCallback(s.ctx, header, body).Return(failed, nil)
+		s.sessionRepositoryMock.EXPECT().FindByReference(
+			s.ctx, "simulator", "sim_1",
+		).Return(pending, nil)
+		s.sessionRepositoryMock.EXPECT().UpdateColumnsById(
+			s.ctx, pending.ID.String(), gomock.Any(), models.AgeVerificationSessionStatusPending,
+		).Return(true, nil)
+		s.ageVerificationRepositoryMock.EXPECT().FindByUserId(
+			s.ctx, s.user.ID.String(),
+		).Return(&models.AgeVerification{
+			UserID: s.user.ID.String(),
+			Status: models.AgeVerificationStatusVerified,
+		}, nil)
+
+		session, err := s.service.HandleCallback(s.ctx, "simulator", header, body)
+
+		s.NoError(err)
+		s.Equal(models.AgeVerificationSessionStatusFailed, session.Status)
+	})
+
+	s.Run("Already completed", func() {
+		completed := s.pendingSession()
+		completed.Status = models.AgeVerificationSessionStatusVerified
+		s.providerMock.EXPECT().HandleCallback(s.ctx, header, body).Return(failed, nil)
+		s.sessionRepositoryMock.EXPECT().FindByReference(
+			s.ctx, "simulator", "sim_1",
+		).Return(completed, nil)
+
+		session, err := s.service.HandleCallback(s.ctx, "simulator", header, body)
+
+		s.NoError(err)
+		s.Equal(completed, session)
+	})
+
+	s.Run("Completed by a concurrent callback", func() {
+		pending := s.pendingSession()
+		completed := *pending
+		completed.Status = models.AgeVerificationSessionStatusFailed
+		s.providerMock.EXPECT().HandleCallback(s.ctx, header, body).Return(failed, nil)
+		s.sessionRepositoryMock.EXPECT().FindByReference(
+			s.ctx, "simulator", "sim_1",
+		).Return(pending, nil)
+		s.sessionRepositoryMock.EXPECT().UpdateColumnsById(
+			s.ctx, pending.ID.String(), gomock.Any(), models.AgeVerificationSessionStatusPending,
+		).Return(false, nil)
+		s.sessionRepositoryMock.EXPECT().FindById(
+			s.ctx, pending.ID.String(),
+		).Return(&completed, nil)
+
+		session, err := s.service.HandleCallback(s.ctx, "simulator", header, body)
+
+		s.NoError(err)
+		s.Equal(&completed, session)
+	})
+
+	s.Run("Expired", func() {
+		expired := s.pendingSession()
+		expired.ExpiresAt = s.now.Add(-time.Second)
+		s.providerMock.EXPECT().HandleCallback(s.ctx, header, body).Return(failed, nil)
+		s.sessionRepositoryMock.EXPECT().FindByReference(
+			s.ctx, "simulator", "sim_1",
+		).Return(expired, nil)
+		s.sessionRepositoryMock.EXPECT().UpdateColumnsById(
+			s.ctx, expired.ID.String(), map[string]interface{}{
+				"status":       models.AgeVerificationSessionStatusExpired,
+				"completed_at": sql.NullTime{Time: s.now, Valid: true},
+			}, models.AgeVerificationSessionStatusPending,
+		).Return(true, nil)
+		s.ageVerificationRepositoryMock.EXPECT().FindByUserId(
+			s.ctx, s.user.ID.String(),
+		).Return(nil, nil)
+
+		session, err := s.service.HandleCallback(s.ctx, "simulator", header, body)
+
+		s.NoError(err)
+		s.Equal(models.AgeVerificationSessionStatusExpired, session.Status)
+	})
+}
+
+func (s *ageVerificationSessionServiceTestSuite) TestExpirePending() {
+	first := s.pendingSession()
+	second := s.pendingSession()
+
+	s.sessionRepositoryMock.EXPECT().FindExpired(s.ctx, s.now, 100).Return(
+		[]models.AgeVerificationSession{*first, *second}, nil,
+	)
+	s.sessionRepositoryMock.EXPECT().UpdateColumnsById(
+		s.ctx, first.ID.String(), gomock.Any(), models.AgeVerificationSessionStatusPending,
+	).Return(false, errors.New("database error"))
+	s.sessionRepositoryMock.EXPECT().UpdateColumnsById(
+		s.ctx, second.ID.String(), gomock.Any(), models.AgeVerificationSessionStatusPending,
+	).Return(true, nil)
+	s.ageVerificationRepositoryMock.EXPECT().FindByUserId(
+		s.ctx, s.user.ID.String(),
+	).Return(nil, nil)
+
+	expired, err := s.service.ExpirePending(s.ctx)
+
+	s.Equal(int64(1), expired)
+	s.EqualError(err, "age verification session "+first.ID.String()+": database error")
+}
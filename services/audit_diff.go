@@ -0,0 +1,100 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"reflect"
+	"sync"
+
+	"gorm.io/gorm/schema"
+
+	"verifymy-golang-test/models"
+)
+
+// auditRedacted replaces the values of secrets in audit diffs
+const auditRedacted = "[REDACTED]"
+
+var (
+	auditSchemas    sync.Map
+	secretValueType = reflect.TypeOf(models.SecretValue(""))
+)
+
+// auditDiff lists, keyed by column, the previous and new value of each
+// column changes modifies on model, a pointer to a GORM model. Columns left
+// as they were are skipped, and both values of SecretValue fields are
+// redacted.
+func auditDiff(model interface{}, changes map[string]interface{}) (models.JSONMap, error) {
+	modelSchema, err := schema.Parse(model, &auditSchemas, schema.NamingStrategy{})
+	if err != nil {
+		return nil, err
+	}
+
+	value := reflect.ValueOf(model).Elem()
+	diff := models.JSONMap{}
+	for column, to := range changes {
+		field := modelSchema.LookUpField(column)
+		if field == nil {
+			diff[column] = map[string]interface{}{"to": to}
+			continue
+		}
+
+		if field.FieldType == secretValueType {
+			diff[column] = map[string]interface{}{"from": auditRedacted, "to": auditRedacted}
+			continue
+		}
+
+		from, _ := field.ValueOf(context.Background(), value)
+		from, to = auditValue(from), auditValue(to)
+		if sameAuditValue(from, to) {
+			continue
+		}
+
+		diff[column] = map[string]interface{}{"from": from, "to": to}
+	}
+
+	return diff, nil
+}
+
+// auditAttributes mirrors what GORM updates from a struct: its non-zero
+// fields, keyed by column
+func auditAttributes(model interface{}) (map[string]interface{}, error) {
+	modelSchema, err := schema.Parse(model, &auditSchemas, schema.NamingStrategy{})
+	if err != nil {
+		return nil, err
+	}
+
+	value := reflect.ValueOf(model).Elem()
+	attributes := map[string]interface{}{}
+	for _, field := range modelSchema.Fields {
+		if field.DBName == "" || !field.Updatable || field.PrimaryKey {
+			continue
+		}
+
+		if fieldValue, isZero := field.ValueOf(context.Background(), value); !isZero {
+			attributes[field.DBName] = fieldValue
+		}
+	}
+
+	return attributes, nil
+}
+
+// auditValue shows values as they are stored, so nullable columns appear as
+// null rather than as a struct with a validity flag
+func auditValue(value interface{}) interface{} {
+	if valuer, ok := value.(driver.Valuer); ok {
+		if stored, err := valuer.Value(); err == nil {
+			return stored
+		}
+	}
+
+	return value
+}
+
+func sameAuditValue(from interface{}, to interface{}) bool {
+	fromJSON, fromErr := json.Marshal(from)
+	toJSON, toErr := json.Marshal(to)
+
+	return fromErr == nil && toErr == nil && bytes.Equal(fromJSON, toJSON)
+}
@@ -0,0 +1,74 @@
+package services
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"verifymy-golang-test/models"
+)
+
+type auditDiffTestSuite struct {
+	suite.Suite
+	user *models.User
+}
+
+func TestAuditDiffTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(auditDiffTestSuite))
+}
+
+func (s *auditDiffTestSuite) SetupTest() {
+	s.user = &models.User{
+		Name:        "Peter Parker",
+		DateOfBirth: models.Date(time.Date(2001, 8, 10, 0, 0, 0, 0, time.UTC)),
+		Email:       "peter.parker@nyork.co",
+		Password:    "hashed-password",
+		Address:     "Queens",
+	}
+}
+
+func (s *auditDiffTestSuite) TestAuditDiff() {
+	diff, err := auditDiff(s.user, map[string]interface{}{
+		"name":                "Peter Parker",
+		"date_of_birth":       "2001-08-11",
+		"address":             "",
+		"password":            "new-hashed-password",
+		"nickname":            "Spidey",
+		"sessions_revoked_at": time.Date(2023, 6, 30, 12, 0, 0, 0, time.UTC),
+	})
+	s.Require().NoError(err)
+
+	encoded, err := json.Marshal(diff)
+	s.Require().NoError(err)
+	s.JSONEq(`{
+		"date_of_birth": {"from": "2001-08-10", "to": "2001-08-11"},
+		"address": {"from": "Queens", "to": ""},
+		"password": {"from": "[REDACTED]", "to": "[REDACTED]"},
+		"nickname": {"to": "Spidey"},
+		"sessions_revoked_at": {"from": null, "to": "2023-06-30T12:00:00Z"}
+	}`, string(encoded))
+	s.NotContains(string(encoded), "hashed-password")
+}
+
+func (s *auditDiffTestSuite) TestAuditAttributes() {
+	attributes, err := auditAttributes(&models.User{
+		Name:     "Spidey",
+		Password: "hashed-password",
+	})
+	s.Require().NoError(err)
+
+	s.Equal(map[string]interface{}{
+		"name":     "Spidey",
+		"password": models.SecretValue("hashed-password"),
+	}, attributes)
+
+	diff, err := auditDiff(s.user, attributes)
+	s.Require().NoError(err)
+	s.Equal(models.JSONMap{
+		"name":     map[string]interface{}{"from": "Peter Parker", "to": "Spidey"},
+		"password": map[string]interface{}{"from": auditRedacted, "to": auditRedacted},
+	}, diff)
+}
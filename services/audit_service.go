@@ -0,0 +1,166 @@
+package services
+
+import (
+	"context"
+
+	"verifymy-golang-test/common"
+	"verifymy-golang-test/entities"
+	"verifymy-golang-test/models"
+	"verifymy-golang-test/repositories"
+)
+
+const (
+	AuditActionUserSignedUp             = "user.signed_up"
+	AuditActionUserSignedIn             = "user.signed_in"
+	AuditActionUserSignInFailed         = "user.sign_in_failed"
+	AuditActionUserReauthenticated      = "user.reauthenticated"
+	AuditActionUserInvitationAccepted   = "user.invitation_accepted"
+	AuditActionUserEmailChangeRequested = "user.email_change_requested"
+	AuditActionUserEmailChanged         = "user.email_changed"
+	AuditActionUserPasswordChanged      = "user.password_changed"
+	AuditActionUserProfileUpdated       = "user.profile_updated"
+	AuditActionUserDeleted              = "user.deleted"
+	AuditActionUserViewed               = "user.viewed"
+	AuditActionUserInvited              = "user.invited"
+	AuditActionUserUpdated              = "user.updated"
+	AuditActionUserRestored             = "user.restored"
+	AuditActionUserDisposed             = "user.disposed"
+	AuditActionParentalConsentRequested = "parental_consent.requested"
+	AuditActionParentalConsentApproved  = "parental_consent.approved"
+	AuditActionParentalConsentRejected  = "parental_consent.rejected"
+	AuditActionWebhookCreated           = "webhook.created"
+	AuditActionWebhookUpdated           = "webhook.updated"
+	AuditActionWebhookDeleted           = "webhook.deleted"
+	AuditActionWebhookRedelivered       = "webhook.redelivered"
+	AuditActionClientCreated            = "client.created"
+	AuditActionClientUpdated            = "client.updated"
+	AuditActionClientSecretRotated      = "client.secret_rotated"
+	AuditActionClientDeleted            = "client.deleted"
+	AuditActionOAuthAuthorized          = "oauth.authorized"
+	AuditActionOAuthRevoked             = "oauth.revoked"
+	AuditActionOAuthLoggedOut           = "oauth.logged_out"
+
+	auditTargetUser         = "user"
+	auditTargetWebhook      = "webhook"
+	auditTargetClient       = "client"
+	maxAuditUserAgentLength = 255
+)
+
+type AuditService interface {
+	Record(
+		ctx context.Context,
+		action string,
+		targetType string,
+		targetId string,
+		metadata map[string]interface{},
+	) error
+	RecordChanges(
+		ctx context.Context,
+		action string,
+		targetType string,
+		targetId string,
+		target interface{},
+		changes map[string]interface{},
+	) error
+	FindAll(ctx context.Context, query entities.AuditEventQuery) (*entities.AuditEventsPage, error)
+}
+
+func NewAuditService(
+	auditEventRepository repositories.AuditEventRepository,
+) AuditService {
+	return &auditService{
+		auditEventRepository: auditEventRepository,
+	}
+}
+
+type auditService struct {
+	auditEventRepository repositories.AuditEventRepository
+}
+
+// Record stores an audit event on behalf of the signed in user, if any,
+// along with where the request came from
+func (s *auditService) Record(
+	ctx context.Context,
+	action string,
+	targetType string,
+	targetId string,
+	metadata map[string]interface{},
+) error {
+	return s.record(ctx, models.AuditEvent{
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetId,
+		Metadata:   metadata,
+	})
+}
+
+// RecordChanges stores an audit event with the diff of changes, keyed by
+// column, applied to target, a pointer to the model as it was before. Secrets
+// are redacted from the diff.
+func (s *auditService) RecordChanges(
+	ctx context.Context,
+	action string,
+	targetType string,
+	targetId string,
+	target interface{},
+	changes map[string]interface{},
+) error {
+	diff, err := auditDiff(target, changes)
+	if err != nil {
+		return err
+	}
+
+	return s.record(ctx, models.AuditEvent{
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetId,
+		Diff:       diff,
+	})
+}
+
+func (s *auditService) record(ctx context.Context, event models.AuditEvent) error {
+	if actor, ok := ctx.Value(common.AuthUser).(*models.User); ok {
+		event.ActorID = actor.ID.String()
+	}
+	if request, ok := ctx.Value(common.Request).(*common.RequestInfo); ok {
+		event.IP = request.IP
+		event.UserAgent = request.UserAgent
+		event.RequestID = request.RequestID
+		if len(event.UserAgent) > maxAuditUserAgentLength {
+			event.UserAgent = event.UserAgent[:maxAuditUserAgentLength]
+		}
+	}
+
+	return s.auditEventRepository.Create(ctx, event)
+}
+
+// FindAll fetches one extra event to find out whether there is a next page
+func (s *auditService) FindAll(
+	ctx context.Context, query entities.AuditEventQuery,
+) (*entities.AuditEventsPage, error) {
+	limit := query.Page.Limit
+	lookahead := query
+	lookahead.Page.Limit = limit + 1
+
+	events, err := s.auditEventRepository.FindAll(ctx, lookahead)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &entities.AuditEventsPage{Events: events}
+	if len(events) > limit {
+		result.Events = events[:limit]
+		result.Next = query.CursorFor(result.Events[limit-1])
+	}
+
+	if query.Page.WithTotal {
+		total, err := s.auditEventRepository.Count(ctx, query.Filters)
+		if err != nil {
+			return nil, err
+		}
+
+		result.Total = &total
+	}
+
+	return result, nil
+}
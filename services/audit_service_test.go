@@ -0,0 +1,207 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	"verifymy-golang-test/common"
+	"verifymy-golang-test/entities"
+	mock_repositories "verifymy-golang-test/mocks/repositories"
+	"verifymy-golang-test/models"
+)
+
+type auditServiceTestSuite struct {
+	suite.Suite
+	ctrl                     *gomock.Controller
+	auditEventRepositoryMock *mock_repositories.MockAuditEventRepository
+	service                  AuditService
+}
+
+func TestAuditServiceTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(auditServiceTestSuite))
+}
+
+func (s *auditServiceTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.auditEventRepositoryMock = mock_repositories.NewMockAuditEventRepository(s.ctrl)
+	s.service = NewAuditService(s.auditEventRepositoryMock)
+}
+
+func (s *auditServiceTestSuite) TestRecord() {
+	actorId := uuid.New()
+
+	tests := []struct {
+		description   string
+		actor         *models.User
+		expectedActor string
+		createError   error
+	}{
+		{
+			description:   "Success with signed in actor",
+			actor:         &models.User{ID: actorId},
+			expectedActor: actorId.String(),
+		},
+		{
+			description: "Success without actor",
+		},
+		{
+			description: "Error creating audit event",
+			actor:       &models.User{ID: actorId},
+			createError: errors.New("error creating audit event"),
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			ctx := context.Background()
+			if test.actor != nil {
+				ctx = context.WithValue(ctx, common.AuthUser, test.actor)
+				test.expectedActor = test.actor.ID.String()
+			}
+
+			s.auditEventRepositoryMock.EXPECT().Create(ctx, models.AuditEvent{
+				ActorID:    test.expectedActor,
+				Action:     "user.restored",
+				TargetType: "user",
+				TargetID:   "target-id",
+				Metadata:   models.JSONMap{"reason": "mistake"},
+			}).Return(test.createError)
+
+			err := s.service.Record(
+				ctx, "user.restored", "user", "target-id",
+				map[string]interface{}{"reason": "mistake"},
+			)
+			s.Equal(test.createError, err)
+		})
+	}
+}
+
+func (s *auditServiceTestSuite) TestRecordWithRequestInfo() {
+	ctx := context.WithValue(context.Background(), common.Request, &common.RequestInfo{
+		IP:        "10.0.0.1",
+		UserAgent: strings.Repeat("a", 300),
+		RequestID: "request-id",
+	})
+
+	s.auditEventRepositoryMock.EXPECT().Create(ctx, models.AuditEvent{
+		Action:     "user.signed_in",
+		TargetType: "user",
+		TargetID:   "target-id",
+		IP:         "10.0.0.1",
+		UserAgent:  strings.Repeat("a", 255),
+		RequestID:  "request-id",
+	}).Return(nil)
+
+	s.NoError(s.service.Record(ctx, "user.signed_in", "user", "target-id", nil))
+}
+
+func (s *auditServiceTestSuite) TestRecordChanges() {
+	actor := &models.User{ID: uuid.New()}
+	ctx := context.WithValue(context.Background(), common.AuthUser, actor)
+	target := &models.User{Name: "Peter", Password: "old-hash"}
+
+	s.auditEventRepositoryMock.EXPECT().Create(ctx, models.AuditEvent{
+		ActorID:    actor.ID.String(),
+		Action:     "user.updated",
+		TargetType: "user",
+		TargetID:   "target-id",
+		Diff: models.JSONMap{
+			"name":     map[string]interface{}{"from": "Peter", "to": "Peter Parker"},
+			"password": map[string]interface{}{"from": "[REDACTED]", "to": "[REDACTED]"},
+		},
+	}).Return(nil)
+
+	err := s.service.RecordChanges(
+		ctx, "user.updated", "user", "target-id", target,
+		map[string]interface{}{"name": "Peter Parker", "password": "new-hash"},
+	)
+	s.NoError(err)
+}
+
+func (s *auditServiceTestSuite) TestFindAll() {
+	ctx := context.Background()
+	createdAt := time.Date(2023, 6, 30, 12, 0, 0, 0, time.UTC)
+	events := []models.AuditEvent{
+		{ID: uuid.New(), CreatedAt: createdAt.Add(time.Minute)},
+		{ID: uuid.New(), CreatedAt: createdAt},
+		{ID: uuid.New(), CreatedAt: createdAt.Add(-time.Minute)},
+	}
+	filters := entities.AuditEventFilters{Action: "user.signed_in"}
+	total := int64(3)
+
+	tests := []struct {
+		description    string
+		withTotal      bool
+		found          []models.AuditEvent
+		findAllError   error
+		countError     error
+		expectedEvents []models.AuditEvent
+		expectedNext   *entities.Cursor
+		expectedTotal  *int64
+	}{
+		{
+			description:    "Page with a next one",
+			found:          events,
+			expectedEvents: events[:2],
+			expectedNext: &entities.Cursor{
+				ID:    events[1].ID.String(),
+				Sort:  "created_at:desc",
+				Value: "2023-06-30T12:00:00Z",
+			},
+		},
+		{
+			description:    "Last page with total",
+			withTotal:      true,
+			found:          events[:2],
+			expectedEvents: events[:2],
+			expectedTotal:  &total,
+		},
+		{
+			description:  "Error finding events",
+			findAllError: errors.New("database error"),
+		},
+		{
+			description: "Error counting events",
+			withTotal:   true,
+			found:       events[:2],
+			countError:  errors.New("database error"),
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			query := entities.AuditEventQuery{
+				Filters: filters,
+				Page:    entities.PageRequest{Limit: 2, WithTotal: test.withTotal},
+			}
+			lookahead := query
+			lookahead.Page.Limit = 3
+
+			s.auditEventRepositoryMock.EXPECT().FindAll(ctx, lookahead).Return(
+				test.found, test.findAllError,
+			)
+			if test.withTotal && test.findAllError == nil {
+				s.auditEventRepositoryMock.EXPECT().Count(ctx, filters).Return(total, test.countError)
+			}
+
+			page, err := s.service.FindAll(ctx, query)
+			if test.findAllError != nil || test.countError != nil {
+				s.Error(err)
+				s.Nil(page)
+			} else {
+				s.NoError(err)
+				s.Equal(test.expectedEvents, page.Events)
+				s.Equal(test.expectedNext, page.Next)
+				s.Equal(test.expectedTotal, page.Total)
+			}
+		})
+	}
+}
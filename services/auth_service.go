@@ -2,11 +2,13 @@ package services
 
 import (
 	"context"
+	"math"
 	"os"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 
+	"verifymy-golang-test/common"
 	"verifymy-golang-test/entities"
 	"verifymy-golang-test/models"
 	"verifymy-golang-test/repositories"
@@ -17,23 +19,64 @@ type AuthService interface {
 	SignUp(ctx context.Context, user models.User) (*entities.Credentials, error)
 	SignIn(ctx context.Context, email string, password string) (*entities.Credentials, error)
 	GetUserFromToken(ctx context.Context, accessToken string) (*models.User, error)
+	AcceptInvitation(
+		ctx context.Context, token string, password string,
+	) (*entities.Credentials, error)
+	ConfirmEmailChange(ctx context.Context, token string) (*entities.Credentials, error)
+	ChangePassword(
+		ctx context.Context, currentPassword string, newPassword string,
+	) (*entities.Credentials, error)
+	Sudo(ctx context.Context, password string) (*entities.Credentials, error)
+	InSudoMode(accessToken string) bool
 }
 
+// sudoModeLifetime is how long credentials issued by re-authenticating can
+// be used on sensitive endpoints
+const sudoModeLifetime = time.Minute * 15
+
 func NewAuthService(
 	userRepository repositories.UserRepository,
+	eventPublisher EventPublisher,
+	auditService AuditService,
+	parentalConsentService ParentalConsentService,
 ) AuthService {
 	return &authService{
-		userRepository: userRepository,
+		userRepository:         userRepository,
+		eventPublisher:         eventPublisher,
+		auditService:           auditService,
+		parentalConsentService: parentalConsentService,
 	}
 }
 
 type authService struct {
-	userRepository repositories.UserRepository
+	userRepository         repositories.UserRepository
+	eventPublisher         EventPublisher
+	auditService           AuditService
+	parentalConsentService ParentalConsentService
+}
+
+// actingAs is the context of audit events of users acting before they are
+// signed in, like when signing up
+func actingAs(ctx context.Context, user *models.User) context.Context {
+	return context.WithValue(ctx, common.AuthUser, user)
 }
 
+// SignUp registers users under the age of consent as pending until their
+// guardian consents, see ParentalConsentService
 func (s *authService) SignUp(
 	ctx context.Context, user models.User,
 ) (*entities.Credentials, error) {
+	user.Status = models.UserStatusActive
+	if s.parentalConsentService.Required(user.DateOfBirth) {
+		if err := validateGuardianEmail(&user); err != nil {
+			return nil, err
+		}
+
+		user.Status = models.UserStatusPendingParentalConsent
+	} else {
+		user.GuardianEmail = ""
+	}
+
 	foundUser, err := s.userRepository.FindByEmail(ctx, user.Email)
 	if err != nil {
 		return nil, err
@@ -47,32 +90,108 @@ func (s *authService) SignUp(
 	}
 
 	user.Password = models.SecretValue(hashedPassword)
-	signedUser, err := s.userRepository.Create(ctx, user)
-	if err != nil {
+	user.Role = models.RoleUser
+	var signedUser *models.User
+	if err := s.eventPublisher.Publish(
+		ctx, func(ctx context.Context) ([]entities.DomainEvent, error) {
+			signedUser, err = s.userRepository.Create(ctx, user)
+			if err != nil {
+				return nil, err
+			}
+
+			return []entities.DomainEvent{registeredEvent(signedUser, false)}, nil
+		},
+	); err != nil {
 		return nil, err
 	}
 
+	if err := s.auditService.Record(
+		actingAs(ctx, signedUser), AuditActionUserSignedUp, auditTargetUser, signedUser.ID.String(), nil,
+	); err != nil {
+		return nil, err
+	}
+
+	if signedUser.Status == models.UserStatusPendingParentalConsent {
+		if err := s.parentalConsentService.Request(actingAs(ctx, signedUser), signedUser); err != nil {
+			return nil, err
+		}
+	}
+
 	return s.getCredentialsFromUser(signedUser)
 }
 
 func (s *authService) getCredentialsFromUser(
 	user *models.User,
 ) (*entities.Credentials, error) {
-	expiresAt := time.Now().UTC().Add(time.Hour * 24).Unix()
+	return s.issueCredentials(user, false)
+}
+
+// issueCredentials signs an access token for user. Tokens in sudo mode also
+// carry sudo_exp, when their sudo mode is over.
+func (s *authService) issueCredentials(
+	user *models.User, sudo bool,
+) (*entities.Credentials, error) {
+	now := time.Now().UTC()
+	credentials := &entities.Credentials{
+		ExpiresAt: now.Add(time.Hour * 24).Unix(),
+	}
+
+	claims := accessTokenClaims(user.ID.String(), now, credentials.ExpiresAt)
+	if sudo {
+		credentials.SudoExpiresAt = now.Add(sudoModeLifetime).Unix()
+		claims["sudo_exp"] = credentials.SudoExpiresAt
+	}
 
-	accessToken := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"user_id": user.ID.String(),
-		"exp":     expiresAt,
-	})
-	accessTokenString, err := accessToken.SignedString([]byte(os.Getenv("SECRET_KEY")))
+	accessTokenString, err := signAccessToken(claims)
 	if err != nil {
 		return nil, err
 	}
 
-	return &entities.Credentials{
-		AccessToken: accessTokenString,
-		ExpiresAt:   expiresAt,
-	}, nil
+	credentials.AccessToken = accessTokenString
+	return credentials, nil
+}
+
+// accessTokenClaims are the claims every access token carries, user_id
+// being left out for tokens of OAuth clients acting on their own. iat keeps
+// milliseconds, so revoking the sessions of a user right before signing them
+// in doesn't revoke the new token too.
+func accessTokenClaims(userId string, issuedAt time.Time, expiresAt int64) jwt.MapClaims {
+	claims := jwt.MapClaims{
+		"iat": float64(issuedAt.UnixMilli()) / 1000,
+		"exp": expiresAt,
+	}
+	if userId != "" {
+		claims["user_id"] = userId
+	}
+
+	return claims
+}
+
+// signAccessToken signs claims with SECRET_KEY, for both users and OAuth
+// clients
+func signAccessToken(claims jwt.MapClaims) (string, error) {
+	accessToken := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+
+	return accessToken.SignedString([]byte(os.Getenv("SECRET_KEY")))
+}
+
+// parseAccessToken checks the signature and expiry of an access token and
+// returns its claims
+func parseAccessToken(accessToken string) (jwt.MapClaims, error) {
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(
+		accessToken,
+		claims,
+		func(token *jwt.Token) (interface{}, error) {
+			return []byte(os.Getenv("SECRET_KEY")), nil
+		},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Name}),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return claims, nil
 }
 
 func (s *authService) SignIn(
@@ -82,29 +201,50 @@ func (s *authService) SignIn(
 	if err != nil {
 		return nil, err
 	} else if user == nil {
-		return nil, entities.NewInvalidEmailAndOrPasswordError()
+		return nil, s.signInFailed(ctx, "", "unknown_email")
 	}
 
 	if err = utils.PasswordCompare(string(user.Password), password); err != nil {
-		return nil, entities.NewInvalidEmailAndOrPasswordError()
+		return nil, s.signInFailed(actingAs(ctx, user), user.ID.String(), "invalid_password")
+	}
+
+	if err := s.auditService.Record(
+		actingAs(ctx, user), AuditActionUserSignedIn, auditTargetUser, user.ID.String(), nil,
+	); err != nil {
+		return nil, err
 	}
 
 	return s.getCredentialsFromUser(user)
 }
 
+// signInFailed records a failed sign in. Clients are not told why it failed,
+// so they can't find out which emails are registered.
+func (s *authService) signInFailed(ctx context.Context, userId string, reason string) error {
+	if err := s.auditService.Record(
+		ctx,
+		AuditActionUserSignInFailed,
+		auditTargetUser,
+		userId,
+		map[string]interface{}{"reason": reason},
+	); err != nil {
+		return err
+	}
+
+	return entities.NewInvalidEmailAndOrPasswordError()
+}
+
 func (s *authService) GetUserFromToken(
 	ctx context.Context, token string,
 ) (*models.User, error) {
-	claims := jwt.MapClaims{}
-	_, err := jwt.ParseWithClaims(token, claims, func(token *jwt.Token) (interface{}, error) {
-		return []byte(os.Getenv("SECRET_KEY")), nil
-	})
+	claims, err := parseAccessToken(token)
 	if err != nil {
 		return nil, err
 	}
 
+	// Tokens issued to OAuth clients only work on the endpoints built for
+	// them, not on those of the user
 	userID, ok := claims["user_id"].(string)
-	if !ok {
+	if _, issuedToClient := claims["client_id"]; !ok || issuedToClient {
 		return nil, entities.NewInvalidTokenError()
 	}
 
@@ -115,5 +255,178 @@ func (s *authService) GetUserFromToken(
 		return nil, entities.NewInvalidTokenError()
 	}
 
+	issuedAt, _ := claims["iat"].(float64)
+	if sessionsRevokedSince(user, issuedAt) {
+		return nil, entities.NewInvalidTokenError()
+	}
+
 	return user, nil
 }
+
+// sessionsRevokedSince tells whether user revoked their sessions after
+// issuedAt, in seconds with millisecond precision like the iat of access
+// tokens, which invalidates what was issued to them or on their behalf
+func sessionsRevokedSince(user *models.User, issuedAt float64) bool {
+	return user.SessionsRevokedAt.Valid &&
+		int64(math.Round(issuedAt*1000)) < user.SessionsRevokedAt.Time.UnixMilli()
+}
+
+// AcceptInvitation sets the password of an invited user and signs them in.
+// The invitation can only be used once, since setting the password bumps the
+// user version the token is bound to.
+func (s *authService) AcceptInvitation(
+	ctx context.Context, token string, password string,
+) (*entities.Credentials, error) {
+	userId, version, err := parseInvitationToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepository.FindById(ctx, userId)
+	if err != nil {
+		return nil, err
+	} else if user == nil || user.Version != version {
+		return nil, entities.NewInvalidTokenError()
+	}
+
+	hashedPassword, err := utils.PasswordHash(password)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.userRepository.UpdateAttributesByUserId(
+		ctx, userId, models.User{Password: models.SecretValue(hashedPassword)}, version,
+	)
+	if _, ok := err.(*entities.PreconditionFailedError); ok {
+		return nil, entities.NewInvalidTokenError()
+	} else if err != nil {
+		return nil, err
+	}
+
+	if err := s.auditService.RecordChanges(
+		actingAs(ctx, user),
+		AuditActionUserInvitationAccepted,
+		auditTargetUser,
+		userId,
+		user,
+		map[string]interface{}{"password": hashedPassword},
+	); err != nil {
+		return nil, err
+	}
+
+	return s.getCredentialsFromUser(user)
+}
+
+// ConfirmEmailChange applies the email change requested through
+// UserService.RequestEmailChange. Sessions signed in with the old address
+// are revoked, so the user gets new credentials.
+func (s *authService) ConfirmEmailChange(
+	ctx context.Context, token string,
+) (*entities.Credentials, error) {
+	userId, email, version, err := parseEmailChangeToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepository.FindById(ctx, userId)
+	if err != nil {
+		return nil, err
+	} else if user == nil || user.Version != version {
+		return nil, entities.NewInvalidTokenError()
+	}
+
+	changes := map[string]interface{}{
+		"email":               email,
+		"sessions_revoked_at": time.Now().UTC().Truncate(time.Millisecond),
+	}
+	err = s.eventPublisher.Publish(
+		ctx, func(ctx context.Context) ([]entities.DomainEvent, error) {
+			if err := s.userRepository.UpdateColumnsByUserId(
+				ctx, userId, changes, version,
+			); err != nil {
+				return nil, err
+			}
+
+			return profileUpdatedEvents(userId, changes), nil
+		},
+	)
+	if _, ok := err.(*entities.PreconditionFailedError); ok {
+		return nil, entities.NewInvalidTokenError()
+	} else if err != nil {
+		return nil, err
+	}
+
+	if err := s.auditService.RecordChanges(
+		actingAs(ctx, user), AuditActionUserEmailChanged, auditTargetUser, userId, user, changes,
+	); err != nil {
+		return nil, err
+	}
+
+	return s.getCredentialsFromUser(user)
+}
+
+// ChangePassword replaces the password of the signed in user once the
+// current one is confirmed. Every other session of the user is revoked.
+func (s *authService) ChangePassword(
+	ctx context.Context, currentPassword string, newPassword string,
+) (*entities.Credentials, error) {
+	user := ctx.Value(common.AuthUser).(*models.User)
+	if err := utils.PasswordCompare(string(user.Password), currentPassword); err != nil {
+		return nil, entities.NewInvalidPasswordError()
+	}
+
+	hashedPassword, err := utils.PasswordHash(newPassword)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := map[string]interface{}{
+		"password":            hashedPassword,
+		"sessions_revoked_at": time.Now().UTC().Truncate(time.Millisecond),
+	}
+	if err := s.userRepository.UpdateColumnsByUserId(
+		ctx, user.ID.String(), changes, 0,
+	); err != nil {
+		return nil, err
+	}
+
+	if err := s.auditService.RecordChanges(
+		ctx, AuditActionUserPasswordChanged, auditTargetUser, user.ID.String(), user, changes,
+	); err != nil {
+		return nil, err
+	}
+
+	return s.getCredentialsFromUser(user)
+}
+
+// Sudo re-authenticates the signed in user. The credentials it issues are
+// in sudo mode for a short while, see InSudoMode.
+func (s *authService) Sudo(
+	ctx context.Context, password string,
+) (*entities.Credentials, error) {
+	user := ctx.Value(common.AuthUser).(*models.User)
+	if err := utils.PasswordCompare(string(user.Password), password); err != nil {
+		return nil, entities.NewInvalidPasswordError()
+	}
+
+	if err := s.auditService.Record(
+		ctx, AuditActionUserReauthenticated, auditTargetUser, user.ID.String(), nil,
+	); err != nil {
+		return nil, err
+	}
+
+	return s.issueCredentials(user, true)
+}
+
+// InSudoMode tells whether the sudo mode of accessToken is not over yet. The
+// token is otherwise checked by GetUserFromToken.
+func (s *authService) InSudoMode(accessToken string) bool {
+	claims, err := parseAccessToken(accessToken)
+	if err != nil {
+		return false
+	}
+
+	sudoExpiresAt, ok := claims["sudo_exp"].(float64)
+
+	return ok && time.Now().UTC().Unix() < int64(sudoExpiresAt)
+}
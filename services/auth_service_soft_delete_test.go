@@ -0,0 +1,117 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"verifymy-golang-test/entities"
+	"verifymy-golang-test/models"
+	"verifymy-golang-test/providers"
+	"verifymy-golang-test/repositories"
+)
+
+// authServiceSoftDeleteTestSuite signs users up against an in-memory SQLite
+// database to check their tokens stop working once they are deleted or their
+// sessions are revoked.
+type authServiceSoftDeleteTestSuite struct {
+	suite.Suite
+	ctx            context.Context
+	userRepository repositories.UserRepository
+	service        AuthService
+}
+
+func TestAuthServiceSoftDeleteTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(authServiceSoftDeleteTestSuite))
+}
+
+func (s *authServiceSoftDeleteTestSuite) SetupTest() {
+	s.ctx = context.Background()
+
+	dbconn, err := gorm.Open(
+		sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", uuid.NewString())),
+		&gorm.Config{Logger: logger.Default.LogMode(logger.Silent), TranslateError: true},
+	)
+	s.Require().NoError(err)
+	s.Require().NoError(providers.Migrate(dbconn))
+
+	s.userRepository = repositories.NewUserRepository(dbconn)
+	eventPublisher := NewEventPublisher(
+		repositories.NewTransactor(dbconn), repositories.NewOutboxEventRepository(dbconn),
+	)
+	auditService := NewAuditService(repositories.NewAuditEventRepository(dbconn))
+	parentalConsentService, err := NewParentalConsentService(
+		s.userRepository, eventPublisher, auditService, providers.NewMailer(zap.NewNop()),
+	)
+	s.Require().NoError(err)
+
+	s.service = NewAuthService(
+		s.userRepository, eventPublisher, auditService, parentalConsentService,
+	)
+}
+
+func (s *authServiceSoftDeleteTestSuite) TestDeletedUserTokenIsRejected() {
+	credentials, err := s.service.SignUp(s.ctx, models.User{
+		Name:        "Peter Parker",
+		DateOfBirth: models.Date(time.Date(2001, 8, 10, 0, 0, 0, 0, time.UTC)),
+		Email:       "peter.parker@nyork.co",
+		Password:    "sp00der",
+	})
+	s.Require().NoError(err)
+
+	user, err := s.service.GetUserFromToken(s.ctx, credentials.AccessToken)
+	s.Require().NoError(err)
+
+	deleted, err := s.userRepository.DeleteById(s.ctx, user.ID.String())
+	s.Require().NoError(err)
+	s.Require().True(deleted)
+
+	user, err = s.service.GetUserFromToken(s.ctx, credentials.AccessToken)
+	s.Equal(entities.NewInvalidTokenError(), err)
+	s.Nil(user)
+
+	_, err = s.service.SignIn(s.ctx, "peter.parker@nyork.co", "sp00der")
+	s.Equal(entities.NewInvalidEmailAndOrPasswordError(), err)
+}
+
+func (s *authServiceSoftDeleteTestSuite) TestEmailChangeRevokesSessions() {
+	credentials, err := s.service.SignUp(s.ctx, models.User{
+		Name:        "Peter Parker",
+		DateOfBirth: models.Date(time.Date(2001, 8, 10, 0, 0, 0, 0, time.UTC)),
+		Email:       "peter.parker@nyork.co",
+		Password:    "sp00der",
+	})
+	s.Require().NoError(err)
+
+	user, err := s.service.GetUserFromToken(s.ctx, credentials.AccessToken)
+	s.Require().NoError(err)
+
+	token, err := newEmailChangeToken(user, "Spidey@nyork.co")
+	s.Require().NoError(err)
+
+	time.Sleep(time.Millisecond)
+	newCredentials, err := s.service.ConfirmEmailChange(s.ctx, token)
+	s.Require().NoError(err)
+
+	_, err = s.service.GetUserFromToken(s.ctx, credentials.AccessToken)
+	s.Equal(entities.NewInvalidTokenError(), err)
+
+	user, err = s.service.GetUserFromToken(s.ctx, newCredentials.AccessToken)
+	s.Require().NoError(err)
+	s.Equal("spidey@nyork.co", user.Email)
+
+	_, err = s.service.ConfirmEmailChange(s.ctx, token)
+	s.Equal(entities.NewInvalidTokenError(), err)
+
+	_, err = s.service.SignIn(s.ctx, "spidey@nyork.co", "sp00der")
+	s.NoError(err)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -12,17 +13,38 @@ import (
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/suite"
 
+	"verifymy-golang-test/common"
+	"verifymy-golang-test/entities"
 	mock_repositories "verifymy-golang-test/mocks/repositories"
+	mock_services "verifymy-golang-test/mocks/services"
 	"verifymy-golang-test/models"
 	"verifymy-golang-test/utils"
 )
 
+// actedBy matches the context of audit events carried out by user
+type actedBy struct {
+	user *models.User
+}
+
+func (m actedBy) Matches(x interface{}) bool {
+	ctx, ok := x.(context.Context)
+
+	return ok && ctx.Value(common.AuthUser) == m.user
+}
+
+func (m actedBy) String() string {
+	return "acted by " + m.user.ID.String()
+}
+
 type authServiceTestSuite struct {
 	suite.Suite
-	ctrl               *gomock.Controller
-	ctx                context.Context
-	userRepositoryMock *mock_repositories.MockUserRepository
-	authService        AuthService
+	ctrl                      *gomock.Controller
+	ctx                       context.Context
+	userRepositoryMock        *mock_repositories.MockUserRepository
+	outboxEventRepositoryMock *mock_repositories.MockOutboxEventRepository
+	auditServiceMock          *mock_services.MockAuditService
+	parentalConsentMock       *mock_services.MockParentalConsentService
+	authService               AuthService
 }
 
 func TestAuthService(t *testing.T) {
@@ -34,7 +56,21 @@ func (s *authServiceTestSuite) SetupTest() {
 	s.ctrl = gomock.NewController(s.T())
 	s.ctx = context.Background()
 	s.userRepositoryMock = mock_repositories.NewMockUserRepository(s.ctrl)
-	s.authService = NewAuthService(s.userRepositoryMock)
+	s.outboxEventRepositoryMock = mock_repositories.NewMockOutboxEventRepository(s.ctrl)
+	s.auditServiceMock = mock_services.NewMockAuditService(s.ctrl)
+	s.parentalConsentMock = mock_services.NewMockParentalConsentService(s.ctrl)
+
+	transactorMock := mock_repositories.NewMockTransactor(s.ctrl)
+	transactorMock.EXPECT().WithinTransaction(
+		gomock.Any(), gomock.Any(),
+	).DoAndReturn(runWithinTransaction).AnyTimes()
+
+	s.authService = NewAuthService(
+		s.userRepositoryMock,
+		NewEventPublisher(transactorMock, s.outboxEventRepositoryMock),
+		s.auditServiceMock,
+		s.parentalConsentMock,
+	)
 }
 
 func (s *authServiceTestSuite) TestSignUp() {
@@ -64,17 +100,47 @@ func (s *authServiceTestSuite) TestSignUp() {
 		Password: "my-password",
 		Address:  "Jl. Raya Bogor",
 	}
+	child := user
+	child.Status = models.UserStatusPendingParentalConsent
+	child.GuardianEmail = "jane.doe@gmail.com"
 
 	tests := []struct {
 		description         string
+		guardianEmail       string
+		underAge            bool
 		findByEmailResponse *models.User
 		findByEmailError    error
 		createUserResponse  *models.User
 		createUserError     error
+		expectedStatus      string
+		expectedError       error
 	}{
 		{
 			description:        "Success",
 			createUserResponse: &user,
+			expectedStatus:     models.UserStatusActive,
+		},
+		{
+			description:        "Under the age of consent",
+			guardianEmail:      " jane.doe@gmail.com ",
+			underAge:           true,
+			createUserResponse: &child,
+			expectedStatus:     models.UserStatusPendingParentalConsent,
+		},
+		{
+			description: "Under the age of consent without guardian",
+			underAge:    true,
+			expectedError: entities.NewValidationError(
+				"guardian_email is required for users under the age of consent",
+			),
+		},
+		{
+			description:   "Under the age of consent with their own e-mail as guardian",
+			guardianEmail: "John.Doe@gmail.com",
+			underAge:      true,
+			expectedError: entities.NewValidationError(
+				"guardian_email must not be the e-mail of the user",
+			),
 		},
 		{
 			description:      "Failed to fetch user by e-mail",
@@ -88,6 +154,7 @@ func (s *authServiceTestSuite) TestSignUp() {
 		{
 			description:     "Failed to create user",
 			createUserError: errors.New("failed to create user"),
+			expectedStatus:  models.UserStatusActive,
 		},
 	}
 
@@ -95,21 +162,48 @@ func (s *authServiceTestSuite) TestSignUp() {
 		s.Run(test.description, func() {
 			s.SetupTest()
 
-			s.userRepositoryMock.EXPECT().FindByEmail(s.ctx, gomock.Any()).Return(
-				test.findByEmailResponse, test.findByEmailError,
-			)
+			payload := payload
+			payload.GuardianEmail = test.guardianEmail
+			s.parentalConsentMock.EXPECT().Required(payload.DateOfBirth).Return(test.underAge)
 
-			if test.findByEmailResponse == nil && test.findByEmailError == nil {
-				s.userRepositoryMock.EXPECT().Create(s.ctx, gomock.Any()).Return(
-					test.createUserResponse, test.createUserError,
+			if test.expectedError == nil {
+				s.userRepositoryMock.EXPECT().FindByEmail(s.ctx, gomock.Any()).Return(
+					test.findByEmailResponse, test.findByEmailError,
 				)
 			}
 
+			if test.expectedError == nil && test.findByEmailResponse == nil && test.findByEmailError == nil {
+				s.userRepositoryMock.EXPECT().Create(s.ctx, gomock.Any()).DoAndReturn(
+					func(ctx context.Context, created models.User) (*models.User, error) {
+						s.Equal(test.expectedStatus, created.Status)
+						s.Equal(strings.TrimSpace(test.guardianEmail), created.GuardianEmail)
+
+						return test.createUserResponse, test.createUserError
+					},
+				)
+			}
+			if test.createUserResponse != nil {
+				s.outboxEventRepositoryMock.EXPECT().Create(s.ctx, outboxEventsOf(
+					entities.UserRegistered{UserID: user.ID.String()},
+				)).Return(nil)
+				s.auditServiceMock.EXPECT().Record(
+					actedBy{test.createUserResponse}, AuditActionUserSignedUp, "user", user.ID.String(), nil,
+				).Return(nil)
+			}
+			if test.underAge && test.createUserResponse != nil {
+				s.parentalConsentMock.EXPECT().Request(
+					actedBy{test.createUserResponse}, test.createUserResponse,
+				).Return(nil)
+			}
+
 			credentials, err := s.authService.SignUp(
 				s.ctx,
 				payload,
 			)
-			if test.findByEmailResponse != nil {
+			if test.expectedError != nil {
+				s.Equal(test.expectedError, err)
+				s.Nil(credentials)
+			} else if test.findByEmailResponse != nil {
 				s.NotNil(err)
 				s.ErrorContains(err, "e-mail is already in use")
 				s.Nil(credentials)
@@ -153,12 +247,27 @@ func (s *authServiceTestSuite) TestSignIn() {
 		findUserByEmailResponse *models.User
 		findUserByEmailError    error
 		invalidPasswordError    bool
+		expectedAction          string
+		expectedTargetId        string
+		expectedMetadata        map[string]interface{}
+		recordError             error
 	}{
 		{
 			description:             "Success",
 			email:                   user.Email,
 			password:                password,
 			findUserByEmailResponse: &user,
+			expectedAction:          AuditActionUserSignedIn,
+			expectedTargetId:        user.ID.String(),
+		},
+		{
+			description:             "Error recording sign in",
+			email:                   user.Email,
+			password:                password,
+			findUserByEmailResponse: &user,
+			expectedAction:          AuditActionUserSignedIn,
+			expectedTargetId:        user.ID.String(),
+			recordError:             errors.New("error recording audit event"),
 		},
 		{
 			description:          "Failed to fetch user by e-mail",
@@ -167,9 +276,11 @@ func (s *authServiceTestSuite) TestSignIn() {
 			findUserByEmailError: errors.New("failed to fetch user by e-mail"),
 		},
 		{
-			description: "User not found",
-			email:       user.Email,
-			password:    password,
+			description:      "User not found",
+			email:            user.Email,
+			password:         password,
+			expectedAction:   AuditActionUserSignInFailed,
+			expectedMetadata: map[string]interface{}{"reason": "unknown_email"},
 		},
 		{
 			description:             "Invalid password",
@@ -177,6 +288,9 @@ func (s *authServiceTestSuite) TestSignIn() {
 			password:                "invalid-password",
 			findUserByEmailResponse: &user,
 			invalidPasswordError:    true,
+			expectedAction:          AuditActionUserSignInFailed,
+			expectedTargetId:        user.ID.String(),
+			expectedMetadata:        map[string]interface{}{"reason": "invalid_password"},
 		},
 	}
 
@@ -188,6 +302,16 @@ func (s *authServiceTestSuite) TestSignIn() {
 				test.findUserByEmailResponse,
 				test.findUserByEmailError,
 			)
+			if test.expectedAction != "" {
+				var ctx interface{} = s.ctx
+				if test.findUserByEmailResponse != nil {
+					ctx = actedBy{test.findUserByEmailResponse}
+				}
+
+				s.auditServiceMock.EXPECT().Record(
+					ctx, test.expectedAction, "user", test.expectedTargetId, test.expectedMetadata,
+				).Return(test.recordError)
+			}
 
 			credentials, err := s.authService.SignIn(
 				s.ctx,
@@ -198,6 +322,9 @@ func (s *authServiceTestSuite) TestSignIn() {
 				s.NotNil(err)
 				s.ErrorContains(err, test.findUserByEmailError.Error())
 				s.Nil(credentials)
+			} else if test.recordError != nil {
+				s.Equal(test.recordError, err)
+				s.Nil(credentials)
 			} else if test.findUserByEmailResponse == nil || test.invalidPasswordError {
 				s.NotNil(err)
 				s.ErrorContains(err, "invalid e-mail and/or password")
@@ -228,6 +355,13 @@ func (s *authServiceTestSuite) TestGetUserFromToken() {
 	})
 	invalidAccessTokenString, _ := invalidAccessToken.SignedString([]byte(os.Getenv("SECRET_KEY")))
 
+	clientAccessToken := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"user_id":   userId,
+		"client_id": uuid.NewString(),
+		"exp":       time.Now().UTC().Add(time.Minute * 3).Unix(),
+	})
+	clientAccessTokenString, _ := clientAccessToken.SignedString([]byte(os.Getenv("SECRET_KEY")))
+
 	user := models.User{
 		ID:    userId,
 		Name:  "John Doe",
@@ -263,6 +397,11 @@ func (s *authServiceTestSuite) TestGetUserFromToken() {
 			accessToken:             invalidAccessTokenString,
 			missingUserIdClaimError: true,
 		},
+		{
+			description:             "Token issued to an OAuth client",
+			accessToken:             clientAccessTokenString,
+			missingUserIdClaimError: true,
+		},
 		{
 			description:   "Failed to fetch user by ID",
 			accessToken:   accessTokenString,
@@ -307,3 +446,410 @@ func (s *authServiceTestSuite) TestGetUserFromToken() {
 		})
 	}
 }
+
+func (s *authServiceTestSuite) TestAcceptInvitation() {
+	user := models.User{
+		ID:      uuid.New(),
+		Name:    "Mary Jane",
+		Email:   "mary.jane@nyork.co",
+		Version: 1,
+	}
+	token, err := newInvitationToken(&user)
+	if err != nil {
+		s.FailNow(err.Error())
+	}
+
+	accessToken := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"user_id": user.ID.String(),
+		"exp":     time.Now().UTC().Add(time.Minute * 3).Unix(),
+	})
+	accessTokenString, _ := accessToken.SignedString([]byte(os.Getenv("SECRET_KEY")))
+
+	changedUser := user
+	changedUser.Version = 2
+
+	tests := []struct {
+		description      string
+		token            string
+		skipFindById     bool
+		findByIdResponse *models.User
+		findByIdError    error
+		skipUpdate       bool
+		updateError      error
+		expectedError    string
+	}{
+		{
+			description:      "Success",
+			token:            token,
+			findByIdResponse: &user,
+		},
+		{
+			description:   "Malformed token",
+			token:         "invalid-token",
+			skipFindById:  true,
+			skipUpdate:    true,
+			expectedError: "invalid token",
+		},
+		{
+			description:   "Access tokens are not invitations",
+			token:         accessTokenString,
+			skipFindById:  true,
+			skipUpdate:    true,
+			expectedError: "invalid token",
+		},
+		{
+			description:   "User not found",
+			token:         token,
+			skipUpdate:    true,
+			expectedError: "invalid token",
+		},
+		{
+			description:      "Invitation already accepted",
+			token:            token,
+			findByIdResponse: &changedUser,
+			skipUpdate:       true,
+			expectedError:    "invalid token",
+		},
+		{
+			description:   "Error finding user",
+			token:         token,
+			findByIdError: errors.New("error finding user"),
+			skipUpdate:    true,
+			expectedError: "error finding user",
+		},
+		{
+			description:      "Invitation accepted concurrently",
+			token:            token,
+			findByIdResponse: &user,
+			updateError:      entities.NewPreconditionFailedError(),
+			expectedError:    "invalid token",
+		},
+		{
+			description:      "Error updating password",
+			token:            token,
+			findByIdResponse: &user,
+			updateError:      errors.New("error updating password"),
+			expectedError:    "error updating password",
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			s.SetupTest()
+
+			if !test.skipFindById {
+				s.userRepositoryMock.EXPECT().FindById(s.ctx, user.ID.String()).Return(
+					test.findByIdResponse, test.findByIdError,
+				)
+			}
+			if !test.skipUpdate {
+				s.userRepositoryMock.EXPECT().UpdateAttributesByUserId(
+					s.ctx, user.ID.String(), gomock.Any(), int64(1),
+				).DoAndReturn(func(
+					ctx context.Context, userId string, data models.User, expectedVersion int64,
+				) error {
+					s.NoError(utils.PasswordCompare(string(data.Password), "my-password"))
+
+					return test.updateError
+				})
+			}
+			if test.expectedError == "" {
+				s.auditServiceMock.EXPECT().RecordChanges(
+					actedBy{&user},
+					AuditActionUserInvitationAccepted,
+					"user",
+					user.ID.String(),
+					&user,
+					gomock.Any(),
+				).Return(nil)
+			}
+
+			credentials, err := s.authService.AcceptInvitation(
+				s.ctx, test.token, "my-password",
+			)
+			if test.expectedError != "" {
+				s.ErrorContains(err, test.expectedError)
+				s.Nil(credentials)
+			} else {
+				s.NoError(err)
+				s.NotNil(credentials)
+			}
+		})
+	}
+}
+
+func (s *authServiceTestSuite) TestConfirmEmailChange() {
+	user := models.User{
+		ID:      uuid.New(),
+		Name:    "Peter Parker",
+		Email:   "peter.parker@nyork.co",
+		Version: 4,
+	}
+	token, err := newEmailChangeToken(&user, "spidey@nyork.co")
+	if err != nil {
+		s.FailNow(err.Error())
+	}
+
+	invitationToken, _ := newInvitationToken(&user)
+
+	changedUser := user
+	changedUser.Version = 5
+
+	tests := []struct {
+		description      string
+		token            string
+		skipFindById     bool
+		findByIdResponse *models.User
+		findByIdError    error
+		skipUpdate       bool
+		updateError      error
+		expectedError    string
+	}{
+		{
+			description:      "Success",
+			token:            token,
+			findByIdResponse: &user,
+		},
+		{
+			description:   "Malformed token",
+			token:         "invalid-token",
+			skipFindById:  true,
+			skipUpdate:    true,
+			expectedError: "invalid token",
+		},
+		{
+			description:   "Invitations are not email changes",
+			token:         invitationToken,
+			skipFindById:  true,
+			skipUpdate:    true,
+			expectedError: "invalid token",
+		},
+		{
+			description:   "User not found",
+			token:         token,
+			skipUpdate:    true,
+			expectedError: "invalid token",
+		},
+		{
+			description:      "User changed since the request",
+			token:            token,
+			findByIdResponse: &changedUser,
+			skipUpdate:       true,
+			expectedError:    "invalid token",
+		},
+		{
+			description:   "Error finding user",
+			token:         token,
+			findByIdError: errors.New("error finding user"),
+			skipUpdate:    true,
+			expectedError: "error finding user",
+		},
+		{
+			description:      "Confirmed concurrently",
+			token:            token,
+			findByIdResponse: &user,
+			updateError:      entities.NewPreconditionFailedError(),
+			expectedError:    "invalid token",
+		},
+		{
+			description:      "Email already in use",
+			token:            token,
+			findByIdResponse: &user,
+			updateError:      entities.NewEmailAlreadyInUseError("spidey@nyork.co"),
+			expectedError:    "e-mail is already in use",
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			s.SetupTest()
+
+			if !test.skipFindById {
+				s.userRepositoryMock.EXPECT().FindById(s.ctx, user.ID.String()).Return(
+					test.findByIdResponse, test.findByIdError,
+				)
+			}
+			if !test.skipUpdate {
+				s.userRepositoryMock.EXPECT().UpdateColumnsByUserId(
+					s.ctx, user.ID.String(), gomock.Any(), int64(4),
+				).DoAndReturn(func(
+					ctx context.Context, userId string, columns map[string]interface{}, expectedVersion int64,
+				) error {
+					s.Equal("spidey@nyork.co", columns["email"])
+					s.IsType(time.Time{}, columns["sessions_revoked_at"])
+
+					return test.updateError
+				})
+			}
+			if test.expectedError == "" {
+				s.outboxEventRepositoryMock.EXPECT().Create(s.ctx, outboxEventsOf(
+					entities.UserProfileUpdated{UserID: user.ID.String(), Fields: []string{"email"}},
+				)).Return(nil)
+				s.auditServiceMock.EXPECT().RecordChanges(
+					actedBy{&user},
+					AuditActionUserEmailChanged,
+					"user",
+					user.ID.String(),
+					&user,
+					gomock.Any(),
+				).Return(nil)
+			}
+
+			credentials, err := s.authService.ConfirmEmailChange(s.ctx, test.token)
+			if test.expectedError != "" {
+				s.ErrorContains(err, test.expectedError)
+				s.Nil(credentials)
+			} else {
+				s.NoError(err)
+				s.NotNil(credentials)
+			}
+		})
+	}
+}
+
+func (s *authServiceTestSuite) TestChangePassword() {
+	hashedPassword, err := utils.PasswordHash("old-password")
+	if err != nil {
+		s.FailNow(err.Error())
+	}
+
+	user := &models.User{
+		ID:       uuid.New(),
+		Password: models.SecretValue(hashedPassword),
+		Version:  3,
+	}
+	ctx := context.WithValue(s.ctx, common.AuthUser, user)
+
+	tests := []struct {
+		description     string
+		currentPassword string
+		skipUpdate      bool
+		updateError     error
+		expectedError   string
+	}{
+		{
+			description:     "Success",
+			currentPassword: "old-password",
+		},
+		{
+			description:     "Wrong current password",
+			currentPassword: "wrong-password",
+			skipUpdate:      true,
+			expectedError:   "invalid password",
+		},
+		{
+			description:     "Error updating password",
+			currentPassword: "old-password",
+			updateError:     errors.New("error updating password"),
+			expectedError:   "error updating password",
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			s.SetupTest()
+
+			if !test.skipUpdate {
+				s.userRepositoryMock.EXPECT().UpdateColumnsByUserId(
+					ctx, user.ID.String(), gomock.Any(), int64(0),
+				).DoAndReturn(func(
+					ctx context.Context, userId string, columns map[string]interface{}, expectedVersion int64,
+				) error {
+					s.NoError(utils.PasswordCompare(columns["password"].(string), "new-password"))
+					s.IsType(time.Time{}, columns["sessions_revoked_at"])
+
+					return test.updateError
+				})
+			}
+			if test.expectedError == "" {
+				s.auditServiceMock.EXPECT().RecordChanges(
+					ctx, AuditActionUserPasswordChanged, "user", user.ID.String(), user, gomock.Any(),
+				).Return(nil)
+			}
+
+			credentials, err := s.authService.ChangePassword(
+				ctx, test.currentPassword, "new-password",
+			)
+			if test.expectedError != "" {
+				s.ErrorContains(err, test.expectedError)
+				s.Nil(credentials)
+			} else {
+				s.NoError(err)
+				s.NotEmpty(credentials.AccessToken)
+				s.Zero(credentials.SudoExpiresAt)
+			}
+		})
+	}
+}
+
+func (s *authServiceTestSuite) TestSudo() {
+	hashedPassword, err := utils.PasswordHash("my-password")
+	if err != nil {
+		s.FailNow(err.Error())
+	}
+
+	user := &models.User{
+		ID:       uuid.New(),
+		Password: models.SecretValue(hashedPassword),
+	}
+	ctx := context.WithValue(s.ctx, common.AuthUser, user)
+
+	credentials, err := s.authService.Sudo(ctx, "wrong-password")
+	s.Equal(entities.NewInvalidPasswordError(), err)
+	s.Nil(credentials)
+
+	s.auditServiceMock.EXPECT().Record(
+		ctx, AuditActionUserReauthenticated, "user", user.ID.String(), nil,
+	).Return(nil)
+
+	credentials, err = s.authService.Sudo(ctx, "my-password")
+	s.Require().NoError(err)
+	s.InDelta(time.Now().UTC().Add(sudoModeLifetime).Unix(), credentials.SudoExpiresAt, 1)
+	s.True(s.authService.InSudoMode(credentials.AccessToken))
+}
+
+func (s *authServiceTestSuite) TestInSudoMode() {
+	tokenWithClaims := func(claims jwt.MapClaims) string {
+		token, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).
+			SignedString([]byte(os.Getenv("SECRET_KEY")))
+
+		return token
+	}
+	expiresAt := time.Now().UTC().Add(time.Hour).Unix()
+
+	tests := []struct {
+		description string
+		accessToken string
+		expected    bool
+	}{
+		{
+			description: "In sudo mode",
+			accessToken: tokenWithClaims(jwt.MapClaims{
+				"exp":      expiresAt,
+				"sudo_exp": time.Now().UTC().Add(time.Minute).Unix(),
+			}),
+			expected: true,
+		},
+		{
+			description: "Sudo mode is over",
+			accessToken: tokenWithClaims(jwt.MapClaims{
+				"exp":      expiresAt,
+				"sudo_exp": time.Now().UTC().Add(-time.Minute).Unix(),
+			}),
+		},
+		{
+			description: "Regular token",
+			accessToken: tokenWithClaims(jwt.MapClaims{"exp": expiresAt}),
+		},
+		{
+			description: "Malformed token",
+			accessToken: "invalid-token",
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			s.Equal(test.expected, s.authService.InSudoMode(test.accessToken))
+		})
+	}
+}
@@ -0,0 +1,346 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"verifymy-golang-test/entities"
+	"verifymy-golang-test/models"
+	"verifymy-golang-test/repositories"
+	"verifymy-golang-test/utils"
+)
+
+const (
+	maxClientNameLength = 255
+	maxClientURILength  = 2048
+)
+
+// ClientService manages the relying parties calling the API for age checks,
+// and authenticates them with their client credentials. Every change leaves
+// an audit record.
+type ClientService interface {
+	Create(
+		ctx context.Context, changes entities.ClientChanges,
+	) (*models.Client, string, error)
+	FindAll(ctx context.Context) ([]models.Client, error)
+	FindById(ctx context.Context, id string) (*models.Client, error)
+	Update(
+		ctx context.Context, id string, changes entities.ClientChanges,
+	) (*models.Client, error)
+	RotateSecret(ctx context.Context, id string) (string, error)
+	DeleteById(ctx context.Context, id string) error
+	Authenticate(ctx context.Context, id string, secret string) (*models.Client, error)
+}
+
+func NewClientService(
+	transactor repositories.Transactor,
+	clientRepository repositories.ClientRepository,
+	oauthGrantRepository repositories.OAuthGrantRepository,
+	oauthAuthorizationCodeRepository repositories.OAuthAuthorizationCodeRepository,
+	auditService AuditService,
+) ClientService {
+	return &clientService{
+		transactor:                       transactor,
+		clientRepository:                 clientRepository,
+		oauthGrantRepository:             oauthGrantRepository,
+		oauthAuthorizationCodeRepository: oauthAuthorizationCodeRepository,
+		auditService:                     auditService,
+		now:                              time.Now,
+	}
+}
+
+type clientService struct {
+	transactor                       repositories.Transactor
+	clientRepository                 repositories.ClientRepository
+	oauthGrantRepository             repositories.OAuthGrantRepository
+	oauthAuthorizationCodeRepository repositories.OAuthAuthorizationCodeRepository
+	auditService                     AuditService
+	now                              func() time.Time
+}
+
+// Create requires a name and scopes, and returns the generated secret along
+// with the client. Only its hash is kept, so it is never shown again.
+func (s *clientService) Create(
+	ctx context.Context, changes entities.ClientChanges,
+) (*models.Client, string, error) {
+	if changes.Name == nil {
+		return nil, "", entities.NewValidationError("name is required")
+	} else if changes.Scopes == nil {
+		return nil, "", entities.NewValidationError("scopes is required")
+	}
+
+	attributes, err := clientAttributes(changes)
+	if err != nil {
+		return nil, "", err
+	}
+
+	secret, secretHash, err := newClientSecret()
+	if err != nil {
+		return nil, "", err
+	}
+
+	client := models.Client{
+		Name:           attributes["name"].(string),
+		RedirectURIs:   models.StringList{},
+		AllowedOrigins: models.StringList{},
+		Scopes:         attributes["scopes"].(models.StringList),
+		SecretHash:     models.SecretValue(secretHash),
+	}
+	if value, ok := attributes["redirect_uris"]; ok {
+		client.RedirectURIs = value.(models.StringList)
+	}
+	if value, ok := attributes["allowed_origins"]; ok {
+		client.AllowedOrigins = value.(models.StringList)
+	}
+
+	created, err := s.clientRepository.Create(ctx, client)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if err := s.auditService.Record(
+		ctx,
+		AuditActionClientCreated,
+		auditTargetClient,
+		created.ID.String(),
+		map[string]interface{}{"name": created.Name, "scopes": created.Scopes},
+	); err != nil {
+		return nil, "", err
+	}
+
+	return created, secret, nil
+}
+
+func (s *clientService) FindAll(ctx context.Context) ([]models.Client, error) {
+	return s.clientRepository.FindAll(ctx)
+}
+
+func (s *clientService) FindById(ctx context.Context, id string) (*models.Client, error) {
+	client, err := s.clientRepository.FindById(ctx, id)
+	if err != nil {
+		return nil, err
+	} else if client == nil {
+		return nil, entities.NewItemNotFoundError("Client", id)
+	}
+
+	return client, nil
+}
+
+func (s *clientService) Update(
+	ctx context.Context, id string, changes entities.ClientChanges,
+) (*models.Client, error) {
+	client, err := s.FindById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	attributes, err := clientAttributes(changes)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := *client
+	if value, ok := attributes["name"]; ok {
+		updated.Name = value.(string)
+	}
+	if value, ok := attributes["redirect_uris"]; ok {
+		updated.RedirectURIs = value.(models.StringList)
+	}
+	if value, ok := attributes["allowed_origins"]; ok {
+		updated.AllowedOrigins = value.(models.StringList)
+	}
+	if value, ok := attributes["scopes"]; ok {
+		updated.Scopes = value.(models.StringList)
+	}
+
+	if err := s.clientRepository.Update(ctx, updated); err != nil {
+		return nil, err
+	}
+
+	if err := s.auditService.RecordChanges(
+		ctx, AuditActionClientUpdated, auditTargetClient, id, client, attributes,
+	); err != nil {
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+// RotateSecret replaces the secret of the client right away, so the previous
+// one stops working, and revokes what the client was granted through OAuth,
+// since whoever held the previous secret may hold its tokens too
+func (s *clientService) RotateSecret(ctx context.Context, id string) (string, error) {
+	client, err := s.FindById(ctx, id)
+	if err != nil {
+		return "", err
+	}
+
+	secret, secretHash, err := newClientSecret()
+	if err != nil {
+		return "", err
+	}
+
+	client.SecretHash = models.SecretValue(secretHash)
+	if err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.clientRepository.Update(ctx, *client); err != nil {
+			return err
+		}
+
+		if err := s.revokeOAuth(ctx, id); err != nil {
+			return err
+		}
+
+		return s.auditService.Record(
+			ctx, AuditActionClientSecretRotated, auditTargetClient, id, nil,
+		)
+	}); err != nil {
+		return "", err
+	}
+
+	return secret, nil
+}
+
+// DeleteById keeps the sessions of the client, which still tell what it was
+// billed for, but revokes what it was granted through OAuth
+func (s *clientService) DeleteById(ctx context.Context, id string) error {
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		deleted, err := s.clientRepository.DeleteById(ctx, id)
+		if err != nil {
+			return err
+		} else if !deleted {
+			return entities.NewItemNotFoundError("Client", id)
+		}
+
+		if err := s.revokeOAuth(ctx, id); err != nil {
+			return err
+		}
+
+		return s.auditService.Record(ctx, AuditActionClientDeleted, auditTargetClient, id, nil)
+	})
+}
+
+// revokeOAuth revokes the grants of the client, along with every token
+// issued from them, and deletes its authorization codes not exchanged yet
+func (s *clientService) revokeOAuth(ctx context.Context, id string) error {
+	if err := s.oauthGrantRepository.RevokeByClientId(ctx, id, s.now().UTC()); err != nil {
+		return err
+	}
+
+	return s.oauthAuthorizationCodeRepository.DeleteByClientId(ctx, id)
+}
+
+// Authenticate checks the client credentials, without telling an unknown
+// client from a wrong secret
+func (s *clientService) Authenticate(
+	ctx context.Context, id string, secret string,
+) (*models.Client, error) {
+	client, err := s.clientRepository.FindById(ctx, id)
+	if err != nil {
+		return nil, err
+	} else if client == nil {
+		return nil, entities.NewInvalidClientError()
+	}
+
+	if err := utils.PasswordCompare(string(client.SecretHash), secret); err != nil {
+		return nil, entities.NewInvalidClientError()
+	}
+
+	return client, nil
+}
+
+// clientAttributes validates changes and returns them keyed by column
+func clientAttributes(changes entities.ClientChanges) (map[string]interface{}, error) {
+	attributes := map[string]interface{}{}
+
+	if changes.Name != nil {
+		name := strings.TrimSpace(*changes.Name)
+		if name == "" || len(name) > maxClientNameLength {
+			return nil, entities.NewValidationError(fmt.Sprintf(
+				"name must be between 1 and %d characters long", maxClientNameLength,
+			))
+		}
+
+		attributes["name"] = name
+	}
+
+	if changes.RedirectURIs != nil {
+		redirectURIs := models.StringList{}
+		for _, redirectURI := range *changes.RedirectURIs {
+			parsed, err := url.Parse(redirectURI)
+			if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") ||
+				parsed.Host == "" || parsed.Fragment != "" || len(redirectURI) > maxClientURILength {
+				return nil, entities.NewValidationError(
+					"redirect_uris must only hold absolute http or https URLs without fragment",
+				)
+			}
+
+			if !redirectURIs.Includes(redirectURI) {
+				redirectURIs = append(redirectURIs, redirectURI)
+			}
+		}
+
+		attributes["redirect_uris"] = redirectURIs
+	}
+
+	if changes.AllowedOrigins != nil {
+		allowedOrigins := models.StringList{}
+		for _, origin := range *changes.AllowedOrigins {
+			parsed, err := url.Parse(origin)
+			if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") ||
+				parsed.Host == "" || parsed.Scheme+"://"+parsed.Host != origin {
+				return nil, entities.NewValidationError(
+					"allowed_origins must only hold origins, like https://example.com",
+				)
+			}
+
+			if !allowedOrigins.Includes(origin) {
+				allowedOrigins = append(allowedOrigins, origin)
+			}
+		}
+
+		attributes["allowed_origins"] = allowedOrigins
+	}
+
+	if changes.Scopes != nil {
+		scopes := models.StringList{}
+		for _, scope := range *changes.Scopes {
+			if !models.StringList(models.ClientScopes).Includes(scope) {
+				return nil, entities.NewValidationError(fmt.Sprintf(
+					"scopes must only hold %v", models.ClientScopes,
+				))
+			}
+
+			if !scopes.Includes(scope) {
+				scopes = append(scopes, scope)
+			}
+		}
+		if len(scopes) == 0 {
+			return nil, entities.NewValidationError("scopes must not be empty")
+		}
+
+		attributes["scopes"] = scopes
+	}
+
+	return attributes, nil
+}
+
+// newClientSecret generates a secret, returning it along with its hash
+func newClientSecret() (string, string, error) {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", "", err
+	}
+
+	secret := hex.EncodeToString(bytes)
+	secretHash, err := utils.PasswordHash(secret)
+	if err != nil {
+		return "", "", err
+	}
+
+	return secret, secretHash, nil
+}
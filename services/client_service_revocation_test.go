@@ -0,0 +1,112 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"verifymy-golang-test/entities"
+	"verifymy-golang-test/models"
+	"verifymy-golang-test/providers"
+	"verifymy-golang-test/repositories"
+)
+
+// clientServiceRevocationTestSuite issues OAuth tokens against an in-memory
+// SQLite database to check they stop working once the secret of their client
+// is rotated or the client is deleted.
+type clientServiceRevocationTestSuite struct {
+	suite.Suite
+	ctx           context.Context
+	clientService ClientService
+	oauthService  OAuthService
+	client        *models.Client
+	secret        string
+}
+
+func TestClientServiceRevocationTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(clientServiceRevocationTestSuite))
+}
+
+func (s *clientServiceRevocationTestSuite) SetupTest() {
+	s.ctx = context.Background()
+
+	dbconn, err := gorm.Open(
+		sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", uuid.NewString())),
+		&gorm.Config{Logger: logger.Default.LogMode(logger.Silent), TranslateError: true},
+	)
+	s.Require().NoError(err)
+	s.Require().NoError(providers.Migrate(dbconn))
+
+	transactor := repositories.NewTransactor(dbconn)
+	clientRepository := repositories.NewClientRepository(dbconn)
+	grantRepository := repositories.NewOAuthGrantRepository(dbconn)
+	codeRepository := repositories.NewOAuthAuthorizationCodeRepository(dbconn)
+	auditService := NewAuditService(repositories.NewAuditEventRepository(dbconn))
+	signingKeys, err := providers.NewSigningKeys()
+	s.Require().NoError(err)
+
+	s.clientService = NewClientService(
+		transactor, clientRepository, grantRepository, codeRepository, auditService,
+	)
+	s.oauthService, err = NewOAuthService(
+		transactor,
+		s.clientService,
+		clientRepository,
+		repositories.NewUserRepository(dbconn),
+		grantRepository,
+		codeRepository,
+		repositories.NewOAuthConsentRepository(dbconn),
+		auditService,
+		signingKeys,
+	)
+	s.Require().NoError(err)
+
+	scopes := []string{models.ClientScopeAgeTokens}
+	s.client, s.secret, err = s.clientService.Create(s.ctx, entities.ClientChanges{
+		Name:   stringPointer("Shop"),
+		Scopes: &scopes,
+	})
+	s.Require().NoError(err)
+}
+
+// accessToken issues an access token to the client itself and checks it works
+func (s *clientServiceRevocationTestSuite) accessToken() string {
+	token, err := s.oauthService.Token(s.ctx, entities.OAuthClientCredentials{
+		ClientID: s.client.ID.String(), ClientSecret: s.secret,
+	}, entities.OAuthTokenRequest{GrantType: OAuthGrantTypeClientCredentials})
+	s.Require().NoError(err)
+
+	_, _, err = s.oauthService.AuthenticateAccessToken(s.ctx, token.AccessToken)
+	s.Require().NoError(err)
+
+	return token.AccessToken
+}
+
+func (s *clientServiceRevocationTestSuite) TestRotateSecretRevokesTokens() {
+	accessToken := s.accessToken()
+
+	secret, err := s.clientService.RotateSecret(s.ctx, s.client.ID.String())
+	s.Require().NoError(err)
+
+	_, _, err = s.oauthService.AuthenticateAccessToken(s.ctx, accessToken)
+	s.Equal(entities.NewInvalidTokenError(), err)
+
+	s.secret = secret
+	s.accessToken()
+}
+
+func (s *clientServiceRevocationTestSuite) TestDeleteByIdRevokesTokens() {
+	accessToken := s.accessToken()
+
+	s.Require().NoError(s.clientService.DeleteById(s.ctx, s.client.ID.String()))
+
+	_, _, err := s.oauthService.AuthenticateAccessToken(s.ctx, accessToken)
+	s.Equal(entities.NewInvalidTokenError(), err)
+}
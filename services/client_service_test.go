@@ -0,0 +1,312 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	"verifymy-golang-test/entities"
+	mock_repositories "verifymy-golang-test/mocks/repositories"
+	mock_services "verifymy-golang-test/mocks/services"
+	"verifymy-golang-test/models"
+	"verifymy-golang-test/utils"
+)
+
+type clientServiceTestSuite struct {
+	suite.Suite
+	ctrl                 *gomock.Controller
+	ctx                  context.Context
+	clientRepositoryMock *mock_repositories.MockClientRepository
+	grantRepositoryMock  *mock_repositories.MockOAuthGrantRepository
+	codeRepositoryMock   *mock_repositories.MockOAuthAuthorizationCodeRepository
+	auditServiceMock     *mock_services.MockAuditService
+	now                  time.Time
+	service              ClientService
+}
+
+func TestClientServiceTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(clientServiceTestSuite))
+}
+
+func (s *clientServiceTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.ctx = context.Background()
+	s.clientRepositoryMock = mock_repositories.NewMockClientRepository(s.ctrl)
+	s.grantRepositoryMock = mock_repositories.NewMockOAuthGrantRepository(s.ctrl)
+	s.codeRepositoryMock = mock_repositories.NewMockOAuthAuthorizationCodeRepository(s.ctrl)
+	s.auditServiceMock = mock_services.NewMockAuditService(s.ctrl)
+	s.now = time.Date(2023, 6, 30, 12, 0, 0, 0, time.UTC)
+
+	transactorMock := mock_repositories.NewMockTransactor(s.ctrl)
+	transactorMock.EXPECT().WithinTransaction(
+		gomock.Any(), gomock.Any(),
+	).DoAndReturn(runWithinTransaction).AnyTimes()
+
+	service := NewClientService(
+		transactorMock,
+		s.clientRepositoryMock,
+		s.grantRepositoryMock,
+		s.codeRepositoryMock,
+		s.auditServiceMock,
+	).(*clientService)
+	service.now = func() time.Time { return s.now }
+	s.service = service
+}
+
+func (s *clientServiceTestSuite) TestCreate() {
+	scopes := []string{"age_verification", "age_tokens", "age_verification"}
+	redirectURIs := []string{"https://shop.example.com/verified"}
+	allowedOrigins := []string{"https://shop.example.com"}
+
+	s.clientRepositoryMock.EXPECT().Create(s.ctx, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, client models.Client) (*models.Client, error) {
+			s.Equal("Shop", client.Name)
+			s.Equal(models.StringList{"https://shop.example.com/verified"}, client.RedirectURIs)
+			s.Equal(models.StringList{"https://shop.example.com"}, client.AllowedOrigins)
+			s.Equal(models.StringList{"age_verification", "age_tokens"}, client.Scopes)
+			s.NotEmpty(client.SecretHash)
+
+			client.ID = uuid.New()
+			return &client, nil
+		},
+	)
+	s.auditServiceMock.EXPECT().Record(
+		s.ctx, AuditActionClientCreated, auditTargetClient, gomock.Any(),
+		map[string]interface{}{
+			"name": "Shop", "scopes": models.StringList{"age_verification", "age_tokens"},
+		},
+	).Return(nil)
+
+	client, secret, err := s.service.Create(s.ctx, entities.ClientChanges{
+		Name:           stringPointer(" Shop "),
+		RedirectURIs:   &redirectURIs,
+		AllowedOrigins: &allowedOrigins,
+		Scopes:         &scopes,
+	})
+
+	s.NoError(err)
+	s.Len(secret, 64)
+	s.NoError(utils.PasswordCompare(string(client.SecretHash), secret))
+}
+
+func (s *clientServiceTestSuite) TestCreateValidation() {
+	scopes := []string{"age_verification"}
+	unknownScopes := []string{"users:manage"}
+	noScopes := []string{}
+	redirectURIs := []string{"https://shop.example.com/verified#done"}
+	allowedOrigins := []string{"https://shop.example.com/"}
+
+	tests := []struct {
+		description   string
+		changes       entities.ClientChanges
+		expectedError error
+	}{
+		{
+			description:   "Missing name",
+			changes:       entities.ClientChanges{Scopes: &scopes},
+			expectedError: entities.NewValidationError("name is required"),
+		},
+		{
+			description:   "Missing scopes",
+			changes:       entities.ClientChanges{Name: stringPointer("Shop")},
+			expectedError: entities.NewValidationError("scopes is required"),
+		},
+		{
+			description: "Blank name",
+			changes:     entities.ClientChanges{Name: stringPointer(" "), Scopes: &scopes},
+			expectedError: entities.NewValidationError(
+				"name must be between 1 and 255 characters long",
+			),
+		},
+		{
+			description: "Unknown scope",
+			changes:     entities.ClientChanges{Name: stringPointer("Shop"), Scopes: &unknownScopes},
+			expectedError: entities.NewValidationError(
+				"scopes must only hold [age_verification age_tokens openid profile email]",
+			),
+		},
+		{
+			description:   "No scopes",
+			changes:       entities.ClientChanges{Name: stringPointer("Shop"), Scopes: &noScopes},
+			expectedError: entities.NewValidationError("scopes must not be empty"),
+		},
+		{
+			description: "Redirect URI with fragment",
+			changes: entities.ClientChanges{
+				Name: stringPointer("Shop"), Scopes: &scopes, RedirectURIs: &redirectURIs,
+			},
+			expectedError: entities.NewValidationError(
+				"redirect_uris must only hold absolute http or https URLs without fragment",
+			),
+		},
+		{
+			description: "Origin with path",
+			changes: entities.ClientChanges{
+				Name: stringPointer("Shop"), Scopes: &scopes, AllowedOrigins: &allowedOrigins,
+			},
+			expectedError: entities.NewValidationError(
+				"allowed_origins must only hold origins, like https://example.com",
+			),
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			client, secret, err := s.service.Create(s.ctx, test.changes)
+
+			s.Nil(client)
+			s.Empty(secret)
+			s.Equal(test.expectedError, err)
+		})
+	}
+}
+
+func (s *clientServiceTestSuite) TestFindById() {
+	id := uuid.NewString()
+	s.clientRepositoryMock.EXPECT().FindById(s.ctx, id).Return(nil, nil)
+
+	client, err := s.service.FindById(s.ctx, id)
+
+	s.Nil(client)
+	s.Equal(entities.NewItemNotFoundError("Client", id), err)
+}
+
+func (s *clientServiceTestSuite) TestUpdate() {
+	client := &models.Client{
+		ID:           uuid.New(),
+		Name:         "Shop",
+		RedirectURIs: models.StringList{},
+		Scopes:       models.StringList{"age_verification"},
+		SecretHash:   "hashed-secret",
+	}
+	redirectURIs := []string{"https://shop.example.com/verified"}
+	expected := *client
+	expected.RedirectURIs = models.StringList{"https://shop.example.com/verified"}
+
+	s.clientRepositoryMock.EXPECT().FindById(s.ctx, client.ID.String()).Return(client, nil)
+	s.clientRepositoryMock.EXPECT().Update(s.ctx, expected).Return(nil)
+	s.auditServiceMock.EXPECT().RecordChanges(
+		s.ctx, AuditActionClientUpdated, auditTargetClient, client.ID.String(), client,
+		map[string]interface{}{"redirect_uris": expected.RedirectURIs},
+	).Return(nil)
+
+	updated, err := s.service.Update(s.ctx, client.ID.String(), entities.ClientChanges{
+		RedirectURIs: &redirectURIs,
+	})
+
+	s.NoError(err)
+	s.Equal(&expected, updated)
+}
+
+func (s *clientServiceTestSuite) TestRotateSecret() {
+	client := &models.Client{ID: uuid.New(), SecretHash: "hashed-secret"}
+
+	s.clientRepositoryMock.EXPECT().FindById(s.ctx, client.ID.String()).Return(client, nil)
+	s.clientRepositoryMock.EXPECT().Update(s.ctx, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, updated models.Client) error {
+			s.NotEqual(models.SecretValue("hashed-secret"), updated.SecretHash)
+			client.SecretHash = updated.SecretHash
+			return nil
+		},
+	)
+	s.grantRepositoryMock.EXPECT().RevokeByClientId(s.ctx, client.ID.String(), s.now).Return(nil)
+	s.codeRepositoryMock.EXPECT().DeleteByClientId(s.ctx, client.ID.String()).Return(nil)
+	s.auditServiceMock.EXPECT().Record(
+		s.ctx, AuditActionClientSecretRotated, auditTargetClient, client.ID.String(), nil,
+	).Return(nil)
+
+	secret, err := s.service.RotateSecret(s.ctx, client.ID.String())
+
+	s.NoError(err)
+	s.NoError(utils.PasswordCompare(string(client.SecretHash), secret))
+}
+
+func (s *clientServiceTestSuite) TestDeleteById() {
+	id := uuid.NewString()
+
+	tests := []struct {
+		description   string
+		deleted       bool
+		deleteError   error
+		expectedError error
+	}{
+		{description: "Deleted", deleted: true},
+		{
+			description:   "Not found",
+			expectedError: entities.NewItemNotFoundError("Client", id),
+		},
+		{
+			description:   "Unexpected error",
+			deleteError:   errors.New("database unavailable"),
+			expectedError: errors.New("database unavailable"),
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			s.clientRepositoryMock.EXPECT().DeleteById(s.ctx, id).Return(test.deleted, test.deleteError)
+			if test.deleted {
+				s.grantRepositoryMock.EXPECT().RevokeByClientId(s.ctx, id, s.now).Return(nil)
+				s.codeRepositoryMock.EXPECT().DeleteByClientId(s.ctx, id).Return(nil)
+				s.auditServiceMock.EXPECT().Record(
+					s.ctx, AuditActionClientDeleted, auditTargetClient, id, nil,
+				).Return(nil)
+			}
+
+			err := s.service.DeleteById(s.ctx, id)
+
+			s.Equal(test.expectedError, err)
+		})
+	}
+}
+
+func (s *clientServiceTestSuite) TestAuthenticate() {
+	secretHash, err := utils.PasswordHash("client-secret")
+	s.Require().NoError(err)
+	client := &models.Client{ID: uuid.New(), SecretHash: models.SecretValue(secretHash)}
+
+	tests := []struct {
+		description    string
+		client         *models.Client
+		secret         string
+		expectedClient *models.Client
+		expectedError  error
+	}{
+		{
+			description:    "Valid credentials",
+			client:         client,
+			secret:         "client-secret",
+			expectedClient: client,
+		},
+		{
+			description:   "Wrong secret",
+			client:        client,
+			secret:        "other-secret",
+			expectedError: entities.NewInvalidClientError(),
+		},
+		{
+			description:   "Unknown client",
+			secret:        "client-secret",
+			expectedError: entities.NewInvalidClientError(),
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			s.clientRepositoryMock.EXPECT().FindById(
+				s.ctx, client.ID.String(),
+			).Return(test.client, nil)
+
+			authenticated, err := s.service.Authenticate(s.ctx, client.ID.String(), test.secret)
+
+			s.Equal(test.expectedClient, authenticated)
+			s.Equal(test.expectedError, err)
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package services
+
+import (
+	"context"
+
+	"verifymy-golang-test/models"
+	"verifymy-golang-test/repositories"
+)
+
+type profileExportCollector struct{}
+
+func NewProfileExportCollector() DataExportCollector {
+	return &profileExportCollector{}
+}
+
+func (c *profileExportCollector) Name() string {
+	return "profile"
+}
+
+func (c *profileExportCollector) Collect(
+	ctx context.Context, user *models.User,
+) (interface{}, error) {
+	return user, nil
+}
+
+type auditEventsExportCollector struct {
+	auditEventRepository repositories.AuditEventRepository
+}
+
+func NewAuditEventsExportCollector(
+	auditEventRepository repositories.AuditEventRepository,
+) DataExportCollector {
+	return &auditEventsExportCollector{
+		auditEventRepository: auditEventRepository,
+	}
+}
+
+func (c *auditEventsExportCollector) Name() string {
+	return "audit_events"
+}
+
+func (c *auditEventsExportCollector) Collect(
+	ctx context.Context, user *models.User,
+) (interface{}, error) {
+	events, err := c.auditEventRepository.FindByUserId(ctx, user.ID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	if events == nil {
+		events = []models.AuditEvent{}
+	}
+
+	return events, nil
+}
+
+// sessionsExportCollector exports when the sessions of the user were last
+// revoked, which is all that is kept about them since access tokens are not
+// stored.
+type sessionsExportCollector struct{}
+
+func NewSessionsExportCollector() DataExportCollector {
+	return &sessionsExportCollector{}
+}
+
+func (c *sessionsExportCollector) Name() string {
+	return "sessions"
+}
+
+func (c *sessionsExportCollector) Collect(
+	ctx context.Context, user *models.User,
+) (interface{}, error) {
+	var revokedAt interface{}
+	if user.SessionsRevokedAt.Valid {
+		revokedAt = user.SessionsRevokedAt.Time
+	}
+
+	return map[string]interface{}{"revoked_at": revokedAt}, nil
+}
+
+// oauthExportCollector exports the consents the user gave clients and the
+// grants those clients hold on their behalf, leaving refresh token hashes out
+type oauthExportCollector struct {
+	oauthConsentRepository repositories.OAuthConsentRepository
+	oauthGrantRepository   repositories.OAuthGrantRepository
+}
+
+func NewOAuthExportCollector(
+	oauthConsentRepository repositories.OAuthConsentRepository,
+	oauthGrantRepository repositories.OAuthGrantRepository,
+) DataExportCollector {
+	return &oauthExportCollector{
+		oauthConsentRepository: oauthConsentRepository,
+		oauthGrantRepository:   oauthGrantRepository,
+	}
+}
+
+func (c *oauthExportCollector) Name() string {
+	return "oauth"
+}
+
+func (c *oauthExportCollector) Collect(
+	ctx context.Context, user *models.User,
+) (interface{}, error) {
+	consents, err := c.oauthConsentRepository.FindByUserId(ctx, user.ID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	grants, err := c.oauthGrantRepository.FindByUserId(ctx, user.ID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	exportedConsents := []map[string]interface{}{}
+	for _, consent := range consents {
+		exportedConsents = append(exportedConsents, map[string]interface{}{
+			"client_id":  consent.ClientID,
+			"scopes":     consent.Scopes,
+			"created_at": consent.CreatedAt,
+			"updated_at": consent.UpdatedAt,
+		})
+	}
+
+	exportedGrants := []map[string]interface{}{}
+	for _, grant := range grants {
+		var revokedAt interface{}
+		if grant.RevokedAt.Valid {
+			revokedAt = grant.RevokedAt.Time
+		}
+
+		exportedGrants = append(exportedGrants, map[string]interface{}{
+			"id":         grant.ID,
+			"client_id":  grant.ClientID,
+			"scopes":     grant.Scopes,
+			"created_at": grant.CreatedAt,
+			"revoked_at": revokedAt,
+		})
+	}
+
+	return map[string]interface{}{
+		"consents": exportedConsents,
+		"grants":   exportedGrants,
+	}, nil
+}
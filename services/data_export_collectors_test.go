@@ -0,0 +1,215 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	mock_repositories "verifymy-golang-test/mocks/repositories"
+	"verifymy-golang-test/models"
+)
+
+type dataExportCollectorsTestSuite struct {
+	suite.Suite
+	ctrl                     *gomock.Controller
+	auditEventRepositoryMock *mock_repositories.MockAuditEventRepository
+	consentRepositoryMock    *mock_repositories.MockOAuthConsentRepository
+	grantRepositoryMock      *mock_repositories.MockOAuthGrantRepository
+	user                     *models.User
+}
+
+func TestDataExportCollectorsTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(dataExportCollectorsTestSuite))
+}
+
+func (s *dataExportCollectorsTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.auditEventRepositoryMock = mock_repositories.NewMockAuditEventRepository(s.ctrl)
+	s.consentRepositoryMock = mock_repositories.NewMockOAuthConsentRepository(s.ctrl)
+	s.grantRepositoryMock = mock_repositories.NewMockOAuthGrantRepository(s.ctrl)
+	s.user = &models.User{ID: uuid.New(), Name: "Peter Parker"}
+}
+
+func (s *dataExportCollectorsTestSuite) TestProfileExportCollector() {
+	collector := NewProfileExportCollector()
+
+	data, err := collector.Collect(context.Background(), s.user)
+
+	s.Equal("profile", collector.Name())
+	s.NoError(err)
+	s.Equal(s.user, data)
+}
+
+func (s *dataExportCollectorsTestSuite) TestAuditEventsExportCollector() {
+	ctx := context.Background()
+	collector := NewAuditEventsExportCollector(s.auditEventRepositoryMock)
+	s.Equal("audit_events", collector.Name())
+
+	tests := []struct {
+		description   string
+		events        []models.AuditEvent
+		findError     error
+		expectedData  interface{}
+		expectedError error
+	}{
+		{
+			description:  "Events",
+			events:       []models.AuditEvent{{Action: "user.updated"}},
+			expectedData: []models.AuditEvent{{Action: "user.updated"}},
+		},
+		{
+			description:  "No events",
+			expectedData: []models.AuditEvent{},
+		},
+		{
+			description:   "Error finding events",
+			findError:     errors.New("database error"),
+			expectedError: errors.New("database error"),
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			s.auditEventRepositoryMock.EXPECT().FindByUserId(
+				ctx, s.user.ID.String(),
+			).Return(test.events, test.findError)
+
+			data, err := collector.Collect(ctx, s.user)
+
+			s.Equal(test.expectedError, err)
+			s.Equal(test.expectedData, data)
+		})
+	}
+}
+
+func (s *dataExportCollectorsTestSuite) TestSessionsExportCollector() {
+	collector := NewSessionsExportCollector()
+	s.Equal("sessions", collector.Name())
+
+	data, err := collector.Collect(context.Background(), s.user)
+	s.NoError(err)
+	s.Equal(map[string]interface{}{"revoked_at": nil}, data)
+
+	revokedAt := time.Date(2023, 6, 30, 12, 0, 0, 0, time.UTC)
+	s.user.SessionsRevokedAt = sql.NullTime{Time: revokedAt, Valid: true}
+
+	data, err = collector.Collect(context.Background(), s.user)
+	s.NoError(err)
+	s.Equal(map[string]interface{}{"revoked_at": revokedAt}, data)
+}
+
+func (s *dataExportCollectorsTestSuite) TestOAuthExportCollector() {
+	ctx := context.Background()
+	collector := NewOAuthExportCollector(s.consentRepositoryMock, s.grantRepositoryMock)
+	s.Equal("oauth", collector.Name())
+
+	createdAt := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+	revokedAt := createdAt.Add(time.Hour)
+	consent := models.OAuthConsent{
+		ID:        uuid.New(),
+		UserID:    s.user.ID.String(),
+		ClientID:  uuid.NewString(),
+		Scopes:    models.StringList{"profile"},
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
+	}
+	grant := models.OAuthGrant{
+		ID:               uuid.New(),
+		ClientID:         consent.ClientID,
+		UserID:           s.user.ID.String(),
+		Scopes:           models.StringList{"profile"},
+		RefreshTokenHash: "refresh-token-hash",
+		CreatedAt:        createdAt,
+	}
+	revoked := grant
+	revoked.ID = uuid.New()
+	revoked.RevokedAt = sql.NullTime{Time: revokedAt, Valid: true}
+
+	tests := []struct {
+		description      string
+		consents         []models.OAuthConsent
+		grants           []models.OAuthGrant
+		consentsError    error
+		grantsError      error
+		skipGrants       bool
+		expectedConsents []map[string]interface{}
+		expectedGrants   []map[string]interface{}
+		expectedError    error
+	}{
+		{
+			description: "Consents and grants",
+			consents:    []models.OAuthConsent{consent},
+			grants:      []models.OAuthGrant{grant, revoked},
+			expectedConsents: []map[string]interface{}{{
+				"client_id":  consent.ClientID,
+				"scopes":     models.StringList{"profile"},
+				"created_at": createdAt,
+				"updated_at": createdAt,
+			}},
+			expectedGrants: []map[string]interface{}{
+				{
+					"id":         grant.ID,
+					"client_id":  consent.ClientID,
+					"scopes":     models.StringList{"profile"},
+					"created_at": createdAt,
+					"revoked_at": nil,
+				},
+				{
+					"id":         revoked.ID,
+					"client_id":  consent.ClientID,
+					"scopes":     models.StringList{"profile"},
+					"created_at": createdAt,
+					"revoked_at": revokedAt,
+				},
+			},
+		},
+		{
+			description:      "Nothing given",
+			expectedConsents: []map[string]interface{}{},
+			expectedGrants:   []map[string]interface{}{},
+		},
+		{
+			description:   "Error finding consents",
+			consentsError: errors.New("database error"),
+			skipGrants:    true,
+			expectedError: errors.New("database error"),
+		},
+		{
+			description:   "Error finding grants",
+			grantsError:   errors.New("database error"),
+			expectedError: errors.New("database error"),
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			s.consentRepositoryMock.EXPECT().FindByUserId(
+				ctx, s.user.ID.String(),
+			).Return(test.consents, test.consentsError)
+			if !test.skipGrants {
+				s.grantRepositoryMock.EXPECT().FindByUserId(
+					ctx, s.user.ID.String(),
+				).Return(test.grants, test.grantsError)
+			}
+
+			data, err := collector.Collect(ctx, s.user)
+
+			s.Equal(test.expectedError, err)
+			if test.expectedError != nil {
+				s.Nil(data)
+				return
+			}
+			s.Equal(map[string]interface{}{
+				"consents": test.expectedConsents,
+				"grants":   test.expectedGrants,
+			}, data)
+		})
+	}
+}
@@ -0,0 +1,319 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	"verifymy-golang-test/common"
+	"verifymy-golang-test/entities"
+	"verifymy-golang-test/models"
+	"verifymy-golang-test/providers"
+	"verifymy-golang-test/repositories"
+)
+
+const (
+	dataExportBatchSize         = 10
+	dataExportLinkLifetime      = time.Hour
+	defaultDataExportLifetime   = 7 * 24 * time.Hour
+	expiredDataExportsBatchSize = 100
+)
+
+// DataExportCollector gathers what a module holds about a user for their
+// data export. What it collects is written to the export as <name>.json.
+type DataExportCollector interface {
+	Name() string
+	Collect(ctx context.Context, user *models.User) (interface{}, error)
+}
+
+// DataExportService packages, in the background, everything held about a
+// user who asked for a copy of their data.
+type DataExportService interface {
+	Request(ctx context.Context) (*models.DataExport, error)
+	FindById(ctx context.Context, exportId string) (*models.DataExport, error)
+	ProcessPending(ctx context.Context) (int64, error)
+	DeleteExpired(ctx context.Context) (int64, error)
+	DownloadURL(export *models.DataExport) (string, int64)
+	Open(
+		ctx context.Context, exportId string, expiresAt int64, signature string,
+	) (io.ReadCloser, error)
+}
+
+// NewDataExportService keeps exports for DATA_EXPORT_LIFETIME, a duration
+// like "168h", once they are packaged.
+func NewDataExportService(
+	dataExportRepository repositories.DataExportRepository,
+	userRepository repositories.UserRepository,
+	storage providers.Storage,
+	collectors []DataExportCollector,
+) (DataExportService, error) {
+	lifetime := defaultDataExportLifetime
+	if value := os.Getenv("DATA_EXPORT_LIFETIME"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed <= 0 {
+			return nil, fmt.Errorf("invalid DATA_EXPORT_LIFETIME %q", value)
+		}
+
+		lifetime = parsed
+	}
+
+	return &dataExportService{
+		dataExportRepository: dataExportRepository,
+		userRepository:       userRepository,
+		storage:              storage,
+		collectors:           collectors,
+		lifetime:             lifetime,
+		now:                  time.Now,
+	}, nil
+}
+
+type dataExportService struct {
+	dataExportRepository repositories.DataExportRepository
+	userRepository       repositories.UserRepository
+	storage              providers.Storage
+	collectors           []DataExportCollector
+	lifetime             time.Duration
+	now                  func() time.Time
+}
+
+// Request queues an export of the data of the signed in user
+func (s *dataExportService) Request(ctx context.Context) (*models.DataExport, error) {
+	user := ctx.Value(common.AuthUser).(*models.User)
+
+	return s.dataExportRepository.Create(ctx, models.DataExport{
+		UserID: user.ID.String(),
+	})
+}
+
+// FindById only finds exports of the signed in user, which haven't expired
+func (s *dataExportService) FindById(
+	ctx context.Context, exportId string,
+) (*models.DataExport, error) {
+	user := ctx.Value(common.AuthUser).(*models.User)
+
+	export, err := s.dataExportRepository.FindById(ctx, exportId)
+	if err != nil {
+		return nil, err
+	} else if export == nil || export.UserID != user.ID.String() || s.expired(export) {
+		return nil, entities.NewItemNotFoundError("Data export", exportId)
+	}
+
+	return export, nil
+}
+
+// ProcessPending packages the oldest pending exports and returns how many
+// are ready. Exports that can't be packaged are marked as failed, and the
+// first error is returned once the others are processed.
+func (s *dataExportService) ProcessPending(ctx context.Context) (int64, error) {
+	exports, err := s.dataExportRepository.FindPending(ctx, dataExportBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	var processed int64
+	var firstErr error
+	for _, export := range exports {
+		err := s.process(ctx, &export)
+		if err != nil {
+			export.Status = models.DataExportStatusFailed
+			if firstErr == nil {
+				firstErr = fmt.Errorf("data export %s: %w", export.ID, err)
+			}
+		} else {
+			export.Status = models.DataExportStatusReady
+			processed++
+		}
+
+		completedAt := s.now().UTC()
+		export.CompletedAt = sql.NullTime{Time: completedAt, Valid: true}
+		export.ExpiresAt = sql.NullTime{Time: completedAt.Add(s.lifetime), Valid: true}
+		if err := s.dataExportRepository.Update(ctx, export); err != nil {
+			return processed, err
+		}
+	}
+
+	return processed, firstErr
+}
+
+// DeleteExpired deletes the expired exports, along with their files, and
+// returns how many were deleted. An export failing to be deleted is left for
+// the next run, and the first error is returned once the others are deleted.
+func (s *dataExportService) DeleteExpired(ctx context.Context) (int64, error) {
+	exports, err := s.dataExportRepository.FindExpired(
+		ctx, s.now().UTC(), expiredDataExportsBatchSize,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	var deleted int64
+	var firstErr error
+	for _, export := range exports {
+		if err := s.delete(ctx, export); err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("data export %s: %w", export.ID, err)
+			}
+			continue
+		}
+
+		deleted++
+	}
+
+	return deleted, firstErr
+}
+
+// delete removes the file before the row, so a failure leaves the row around
+// to try again
+func (s *dataExportService) delete(ctx context.Context, export models.DataExport) error {
+	if export.File != "" {
+		if err := s.storage.Delete(ctx, export.File); err != nil {
+			return err
+		}
+	}
+
+	return s.dataExportRepository.DeleteById(ctx, export.ID.String())
+}
+
+func (s *dataExportService) expired(export *models.DataExport) bool {
+	return export.ExpiresAt.Valid && !s.now().UTC().Before(export.ExpiresAt.Time)
+}
+
+// process writes a ZIP with a JSON file per collector to the storage
+func (s *dataExportService) process(ctx context.Context, export *models.DataExport) error {
+	user, err := s.userRepository.FindById(ctx, export.UserID)
+	if err != nil {
+		return err
+	} else if user == nil {
+		return fmt.Errorf("user %s not found", export.UserID)
+	}
+
+	var archive bytes.Buffer
+	writer := zip.NewWriter(&archive)
+	for _, collector := range s.collectors {
+		data, err := collector.Collect(ctx, user)
+		if err != nil {
+			return fmt.Errorf("collector %s: %w", collector.Name(), err)
+		}
+
+		content, err := json.MarshalIndent(data, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		file, err := writer.Create(collector.Name() + ".json")
+		if err != nil {
+			return err
+		}
+		if _, err := file.Write(content); err != nil {
+			return err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	export.File = fmt.Sprintf("exports/%s.zip", export.ID)
+
+	return s.storage.Write(ctx, export.File, archive.Bytes())
+}
+
+// DownloadURL returns a link to download the export without signing in,
+// along with when the link expires, which is never after the export itself.
+// The link is signed with SECRET_KEY and points to DATA_EXPORT_DOWNLOAD_URL.
+func (s *dataExportService) DownloadURL(export *models.DataExport) (string, int64) {
+	baseURL := os.Getenv("DATA_EXPORT_DOWNLOAD_URL")
+	if baseURL == "" {
+		baseURL = "http://localhost:8080/exports/download"
+	}
+
+	exportId := export.ID.String()
+	expiresAt := s.now().UTC().Add(dataExportLinkLifetime).Unix()
+	if export.ExpiresAt.Valid && export.ExpiresAt.Time.Unix() < expiresAt {
+		expiresAt = export.ExpiresAt.Time.Unix()
+	}
+	query := url.Values{
+		"id":        {exportId},
+		"expires":   {strconv.FormatInt(expiresAt, 10)},
+		"signature": {dataExportSignature(exportId, expiresAt)},
+	}
+
+	return baseURL + "?" + query.Encode(), expiresAt
+}
+
+// Open checks a download link and opens the export it points to
+func (s *dataExportService) Open(
+	ctx context.Context, exportId string, expiresAt int64, signature string,
+) (io.ReadCloser, error) {
+	expectedSignature := dataExportSignature(exportId, expiresAt)
+	if s.now().UTC().Unix() >= expiresAt ||
+		!hmac.Equal([]byte(signature), []byte(expectedSignature)) {
+		return nil, entities.NewInvalidTokenError()
+	}
+
+	export, err := s.dataExportRepository.FindById(ctx, exportId)
+	if err != nil {
+		return nil, err
+	} else if export == nil || export.Status != models.DataExportStatusReady || s.expired(export) {
+		return nil, entities.NewItemNotFoundError("Data export", exportId)
+	}
+
+	return s.storage.Open(ctx, export.File)
+}
+
+func dataExportSignature(exportId string, expiresAt int64) string {
+	mac := hmac.New(sha256.New, []byte(os.Getenv("SECRET_KEY")))
+	fmt.Fprintf(mac, "data_export:%s:%d", exportId, expiresAt)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// dataExportErasureHook removes the exports of an erased user, since they are
+// full of their personal data.
+type dataExportErasureHook struct {
+	dataExportRepository repositories.DataExportRepository
+	storage              providers.Storage
+}
+
+func NewDataExportErasureHook(
+	dataExportRepository repositories.DataExportRepository,
+	storage providers.Storage,
+) ErasureHook {
+	return &dataExportErasureHook{
+		dataExportRepository: dataExportRepository,
+		storage:              storage,
+	}
+}
+
+func (h *dataExportErasureHook) Name() string {
+	return "data_exports"
+}
+
+func (h *dataExportErasureHook) Erase(ctx context.Context, user *models.User) error {
+	exports, err := h.dataExportRepository.FindByUserId(ctx, user.ID.String())
+	if err != nil {
+		return err
+	}
+
+	for _, export := range exports {
+		if export.File == "" {
+			continue
+		}
+
+		if err := h.storage.Delete(ctx, export.File); err != nil {
+			return err
+		}
+	}
+
+	return h.dataExportRepository.DeleteByUserId(ctx, user.ID.String())
+}
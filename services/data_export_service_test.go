@@ -0,0 +1,379 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	"verifymy-golang-test/common"
+	"verifymy-golang-test/entities"
+	mock_providers "verifymy-golang-test/mocks/providers"
+	mock_repositories "verifymy-golang-test/mocks/repositories"
+	"verifymy-golang-test/models"
+)
+
+type dataExportCollectorStub struct {
+	name string
+	data interface{}
+	err  error
+}
+
+func (c *dataExportCollectorStub) Name() string {
+	return c.name
+}
+
+func (c *dataExportCollectorStub) Collect(
+	ctx context.Context, user *models.User,
+) (interface{}, error) {
+	return c.data, c.err
+}
+
+type dataExportServiceTestSuite struct {
+	suite.Suite
+	ctrl                     *gomock.Controller
+	dataExportRepositoryMock *mock_repositories.MockDataExportRepository
+	userRepositoryMock       *mock_repositories.MockUserRepository
+	storageMock              *mock_providers.MockStorage
+	user                     *models.User
+	ctx                      context.Context
+	now                      time.Time
+}
+
+func TestDataExportServiceTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(dataExportServiceTestSuite))
+}
+
+func (s *dataExportServiceTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.dataExportRepositoryMock = mock_repositories.NewMockDataExportRepository(s.ctrl)
+	s.userRepositoryMock = mock_repositories.NewMockUserRepository(s.ctrl)
+	s.storageMock = mock_providers.NewMockStorage(s.ctrl)
+	s.user = &models.User{ID: uuid.New(), Name: "Peter Parker"}
+	s.ctx = context.WithValue(context.Background(), common.AuthUser, s.user)
+	s.now = time.Date(2023, 6, 30, 12, 0, 0, 0, time.UTC)
+}
+
+func (s *dataExportServiceTestSuite) service(collectors ...DataExportCollector) *dataExportService {
+	service, err := NewDataExportService(
+		s.dataExportRepositoryMock, s.userRepositoryMock, s.storageMock, collectors,
+	)
+	s.Require().NoError(err)
+	service.(*dataExportService).now = func() time.Time { return s.now }
+
+	return service.(*dataExportService)
+}
+
+func (s *dataExportServiceTestSuite) TestRequest() {
+	export := &models.DataExport{ID: uuid.New(), UserID: s.user.ID.String()}
+	s.dataExportRepositoryMock.EXPECT().Create(
+		s.ctx, models.DataExport{UserID: s.user.ID.String()},
+	).Return(export, nil)
+
+	result, err := s.service().Request(s.ctx)
+
+	s.NoError(err)
+	s.Equal(export, result)
+}
+
+func (s *dataExportServiceTestSuite) TestFindById() {
+	exportId := uuid.New()
+
+	tests := []struct {
+		description    string
+		export         *models.DataExport
+		findError      error
+		expectedExport bool
+		expectedError  error
+	}{
+		{
+			description:    "Own export",
+			export:         &models.DataExport{ID: exportId, UserID: s.user.ID.String()},
+			expectedExport: true,
+		},
+		{
+			description:   "Export of another user",
+			export:        &models.DataExport{ID: exportId, UserID: uuid.NewString()},
+			expectedError: entities.NewItemNotFoundError("Data export", exportId.String()),
+		},
+		{
+			description: "Expired export",
+			export: &models.DataExport{
+				ID:        exportId,
+				UserID:    s.user.ID.String(),
+				ExpiresAt: sql.NullTime{Time: s.now, Valid: true},
+			},
+			expectedError: entities.NewItemNotFoundError("Data export", exportId.String()),
+		},
+		{
+			description:   "Not found",
+			expectedError: entities.NewItemNotFoundError("Data export", exportId.String()),
+		},
+		{
+			description:   "Error finding export",
+			findError:     errors.New("database error"),
+			expectedError: errors.New("database error"),
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			s.dataExportRepositoryMock.EXPECT().FindById(
+				s.ctx, exportId.String(),
+			).Return(test.export, test.findError)
+
+			export, err := s.service().FindById(s.ctx, exportId.String())
+
+			s.Equal(test.expectedError, err)
+			if test.expectedExport {
+				s.Equal(test.export, export)
+			} else {
+				s.Nil(export)
+			}
+		})
+	}
+}
+
+func (s *dataExportServiceTestSuite) TestProcessPending() {
+	ctx := context.Background()
+	export := models.DataExport{
+		ID:     uuid.New(),
+		UserID: s.user.ID.String(),
+		Status: models.DataExportStatusPending,
+	}
+	file := "exports/" + export.ID.String() + ".zip"
+
+	s.Run("Success", func() {
+		var content []byte
+		s.dataExportRepositoryMock.EXPECT().FindPending(
+			ctx, dataExportBatchSize,
+		).Return([]models.DataExport{export}, nil)
+		s.userRepositoryMock.EXPECT().FindById(ctx, s.user.ID.String()).Return(s.user, nil)
+		s.storageMock.EXPECT().Write(ctx, file, gomock.Any()).DoAndReturn(
+			func(ctx context.Context, name string, data []byte) error {
+				content = data
+				return nil
+			},
+		)
+		s.dataExportRepositoryMock.EXPECT().Update(ctx, models.DataExport{
+			ID:          export.ID,
+			UserID:      export.UserID,
+			Status:      models.DataExportStatusReady,
+			File:        file,
+			CompletedAt: sql.NullTime{Time: s.now, Valid: true},
+			ExpiresAt:   sql.NullTime{Time: s.now.Add(defaultDataExportLifetime), Valid: true},
+		}).Return(nil)
+
+		processed, err := s.service(
+			&dataExportCollectorStub{name: "profile", data: map[string]string{"name": "Peter Parker"}},
+			&dataExportCollectorStub{name: "orders", data: []string{}},
+		).ProcessPending(ctx)
+
+		s.NoError(err)
+		s.Equal(int64(1), processed)
+
+		archive, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+		s.Require().NoError(err)
+		s.Require().Len(archive.File, 2)
+		s.Equal("profile.json", archive.File[0].Name)
+		s.Equal("orders.json", archive.File[1].Name)
+
+		reader, err := archive.File[0].Open()
+		s.Require().NoError(err)
+		profile, _ := io.ReadAll(reader)
+		s.JSONEq(`{"name":"Peter Parker"}`, string(profile))
+	})
+
+	s.Run("Failing collector", func() {
+		s.dataExportRepositoryMock.EXPECT().FindPending(
+			ctx, dataExportBatchSize,
+		).Return([]models.DataExport{export}, nil)
+		s.userRepositoryMock.EXPECT().FindById(ctx, s.user.ID.String()).Return(s.user, nil)
+		s.dataExportRepositoryMock.EXPECT().Update(ctx, models.DataExport{
+			ID:          export.ID,
+			UserID:      export.UserID,
+			Status:      models.DataExportStatusFailed,
+			CompletedAt: sql.NullTime{Time: s.now, Valid: true},
+			ExpiresAt:   sql.NullTime{Time: s.now.Add(defaultDataExportLifetime), Valid: true},
+		}).Return(nil)
+
+		processed, err := s.service(
+			&dataExportCollectorStub{name: "orders", err: errors.New("orders unavailable")},
+		).ProcessPending(ctx)
+
+		s.EqualError(err, "data export "+export.ID.String()+": collector orders: orders unavailable")
+		s.Equal(int64(0), processed)
+	})
+
+	s.Run("Error finding pending exports", func() {
+		s.dataExportRepositoryMock.EXPECT().FindPending(
+			ctx, dataExportBatchSize,
+		).Return(nil, errors.New("database error"))
+
+		processed, err := s.service().ProcessPending(ctx)
+
+		s.EqualError(err, "database error")
+		s.Equal(int64(0), processed)
+	})
+}
+
+func (s *dataExportServiceTestSuite) TestDeleteExpired() {
+	ctx := context.Background()
+	ready := models.DataExport{ID: uuid.New(), File: "exports/ready.zip"}
+	failed := models.DataExport{ID: uuid.New()}
+	stuck := models.DataExport{ID: uuid.New(), File: "exports/stuck.zip"}
+
+	s.Run("Success", func() {
+		s.dataExportRepositoryMock.EXPECT().FindExpired(
+			ctx, s.now, expiredDataExportsBatchSize,
+		).Return([]models.DataExport{ready, failed}, nil)
+		gomock.InOrder(
+			s.storageMock.EXPECT().Delete(ctx, ready.File).Return(nil),
+			s.dataExportRepositoryMock.EXPECT().DeleteById(ctx, ready.ID.String()).Return(nil),
+		)
+		s.dataExportRepositoryMock.EXPECT().DeleteById(ctx, failed.ID.String()).Return(nil)
+
+		deleted, err := s.service().DeleteExpired(ctx)
+
+		s.NoError(err)
+		s.Equal(int64(2), deleted)
+	})
+
+	s.Run("Failing file is kept for the next run", func() {
+		s.dataExportRepositoryMock.EXPECT().FindExpired(
+			ctx, s.now, expiredDataExportsBatchSize,
+		).Return([]models.DataExport{stuck, ready}, nil)
+		s.storageMock.EXPECT().Delete(ctx, stuck.File).Return(errors.New("disk error"))
+		s.storageMock.EXPECT().Delete(ctx, ready.File).Return(nil)
+		s.dataExportRepositoryMock.EXPECT().DeleteById(ctx, ready.ID.String()).Return(nil)
+
+		deleted, err := s.service().DeleteExpired(ctx)
+
+		s.EqualError(err, "data export "+stuck.ID.String()+": disk error")
+		s.Equal(int64(1), deleted)
+	})
+
+	s.Run("Error finding expired exports", func() {
+		s.dataExportRepositoryMock.EXPECT().FindExpired(
+			ctx, s.now, expiredDataExportsBatchSize,
+		).Return(nil, errors.New("database error"))
+
+		deleted, err := s.service().DeleteExpired(ctx)
+
+		s.EqualError(err, "database error")
+		s.Equal(int64(0), deleted)
+	})
+}
+
+func (s *dataExportServiceTestSuite) TestDownloadURLAndOpen() {
+	export := &models.DataExport{
+		ID:        uuid.New(),
+		Status:    models.DataExportStatusReady,
+		File:      "exports/export.zip",
+		ExpiresAt: sql.NullTime{Time: s.now.Add(defaultDataExportLifetime), Valid: true},
+	}
+	service := s.service()
+
+	downloadURL, expiresAt := service.DownloadURL(export)
+	s.Equal(s.now.Add(dataExportLinkLifetime).Unix(), expiresAt)
+
+	parsed, err := url.Parse(downloadURL)
+	s.Require().NoError(err)
+	s.Equal("/exports/download", parsed.Path)
+	query := parsed.Query()
+	s.Equal(export.ID.String(), query.Get("id"))
+	s.Equal(strconv.FormatInt(expiresAt, 10), query.Get("expires"))
+	signature := query.Get("signature")
+
+	s.Run("Valid link", func() {
+		file := io.NopCloser(bytes.NewReader([]byte("zip")))
+		s.dataExportRepositoryMock.EXPECT().FindById(
+			s.ctx, export.ID.String(),
+		).Return(export, nil)
+		s.storageMock.EXPECT().Open(s.ctx, export.File).Return(file, nil)
+
+		opened, err := service.Open(s.ctx, export.ID.String(), expiresAt, signature)
+
+		s.NoError(err)
+		s.Equal(file, opened)
+	})
+
+	s.Run("Tampered link", func() {
+		_, err := service.Open(s.ctx, export.ID.String(), expiresAt+3600, signature)
+
+		s.Equal(entities.NewInvalidTokenError(), err)
+	})
+
+	s.Run("Expired link", func() {
+		expired := s.service()
+		expired.now = func() time.Time { return s.now.Add(2 * dataExportLinkLifetime) }
+
+		_, err := expired.Open(s.ctx, export.ID.String(), expiresAt, signature)
+
+		s.Equal(entities.NewInvalidTokenError(), err)
+	})
+
+	s.Run("Export not ready", func() {
+		s.dataExportRepositoryMock.EXPECT().FindById(
+			s.ctx, export.ID.String(),
+		).Return(&models.DataExport{ID: export.ID, Status: models.DataExportStatusPending}, nil)
+
+		_, err := service.Open(s.ctx, export.ID.String(), expiresAt, signature)
+
+		s.Equal(entities.NewItemNotFoundError("Data export", export.ID.String()), err)
+	})
+
+	s.Run("Link of an expiring export", func() {
+		expiring := &models.DataExport{
+			ID:        export.ID,
+			Status:    models.DataExportStatusReady,
+			ExpiresAt: sql.NullTime{Time: s.now.Add(time.Minute), Valid: true},
+		}
+
+		_, expiresAt := service.DownloadURL(expiring)
+
+		s.Equal(s.now.Add(time.Minute).Unix(), expiresAt)
+	})
+
+	s.Run("Expired export", func() {
+		s.dataExportRepositoryMock.EXPECT().FindById(
+			s.ctx, export.ID.String(),
+		).Return(&models.DataExport{
+			ID:        export.ID,
+			Status:    models.DataExportStatusReady,
+			File:      export.File,
+			ExpiresAt: sql.NullTime{Time: s.now, Valid: true},
+		}, nil)
+
+		_, err := service.Open(s.ctx, export.ID.String(), expiresAt, signature)
+
+		s.Equal(entities.NewItemNotFoundError("Data export", export.ID.String()), err)
+	})
+}
+
+func (s *dataExportServiceTestSuite) TestDataExportErasureHook() {
+	hook := NewDataExportErasureHook(s.dataExportRepositoryMock, s.storageMock)
+	s.Equal("data_exports", hook.Name())
+
+	s.dataExportRepositoryMock.EXPECT().FindByUserId(
+		s.ctx, s.user.ID.String(),
+	).Return([]models.DataExport{
+		{ID: uuid.New(), File: "exports/ready.zip"},
+		{ID: uuid.New()},
+	}, nil)
+	s.storageMock.EXPECT().Delete(s.ctx, "exports/ready.zip").Return(nil)
+	s.dataExportRepositoryMock.EXPECT().DeleteByUserId(s.ctx, s.user.ID.String()).Return(nil)
+
+	s.NoError(hook.Erase(s.ctx, s.user))
+}
@@ -0,0 +1,39 @@
+package services
+
+import (
+	"os"
+	"time"
+
+	"verifymy-golang-test/models"
+)
+
+const (
+	emailChangePurpose  = "email_change"
+	emailChangeLifetime = time.Hour * 24
+)
+
+// newEmailChangeToken issues the token sent to the new address of a user,
+// which a newer request supersedes
+func newEmailChangeToken(user *models.User, email string) (string, error) {
+	return signPurposeToken(
+		emailChangePurpose, user, emailChangeLifetime, map[string]string{"email": email},
+	)
+}
+
+func parseEmailChangeToken(token string) (string, string, int64, error) {
+	userId, version, claims, err := parsePurposeToken(emailChangePurpose, token, "email")
+	if err != nil {
+		return "", "", 0, err
+	}
+
+	return userId, claims["email"], version, nil
+}
+
+func emailChangeURL(token string) string {
+	baseURL := os.Getenv("EMAIL_CHANGE_URL")
+	if baseURL == "" {
+		baseURL = "http://localhost:8080/auth/email/confirm"
+	}
+
+	return baseURL + "?token=" + token
+}
@@ -0,0 +1,115 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"verifymy-golang-test/entities"
+	"verifymy-golang-test/models"
+	"verifymy-golang-test/repositories"
+)
+
+// EventPublisher publishes domain events through the outbox, from where they
+// are relayed to the event sinks, see EventRelayService
+type EventPublisher interface {
+	// Publish runs write and stores the events it returns in the outbox in a
+	// single transaction, so events are published if and only if the write
+	// is committed
+	Publish(
+		ctx context.Context, write func(ctx context.Context) ([]entities.DomainEvent, error),
+	) error
+}
+
+func NewEventPublisher(
+	transactor repositories.Transactor,
+	outboxEventRepository repositories.OutboxEventRepository,
+) EventPublisher {
+	return &eventPublisher{
+		transactor:            transactor,
+		outboxEventRepository: outboxEventRepository,
+	}
+}
+
+type eventPublisher struct {
+	transactor            repositories.Transactor
+	outboxEventRepository repositories.OutboxEventRepository
+}
+
+func (p *eventPublisher) Publish(
+	ctx context.Context, write func(ctx context.Context) ([]entities.DomainEvent, error),
+) error {
+	return p.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		events, err := write(ctx)
+		if err != nil {
+			return err
+		}
+
+		outboxEvents := make([]models.OutboxEvent, 0, len(events))
+		for _, event := range events {
+			outboxEvent, err := newOutboxEvent(event)
+			if err != nil {
+				return err
+			}
+
+			outboxEvents = append(outboxEvents, outboxEvent)
+		}
+
+		return p.outboxEventRepository.Create(ctx, outboxEvents)
+	})
+}
+
+func newOutboxEvent(event entities.DomainEvent) (models.OutboxEvent, error) {
+	bytes, err := json.Marshal(event)
+	if err != nil {
+		return models.OutboxEvent{}, err
+	}
+
+	payload := models.JSONMap{}
+	if err := json.Unmarshal(bytes, &payload); err != nil {
+		return models.OutboxEvent{}, err
+	}
+
+	return models.OutboxEvent{
+		Type:        event.EventType(),
+		AggregateID: event.AggregateID(),
+		Payload:     payload,
+		CreatedAt:   time.Now().UTC(),
+	}, nil
+}
+
+// profileEventFields are the columns of a user other services are told
+// about when they change
+var profileEventFields = map[string]bool{
+	"name":          true,
+	"date_of_birth": true,
+	"email":         true,
+	"address":       true,
+	"role":          true,
+}
+
+// profileUpdatedEvents is a UserProfileUpdated naming the profile columns in
+// changes, or nothing when changes touch none, like a password change
+func profileUpdatedEvents(userId string, changes map[string]interface{}) []entities.DomainEvent {
+	fields := []string{}
+	for field := range changes {
+		if profileEventFields[field] {
+			fields = append(fields, field)
+		}
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+	sort.Strings(fields)
+
+	return []entities.DomainEvent{entities.UserProfileUpdated{UserID: userId, Fields: fields}}
+}
+
+func registeredEvent(user *models.User, invited bool) entities.UserRegistered {
+	return entities.UserRegistered{
+		UserID:  user.ID.String(),
+		Role:    user.Role,
+		Invited: invited,
+	}
+}
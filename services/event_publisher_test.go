@@ -0,0 +1,148 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	"verifymy-golang-test/entities"
+	mock_repositories "verifymy-golang-test/mocks/repositories"
+	"verifymy-golang-test/models"
+)
+
+// runWithinTransaction stands in for Transactor.WithinTransaction in tests
+func runWithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// outboxEventsMatcher matches the outbox events stored for events, whatever
+// the time they were stored at
+type outboxEventsMatcher struct {
+	events []entities.DomainEvent
+}
+
+func outboxEventsOf(events ...entities.DomainEvent) gomock.Matcher {
+	return outboxEventsMatcher{events: events}
+}
+
+func (m outboxEventsMatcher) Matches(x interface{}) bool {
+	outboxEvents, ok := x.([]models.OutboxEvent)
+	if !ok || len(outboxEvents) != len(m.events) {
+		return false
+	}
+
+	for i, event := range m.events {
+		expected, err := newOutboxEvent(event)
+		if err != nil {
+			return false
+		}
+
+		if outboxEvents[i].Type != expected.Type ||
+			outboxEvents[i].AggregateID != expected.AggregateID ||
+			fmt.Sprint(outboxEvents[i].Payload) != fmt.Sprint(expected.Payload) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (m outboxEventsMatcher) String() string {
+	return fmt.Sprintf("outbox events of %v", m.events)
+}
+
+type eventPublisherTestSuite struct {
+	suite.Suite
+	ctrl                      *gomock.Controller
+	transactorMock            *mock_repositories.MockTransactor
+	outboxEventRepositoryMock *mock_repositories.MockOutboxEventRepository
+	publisher                 EventPublisher
+}
+
+func TestEventPublisherTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(eventPublisherTestSuite))
+}
+
+func (s *eventPublisherTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.transactorMock = mock_repositories.NewMockTransactor(s.ctrl)
+	s.outboxEventRepositoryMock = mock_repositories.NewMockOutboxEventRepository(s.ctrl)
+	s.publisher = NewEventPublisher(s.transactorMock, s.outboxEventRepositoryMock)
+}
+
+func (s *eventPublisherTestSuite) TestPublish() {
+	userId := uuid.NewString()
+	event := entities.UserDeleted{UserID: userId}
+
+	tests := []struct {
+		description   string
+		events        []entities.DomainEvent
+		writeError    error
+		createError   error
+		expectedError error
+	}{
+		{
+			description: "Success",
+			events:      []entities.DomainEvent{event},
+		},
+		{
+			description: "No events",
+		},
+		{
+			description:   "Error writing",
+			writeError:    errors.New("error writing"),
+			expectedError: errors.New("error writing"),
+		},
+		{
+			description:   "Error storing events",
+			events:        []entities.DomainEvent{event},
+			createError:   errors.New("error storing events"),
+			expectedError: errors.New("error storing events"),
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			ctx := context.Background()
+			txCtx := context.WithValue(ctx, transactionTestKey{}, true)
+
+			s.transactorMock.EXPECT().WithinTransaction(ctx, gomock.Any()).DoAndReturn(
+				func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(txCtx)
+				},
+			)
+			if test.writeError == nil {
+				s.outboxEventRepositoryMock.EXPECT().Create(
+					txCtx, outboxEventsOf(test.events...),
+				).Return(test.createError)
+			}
+
+			err := s.publisher.Publish(ctx, func(ctx context.Context) ([]entities.DomainEvent, error) {
+				s.Equal(txCtx, ctx)
+				return test.events, test.writeError
+			})
+
+			s.Equal(test.expectedError, err)
+		})
+	}
+}
+
+type transactionTestKey struct{}
+
+func (s *eventPublisherTestSuite) TestProfileUpdatedEvents() {
+	s.Equal(
+		[]entities.DomainEvent{entities.UserProfileUpdated{
+			UserID: "user-id", Fields: []string{"address", "name"},
+		}},
+		profileUpdatedEvents("user-id", map[string]interface{}{
+			"name": "Peter", "address": "Queens", "sessions_revoked_at": "now",
+		}),
+	)
+	s.Nil(profileUpdatedEvents("user-id", map[string]interface{}{"password": "hash"}))
+}
@@ -0,0 +1,176 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"verifymy-golang-test/models"
+	"verifymy-golang-test/repositories"
+)
+
+// eventRelayBatchSize is how many events are relayed to each sink per run
+const eventRelayBatchSize = 100
+
+// defaultEventSinks are the sinks enabled when EVENT_SINKS is not set
+const defaultEventSinks = "log,subscriptions"
+
+// EventSink delivers the domain events stored in the outbox out of the
+// service. Events are delivered at least once, so sinks may see an event
+// again when recording its delivery failed.
+type EventSink interface {
+	Name() string
+	Deliver(ctx context.Context, event models.OutboxEvent) error
+}
+
+// EventRelayService relays the events stored by EventPublisher to the sinks
+type EventRelayService interface {
+	RelayPending(ctx context.Context) (int64, error)
+}
+
+// NewEventRelayService delivers to the sinks listed, comma separated, in
+// EVENT_SINKS, in that order, or to the log and subscriptions sinks when it is
+// not set.
+func NewEventRelayService(
+	outboxEventRepository repositories.OutboxEventRepository,
+	sinks []EventSink,
+) (EventRelayService, error) {
+	names := os.Getenv("EVENT_SINKS")
+	if names == "" {
+		names = defaultEventSinks
+	}
+
+	enabledSinks, err := enabledEventSinks(sinks, names)
+	if err != nil {
+		return nil, err
+	}
+
+	return &eventRelayService{
+		outboxEventRepository: outboxEventRepository,
+		sinks:                 enabledSinks,
+	}, nil
+}
+
+// enabledEventSinks returns the sinks named in names, comma separated
+func enabledEventSinks(sinks []EventSink, names string) ([]EventSink, error) {
+	sinksByName := map[string]EventSink{}
+	for _, sink := range sinks {
+		sinksByName[sink.Name()] = sink
+	}
+
+	enabledSinks := []EventSink{}
+	for _, name := range strings.Split(names, ",") {
+		sink, ok := sinksByName[strings.TrimSpace(name)]
+		if !ok {
+			return nil, fmt.Errorf("unknown event sink %q in EVENT_SINKS", name)
+		}
+
+		enabledSinks = append(enabledSinks, sink)
+	}
+
+	return enabledSinks, nil
+}
+
+type eventRelayService struct {
+	outboxEventRepository repositories.OutboxEventRepository
+	sinks                 []EventSink
+}
+
+// eventRelay is what a run of RelayPending did with an event
+type eventRelay struct {
+	event          models.OutboxEvent
+	deliveredSinks models.StringList
+	failures       []string
+}
+
+// RelayPending delivers the oldest events each sink did not get yet to it,
+// in the order they happened. Sinks pick their events separately, so one
+// failing does not hold back the others: the failure is recorded and the
+// event retried on the next run, only on the sinks it did not reach. The
+// failing sink gets no later event meanwhile, so it still sees them in order.
+func (s *eventRelayService) RelayPending(ctx context.Context) (int64, error) {
+	relays := map[uuid.UUID]*eventRelay{}
+	order := []uuid.UUID{}
+	for _, sink := range s.sinks {
+		events, err := s.outboxEventRepository.FindUndeliveredTo(
+			ctx, sink.Name(), eventRelayBatchSize,
+		)
+		if err != nil {
+			return 0, err
+		}
+
+		for _, event := range events {
+			relay, ok := relays[event.ID]
+			if !ok {
+				relay = &eventRelay{
+					event:          event,
+					deliveredSinks: append(models.StringList{}, event.DeliveredSinks...),
+				}
+				relays[event.ID] = relay
+				order = append(order, event.ID)
+			}
+
+			if err := sink.Deliver(ctx, event); err != nil {
+				relay.failures = append(
+					relay.failures, fmt.Sprintf("event sink %s: %s", sink.Name(), err),
+				)
+				break
+			}
+
+			relay.deliveredSinks = append(relay.deliveredSinks, sink.Name())
+		}
+	}
+
+	var delivered int64
+	var relayErr error
+	for _, id := range order {
+		relay := relays[id]
+		if len(relay.failures) == 0 && s.deliveredToAll(relay.deliveredSinks) {
+			if err := s.outboxEventRepository.MarkDelivered(
+				ctx, id.String(), time.Now().UTC(),
+			); err != nil {
+				return delivered, err
+			}
+			delivered++
+
+			continue
+		}
+
+		if len(relay.deliveredSinks) > len(relay.event.DeliveredSinks) {
+			if err := s.outboxEventRepository.MarkDeliveredTo(
+				ctx, id.String(), relay.deliveredSinks,
+			); err != nil {
+				return delivered, err
+			}
+		}
+
+		if len(relay.failures) > 0 {
+			reason := strings.Join(relay.failures, "; ")
+			if err := s.outboxEventRepository.RecordFailure(
+				ctx, id.String(), reason,
+			); err != nil {
+				return delivered, err
+			}
+
+			if relayErr == nil {
+				relayErr = fmt.Errorf("relaying event %s: %s", id, reason)
+			}
+		}
+	}
+
+	return delivered, relayErr
+}
+
+func (s *eventRelayService) deliveredToAll(deliveredSinks models.StringList) bool {
+	for _, sink := range s.sinks {
+		if !deliveredSinks.Includes(sink.Name()) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,253 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	mock_repositories "verifymy-golang-test/mocks/repositories"
+	"verifymy-golang-test/models"
+)
+
+type eventSinkStub struct {
+	name      string
+	err       error
+	delivered []models.OutboxEvent
+}
+
+func (sink *eventSinkStub) Name() string {
+	return sink.name
+}
+
+func (sink *eventSinkStub) Deliver(ctx context.Context, event models.OutboxEvent) error {
+	sink.delivered = append(sink.delivered, event)
+
+	return sink.err
+}
+
+type eventRelayServiceTestSuite struct {
+	suite.Suite
+	ctrl                      *gomock.Controller
+	outboxEventRepositoryMock *mock_repositories.MockOutboxEventRepository
+}
+
+func TestEventRelayServiceTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(eventRelayServiceTestSuite))
+}
+
+func (s *eventRelayServiceTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.outboxEventRepositoryMock = mock_repositories.NewMockOutboxEventRepository(s.ctrl)
+}
+
+func (s *eventRelayServiceTestSuite) TestNewEventRelayService() {
+	logSink := &eventSinkStub{name: EventSinkLog}
+	webhookSink := &eventSinkStub{name: EventSinkWebhook}
+	subscriptionsSink := &eventSinkStub{name: EventSinkSubscriptions}
+
+	service, err := NewEventRelayService(
+		s.outboxEventRepositoryMock, []EventSink{logSink, webhookSink, subscriptionsSink},
+	)
+
+	s.NoError(err)
+	s.Equal([]EventSink{logSink, subscriptionsSink}, service.(*eventRelayService).sinks)
+}
+
+func (s *eventRelayServiceTestSuite) TestEnabledEventSinks() {
+	logSink := &eventSinkStub{name: EventSinkLog}
+	webhookSink := &eventSinkStub{name: EventSinkWebhook}
+	sinks := []EventSink{logSink, webhookSink}
+
+	tests := []struct {
+		description   string
+		names         string
+		expectedSinks []EventSink
+		expectedError string
+	}{
+		{
+			description:   "Listed sinks in order",
+			names:         "webhook, log",
+			expectedSinks: []EventSink{webhookSink, logSink},
+		},
+		{
+			description:   "No sink but the listed ones",
+			names:         "webhook",
+			expectedSinks: []EventSink{webhookSink},
+		},
+		{
+			description:   "Unknown sink",
+			names:         "log,queue",
+			expectedError: `unknown event sink "queue" in EVENT_SINKS`,
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			enabledSinks, err := enabledEventSinks(sinks, test.names)
+
+			if test.expectedError != "" {
+				s.EqualError(err, test.expectedError)
+			} else {
+				s.NoError(err)
+			}
+			s.Equal(test.expectedSinks, enabledSinks)
+		})
+	}
+}
+
+func (s *eventRelayServiceTestSuite) TestRelayPending() {
+	ctx := context.Background()
+	first := models.OutboxEvent{ID: uuid.New(), Type: "user.registered"}
+	second := models.OutboxEvent{ID: uuid.New(), Type: "user.deleted"}
+
+	s.Run("Delivers every event to every sink", func() {
+		s.SetupTest()
+		logSink := &eventSinkStub{name: EventSinkLog}
+		webhookSink := &eventSinkStub{name: EventSinkWebhook}
+		service := &eventRelayService{
+			outboxEventRepository: s.outboxEventRepositoryMock,
+			sinks:                 []EventSink{logSink, webhookSink},
+		}
+
+		for _, sink := range []string{EventSinkLog, EventSinkWebhook} {
+			s.outboxEventRepositoryMock.EXPECT().FindUndeliveredTo(
+				ctx, sink, eventRelayBatchSize,
+			).Return([]models.OutboxEvent{first, second}, nil)
+		}
+		gomock.InOrder(
+			s.outboxEventRepositoryMock.EXPECT().MarkDelivered(ctx, first.ID.String(), gomock.Any()),
+			s.outboxEventRepositoryMock.EXPECT().MarkDelivered(ctx, second.ID.String(), gomock.Any()),
+		)
+
+		relayed, err := service.RelayPending(ctx)
+
+		s.NoError(err)
+		s.Equal(int64(2), relayed)
+		s.Equal([]models.OutboxEvent{first, second}, logSink.delivered)
+		s.Equal([]models.OutboxEvent{first, second}, webhookSink.delivered)
+	})
+
+	s.Run("Keeps relaying to the other sinks when one fails", func() {
+		s.SetupTest()
+		logSink := &eventSinkStub{name: EventSinkLog}
+		webhookSink := &eventSinkStub{name: EventSinkWebhook, err: errors.New("webhook answered 500")}
+		service := &eventRelayService{
+			outboxEventRepository: s.outboxEventRepositoryMock,
+			sinks:                 []EventSink{logSink, webhookSink},
+		}
+
+		for _, sink := range []string{EventSinkLog, EventSinkWebhook} {
+			s.outboxEventRepositoryMock.EXPECT().FindUndeliveredTo(
+				ctx, sink, eventRelayBatchSize,
+			).Return([]models.OutboxEvent{first, second}, nil)
+		}
+		gomock.InOrder(
+			s.outboxEventRepositoryMock.EXPECT().MarkDeliveredTo(
+				ctx, first.ID.String(), models.StringList{EventSinkLog},
+			).Return(nil),
+			s.outboxEventRepositoryMock.EXPECT().RecordFailure(
+				ctx, first.ID.String(), "event sink webhook: webhook answered 500",
+			).Return(nil),
+			s.outboxEventRepositoryMock.EXPECT().MarkDeliveredTo(
+				ctx, second.ID.String(), models.StringList{EventSinkLog},
+			).Return(nil),
+		)
+
+		relayed, err := service.RelayPending(ctx)
+
+		s.EqualError(
+			err, "relaying event "+first.ID.String()+": event sink webhook: webhook answered 500",
+		)
+		s.Zero(relayed)
+		s.Equal([]models.OutboxEvent{first, second}, logSink.delivered)
+		// so the webhook still gets the events in order once it recovers
+		s.Equal([]models.OutboxEvent{first}, webhookSink.delivered)
+	})
+
+	s.Run("Keeps relaying to the other sinks past a batch failed by one", func() {
+		s.SetupTest()
+		logSink := &eventSinkStub{name: EventSinkLog}
+		webhookSink := &eventSinkStub{name: EventSinkWebhook, err: errors.New("webhook answered 500")}
+		service := &eventRelayService{
+			outboxEventRepository: s.outboxEventRepositoryMock,
+			sinks:                 []EventSink{logSink, webhookSink},
+		}
+		// a full batch the webhook keeps failing, which the log already got
+		failed := make([]models.OutboxEvent, eventRelayBatchSize)
+		for i := range failed {
+			failed[i] = models.OutboxEvent{
+				ID: uuid.New(), DeliveredSinks: models.StringList{EventSinkLog},
+			}
+		}
+		newer := models.OutboxEvent{ID: uuid.New(), Type: "user.updated"}
+
+		s.outboxEventRepositoryMock.EXPECT().FindUndeliveredTo(
+			ctx, EventSinkLog, eventRelayBatchSize,
+		).Return([]models.OutboxEvent{newer}, nil)
+		s.outboxEventRepositoryMock.EXPECT().FindUndeliveredTo(
+			ctx, EventSinkWebhook, eventRelayBatchSize,
+		).Return(failed, nil)
+		s.outboxEventRepositoryMock.EXPECT().MarkDeliveredTo(
+			ctx, newer.ID.String(), models.StringList{EventSinkLog},
+		).Return(nil)
+		s.outboxEventRepositoryMock.EXPECT().RecordFailure(
+			ctx, failed[0].ID.String(), "event sink webhook: webhook answered 500",
+		).Return(nil)
+
+		relayed, err := service.RelayPending(ctx)
+
+		s.Error(err)
+		s.Zero(relayed)
+		s.Equal([]models.OutboxEvent{newer}, logSink.delivered)
+		s.Equal([]models.OutboxEvent{failed[0]}, webhookSink.delivered)
+	})
+
+	s.Run("Retries only the sinks an event did not reach", func() {
+		s.SetupTest()
+		logSink := &eventSinkStub{name: EventSinkLog}
+		webhookSink := &eventSinkStub{name: EventSinkWebhook}
+		service := &eventRelayService{
+			outboxEventRepository: s.outboxEventRepositoryMock,
+			sinks:                 []EventSink{logSink, webhookSink},
+		}
+		retried := first
+		retried.DeliveredSinks = models.StringList{EventSinkLog}
+
+		s.outboxEventRepositoryMock.EXPECT().FindUndeliveredTo(
+			ctx, EventSinkLog, eventRelayBatchSize,
+		).Return([]models.OutboxEvent{}, nil)
+		s.outboxEventRepositoryMock.EXPECT().FindUndeliveredTo(
+			ctx, EventSinkWebhook, eventRelayBatchSize,
+		).Return([]models.OutboxEvent{retried}, nil)
+		s.outboxEventRepositoryMock.EXPECT().MarkDelivered(ctx, first.ID.String(), gomock.Any())
+
+		relayed, err := service.RelayPending(ctx)
+
+		s.NoError(err)
+		s.Equal(int64(1), relayed)
+		s.Empty(logSink.delivered)
+		s.Equal([]models.OutboxEvent{retried}, webhookSink.delivered)
+	})
+
+	s.Run("Error finding undelivered events", func() {
+		s.SetupTest()
+		service := &eventRelayService{
+			outboxEventRepository: s.outboxEventRepositoryMock,
+			sinks:                 []EventSink{&eventSinkStub{name: EventSinkLog}},
+		}
+
+		s.outboxEventRepositoryMock.EXPECT().FindUndeliveredTo(
+			ctx, EventSinkLog, eventRelayBatchSize,
+		).Return(nil, errors.New("error finding events"))
+
+		relayed, err := service.RelayPending(ctx)
+
+		s.EqualError(err, "error finding events")
+		s.Zero(relayed)
+	})
+}
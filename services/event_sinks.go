@@ -0,0 +1,156 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"go.uber.org/zap"
+
+	"verifymy-golang-test/models"
+	"verifymy-golang-test/repositories"
+)
+
+const (
+	EventSinkLog           = "log"
+	EventSinkWebhook       = "webhook"
+	EventSinkSubscriptions = "subscriptions"
+)
+
+// webhookTimeout bounds each delivery to the webhook, so a slow receiver
+// does not hold the relay
+const webhookTimeout = time.Second * 10
+
+// NewLogEventSink logs every event, which is handy to follow events locally
+func NewLogEventSink(log *zap.Logger) EventSink {
+	return &logEventSink{
+		log: log,
+	}
+}
+
+type logEventSink struct {
+	log *zap.Logger
+}
+
+func (sink *logEventSink) Name() string {
+	return EventSinkLog
+}
+
+func (sink *logEventSink) Deliver(ctx context.Context, event models.OutboxEvent) error {
+	sink.log.Info(
+		"Domain event",
+		zap.String("id", event.ID.String()),
+		zap.String("type", event.Type),
+		zap.String("aggregate_id", event.AggregateID),
+		zap.Time("occurred_at", event.CreatedAt),
+	)
+
+	return nil
+}
+
+// NewWebhookEventSink posts every event as JSON to EVENTS_WEBHOOK_URL
+func NewWebhookEventSink() EventSink {
+	return &webhookEventSink{
+		url:    os.Getenv("EVENTS_WEBHOOK_URL"),
+		client: &http.Client{Timeout: webhookTimeout},
+	}
+}
+
+type webhookEventSink struct {
+	url    string
+	client *http.Client
+}
+
+func (sink *webhookEventSink) Name() string {
+	return EventSinkWebhook
+}
+
+// Deliver fails unless the webhook answers with a 2xx status. Receivers can
+// tell events apart by the X-Event-ID header, since an event may be delivered
+// more than once.
+func (sink *webhookEventSink) Deliver(ctx context.Context, event models.OutboxEvent) error {
+	if sink.url == "" {
+		return fmt.Errorf("EVENTS_WEBHOOK_URL is not set")
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequestWithContext(
+		ctx, http.MethodPost, sink.url, bytes.NewReader(body),
+	)
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("X-Event-ID", event.ID.String())
+	request.Header.Set("X-Event-Type", event.Type)
+
+	response, err := sink.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf("webhook answered %d", response.StatusCode)
+	}
+
+	return nil
+}
+
+// NewWebhookSubscriptionsEventSink queues a delivery of every event for each
+// webhook subscribed to its type. Deliveries are pushed by
+// WebhookDeliveryService.
+func NewWebhookSubscriptionsEventSink(
+	webhookSubscriptionRepository repositories.WebhookSubscriptionRepository,
+	webhookDeliveryRepository repositories.WebhookDeliveryRepository,
+) EventSink {
+	return &webhookSubscriptionsEventSink{
+		webhookSubscriptionRepository: webhookSubscriptionRepository,
+		webhookDeliveryRepository:     webhookDeliveryRepository,
+	}
+}
+
+type webhookSubscriptionsEventSink struct {
+	webhookSubscriptionRepository repositories.WebhookSubscriptionRepository
+	webhookDeliveryRepository     repositories.WebhookDeliveryRepository
+}
+
+func (sink *webhookSubscriptionsEventSink) Name() string {
+	return EventSinkSubscriptions
+}
+
+func (sink *webhookSubscriptionsEventSink) Deliver(
+	ctx context.Context, event models.OutboxEvent,
+) error {
+	subscriptions, err := sink.webhookSubscriptionRepository.FindByEventType(ctx, event.Type)
+	if err != nil || len(subscriptions) == 0 {
+		return err
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	deliveries := make([]models.WebhookDelivery, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		deliveries = append(deliveries, models.WebhookDelivery{
+			SubscriptionID: subscription.ID.String(),
+			EventID:        event.ID.String(),
+			EventType:      event.Type,
+			Payload:        string(payload),
+			NextAttemptAt:  now,
+		})
+	}
+
+	return sink.webhookDeliveryRepository.Create(ctx, deliveries)
+}
@@ -0,0 +1,152 @@
+package services
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+
+	mock_repositories "verifymy-golang-test/mocks/repositories"
+	"verifymy-golang-test/models"
+)
+
+type eventSinksTestSuite struct {
+	suite.Suite
+	event models.OutboxEvent
+}
+
+func TestEventSinksTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(eventSinksTestSuite))
+}
+
+func (s *eventSinksTestSuite) SetupTest() {
+	s.event = models.OutboxEvent{
+		ID:          uuid.New(),
+		Type:        "user.deleted",
+		AggregateID: "user-id",
+		Payload:     models.JSONMap{"user_id": "user-id", "erased": false},
+		CreatedAt:   time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+		Attempts:    2,
+		LastError:   "webhook answered 503",
+	}
+}
+
+func (s *eventSinksTestSuite) TestLogEventSink() {
+	sink := NewLogEventSink(zap.NewNop())
+
+	s.Equal("log", sink.Name())
+	s.NoError(sink.Deliver(context.Background(), s.event))
+}
+
+func (s *eventSinksTestSuite) TestWebhookEventSink() {
+	tests := []struct {
+		description   string
+		status        int
+		expectedError string
+	}{
+		{
+			description: "Success",
+			status:      http.StatusNoContent,
+		},
+		{
+			description:   "Webhook fails",
+			status:        http.StatusServiceUnavailable,
+			expectedError: "webhook answered 503",
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			server := httptest.NewServer(http.HandlerFunc(
+				func(w http.ResponseWriter, r *http.Request) {
+					s.Equal(http.MethodPost, r.Method)
+					s.Equal("application/json", r.Header.Get("Content-Type"))
+					s.Equal(s.event.ID.String(), r.Header.Get("X-Event-ID"))
+					s.Equal("user.deleted", r.Header.Get("X-Event-Type"))
+
+					body, err := io.ReadAll(r.Body)
+					s.NoError(err)
+					s.JSONEq(`{
+						"id": "`+s.event.ID.String()+`",
+						"type": "user.deleted",
+						"aggregate_id": "user-id",
+						"data": {"user_id": "user-id", "erased": false},
+						"occurred_at": "2024-05-01T10:00:00Z"
+					}`, string(body))
+
+					w.WriteHeader(test.status)
+				},
+			))
+			defer server.Close()
+
+			sink := &webhookEventSink{url: server.URL, client: server.Client()}
+
+			err := sink.Deliver(context.Background(), s.event)
+			if test.expectedError != "" {
+				s.EqualError(err, test.expectedError)
+			} else {
+				s.NoError(err)
+			}
+		})
+	}
+}
+
+func (s *eventSinksTestSuite) TestWebhookEventSinkWithoutURL() {
+	sink := NewWebhookEventSink()
+
+	s.Equal("webhook", sink.Name())
+	s.EqualError(
+		sink.Deliver(context.Background(), s.event), "EVENTS_WEBHOOK_URL is not set",
+	)
+}
+
+func (s *eventSinksTestSuite) TestWebhookSubscriptionsEventSink() {
+	ctrl := gomock.NewController(s.T())
+	webhookSubscriptionRepositoryMock := mock_repositories.NewMockWebhookSubscriptionRepository(ctrl)
+	webhookDeliveryRepositoryMock := mock_repositories.NewMockWebhookDeliveryRepository(ctrl)
+	sink := NewWebhookSubscriptionsEventSink(
+		webhookSubscriptionRepositoryMock, webhookDeliveryRepositoryMock,
+	)
+	ctx := context.Background()
+	subscriptions := []models.WebhookSubscription{{ID: uuid.New()}, {ID: uuid.New()}}
+
+	s.Equal("subscriptions", sink.Name())
+
+	s.Run("Queues a delivery per subscription", func() {
+		webhookSubscriptionRepositoryMock.EXPECT().FindByEventType(ctx, "user.deleted").Return(
+			subscriptions, nil,
+		)
+		webhookDeliveryRepositoryMock.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(
+			func(ctx context.Context, deliveries []models.WebhookDelivery) error {
+				s.Len(deliveries, 2)
+				for i, delivery := range deliveries {
+					s.Equal(subscriptions[i].ID.String(), delivery.SubscriptionID)
+					s.Equal(s.event.ID.String(), delivery.EventID)
+					s.Equal("user.deleted", delivery.EventType)
+					s.Contains(delivery.Payload, `"aggregate_id":"user-id"`)
+					s.WithinDuration(time.Now(), delivery.NextAttemptAt, time.Second*5)
+				}
+
+				return nil
+			},
+		)
+
+		s.NoError(sink.Deliver(ctx, s.event))
+	})
+
+	s.Run("No subscriptions", func() {
+		webhookSubscriptionRepositoryMock.EXPECT().FindByEventType(ctx, "user.deleted").Return(
+			[]models.WebhookSubscription{}, nil,
+		)
+
+		s.NoError(sink.Deliver(ctx, s.event))
+	})
+}
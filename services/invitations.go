@@ -0,0 +1,34 @@
+package services
+
+import (
+	"os"
+	"time"
+
+	"verifymy-golang-test/models"
+)
+
+const (
+	invitationPurpose  = "invitation"
+	invitationLifetime = time.Hour * 72
+)
+
+// newInvitationToken issues the token sent to invited users, which stops
+// working as soon as the invitation is accepted
+func newInvitationToken(user *models.User) (string, error) {
+	return signPurposeToken(invitationPurpose, user, invitationLifetime, nil)
+}
+
+func parseInvitationToken(token string) (string, int64, error) {
+	userId, version, _, err := parsePurposeToken(invitationPurpose, token)
+
+	return userId, version, err
+}
+
+func invitationURL(token string) string {
+	baseURL := os.Getenv("INVITATION_URL")
+	if baseURL == "" {
+		baseURL = "http://localhost:8080/auth/invitations/accept"
+	}
+
+	return baseURL + "?token=" + token
+}
@@ -8,4 +8,17 @@ import (
 
 var Module = fx.Provide(
 	repositories.NewUserRepository,
+	repositories.NewAuditEventRepository,
+	repositories.NewUserTombstoneRepository,
+	repositories.NewDataExportRepository,
+	repositories.NewOutboxEventRepository,
+	repositories.NewTransactor,
+	repositories.NewWebhookSubscriptionRepository,
+	repositories.NewWebhookDeliveryRepository,
+	repositories.NewAgeVerificationRepository,
+	repositories.NewAgeVerificationSessionRepository,
+	repositories.NewClientRepository,
+	repositories.NewOAuthGrantRepository,
+	repositories.NewOAuthAuthorizationCodeRepository,
+	repositories.NewOAuthConsentRepository,
 )
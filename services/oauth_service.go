@@ -0,0 +1,882 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"verifymy-golang-test/common"
+	"verifymy-golang-test/entities"
+	"verifymy-golang-test/models"
+	"verifymy-golang-test/providers"
+	"verifymy-golang-test/repositories"
+)
+
+const (
+	OAuthGrantTypeAuthorizationCode = "authorization_code"
+	OAuthGrantTypeClientCredentials = "client_credentials"
+	OAuthGrantTypeRefreshToken      = "refresh_token"
+	OAuthTokenType                  = "Bearer"
+
+	oauthResponseTypeCode            = "code"
+	oauthCodeChallengeMethod         = "S256"
+	oauthAuthorizationCodeLifetime   = time.Minute * 10
+	defaultOAuthAccessTokenLifetime  = time.Hour
+	defaultOAuthRefreshTokenLifetime = time.Hour * 24 * 30
+	maxOAuthNonceLength              = 255
+)
+
+// pkcePattern matches code verifiers and challenges, RFC 7636
+var pkcePattern = regexp.MustCompile(`^[A-Za-z0-9._~-]{43,128}$`)
+
+// OAuthService lets partner apps act on behalf of users through OAuth 2.0:
+// the authorization code grant with PKCE, refresh tokens and the client
+// credentials grant. Access tokens are JWTs signed like those of users,
+// carrying the client_id they were issued to and the grant they come from,
+// so revoking the grant revokes them.
+//
+// Clients granted the openid scope sign users in through OpenID Connect,
+// getting ID tokens along with access tokens.
+type OAuthService interface {
+	Prompt(
+		ctx context.Context, request entities.OAuthAuthorizationRequest,
+	) (*entities.OAuthAuthorizationPrompt, error)
+	Decide(
+		ctx context.Context, decision entities.OAuthAuthorizationDecision,
+	) (*entities.OAuthAuthorizationResult, error)
+	Token(
+		ctx context.Context,
+		credentials entities.OAuthClientCredentials,
+		request entities.OAuthTokenRequest,
+	) (*entities.OAuthToken, error)
+	Revoke(ctx context.Context, credentials entities.OAuthClientCredentials, token string) error
+	Introspect(
+		ctx context.Context, credentials entities.OAuthClientCredentials, token string,
+	) (*entities.OAuthTokenIntrospection, error)
+	AuthenticateAccessToken(
+		ctx context.Context, accessToken string,
+	) (*models.OAuthGrant, models.StringList, error)
+	Configuration() entities.OpenIDConfiguration
+	UserInfo(ctx context.Context, accessToken string) (*entities.OpenIDUserInfo, error)
+	Logout(ctx context.Context, request entities.OpenIDLogoutRequest) (string, error)
+}
+
+// NewOAuthService issues access tokens lasting OAUTH_ACCESS_TOKEN_LIFETIME,
+// one hour by default, and refresh tokens lasting
+// OAUTH_REFRESH_TOKEN_LIFETIME since they were last used, 30 days by default.
+// Users are sent to OAUTH_AUTHORIZATION_URL, the consent screen, to
+// authorize clients.
+func NewOAuthService(
+	transactor repositories.Transactor,
+	clientService ClientService,
+	clientRepository repositories.ClientRepository,
+	userRepository repositories.UserRepository,
+	oauthGrantRepository repositories.OAuthGrantRepository,
+	oauthAuthorizationCodeRepository repositories.OAuthAuthorizationCodeRepository,
+	oauthConsentRepository repositories.OAuthConsentRepository,
+	auditService AuditService,
+	signingKeys providers.SigningKeys,
+) (OAuthService, error) {
+	lifetimes := map[string]time.Duration{
+		"OAUTH_ACCESS_TOKEN_LIFETIME":  defaultOAuthAccessTokenLifetime,
+		"OAUTH_REFRESH_TOKEN_LIFETIME": defaultOAuthRefreshTokenLifetime,
+	}
+	for name := range lifetimes {
+		if value := os.Getenv(name); value != "" {
+			parsed, err := time.ParseDuration(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s: %w", name, err)
+			}
+
+			lifetimes[name] = parsed
+		}
+	}
+
+	issuer := issuerURL()
+	authorizationURL := os.Getenv("OAUTH_AUTHORIZATION_URL")
+	if authorizationURL == "" {
+		authorizationURL = issuer + "/oauth/authorize"
+	}
+
+	return &oauthService{
+		transactor:                       transactor,
+		clientService:                    clientService,
+		clientRepository:                 clientRepository,
+		userRepository:                   userRepository,
+		oauthGrantRepository:             oauthGrantRepository,
+		oauthAuthorizationCodeRepository: oauthAuthorizationCodeRepository,
+		oauthConsentRepository:           oauthConsentRepository,
+		auditService:                     auditService,
+		signingKeys:                      signingKeys,
+		issuer:                           issuer,
+		authorizationURL:                 authorizationURL,
+		accessTokenLifetime:              lifetimes["OAUTH_ACCESS_TOKEN_LIFETIME"],
+		refreshTokenLifetime:             lifetimes["OAUTH_REFRESH_TOKEN_LIFETIME"],
+		now:                              time.Now,
+	}, nil
+}
+
+type oauthService struct {
+	transactor                       repositories.Transactor
+	clientService                    ClientService
+	clientRepository                 repositories.ClientRepository
+	userRepository                   repositories.UserRepository
+	oauthGrantRepository             repositories.OAuthGrantRepository
+	oauthAuthorizationCodeRepository repositories.OAuthAuthorizationCodeRepository
+	oauthConsentRepository           repositories.OAuthConsentRepository
+	auditService                     AuditService
+	signingKeys                      providers.SigningKeys
+	issuer                           string
+	authorizationURL                 string
+	accessTokenLifetime              time.Duration
+	refreshTokenLifetime             time.Duration
+	now                              func() time.Time
+}
+
+// Prompt validates the authorization request of a client and tells what the
+// consent screen shows the signed in user
+func (s *oauthService) Prompt(
+	ctx context.Context, request entities.OAuthAuthorizationRequest,
+) (*entities.OAuthAuthorizationPrompt, error) {
+	user := ctx.Value(common.AuthUser).(*models.User)
+
+	client, scopes, err := s.validateAuthorization(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	consent, err := s.oauthConsentRepository.FindByUserIdAndClientId(
+		ctx, user.ID.String(), client.ID.String(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	consented := consent != nil
+	for _, scope := range scopes {
+		consented = consented && consent.Scopes.Includes(scope)
+	}
+
+	return &entities.OAuthAuthorizationPrompt{
+		ClientID:   client.ID.String(),
+		ClientName: client.Name,
+		Scopes:     scopes,
+		Consented:  consented,
+	}, nil
+}
+
+// Decide sends the user back to the client with an authorization code when
+// they approve the request, remembering their consent, or with an
+// access_denied error otherwise
+func (s *oauthService) Decide(
+	ctx context.Context, decision entities.OAuthAuthorizationDecision,
+) (*entities.OAuthAuthorizationResult, error) {
+	user := ctx.Value(common.AuthUser).(*models.User)
+
+	client, scopes, err := s.validateAuthorization(ctx, decision.OAuthAuthorizationRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	if decision.Approved == nil {
+		return nil, entities.NewValidationError("approved is required")
+	} else if !*decision.Approved {
+		return &entities.OAuthAuthorizationResult{
+			RedirectURI: oauthRedirectURI(decision.RedirectURI, url.Values{
+				"error": {entities.OAuthErrorAccessDenied},
+				"state": {decision.State},
+			}),
+		}, nil
+	}
+
+	code, codeHash, err := newOAuthToken()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		consent, err := s.oauthConsentRepository.FindByUserIdAndClientId(
+			ctx, user.ID.String(), client.ID.String(),
+		)
+		if err != nil {
+			return err
+		} else if consent == nil {
+			consent = &models.OAuthConsent{
+				UserID: user.ID.String(), ClientID: client.ID.String(), Scopes: models.StringList{},
+			}
+		}
+		for _, scope := range scopes {
+			if !consent.Scopes.Includes(scope) {
+				consent.Scopes = append(consent.Scopes, scope)
+			}
+		}
+		if _, err := s.oauthConsentRepository.Save(ctx, *consent); err != nil {
+			return err
+		}
+
+		return s.oauthAuthorizationCodeRepository.Create(ctx, models.OAuthAuthorizationCode{
+			CodeHash:      codeHash,
+			ClientID:      client.ID.String(),
+			UserID:        user.ID.String(),
+			RedirectURI:   decision.RedirectURI,
+			Scopes:        scopes,
+			CodeChallenge: decision.CodeChallenge,
+			Nonce:         decision.Nonce,
+			ExpiresAt:     s.now().UTC().Add(oauthAuthorizationCodeLifetime),
+		})
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := s.auditService.Record(
+		ctx,
+		AuditActionOAuthAuthorized,
+		auditTargetClient,
+		client.ID.String(),
+		map[string]interface{}{"scopes": scopes},
+	); err != nil {
+		return nil, err
+	}
+
+	return &entities.OAuthAuthorizationResult{
+		RedirectURI: oauthRedirectURI(decision.RedirectURI, url.Values{
+			"code":  {code},
+			"state": {decision.State},
+		}),
+	}, nil
+}
+
+// validateAuthorization returns the client of the request along with the
+// scopes it asks for, which default to every scope of the client users can
+// grant. Until the client and its redirect URI are known to be valid, errors
+// are shown to the user; after that, they are sent back to the client.
+func (s *oauthService) validateAuthorization(
+	ctx context.Context, request entities.OAuthAuthorizationRequest,
+) (*models.Client, models.StringList, error) {
+	if request.ClientID == "" {
+		return nil, nil, entities.NewInvalidParameterError("client_id", "client_id is required")
+	}
+
+	client, err := s.clientRepository.FindById(ctx, request.ClientID)
+	if err != nil {
+		return nil, nil, err
+	} else if client == nil {
+		return nil, nil, entities.NewInvalidParameterError(
+			"client_id", "client_id must be a registered client",
+		)
+	} else if !client.RedirectURIs.Includes(request.RedirectURI) {
+		return nil, nil, entities.NewInvalidParameterError(
+			"redirect_uri", "redirect_uri must be one of the redirect URIs of the client",
+		)
+	}
+
+	fail := func(code string, description string) error {
+		return &entities.OAuthError{
+			Code:        code,
+			Description: description,
+			RedirectURI: oauthRedirectURI(request.RedirectURI, url.Values{
+				"error":             {code},
+				"error_description": {description},
+				"state":             {request.State},
+			}),
+		}
+	}
+
+	if request.ResponseType != oauthResponseTypeCode {
+		return nil, nil, fail(
+			entities.OAuthErrorUnsupportedResponseType, "response_type must be code",
+		)
+	} else if request.CodeChallengeMethod != oauthCodeChallengeMethod {
+		return nil, nil, fail(
+			entities.OAuthErrorInvalidRequest, "code_challenge_method must be S256",
+		)
+	} else if !pkcePattern.MatchString(request.CodeChallenge) {
+		return nil, nil, fail(
+			entities.OAuthErrorInvalidRequest, "code_challenge must be a S256 code challenge",
+		)
+	} else if len(request.Nonce) > maxOAuthNonceLength {
+		return nil, nil, fail(
+			entities.OAuthErrorInvalidRequest,
+			fmt.Sprintf("nonce must be at most %d characters long", maxOAuthNonceLength),
+		)
+	}
+
+	userScopes := models.StringList{}
+	for _, scope := range client.Scopes {
+		if models.ClientUserScopes.Includes(scope) {
+			userScopes = append(userScopes, scope)
+		}
+	}
+
+	scopes, ok := oauthScopes(request.Scope, userScopes)
+	if !ok || len(scopes) == 0 {
+		return nil, nil, fail(
+			entities.OAuthErrorInvalidScope, "scope must only hold scopes of the client users can grant",
+		)
+	}
+
+	return client, scopes, nil
+}
+
+// Token issues tokens to an authenticated client for any of the supported
+// grant types
+func (s *oauthService) Token(
+	ctx context.Context,
+	credentials entities.OAuthClientCredentials,
+	request entities.OAuthTokenRequest,
+) (*entities.OAuthToken, error) {
+	client, err := s.authenticate(ctx, credentials)
+	if err != nil {
+		return nil, err
+	}
+
+	switch request.GrantType {
+	case OAuthGrantTypeAuthorizationCode:
+		return s.exchangeAuthorizationCode(ctx, client, request)
+	case OAuthGrantTypeRefreshToken:
+		return s.refresh(ctx, client, request)
+	case OAuthGrantTypeClientCredentials:
+		return s.grantClient(ctx, client, request)
+	case "":
+		return nil, entities.NewOAuthError(entities.OAuthErrorInvalidRequest, "grant_type is required")
+	default:
+		return nil, entities.NewOAuthError(
+			entities.OAuthErrorUnsupportedGrantType,
+			fmt.Sprintf("grant_type must be one of %v", []string{
+				OAuthGrantTypeAuthorizationCode,
+				OAuthGrantTypeRefreshToken,
+				OAuthGrantTypeClientCredentials,
+			}),
+		)
+	}
+}
+
+// exchangeAuthorizationCode trades a code for an access and a refresh token.
+// Codes are only exchanged once, by the client they were issued to, with
+// the same redirect URI and the code verifier of the code challenge.
+func (s *oauthService) exchangeAuthorizationCode(
+	ctx context.Context, client *models.Client, request entities.OAuthTokenRequest,
+) (*entities.OAuthToken, error) {
+	if request.Code == "" {
+		return nil, entities.NewOAuthError(entities.OAuthErrorInvalidRequest, "code is required")
+	} else if request.CodeVerifier == "" {
+		return nil, entities.NewOAuthError(
+			entities.OAuthErrorInvalidRequest, "code_verifier is required",
+		)
+	}
+
+	codeHash := hashOAuthToken(request.Code)
+	code, err := s.oauthAuthorizationCodeRepository.FindByCodeHash(ctx, codeHash)
+	if err != nil {
+		return nil, err
+	} else if code == nil || code.ClientID != client.ID.String() ||
+		!s.now().Before(code.ExpiresAt) {
+		return nil, entities.NewOAuthError(
+			entities.OAuthErrorInvalidGrant, "code is invalid or expired",
+		)
+	} else if code.RedirectURI != request.RedirectURI {
+		return nil, entities.NewOAuthError(
+			entities.OAuthErrorInvalidGrant, "redirect_uri must be the one the code was issued for",
+		)
+	} else if !verifyCodeChallenge(request.CodeVerifier, code.CodeChallenge) {
+		return nil, entities.NewOAuthError(
+			entities.OAuthErrorInvalidGrant, "code_verifier does not match the code_challenge",
+		)
+	}
+
+	user, err := s.userRepository.FindById(ctx, code.UserID)
+	if err != nil {
+		return nil, err
+	} else if user == nil {
+		return nil, entities.NewOAuthError(
+			entities.OAuthErrorInvalidGrant, "code is invalid or expired",
+		)
+	}
+
+	var token *entities.OAuthToken
+	if err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		deleted, err := s.oauthAuthorizationCodeRepository.DeleteByCodeHash(ctx, codeHash)
+		if err != nil {
+			return err
+		} else if !deleted {
+			return entities.NewOAuthError(
+				entities.OAuthErrorInvalidGrant, "code is invalid or expired",
+			)
+		}
+
+		token, err = s.createGrant(ctx, models.OAuthGrant{
+			ClientID: code.ClientID, UserID: code.UserID, Scopes: code.Scopes,
+		}, true)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	if code.Scopes.Includes(models.ClientScopeOpenID) {
+		if token.IDToken, err = s.idToken(user, code.ClientID, code.Scopes, code.Nonce); err != nil {
+			return nil, err
+		}
+	}
+
+	return token, nil
+}
+
+// refresh issues a new access token from a refresh token, which is replaced
+// by a new one. The scope can be narrowed down, but not widened. ID tokens
+// issued on refresh carry no nonce.
+func (s *oauthService) refresh(
+	ctx context.Context, client *models.Client, request entities.OAuthTokenRequest,
+) (*entities.OAuthToken, error) {
+	if request.RefreshToken == "" {
+		return nil, entities.NewOAuthError(
+			entities.OAuthErrorInvalidRequest, "refresh_token is required",
+		)
+	}
+
+	grant, err := s.oauthGrantRepository.FindByRefreshTokenHash(
+		ctx, hashOAuthToken(request.RefreshToken),
+	)
+	if err != nil {
+		return nil, err
+	} else if grant == nil || grant.ClientID != client.ID.String() || grant.RevokedAt.Valid ||
+		!s.now().Before(grant.RefreshTokenExpiresAt.Time) {
+		return nil, entities.NewOAuthError(
+			entities.OAuthErrorInvalidGrant, "refresh_token is invalid or expired",
+		)
+	}
+
+	scopes, ok := oauthScopes(request.Scope, grant.Scopes)
+	if !ok {
+		return nil, entities.NewOAuthError(
+			entities.OAuthErrorInvalidScope, "scope must only hold scopes granted to the client",
+		)
+	}
+
+	user, err := s.userRepository.FindById(ctx, grant.UserID)
+	if err != nil {
+		return nil, err
+	} else if user == nil || sessionsRevokedSince(user, grantIssuedAt(grant)) {
+		return nil, entities.NewOAuthError(
+			entities.OAuthErrorInvalidGrant, "refresh_token is invalid or expired",
+		)
+	}
+
+	refreshToken, refreshTokenHash, err := newOAuthToken()
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := s.oauthGrantRepository.UpdateRefreshToken(
+		ctx,
+		grant.ID.String(),
+		grant.RefreshTokenHash,
+		refreshTokenHash,
+		s.now().UTC().Add(s.refreshTokenLifetime),
+	)
+	if err != nil {
+		return nil, err
+	} else if !updated {
+		return nil, entities.NewOAuthError(
+			entities.OAuthErrorInvalidGrant, "refresh_token is invalid or expired",
+		)
+	}
+
+	token, err := s.accessToken(grant, scopes)
+	if err != nil {
+		return nil, err
+	}
+
+	if scopes.Includes(models.ClientScopeOpenID) {
+		if token.IDToken, err = s.idToken(user, grant.ClientID, scopes, ""); err != nil {
+			return nil, err
+		}
+	}
+
+	token.RefreshToken = refreshToken
+	return token, nil
+}
+
+// grantClient issues an access token to the client itself, for the scopes
+// of the client users don't grant. No refresh token is issued; the client
+// asks for another access token instead.
+func (s *oauthService) grantClient(
+	ctx context.Context, client *models.Client, request entities.OAuthTokenRequest,
+) (*entities.OAuthToken, error) {
+	clientScopes := models.StringList{}
+	for _, scope := range client.Scopes {
+		if !models.ClientUserScopes.Includes(scope) {
+			clientScopes = append(clientScopes, scope)
+		}
+	}
+	if len(clientScopes) == 0 {
+		return nil, entities.NewOAuthError(
+			entities.OAuthErrorUnauthorizedClient,
+			"client is not allowed to use the client_credentials grant",
+		)
+	}
+
+	scopes, ok := oauthScopes(request.Scope, clientScopes)
+	if !ok {
+		return nil, entities.NewOAuthError(
+			entities.OAuthErrorInvalidScope,
+			fmt.Sprintf("scope must only hold scopes among %v", clientScopes),
+		)
+	}
+
+	return s.createGrant(ctx, models.OAuthGrant{
+		ClientID: client.ID.String(), Scopes: scopes,
+	}, false)
+}
+
+// createGrant stores grant and issues its first access token, along with a
+// refresh token when refreshable
+func (s *oauthService) createGrant(
+	ctx context.Context, grant models.OAuthGrant, refreshable bool,
+) (*entities.OAuthToken, error) {
+	var refreshToken string
+	if refreshable {
+		var refreshTokenHash string
+		var err error
+		if refreshToken, refreshTokenHash, err = newOAuthToken(); err != nil {
+			return nil, err
+		}
+
+		grant.RefreshTokenHash = refreshTokenHash
+		grant.RefreshTokenExpiresAt = sql.NullTime{
+			Time: s.now().UTC().Add(s.refreshTokenLifetime), Valid: true,
+		}
+	}
+
+	created, err := s.oauthGrantRepository.Create(ctx, grant)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := s.accessToken(created, created.Scopes)
+	if err != nil {
+		return nil, err
+	}
+
+	token.RefreshToken = refreshToken
+	return token, nil
+}
+
+// accessToken signs an access token of grant for scopes
+func (s *oauthService) accessToken(
+	grant *models.OAuthGrant, scopes models.StringList,
+) (*entities.OAuthToken, error) {
+	now := s.now().UTC()
+	expiresAt := now.Add(s.accessTokenLifetime).Unix()
+	scope := strings.Join(scopes, " ")
+
+	claims := accessTokenClaims(grant.UserID, now, expiresAt)
+	claims["client_id"] = grant.ClientID
+	claims["grant_id"] = grant.ID.String()
+	claims["scope"] = scope
+
+	accessToken, err := signAccessToken(claims)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entities.OAuthToken{
+		AccessToken: accessToken,
+		TokenType:   OAuthTokenType,
+		ExpiresIn:   expiresAt - now.Unix(),
+		Scope:       scope,
+	}, nil
+}
+
+// Revoke revokes the grant of an access or refresh token of the client,
+// along with every token issued from it, RFC 7009. Unknown tokens and those
+// of other clients are ignored.
+func (s *oauthService) Revoke(
+	ctx context.Context, credentials entities.OAuthClientCredentials, token string,
+) error {
+	client, err := s.authenticate(ctx, credentials)
+	if err != nil {
+		return err
+	} else if token == "" {
+		return entities.NewOAuthError(entities.OAuthErrorInvalidRequest, "token is required")
+	}
+
+	grant, _, err := s.findGrantByToken(ctx, token)
+	if err != nil {
+		return err
+	} else if grant == nil || grant.ClientID != client.ID.String() || grant.RevokedAt.Valid {
+		return nil
+	}
+
+	if err := s.oauthGrantRepository.RevokeById(
+		ctx, grant.ID.String(), s.now().UTC(),
+	); err != nil {
+		return err
+	}
+
+	return s.auditService.Record(
+		ctx,
+		AuditActionOAuthRevoked,
+		auditTargetClient,
+		client.ID.String(),
+		map[string]interface{}{"grant_id": grant.ID.String(), "user_id": grant.UserID},
+	)
+}
+
+// Introspect tells the client whether one of its access or refresh tokens
+// is active, RFC 7662. Tokens of other clients are reported as inactive.
+func (s *oauthService) Introspect(
+	ctx context.Context, credentials entities.OAuthClientCredentials, token string,
+) (*entities.OAuthTokenIntrospection, error) {
+	client, err := s.authenticate(ctx, credentials)
+	if err != nil {
+		return nil, err
+	} else if token == "" {
+		return nil, entities.NewOAuthError(entities.OAuthErrorInvalidRequest, "token is required")
+	}
+
+	inactive := &entities.OAuthTokenIntrospection{Active: false}
+	grant, claims, err := s.findGrantByToken(ctx, token)
+	if err != nil {
+		return nil, err
+	} else if grant == nil || grant.ClientID != client.ID.String() || grant.RevokedAt.Valid {
+		return inactive, nil
+	}
+
+	introspection := &entities.OAuthTokenIntrospection{
+		Active:   true,
+		ClientID: grant.ClientID,
+		Subject:  grant.UserID,
+		Issuer:   s.issuer,
+	}
+	issuedAt := grantIssuedAt(grant)
+	if claims != nil {
+		scope, _ := claims["scope"].(string)
+		expiresAt, _ := claims["exp"].(float64)
+		issuedAt, _ = claims["iat"].(float64)
+
+		introspection.Scope = scope
+		introspection.TokenType = OAuthTokenType
+		introspection.ExpiresAt = int64(expiresAt)
+		introspection.IssuedAt = int64(math.Floor(issuedAt))
+	} else if s.now().Before(grant.RefreshTokenExpiresAt.Time) {
+		introspection.Scope = strings.Join(grant.Scopes, " ")
+		introspection.ExpiresAt = grant.RefreshTokenExpiresAt.Time.Unix()
+	} else {
+		return inactive, nil
+	}
+
+	if grant.UserID == "" {
+		introspection.Subject = grant.ClientID
+	} else if user, err := s.userRepository.FindById(ctx, grant.UserID); err != nil {
+		return nil, err
+	} else if user == nil || sessionsRevokedSince(user, issuedAt) {
+		return inactive, nil
+	}
+
+	return introspection, nil
+}
+
+// AuthenticateAccessToken checks an access token issued through OAuth is
+// still active, returning the grant it comes from and the scopes of the token
+func (s *oauthService) AuthenticateAccessToken(
+	ctx context.Context, accessToken string,
+) (*models.OAuthGrant, models.StringList, error) {
+	grant, _, scopes, err := s.authenticateAccessToken(ctx, accessToken)
+	return grant, scopes, err
+}
+
+// authenticateAccessToken is AuthenticateAccessToken, also returning the
+// user the token was issued on behalf of, nil for tokens of the client
+// itself. Tokens issued on behalf of a user stop working once they are
+// deleted or revoke their sessions, like their own.
+func (s *oauthService) authenticateAccessToken(
+	ctx context.Context, accessToken string,
+) (*models.OAuthGrant, *models.User, models.StringList, error) {
+	claims, err := parseAccessToken(accessToken)
+	if err != nil {
+		return nil, nil, nil, entities.NewInvalidTokenError()
+	}
+
+	grantId, _ := claims["grant_id"].(string)
+	if grantId == "" {
+		return nil, nil, nil, entities.NewInvalidTokenError()
+	}
+
+	grant, err := s.oauthGrantRepository.FindById(ctx, grantId)
+	if err != nil {
+		return nil, nil, nil, err
+	} else if grant == nil || grant.RevokedAt.Valid {
+		return nil, nil, nil, entities.NewInvalidTokenError()
+	}
+
+	var user *models.User
+	if grant.UserID != "" {
+		issuedAt, _ := claims["iat"].(float64)
+		user, err = s.userRepository.FindById(ctx, grant.UserID)
+		if err != nil {
+			return nil, nil, nil, err
+		} else if user == nil || sessionsRevokedSince(user, issuedAt) {
+			return nil, nil, nil, entities.NewInvalidTokenError()
+		}
+	}
+
+	scope, _ := claims["scope"].(string)
+	return grant, user, strings.Fields(scope), nil
+}
+
+// grantIssuedAt is when grant was given, in seconds like the iat of access
+// tokens. Refresh tokens are as old as their grant, as far as revoking the
+// sessions of the user is concerned.
+func grantIssuedAt(grant *models.OAuthGrant) float64 {
+	return float64(grant.CreatedAt.UnixMilli()) / 1000
+}
+
+// findGrantByToken finds the grant of an access token, returning its claims
+// too, or of a refresh token
+func (s *oauthService) findGrantByToken(
+	ctx context.Context, token string,
+) (*models.OAuthGrant, jwt.MapClaims, error) {
+	if claims, err := parseAccessToken(token); err == nil {
+		grantId, _ := claims["grant_id"].(string)
+		if grantId == "" {
+			return nil, nil, nil
+		}
+
+		grant, err := s.oauthGrantRepository.FindById(ctx, grantId)
+		return grant, claims, err
+	}
+
+	grant, err := s.oauthGrantRepository.FindByRefreshTokenHash(ctx, hashOAuthToken(token))
+	return grant, nil, err
+}
+
+func (s *oauthService) authenticate(
+	ctx context.Context, credentials entities.OAuthClientCredentials,
+) (*models.Client, error) {
+	client, err := s.clientService.Authenticate(
+		ctx, credentials.ClientID, credentials.ClientSecret,
+	)
+	if _, ok := err.(*entities.InvalidClientError); ok {
+		return nil, entities.NewOAuthError(
+			entities.OAuthErrorInvalidClient, "client authentication failed",
+		)
+	}
+
+	return client, err
+}
+
+// oauthScopes parses scope, space separated, which must only hold scopes
+// among allowed. It defaults to allowed when empty.
+func oauthScopes(scope string, allowed models.StringList) (models.StringList, bool) {
+	requested := strings.Fields(scope)
+	if len(requested) == 0 {
+		return allowed, true
+	}
+
+	scopes := models.StringList{}
+	for _, scope := range requested {
+		if !allowed.Includes(scope) {
+			return nil, false
+		} else if !scopes.Includes(scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+
+	return scopes, true
+}
+
+// oauthRedirectURI adds params to the query of redirectURI, a redirect URI
+// of a client, leaving out empty ones
+func oauthRedirectURI(redirectURI string, params url.Values) string {
+	parsed, err := url.Parse(redirectURI)
+	if err != nil {
+		return redirectURI
+	}
+
+	query := parsed.Query()
+	for name, values := range params {
+		if len(values) > 0 && values[0] != "" {
+			query.Set(name, values[0])
+		}
+	}
+	parsed.RawQuery = query.Encode()
+
+	return parsed.String()
+}
+
+// newOAuthToken generates an opaque token, like authorization codes and
+// refresh tokens, returning it along with its hash, which is what is kept
+func newOAuthToken() (string, string, error) {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", "", err
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(bytes)
+	return token, hashOAuthToken(token), nil
+}
+
+func hashOAuthToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// verifyCodeChallenge checks verifier against an S256 code challenge,
+// RFC 7636
+func verifyCodeChallenge(verifier string, challenge string) bool {
+	if !pkcePattern.MatchString(verifier) {
+		return false
+	}
+
+	sum := sha256.Sum256([]byte(verifier))
+	expected := base64.RawURLEncoding.EncodeToString(sum[:])
+
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(challenge)) == 1
+}
+
+// oauthErasureHook removes what clients were granted by an erased user
+type oauthErasureHook struct {
+	oauthGrantRepository             repositories.OAuthGrantRepository
+	oauthAuthorizationCodeRepository repositories.OAuthAuthorizationCodeRepository
+	oauthConsentRepository           repositories.OAuthConsentRepository
+}
+
+func NewOAuthErasureHook(
+	oauthGrantRepository repositories.OAuthGrantRepository,
+	oauthAuthorizationCodeRepository repositories.OAuthAuthorizationCodeRepository,
+	oauthConsentRepository repositories.OAuthConsentRepository,
+) ErasureHook {
+	return &oauthErasureHook{
+		oauthGrantRepository:             oauthGrantRepository,
+		oauthAuthorizationCodeRepository: oauthAuthorizationCodeRepository,
+		oauthConsentRepository:           oauthConsentRepository,
+	}
+}
+
+func (h *oauthErasureHook) Name() string {
+	return "oauth"
+}
+
+func (h *oauthErasureHook) Erase(ctx context.Context, user *models.User) error {
+	if err := h.oauthAuthorizationCodeRepository.DeleteByUserId(ctx, user.ID.String()); err != nil {
+		return err
+	}
+	if err := h.oauthGrantRepository.DeleteByUserId(ctx, user.ID.String()); err != nil {
+		return err
+	}
+
+	return h.oauthConsentRepository.DeleteByUserId(ctx, user.ID.String())
+}
@@ -0,0 +1,1063 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	"verifymy-golang-test/common"
+	"verifymy-golang-test/entities"
+	mock_repositories "verifymy-golang-test/mocks/repositories"
+	mock_services "verifymy-golang-test/mocks/services"
+	"verifymy-golang-test/models"
+	"verifymy-golang-test/providers"
+)
+
+const (
+	// The code verifier and challenge of RFC 7636, appendix B
+	testCodeVerifier  = "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+	testCodeChallenge = "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"
+	testRedirectURI   = "https://shop.example.com/callback"
+)
+
+type oauthServiceTestSuite struct {
+	suite.Suite
+	ctrl                  *gomock.Controller
+	ctx                   context.Context
+	now                   time.Time
+	user                  *models.User
+	client                *models.Client
+	clientServiceMock     *mock_services.MockClientService
+	clientRepositoryMock  *mock_repositories.MockClientRepository
+	userRepositoryMock    *mock_repositories.MockUserRepository
+	grantRepositoryMock   *mock_repositories.MockOAuthGrantRepository
+	codeRepositoryMock    *mock_repositories.MockOAuthAuthorizationCodeRepository
+	consentRepositoryMock *mock_repositories.MockOAuthConsentRepository
+	auditServiceMock      *mock_services.MockAuditService
+	credentials           entities.OAuthClientCredentials
+	signingKeys           providers.SigningKeys
+	service               *oauthService
+}
+
+func TestOAuthServiceTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(oauthServiceTestSuite))
+}
+
+func (s *oauthServiceTestSuite) SetupSuite() {
+	signingKeys, err := providers.NewSigningKeys()
+	s.Require().NoError(err)
+
+	s.signingKeys = signingKeys
+}
+
+func (s *oauthServiceTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.user = &models.User{ID: uuid.New(), Email: "john@doe.com"}
+	s.ctx = context.WithValue(context.Background(), common.AuthUser, s.user)
+	s.now = time.Now().UTC().Truncate(time.Second)
+	s.client = &models.Client{
+		ID:           uuid.New(),
+		Name:         "Shop",
+		RedirectURIs: models.StringList{testRedirectURI},
+		Scopes: models.StringList{
+			models.ClientScopeAgeTokens, models.ClientScopeProfile, models.ClientScopeEmail,
+		},
+	}
+	s.credentials = entities.OAuthClientCredentials{
+		ClientID: s.client.ID.String(), ClientSecret: "client-secret",
+	}
+	s.clientServiceMock = mock_services.NewMockClientService(s.ctrl)
+	s.clientRepositoryMock = mock_repositories.NewMockClientRepository(s.ctrl)
+	s.userRepositoryMock = mock_repositories.NewMockUserRepository(s.ctrl)
+	s.grantRepositoryMock = mock_repositories.NewMockOAuthGrantRepository(s.ctrl)
+	s.codeRepositoryMock = mock_repositories.NewMockOAuthAuthorizationCodeRepository(s.ctrl)
+	s.consentRepositoryMock = mock_repositories.NewMockOAuthConsentRepository(s.ctrl)
+	s.auditServiceMock = mock_services.NewMockAuditService(s.ctrl)
+
+	transactorMock := mock_repositories.NewMockTransactor(s.ctrl)
+	transactorMock.EXPECT().WithinTransaction(
+		gomock.Any(), gomock.Any(),
+	).DoAndReturn(runWithinTransaction).AnyTimes()
+
+	service, err := NewOAuthService(
+		transactorMock,
+		s.clientServiceMock,
+		s.clientRepositoryMock,
+		s.userRepositoryMock,
+		s.grantRepositoryMock,
+		s.codeRepositoryMock,
+		s.consentRepositoryMock,
+		s.auditServiceMock,
+		s.signingKeys,
+	)
+	s.Require().NoError(err)
+
+	s.service = service.(*oauthService)
+	s.service.now = func() time.Time { return s.now }
+}
+
+func (s *oauthServiceTestSuite) authorizationRequest() entities.OAuthAuthorizationRequest {
+	return entities.OAuthAuthorizationRequest{
+		ResponseType:        "code",
+		ClientID:            s.client.ID.String(),
+		RedirectURI:         testRedirectURI,
+		Scope:               "profile",
+		State:               "xyz",
+		CodeChallenge:       testCodeChallenge,
+		CodeChallengeMethod: "S256",
+		Nonce:               "n-0S6_WzA2Mj",
+	}
+}
+
+func (s *oauthServiceTestSuite) expectAuthentication() {
+	s.clientServiceMock.EXPECT().Authenticate(
+		s.ctx, s.client.ID.String(), "client-secret",
+	).Return(s.client, nil)
+}
+
+func (s *oauthServiceTestSuite) grant(userId string) *models.OAuthGrant {
+	return &models.OAuthGrant{
+		ID:                    uuid.New(),
+		ClientID:              s.client.ID.String(),
+		UserID:                userId,
+		Scopes:                models.StringList{models.ClientScopeProfile, models.ClientScopeEmail},
+		RefreshTokenHash:      hashOAuthToken("refresh-token"),
+		RefreshTokenExpiresAt: sql.NullTime{Time: s.now.Add(time.Hour), Valid: true},
+	}
+}
+
+func (s *oauthServiceTestSuite) TestPromptValidation() {
+	clientError := func(code string, description string) error {
+		return &entities.OAuthError{
+			Code:        code,
+			Description: description,
+			RedirectURI: testRedirectURI + "?" + url.Values{
+				"error":             {code},
+				"error_description": {description},
+				"state":             {"xyz"},
+			}.Encode(),
+		}
+	}
+
+	tests := []struct {
+		description   string
+		change        func(request *entities.OAuthAuthorizationRequest)
+		client        *models.Client
+		expectedError error
+	}{
+		{
+			description: "Missing client",
+			change:      func(request *entities.OAuthAuthorizationRequest) { request.ClientID = "" },
+			expectedError: entities.NewInvalidParameterError(
+				"client_id", "client_id is required",
+			),
+		},
+		{
+			description: "Unknown client",
+			change:      func(request *entities.OAuthAuthorizationRequest) {},
+			expectedError: entities.NewInvalidParameterError(
+				"client_id", "client_id must be a registered client",
+			),
+		},
+		{
+			description: "Other redirect URI",
+			change: func(request *entities.OAuthAuthorizationRequest) {
+				request.RedirectURI = "https://evil.example.com/callback"
+			},
+			client: s.client,
+			expectedError: entities.NewInvalidParameterError(
+				"redirect_uri", "redirect_uri must be one of the redirect URIs of the client",
+			),
+		},
+		{
+			description: "Implicit flow",
+			change: func(request *entities.OAuthAuthorizationRequest) {
+				request.ResponseType = "token"
+			},
+			client: s.client,
+			expectedError: clientError(
+				entities.OAuthErrorUnsupportedResponseType, "response_type must be code",
+			),
+		},
+		{
+			description: "Plain code challenge",
+			change: func(request *entities.OAuthAuthorizationRequest) {
+				request.CodeChallengeMethod = "plain"
+			},
+			client: s.client,
+			expectedError: clientError(
+				entities.OAuthErrorInvalidRequest, "code_challenge_method must be S256",
+			),
+		},
+		{
+			description: "Missing code challenge",
+			change: func(request *entities.OAuthAuthorizationRequest) {
+				request.CodeChallenge = ""
+			},
+			client: s.client,
+			expectedError: clientError(
+				entities.OAuthErrorInvalidRequest, "code_challenge must be a S256 code challenge",
+			),
+		},
+		{
+			description: "Long nonce",
+			change: func(request *entities.OAuthAuthorizationRequest) {
+				request.Nonce = strings.Repeat("n", 256)
+			},
+			client: s.client,
+			expectedError: clientError(
+				entities.OAuthErrorInvalidRequest, "nonce must be at most 255 characters long",
+			),
+		},
+		{
+			description: "Scope of the client itself",
+			change: func(request *entities.OAuthAuthorizationRequest) {
+				request.Scope = "profile age_tokens"
+			},
+			client: s.client,
+			expectedError: clientError(
+				entities.OAuthErrorInvalidScope,
+				"scope must only hold scopes of the client users can grant",
+			),
+		},
+		{
+			description: "Client without user scopes",
+			change: func(request *entities.OAuthAuthorizationRequest) {
+				request.Scope = ""
+			},
+			client: &models.Client{
+				ID:           s.client.ID,
+				RedirectURIs: s.client.RedirectURIs,
+				Scopes:       models.StringList{models.ClientScopeAgeTokens},
+			},
+			expectedError: clientError(
+				entities.OAuthErrorInvalidScope,
+				"scope must only hold scopes of the client users can grant",
+			),
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			request := s.authorizationRequest()
+			test.change(&request)
+			if request.ClientID != "" {
+				s.clientRepositoryMock.EXPECT().FindById(
+					s.ctx, s.client.ID.String(),
+				).Return(test.client, nil)
+			}
+
+			prompt, err := s.service.Prompt(s.ctx, request)
+
+			s.Nil(prompt)
+			s.Equal(test.expectedError, err)
+		})
+	}
+}
+
+func (s *oauthServiceTestSuite) TestPrompt() {
+	tests := []struct {
+		description       string
+		scope             string
+		consent           *models.OAuthConsent
+		expectedScopes    []string
+		expectedConsented bool
+	}{
+		{
+			description:    "Without consent",
+			scope:          "profile",
+			expectedScopes: []string{"profile"},
+		},
+		{
+			description:    "Default scopes",
+			expectedScopes: []string{"profile", "email"},
+		},
+		{
+			description:       "Consented",
+			scope:             "profile",
+			consent:           &models.OAuthConsent{Scopes: models.StringList{"profile", "email"}},
+			expectedScopes:    []string{"profile"},
+			expectedConsented: true,
+		},
+		{
+			description:    "Consented to fewer scopes",
+			scope:          "profile email",
+			consent:        &models.OAuthConsent{Scopes: models.StringList{"profile"}},
+			expectedScopes: []string{"profile", "email"},
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			request := s.authorizationRequest()
+			request.Scope = test.scope
+			s.clientRepositoryMock.EXPECT().FindById(s.ctx, s.client.ID.String()).Return(s.client, nil)
+			s.consentRepositoryMock.EXPECT().FindByUserIdAndClientId(
+				s.ctx, s.user.ID.String(), s.client.ID.String(),
+			).Return(test.consent, nil)
+
+			prompt, err := s.service.Prompt(s.ctx, request)
+
+			s.NoError(err)
+			s.Equal(&entities.OAuthAuthorizationPrompt{
+				ClientID:   s.client.ID.String(),
+				ClientName: "Shop",
+				Scopes:     test.expectedScopes,
+				Consented:  test.expectedConsented,
+			}, prompt)
+		})
+	}
+}
+
+func (s *oauthServiceTestSuite) TestDecide() {
+	approved := true
+	consent := &models.OAuthConsent{
+		ID:       uuid.New(),
+		UserID:   s.user.ID.String(),
+		ClientID: s.client.ID.String(),
+		Scopes:   models.StringList{models.ClientScopeEmail},
+	}
+
+	var stored models.OAuthAuthorizationCode
+	s.clientRepositoryMock.EXPECT().FindById(s.ctx, s.client.ID.String()).Return(s.client, nil)
+	s.consentRepositoryMock.EXPECT().FindByUserIdAndClientId(
+		s.ctx, s.user.ID.String(), s.client.ID.String(),
+	).Return(consent, nil)
+	s.consentRepositoryMock.EXPECT().Save(s.ctx, models.OAuthConsent{
+		ID:       consent.ID,
+		UserID:   s.user.ID.String(),
+		ClientID: s.client.ID.String(),
+		Scopes:   models.StringList{models.ClientScopeEmail, models.ClientScopeProfile},
+	}).Return(consent, nil)
+	s.codeRepositoryMock.EXPECT().Create(s.ctx, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, code models.OAuthAuthorizationCode) error {
+			stored = code
+			return nil
+		},
+	)
+	s.auditServiceMock.EXPECT().Record(
+		s.ctx, AuditActionOAuthAuthorized, auditTargetClient, s.client.ID.String(),
+		map[string]interface{}{"scopes": models.StringList{"profile"}},
+	).Return(nil)
+
+	result, err := s.service.Decide(s.ctx, entities.OAuthAuthorizationDecision{
+		OAuthAuthorizationRequest: s.authorizationRequest(), Approved: &approved,
+	})
+	s.Require().NoError(err)
+
+	redirectURI, err := url.Parse(result.RedirectURI)
+	s.Require().NoError(err)
+	s.Equal("shop.example.com", redirectURI.Host)
+	s.Equal("/callback", redirectURI.Path)
+	s.Equal("xyz", redirectURI.Query().Get("state"))
+	s.Equal(hashOAuthToken(redirectURI.Query().Get("code")), stored.CodeHash)
+	s.Equal(s.client.ID.String(), stored.ClientID)
+	s.Equal(s.user.ID.String(), stored.UserID)
+	s.Equal(testRedirectURI, stored.RedirectURI)
+	s.Equal(models.StringList{"profile"}, stored.Scopes)
+	s.Equal(testCodeChallenge, stored.CodeChallenge)
+	s.Equal("n-0S6_WzA2Mj", stored.Nonce)
+	s.Equal(s.now.Add(10*time.Minute), stored.ExpiresAt)
+}
+
+func (s *oauthServiceTestSuite) TestDecideDenied() {
+	denied := false
+	s.clientRepositoryMock.EXPECT().FindById(
+		s.ctx, s.client.ID.String(),
+	).Return(s.client, nil).Times(2)
+
+	result, err := s.service.Decide(s.ctx, entities.OAuthAuthorizationDecision{
+		OAuthAuthorizationRequest: s.authorizationRequest(), Approved: &denied,
+	})
+	s.NoError(err)
+	s.Equal(&entities.OAuthAuthorizationResult{
+		RedirectURI: testRedirectURI + "?error=access_denied&state=xyz",
+	}, result)
+
+	result, err = s.service.Decide(s.ctx, entities.OAuthAuthorizationDecision{
+		OAuthAuthorizationRequest: s.authorizationRequest(),
+	})
+	s.Nil(result)
+	s.Equal(entities.NewValidationError("approved is required"), err)
+}
+
+func (s *oauthServiceTestSuite) TestTokenAuthorizationCode() {
+	code := &models.OAuthAuthorizationCode{
+		CodeHash:      hashOAuthToken("the-code"),
+		ClientID:      s.client.ID.String(),
+		UserID:        s.user.ID.String(),
+		RedirectURI:   testRedirectURI,
+		Scopes:        models.StringList{models.ClientScopeProfile},
+		CodeChallenge: testCodeChallenge,
+		ExpiresAt:     s.now.Add(time.Minute),
+	}
+	grantId := uuid.New()
+
+	s.expectAuthentication()
+	s.codeRepositoryMock.EXPECT().FindByCodeHash(s.ctx, code.CodeHash).Return(code, nil)
+	s.userRepositoryMock.EXPECT().FindById(s.ctx, s.user.ID.String()).Return(s.user, nil)
+	s.codeRepositoryMock.EXPECT().DeleteByCodeHash(s.ctx, code.CodeHash).Return(true, nil)
+	s.grantRepositoryMock.EXPECT().Create(s.ctx, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, grant models.OAuthGrant) (*models.OAuthGrant, error) {
+			s.Equal(s.client.ID.String(), grant.ClientID)
+			s.Equal(s.user.ID.String(), grant.UserID)
+			s.Equal(models.StringList{"profile"}, grant.Scopes)
+			s.NotEmpty(grant.RefreshTokenHash)
+			s.Equal(s.now.Add(30*24*time.Hour), grant.RefreshTokenExpiresAt.Time)
+
+			grant.ID = grantId
+			return &grant, nil
+		},
+	)
+
+	token, err := s.service.Token(s.ctx, s.credentials, entities.OAuthTokenRequest{
+		GrantType:    OAuthGrantTypeAuthorizationCode,
+		Code:         "the-code",
+		RedirectURI:  testRedirectURI,
+		CodeVerifier: testCodeVerifier,
+	})
+	s.Require().NoError(err)
+
+	s.Equal("Bearer", token.TokenType)
+	s.Equal(int64(3600), token.ExpiresIn)
+	s.Equal("profile", token.Scope)
+	s.NotEmpty(token.RefreshToken)
+	s.Empty(token.IDToken)
+
+	claims, err := parseAccessToken(token.AccessToken)
+	s.Require().NoError(err)
+	s.Equal(s.user.ID.String(), claims["user_id"])
+	s.Equal(s.client.ID.String(), claims["client_id"])
+	s.Equal(grantId.String(), claims["grant_id"])
+	s.Equal("profile", claims["scope"])
+}
+
+func (s *oauthServiceTestSuite) TestTokenAuthorizationCodeValidation() {
+	code := &models.OAuthAuthorizationCode{
+		CodeHash:      hashOAuthToken("the-code"),
+		ClientID:      s.client.ID.String(),
+		UserID:        s.user.ID.String(),
+		RedirectURI:   testRedirectURI,
+		Scopes:        models.StringList{models.ClientScopeProfile},
+		CodeChallenge: testCodeChallenge,
+		ExpiresAt:     s.now.Add(time.Minute),
+	}
+	expired := *code
+	expired.ExpiresAt = s.now
+	otherClient := *code
+	otherClient.ClientID = uuid.NewString()
+	request := entities.OAuthTokenRequest{
+		GrantType:    OAuthGrantTypeAuthorizationCode,
+		Code:         "the-code",
+		RedirectURI:  testRedirectURI,
+		CodeVerifier: testCodeVerifier,
+	}
+	invalidCode := entities.NewOAuthError(
+		entities.OAuthErrorInvalidGrant, "code is invalid or expired",
+	)
+
+	tests := []struct {
+		description   string
+		change        func(request *entities.OAuthTokenRequest)
+		code          *models.OAuthAuthorizationCode
+		skipFind      bool
+		exchange      bool
+		expectedError error
+	}{
+		{
+			description: "Missing code",
+			change:      func(request *entities.OAuthTokenRequest) { request.Code = "" },
+			skipFind:    true,
+			expectedError: entities.NewOAuthError(
+				entities.OAuthErrorInvalidRequest, "code is required",
+			),
+		},
+		{
+			description: "Missing code verifier",
+			change:      func(request *entities.OAuthTokenRequest) { request.CodeVerifier = "" },
+			skipFind:    true,
+			expectedError: entities.NewOAuthError(
+				entities.OAuthErrorInvalidRequest, "code_verifier is required",
+			),
+		},
+		{
+			description:   "Unknown code",
+			change:        func(request *entities.OAuthTokenRequest) {},
+			expectedError: invalidCode,
+		},
+		{
+			description:   "Expired code",
+			change:        func(request *entities.OAuthTokenRequest) {},
+			code:          &expired,
+			expectedError: invalidCode,
+		},
+		{
+			description:   "Code of another client",
+			change:        func(request *entities.OAuthTokenRequest) {},
+			code:          &otherClient,
+			expectedError: invalidCode,
+		},
+		{
+			description: "Other redirect URI",
+			change: func(request *entities.OAuthTokenRequest) {
+				request.RedirectURI = "https://shop.example.com/other"
+			},
+			code: code,
+			expectedError: entities.NewOAuthError(
+				entities.OAuthErrorInvalidGrant, "redirect_uri must be the one the code was issued for",
+			),
+		},
+		{
+			description: "Wrong code verifier",
+			change: func(request *entities.OAuthTokenRequest) {
+				request.CodeVerifier = "a" + testCodeVerifier
+			},
+			code: code,
+			expectedError: entities.NewOAuthError(
+				entities.OAuthErrorInvalidGrant, "code_verifier does not match the code_challenge",
+			),
+		},
+		{
+			description:   "Code already exchanged",
+			change:        func(request *entities.OAuthTokenRequest) {},
+			code:          code,
+			exchange:      true,
+			expectedError: invalidCode,
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			request := request
+			test.change(&request)
+			s.expectAuthentication()
+			if !test.skipFind {
+				s.codeRepositoryMock.EXPECT().FindByCodeHash(
+					s.ctx, code.CodeHash,
+				).Return(test.code, nil)
+			}
+			if test.exchange {
+				s.userRepositoryMock.EXPECT().FindById(s.ctx, s.user.ID.String()).Return(s.user, nil)
+				s.codeRepositoryMock.EXPECT().DeleteByCodeHash(
+					s.ctx, code.CodeHash,
+				).Return(false, nil)
+			}
+
+			token, err := s.service.Token(s.ctx, s.credentials, request)
+
+			s.Nil(token)
+			s.Equal(test.expectedError, err)
+		})
+	}
+}
+
+func (s *oauthServiceTestSuite) TestTokenRefresh() {
+	grant := s.grant(s.user.ID.String())
+
+	s.expectAuthentication()
+	s.grantRepositoryMock.EXPECT().FindByRefreshTokenHash(
+		s.ctx, hashOAuthToken("refresh-token"),
+	).Return(grant, nil)
+	s.userRepositoryMock.EXPECT().FindById(s.ctx, s.user.ID.String()).Return(s.user, nil)
+
+	var nextRefreshTokenHash string
+	s.grantRepositoryMock.EXPECT().UpdateRefreshToken(
+		s.ctx, grant.ID.String(), grant.RefreshTokenHash, gomock.Any(), s.now.Add(30*24*time.Hour),
+	).DoAndReturn(
+		func(ctx context.Context, id string, currentHash string, hash string, expiresAt time.Time) (bool, error) {
+			nextRefreshTokenHash = hash
+			return true, nil
+		},
+	)
+
+	token, err := s.service.Token(s.ctx, s.credentials, entities.OAuthTokenRequest{
+		GrantType: OAuthGrantTypeRefreshToken, RefreshToken: "refresh-token", Scope: "email",
+	})
+	s.Require().NoError(err)
+
+	s.Equal("email", token.Scope)
+	s.Equal(hashOAuthToken(token.RefreshToken), nextRefreshTokenHash)
+
+	claims, err := parseAccessToken(token.AccessToken)
+	s.Require().NoError(err)
+	s.Equal(grant.ID.String(), claims["grant_id"])
+	s.Equal("email", claims["scope"])
+}
+
+func (s *oauthServiceTestSuite) TestTokenRefreshValidation() {
+	grant := s.grant(s.user.ID.String())
+	revoked := *grant
+	revoked.RevokedAt = sql.NullTime{Time: s.now, Valid: true}
+	expired := *grant
+	expired.RefreshTokenExpiresAt = sql.NullTime{Time: s.now, Valid: true}
+	grant.CreatedAt = s.now.Add(-time.Hour)
+	signedOut := *s.user
+	signedOut.SessionsRevokedAt = sql.NullTime{Time: s.now, Valid: true}
+	invalidRefreshToken := entities.NewOAuthError(
+		entities.OAuthErrorInvalidGrant, "refresh_token is invalid or expired",
+	)
+
+	tests := []struct {
+		description   string
+		scope         string
+		grant         *models.OAuthGrant
+		user          *models.User
+		rotate        bool
+		expectedError error
+	}{
+		{description: "Unknown refresh token", expectedError: invalidRefreshToken},
+		{
+			description:   "Sessions revoked since the grant",
+			grant:         grant,
+			user:          &signedOut,
+			expectedError: invalidRefreshToken,
+		},
+		{description: "Revoked grant", grant: &revoked, expectedError: invalidRefreshToken},
+		{description: "Expired refresh token", grant: &expired, expectedError: invalidRefreshToken},
+		{
+			description: "Wider scope",
+			scope:       "profile age_tokens",
+			grant:       grant,
+			expectedError: entities.NewOAuthError(
+				entities.OAuthErrorInvalidScope, "scope must only hold scopes granted to the client",
+			),
+		},
+		{
+			description:   "Refresh token already used",
+			grant:         grant,
+			rotate:        true,
+			expectedError: invalidRefreshToken,
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			s.expectAuthentication()
+			s.grantRepositoryMock.EXPECT().FindByRefreshTokenHash(
+				s.ctx, hashOAuthToken("refresh-token"),
+			).Return(test.grant, nil)
+			if test.user != nil {
+				s.userRepositoryMock.EXPECT().FindById(s.ctx, s.user.ID.String()).Return(test.user, nil)
+			}
+			if test.rotate {
+				s.userRepositoryMock.EXPECT().FindById(s.ctx, s.user.ID.String()).Return(s.user, nil)
+				s.grantRepositoryMock.EXPECT().UpdateRefreshToken(
+					s.ctx, grant.ID.String(), grant.RefreshTokenHash, gomock.Any(), gomock.Any(),
+				).Return(false, nil)
+			}
+
+			token, err := s.service.Token(s.ctx, s.credentials, entities.OAuthTokenRequest{
+				GrantType: OAuthGrantTypeRefreshToken, RefreshToken: "refresh-token", Scope: test.scope,
+			})
+
+			s.Nil(token)
+			s.Equal(test.expectedError, err)
+		})
+	}
+}
+
+func (s *oauthServiceTestSuite) TestTokenClientCredentials() {
+	grantId := uuid.New()
+	s.expectAuthentication()
+	s.grantRepositoryMock.EXPECT().Create(s.ctx, models.OAuthGrant{
+		ClientID: s.client.ID.String(), Scopes: models.StringList{models.ClientScopeAgeTokens},
+	}).DoAndReturn(
+		func(ctx context.Context, grant models.OAuthGrant) (*models.OAuthGrant, error) {
+			grant.ID = grantId
+			return &grant, nil
+		},
+	)
+
+	token, err := s.service.Token(s.ctx, s.credentials, entities.OAuthTokenRequest{
+		GrantType: OAuthGrantTypeClientCredentials,
+	})
+	s.Require().NoError(err)
+
+	s.Equal("age_tokens", token.Scope)
+	s.Empty(token.RefreshToken)
+
+	claims, err := parseAccessToken(token.AccessToken)
+	s.Require().NoError(err)
+	s.NotContains(claims, "user_id")
+	s.Equal(s.client.ID.String(), claims["client_id"])
+	s.Equal(grantId.String(), claims["grant_id"])
+}
+
+func (s *oauthServiceTestSuite) TestTokenValidation() {
+	userScopesClient := &models.Client{
+		ID: s.client.ID, Scopes: models.StringList{models.ClientScopeProfile},
+	}
+
+	tests := []struct {
+		description       string
+		request           entities.OAuthTokenRequest
+		client            *models.Client
+		authenticateError error
+		expectedError     error
+	}{
+		{
+			description:       "Invalid client",
+			request:           entities.OAuthTokenRequest{GrantType: OAuthGrantTypeClientCredentials},
+			authenticateError: entities.NewInvalidClientError(),
+			expectedError: entities.NewOAuthError(
+				entities.OAuthErrorInvalidClient, "client authentication failed",
+			),
+		},
+		{
+			description:       "Unexpected error",
+			request:           entities.OAuthTokenRequest{GrantType: OAuthGrantTypeClientCredentials},
+			authenticateError: errors.New("database unavailable"),
+			expectedError:     errors.New("database unavailable"),
+		},
+		{
+			description: "Missing grant type",
+			request:     entities.OAuthTokenRequest{},
+			client:      s.client,
+			expectedError: entities.NewOAuthError(
+				entities.OAuthErrorInvalidRequest, "grant_type is required",
+			),
+		},
+		{
+			description: "Unsupported grant type",
+			request:     entities.OAuthTokenRequest{GrantType: "password"},
+			client:      s.client,
+			expectedError: entities.NewOAuthError(
+				entities.OAuthErrorUnsupportedGrantType,
+				"grant_type must be one of [authorization_code refresh_token client_credentials]",
+			),
+		},
+		{
+			description: "Client credentials for user scopes",
+			request: entities.OAuthTokenRequest{
+				GrantType: OAuthGrantTypeClientCredentials, Scope: "profile",
+			},
+			client: s.client,
+			expectedError: entities.NewOAuthError(
+				entities.OAuthErrorInvalidScope, "scope must only hold scopes among [age_tokens]",
+			),
+		},
+		{
+			description: "Client credentials without client scopes",
+			request:     entities.OAuthTokenRequest{GrantType: OAuthGrantTypeClientCredentials},
+			client:      userScopesClient,
+			expectedError: entities.NewOAuthError(
+				entities.OAuthErrorUnauthorizedClient,
+				"client is not allowed to use the client_credentials grant",
+			),
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			s.clientServiceMock.EXPECT().Authenticate(
+				s.ctx, s.client.ID.String(), "client-secret",
+			).Return(test.client, test.authenticateError)
+
+			token, err := s.service.Token(s.ctx, s.credentials, test.request)
+
+			s.Nil(token)
+			s.Equal(test.expectedError, err)
+		})
+	}
+}
+
+func (s *oauthServiceTestSuite) TestRevoke() {
+	grant := s.grant(s.user.ID.String())
+	otherGrant := s.grant(s.user.ID.String())
+	otherGrant.ClientID = uuid.NewString()
+	accessToken, err := s.service.accessToken(grant, grant.Scopes)
+	s.Require().NoError(err)
+
+	s.Run("Access token", func() {
+		s.expectAuthentication()
+		s.grantRepositoryMock.EXPECT().FindById(s.ctx, grant.ID.String()).Return(grant, nil)
+		s.grantRepositoryMock.EXPECT().RevokeById(s.ctx, grant.ID.String(), s.now).Return(nil)
+		s.auditServiceMock.EXPECT().Record(
+			s.ctx, AuditActionOAuthRevoked, auditTargetClient, s.client.ID.String(),
+			map[string]interface{}{"grant_id": grant.ID.String(), "user_id": s.user.ID.String()},
+		).Return(nil)
+
+		s.NoError(s.service.Revoke(s.ctx, s.credentials, accessToken.AccessToken))
+	})
+
+	s.Run("Refresh token of another client", func() {
+		s.expectAuthentication()
+		s.grantRepositoryMock.EXPECT().FindByRefreshTokenHash(
+			s.ctx, hashOAuthToken("refresh-token"),
+		).Return(otherGrant, nil)
+
+		s.NoError(s.service.Revoke(s.ctx, s.credentials, "refresh-token"))
+	})
+
+	s.Run("Missing token", func() {
+		s.expectAuthentication()
+
+		s.Equal(
+			entities.NewOAuthError(entities.OAuthErrorInvalidRequest, "token is required"),
+			s.service.Revoke(s.ctx, s.credentials, ""),
+		)
+	})
+}
+
+func (s *oauthServiceTestSuite) TestIntrospect() {
+	grant := s.grant(s.user.ID.String())
+	revoked := s.grant(s.user.ID.String())
+	revoked.RevokedAt = sql.NullTime{Time: s.now, Valid: true}
+	clientGrant := &models.OAuthGrant{
+		ID: uuid.New(), ClientID: s.client.ID.String(), Scopes: models.StringList{"age_tokens"},
+	}
+	grant.CreatedAt = s.now.Add(-time.Hour)
+	signedOut := *s.user
+	signedOut.SessionsRevokedAt = sql.NullTime{Time: s.now.Add(-time.Minute), Valid: true}
+	sign := func(grant *models.OAuthGrant) string {
+		token, err := s.service.accessToken(grant, grant.Scopes)
+		s.Require().NoError(err)
+
+		return token.AccessToken
+	}
+
+	tests := []struct {
+		description string
+		token       string
+		grant       *models.OAuthGrant
+		findUser    bool
+		user        *models.User
+		expected    *entities.OAuthTokenIntrospection
+	}{
+		{
+			description: "Access token",
+			token:       sign(grant),
+			grant:       grant,
+			findUser:    true,
+			expected: &entities.OAuthTokenIntrospection{
+				Active:    true,
+				Scope:     "profile email",
+				ClientID:  s.client.ID.String(),
+				TokenType: "Bearer",
+				ExpiresAt: s.now.Add(time.Hour).Unix(),
+				IssuedAt:  s.now.Unix(),
+				Subject:   s.user.ID.String(),
+				Issuer:    s.service.issuer,
+			},
+		},
+		{
+			description: "Access token of the client itself",
+			token:       sign(clientGrant),
+			grant:       clientGrant,
+			expected: &entities.OAuthTokenIntrospection{
+				Active:    true,
+				Scope:     "age_tokens",
+				ClientID:  s.client.ID.String(),
+				TokenType: "Bearer",
+				ExpiresAt: s.now.Add(time.Hour).Unix(),
+				IssuedAt:  s.now.Unix(),
+				Subject:   s.client.ID.String(),
+				Issuer:    s.service.issuer,
+			},
+		},
+		{
+			description: "Refresh token",
+			token:       "refresh-token",
+			grant:       grant,
+			findUser:    true,
+			expected: &entities.OAuthTokenIntrospection{
+				Active:    true,
+				Scope:     "profile email",
+				ClientID:  s.client.ID.String(),
+				ExpiresAt: s.now.Add(time.Hour).Unix(),
+				Subject:   s.user.ID.String(),
+				Issuer:    s.service.issuer,
+			},
+		},
+		{
+			description: "Access token issued after the sessions were revoked",
+			token:       sign(grant),
+			grant:       grant,
+			findUser:    true,
+			user:        &signedOut,
+			expected: &entities.OAuthTokenIntrospection{
+				Active:    true,
+				Scope:     "profile email",
+				ClientID:  s.client.ID.String(),
+				TokenType: "Bearer",
+				ExpiresAt: s.now.Add(time.Hour).Unix(),
+				IssuedAt:  s.now.Unix(),
+				Subject:   s.user.ID.String(),
+				Issuer:    s.service.issuer,
+			},
+		},
+		{
+			description: "Refresh token of a grant given before the sessions were revoked",
+			token:       "refresh-token",
+			grant:       grant,
+			findUser:    true,
+			user:        &signedOut,
+			expected:    &entities.OAuthTokenIntrospection{},
+		},
+		{
+			description: "Revoked grant",
+			token:       sign(revoked),
+			grant:       revoked,
+			expected:    &entities.OAuthTokenIntrospection{},
+		},
+		{
+			description: "Unknown token",
+			token:       "unknown-token",
+			expected:    &entities.OAuthTokenIntrospection{},
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			s.expectAuthentication()
+			if test.token == "refresh-token" || test.token == "unknown-token" {
+				s.grantRepositoryMock.EXPECT().FindByRefreshTokenHash(
+					s.ctx, hashOAuthToken(test.token),
+				).Return(test.grant, nil)
+			} else {
+				s.grantRepositoryMock.EXPECT().FindById(
+					s.ctx, test.grant.ID.String(),
+				).Return(test.grant, nil)
+			}
+			if test.findUser {
+				user := test.user
+				if user == nil {
+					user = s.user
+				}
+				s.userRepositoryMock.EXPECT().FindById(s.ctx, s.user.ID.String()).Return(user, nil)
+			}
+
+			introspection, err := s.service.Introspect(s.ctx, s.credentials, test.token)
+
+			s.NoError(err)
+			s.Equal(test.expected, introspection)
+		})
+	}
+}
+
+func (s *oauthServiceTestSuite) TestAuthenticateAccessToken() {
+	grant := s.grant(s.user.ID.String())
+	revoked := s.grant(s.user.ID.String())
+	revoked.RevokedAt = sql.NullTime{Time: s.now, Valid: true}
+	clientGrant := &models.OAuthGrant{
+		ID: uuid.New(), ClientID: s.client.ID.String(), Scopes: models.StringList{"age_tokens"},
+	}
+	accessToken, err := s.service.accessToken(grant, models.StringList{"profile"})
+	s.Require().NoError(err)
+	revokedAccessToken, err := s.service.accessToken(revoked, revoked.Scopes)
+	s.Require().NoError(err)
+	clientAccessToken, err := s.service.accessToken(clientGrant, clientGrant.Scopes)
+	s.Require().NoError(err)
+	userAccessToken, err := signAccessToken(
+		accessTokenClaims(s.user.ID.String(), s.now, s.now.Add(time.Hour).Unix()),
+	)
+	s.Require().NoError(err)
+
+	signedOutBefore := *s.user
+	signedOutBefore.SessionsRevokedAt = sql.NullTime{Time: s.now, Valid: true}
+	signedOutAfter := *s.user
+	signedOutAfter.SessionsRevokedAt = sql.NullTime{Time: s.now.Add(time.Second), Valid: true}
+
+	tests := []struct {
+		description   string
+		accessToken   string
+		grant         *models.OAuthGrant
+		findsUser     bool
+		user          *models.User
+		expectedScope models.StringList
+	}{
+		{
+			description:   "Active",
+			accessToken:   accessToken.AccessToken,
+			grant:         grant,
+			findsUser:     true,
+			user:          s.user,
+			expectedScope: models.StringList{"profile"},
+		},
+		{
+			description:   "Sessions revoked before it was issued",
+			accessToken:   accessToken.AccessToken,
+			grant:         grant,
+			findsUser:     true,
+			user:          &signedOutBefore,
+			expectedScope: models.StringList{"profile"},
+		},
+		{
+			description:   "Issued to the client itself",
+			accessToken:   clientAccessToken.AccessToken,
+			grant:         clientGrant,
+			expectedScope: models.StringList{"age_tokens"},
+		},
+		{
+			description: "Sessions revoked since",
+			accessToken: accessToken.AccessToken,
+			grant:       grant,
+			findsUser:   true,
+			user:        &signedOutAfter,
+		},
+		{
+			description: "Deleted user",
+			accessToken: accessToken.AccessToken,
+			grant:       grant,
+			findsUser:   true,
+		},
+		{
+			description: "Revoked grant",
+			accessToken: revokedAccessToken.AccessToken,
+			grant:       revoked,
+		},
+		{description: "Access token of the user", accessToken: userAccessToken},
+		{description: "Invalid token", accessToken: "invalid-token"},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			if test.grant != nil {
+				s.grantRepositoryMock.EXPECT().FindById(
+					s.ctx, test.grant.ID.String(),
+				).Return(test.grant, nil)
+			}
+			if test.findsUser {
+				s.userRepositoryMock.EXPECT().FindById(
+					s.ctx, s.user.ID.String(),
+				).Return(test.user, nil)
+			}
+
+			authenticated, scopes, err := s.service.AuthenticateAccessToken(s.ctx, test.accessToken)
+
+			if test.expectedScope != nil {
+				s.NoError(err)
+				s.Equal(test.grant, authenticated)
+				s.Equal(test.expectedScope, scopes)
+			} else {
+				s.Nil(authenticated)
+				s.Nil(scopes)
+				s.Equal(entities.NewInvalidTokenError(), err)
+			}
+		})
+	}
+}
+
+func (s *oauthServiceTestSuite) TestOAuthErasureHook() {
+	hook := NewOAuthErasureHook(
+		s.grantRepositoryMock, s.codeRepositoryMock, s.consentRepositoryMock,
+	)
+	s.Equal("oauth", hook.Name())
+
+	gomock.InOrder(
+		s.codeRepositoryMock.EXPECT().DeleteByUserId(s.ctx, s.user.ID.String()).Return(nil),
+		s.grantRepositoryMock.EXPECT().DeleteByUserId(s.ctx, s.user.ID.String()).Return(nil),
+		s.consentRepositoryMock.EXPECT().DeleteByUserId(s.ctx, s.user.ID.String()).Return(nil),
+	)
+
+	s.NoError(hook.Erase(s.ctx, s.user))
+}
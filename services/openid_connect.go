@@ -0,0 +1,197 @@
+package services
+
+import (
+	"context"
+	"net/url"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"verifymy-golang-test/entities"
+	"verifymy-golang-test/models"
+)
+
+const (
+	// IDTokenType is the typ header of OpenID Connect ID tokens
+	IDTokenType = "JWT"
+
+	openIDSubjectType = "public"
+)
+
+// Configuration is the OpenID Connect discovery document
+func (s *oauthService) Configuration() entities.OpenIDConfiguration {
+	return entities.OpenIDConfiguration{
+		Issuer:                s.issuer,
+		AuthorizationEndpoint: s.authorizationURL,
+		TokenEndpoint:         s.issuer + "/oauth/token",
+		UserInfoEndpoint:      s.issuer + "/userinfo",
+		JWKSURI:               s.issuer + "/.well-known/jwks.json",
+		RevocationEndpoint:    s.issuer + "/oauth/revoke",
+		IntrospectionEndpoint: s.issuer + "/oauth/introspect",
+		EndSessionEndpoint:    s.issuer + "/oauth/logout",
+		ScopesSupported:       models.ClientScopes,
+		ResponseTypesSupported: []string{
+			oauthResponseTypeCode,
+		},
+		GrantTypesSupported: []string{
+			OAuthGrantTypeAuthorizationCode,
+			OAuthGrantTypeRefreshToken,
+			OAuthGrantTypeClientCredentials,
+		},
+		SubjectTypesSupported: []string{openIDSubjectType},
+		IDTokenSigningAlgValuesSupported: []string{
+			jwt.SigningMethodRS256.Alg(),
+		},
+		TokenEndpointAuthMethodsSupported: []string{
+			"client_secret_basic", "client_secret_post",
+		},
+		CodeChallengeMethodsSupported: []string{oauthCodeChallengeMethod},
+		ClaimsSupported: []string{
+			"iss", "sub", "aud", "exp", "iat", "nonce", "name", "birthdate", "email",
+		},
+	}
+}
+
+// UserInfo returns the claims about the user an access token was granted
+// the scopes of. The token must be granted the openid scope.
+func (s *oauthService) UserInfo(
+	ctx context.Context, accessToken string,
+) (*entities.OpenIDUserInfo, error) {
+	invalidToken := entities.NewOAuthError(
+		entities.OAuthErrorInvalidToken, "access token is invalid or expired",
+	)
+
+	_, user, scopes, err := s.authenticateAccessToken(ctx, accessToken)
+	if _, ok := err.(*entities.InvalidTokenError); ok {
+		return nil, invalidToken
+	} else if err != nil {
+		return nil, err
+	} else if !scopes.Includes(models.ClientScopeOpenID) {
+		return nil, entities.NewOAuthError(
+			entities.OAuthErrorInsufficientScope, "access token must be granted the openid scope",
+		)
+	} else if user == nil {
+		return nil, invalidToken
+	}
+
+	return openIDUserInfo(user, scopes), nil
+}
+
+// Logout signs the user of an ID token out of the client it was issued to,
+// revoking what they granted it, and returns where to send them back to,
+// if anywhere. Expired ID tokens are accepted, as clients usually hold on
+// to them past their expiry.
+func (s *oauthService) Logout(
+	ctx context.Context, request entities.OpenIDLogoutRequest,
+) (string, error) {
+	if request.IDTokenHint == "" {
+		return "", entities.NewInvalidParameterError(
+			"id_token_hint", "id_token_hint is required",
+		)
+	}
+
+	invalidHint := entities.NewInvalidParameterError(
+		"id_token_hint", "id_token_hint must be an ID token issued by us",
+	)
+	claims := jwt.MapClaims{}
+	if _, err := s.signingKeys.Parse(
+		request.IDTokenHint, IDTokenType, claims, jwt.WithoutClaimsValidation(),
+	); err != nil {
+		return "", invalidHint
+	}
+
+	issuer, _ := claims.GetIssuer()
+	subject, _ := claims.GetSubject()
+	audience, _ := claims.GetAudience()
+	if issuer != s.issuer || subject == "" || len(audience) != 1 {
+		return "", invalidHint
+	} else if request.ClientID != "" && request.ClientID != audience[0] {
+		return "", entities.NewInvalidParameterError(
+			"client_id", "client_id must be the audience of id_token_hint",
+		)
+	}
+
+	client, err := s.clientRepository.FindById(ctx, audience[0])
+	if err != nil {
+		return "", err
+	} else if client == nil {
+		return "", invalidHint
+	} else if request.PostLogoutRedirectURI != "" &&
+		!client.RedirectURIs.Includes(request.PostLogoutRedirectURI) {
+		return "", entities.NewInvalidParameterError(
+			"post_logout_redirect_uri",
+			"post_logout_redirect_uri must be one of the redirect URIs of the client",
+		)
+	}
+
+	if err := s.oauthGrantRepository.RevokeByUserIdAndClientId(
+		ctx, subject, client.ID.String(), s.now().UTC(),
+	); err != nil {
+		return "", err
+	}
+
+	if err := s.auditService.Record(
+		ctx,
+		AuditActionOAuthLoggedOut,
+		auditTargetClient,
+		client.ID.String(),
+		map[string]interface{}{"user_id": subject},
+	); err != nil {
+		return "", err
+	}
+
+	if request.PostLogoutRedirectURI == "" {
+		return "", nil
+	}
+
+	return oauthRedirectURI(request.PostLogoutRedirectURI, url.Values{
+		"state": {request.State},
+	}), nil
+}
+
+// idToken signs an ID token of user for the client, holding the claims
+// scopes grant
+func (s *oauthService) idToken(
+	user *models.User, clientId string, scopes models.StringList, nonce string,
+) (string, error) {
+	now := s.now().UTC()
+	info := openIDUserInfo(user, scopes)
+
+	claims := jwt.MapClaims{
+		"iss": s.issuer,
+		"sub": info.Subject,
+		"aud": clientId,
+		"iat": now.Unix(),
+		"exp": now.Add(s.accessTokenLifetime).Unix(),
+	}
+	optional := map[string]string{
+		"nonce":     nonce,
+		"name":      info.Name,
+		"birthdate": info.Birthdate,
+		"email":     info.Email,
+	}
+	for claim, value := range optional {
+		if value != "" {
+			claims[claim] = value
+		}
+	}
+
+	return s.signingKeys.Sign(claims, IDTokenType)
+}
+
+// openIDUserInfo holds the claims about user scopes grant: profile grants
+// name and birthdate, and email the e-mail address
+func openIDUserInfo(user *models.User, scopes models.StringList) *entities.OpenIDUserInfo {
+	info := &entities.OpenIDUserInfo{Subject: user.ID.String()}
+	if scopes.Includes(models.ClientScopeProfile) {
+		info.Name = user.Name
+		if dateOfBirth := time.Time(user.DateOfBirth); !dateOfBirth.IsZero() {
+			info.Birthdate = dateOfBirth.Format(models.DateFormat)
+		}
+	}
+	if scopes.Includes(models.ClientScopeEmail) {
+		info.Email = user.Email
+	}
+
+	return info
+}
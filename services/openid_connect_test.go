@@ -0,0 +1,346 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+
+	"verifymy-golang-test/entities"
+	"verifymy-golang-test/models"
+)
+
+func (s *oauthServiceTestSuite) parseIDToken(idToken string) jwt.MapClaims {
+	claims := jwt.MapClaims{}
+	_, err := s.signingKeys.Parse(idToken, IDTokenType, claims)
+	s.Require().NoError(err)
+
+	return claims
+}
+
+func (s *oauthServiceTestSuite) TestConfiguration() {
+	configuration := s.service.Configuration()
+
+	s.Equal("http://localhost:8080", configuration.Issuer)
+	s.Equal("http://localhost:8080/oauth/authorize", configuration.AuthorizationEndpoint)
+	s.Equal("http://localhost:8080/oauth/token", configuration.TokenEndpoint)
+	s.Equal("http://localhost:8080/userinfo", configuration.UserInfoEndpoint)
+	s.Equal("http://localhost:8080/.well-known/jwks.json", configuration.JWKSURI)
+	s.Equal("http://localhost:8080/oauth/logout", configuration.EndSessionEndpoint)
+	s.Contains(configuration.ScopesSupported, "openid")
+	s.Equal([]string{"code"}, configuration.ResponseTypesSupported)
+	s.Equal([]string{"RS256"}, configuration.IDTokenSigningAlgValuesSupported)
+	s.Equal([]string{"S256"}, configuration.CodeChallengeMethodsSupported)
+}
+
+func (s *oauthServiceTestSuite) TestTokenAuthorizationCodeIDToken() {
+	s.user.Name = "John Doe"
+	s.user.DateOfBirth = models.Date(time.Date(1990, 5, 17, 0, 0, 0, 0, time.UTC))
+	code := &models.OAuthAuthorizationCode{
+		CodeHash:    hashOAuthToken("the-code"),
+		ClientID:    s.client.ID.String(),
+		UserID:      s.user.ID.String(),
+		RedirectURI: testRedirectURI,
+		Scopes: models.StringList{
+			models.ClientScopeOpenID, models.ClientScopeProfile, models.ClientScopeEmail,
+		},
+		CodeChallenge: testCodeChallenge,
+		Nonce:         "n-0S6_WzA2Mj",
+		ExpiresAt:     s.now.Add(time.Minute),
+	}
+
+	s.expectAuthentication()
+	s.codeRepositoryMock.EXPECT().FindByCodeHash(s.ctx, code.CodeHash).Return(code, nil)
+	s.userRepositoryMock.EXPECT().FindById(s.ctx, s.user.ID.String()).Return(s.user, nil)
+	s.codeRepositoryMock.EXPECT().DeleteByCodeHash(s.ctx, code.CodeHash).Return(true, nil)
+	s.grantRepositoryMock.EXPECT().Create(s.ctx, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, grant models.OAuthGrant) (*models.OAuthGrant, error) {
+			grant.ID = uuid.New()
+			return &grant, nil
+		},
+	)
+
+	token, err := s.service.Token(s.ctx, s.credentials, entities.OAuthTokenRequest{
+		GrantType:    OAuthGrantTypeAuthorizationCode,
+		Code:         "the-code",
+		RedirectURI:  testRedirectURI,
+		CodeVerifier: testCodeVerifier,
+	})
+	s.Require().NoError(err)
+
+	claims := s.parseIDToken(token.IDToken)
+	s.Equal("http://localhost:8080", claims["iss"])
+	s.Equal(s.user.ID.String(), claims["sub"])
+	s.Equal(s.client.ID.String(), claims["aud"])
+	s.Equal(float64(s.now.Add(time.Hour).Unix()), claims["exp"])
+	s.Equal("n-0S6_WzA2Mj", claims["nonce"])
+	s.Equal("John Doe", claims["name"])
+	s.Equal("1990-05-17", claims["birthdate"])
+	s.Equal("john@doe.com", claims["email"])
+}
+
+func (s *oauthServiceTestSuite) TestTokenRefreshIDToken() {
+	s.user.Name = "John Doe"
+	grant := s.grant(s.user.ID.String())
+	grant.Scopes = models.StringList{models.ClientScopeOpenID, models.ClientScopeProfile}
+
+	s.expectAuthentication()
+	s.grantRepositoryMock.EXPECT().FindByRefreshTokenHash(
+		s.ctx, hashOAuthToken("refresh-token"),
+	).Return(grant, nil)
+	s.userRepositoryMock.EXPECT().FindById(s.ctx, s.user.ID.String()).Return(s.user, nil)
+	s.grantRepositoryMock.EXPECT().UpdateRefreshToken(
+		s.ctx, grant.ID.String(), grant.RefreshTokenHash, gomock.Any(), gomock.Any(),
+	).Return(true, nil)
+
+	token, err := s.service.Token(s.ctx, s.credentials, entities.OAuthTokenRequest{
+		GrantType: OAuthGrantTypeRefreshToken, RefreshToken: "refresh-token", Scope: "openid",
+	})
+	s.Require().NoError(err)
+
+	claims := s.parseIDToken(token.IDToken)
+	s.Equal(s.user.ID.String(), claims["sub"])
+	s.NotContains(claims, "nonce")
+	s.NotContains(claims, "name")
+	s.NotContains(claims, "email")
+}
+
+func (s *oauthServiceTestSuite) TestUserInfo() {
+	s.user.Name = "John Doe"
+	s.user.DateOfBirth = models.Date(time.Date(1990, 5, 17, 0, 0, 0, 0, time.UTC))
+	grant := s.grant(s.user.ID.String())
+	revoked := s.grant(s.user.ID.String())
+	revoked.RevokedAt = sql.NullTime{Time: s.now, Valid: true}
+	accessToken := func(grant *models.OAuthGrant, scopes ...string) string {
+		token, err := s.service.accessToken(grant, scopes)
+		s.Require().NoError(err)
+
+		return token.AccessToken
+	}
+	invalidToken := entities.NewOAuthError(
+		entities.OAuthErrorInvalidToken, "access token is invalid or expired",
+	)
+
+	tests := []struct {
+		description      string
+		accessToken      string
+		grant            *models.OAuthGrant
+		findsUser        bool
+		user             *models.User
+		findUserError    error
+		expectedUserInfo *entities.OpenIDUserInfo
+		expectedError    error
+	}{
+		{
+			description: "Profile scope",
+			accessToken: accessToken(grant, "openid", "profile"),
+			grant:       grant,
+			findsUser:   true,
+			user:        s.user,
+			expectedUserInfo: &entities.OpenIDUserInfo{
+				Subject: s.user.ID.String(), Name: "John Doe", Birthdate: "1990-05-17",
+			},
+		},
+		{
+			description: "Email scope",
+			accessToken: accessToken(grant, "openid", "email"),
+			grant:       grant,
+			findsUser:   true,
+			user:        s.user,
+			expectedUserInfo: &entities.OpenIDUserInfo{
+				Subject: s.user.ID.String(), Email: "john@doe.com",
+			},
+		},
+		{
+			description: "Without openid scope",
+			accessToken: accessToken(grant, "profile"),
+			grant:       grant,
+			findsUser:   true,
+			user:        s.user,
+			expectedError: entities.NewOAuthError(
+				entities.OAuthErrorInsufficientScope, "access token must be granted the openid scope",
+			),
+		},
+		{
+			description:   "Invalid token",
+			accessToken:   "invalid-token",
+			expectedError: invalidToken,
+		},
+		{
+			description:   "Revoked grant",
+			accessToken:   accessToken(revoked, "openid"),
+			grant:         revoked,
+			expectedError: invalidToken,
+		},
+		{
+			description:   "Deleted user",
+			accessToken:   accessToken(grant, "openid"),
+			grant:         grant,
+			findsUser:     true,
+			expectedError: invalidToken,
+		},
+		{
+			description:   "Unexpected error",
+			accessToken:   accessToken(grant, "openid"),
+			grant:         grant,
+			findsUser:     true,
+			findUserError: errors.New("database unavailable"),
+			expectedError: errors.New("database unavailable"),
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			if test.grant != nil {
+				s.grantRepositoryMock.EXPECT().FindById(
+					s.ctx, test.grant.ID.String(),
+				).Return(test.grant, nil)
+			}
+			if test.findsUser {
+				s.userRepositoryMock.EXPECT().FindById(
+					s.ctx, s.user.ID.String(),
+				).Return(test.user, test.findUserError)
+			}
+
+			userInfo, err := s.service.UserInfo(s.ctx, test.accessToken)
+
+			s.Equal(test.expectedUserInfo, userInfo)
+			s.Equal(test.expectedError, err)
+		})
+	}
+}
+
+func (s *oauthServiceTestSuite) TestLogout() {
+	claims := jwt.MapClaims{
+		"iss": "http://localhost:8080",
+		"sub": s.user.ID.String(),
+		"aud": s.client.ID.String(),
+		"exp": s.now.Add(-time.Hour).Unix(),
+	}
+	idToken := func(changes jwt.MapClaims, tokenType string) string {
+		changed := jwt.MapClaims{}
+		for claim, value := range claims {
+			changed[claim] = value
+		}
+		for claim, value := range changes {
+			changed[claim] = value
+		}
+
+		token, err := s.signingKeys.Sign(changed, tokenType)
+		s.Require().NoError(err)
+
+		return token
+	}
+	invalidHint := entities.NewInvalidParameterError(
+		"id_token_hint", "id_token_hint must be an ID token issued by us",
+	)
+
+	tests := []struct {
+		description         string
+		request             entities.OpenIDLogoutRequest
+		findsClient         bool
+		client              *models.Client
+		expectedRedirectURI string
+		expectedError       error
+	}{
+		{
+			description: "Redirected back",
+			request: entities.OpenIDLogoutRequest{
+				IDTokenHint:           idToken(nil, IDTokenType),
+				ClientID:              s.client.ID.String(),
+				PostLogoutRedirectURI: testRedirectURI,
+				State:                 "xyz",
+			},
+			findsClient:         true,
+			client:              s.client,
+			expectedRedirectURI: testRedirectURI + "?state=xyz",
+		},
+		{
+			description: "Not redirected",
+			request: entities.OpenIDLogoutRequest{
+				IDTokenHint: idToken(nil, IDTokenType),
+			},
+			findsClient: true,
+			client:      s.client,
+		},
+		{
+			description: "Missing ID token",
+			request:     entities.OpenIDLogoutRequest{},
+			expectedError: entities.NewInvalidParameterError(
+				"id_token_hint", "id_token_hint is required",
+			),
+		},
+		{
+			description: "Age token",
+			request: entities.OpenIDLogoutRequest{
+				IDTokenHint: idToken(nil, AgeTokenType),
+			},
+			expectedError: invalidHint,
+		},
+		{
+			description: "Other issuer",
+			request: entities.OpenIDLogoutRequest{
+				IDTokenHint: idToken(jwt.MapClaims{"iss": "https://evil.example.com"}, IDTokenType),
+			},
+			expectedError: invalidHint,
+		},
+		{
+			description: "Other client",
+			request: entities.OpenIDLogoutRequest{
+				IDTokenHint: idToken(nil, IDTokenType), ClientID: uuid.NewString(),
+			},
+			expectedError: entities.NewInvalidParameterError(
+				"client_id", "client_id must be the audience of id_token_hint",
+			),
+		},
+		{
+			description: "Deleted client",
+			request: entities.OpenIDLogoutRequest{
+				IDTokenHint: idToken(nil, IDTokenType),
+			},
+			findsClient:   true,
+			expectedError: invalidHint,
+		},
+		{
+			description: "Unregistered redirect URI",
+			request: entities.OpenIDLogoutRequest{
+				IDTokenHint:           idToken(nil, IDTokenType),
+				PostLogoutRedirectURI: "https://evil.example.com/callback",
+			},
+			findsClient: true,
+			client:      s.client,
+			expectedError: entities.NewInvalidParameterError(
+				"post_logout_redirect_uri",
+				"post_logout_redirect_uri must be one of the redirect URIs of the client",
+			),
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			if test.findsClient {
+				s.clientRepositoryMock.EXPECT().FindById(
+					s.ctx, s.client.ID.String(),
+				).Return(test.client, nil)
+			}
+			if test.expectedError == nil {
+				s.grantRepositoryMock.EXPECT().RevokeByUserIdAndClientId(
+					s.ctx, s.user.ID.String(), s.client.ID.String(), s.now,
+				).Return(nil)
+				s.auditServiceMock.EXPECT().Record(
+					s.ctx, AuditActionOAuthLoggedOut, auditTargetClient, s.client.ID.String(),
+					map[string]interface{}{"user_id": s.user.ID.String()},
+				).Return(nil)
+			}
+
+			redirectURI, err := s.service.Logout(s.ctx, test.request)
+
+			s.Equal(test.expectedRedirectURI, redirectURI)
+			s.Equal(test.expectedError, err)
+		})
+	}
+}
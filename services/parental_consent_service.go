@@ -0,0 +1,215 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"verifymy-golang-test/common"
+	"verifymy-golang-test/entities"
+	"verifymy-golang-test/models"
+	"verifymy-golang-test/providers"
+	"verifymy-golang-test/repositories"
+)
+
+const defaultParentalConsentAge = 13
+
+// ParentalConsentService handles the consent guardians give to users signing
+// up under the age of consent. Those users are pending until their guardian
+// decides, and only get to see or delete their profile meanwhile.
+type ParentalConsentService interface {
+	Required(dateOfBirth models.Date) bool
+	Request(ctx context.Context, user *models.User) error
+	Resend(ctx context.Context, guardianEmail string) error
+	Decide(ctx context.Context, token string, approved bool) error
+}
+
+// NewParentalConsentService requires the consent of a guardian for users
+// under PARENTAL_CONSENT_AGE, 13 by default
+func NewParentalConsentService(
+	userRepository repositories.UserRepository,
+	eventPublisher EventPublisher,
+	auditService AuditService,
+	mailer providers.Mailer,
+) (ParentalConsentService, error) {
+	age := defaultParentalConsentAge
+	if value := os.Getenv("PARENTAL_CONSENT_AGE"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 {
+			return nil, fmt.Errorf("invalid PARENTAL_CONSENT_AGE %q", value)
+		}
+
+		age = parsed
+	}
+
+	return &parentalConsentService{
+		userRepository: userRepository,
+		eventPublisher: eventPublisher,
+		auditService:   auditService,
+		mailer:         mailer,
+		age:            age,
+		now:            time.Now,
+	}, nil
+}
+
+type parentalConsentService struct {
+	userRepository repositories.UserRepository
+	eventPublisher EventPublisher
+	auditService   AuditService
+	mailer         providers.Mailer
+	age            int
+	now            func() time.Time
+}
+
+// Required tells whether someone born on dateOfBirth is under the age of
+// consent today
+func (s *parentalConsentService) Required(dateOfBirth models.Date) bool {
+	return dateOfBirth.AgeOn(s.now().UTC()) < s.age
+}
+
+// Request emails the guardian of a pending user a link to approve or reject
+// them signing up, see Decide
+func (s *parentalConsentService) Request(ctx context.Context, user *models.User) error {
+	token, err := newParentalConsentToken(user)
+	if err != nil {
+		return err
+	}
+
+	if err := s.mailer.Send(ctx, providers.Email{
+		To:      user.GuardianEmail,
+		Subject: "Your consent is required",
+		Body: fmt.Sprintf(
+			"Hello,\n\n%s signed up with %s and named you as their guardian. "+
+				"Until you approve, their account stays restricted. "+
+				"Approve or reject it at %s\n",
+			user.Name, user.Email, parentalConsentURL(token),
+		),
+	}); err != nil {
+		return err
+	}
+
+	return s.auditService.Record(
+		ctx,
+		AuditActionParentalConsentRequested,
+		auditTargetUser,
+		user.ID.String(),
+		map[string]interface{}{"guardian_email": user.GuardianEmail},
+	)
+}
+
+// Resend requests the consent of the signed in user's guardian again, to
+// guardianEmail, which may name another guardian. Links sent before stop
+// working.
+func (s *parentalConsentService) Resend(ctx context.Context, guardianEmail string) error {
+	user := ctx.Value(common.AuthUser).(*models.User)
+	if user.Status != models.UserStatusPendingParentalConsent {
+		return entities.NewValidationError("parental consent is not pending")
+	}
+
+	pending := *user
+	pending.GuardianEmail = guardianEmail
+	if err := validateGuardianEmail(&pending); err != nil {
+		return err
+	}
+
+	if err := s.userRepository.UpdateColumnsByUserId(
+		ctx,
+		user.ID.String(),
+		map[string]interface{}{"guardian_email": pending.GuardianEmail},
+		user.Version,
+	); err != nil {
+		return err
+	}
+	pending.Version++
+
+	return s.Request(ctx, &pending)
+}
+
+// Decide applies the decision of a guardian on the request token was sent
+// for. Approving lifts the restrictions of the user, while rejecting deletes
+// them, leaving their data to the retention policy.
+func (s *parentalConsentService) Decide(
+	ctx context.Context, token string, approved bool,
+) error {
+	userId, guardianEmail, version, err := parseParentalConsentToken(token)
+	if err != nil {
+		return err
+	}
+
+	user, err := s.userRepository.FindById(ctx, userId)
+	if err != nil {
+		return err
+	} else if user == nil ||
+		user.Version != version ||
+		user.Status != models.UserStatusPendingParentalConsent ||
+		user.GuardianEmail != guardianEmail {
+		return entities.NewInvalidTokenError()
+	}
+
+	action := AuditActionParentalConsentApproved
+	if approved {
+		err = s.userRepository.UpdateColumnsByUserId(
+			ctx, userId, map[string]interface{}{"status": models.UserStatusActive}, version,
+		)
+	} else {
+		action = AuditActionParentalConsentRejected
+		err = s.reject(ctx, userId, version)
+	}
+	if _, ok := err.(*entities.PreconditionFailedError); ok {
+		return entities.NewInvalidTokenError()
+	} else if err != nil {
+		return err
+	}
+
+	return s.auditService.Record(
+		ctx,
+		action,
+		auditTargetUser,
+		userId,
+		map[string]interface{}{"guardian_email": guardianEmail},
+	)
+}
+
+// reject deletes a user whose guardian rejected them signing up, marking
+// them as rejected to tell them apart from users deleted otherwise
+func (s *parentalConsentService) reject(ctx context.Context, userId string, version int64) error {
+	return s.eventPublisher.Publish(
+		ctx, func(ctx context.Context) ([]entities.DomainEvent, error) {
+			if err := s.userRepository.UpdateColumnsByUserId(
+				ctx,
+				userId,
+				map[string]interface{}{"status": models.UserStatusParentalConsentRejected},
+				version,
+			); err != nil {
+				return nil, err
+			}
+
+			if _, err := s.userRepository.DeleteById(ctx, userId); err != nil {
+				return nil, err
+			}
+
+			return []entities.DomainEvent{entities.UserDeleted{UserID: userId}}, nil
+		},
+	)
+}
+
+// validateGuardianEmail checks that a user under the age of consent named a
+// guardian other than themselves
+func validateGuardianEmail(user *models.User) error {
+	user.GuardianEmail = strings.TrimSpace(user.GuardianEmail)
+	if user.GuardianEmail == "" {
+		return entities.NewValidationError(
+			"guardian_email is required for users under the age of consent",
+		)
+	} else if _, err := mail.ParseAddress(user.GuardianEmail); err != nil {
+		return entities.NewValidationError("guardian_email must be a valid email address")
+	} else if strings.EqualFold(user.GuardianEmail, user.Email) {
+		return entities.NewValidationError("guardian_email must not be the e-mail of the user")
+	}
+
+	return nil
+}
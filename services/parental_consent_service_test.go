@@ -0,0 +1,332 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	"verifymy-golang-test/common"
+	"verifymy-golang-test/entities"
+	mock_providers "verifymy-golang-test/mocks/providers"
+	mock_repositories "verifymy-golang-test/mocks/repositories"
+	mock_services "verifymy-golang-test/mocks/services"
+	"verifymy-golang-test/models"
+	"verifymy-golang-test/providers"
+)
+
+type parentalConsentServiceTestSuite struct {
+	suite.Suite
+	ctrl                      *gomock.Controller
+	ctx                       context.Context
+	userRepositoryMock        *mock_repositories.MockUserRepository
+	outboxEventRepositoryMock *mock_repositories.MockOutboxEventRepository
+	auditServiceMock          *mock_services.MockAuditService
+	mailerMock                *mock_providers.MockMailer
+	service                   ParentalConsentService
+}
+
+func TestParentalConsentServiceTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(parentalConsentServiceTestSuite))
+}
+
+func (s *parentalConsentServiceTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.ctx = context.Background()
+	s.userRepositoryMock = mock_repositories.NewMockUserRepository(s.ctrl)
+	s.outboxEventRepositoryMock = mock_repositories.NewMockOutboxEventRepository(s.ctrl)
+	s.auditServiceMock = mock_services.NewMockAuditService(s.ctrl)
+	s.mailerMock = mock_providers.NewMockMailer(s.ctrl)
+
+	transactorMock := mock_repositories.NewMockTransactor(s.ctrl)
+	transactorMock.EXPECT().WithinTransaction(
+		gomock.Any(), gomock.Any(),
+	).DoAndReturn(runWithinTransaction).AnyTimes()
+
+	service, err := NewParentalConsentService(
+		s.userRepositoryMock,
+		NewEventPublisher(transactorMock, s.outboxEventRepositoryMock),
+		s.auditServiceMock,
+		s.mailerMock,
+	)
+	s.Require().NoError(err)
+	s.service = service
+}
+
+// pendingUser is a user whose guardian has yet to consent
+func pendingUser() *models.User {
+	return &models.User{
+		ID:            uuid.New(),
+		Name:          "Miles Morales",
+		Email:         "miles.morales@nyork.co",
+		Status:        models.UserStatusPendingParentalConsent,
+		GuardianEmail: "rio.morales@nyork.co",
+		Version:       3,
+	}
+}
+
+func (s *parentalConsentServiceTestSuite) TestRequired() {
+	today := time.Date(2023, time.March, 1, 12, 0, 0, 0, time.UTC)
+	s.service.(*parentalConsentService).now = func() time.Time { return today }
+
+	tests := []struct {
+		description string
+		dateOfBirth time.Time
+		required    bool
+	}{
+		{description: "Under the age of consent", dateOfBirth: today.AddDate(-10, 0, 0), required: true},
+		{description: "Turning 13 tomorrow", dateOfBirth: today.AddDate(-13, 0, 1), required: true},
+		{description: "Turning 13 today", dateOfBirth: today.AddDate(-13, 0, 0)},
+		{description: "Adult", dateOfBirth: today.AddDate(-30, 0, 0)},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			s.Equal(test.required, s.service.Required(models.Date(test.dateOfBirth)))
+		})
+	}
+}
+
+func (s *parentalConsentServiceTestSuite) TestRequest() {
+	user := pendingUser()
+
+	var sent providers.Email
+	s.mailerMock.EXPECT().Send(s.ctx, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, email providers.Email) error {
+			sent = email
+			return nil
+		},
+	)
+	s.auditServiceMock.EXPECT().Record(
+		s.ctx, AuditActionParentalConsentRequested, "user", user.ID.String(),
+		map[string]interface{}{"guardian_email": "rio.morales@nyork.co"},
+	).Return(nil)
+
+	err := s.service.Request(s.ctx, user)
+
+	s.NoError(err)
+	s.Equal("rio.morales@nyork.co", sent.To)
+	s.Contains(sent.Body, "miles.morales@nyork.co")
+	token := sent.Body[strings.Index(sent.Body, "?token=")+7 : len(sent.Body)-1]
+	userId, guardianEmail, version, err := parseParentalConsentToken(token)
+	s.NoError(err)
+	s.Equal(user.ID.String(), userId)
+	s.Equal("rio.morales@nyork.co", guardianEmail)
+	s.Equal(int64(3), version)
+}
+
+func (s *parentalConsentServiceTestSuite) TestResend() {
+	tests := []struct {
+		description   string
+		status        string
+		guardianEmail string
+		updateError   error
+		expectedError error
+	}{
+		{
+			description:   "Success",
+			status:        models.UserStatusPendingParentalConsent,
+			guardianEmail: "jefferson.davis@nyork.co",
+		},
+		{
+			description:   "Not pending",
+			status:        models.UserStatusActive,
+			guardianEmail: "jefferson.davis@nyork.co",
+			expectedError: entities.NewValidationError("parental consent is not pending"),
+		},
+		{
+			description:   "Own e-mail",
+			status:        models.UserStatusPendingParentalConsent,
+			guardianEmail: "Miles.Morales@nyork.co",
+			expectedError: entities.NewValidationError(
+				"guardian_email must not be the e-mail of the user",
+			),
+		},
+		{
+			description:   "User changed meanwhile",
+			status:        models.UserStatusPendingParentalConsent,
+			guardianEmail: "jefferson.davis@nyork.co",
+			updateError:   entities.NewPreconditionFailedError(),
+			expectedError: entities.NewPreconditionFailedError(),
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			user := pendingUser()
+			user.Status = test.status
+			ctx := context.WithValue(s.ctx, common.AuthUser, user)
+
+			if test.status == models.UserStatusPendingParentalConsent &&
+				test.guardianEmail != "Miles.Morales@nyork.co" {
+				s.userRepositoryMock.EXPECT().UpdateColumnsByUserId(
+					ctx, user.ID.String(),
+					map[string]interface{}{"guardian_email": test.guardianEmail}, int64(3),
+				).Return(test.updateError)
+			}
+
+			var sent providers.Email
+			if test.expectedError == nil {
+				s.mailerMock.EXPECT().Send(ctx, gomock.Any()).DoAndReturn(
+					func(ctx context.Context, email providers.Email) error {
+						sent = email
+						return nil
+					},
+				)
+				s.auditServiceMock.EXPECT().Record(
+					ctx, AuditActionParentalConsentRequested, "user", user.ID.String(),
+					map[string]interface{}{"guardian_email": test.guardianEmail},
+				).Return(nil)
+			}
+
+			err := s.service.Resend(ctx, test.guardianEmail)
+
+			s.Equal(test.expectedError, err)
+			if test.expectedError != nil {
+				return
+			}
+
+			s.Equal(test.guardianEmail, sent.To)
+			token := sent.Body[strings.Index(sent.Body, "?token=")+7 : len(sent.Body)-1]
+			_, guardianEmail, version, err := parseParentalConsentToken(token)
+			s.NoError(err)
+			s.Equal(test.guardianEmail, guardianEmail)
+			s.Equal(int64(4), version)
+			s.Equal("rio.morales@nyork.co", user.GuardianEmail)
+		})
+	}
+}
+
+func (s *parentalConsentServiceTestSuite) TestDecide() {
+	user := pendingUser()
+	token, err := newParentalConsentToken(user)
+	s.Require().NoError(err)
+
+	otherGuardian := *user
+	otherGuardian.GuardianEmail = "jefferson.davis@nyork.co"
+	otherGuardianToken, err := newParentalConsentToken(&otherGuardian)
+	s.Require().NoError(err)
+
+	emailChangeToken, err := newEmailChangeToken(user, "spidey@nyork.co")
+	s.Require().NoError(err)
+
+	superseded := *user
+	superseded.Version = 4
+	active := *user
+	active.Status = models.UserStatusActive
+
+	tests := []struct {
+		description    string
+		token          string
+		approved       bool
+		skipFind       bool
+		foundUser      *models.User
+		updateError    error
+		expectedStatus string
+		expectedAction string
+		expectedError  error
+	}{
+		{
+			description:    "Approved",
+			token:          token,
+			approved:       true,
+			foundUser:      user,
+			expectedStatus: models.UserStatusActive,
+			expectedAction: AuditActionParentalConsentApproved,
+		},
+		{
+			description:    "Rejected",
+			token:          token,
+			foundUser:      user,
+			expectedStatus: models.UserStatusParentalConsentRejected,
+			expectedAction: AuditActionParentalConsentRejected,
+		},
+		{
+			description:   "Token for another purpose",
+			token:         emailChangeToken,
+			approved:      true,
+			skipFind:      true,
+			expectedError: entities.NewInvalidTokenError(),
+		},
+		{
+			description:   "User not found",
+			token:         token,
+			approved:      true,
+			expectedError: entities.NewInvalidTokenError(),
+		},
+		{
+			description:   "Superseded by a newer request",
+			token:         token,
+			approved:      true,
+			foundUser:     &superseded,
+			expectedError: entities.NewInvalidTokenError(),
+		},
+		{
+			description:   "Already decided",
+			token:         token,
+			approved:      true,
+			foundUser:     &active,
+			expectedError: entities.NewInvalidTokenError(),
+		},
+		{
+			description:   "Sent to another guardian",
+			token:         otherGuardianToken,
+			approved:      true,
+			foundUser:     user,
+			expectedError: entities.NewInvalidTokenError(),
+		},
+		{
+			description:    "Decided meanwhile",
+			token:          token,
+			approved:       true,
+			foundUser:      user,
+			updateError:    entities.NewPreconditionFailedError(),
+			expectedStatus: models.UserStatusActive,
+			expectedError:  entities.NewInvalidTokenError(),
+		},
+		{
+			description:    "Unexpected error",
+			token:          token,
+			foundUser:      user,
+			updateError:    errors.New("database unavailable"),
+			expectedStatus: models.UserStatusParentalConsentRejected,
+			expectedError:  errors.New("database unavailable"),
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			if !test.skipFind {
+				s.userRepositoryMock.EXPECT().FindById(s.ctx, user.ID.String()).Return(test.foundUser, nil)
+			}
+			if test.expectedStatus != "" {
+				s.userRepositoryMock.EXPECT().UpdateColumnsByUserId(
+					s.ctx, user.ID.String(),
+					map[string]interface{}{"status": test.expectedStatus}, int64(3),
+				).Return(test.updateError)
+			}
+			if test.expectedStatus == models.UserStatusParentalConsentRejected && test.updateError == nil {
+				s.userRepositoryMock.EXPECT().DeleteById(s.ctx, user.ID.String()).Return(true, nil)
+				s.outboxEventRepositoryMock.EXPECT().Create(
+					s.ctx, outboxEventsOf(entities.UserDeleted{UserID: user.ID.String()}),
+				).Return(nil)
+			}
+			if test.expectedAction != "" {
+				s.auditServiceMock.EXPECT().Record(
+					s.ctx, test.expectedAction, "user", user.ID.String(),
+					map[string]interface{}{"guardian_email": "rio.morales@nyork.co"},
+				).Return(nil)
+			}
+
+			err := s.service.Decide(s.ctx, test.token, test.approved)
+
+			s.Equal(test.expectedError, err)
+		})
+	}
+}
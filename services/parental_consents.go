@@ -0,0 +1,44 @@
+package services
+
+import (
+	"os"
+	"time"
+
+	"verifymy-golang-test/models"
+)
+
+const (
+	parentalConsentPurpose  = "parental_consent"
+	parentalConsentLifetime = time.Hour * 24 * 7
+)
+
+// newParentalConsentToken issues the token sent to the guardian of a user,
+// who can only decide once
+func newParentalConsentToken(user *models.User) (string, error) {
+	return signPurposeToken(
+		parentalConsentPurpose,
+		user,
+		parentalConsentLifetime,
+		map[string]string{"guardian_email": user.GuardianEmail},
+	)
+}
+
+func parseParentalConsentToken(token string) (string, string, int64, error) {
+	userId, version, claims, err := parsePurposeToken(
+		parentalConsentPurpose, token, "guardian_email",
+	)
+	if err != nil {
+		return "", "", 0, err
+	}
+
+	return userId, claims["guardian_email"], version, nil
+}
+
+func parentalConsentURL(token string) string {
+	baseURL := os.Getenv("PARENTAL_CONSENT_URL")
+	if baseURL == "" {
+		baseURL = "http://localhost:8080/auth/parental_consent"
+	}
+
+	return baseURL + "?token=" + token
+}
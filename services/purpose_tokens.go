@@ -0,0 +1,65 @@
+package services
+
+import (
+	"os"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"verifymy-golang-test/entities"
+	"verifymy-golang-test/models"
+)
+
+// signPurposeToken issues a token e-mailed to act on the account of a user
+// for a single purpose, carrying claims along. It is bound to the current
+// version of the user, so it can only be used once and stops working as
+// soon as the user is changed.
+func signPurposeToken(
+	purpose string, user *models.User, lifetime time.Duration, claims map[string]string,
+) (string, error) {
+	tokenClaims := jwt.MapClaims{
+		"sub":     user.ID.String(),
+		"purpose": purpose,
+		"version": user.Version,
+		"exp":     time.Now().UTC().Add(lifetime).Unix(),
+	}
+	for claim, value := range claims {
+		tokenClaims[claim] = value
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, tokenClaims)
+
+	return token.SignedString([]byte(os.Getenv("SECRET_KEY")))
+}
+
+// parsePurposeToken returns the user id and version a token issued for
+// purpose is bound to, along with the claims it must carry
+func parsePurposeToken(
+	purpose string, token string, claims ...string,
+) (string, int64, map[string]string, error) {
+	tokenClaims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(token, tokenClaims, func(token *jwt.Token) (interface{}, error) {
+		return []byte(os.Getenv("SECRET_KEY")), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	if err != nil {
+		return "", 0, nil, entities.NewInvalidTokenError()
+	}
+
+	userId, _ := tokenClaims["sub"].(string)
+	version, _ := tokenClaims["version"].(float64)
+	if tokenClaims["purpose"] != purpose || userId == "" {
+		return "", 0, nil, entities.NewInvalidTokenError()
+	}
+
+	values := make(map[string]string, len(claims))
+	for _, claim := range claims {
+		value, _ := tokenClaims[claim].(string)
+		if value == "" {
+			return "", 0, nil, entities.NewInvalidTokenError()
+		}
+
+		values[claim] = value
+	}
+
+	return userId, int64(version), values, nil
+}
@@ -0,0 +1,117 @@
+package services
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	"verifymy-golang-test/entities"
+	"verifymy-golang-test/models"
+)
+
+type purposeTokensTestSuite struct {
+	suite.Suite
+	user *models.User
+}
+
+func TestPurposeTokensTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(purposeTokensTestSuite))
+}
+
+func (s *purposeTokensTestSuite) SetupTest() {
+	s.user = &models.User{ID: uuid.New(), Version: 3}
+}
+
+func (s *purposeTokensTestSuite) TestParsePurposeToken() {
+	sign := func(method jwt.SigningMethod, claims jwt.MapClaims) string {
+		token, err := jwt.NewWithClaims(method, claims).SignedString(
+			[]byte(os.Getenv("SECRET_KEY")),
+		)
+		s.Require().NoError(err)
+
+		return token
+	}
+	valid, err := signPurposeToken(
+		"testing", s.user, time.Hour, map[string]string{"email": "spidey@nyork.co"},
+	)
+	s.Require().NoError(err)
+
+	tests := []struct {
+		description     string
+		token           string
+		claims          []string
+		expectedClaims  map[string]string
+		expectedInvalid bool
+	}{
+		{
+			description:    "Valid",
+			token:          valid,
+			claims:         []string{"email"},
+			expectedClaims: map[string]string{"email": "spidey@nyork.co"},
+		},
+		{
+			description:     "Missing claim",
+			token:           valid,
+			claims:          []string{"email", "guardian_email"},
+			expectedInvalid: true,
+		},
+		{
+			description: "Other purpose",
+			token: sign(jwt.SigningMethodHS256, jwt.MapClaims{
+				"sub": s.user.ID.String(), "purpose": "other", "version": 3,
+			}),
+			expectedInvalid: true,
+		},
+		{
+			description: "Without user",
+			token: sign(jwt.SigningMethodHS256, jwt.MapClaims{
+				"purpose": "testing", "version": 3,
+			}),
+			expectedInvalid: true,
+		},
+		{
+			description: "Expired",
+			token: sign(jwt.SigningMethodHS256, jwt.MapClaims{
+				"sub":     s.user.ID.String(),
+				"purpose": "testing",
+				"version": 3,
+				"exp":     time.Now().Add(-time.Minute).Unix(),
+			}),
+			expectedInvalid: true,
+		},
+		{
+			description: "Other signing method",
+			token: sign(jwt.SigningMethodHS512, jwt.MapClaims{
+				"sub": s.user.ID.String(), "purpose": "testing", "version": 3,
+			}),
+			expectedInvalid: true,
+		},
+		{
+			description:     "Malformed",
+			token:           "not.a.token",
+			expectedInvalid: true,
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			userId, version, claims, err := parsePurposeToken("testing", test.token, test.claims...)
+
+			if test.expectedInvalid {
+				s.Equal(entities.NewInvalidTokenError(), err)
+				s.Empty(userId)
+				s.Nil(claims)
+			} else {
+				s.NoError(err)
+				s.Equal(s.user.ID.String(), userId)
+				s.Equal(s.user.Version, version)
+				s.Equal(test.expectedClaims, claims)
+			}
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"verifymy-golang-test/common"
+	"verifymy-golang-test/entities"
+	"verifymy-golang-test/models"
+	"verifymy-golang-test/repositories"
+)
+
+const (
+	ErasureStepProfile  = "profile"
+	ErasureStepSessions = "sessions"
+)
+
+// ErasureHook erases what a module keeps about a user once the user asks to
+// be erased. Hooks run before the user is, so they still get their personal
+// data.
+type ErasureHook interface {
+	Name() string
+	Erase(ctx context.Context, user *models.User) error
+}
+
+// UserErasureService erases users on their request, or once the grace
+// period of their deletion is over, leaving only a tombstone behind.
+type UserErasureService interface {
+	Erase(ctx context.Context, user *models.User) error
+	Dispose(ctx context.Context, user *models.User, mode string) error
+}
+
+// NewUserErasureService runs the hooks listed, comma separated, in
+// USER_ERASURE_HOOKS, in that order, or every hook when it is not set.
+func NewUserErasureService(
+	userRepository repositories.UserRepository,
+	eventPublisher EventPublisher,
+	userTombstoneRepository repositories.UserTombstoneRepository,
+	auditService AuditService,
+	hooks []ErasureHook,
+) (UserErasureService, error) {
+	enabledHooks, err := enabledErasureHooks(hooks, os.Getenv("USER_ERASURE_HOOKS"))
+	if err != nil {
+		return nil, err
+	}
+
+	return &userErasureService{
+		userRepository:          userRepository,
+		eventPublisher:          eventPublisher,
+		userTombstoneRepository: userTombstoneRepository,
+		auditService:            auditService,
+		hooks:                   enabledHooks,
+	}, nil
+}
+
+// enabledErasureHooks returns the hooks named in names, comma separated, or
+// every hook when names is empty
+func enabledErasureHooks(hooks []ErasureHook, names string) ([]ErasureHook, error) {
+	if names == "" {
+		return hooks, nil
+	}
+
+	hooksByName := map[string]ErasureHook{}
+	for _, hook := range hooks {
+		hooksByName[hook.Name()] = hook
+	}
+
+	enabledHooks := []ErasureHook{}
+	for _, name := range strings.Split(names, ",") {
+		hook, ok := hooksByName[strings.TrimSpace(name)]
+		if !ok {
+			return nil, fmt.Errorf("unknown erasure hook %q in USER_ERASURE_HOOKS", name)
+		}
+
+		enabledHooks = append(enabledHooks, hook)
+	}
+
+	return enabledHooks, nil
+}
+
+type userErasureService struct {
+	userRepository          repositories.UserRepository
+	eventPublisher          EventPublisher
+	userTombstoneRepository repositories.UserTombstoneRepository
+	auditService            AuditService
+	hooks                   []ErasureHook
+}
+
+// Erase runs the erasure hooks and then erases the personal data of the user
+// and revokes their sessions. When a hook fails the user is left untouched,
+// so the erasure can be requested again. The tombstone and the audit event
+// are written in the transaction erasing the user, so there is no erasure
+// without them.
+func (s *userErasureService) Erase(ctx context.Context, user *models.User) error {
+	return s.erase(
+		ctx,
+		user,
+		s.userRepository.EraseById,
+		[]string{ErasureStepProfile, ErasureStepSessions},
+		AuditActionUserDeleted,
+		map[string]interface{}{"erasure": true},
+	)
+}
+
+// Dispose erases a deleted user the same way once its grace period is over,
+// anonymizing or purging its row depending on the retention mode.
+func (s *userErasureService) Dispose(
+	ctx context.Context, user *models.User, mode string,
+) error {
+	eraseById := s.userRepository.AnonymizeDeletedById
+	if mode == RetentionModePurge {
+		eraseById = s.userRepository.PurgeDeletedById
+	}
+
+	return s.erase(
+		ctx,
+		user,
+		eraseById,
+		[]string{ErasureStepProfile},
+		AuditActionUserDisposed,
+		map[string]interface{}{"mode": mode},
+	)
+}
+
+// erase runs the hooks and then eraseById, followed by steps
+func (s *userErasureService) erase(
+	ctx context.Context,
+	user *models.User,
+	eraseById func(ctx context.Context, userId string) (bool, error),
+	steps []string,
+	action string,
+	metadata map[string]interface{},
+) error {
+	carriedOut := []string{}
+	for _, hook := range s.hooks {
+		if err := hook.Erase(ctx, user); err != nil {
+			return fmt.Errorf("erasure hook %s: %w", hook.Name(), err)
+		}
+
+		carriedOut = append(carriedOut, hook.Name())
+	}
+	carriedOut = append(carriedOut, steps...)
+
+	tombstone := models.UserTombstone{
+		UserID:   user.ID.String(),
+		Steps:    strings.Join(carriedOut, ","),
+		ErasedAt: time.Now().UTC(),
+	}
+	if actor, ok := ctx.Value(common.AuthUser).(*models.User); ok {
+		tombstone.RequestedBy = actor.ID.String()
+	}
+
+	return s.eventPublisher.Publish(
+		ctx, func(ctx context.Context) ([]entities.DomainEvent, error) {
+			erased, err := eraseById(ctx, user.ID.String())
+			if err != nil {
+				return nil, err
+			} else if !erased {
+				return nil, entities.NewItemNotFoundError("User", user.ID.String())
+			}
+
+			if err := s.userTombstoneRepository.Create(ctx, tombstone); err != nil {
+				return nil, err
+			}
+
+			if err := s.auditService.Record(
+				ctx, action, auditTargetUser, user.ID.String(), metadata,
+			); err != nil {
+				return nil, err
+			}
+
+			return []entities.DomainEvent{
+				entities.UserDeleted{UserID: user.ID.String(), Erased: true},
+			}, nil
+		},
+	)
+}
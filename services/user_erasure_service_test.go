@@ -0,0 +1,327 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	"verifymy-golang-test/common"
+	"verifymy-golang-test/entities"
+	mock_repositories "verifymy-golang-test/mocks/repositories"
+	mock_services "verifymy-golang-test/mocks/services"
+	"verifymy-golang-test/models"
+)
+
+type erasureHookStub struct {
+	name   string
+	err    error
+	erased []*models.User
+}
+
+func (h *erasureHookStub) Name() string {
+	return h.name
+}
+
+func (h *erasureHookStub) Erase(ctx context.Context, user *models.User) error {
+	h.erased = append(h.erased, user)
+
+	return h.err
+}
+
+type userErasureServiceTestSuite struct {
+	suite.Suite
+	ctrl                        *gomock.Controller
+	userRepositoryMock          *mock_repositories.MockUserRepository
+	outboxEventRepositoryMock   *mock_repositories.MockOutboxEventRepository
+	userTombstoneRepositoryMock *mock_repositories.MockUserTombstoneRepository
+	auditServiceMock            *mock_services.MockAuditService
+	eventPublisher              EventPublisher
+}
+
+func TestUserErasureServiceTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(userErasureServiceTestSuite))
+}
+
+func (s *userErasureServiceTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.userRepositoryMock = mock_repositories.NewMockUserRepository(s.ctrl)
+	s.outboxEventRepositoryMock = mock_repositories.NewMockOutboxEventRepository(s.ctrl)
+	s.userTombstoneRepositoryMock = mock_repositories.NewMockUserTombstoneRepository(s.ctrl)
+	s.auditServiceMock = mock_services.NewMockAuditService(s.ctrl)
+
+	transactorMock := mock_repositories.NewMockTransactor(s.ctrl)
+	transactorMock.EXPECT().WithinTransaction(
+		gomock.Any(), gomock.Any(),
+	).DoAndReturn(runWithinTransaction).AnyTimes()
+	s.eventPublisher = NewEventPublisher(transactorMock, s.outboxEventRepositoryMock)
+}
+
+func (s *userErasureServiceTestSuite) TestNewUserErasureService() {
+	hooks := []ErasureHook{&erasureHookStub{name: "orders"}}
+
+	service, err := NewUserErasureService(
+		s.userRepositoryMock, s.eventPublisher, s.userTombstoneRepositoryMock, s.auditServiceMock,
+		hooks,
+	)
+
+	s.NoError(err)
+	s.Equal(hooks, service.(*userErasureService).hooks)
+}
+
+func (s *userErasureServiceTestSuite) TestEnabledErasureHooks() {
+	orders := &erasureHookStub{name: "orders"}
+	newsletter := &erasureHookStub{name: "newsletter"}
+	hooks := []ErasureHook{orders, newsletter}
+
+	tests := []struct {
+		description   string
+		names         string
+		expectedHooks []ErasureHook
+		expectedError string
+	}{
+		{
+			description:   "Every hook by default",
+			expectedHooks: hooks,
+		},
+		{
+			description:   "Listed hooks in order",
+			names:         "newsletter, orders",
+			expectedHooks: []ErasureHook{newsletter, orders},
+		},
+		{
+			description:   "No hook but the listed ones",
+			names:         "orders",
+			expectedHooks: []ErasureHook{orders},
+		},
+		{
+			description:   "Unknown hook",
+			names:         "orders,invoices",
+			expectedError: `unknown erasure hook "invoices" in USER_ERASURE_HOOKS`,
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			enabledHooks, err := enabledErasureHooks(hooks, test.names)
+
+			if test.expectedError != "" {
+				s.EqualError(err, test.expectedError)
+			} else {
+				s.NoError(err)
+			}
+			s.Equal(test.expectedHooks, enabledHooks)
+		})
+	}
+}
+
+func (s *userErasureServiceTestSuite) TestErase() {
+	user := &models.User{ID: uuid.New()}
+	ctx := context.WithValue(context.Background(), common.AuthUser, user)
+
+	tests := []struct {
+		description   string
+		hookError     error
+		erased        bool
+		eraseError    error
+		tombstone     *models.UserTombstone
+		tombstoneErr  error
+		auditError    error
+		expectedError error
+	}{
+		{
+			description: "Success",
+			erased:      true,
+			tombstone: &models.UserTombstone{
+				UserID:      user.ID.String(),
+				RequestedBy: user.ID.String(),
+				Steps:       "orders,newsletter,profile,sessions",
+			},
+		},
+		{
+			description: "Error creating tombstone",
+			erased:      true,
+			tombstone: &models.UserTombstone{
+				UserID:      user.ID.String(),
+				RequestedBy: user.ID.String(),
+				Steps:       "orders,newsletter,profile,sessions",
+			},
+			tombstoneErr:  errors.New("error creating tombstone"),
+			expectedError: errors.New("error creating tombstone"),
+		},
+		{
+			description: "Error recording audit event",
+			erased:      true,
+			tombstone: &models.UserTombstone{
+				UserID:      user.ID.String(),
+				RequestedBy: user.ID.String(),
+				Steps:       "orders,newsletter,profile,sessions",
+			},
+			auditError:    errors.New("error recording audit event"),
+			expectedError: errors.New("error recording audit event"),
+		},
+		{
+			description:   "Hook fails",
+			hookError:     errors.New("error erasing orders"),
+			expectedError: errors.New("erasure hook orders: error erasing orders"),
+		},
+		{
+			description:   "User not found",
+			expectedError: entities.NewItemNotFoundError("User", user.ID.String()),
+		},
+		{
+			description:   "Error erasing user",
+			eraseError:    errors.New("error erasing user"),
+			expectedError: errors.New("error erasing user"),
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			orders := &erasureHookStub{name: "orders", err: test.hookError}
+			newsletter := &erasureHookStub{name: "newsletter"}
+			service, _ := NewUserErasureService(
+				s.userRepositoryMock,
+				s.eventPublisher,
+				s.userTombstoneRepositoryMock,
+				s.auditServiceMock,
+				[]ErasureHook{orders, newsletter},
+			)
+
+			if test.hookError == nil {
+				s.userRepositoryMock.EXPECT().EraseById(
+					ctx, user.ID.String(),
+				).Return(test.erased, test.eraseError)
+			}
+			if test.tombstone != nil {
+				s.userTombstoneRepositoryMock.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(
+					func(ctx context.Context, tombstone models.UserTombstone) error {
+						s.False(tombstone.ErasedAt.IsZero())
+						tombstone.ErasedAt = test.tombstone.ErasedAt
+						s.Equal(*test.tombstone, tombstone)
+
+						return test.tombstoneErr
+					},
+				)
+			}
+			if test.tombstone != nil && test.tombstoneErr == nil {
+				s.auditServiceMock.EXPECT().Record(
+					ctx,
+					AuditActionUserDeleted,
+					auditTargetUser,
+					user.ID.String(),
+					map[string]interface{}{"erasure": true},
+				).Return(test.auditError)
+			}
+			if test.tombstone != nil && test.tombstoneErr == nil && test.auditError == nil {
+				s.outboxEventRepositoryMock.EXPECT().Create(ctx, outboxEventsOf(
+					entities.UserDeleted{UserID: user.ID.String(), Erased: true},
+				)).Return(nil)
+			}
+
+			err := service.Erase(ctx, user)
+
+			if test.expectedError != nil {
+				s.EqualError(err, test.expectedError.Error())
+			} else {
+				s.NoError(err)
+			}
+			s.Equal([]*models.User{user}, orders.erased)
+			if test.hookError != nil {
+				s.Empty(newsletter.erased)
+			}
+		})
+	}
+}
+
+func (s *userErasureServiceTestSuite) TestDispose() {
+	user := &models.User{ID: uuid.New()}
+	ctx := context.Background()
+
+	tests := []struct {
+		description   string
+		mode          string
+		disposed      bool
+		disposeError  error
+		expectedError error
+	}{
+		{
+			description: "Anonymize",
+			mode:        RetentionModeAnonymize,
+			disposed:    true,
+		},
+		{
+			description: "Purge",
+			mode:        RetentionModePurge,
+			disposed:    true,
+		},
+		{
+			description:   "User not found",
+			mode:          RetentionModeAnonymize,
+			expectedError: entities.NewItemNotFoundError("User", user.ID.String()),
+		},
+		{
+			description:   "Error purging user",
+			mode:          RetentionModePurge,
+			disposeError:  errors.New("error purging user"),
+			expectedError: errors.New("error purging user"),
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			orders := &erasureHookStub{name: "orders"}
+			service, _ := NewUserErasureService(
+				s.userRepositoryMock,
+				s.eventPublisher,
+				s.userTombstoneRepositoryMock,
+				s.auditServiceMock,
+				[]ErasureHook{orders},
+			)
+
+			if test.mode == RetentionModePurge {
+				s.userRepositoryMock.EXPECT().PurgeDeletedById(
+					ctx, user.ID.String(),
+				).Return(test.disposed, test.disposeError)
+			} else {
+				s.userRepositoryMock.EXPECT().AnonymizeDeletedById(
+					ctx, user.ID.String(),
+				).Return(test.disposed, test.disposeError)
+			}
+			if test.disposed {
+				s.userTombstoneRepositoryMock.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(
+					func(ctx context.Context, tombstone models.UserTombstone) error {
+						s.Equal(user.ID.String(), tombstone.UserID)
+						s.Empty(tombstone.RequestedBy)
+						s.Equal("orders,profile", tombstone.Steps)
+
+						return nil
+					},
+				)
+				s.auditServiceMock.EXPECT().Record(
+					ctx,
+					AuditActionUserDisposed,
+					auditTargetUser,
+					user.ID.String(),
+					map[string]interface{}{"mode": test.mode},
+				).Return(nil)
+				s.outboxEventRepositoryMock.EXPECT().Create(ctx, outboxEventsOf(
+					entities.UserDeleted{UserID: user.ID.String(), Erased: true},
+				)).Return(nil)
+			}
+
+			err := service.Dispose(ctx, user, test.mode)
+
+			if test.expectedError != nil {
+				s.EqualError(err, test.expectedError.Error())
+			} else {
+				s.NoError(err)
+			}
+			s.Equal([]*models.User{user}, orders.erased)
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package services
+
+import (
+	"context"
+
+	"verifymy-golang-test/models"
+)
+
+// UserIncluder resolves a resource related to users so it can be embedded
+// in user responses through the include query parameter.
+type UserIncluder interface {
+	Name() string
+	// Fields lists the user fields Include reads, which must be loaded even
+	// when a sparse fieldset leaves them out
+	Fields() []string
+	Include(ctx context.Context, users []models.User) (map[string]interface{}, error)
+}
+
+type UserIncludeRegistry interface {
+	Names() []interface{}
+	Fields(names []string) []string
+	Resolve(
+		ctx context.Context, names []string, users []models.User,
+	) (map[string]map[string]interface{}, error)
+}
+
+type userIncludeRegistry struct {
+	includers map[string]UserIncluder
+	names     []interface{}
+}
+
+func NewUserIncludeRegistry(includers []UserIncluder) UserIncludeRegistry {
+	registry := &userIncludeRegistry{includers: map[string]UserIncluder{}}
+	for _, includer := range includers {
+		registry.includers[includer.Name()] = includer
+		registry.names = append(registry.names, includer.Name())
+	}
+
+	return registry
+}
+
+func (r *userIncludeRegistry) Names() []interface{} {
+	return r.names
+}
+
+// Fields returns the user fields the requested includers read
+func (r *userIncludeRegistry) Fields(names []string) []string {
+	var fields []string
+	for _, name := range names {
+		if includer, ok := r.includers[name]; ok {
+			fields = append(fields, includer.Fields()...)
+		}
+	}
+
+	return fields
+}
+
+// Resolve returns the requested related resources keyed by user id and then
+// by include name.
+func (r *userIncludeRegistry) Resolve(
+	ctx context.Context, names []string, users []models.User,
+) (map[string]map[string]interface{}, error) {
+	included := map[string]map[string]interface{}{}
+	for _, user := range users {
+		included[user.ID.String()] = map[string]interface{}{}
+	}
+
+	for _, name := range names {
+		includer, ok := r.includers[name]
+		if !ok {
+			continue
+		}
+
+		resources, err := includer.Include(ctx, users)
+		if err != nil {
+			return nil, err
+		}
+
+		for userId := range included {
+			included[userId][name] = resources[userId]
+		}
+	}
+
+	return included, nil
+}
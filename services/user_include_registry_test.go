@@ -0,0 +1,101 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	mock_services "verifymy-golang-test/mocks/services"
+	"verifymy-golang-test/models"
+)
+
+type userIncludeRegistryTestSuite struct {
+	suite.Suite
+	ctrl         *gomock.Controller
+	includerMock *mock_services.MockUserIncluder
+	registry     UserIncludeRegistry
+}
+
+func TestUserIncludeRegistryTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(userIncludeRegistryTestSuite))
+}
+
+func (s *userIncludeRegistryTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.includerMock = mock_services.NewMockUserIncluder(s.ctrl)
+	s.includerMock.EXPECT().Name().Return("roles").AnyTimes()
+	s.registry = NewUserIncludeRegistry([]UserIncluder{s.includerMock})
+}
+
+func (s *userIncludeRegistryTestSuite) TestNames() {
+	s.Equal([]interface{}{"roles"}, s.registry.Names())
+}
+
+func (s *userIncludeRegistryTestSuite) TestFields() {
+	s.includerMock.EXPECT().Fields().Return([]string{"role"})
+
+	s.Equal([]string{"role"}, s.registry.Fields([]string{"roles", "unknown"}))
+	s.Nil(s.registry.Fields(nil))
+}
+
+func (s *userIncludeRegistryTestSuite) TestResolve() {
+	users := []models.User{{ID: uuid.New()}, {ID: uuid.New()}}
+
+	tests := []struct {
+		description     string
+		names           []string
+		includeResponse map[string]interface{}
+		includeError    error
+		expected        map[string]map[string]interface{}
+	}{
+		{
+			description: "No includes requested",
+			expected: map[string]map[string]interface{}{
+				users[0].ID.String(): {},
+				users[1].ID.String(): {},
+			},
+		},
+		{
+			description: "Success",
+			names:       []string{"roles"},
+			includeResponse: map[string]interface{}{
+				users[0].ID.String(): []string{"admin"},
+			},
+			expected: map[string]map[string]interface{}{
+				users[0].ID.String(): {"roles": []string{"admin"}},
+				users[1].ID.String(): {"roles": nil},
+			},
+		},
+		{
+			description:  "Error including resource",
+			names:        []string{"roles"},
+			includeError: errors.New("error"),
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			ctx := context.Background()
+
+			if len(test.names) > 0 {
+				s.includerMock.EXPECT().Include(ctx, users).Return(
+					test.includeResponse, test.includeError,
+				)
+			}
+
+			included, err := s.registry.Resolve(ctx, test.names, users)
+			if test.includeError != nil {
+				s.Error(err)
+				s.Nil(included)
+			} else {
+				s.NoError(err)
+				s.Equal(test.expected, included)
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package services
+
+import (
+	"context"
+
+	"verifymy-golang-test/models"
+)
+
+// rolesIncluder embeds the roles of users along with the permissions they
+// grant
+type rolesIncluder struct{}
+
+func NewRolesIncluder() UserIncluder {
+	return &rolesIncluder{}
+}
+
+func (i *rolesIncluder) Name() string {
+	return "roles"
+}
+
+func (i *rolesIncluder) Fields() []string {
+	return []string{"role"}
+}
+
+func (i *rolesIncluder) Include(
+	ctx context.Context, users []models.User,
+) (map[string]interface{}, error) {
+	roles := make(map[string]interface{}, len(users))
+	for _, user := range users {
+		roles[user.ID.String()] = []map[string]interface{}{
+			{"name": user.Role, "permissions": models.RolePermissions(user.Role)},
+		}
+	}
+
+	return roles, nil
+}
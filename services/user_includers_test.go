@@ -0,0 +1,47 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	"verifymy-golang-test/models"
+)
+
+type userIncludersTestSuite struct {
+	suite.Suite
+}
+
+func TestUserIncludersTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(userIncludersTestSuite))
+}
+
+func (s *userIncludersTestSuite) TestRolesIncluder() {
+	includer := NewRolesIncluder()
+	admin := models.User{ID: uuid.New(), Role: models.RoleAdmin}
+	user := models.User{ID: uuid.New(), Role: models.RoleUser}
+
+	roles, err := includer.Include(context.Background(), []models.User{admin, user})
+
+	s.Equal("roles", includer.Name())
+	s.Equal([]string{"role"}, includer.Fields())
+	s.NoError(err)
+	s.Equal(map[string]interface{}{
+		admin.ID.String(): []map[string]interface{}{{
+			"name": models.RoleAdmin,
+			"permissions": []models.Permission{
+				models.PermissionManageUsers,
+				models.PermissionViewAuditLog,
+				models.PermissionManageWebhooks,
+				models.PermissionManageClients,
+			},
+		}},
+		user.ID.String(): []map[string]interface{}{{
+			"name":        models.RoleUser,
+			"permissions": []models.Permission{},
+		}},
+	}, roles)
+}
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"verifymy-golang-test/repositories"
+)
+
+const (
+	RetentionModePurge     = "purge"
+	RetentionModeAnonymize = "anonymize"
+
+	defaultDeletedUsersGracePeriod = 30 * 24 * time.Hour
+	disposeDeletedUsersBatchSize   = 100
+)
+
+// UserRetentionService disposes of soft deleted users once their grace
+// period, during which administrators can still restore them, is over.
+type UserRetentionService interface {
+	DisposeDeletedUsers(ctx context.Context) (int64, error)
+}
+
+// NewUserRetentionService reads its policy from DELETED_USERS_GRACE_PERIOD, a
+// duration like "720h", and DELETED_USERS_RETENTION_MODE, either purge or
+// anonymize.
+func NewUserRetentionService(
+	userRepository repositories.UserRepository,
+	userErasureService UserErasureService,
+) (UserRetentionService, error) {
+	gracePeriod := defaultDeletedUsersGracePeriod
+	if value := os.Getenv("DELETED_USERS_GRACE_PERIOD"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed < 0 {
+			return nil, fmt.Errorf("invalid DELETED_USERS_GRACE_PERIOD %q", value)
+		}
+
+		gracePeriod = parsed
+	}
+
+	mode := os.Getenv("DELETED_USERS_RETENTION_MODE")
+	if mode == "" {
+		mode = RetentionModeAnonymize
+	} else if mode != RetentionModePurge && mode != RetentionModeAnonymize {
+		return nil, fmt.Errorf("invalid DELETED_USERS_RETENTION_MODE %q", mode)
+	}
+
+	return &userRetentionService{
+		userRepository:     userRepository,
+		userErasureService: userErasureService,
+		gracePeriod:        gracePeriod,
+		mode:               mode,
+		now:                time.Now,
+	}, nil
+}
+
+type userRetentionService struct {
+	userRepository     repositories.UserRepository
+	userErasureService UserErasureService
+	gracePeriod        time.Duration
+	mode               string
+	now                func() time.Time
+}
+
+// DisposeDeletedUsers purges or anonymizes, depending on the retention mode,
+// the users deleted longer than the grace period ago and returns how many
+// were disposed of. Their data is erased through UserErasureService, so the
+// erasure hooks run for them too. A user failing to be disposed of is left
+// for the next run, without holding back the others.
+func (s *userRetentionService) DisposeDeletedUsers(ctx context.Context) (int64, error) {
+	cutoff := s.now().UTC().Add(-s.gracePeriod)
+
+	var disposed int64
+	var disposeErr error
+	afterId := ""
+	for {
+		users, err := s.userRepository.FindDeletedBefore(
+			ctx, cutoff, afterId, disposeDeletedUsersBatchSize,
+		)
+		if err != nil {
+			return disposed, err
+		}
+
+		for i := range users {
+			if err := s.userErasureService.Dispose(ctx, &users[i], s.mode); err != nil {
+				if disposeErr == nil {
+					disposeErr = err
+				}
+				continue
+			}
+
+			disposed++
+		}
+
+		if len(users) < disposeDeletedUsersBatchSize {
+			break
+		}
+		afterId = users[len(users)-1].ID.String()
+	}
+
+	if s.mode == RetentionModePurge {
+		// Users erased on their request kept their row until now
+		purged, err := s.userRepository.PurgeDeletedBefore(ctx, cutoff)
+		if err != nil {
+			return disposed, err
+		}
+
+		disposed += purged
+	}
+
+	return disposed, disposeErr
+}
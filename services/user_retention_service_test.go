@@ -0,0 +1,209 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	"verifymy-golang-test/entities"
+	mock_repositories "verifymy-golang-test/mocks/repositories"
+	mock_services "verifymy-golang-test/mocks/services"
+	"verifymy-golang-test/models"
+)
+
+type userRetentionServiceTestSuite struct {
+	suite.Suite
+	ctrl                   *gomock.Controller
+	userRepositoryMock     *mock_repositories.MockUserRepository
+	userErasureServiceMock *mock_services.MockUserErasureService
+	now                    time.Time
+}
+
+func TestUserRetentionServiceTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(userRetentionServiceTestSuite))
+}
+
+func (s *userRetentionServiceTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.userRepositoryMock = mock_repositories.NewMockUserRepository(s.ctrl)
+	s.userErasureServiceMock = mock_services.NewMockUserErasureService(s.ctrl)
+	s.now = time.Date(2023, 6, 30, 12, 0, 0, 0, time.UTC)
+}
+
+func (s *userRetentionServiceTestSuite) service(
+	mode string, userErasureService UserErasureService,
+) UserRetentionService {
+	return &userRetentionService{
+		userRepository:     s.userRepositoryMock,
+		userErasureService: userErasureService,
+		gracePeriod:        48 * time.Hour,
+		mode:               mode,
+		now:                func() time.Time { return s.now },
+	}
+}
+
+func (s *userRetentionServiceTestSuite) TestNewUserRetentionService() {
+	service, err := NewUserRetentionService(s.userRepositoryMock, s.userErasureServiceMock)
+
+	s.NoError(err)
+	s.Equal(defaultDeletedUsersGracePeriod, service.(*userRetentionService).gracePeriod)
+	s.Equal(RetentionModeAnonymize, service.(*userRetentionService).mode)
+}
+
+func (s *userRetentionServiceTestSuite) TestDisposeDeletedUsers() {
+	ctx := context.Background()
+	expectedCutoff := s.now.Add(-48 * time.Hour)
+	users := []models.User{{ID: uuid.New()}, {ID: uuid.New()}}
+
+	tests := []struct {
+		description      string
+		mode             string
+		findError        error
+		disposeErrors    []error
+		purged           int64
+		purgeError       error
+		expectedDisposed int64
+		expectedError    error
+	}{
+		{
+			description:      "Anonymize",
+			mode:             RetentionModeAnonymize,
+			disposeErrors:    []error{nil, nil},
+			expectedDisposed: 2,
+		},
+		{
+			description:      "Purge, along with erased users",
+			mode:             RetentionModePurge,
+			disposeErrors:    []error{nil, nil},
+			purged:           3,
+			expectedDisposed: 5,
+		},
+		{
+			description:      "Keeps disposing past a user failing to be",
+			mode:             RetentionModeAnonymize,
+			disposeErrors:    []error{errors.New("erasure hook orders: error"), nil},
+			expectedDisposed: 1,
+			expectedError:    errors.New("erasure hook orders: error"),
+		},
+		{
+			description:   "Error finding deleted users",
+			mode:          RetentionModeAnonymize,
+			findError:     errors.New("error finding users"),
+			expectedError: errors.New("error finding users"),
+		},
+		{
+			description:      "Error purging erased users",
+			mode:             RetentionModePurge,
+			disposeErrors:    []error{nil, nil},
+			purgeError:       errors.New("error purging users"),
+			expectedDisposed: 2,
+			expectedError:    errors.New("error purging users"),
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			if test.findError != nil {
+				s.userRepositoryMock.EXPECT().FindDeletedBefore(
+					ctx, expectedCutoff, "", disposeDeletedUsersBatchSize,
+				).Return(nil, test.findError)
+			} else {
+				s.userRepositoryMock.EXPECT().FindDeletedBefore(
+					ctx, expectedCutoff, "", disposeDeletedUsersBatchSize,
+				).Return(users, nil)
+			}
+			for i, disposeError := range test.disposeErrors {
+				s.userErasureServiceMock.EXPECT().Dispose(
+					ctx, &users[i], test.mode,
+				).Return(disposeError)
+			}
+			if test.mode == RetentionModePurge && test.findError == nil {
+				s.userRepositoryMock.EXPECT().PurgeDeletedBefore(
+					ctx, expectedCutoff,
+				).Return(test.purged, test.purgeError)
+			}
+
+			disposed, err := s.service(test.mode, s.userErasureServiceMock).DisposeDeletedUsers(ctx)
+
+			s.Equal(test.expectedError, err)
+			s.Equal(test.expectedDisposed, disposed)
+		})
+	}
+}
+
+func (s *userRetentionServiceTestSuite) TestDisposeDeletedUsersInBatches() {
+	ctx := context.Background()
+	expectedCutoff := s.now.Add(-48 * time.Hour)
+	batch := make([]models.User, disposeDeletedUsersBatchSize)
+	for i := range batch {
+		batch[i] = models.User{ID: uuid.New()}
+	}
+
+	gomock.InOrder(
+		s.userRepositoryMock.EXPECT().FindDeletedBefore(
+			ctx, expectedCutoff, "", disposeDeletedUsersBatchSize,
+		).Return(batch, nil),
+		s.userRepositoryMock.EXPECT().FindDeletedBefore(
+			ctx, expectedCutoff, batch[len(batch)-1].ID.String(), disposeDeletedUsersBatchSize,
+		).Return(nil, nil),
+	)
+	s.userErasureServiceMock.EXPECT().Dispose(
+		ctx, gomock.Any(), RetentionModeAnonymize,
+	).Return(nil).Times(len(batch))
+
+	disposed, err := s.service(
+		RetentionModeAnonymize, s.userErasureServiceMock,
+	).DisposeDeletedUsers(ctx)
+
+	s.NoError(err)
+	s.Equal(int64(len(batch)), disposed)
+}
+
+func (s *userRetentionServiceTestSuite) TestDisposeDeletedUsersRunsErasureHooks() {
+	ctx := context.Background()
+	user := models.User{ID: uuid.New(), Email: "peter.parker@nyork.co"}
+	hook := &erasureHookStub{name: "data_exports"}
+
+	outboxEventRepositoryMock := mock_repositories.NewMockOutboxEventRepository(s.ctrl)
+	userTombstoneRepositoryMock := mock_repositories.NewMockUserTombstoneRepository(s.ctrl)
+	auditServiceMock := mock_services.NewMockAuditService(s.ctrl)
+	transactorMock := mock_repositories.NewMockTransactor(s.ctrl)
+	transactorMock.EXPECT().WithinTransaction(
+		gomock.Any(), gomock.Any(),
+	).DoAndReturn(runWithinTransaction).AnyTimes()
+	userErasureService, err := NewUserErasureService(
+		s.userRepositoryMock,
+		NewEventPublisher(transactorMock, outboxEventRepositoryMock),
+		userTombstoneRepositoryMock,
+		auditServiceMock,
+		[]ErasureHook{hook},
+	)
+	s.Require().NoError(err)
+
+	s.userRepositoryMock.EXPECT().FindDeletedBefore(
+		ctx, s.now.Add(-48*time.Hour), "", disposeDeletedUsersBatchSize,
+	).Return([]models.User{user}, nil)
+	s.userRepositoryMock.EXPECT().AnonymizeDeletedById(ctx, user.ID.String()).Return(true, nil)
+	userTombstoneRepositoryMock.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+	auditServiceMock.EXPECT().Record(
+		ctx, AuditActionUserDisposed, auditTargetUser, user.ID.String(), gomock.Any(),
+	).Return(nil)
+	outboxEventRepositoryMock.EXPECT().Create(ctx, outboxEventsOf(
+		entities.UserDeleted{UserID: user.ID.String(), Erased: true},
+	)).Return(nil)
+
+	disposed, err := s.service(
+		RetentionModeAnonymize, userErasureService,
+	).DisposeDeletedUsers(ctx)
+
+	s.NoError(err)
+	s.Equal(int64(1), disposed)
+	s.Require().Len(hook.erased, 1)
+	s.Equal("peter.parker@nyork.co", hook.erased[0].Email)
+}
@@ -2,32 +2,43 @@ package services
 
 import (
 	"context"
-	"database/sql"
-	"time"
+	"fmt"
 
 	"verifymy-golang-test/common"
 	"verifymy-golang-test/entities"
 	"verifymy-golang-test/models"
+	"verifymy-golang-test/providers"
 	"verifymy-golang-test/repositories"
 	"verifymy-golang-test/utils"
 )
 
 type UserService interface {
 	FindById(ctx context.Context, userId string) (*models.User, error)
-	FindAll(ctx context.Context, limit int, page int) ([]models.User, int64, error)
-	UpdateProfile(ctx context.Context, attributes models.User) error
+	FindAll(ctx context.Context, query entities.UserQuery) (*entities.UsersPage, error)
+	UpdateProfile(ctx context.Context, attributes models.User, expectedVersion int64) error
+	PatchProfile(ctx context.Context, changes map[string]interface{}, expectedVersion int64) error
+	RequestEmailChange(ctx context.Context, email string) error
 	DeleteById(ctx context.Context, userId string) error
 }
 
 type userService struct {
 	userRepository repositories.UserRepository
+	eventPublisher EventPublisher
+	auditService   AuditService
+	mailer         providers.Mailer
 }
 
 func NewUserService(
 	userRepository repositories.UserRepository,
+	eventPublisher EventPublisher,
+	auditService AuditService,
+	mailer providers.Mailer,
 ) UserService {
 	return &userService{
 		userRepository: userRepository,
+		eventPublisher: eventPublisher,
+		auditService:   auditService,
+		mailer:         mailer,
 	}
 }
 
@@ -42,31 +53,200 @@ func (s *userService) FindById(ctx context.Context, userId string) (*models.User
 	return user, nil
 }
 
-func (s *userService) FindAll(ctx context.Context, limit int, page int) ([]models.User, int64, error) {
-	offset := (page - 1) * limit
-	return s.userRepository.FindAll(ctx, limit, offset)
-}
+// FindAll fetches one extra user to find out whether there is another page
+// in the requested direction without running a COUNT.
+func (s *userService) FindAll(
+	ctx context.Context, query entities.UserQuery,
+) (*entities.UsersPage, error) {
+	limit := query.Page.Limit
+	lookahead := query
+	lookahead.Page.Limit = limit + 1
 
-func (s *userService) UpdateProfile(ctx context.Context, attributes models.User) error {
-	user := ctx.Value(common.AuthUser).(*models.User)
-	if attributes.Password != "" {
-		hashedPassword, err := utils.PasswordHash(string(attributes.Password))
+	users, err := s.userRepository.FindAll(ctx, lookahead)
+	if err != nil {
+		return nil, err
+	}
+
+	hasMore := len(users) > limit
+	if hasMore {
+		users = users[:limit]
+	}
+
+	cursor := query.Page.Cursor
+	result := &entities.UsersPage{Users: users}
+	if cursor != nil && cursor.Backward {
+		for i, j := 0, len(users)-1; i < j; i, j = i+1, j-1 {
+			users[i], users[j] = users[j], users[i]
+		}
+
+		if len(users) > 0 {
+			result.Next = query.CursorFor(users[len(users)-1], false)
+			if hasMore {
+				result.Previous = query.CursorFor(users[0], true)
+			}
+		}
+	} else if len(users) > 0 {
+		if hasMore {
+			result.Next = query.CursorFor(users[len(users)-1], false)
+		}
+		if cursor != nil {
+			result.Previous = query.CursorFor(users[0], true)
+		}
+	}
+
+	if query.Page.WithTotal {
+		total, err := s.userRepository.Count(ctx, query.Filters)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
-		attributes.Password = models.SecretValue(hashedPassword)
+		result.Total = &total
 	}
 
-	return s.userRepository.UpdateAttributesByUserId(ctx, user.ID.String(), attributes)
+	return result, nil
 }
 
-func (s *userService) DeleteById(ctx context.Context, userId string) error {
-	return s.userRepository.UpdateAttributesByUserId(
-		ctx,
-		userId,
-		models.User{
-			DeletedAt: sql.NullTime{Time: time.Now().UTC()},
+func (s *userService) UpdateProfile(
+	ctx context.Context, attributes models.User, expectedVersion int64,
+) error {
+	user := ctx.Value(common.AuthUser).(*models.User)
+	// Roles are only assigned by administrators
+	attributes.Role = ""
+	// the status and guardian only through parental consent, see
+	// ParentalConsentService
+	attributes.Status = ""
+	attributes.GuardianEmail = ""
+	// and emails are only changed once the new address is confirmed
+	if attributes.Email != "" &&
+		utils.NormalizeEmail(attributes.Email, false) != utils.NormalizeEmail(user.Email, false) {
+		return newUnconfirmedEmailChangeError()
+	}
+	attributes.Email = ""
+	// and passwords once the current one is confirmed, see
+	// AuthService.ChangePassword
+	attributes.Password = ""
+
+	changes, err := auditAttributes(&attributes)
+	if err != nil {
+		return err
+	}
+
+	if err := s.eventPublisher.Publish(
+		ctx, func(ctx context.Context) ([]entities.DomainEvent, error) {
+			if err := s.userRepository.UpdateAttributesByUserId(
+				ctx, user.ID.String(), attributes, expectedVersion,
+			); err != nil {
+				return nil, err
+			}
+
+			return profileUpdatedEvents(user.ID.String(), changes), nil
+		},
+	); err != nil {
+		return err
+	}
+
+	return s.auditService.RecordChanges(
+		ctx, AuditActionUserProfileUpdated, auditTargetUser, user.ID.String(), user, changes,
+	)
+}
+
+// PatchProfile writes changes, keyed by column, to the signed in user. Unlike
+// UpdateProfile, zero values are written so fields can be cleared.
+func (s *userService) PatchProfile(
+	ctx context.Context, changes map[string]interface{}, expectedVersion int64,
+) error {
+	user := ctx.Value(common.AuthUser).(*models.User)
+	if _, ok := changes["email"]; ok {
+		return newUnconfirmedEmailChangeError()
+	}
+
+	if err := s.eventPublisher.Publish(
+		ctx, func(ctx context.Context) ([]entities.DomainEvent, error) {
+			if err := s.userRepository.UpdateColumnsByUserId(
+				ctx, user.ID.String(), changes, expectedVersion,
+			); err != nil {
+				return nil, err
+			}
+
+			return profileUpdatedEvents(user.ID.String(), changes), nil
 		},
+	); err != nil {
+		return err
+	}
+
+	return s.auditService.RecordChanges(
+		ctx, AuditActionUserProfileUpdated, auditTargetUser, user.ID.String(), user, changes,
+	)
+}
+
+func newUnconfirmedEmailChangeError() error {
+	return entities.NewValidationError(
+		"email can only be changed by confirming the new address",
+	)
+}
+
+// RequestEmailChange emails a confirmation link to the new address of the
+// signed in user, see AuthService.ConfirmEmailChange, and a notice to the
+// current one.
+func (s *userService) RequestEmailChange(ctx context.Context, email string) error {
+	user := ctx.Value(common.AuthUser).(*models.User)
+
+	foundUser, err := s.userRepository.FindByEmail(ctx, email)
+	if err != nil {
+		return err
+	} else if foundUser != nil {
+		return entities.NewEmailAlreadyInUseError(email)
+	}
+
+	token, err := newEmailChangeToken(user, email)
+	if err != nil {
+		return err
+	}
+
+	if err := s.mailer.Send(ctx, providers.Email{
+		To:      email,
+		Subject: "Confirm your new e-mail",
+		Body: fmt.Sprintf(
+			"Hello %s,\n\nConfirm this is your new e-mail at %s\n",
+			user.Name, emailChangeURL(token),
+		),
+	}); err != nil {
+		return err
+	}
+
+	if err := s.mailer.Send(ctx, providers.Email{
+		To:      user.Email,
+		Subject: "Your e-mail is being changed",
+		Body: fmt.Sprintf(
+			"Hello %s,\n\nA change of your e-mail to %s was requested. "+
+				"It only takes effect once confirmed from the new address. "+
+				"If you did not request it, change your password.\n",
+			user.Name, email,
+		),
+	}); err != nil {
+		return err
+	}
+
+	return s.auditService.Record(
+		ctx, AuditActionUserEmailChangeRequested, auditTargetUser, user.ID.String(), nil,
 	)
 }
+
+func (s *userService) DeleteById(ctx context.Context, userId string) error {
+	if err := s.eventPublisher.Publish(
+		ctx, func(ctx context.Context) ([]entities.DomainEvent, error) {
+			deleted, err := s.userRepository.DeleteById(ctx, userId)
+			if err != nil {
+				return nil, err
+			} else if !deleted {
+				return nil, entities.NewItemNotFoundError("User", userId)
+			}
+
+			return []entities.DomainEvent{entities.UserDeleted{UserID: userId}}, nil
+		},
+	); err != nil {
+		return err
+	}
+
+	return s.auditService.Record(ctx, AuditActionUserDeleted, auditTargetUser, userId, nil)
+}
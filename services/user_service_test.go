@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 
 	"github.com/golang/mock/gomock"
@@ -10,15 +11,22 @@ import (
 	"github.com/stretchr/testify/suite"
 
 	"verifymy-golang-test/common"
+	"verifymy-golang-test/entities"
+	mock_providers "verifymy-golang-test/mocks/providers"
 	mock_repositories "verifymy-golang-test/mocks/repositories"
+	mock_services "verifymy-golang-test/mocks/services"
 	"verifymy-golang-test/models"
+	"verifymy-golang-test/providers"
 )
 
 type userServiceTestSuite struct {
 	suite.Suite
-	ctrl               *gomock.Controller
-	userRepositoryMock *mock_repositories.MockUserRepository
-	service            UserService
+	ctrl                      *gomock.Controller
+	userRepositoryMock        *mock_repositories.MockUserRepository
+	outboxEventRepositoryMock *mock_repositories.MockOutboxEventRepository
+	auditServiceMock          *mock_services.MockAuditService
+	mailerMock                *mock_providers.MockMailer
+	service                   UserService
 }
 
 func TestUserServiceTestSuite(t *testing.T) {
@@ -29,7 +37,21 @@ func TestUserServiceTestSuite(t *testing.T) {
 func (s *userServiceTestSuite) SetupTest() {
 	s.ctrl = gomock.NewController(s.T())
 	s.userRepositoryMock = mock_repositories.NewMockUserRepository(s.ctrl)
-	s.service = NewUserService(s.userRepositoryMock)
+	s.outboxEventRepositoryMock = mock_repositories.NewMockOutboxEventRepository(s.ctrl)
+	s.auditServiceMock = mock_services.NewMockAuditService(s.ctrl)
+	s.mailerMock = mock_providers.NewMockMailer(s.ctrl)
+
+	transactorMock := mock_repositories.NewMockTransactor(s.ctrl)
+	transactorMock.EXPECT().WithinTransaction(
+		gomock.Any(), gomock.Any(),
+	).DoAndReturn(runWithinTransaction).AnyTimes()
+
+	s.service = NewUserService(
+		s.userRepositoryMock,
+		NewEventPublisher(transactorMock, s.outboxEventRepositoryMock),
+		s.auditServiceMock,
+		s.mailerMock,
+	)
 }
 
 func (s *userServiceTestSuite) TestFindById() {
@@ -82,29 +104,80 @@ func (s *userServiceTestSuite) TestFindById() {
 }
 
 func (s *userServiceTestSuite) TestFindAll() {
+	users := []models.User{
+		{ID: uuid.New(), Name: "Anna"},
+		{ID: uuid.New(), Name: "Bruno"},
+		{ID: uuid.New(), Name: "Carla"},
+	}
+	total := int64(3)
+	filters := entities.UserFilters{Name: "a"}
+
+	byName := func(page entities.PageRequest) entities.UserQuery {
+		return entities.UserQuery{
+			Filters:       filters,
+			SortField:     "name",
+			SortDirection: entities.SortAscending,
+			Page:          page,
+		}
+	}
+	cursorFor := func(user models.User, backward bool) *entities.Cursor {
+		return &entities.Cursor{
+			ID: user.ID.String(), Sort: "name:asc", Value: user.Name, Backward: backward,
+		}
+	}
+
 	tests := []struct {
-		description    string
-		limit          int
-		page           int
-		expectedOffset int
+		description      string
+		query            entities.UserQuery
+		findAllResponse  []models.User
+		findAllError     error
+		countError       error
+		expectedUsers    []models.User
+		expectedNext     *entities.Cursor
+		expectedPrevious *entities.Cursor
+		expectedTotal    *int64
 	}{
 		{
-			description:    "Page 1 Limit 10",
-			limit:          10,
-			page:           1,
-			expectedOffset: 0,
+			description:     "First page with more results",
+			query:           byName(entities.PageRequest{Limit: 2}),
+			findAllResponse: users,
+			expectedUsers:   users[:2],
+			expectedNext:    cursorFor(users[1], false),
 		},
 		{
-			description:    "Page 2 Limit 5",
-			limit:          10,
-			page:           2,
-			expectedOffset: 10,
+			description:      "Last page after forward cursor",
+			query:            byName(entities.PageRequest{Limit: 2, Cursor: cursorFor(users[1], false)}),
+			findAllResponse:  users[2:],
+			expectedUsers:    users[2:],
+			expectedPrevious: cursorFor(users[2], true),
 		},
 		{
-			description:    "Page 3 Limit 7",
-			limit:          7,
-			page:           3,
-			expectedOffset: 14,
+			description: "Backward cursor with more results",
+			query: byName(
+				entities.PageRequest{Limit: 2, Cursor: cursorFor(users[2], true)},
+			),
+			findAllResponse:  []models.User{users[2], users[1], users[0]},
+			expectedUsers:    []models.User{users[1], users[2]},
+			expectedNext:     cursorFor(users[2], false),
+			expectedPrevious: cursorFor(users[1], true),
+		},
+		{
+			description:     "With total count",
+			query:           byName(entities.PageRequest{Limit: 3, WithTotal: true}),
+			findAllResponse: users,
+			expectedUsers:   users,
+			expectedTotal:   &total,
+		},
+		{
+			description:  "Error finding users",
+			query:        byName(entities.PageRequest{Limit: 2}),
+			findAllError: errors.New("error"),
+		},
+		{
+			description:     "Error counting users",
+			query:           byName(entities.PageRequest{Limit: 3, WithTotal: true}),
+			findAllResponse: users,
+			countError:      errors.New("error"),
 		},
 	}
 
@@ -112,12 +185,28 @@ func (s *userServiceTestSuite) TestFindAll() {
 		s.Run(test.description, func() {
 			ctx := context.Background()
 
-			s.userRepositoryMock.EXPECT().FindAll(ctx, test.limit, test.expectedOffset).Return(
-				[]models.User{}, int64(0), nil,
+			lookahead := test.query
+			lookahead.Page.Limit++
+
+			findAllResponse := append([]models.User{}, test.findAllResponse...)
+			s.userRepositoryMock.EXPECT().FindAll(ctx, lookahead).Return(
+				findAllResponse, test.findAllError,
 			)
+			if test.query.Page.WithTotal && test.findAllError == nil {
+				s.userRepositoryMock.EXPECT().Count(ctx, filters).Return(total, test.countError)
+			}
 
-			_, _, err := s.service.FindAll(ctx, test.limit, test.page)
-			s.NoError(err)
+			page, err := s.service.FindAll(ctx, test.query)
+			if test.findAllError != nil || test.countError != nil {
+				s.Error(err)
+				s.Nil(page)
+			} else {
+				s.NoError(err)
+				s.Equal(test.expectedUsers, page.Users)
+				s.Equal(test.expectedNext, page.Next)
+				s.Equal(test.expectedPrevious, page.Previous)
+				s.Equal(test.expectedTotal, page.Total)
+			}
 		})
 	}
 }
@@ -125,14 +214,18 @@ func (s *userServiceTestSuite) TestFindAll() {
 func (s *userServiceTestSuite) TestUpdateProfile() {
 	userId := uuid.New()
 	user := &models.User{
-		ID: userId,
+		ID:    userId,
+		Email: "peter.parker@nyork.co",
 	}
 
 	tests := []struct {
 		description                   string
+		user                          *models.User
 		attributes                    models.User
-		updatingPassword              bool
+		expectedAttributes            *models.User
 		updateAttributesByUserIdError error
+		recordError                   error
+		expectedError                 error
 	}{
 		{
 			description: "Success",
@@ -141,11 +234,60 @@ func (s *userServiceTestSuite) TestUpdateProfile() {
 			},
 		},
 		{
-			description: "Success changing password",
+			description: "Role is ignored",
 			attributes: models.User{
+				Name: "John Doe",
+				Role: models.RoleAdmin,
+			},
+			expectedAttributes: &models.User{
+				Name: "John Doe",
+			},
+		},
+		{
+			description: "Password is ignored",
+			attributes: models.User{
+				Name:     "John Doe",
 				Password: "my-password",
 			},
-			updatingPassword: true,
+			expectedAttributes: &models.User{
+				Name: "John Doe",
+			},
+		},
+		{
+			description: "Status and guardian email are ignored",
+			user: &models.User{
+				ID:            userId,
+				Email:         "peter.parker@nyork.co",
+				Status:        models.UserStatusPendingParentalConsent,
+				GuardianEmail: "may.parker@nyork.co",
+			},
+			attributes: models.User{
+				Name:          "John Doe",
+				Status:        models.UserStatusActive,
+				GuardianEmail: "peter.parker@nyork.co",
+			},
+			expectedAttributes: &models.User{
+				Name: "John Doe",
+			},
+		},
+		{
+			description: "Unchanged email is ignored",
+			attributes: models.User{
+				Name:  "John Doe",
+				Email: "Peter.Parker@nyork.co",
+			},
+			expectedAttributes: &models.User{
+				Name: "John Doe",
+			},
+		},
+		{
+			description: "Email change",
+			attributes: models.User{
+				Email: "spidey@nyork.co",
+			},
+			expectedError: entities.NewValidationError(
+				"email can only be changed by confirming the new address",
+			),
 		},
 		{
 			description: "Error updating attributes by user id",
@@ -154,24 +296,54 @@ func (s *userServiceTestSuite) TestUpdateProfile() {
 			},
 			updateAttributesByUserIdError: errors.New("error"),
 		},
+		{
+			description: "Error recording audit event",
+			attributes: models.User{
+				Name: "John Doe",
+			},
+			recordError: errors.New("error recording audit event"),
+		},
 	}
 
 	for _, test := range tests {
 		s.Run(test.description, func() {
+			authUser := user
+			if test.user != nil {
+				authUser = test.user
+			}
 			ctx := context.Background()
-			ctx = context.WithValue(ctx, common.AuthUser, user)
+			ctx = context.WithValue(ctx, common.AuthUser, authUser)
 
 			var attributesCopy interface{} = test.attributes
-			if test.updatingPassword {
-				attributesCopy = gomock.Any()
+			if test.expectedAttributes != nil {
+				attributesCopy = *test.expectedAttributes
+			}
+
+			if test.expectedError != nil {
+				err := s.service.UpdateProfile(ctx, test.attributes, 2)
+				s.Equal(test.expectedError, err)
+				return
 			}
 
 			s.userRepositoryMock.EXPECT().UpdateAttributesByUserId(
-				ctx, userId.String(), attributesCopy,
+				ctx, userId.String(), attributesCopy, int64(2),
 			).Return(test.updateAttributesByUserIdError)
+			if test.updateAttributesByUserIdError == nil {
+				s.outboxEventRepositoryMock.EXPECT().Create(ctx, outboxEventsOf(
+					entities.UserProfileUpdated{UserID: userId.String(), Fields: []string{"name"}},
+				)).Return(nil)
+				s.auditServiceMock.EXPECT().RecordChanges(
+					ctx,
+					AuditActionUserProfileUpdated,
+					"user",
+					userId.String(),
+					authUser,
+					map[string]interface{}{"name": "John Doe"},
+				).Return(test.recordError)
+			}
 
-			err := s.service.UpdateProfile(ctx, test.attributes)
-			if test.updateAttributesByUserIdError != nil {
+			err := s.service.UpdateProfile(ctx, test.attributes, 2)
+			if test.updateAttributesByUserIdError != nil || test.recordError != nil {
 				s.Error(err)
 			} else {
 				s.NoError(err)
@@ -180,15 +352,162 @@ func (s *userServiceTestSuite) TestUpdateProfile() {
 	}
 }
 
-func (s *userServiceTestSuite) TestDeleteById() {
+func (s *userServiceTestSuite) TestPatchProfile() {
 	userId := uuid.New()
-	ctx := context.Background()
+	user := &models.User{ID: userId, Address: "Queens"}
+	ctx := context.WithValue(context.Background(), common.AuthUser, user)
+	changes := map[string]interface{}{"address": ""}
 
-	s.userRepositoryMock.EXPECT().UpdateAttributesByUserId(
-		ctx, userId.String(), gomock.Any(),
+	s.userRepositoryMock.EXPECT().UpdateColumnsByUserId(
+		ctx, userId.String(), changes, int64(4),
+	).Return(nil)
+	s.outboxEventRepositoryMock.EXPECT().Create(ctx, outboxEventsOf(
+		entities.UserProfileUpdated{UserID: userId.String(), Fields: []string{"address"}},
+	)).Return(nil)
+	s.auditServiceMock.EXPECT().RecordChanges(
+		ctx, AuditActionUserProfileUpdated, "user", userId.String(), user, changes,
 	).Return(nil)
 
-	err := s.service.DeleteById(ctx, userId.String())
+	err := s.service.PatchProfile(ctx, changes, 4)
 
 	s.NoError(err)
+
+	err = s.service.PatchProfile(ctx, map[string]interface{}{"email": "spidey@nyork.co"}, 4)
+
+	s.Equal(
+		entities.NewValidationError("email can only be changed by confirming the new address"),
+		err,
+	)
+}
+
+func (s *userServiceTestSuite) TestRequestEmailChange() {
+	user := &models.User{
+		ID:      uuid.New(),
+		Name:    "Peter Parker",
+		Email:   "peter.parker@nyork.co",
+		Version: 5,
+	}
+	ctx := context.WithValue(context.Background(), common.AuthUser, user)
+
+	tests := []struct {
+		description       string
+		foundUser         *models.User
+		findByEmailError  error
+		confirmationError error
+		expectedError     error
+	}{
+		{
+			description: "Success",
+		},
+		{
+			description:   "Email already in use",
+			foundUser:     &models.User{ID: uuid.New()},
+			expectedError: entities.NewEmailAlreadyInUseError("spidey@nyork.co"),
+		},
+		{
+			description:      "Error finding user by email",
+			findByEmailError: errors.New("error finding user"),
+			expectedError:    errors.New("error finding user"),
+		},
+		{
+			description:       "Error sending confirmation",
+			confirmationError: errors.New("error sending email"),
+			expectedError:     errors.New("error sending email"),
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			s.userRepositoryMock.EXPECT().FindByEmail(
+				ctx, "spidey@nyork.co",
+			).Return(test.foundUser, test.findByEmailError)
+
+			var sent []providers.Email
+			if test.foundUser == nil && test.findByEmailError == nil {
+				s.mailerMock.EXPECT().Send(ctx, gomock.Any()).DoAndReturn(
+					func(ctx context.Context, email providers.Email) error {
+						sent = append(sent, email)
+						return test.confirmationError
+					},
+				)
+			}
+			if test.expectedError == nil {
+				s.mailerMock.EXPECT().Send(ctx, gomock.Any()).DoAndReturn(
+					func(ctx context.Context, email providers.Email) error {
+						sent = append(sent, email)
+						return nil
+					},
+				)
+				s.auditServiceMock.EXPECT().Record(
+					ctx, AuditActionUserEmailChangeRequested, "user", user.ID.String(), nil,
+				).Return(nil)
+			}
+
+			err := s.service.RequestEmailChange(ctx, "spidey@nyork.co")
+
+			s.Equal(test.expectedError, err)
+			if test.expectedError != nil {
+				return
+			}
+
+			s.Len(sent, 2)
+			s.Equal("spidey@nyork.co", sent[0].To)
+			token := sent[0].Body[strings.Index(sent[0].Body, "?token=")+7 : len(sent[0].Body)-1]
+			userId, email, version, err := parseEmailChangeToken(token)
+			s.NoError(err)
+			s.Equal(user.ID.String(), userId)
+			s.Equal("spidey@nyork.co", email)
+			s.Equal(int64(5), version)
+
+			s.Equal("peter.parker@nyork.co", sent[1].To)
+			s.Contains(sent[1].Body, "spidey@nyork.co")
+			s.NotContains(sent[1].Body, token)
+		})
+	}
+}
+
+func (s *userServiceTestSuite) TestDeleteById() {
+	userId := uuid.New()
+	ctx := context.Background()
+
+	tests := []struct {
+		description   string
+		deleted       bool
+		deleteError   error
+		expectedError error
+	}{
+		{
+			description: "Success",
+			deleted:     true,
+		},
+		{
+			description:   "User not found",
+			expectedError: entities.NewItemNotFoundError("User", userId.String()),
+		},
+		{
+			description:   "Error deleting user",
+			deleteError:   errors.New("error deleting user"),
+			expectedError: errors.New("error deleting user"),
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			s.userRepositoryMock.EXPECT().DeleteById(
+				ctx, userId.String(),
+			).Return(test.deleted, test.deleteError)
+			if test.deleted {
+				s.outboxEventRepositoryMock.EXPECT().Create(ctx, outboxEventsOf(
+					entities.UserDeleted{UserID: userId.String()},
+				)).Return(nil)
+				s.auditServiceMock.EXPECT().Record(
+					ctx, AuditActionUserDeleted, "user", userId.String(), nil,
+				).Return(nil)
+			}
+
+			err := s.service.DeleteById(ctx, userId.String())
+
+			s.Equal(test.expectedError, err)
+		})
+	}
 }
@@ -0,0 +1,284 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"verifymy-golang-test/entities"
+	"verifymy-golang-test/models"
+	"verifymy-golang-test/repositories"
+)
+
+const (
+	WebhookSignatureHeader = "X-Webhook-Signature"
+	WebhookTimestampHeader = "X-Webhook-Timestamp"
+	WebhookDeliveryHeader  = "X-Webhook-Delivery"
+
+	defaultWebhookMaxAttempts = 8
+	defaultWebhookRetryDelay  = time.Second * 30
+	// maxWebhookRetryDelay caps the exponential backoff
+	maxWebhookRetryDelay = time.Hour * 6
+	// webhookDeliveryBatchSize is how many deliveries are attempted per run
+	webhookDeliveryBatchSize = 100
+	// webhookDeliveriesListLimit is how many deliveries of a subscription
+	// are listed, newest first
+	webhookDeliveriesListLimit = 100
+)
+
+// WebhookDeliveryService pushes the deliveries queued by the subscriptions
+// event sink to the webhooks, see NewWebhookSubscriptionsEventSink
+type WebhookDeliveryService interface {
+	FindBySubscriptionId(
+		ctx context.Context, subscriptionId string, status string,
+	) ([]models.WebhookDelivery, error)
+	Redeliver(ctx context.Context, subscriptionId string, deliveryId string) error
+	DeliverDue(ctx context.Context) (int64, error)
+}
+
+// NewWebhookDeliveryService gives up on a delivery after
+// WEBHOOK_MAX_ATTEMPTS attempts, 8 by default, waiting WEBHOOK_RETRY_DELAY,
+// 30 seconds by default, before the first retry and twice as long before
+// each following one.
+func NewWebhookDeliveryService(
+	transactor repositories.Transactor,
+	webhookSubscriptionRepository repositories.WebhookSubscriptionRepository,
+	webhookDeliveryRepository repositories.WebhookDeliveryRepository,
+	auditService AuditService,
+) (WebhookDeliveryService, error) {
+	maxAttempts := defaultWebhookMaxAttempts
+	if value := os.Getenv("WEBHOOK_MAX_ATTEMPTS"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 {
+			return nil, fmt.Errorf("invalid WEBHOOK_MAX_ATTEMPTS %q", value)
+		}
+
+		maxAttempts = parsed
+	}
+
+	retryDelay := defaultWebhookRetryDelay
+	if value := os.Getenv("WEBHOOK_RETRY_DELAY"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed <= 0 {
+			return nil, fmt.Errorf("invalid WEBHOOK_RETRY_DELAY %q", value)
+		}
+
+		retryDelay = parsed
+	}
+
+	return &webhookDeliveryService{
+		transactor:                    transactor,
+		webhookSubscriptionRepository: webhookSubscriptionRepository,
+		webhookDeliveryRepository:     webhookDeliveryRepository,
+		auditService:                  auditService,
+		client:                        &http.Client{Timeout: webhookTimeout},
+		maxAttempts:                   maxAttempts,
+		retryDelay:                    retryDelay,
+	}, nil
+}
+
+type webhookDeliveryService struct {
+	transactor                    repositories.Transactor
+	webhookSubscriptionRepository repositories.WebhookSubscriptionRepository
+	webhookDeliveryRepository     repositories.WebhookDeliveryRepository
+	auditService                  AuditService
+	client                        *http.Client
+	maxAttempts                   int
+	retryDelay                    time.Duration
+}
+
+func (s *webhookDeliveryService) FindBySubscriptionId(
+	ctx context.Context, subscriptionId string, status string,
+) ([]models.WebhookDelivery, error) {
+	subscription, err := s.webhookSubscriptionRepository.FindById(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	} else if subscription == nil {
+		return nil, entities.NewItemNotFoundError("Webhook", subscriptionId)
+	}
+
+	return s.webhookDeliveryRepository.FindBySubscriptionId(
+		ctx, subscriptionId, status, webhookDeliveriesListLimit,
+	)
+}
+
+// Redeliver queues a delivery again, with a fresh set of attempts, whatever
+// its status
+func (s *webhookDeliveryService) Redeliver(
+	ctx context.Context, subscriptionId string, deliveryId string,
+) error {
+	delivery, err := s.webhookDeliveryRepository.FindById(ctx, deliveryId)
+	if err != nil {
+		return err
+	} else if delivery == nil || delivery.SubscriptionID != subscriptionId {
+		return entities.NewItemNotFoundError("Webhook delivery", deliveryId)
+	}
+
+	delivery.Status = models.WebhookDeliveryStatusPending
+	delivery.AttemptCount = 0
+	delivery.NextAttemptAt = time.Now().UTC()
+	if err := s.webhookDeliveryRepository.Update(ctx, *delivery); err != nil {
+		return err
+	}
+
+	return s.auditService.Record(
+		ctx,
+		AuditActionWebhookRedelivered,
+		auditTargetWebhook,
+		subscriptionId,
+		map[string]interface{}{"delivery_id": deliveryId, "event_id": delivery.EventID},
+	)
+}
+
+// DeliverDue attempts the deliveries which are due and returns how many
+// succeeded. Failed attempts are logged and retried later rather than
+// reported, so one unreachable webhook does not hold the others.
+func (s *webhookDeliveryService) DeliverDue(ctx context.Context) (int64, error) {
+	deliveries, err := s.webhookDeliveryRepository.FindDue(
+		ctx, time.Now().UTC(), webhookDeliveryBatchSize,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	subscriptions := map[string]*models.WebhookSubscription{}
+	var succeeded int64
+	for _, delivery := range deliveries {
+		subscription, ok := subscriptions[delivery.SubscriptionID]
+		if !ok {
+			subscription, err = s.webhookSubscriptionRepository.FindById(
+				ctx, delivery.SubscriptionID,
+			)
+			if err != nil {
+				return succeeded, err
+			}
+
+			subscriptions[delivery.SubscriptionID] = subscription
+		}
+		// the subscription was deleted since the delivery was found
+		if subscription == nil {
+			continue
+		}
+
+		delivered, err := s.attempt(ctx, subscription, delivery)
+		if err != nil {
+			return succeeded, err
+		} else if delivered {
+			succeeded++
+		}
+	}
+
+	return succeeded, nil
+}
+
+// attempt sends the delivery and logs the attempt along with the outcome
+func (s *webhookDeliveryService) attempt(
+	ctx context.Context, subscription *models.WebhookSubscription, delivery models.WebhookDelivery,
+) (bool, error) {
+	startedAt := time.Now().UTC()
+	statusCode, sendErr := s.send(ctx, subscription, delivery, startedAt)
+
+	attempt := models.WebhookDeliveryAttempt{
+		DeliveryID: delivery.ID.String(),
+		StatusCode: statusCode,
+		DurationMs: time.Since(startedAt).Milliseconds(),
+		CreatedAt:  startedAt,
+	}
+
+	delivery.AttemptCount++
+	if sendErr == nil {
+		delivery.Status = models.WebhookDeliveryStatusSucceeded
+		delivery.DeliveredAt.Time, delivery.DeliveredAt.Valid = time.Now().UTC(), true
+	} else {
+		attempt.Error = sendErr.Error()
+		if delivery.AttemptCount >= s.maxAttempts {
+			delivery.Status = models.WebhookDeliveryStatusDead
+		} else {
+			delivery.NextAttemptAt = startedAt.Add(s.backoff(delivery.AttemptCount))
+		}
+	}
+
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.webhookDeliveryRepository.CreateAttempt(ctx, attempt); err != nil {
+			return err
+		}
+
+		return s.webhookDeliveryRepository.Update(ctx, delivery)
+	})
+
+	return sendErr == nil, err
+}
+
+// backoff is how long to wait after the attempt-th failed attempt
+func (s *webhookDeliveryService) backoff(attempt int) time.Duration {
+	delay := s.retryDelay
+	for i := 1; i < attempt && delay < maxWebhookRetryDelay; i++ {
+		delay *= 2
+	}
+
+	if delay > maxWebhookRetryDelay {
+		return maxWebhookRetryDelay
+	}
+
+	return delay
+}
+
+// send posts the payload of the delivery signed with the secret of the
+// subscription, see webhookSignature. It fails unless the webhook answers
+// with a 2xx status.
+func (s *webhookDeliveryService) send(
+	ctx context.Context,
+	subscription *models.WebhookSubscription,
+	delivery models.WebhookDelivery,
+	now time.Time,
+) (int, error) {
+	body := []byte(delivery.Payload)
+	request, err := http.NewRequestWithContext(
+		ctx, http.MethodPost, subscription.URL, bytes.NewReader(body),
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := now.Unix()
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("X-Event-ID", delivery.EventID)
+	request.Header.Set("X-Event-Type", delivery.EventType)
+	request.Header.Set(WebhookDeliveryHeader, delivery.ID.String())
+	request.Header.Set(WebhookTimestampHeader, strconv.FormatInt(timestamp, 10))
+	request.Header.Set(
+		WebhookSignatureHeader,
+		"sha256="+webhookSignature(string(subscription.Secret), timestamp, body),
+	)
+
+	response, err := s.client.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return response.StatusCode, fmt.Errorf("webhook answered %d", response.StatusCode)
+	}
+
+	return response.StatusCode, nil
+}
+
+// webhookSignature is the hex encoded HMAC-SHA256, keyed by the secret, of
+// the timestamp and the body joined by a dot. Receivers recompute it to
+// check the delivery is genuine, and reject stale timestamps to prevent
+// replays.
+func webhookSignature(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "."))
+	mac.Write(body)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
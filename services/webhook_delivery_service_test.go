@@ -0,0 +1,346 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	"verifymy-golang-test/entities"
+	mock_repositories "verifymy-golang-test/mocks/repositories"
+	mock_services "verifymy-golang-test/mocks/services"
+	"verifymy-golang-test/models"
+)
+
+type webhookDeliveryServiceTestSuite struct {
+	suite.Suite
+	ctrl                              *gomock.Controller
+	ctx                               context.Context
+	webhookSubscriptionRepositoryMock *mock_repositories.MockWebhookSubscriptionRepository
+	webhookDeliveryRepositoryMock     *mock_repositories.MockWebhookDeliveryRepository
+	auditServiceMock                  *mock_services.MockAuditService
+	service                           *webhookDeliveryService
+}
+
+func TestWebhookDeliveryServiceTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(webhookDeliveryServiceTestSuite))
+}
+
+func (s *webhookDeliveryServiceTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.ctx = context.Background()
+	s.webhookSubscriptionRepositoryMock = mock_repositories.NewMockWebhookSubscriptionRepository(s.ctrl)
+	s.webhookDeliveryRepositoryMock = mock_repositories.NewMockWebhookDeliveryRepository(s.ctrl)
+	s.auditServiceMock = mock_services.NewMockAuditService(s.ctrl)
+
+	transactorMock := mock_repositories.NewMockTransactor(s.ctrl)
+	transactorMock.EXPECT().WithinTransaction(
+		gomock.Any(), gomock.Any(),
+	).DoAndReturn(runWithinTransaction).AnyTimes()
+
+	service, err := NewWebhookDeliveryService(
+		transactorMock,
+		s.webhookSubscriptionRepositoryMock,
+		s.webhookDeliveryRepositoryMock,
+		s.auditServiceMock,
+	)
+	s.Require().NoError(err)
+	s.service = service.(*webhookDeliveryService)
+}
+
+func (s *webhookDeliveryServiceTestSuite) TestNewWebhookDeliveryService() {
+	s.Equal(8, s.service.maxAttempts)
+	s.Equal(time.Second*30, s.service.retryDelay)
+}
+
+func (s *webhookDeliveryServiceTestSuite) TestBackoff() {
+	s.Equal(time.Second*30, s.service.backoff(1))
+	s.Equal(time.Minute, s.service.backoff(2))
+	s.Equal(time.Minute*2, s.service.backoff(3))
+	s.Equal(time.Hour*6, s.service.backoff(20))
+	s.Equal(time.Hour*6, s.service.backoff(1000))
+}
+
+func (s *webhookDeliveryServiceTestSuite) TestDeliverDue() {
+	secret := "a-very-long-secret"
+	payload := `{"id":"event-id","type":"user.registered"}`
+
+	tests := []struct {
+		description          string
+		status               int
+		attemptCount         int
+		expectedStatus       string
+		expectedAttemptCount int
+		expectedError        string
+		expectedRetry        bool
+	}{
+		{
+			description:          "Success",
+			status:               http.StatusOK,
+			expectedStatus:       models.WebhookDeliveryStatusSucceeded,
+			expectedAttemptCount: 1,
+		},
+		{
+			description:          "Failure is retried",
+			status:               http.StatusInternalServerError,
+			attemptCount:         2,
+			expectedStatus:       models.WebhookDeliveryStatusPending,
+			expectedAttemptCount: 3,
+			expectedError:        "webhook answered 500",
+			expectedRetry:        true,
+		},
+		{
+			description:          "Last failure is dead",
+			status:               http.StatusGone,
+			attemptCount:         7,
+			expectedStatus:       models.WebhookDeliveryStatusDead,
+			expectedAttemptCount: 8,
+			expectedError:        "webhook answered 410",
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			s.SetupTest()
+
+			delivery := models.WebhookDelivery{
+				ID:             uuid.New(),
+				SubscriptionID: uuid.NewString(),
+				EventID:        "event-id",
+				EventType:      "user.registered",
+				Payload:        payload,
+				Status:         models.WebhookDeliveryStatusPending,
+				AttemptCount:   test.attemptCount,
+			}
+
+			receiver := httptest.NewServer(http.HandlerFunc(
+				func(w http.ResponseWriter, r *http.Request) {
+					body, err := io.ReadAll(r.Body)
+					s.NoError(err)
+					s.Equal(payload, string(body))
+					s.Equal("application/json", r.Header.Get("Content-Type"))
+					s.Equal("event-id", r.Header.Get("X-Event-ID"))
+					s.Equal("user.registered", r.Header.Get("X-Event-Type"))
+					s.Equal(delivery.ID.String(), r.Header.Get("X-Webhook-Delivery"))
+
+					timestamp, err := strconv.ParseInt(r.Header.Get("X-Webhook-Timestamp"), 10, 64)
+					s.NoError(err)
+					s.WithinDuration(time.Now(), time.Unix(timestamp, 0), time.Minute)
+
+					mac := hmac.New(sha256.New, []byte(secret))
+					mac.Write([]byte(r.Header.Get("X-Webhook-Timestamp") + "." + string(body)))
+					s.Equal(
+						"sha256="+hex.EncodeToString(mac.Sum(nil)),
+						r.Header.Get("X-Webhook-Signature"),
+					)
+
+					w.WriteHeader(test.status)
+				},
+			))
+			defer receiver.Close()
+
+			s.webhookDeliveryRepositoryMock.EXPECT().FindDue(
+				s.ctx, gomock.Any(), webhookDeliveryBatchSize,
+			).Return([]models.WebhookDelivery{delivery}, nil)
+			s.webhookSubscriptionRepositoryMock.EXPECT().FindById(
+				s.ctx, delivery.SubscriptionID,
+			).Return(&models.WebhookSubscription{
+				URL: receiver.URL, Secret: models.SecretValue(secret),
+			}, nil)
+			s.webhookDeliveryRepositoryMock.EXPECT().CreateAttempt(s.ctx, gomock.Any()).DoAndReturn(
+				func(ctx context.Context, attempt models.WebhookDeliveryAttempt) error {
+					s.Equal(delivery.ID.String(), attempt.DeliveryID)
+					s.Equal(test.status, attempt.StatusCode)
+					s.Equal(test.expectedError, attempt.Error)
+					s.False(attempt.CreatedAt.IsZero())
+
+					return nil
+				},
+			)
+			s.webhookDeliveryRepositoryMock.EXPECT().Update(s.ctx, gomock.Any()).DoAndReturn(
+				func(ctx context.Context, updated models.WebhookDelivery) error {
+					s.Equal(test.expectedStatus, updated.Status)
+					s.Equal(test.expectedAttemptCount, updated.AttemptCount)
+					s.Equal(test.expectedStatus == models.WebhookDeliveryStatusSucceeded, updated.DeliveredAt.Valid)
+					if test.expectedRetry {
+						s.WithinDuration(
+							time.Now().Add(s.service.backoff(test.expectedAttemptCount)),
+							updated.NextAttemptAt,
+							time.Second*5,
+						)
+					}
+
+					return nil
+				},
+			)
+
+			succeeded, err := s.service.DeliverDue(s.ctx)
+
+			s.NoError(err)
+			if test.expectedStatus == models.WebhookDeliveryStatusSucceeded {
+				s.Equal(int64(1), succeeded)
+			} else {
+				s.Zero(succeeded)
+			}
+		})
+	}
+}
+
+func (s *webhookDeliveryServiceTestSuite) TestDeliverDueUnreachable() {
+	receiver := httptest.NewServer(http.NotFoundHandler())
+	receiver.Close()
+	delivery := models.WebhookDelivery{ID: uuid.New(), SubscriptionID: uuid.NewString()}
+
+	s.webhookDeliveryRepositoryMock.EXPECT().FindDue(
+		s.ctx, gomock.Any(), webhookDeliveryBatchSize,
+	).Return([]models.WebhookDelivery{delivery, delivery}, nil)
+	s.webhookSubscriptionRepositoryMock.EXPECT().FindById(
+		s.ctx, delivery.SubscriptionID,
+	).Return(&models.WebhookSubscription{URL: receiver.URL}, nil)
+	s.webhookDeliveryRepositoryMock.EXPECT().CreateAttempt(s.ctx, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, attempt models.WebhookDeliveryAttempt) error {
+			s.Zero(attempt.StatusCode)
+			s.NotEmpty(attempt.Error)
+
+			return nil
+		},
+	).Times(2)
+	s.webhookDeliveryRepositoryMock.EXPECT().Update(s.ctx, gomock.Any()).Return(nil).Times(2)
+
+	succeeded, err := s.service.DeliverDue(s.ctx)
+
+	s.NoError(err)
+	s.Zero(succeeded)
+}
+
+func (s *webhookDeliveryServiceTestSuite) TestDeliverDueDeletedSubscription() {
+	delivery := models.WebhookDelivery{ID: uuid.New(), SubscriptionID: uuid.NewString()}
+
+	s.webhookDeliveryRepositoryMock.EXPECT().FindDue(
+		s.ctx, gomock.Any(), webhookDeliveryBatchSize,
+	).Return([]models.WebhookDelivery{delivery}, nil)
+	s.webhookSubscriptionRepositoryMock.EXPECT().FindById(
+		s.ctx, delivery.SubscriptionID,
+	).Return(nil, nil)
+
+	succeeded, err := s.service.DeliverDue(s.ctx)
+
+	s.NoError(err)
+	s.Zero(succeeded)
+}
+
+func (s *webhookDeliveryServiceTestSuite) TestRedeliver() {
+	subscriptionId := uuid.NewString()
+	delivery := &models.WebhookDelivery{
+		ID:             uuid.New(),
+		SubscriptionID: subscriptionId,
+		EventID:        "event-id",
+		Status:         models.WebhookDeliveryStatusDead,
+		AttemptCount:   8,
+	}
+
+	tests := []struct {
+		description      string
+		subscriptionId   string
+		findByIdResponse *models.WebhookDelivery
+		findByIdError    error
+		expectedError    error
+	}{
+		{
+			description:      "Success",
+			subscriptionId:   subscriptionId,
+			findByIdResponse: delivery,
+		},
+		{
+			description:    "Delivery not found",
+			subscriptionId: subscriptionId,
+			expectedError:  entities.NewItemNotFoundError("Webhook delivery", delivery.ID.String()),
+		},
+		{
+			description:      "Delivery of another webhook",
+			subscriptionId:   uuid.NewString(),
+			findByIdResponse: delivery,
+			expectedError:    entities.NewItemNotFoundError("Webhook delivery", delivery.ID.String()),
+		},
+		{
+			description:    "Error finding delivery",
+			subscriptionId: subscriptionId,
+			findByIdError:  errors.New("error finding delivery"),
+			expectedError:  errors.New("error finding delivery"),
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			s.SetupTest()
+
+			var found *models.WebhookDelivery
+			if test.findByIdResponse != nil {
+				copied := *test.findByIdResponse
+				found = &copied
+			}
+			s.webhookDeliveryRepositoryMock.EXPECT().FindById(
+				s.ctx, delivery.ID.String(),
+			).Return(found, test.findByIdError)
+			if test.expectedError == nil {
+				s.webhookDeliveryRepositoryMock.EXPECT().Update(s.ctx, gomock.Any()).DoAndReturn(
+					func(ctx context.Context, updated models.WebhookDelivery) error {
+						s.Equal(models.WebhookDeliveryStatusPending, updated.Status)
+						s.Zero(updated.AttemptCount)
+						s.WithinDuration(time.Now(), updated.NextAttemptAt, time.Second*5)
+
+						return nil
+					},
+				)
+				s.auditServiceMock.EXPECT().Record(
+					s.ctx,
+					AuditActionWebhookRedelivered,
+					"webhook",
+					subscriptionId,
+					map[string]interface{}{
+						"delivery_id": delivery.ID.String(), "event_id": "event-id",
+					},
+				).Return(nil)
+			}
+
+			err := s.service.Redeliver(s.ctx, test.subscriptionId, delivery.ID.String())
+
+			s.Equal(test.expectedError, err)
+		})
+	}
+}
+
+func (s *webhookDeliveryServiceTestSuite) TestFindBySubscriptionId() {
+	subscriptionId := uuid.NewString()
+	deliveries := []models.WebhookDelivery{{ID: uuid.New()}}
+
+	s.webhookSubscriptionRepositoryMock.EXPECT().FindById(s.ctx, subscriptionId).Return(
+		&models.WebhookSubscription{}, nil,
+	)
+	s.webhookDeliveryRepositoryMock.EXPECT().FindBySubscriptionId(
+		s.ctx, subscriptionId, models.WebhookDeliveryStatusDead, webhookDeliveriesListLimit,
+	).Return(deliveries, nil)
+
+	found, err := s.service.FindBySubscriptionId(
+		s.ctx, subscriptionId, models.WebhookDeliveryStatusDead,
+	)
+	s.NoError(err)
+	s.Equal(deliveries, found)
+
+	s.webhookSubscriptionRepositoryMock.EXPECT().FindById(s.ctx, subscriptionId).Return(nil, nil)
+
+	_, err = s.service.FindBySubscriptionId(s.ctx, subscriptionId, "")
+	s.Equal(entities.NewItemNotFoundError("Webhook", subscriptionId), err)
+}
@@ -0,0 +1,243 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+
+	"verifymy-golang-test/entities"
+	"verifymy-golang-test/models"
+	"verifymy-golang-test/repositories"
+)
+
+const (
+	minWebhookSecretLength = 16
+	maxWebhookSecretLength = 255
+	maxWebhookURLLength    = 2048
+)
+
+// WebhookSubscriptionService manages the webhooks partner systems are
+// pushed domain events through. Every change leaves an audit record.
+type WebhookSubscriptionService interface {
+	Create(
+		ctx context.Context, changes entities.WebhookSubscriptionChanges,
+	) (*models.WebhookSubscription, error)
+	FindAll(ctx context.Context) ([]models.WebhookSubscription, error)
+	FindById(ctx context.Context, id string) (*models.WebhookSubscription, error)
+	Update(
+		ctx context.Context, id string, changes entities.WebhookSubscriptionChanges,
+	) (*models.WebhookSubscription, error)
+	DeleteById(ctx context.Context, id string) error
+}
+
+func NewWebhookSubscriptionService(
+	transactor repositories.Transactor,
+	webhookSubscriptionRepository repositories.WebhookSubscriptionRepository,
+	webhookDeliveryRepository repositories.WebhookDeliveryRepository,
+	auditService AuditService,
+) WebhookSubscriptionService {
+	return &webhookSubscriptionService{
+		transactor:                    transactor,
+		webhookSubscriptionRepository: webhookSubscriptionRepository,
+		webhookDeliveryRepository:     webhookDeliveryRepository,
+		auditService:                  auditService,
+	}
+}
+
+type webhookSubscriptionService struct {
+	transactor                    repositories.Transactor
+	webhookSubscriptionRepository repositories.WebhookSubscriptionRepository
+	webhookDeliveryRepository     repositories.WebhookDeliveryRepository
+	auditService                  AuditService
+}
+
+// Create requires a URL and event types. A secret is generated when none is
+// given; either way it is only ever returned here.
+func (s *webhookSubscriptionService) Create(
+	ctx context.Context, changes entities.WebhookSubscriptionChanges,
+) (*models.WebhookSubscription, error) {
+	if changes.URL == nil {
+		return nil, entities.NewValidationError("url is required")
+	} else if changes.EventTypes == nil {
+		return nil, entities.NewValidationError("event_types is required")
+	}
+
+	if changes.Secret == nil {
+		secret, err := newWebhookSecret()
+		if err != nil {
+			return nil, err
+		}
+
+		changes.Secret = &secret
+	}
+
+	attributes, err := webhookSubscriptionAttributes(changes)
+	if err != nil {
+		return nil, err
+	}
+
+	subscription, err := s.webhookSubscriptionRepository.Create(ctx, models.WebhookSubscription{
+		URL:        attributes["url"].(string),
+		EventTypes: attributes["event_types"].(models.StringList),
+		Secret:     attributes["secret"].(models.SecretValue),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.auditService.Record(
+		ctx,
+		AuditActionWebhookCreated,
+		auditTargetWebhook,
+		subscription.ID.String(),
+		map[string]interface{}{"url": subscription.URL, "event_types": subscription.EventTypes},
+	); err != nil {
+		return nil, err
+	}
+
+	return subscription, nil
+}
+
+func (s *webhookSubscriptionService) FindAll(
+	ctx context.Context,
+) ([]models.WebhookSubscription, error) {
+	return s.webhookSubscriptionRepository.FindAll(ctx)
+}
+
+func (s *webhookSubscriptionService) FindById(
+	ctx context.Context, id string,
+) (*models.WebhookSubscription, error) {
+	subscription, err := s.webhookSubscriptionRepository.FindById(ctx, id)
+	if err != nil {
+		return nil, err
+	} else if subscription == nil {
+		return nil, entities.NewItemNotFoundError("Webhook", id)
+	}
+
+	return subscription, nil
+}
+
+// Update applies to deliveries still to be attempted, which are sent to the
+// new URL and signed with the new secret
+func (s *webhookSubscriptionService) Update(
+	ctx context.Context, id string, changes entities.WebhookSubscriptionChanges,
+) (*models.WebhookSubscription, error) {
+	subscription, err := s.FindById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	attributes, err := webhookSubscriptionAttributes(changes)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := *subscription
+	if value, ok := attributes["url"]; ok {
+		updated.URL = value.(string)
+	}
+	if value, ok := attributes["event_types"]; ok {
+		updated.EventTypes = value.(models.StringList)
+	}
+	if value, ok := attributes["secret"]; ok {
+		updated.Secret = value.(models.SecretValue)
+	}
+
+	if err := s.webhookSubscriptionRepository.Update(ctx, updated); err != nil {
+		return nil, err
+	}
+
+	if err := s.auditService.RecordChanges(
+		ctx, AuditActionWebhookUpdated, auditTargetWebhook, id, subscription, attributes,
+	); err != nil {
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+// DeleteById deletes the subscription along with its deliveries, so pending
+// ones are not attempted anymore
+func (s *webhookSubscriptionService) DeleteById(ctx context.Context, id string) error {
+	if err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.webhookDeliveryRepository.DeleteBySubscriptionId(ctx, id); err != nil {
+			return err
+		}
+
+		deleted, err := s.webhookSubscriptionRepository.DeleteById(ctx, id)
+		if err != nil {
+			return err
+		} else if !deleted {
+			return entities.NewItemNotFoundError("Webhook", id)
+		}
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	return s.auditService.Record(ctx, AuditActionWebhookDeleted, auditTargetWebhook, id, nil)
+}
+
+// webhookSubscriptionAttributes validates changes and returns them keyed by
+// column
+func webhookSubscriptionAttributes(
+	changes entities.WebhookSubscriptionChanges,
+) (map[string]interface{}, error) {
+	attributes := map[string]interface{}{}
+
+	if changes.URL != nil {
+		parsed, err := url.Parse(*changes.URL)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") ||
+			parsed.Host == "" || len(*changes.URL) > maxWebhookURLLength {
+			return nil, entities.NewValidationError("url must be an absolute http or https URL")
+		}
+
+		attributes["url"] = *changes.URL
+	}
+
+	if changes.EventTypes != nil {
+		eventTypes := models.StringList{}
+		for _, eventType := range *changes.EventTypes {
+			if !models.StringList(entities.WebhookEventTypes).Includes(eventType) {
+				return nil, entities.NewValidationError(fmt.Sprintf(
+					"event_types must only hold %v", entities.WebhookEventTypes,
+				))
+			}
+
+			if !eventTypes.Includes(eventType) {
+				eventTypes = append(eventTypes, eventType)
+			}
+		}
+		if len(eventTypes) == 0 {
+			return nil, entities.NewValidationError("event_types must not be empty")
+		}
+
+		attributes["event_types"] = eventTypes
+	}
+
+	if changes.Secret != nil {
+		if len(*changes.Secret) < minWebhookSecretLength ||
+			len(*changes.Secret) > maxWebhookSecretLength {
+			return nil, entities.NewValidationError(fmt.Sprintf(
+				"secret must be between %d and %d characters long",
+				minWebhookSecretLength, maxWebhookSecretLength,
+			))
+		}
+
+		attributes["secret"] = models.SecretValue(*changes.Secret)
+	}
+
+	return attributes, nil
+}
+
+func newWebhookSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(secret), nil
+}
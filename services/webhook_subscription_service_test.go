@@ -0,0 +1,294 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	"verifymy-golang-test/entities"
+	mock_repositories "verifymy-golang-test/mocks/repositories"
+	mock_services "verifymy-golang-test/mocks/services"
+	"verifymy-golang-test/models"
+)
+
+type webhookSubscriptionServiceTestSuite struct {
+	suite.Suite
+	ctrl                              *gomock.Controller
+	ctx                               context.Context
+	webhookSubscriptionRepositoryMock *mock_repositories.MockWebhookSubscriptionRepository
+	webhookDeliveryRepositoryMock     *mock_repositories.MockWebhookDeliveryRepository
+	auditServiceMock                  *mock_services.MockAuditService
+	service                           WebhookSubscriptionService
+}
+
+func TestWebhookSubscriptionServiceTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(webhookSubscriptionServiceTestSuite))
+}
+
+func (s *webhookSubscriptionServiceTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.ctx = context.Background()
+	s.webhookSubscriptionRepositoryMock = mock_repositories.NewMockWebhookSubscriptionRepository(s.ctrl)
+	s.webhookDeliveryRepositoryMock = mock_repositories.NewMockWebhookDeliveryRepository(s.ctrl)
+	s.auditServiceMock = mock_services.NewMockAuditService(s.ctrl)
+
+	transactorMock := mock_repositories.NewMockTransactor(s.ctrl)
+	transactorMock.EXPECT().WithinTransaction(
+		gomock.Any(), gomock.Any(),
+	).DoAndReturn(runWithinTransaction).AnyTimes()
+
+	s.service = NewWebhookSubscriptionService(
+		transactorMock,
+		s.webhookSubscriptionRepositoryMock,
+		s.webhookDeliveryRepositoryMock,
+		s.auditServiceMock,
+	)
+}
+
+func stringPointer(value string) *string {
+	return &value
+}
+
+func (s *webhookSubscriptionServiceTestSuite) TestCreate() {
+	eventTypes := []string{"user.registered", "user.deleted", "user.registered"}
+
+	tests := []struct {
+		description    string
+		changes        entities.WebhookSubscriptionChanges
+		expectedSecret string
+		expectedError  string
+	}{
+		{
+			description: "Success",
+			changes: entities.WebhookSubscriptionChanges{
+				URL:        stringPointer("https://partner.example.com/hooks"),
+				EventTypes: &eventTypes,
+				Secret:     stringPointer("a-very-long-secret"),
+			},
+			expectedSecret: "a-very-long-secret",
+		},
+		{
+			description: "Secret is generated",
+			changes: entities.WebhookSubscriptionChanges{
+				URL:        stringPointer("https://partner.example.com/hooks"),
+				EventTypes: &eventTypes,
+			},
+		},
+		{
+			description: "URL is required",
+			changes: entities.WebhookSubscriptionChanges{
+				EventTypes: &eventTypes,
+			},
+			expectedError: "url is required",
+		},
+		{
+			description: "Event types are required",
+			changes: entities.WebhookSubscriptionChanges{
+				URL: stringPointer("https://partner.example.com/hooks"),
+			},
+			expectedError: "event_types is required",
+		},
+		{
+			description: "Invalid URL",
+			changes: entities.WebhookSubscriptionChanges{
+				URL:        stringPointer("ftp://partner.example.com/hooks"),
+				EventTypes: &eventTypes,
+			},
+			expectedError: "url must be an absolute http or https URL",
+		},
+		{
+			description: "Unknown event type",
+			changes: entities.WebhookSubscriptionChanges{
+				URL:        stringPointer("https://partner.example.com/hooks"),
+				EventTypes: &[]string{"user.signed_in"},
+			},
+			expectedError: "event_types must only hold",
+		},
+		{
+			description: "No event types",
+			changes: entities.WebhookSubscriptionChanges{
+				URL:        stringPointer("https://partner.example.com/hooks"),
+				EventTypes: &[]string{},
+			},
+			expectedError: "event_types must not be empty",
+		},
+		{
+			description: "Short secret",
+			changes: entities.WebhookSubscriptionChanges{
+				URL:        stringPointer("https://partner.example.com/hooks"),
+				EventTypes: &eventTypes,
+				Secret:     stringPointer("short"),
+			},
+			expectedError: "secret must be between 16 and 255 characters long",
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			s.SetupTest()
+
+			subscriptionId := uuid.New()
+			if test.expectedError == "" {
+				s.webhookSubscriptionRepositoryMock.EXPECT().Create(s.ctx, gomock.Any()).DoAndReturn(
+					func(
+						ctx context.Context, subscription models.WebhookSubscription,
+					) (*models.WebhookSubscription, error) {
+						s.Equal("https://partner.example.com/hooks", subscription.URL)
+						s.Equal(models.StringList{"user.registered", "user.deleted"}, subscription.EventTypes)
+						if test.expectedSecret != "" {
+							s.Equal(models.SecretValue(test.expectedSecret), subscription.Secret)
+						} else {
+							s.Len(subscription.Secret, 64)
+						}
+
+						subscription.ID = subscriptionId
+						return &subscription, nil
+					},
+				)
+				s.auditServiceMock.EXPECT().Record(
+					s.ctx,
+					AuditActionWebhookCreated,
+					"webhook",
+					subscriptionId.String(),
+					map[string]interface{}{
+						"url":         "https://partner.example.com/hooks",
+						"event_types": models.StringList{"user.registered", "user.deleted"},
+					},
+				).Return(nil)
+			}
+
+			subscription, err := s.service.Create(s.ctx, test.changes)
+			if test.expectedError != "" {
+				s.IsType(&entities.ValidationError{}, err)
+				s.Contains(err.(*entities.ValidationError).Details[0], test.expectedError)
+				s.Nil(subscription)
+			} else {
+				s.NoError(err)
+				s.Equal(subscriptionId, subscription.ID)
+			}
+		})
+	}
+}
+
+func (s *webhookSubscriptionServiceTestSuite) TestUpdate() {
+	subscription := &models.WebhookSubscription{
+		ID:         uuid.New(),
+		URL:        "https://partner.example.com/hooks",
+		EventTypes: models.StringList{"user.registered"},
+		Secret:     "a-very-long-secret",
+	}
+	id := subscription.ID.String()
+	changes := entities.WebhookSubscriptionChanges{
+		URL:    stringPointer("https://partner.example.com/v2/hooks"),
+		Secret: stringPointer("another-long-secret"),
+	}
+
+	s.Run("Success", func() {
+		s.SetupTest()
+
+		s.webhookSubscriptionRepositoryMock.EXPECT().FindById(s.ctx, id).Return(subscription, nil)
+		s.webhookSubscriptionRepositoryMock.EXPECT().Update(s.ctx, models.WebhookSubscription{
+			ID:         subscription.ID,
+			URL:        "https://partner.example.com/v2/hooks",
+			EventTypes: models.StringList{"user.registered"},
+			Secret:     "another-long-secret",
+		}).Return(nil)
+		s.auditServiceMock.EXPECT().RecordChanges(
+			s.ctx,
+			AuditActionWebhookUpdated,
+			"webhook",
+			id,
+			subscription,
+			map[string]interface{}{
+				"url":    "https://partner.example.com/v2/hooks",
+				"secret": models.SecretValue("another-long-secret"),
+			},
+		).Return(nil)
+
+		updated, err := s.service.Update(s.ctx, id, changes)
+
+		s.NoError(err)
+		s.Equal("https://partner.example.com/v2/hooks", updated.URL)
+		s.Equal("https://partner.example.com/hooks", subscription.URL)
+	})
+
+	s.Run("Webhook not found", func() {
+		s.SetupTest()
+
+		s.webhookSubscriptionRepositoryMock.EXPECT().FindById(s.ctx, id).Return(nil, nil)
+
+		_, err := s.service.Update(s.ctx, id, changes)
+
+		s.IsType(&entities.ItemNotFoundError{}, err)
+	})
+
+	s.Run("Invalid changes", func() {
+		s.SetupTest()
+
+		s.webhookSubscriptionRepositoryMock.EXPECT().FindById(s.ctx, id).Return(subscription, nil)
+
+		_, err := s.service.Update(
+			s.ctx, id, entities.WebhookSubscriptionChanges{URL: stringPointer("partner")},
+		)
+
+		s.IsType(&entities.ValidationError{}, err)
+	})
+}
+
+func (s *webhookSubscriptionServiceTestSuite) TestDeleteById() {
+	id := uuid.NewString()
+
+	tests := []struct {
+		description   string
+		deleted       bool
+		deleteError   error
+		expectedError string
+	}{
+		{
+			description: "Success",
+			deleted:     true,
+		},
+		{
+			description:   "Webhook not found",
+			expectedError: "Webhook not found",
+		},
+		{
+			description:   "Error deleting deliveries",
+			deleteError:   errors.New("error deleting deliveries"),
+			expectedError: "error deleting deliveries",
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.description, func() {
+			s.SetupTest()
+
+			s.webhookDeliveryRepositoryMock.EXPECT().DeleteBySubscriptionId(
+				s.ctx, id,
+			).Return(test.deleteError)
+			if test.deleteError == nil {
+				s.webhookSubscriptionRepositoryMock.EXPECT().DeleteById(
+					s.ctx, id,
+				).Return(test.deleted, nil)
+			}
+			if test.deleted {
+				s.auditServiceMock.EXPECT().Record(
+					s.ctx, AuditActionWebhookDeleted, "webhook", id, nil,
+				).Return(nil)
+			}
+
+			err := s.service.DeleteById(s.ctx, id)
+
+			if test.expectedError != "" {
+				s.ErrorContains(err, test.expectedError)
+			} else {
+				s.NoError(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package utils
+
+import "strings"
+
+// NormalizeEmail trims and lowercases email so the same mailbox always maps
+// to the same account. With stripPlusTag, the "+tag" suffix of the local part
+// is dropped as well, so foo+news@x.com and foo@x.com are the same account.
+func NormalizeEmail(email string, stripPlusTag bool) string {
+	email = strings.ToLower(strings.TrimSpace(email))
+	if !stripPlusTag {
+		return email
+	}
+
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return email
+	}
+
+	local, domain := email[:at], email[at:]
+	if plus := strings.Index(local, "+"); plus > 0 {
+		local = local[:plus]
+	}
+
+	return local + domain
+}
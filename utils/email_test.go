@@ -0,0 +1,57 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeEmail(t *testing.T) {
+	tests := []struct {
+		description    string
+		email          string
+		stripPlusTag   bool
+		expectedResult string
+	}{
+		{
+			description:    "Trims and lowercases",
+			email:          "  Peter.Parker@NYork.co ",
+			expectedResult: "peter.parker@nyork.co",
+		},
+		{
+			description:    "Keeps plus tag",
+			email:          "Peter+News@nyork.co",
+			expectedResult: "peter+news@nyork.co",
+		},
+		{
+			description:    "Strips plus tag",
+			email:          "Peter+News@nyork.co",
+			stripPlusTag:   true,
+			expectedResult: "peter@nyork.co",
+		},
+		{
+			description:    "Keeps leading plus",
+			email:          "+peter@nyork.co",
+			stripPlusTag:   true,
+			expectedResult: "+peter@nyork.co",
+		},
+		{
+			description:    "Plus in domain is kept",
+			email:          "peter@ny+ork.co",
+			stripPlusTag:   true,
+			expectedResult: "peter@ny+ork.co",
+		},
+		{
+			description:    "Not an email",
+			email:          " Peter+Parker ",
+			stripPlusTag:   true,
+			expectedResult: "peter+parker",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			assert.Equal(t, test.expectedResult, NormalizeEmail(test.email, test.stripPlusTag))
+		})
+	}
+}
@@ -0,0 +1,320 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// JSONPatchOperation is a single operation of a JSON Patch document (RFC 6902)
+type JSONPatchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	From  string      `json:"from,omitempty"`
+	Value interface{} `json:"value"`
+}
+
+// MergePatch applies a JSON Merge Patch (RFC 7396) to target and returns the
+// result. Null members of the patch remove the matching member of target.
+func MergePatch(target interface{}, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+
+	targetObject, ok := target.(map[string]interface{})
+	result := map[string]interface{}{}
+	if ok {
+		for key, value := range targetObject {
+			result[key] = value
+		}
+	}
+
+	for key, value := range patchObject {
+		if value == nil {
+			delete(result, key)
+			continue
+		}
+
+		result[key] = MergePatch(result[key], value)
+	}
+
+	return result
+}
+
+// ApplyJSONPatch applies the operations of a JSON Patch (RFC 6902) in order
+// and returns the resulting document. The patch is atomic: document is left
+// untouched and an error is returned when any of the operations fails.
+func ApplyJSONPatch(
+	document interface{}, operations []JSONPatchOperation,
+) (interface{}, error) {
+	result := deepCopy(document)
+
+	var err error
+	for i, operation := range operations {
+		result, err = applyJSONPatchOperation(result, operation)
+		if err != nil {
+			return nil, fmt.Errorf("operation %d: %w", i, err)
+		}
+	}
+
+	return result, nil
+}
+
+func applyJSONPatchOperation(
+	document interface{}, operation JSONPatchOperation,
+) (interface{}, error) {
+	switch operation.Op {
+	case "add":
+		return jsonPointerAdd(document, operation.Path, deepCopy(operation.Value))
+	case "remove":
+		document, _, err := jsonPointerRemove(document, operation.Path)
+		return document, err
+	case "replace":
+		document, _, err := jsonPointerRemove(document, operation.Path)
+		if err != nil {
+			return nil, err
+		}
+
+		return jsonPointerAdd(document, operation.Path, deepCopy(operation.Value))
+	case "move":
+		if strings.HasPrefix(operation.Path, operation.From+"/") {
+			return nil, errors.New("cannot move a value into one of its children")
+		}
+
+		document, value, err := jsonPointerRemove(document, operation.From)
+		if err != nil {
+			return nil, err
+		}
+
+		return jsonPointerAdd(document, operation.Path, value)
+	case "copy":
+		value, err := jsonPointerGet(document, operation.From)
+		if err != nil {
+			return nil, err
+		}
+
+		return jsonPointerAdd(document, operation.Path, deepCopy(value))
+	case "test":
+		value, err := jsonPointerGet(document, operation.Path)
+		if err != nil {
+			return nil, err
+		}
+
+		if !reflect.DeepEqual(value, operation.Value) {
+			return nil, fmt.Errorf("test failed for path %q", operation.Path)
+		}
+
+		return document, nil
+	}
+
+	return nil, fmt.Errorf("unknown op %q", operation.Op)
+}
+
+// parseJSONPointer splits a JSON Pointer (RFC 6901) into its unescaped
+// reference tokens
+func parseJSONPointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("invalid path %q", pointer)
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+	}
+
+	return tokens, nil
+}
+
+func jsonPointerGet(document interface{}, pointer string) (interface{}, error) {
+	tokens, err := parseJSONPointer(pointer)
+	if err != nil {
+		return nil, err
+	}
+
+	current := document
+	for _, token := range tokens {
+		switch node := current.(type) {
+		case map[string]interface{}:
+			value, ok := node[token]
+			if !ok {
+				return nil, fmt.Errorf("path %q does not exist", pointer)
+			}
+
+			current = value
+		case []interface{}:
+			index, err := arrayIndex(token, len(node)-1)
+			if err != nil {
+				return nil, fmt.Errorf("path %q does not exist", pointer)
+			}
+
+			current = node[index]
+		default:
+			return nil, fmt.Errorf("path %q does not exist", pointer)
+		}
+	}
+
+	return current, nil
+}
+
+func jsonPointerAdd(
+	document interface{}, pointer string, value interface{},
+) (interface{}, error) {
+	tokens, err := parseJSONPointer(pointer)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tokens) == 0 {
+		return value, nil
+	}
+
+	parentPointer := pointer[:strings.LastIndex(pointer, "/")]
+	parent, err := jsonPointerGet(document, parentPointer)
+	if err != nil {
+		return nil, err
+	}
+
+	token := tokens[len(tokens)-1]
+	switch node := parent.(type) {
+	case map[string]interface{}:
+		node[token] = value
+		return document, nil
+	case []interface{}:
+		index := len(node)
+		if token != "-" {
+			index, err = arrayIndex(token, len(node))
+			if err != nil {
+				return nil, fmt.Errorf("path %q does not exist", pointer)
+			}
+		}
+
+		node = append(node, nil)
+		copy(node[index+1:], node[index:])
+		node[index] = value
+
+		return jsonPointerSet(document, parentPointer, node)
+	}
+
+	return nil, fmt.Errorf("path %q does not exist", pointer)
+}
+
+// jsonPointerSet replaces the existing value the pointer references
+func jsonPointerSet(
+	document interface{}, pointer string, value interface{},
+) (interface{}, error) {
+	tokens, err := parseJSONPointer(pointer)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tokens) == 0 {
+		return value, nil
+	}
+
+	parent, err := jsonPointerGet(document, pointer[:strings.LastIndex(pointer, "/")])
+	if err != nil {
+		return nil, err
+	}
+
+	token := tokens[len(tokens)-1]
+	switch node := parent.(type) {
+	case map[string]interface{}:
+		node[token] = value
+		return document, nil
+	case []interface{}:
+		index, err := arrayIndex(token, len(node)-1)
+		if err != nil {
+			return nil, fmt.Errorf("path %q does not exist", pointer)
+		}
+
+		node[index] = value
+		return document, nil
+	}
+
+	return nil, fmt.Errorf("path %q does not exist", pointer)
+}
+
+func jsonPointerRemove(
+	document interface{}, pointer string,
+) (interface{}, interface{}, error) {
+	tokens, err := parseJSONPointer(pointer)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(tokens) == 0 {
+		return nil, document, nil
+	}
+
+	parentPointer := pointer[:strings.LastIndex(pointer, "/")]
+	parent, err := jsonPointerGet(document, parentPointer)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	token := tokens[len(tokens)-1]
+	switch node := parent.(type) {
+	case map[string]interface{}:
+		value, ok := node[token]
+		if !ok {
+			return nil, nil, fmt.Errorf("path %q does not exist", pointer)
+		}
+
+		delete(node, token)
+		return document, value, nil
+	case []interface{}:
+		index, err := arrayIndex(token, len(node)-1)
+		if err != nil {
+			return nil, nil, fmt.Errorf("path %q does not exist", pointer)
+		}
+
+		value := node[index]
+		node = append(node[:index:index], node[index+1:]...)
+
+		document, err = jsonPointerSet(document, parentPointer, node)
+		return document, value, err
+	}
+
+	return nil, nil, fmt.Errorf("path %q does not exist", pointer)
+}
+
+func arrayIndex(token string, max int) (int, error) {
+	if token != "0" && strings.HasPrefix(token, "0") {
+		return 0, errors.New("leading zeros are not allowed")
+	}
+
+	index, err := strconv.Atoi(token)
+	if err != nil || index < 0 || index > max {
+		return 0, errors.New("index out of range")
+	}
+
+	return index, nil
+}
+
+func deepCopy(value interface{}) interface{} {
+	switch node := value.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(node))
+		for key, child := range node {
+			result[key] = deepCopy(child)
+		}
+
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(node))
+		for i, child := range node {
+			result[i] = deepCopy(child)
+		}
+
+		return result
+	}
+
+	return value
+}
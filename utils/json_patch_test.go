@@ -0,0 +1,220 @@
+package utils
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func decodeJSON(t *testing.T, document string) interface{} {
+	var result interface{}
+	if err := json.Unmarshal([]byte(document), &result); err != nil {
+		t.Fatal(err)
+	}
+
+	return result
+}
+
+func TestMergePatch(t *testing.T) {
+	tests := []struct {
+		description    string
+		target         string
+		patch          string
+		expectedResult string
+	}{
+		{
+			description:    "Replaces member",
+			target:         `{"a":"b"}`,
+			patch:          `{"a":"c"}`,
+			expectedResult: `{"a":"c"}`,
+		},
+		{
+			description:    "Adds member",
+			target:         `{"a":"b"}`,
+			patch:          `{"b":"c"}`,
+			expectedResult: `{"a":"b","b":"c"}`,
+		},
+		{
+			description:    "Removes member with null",
+			target:         `{"a":"b","b":"c"}`,
+			patch:          `{"a":null}`,
+			expectedResult: `{"b":"c"}`,
+		},
+		{
+			description:    "Replaces arrays as a whole",
+			target:         `{"a":[{"b":"c"}]}`,
+			patch:          `{"a":[1]}`,
+			expectedResult: `{"a":[1]}`,
+		},
+		{
+			description:    "Merges nested objects",
+			target:         `{"a":{"b":"c","d":"e"}}`,
+			patch:          `{"a":{"d":null,"f":"g"}}`,
+			expectedResult: `{"a":{"b":"c","f":"g"}}`,
+		},
+		{
+			description:    "Non object patch replaces target",
+			target:         `{"a":"b"}`,
+			patch:          `["c"]`,
+			expectedResult: `["c"]`,
+		},
+		{
+			description:    "Object patch over non object target",
+			target:         `["c"]`,
+			patch:          `{"a":{"bb":{"ccc":null}}}`,
+			expectedResult: `{"a":{"bb":{}}}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			target := decodeJSON(t, test.target)
+
+			result := MergePatch(target, decodeJSON(t, test.patch))
+
+			assert.Equal(t, decodeJSON(t, test.expectedResult), result)
+			assert.Equal(t, decodeJSON(t, test.target), target)
+		})
+	}
+}
+
+func TestApplyJSONPatch(t *testing.T) {
+	tests := []struct {
+		description    string
+		document       string
+		patch          string
+		expectedResult string
+		expectedError  string
+	}{
+		{
+			description:    "Add member",
+			document:       `{"foo":"bar"}`,
+			patch:          `[{"op":"add","path":"/baz","value":"qux"}]`,
+			expectedResult: `{"foo":"bar","baz":"qux"}`,
+		},
+		{
+			description:    "Add array element",
+			document:       `{"foo":["bar","baz"]}`,
+			patch:          `[{"op":"add","path":"/foo/1","value":"qux"}]`,
+			expectedResult: `{"foo":["bar","qux","baz"]}`,
+		},
+		{
+			description:    "Append array element",
+			document:       `{"foo":["bar"]}`,
+			patch:          `[{"op":"add","path":"/foo/-","value":["abc"]}]`,
+			expectedResult: `{"foo":["bar",["abc"]]}`,
+		},
+		{
+			description:    "Remove member",
+			document:       `{"baz":"qux","foo":"bar"}`,
+			patch:          `[{"op":"remove","path":"/baz"}]`,
+			expectedResult: `{"foo":"bar"}`,
+		},
+		{
+			description:    "Remove array element",
+			document:       `{"foo":["bar","qux","baz"]}`,
+			patch:          `[{"op":"remove","path":"/foo/1"}]`,
+			expectedResult: `{"foo":["bar","baz"]}`,
+		},
+		{
+			description:    "Replace value",
+			document:       `{"baz":"qux","foo":"bar"}`,
+			patch:          `[{"op":"replace","path":"/baz","value":"boo"}]`,
+			expectedResult: `{"baz":"boo","foo":"bar"}`,
+		},
+		{
+			description:    "Move value",
+			document:       `{"foo":{"bar":"baz","waldo":"fred"},"qux":{"corge":"grault"}}`,
+			patch:          `[{"op":"move","from":"/foo/waldo","path":"/qux/thud"}]`,
+			expectedResult: `{"foo":{"bar":"baz"},"qux":{"corge":"grault","thud":"fred"}}`,
+		},
+		{
+			description:    "Move array element",
+			document:       `{"foo":["all","grass","cows","eat"]}`,
+			patch:          `[{"op":"move","from":"/foo/1","path":"/foo/3"}]`,
+			expectedResult: `{"foo":["all","cows","eat","grass"]}`,
+		},
+		{
+			description:    "Copy value",
+			document:       `{"foo":{"bar":"baz"}}`,
+			patch:          `[{"op":"copy","from":"/foo","path":"/qux"}]`,
+			expectedResult: `{"foo":{"bar":"baz"},"qux":{"bar":"baz"}}`,
+		},
+		{
+			description:    "Test succeeds",
+			document:       `{"baz":"qux","foo":["a",2,"c"]}`,
+			patch:          `[{"op":"test","path":"/baz","value":"qux"},{"op":"test","path":"/foo/1","value":2}]`,
+			expectedResult: `{"baz":"qux","foo":["a",2,"c"]}`,
+		},
+		{
+			description:    "Escaped pointer tokens",
+			document:       `{"a/b":1,"m~n":2}`,
+			patch:          `[{"op":"remove","path":"/a~1b"},{"op":"replace","path":"/m~0n","value":3}]`,
+			expectedResult: `{"m~n":3}`,
+		},
+		{
+			description:   "Test fails",
+			document:      `{"baz":"qux"}`,
+			patch:         `[{"op":"test","path":"/baz","value":"bar"}]`,
+			expectedError: `operation 0: test failed for path "/baz"`,
+		},
+		{
+			description:   "Remove missing member",
+			document:      `{"foo":"bar"}`,
+			patch:         `[{"op":"add","path":"/baz","value":1},{"op":"remove","path":"/qux"}]`,
+			expectedError: `operation 1: path "/qux" does not exist`,
+		},
+		{
+			description:   "Add to missing parent",
+			document:      `{"foo":"bar"}`,
+			patch:         `[{"op":"add","path":"/baz/bat","value":"qux"}]`,
+			expectedError: `operation 0: path "/baz" does not exist`,
+		},
+		{
+			description:   "Array index out of range",
+			document:      `{"foo":["bar"]}`,
+			patch:         `[{"op":"add","path":"/foo/2","value":"qux"}]`,
+			expectedError: `operation 0: path "/foo/2" does not exist`,
+		},
+		{
+			description:   "Move into own child",
+			document:      `{"foo":{"bar":"baz"}}`,
+			patch:         `[{"op":"move","from":"/foo","path":"/foo/bar/qux"}]`,
+			expectedError: "operation 0: cannot move a value into one of its children",
+		},
+		{
+			description:   "Invalid path",
+			document:      `{"foo":"bar"}`,
+			patch:         `[{"op":"remove","path":"foo"}]`,
+			expectedError: `operation 0: invalid path "foo"`,
+		},
+		{
+			description:   "Unknown op",
+			document:      `{"foo":"bar"}`,
+			patch:         `[{"op":"merge","path":"/foo","value":"baz"}]`,
+			expectedError: `operation 0: unknown op "merge"`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			var operations []JSONPatchOperation
+			if err := json.Unmarshal([]byte(test.patch), &operations); err != nil {
+				t.Fatal(err)
+			}
+			document := decodeJSON(t, test.document)
+
+			result, err := ApplyJSONPatch(document, operations)
+
+			if test.expectedError != "" {
+				assert.EqualError(t, err, test.expectedError)
+				assert.Nil(t, result)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, decodeJSON(t, test.expectedResult), result)
+			}
+			assert.Equal(t, decodeJSON(t, test.document), document)
+		})
+	}
+}